@@ -0,0 +1,146 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"fmt"
+
+	"github.com/hashkey-chain/hashkey-chain/accounts/abi"
+	"github.com/hashkey-chain/hashkey-chain/accounts/abi/wasm"
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/crypto"
+	"github.com/hashkey-chain/hashkey-chain/event"
+)
+
+// WasmBoundContract is the base wrapper object that reflects a WASM contract on
+// the hskchain network. The inputs and outputs are RLP encoded as the contracts
+// compiled by the platon-cdt expect.
+type WasmBoundContract struct {
+	contract *BoundContract // Transport shared with the EVM contracts
+	abi      wasm.ABI       // Reflect based ABI to access the correct WASM methods
+}
+
+// NewWasmBoundContract creates a low level WASM contract interface through which
+// calls and transactions may be made through.
+func NewWasmBoundContract(address common.Address, wasmABI wasm.ABI, caller ContractCaller, transactor ContractTransactor, filterer ContractFilterer) *WasmBoundContract {
+	return &WasmBoundContract{
+		contract: NewBoundContract(address, abi.ABI{}, caller, transactor, filterer),
+		abi:      wasmABI,
+	}
+}
+
+// DeployWasmContract deploys a WASM contract onto the hskchain blockchain and
+// binds the deployment address with a Go wrapper. The params are passed to the
+// init function of the contract.
+func DeployWasmContract(opts *TransactOpts, wasmABI wasm.ABI, code []byte, backend ContractBackend, params ...interface{}) (common.Address, *types.Transaction, *WasmBoundContract, error) {
+	c := NewWasmBoundContract(common.Address{}, wasmABI, backend, backend, backend)
+
+	input, err := c.abi.PackDeploy(code, params...)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	tx, err := c.contract.transact(opts, nil, input)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	c.contract.address = crypto.CreateAddress(opts.From, tx.Nonce())
+	return c.contract.address, tx, c, nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// decodes the output into result, which must be a pointer to the Go type of the
+// method output. The result is ignored for the methods returning void.
+func (c *WasmBoundContract) Call(opts *CallOpts, result interface{}, method string, params ...interface{}) error {
+	// Don't crash on a lazy user
+	if opts == nil {
+		opts = new(CallOpts)
+	}
+	input, err := c.abi.Pack(method, params...)
+	if err != nil {
+		return err
+	}
+	output, err := c.contract.call(opts, input)
+	if err != nil {
+		return err
+	}
+	return c.abi.Unpack(method, result, output)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (c *WasmBoundContract) Transact(opts *TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	input, err := c.abi.Pack(method, params...)
+	if err != nil {
+		return nil, err
+	}
+	return c.contract.transact(opts, &c.contract.address, input)
+}
+
+// RawTransact initiates a transaction with the given raw calldata as input.
+func (c *WasmBoundContract) RawTransact(opts *TransactOpts, calldata []byte) (*types.Transaction, error) {
+	return c.contract.RawTransact(opts, calldata)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract.
+func (c *WasmBoundContract) Transfer(opts *TransactOpts) (*types.Transaction, error) {
+	return c.contract.Transfer(opts)
+}
+
+// eventQuery prepends the event name topic to the filter query of the event.
+func (c *WasmBoundContract) eventQuery(name string, query [][]interface{}) ([][]common.Hash, error) {
+	ev, ok := c.abi.Events[name]
+	if !ok {
+		return nil, fmt.Errorf("wasm: event %s not found", name)
+	}
+	if len(query) > ev.Topics {
+		return nil, fmt.Errorf("wasm: event %s has %d indexed inputs, got %d rules", name, ev.Topics, len(query))
+	}
+	return wasm.MakeTopics(append([][]interface{}{{ev.ID()}}, query...)...)
+}
+
+// FilterLogs filters contract logs for past blocks, returning the necessary
+// channels to construct a strongly typed bound iterator on top of them.
+func (c *WasmBoundContract) FilterLogs(opts *FilterOpts, name string, query ...[]interface{}) (chan types.Log, event.Subscription, error) {
+	// Don't crash on a lazy user
+	if opts == nil {
+		opts = new(FilterOpts)
+	}
+	topics, err := c.eventQuery(name, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.contract.filterLogs(opts, topics)
+}
+
+// WatchLogs filters subscribes to contract logs for future blocks, returning a
+// subscription object that can be used to tear down the watcher.
+func (c *WasmBoundContract) WatchLogs(opts *WatchOpts, name string, query ...[]interface{}) (chan types.Log, event.Subscription, error) {
+	// Don't crash on a lazy user
+	if opts == nil {
+		opts = new(WatchOpts)
+	}
+	topics, err := c.eventQuery(name, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.contract.watchLogs(opts, topics)
+}
+
+// UnpackLog unpacks a retrieved log into the provided output structure.
+func (c *WasmBoundContract) UnpackLog(out interface{}, event string, log types.Log) error {
+	return c.abi.UnpackLog(out, event, log.Topics, log.Data)
+}
@@ -0,0 +1,265 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/hashkey-chain/hashkey-chain/accounts/abi/wasm"
+)
+
+// BindWasm generates a Go wrapper around a WASM contract ABI, the counterpart
+// of Bind for the contracts compiled by the platon-cdt. The bytecodes are the
+// hex encoded WASM modules, an empty bytecode skips the deploy method.
+func BindWasm(types []string, abis []string, bytecodes []string, pkg string, aliases map[string]string) (string, error) {
+	var (
+		// contracts is the map of each individual contract requested binding
+		contracts = make(map[string]*tmplWasmContract)
+
+		// structs is the map of all structs shared by passed contracts
+		structs = make(map[string]*tmplWasmStruct)
+	)
+	for i := 0; i < len(types); i++ {
+		// Parse the actual ABI to generate the binding for
+		wasmABI, err := wasm.JSON(strings.NewReader(abis[i]))
+		if err != nil {
+			return "", err
+		}
+		// Strip any whitespace from the JSON ABI
+		strippedABI := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+			return r
+		}, abis[i])
+
+		for _, original := range wasmABI.Structs {
+			s, err := bindWasmStruct(original)
+			if err != nil {
+				return "", err
+			}
+			if prev, ok := structs[s.Name]; ok && !equalWasmStructs(prev, s) {
+				return "", fmt.Errorf("conflicting definitions of struct %s", original.Name)
+			}
+			structs[s.Name] = s
+		}
+		var (
+			calls     = make(map[string]*tmplWasmMethod)
+			transacts = make(map[string]*tmplWasmMethod)
+			events    = make(map[string]*tmplWasmEvent)
+
+			// identifiers are used to detect duplicated identifiers of functions
+			// and events after the normalization.
+			callIdentifiers     = make(map[string]bool)
+			transactIdentifiers = make(map[string]bool)
+			eventIdentifiers    = make(map[string]bool)
+		)
+		for _, original := range wasmABI.Functions {
+			normalizedName := capitalise(alias(aliases, original.Name))
+			identifiers := callIdentifiers
+			if !original.Constant {
+				identifiers = transactIdentifiers
+			}
+			if identifiers[normalizedName] {
+				return "", fmt.Errorf("duplicated identifier \"%s\"(normalized \"%s\"), use --alias for renaming", original.Name, normalizedName)
+			}
+			identifiers[normalizedName] = true
+
+			method, err := bindWasmMethod(normalizedName, original)
+			if err != nil {
+				return "", err
+			}
+			if original.Constant {
+				calls[original.Name] = method
+			} else {
+				transacts[original.Name] = method
+			}
+		}
+		for _, original := range wasmABI.Events {
+			normalizedName := capitalise(alias(aliases, original.Name))
+			if eventIdentifiers[normalizedName] {
+				return "", fmt.Errorf("duplicated identifier \"%s\"(normalized \"%s\"), use --alias for renaming", original.Name, normalizedName)
+			}
+			eventIdentifiers[normalizedName] = true
+
+			inputs, err := bindWasmArguments(original.Inputs)
+			if err != nil {
+				return "", fmt.Errorf("event %s: %v", original.Name, err)
+			}
+			for j := 0; j < original.Topics; j++ {
+				inputs[j].Indexed = true
+			}
+			events[original.Name] = &tmplWasmEvent{Original: original.Name, Name: normalizedName, Inputs: inputs}
+		}
+		constructor, err := bindWasmMethod("", wasmABI.Constructor)
+		if err != nil {
+			return "", err
+		}
+		contracts[types[i]] = &tmplWasmContract{
+			Type:        capitalise(types[i]),
+			InputABI:    strings.Replace(strippedABI, "\"", "\\\"", -1),
+			InputBin:    strings.TrimPrefix(strings.TrimSpace(bytecodes[i]), "0x"),
+			Constructor: constructor,
+			Calls:       calls,
+			Transacts:   transacts,
+			Events:      events,
+		}
+	}
+	// Generate the contract template data content and render it
+	data := &tmplWasmData{
+		Package:   pkg,
+		Contracts: contracts,
+		Structs:   sortedWasmStructs(structs),
+	}
+	buffer := new(bytes.Buffer)
+
+	funcs := map[string]interface{}{
+		"capitalise": capitalise,
+	}
+	tmpl := template.Must(template.New("").Funcs(funcs).Parse(tmplSourceWasmGo))
+	if err := tmpl.Execute(buffer, data); err != nil {
+		return "", err
+	}
+	code, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("%v\n%s", err, buffer)
+	}
+	return string(code), nil
+}
+
+// bindWasmMethod converts a function of the WASM ABI into the template data.
+func bindWasmMethod(name string, fn wasm.Function) (*tmplWasmMethod, error) {
+	inputs, err := bindWasmArguments(fn.Inputs)
+	if err != nil {
+		return nil, fmt.Errorf("function %s: %v", fn.Name, err)
+	}
+	method := &tmplWasmMethod{Original: fn.Name, Name: name, Inputs: inputs}
+	if fn.Output.T != wasm.VoidTy {
+		if method.Output, err = bindWasmTypeGo(fn.Output); err != nil {
+			return nil, fmt.Errorf("function %s: %v", fn.Name, err)
+		}
+	}
+	return method, nil
+}
+
+// bindWasmArguments converts the arguments of the WASM ABI into the template
+// data, naming the anonymous ones by their positions.
+func bindWasmArguments(args []wasm.Argument) ([]*tmplWasmArgument, error) {
+	bound := make([]*tmplWasmArgument, len(args))
+	for i, arg := range args {
+		typ, err := bindWasmTypeGo(arg.Type)
+		if err != nil {
+			return nil, err
+		}
+		name := arg.Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		bound[i] = &tmplWasmArgument{Name: name, Type: typ}
+	}
+	return bound, nil
+}
+
+// bindWasmStruct converts a struct of the WASM ABI into the template data.
+func bindWasmStruct(s wasm.Struct) (*tmplWasmStruct, error) {
+	fields, err := bindWasmArguments(s.Fields)
+	if err != nil {
+		return nil, fmt.Errorf("struct %s: %v", s.Name, err)
+	}
+	bound := &tmplWasmStruct{Name: capitalise(s.Name), Fields: fields}
+	if s.Base != "" {
+		bound.Base = capitalise(s.Base)
+	}
+	return bound, nil
+}
+
+func equalWasmStructs(a, b *tmplWasmStruct) bool {
+	if a.Base != b.Base || len(a.Fields) != len(b.Fields) {
+		return false
+	}
+	for i := range a.Fields {
+		if *a.Fields[i] != *b.Fields[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedWasmStructs(structs map[string]*tmplWasmStruct) []*tmplWasmStruct {
+	sorted := make([]*tmplWasmStruct, 0, len(structs))
+	for _, s := range structs {
+		sorted = append(sorted, s)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
+
+// bindWasmTypeGo converts the WASM ABI types to Go ones, the Go types are
+// encoded by the wasm package as the platon-cdt serializes the C++ types.
+func bindWasmTypeGo(kind wasm.Type) (string, error) {
+	switch kind.T {
+	case wasm.BoolTy:
+		return "bool", nil
+	case wasm.IntTy:
+		return fmt.Sprintf("int%d", kind.Size), nil
+	case wasm.UintTy:
+		return fmt.Sprintf("uint%d", kind.Size), nil
+	case wasm.BigIntTy:
+		return "*big.Int", nil
+	case wasm.StringTy:
+		return "string", nil
+	case wasm.BytesTy:
+		return "[]byte", nil
+	case wasm.FixedBytesTy:
+		return fmt.Sprintf("[%d]byte", kind.Size), nil
+	case wasm.AddressTy:
+		return "common.Address", nil
+	case wasm.SliceTy:
+		elem, err := bindWasmTypeGo(*kind.Elem)
+		return "[]" + elem, err
+	case wasm.ArrayTy:
+		elem, err := bindWasmTypeGo(*kind.Elem)
+		return fmt.Sprintf("[%d]", kind.Size) + elem, err
+	case wasm.MapTy:
+		switch kind.Key.T {
+		case wasm.BigIntTy, wasm.BytesTy, wasm.SliceTy, wasm.MapTy:
+			return "", fmt.Errorf("unsupported map key of %s", kind.String)
+		}
+		key, err := bindWasmTypeGo(*kind.Key)
+		if err != nil {
+			return "", err
+		}
+		elem, err := bindWasmTypeGo(*kind.Elem)
+		return "map[" + key + "]" + elem, err
+	case wasm.PairTy:
+		first, err := bindWasmTypeGo(*kind.Key)
+		if err != nil {
+			return "", err
+		}
+		second, err := bindWasmTypeGo(*kind.Elem)
+		return "struct{ First " + first + "; Second " + second + " }", err
+	case wasm.StructTy:
+		return capitalise(kind.Struct), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", kind.String)
+}
@@ -0,0 +1,136 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind_test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	ethereum "github.com/hashkey-chain/hashkey-chain"
+	"github.com/hashkey-chain/hashkey-chain/accounts/abi/bind"
+	"github.com/hashkey-chain/hashkey-chain/accounts/abi/wasm"
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+)
+
+const wasmStoreABI = `[
+	{"name":"Base","type":"struct","baseclass":[],"fields":[{"name":"id","type":"uint64"}]},
+	{"name":"Info","type":"struct","baseclass":["Base"],"fields":[{"name":"name","type":"string"},{"name":"tags","type":"map<string,uint32>"}]},
+	{"name":"init","type":"Action","constant":false,"input":[{"name":"owner","type":"FixedHash<20>"}],"output":"void"},
+	{"name":"set_info","type":"Action","constant":false,"input":[{"name":"info","type":"Info"}],"output":"void"},
+	{"name":"get_info","type":"Action","constant":true,"input":[{"name":"id","type":"uint64"}],"output":"Info"},
+	{"name":"balances","type":"Action","constant":true,"input":[],"output":"list<pair<string,uint128>>"},
+	{"name":"transfer","type":"Event","topic":1,"input":[{"name":"from","type":"string"},{"name":"amount","type":"int64"}]}
+]`
+
+func TestBindWasm(t *testing.T) {
+	code, err := bind.BindWasm([]string{"store"}, []string{wasmStoreABI}, []string{"0x0061736d01000000"}, "bindtest", nil)
+	if err != nil {
+		t.Fatalf("failed to generate binding: %v", err)
+	}
+	for _, want := range []string{
+		"type Info struct {\n\tBase\n\tName string\n\tTags map[string]uint32\n}",
+		"func DeployStore(auth *bind.TransactOpts, backend bind.ContractBackend, owner common.Address)",
+		"func (_Store *StoreCaller) GetInfo(opts *bind.CallOpts, id uint64) (Info, error)",
+		"func (_Store *StoreCaller) Balances(opts *bind.CallOpts) ([]struct {",
+		"func (_Store *StoreTransactor) SetInfo(opts *bind.TransactOpts, info Info) (*types.Transaction, error)",
+		"func (_Store *StoreFilterer) FilterTransfer(opts *bind.FilterOpts, from []string) (*StoreTransferIterator, error)",
+		"\tFrom   common.Hash\n\tAmount int64\n",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("binding misses %q", want)
+		}
+	}
+	// The bindings are generated without the deploy method if no module is given
+	code, err = bind.BindWasm([]string{"store"}, []string{wasmStoreABI}, []string{""}, "bindtest", map[string]string{"get_info": "info"})
+	if err != nil {
+		t.Fatalf("failed to generate binding: %v", err)
+	}
+	if strings.Contains(code, "DeployStore") || !strings.Contains(code, ") Info(opts *bind.CallOpts") {
+		t.Error("binding mismatch without module and with aliases")
+	}
+	// Conflicting struct definitions across the contracts are rejected
+	other := `[{"name":"Base","type":"struct","baseclass":[],"fields":[{"name":"id","type":"string"}]}]`
+	if _, err := bind.BindWasm([]string{"store", "other"}, []string{wasmStoreABI, other}, []string{"", ""}, "bindtest", nil); err == nil {
+		t.Error("expected error for conflicting structs")
+	}
+}
+
+type wasmMockCaller struct {
+	input  []byte
+	output []byte
+}
+
+func (mc *wasmMockCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return wasm.Magic, nil
+}
+
+func (mc *wasmMockCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	mc.input = call.Data
+	return mc.output, nil
+}
+
+func TestWasmBoundContract(t *testing.T) {
+	parsed, err := wasm.JSON(strings.NewReader(wasmStoreABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	type base struct{ Id uint64 }
+	type info struct {
+		Base base
+		Name string
+		Tags map[string]uint32
+	}
+	want := info{Base: base{Id: 3}, Name: "store", Tags: map[string]uint32{"a": 1}}
+	output, _ := wasm.Encode(want)
+
+	caller := &wasmMockCaller{output: output}
+	contract := bind.NewWasmBoundContract(common.Address{}, parsed, caller, nil, nil)
+
+	var have info
+	if err := contract.Call(nil, &have, "get_info", uint64(3)); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if input, _ := parsed.Pack("get_info", uint64(3)); !bytes.Equal(caller.input, input) {
+		t.Errorf("call input mismatch: have %x, want %x", caller.input, input)
+	}
+	if have.Base != want.Base || have.Name != want.Name || have.Tags["a"] != 1 {
+		t.Errorf("call output mismatch: have %+v, want %+v", have, want)
+	}
+	// An empty output of a deployed contract is a decoding failure
+	caller.output = nil
+	if err := contract.Call(nil, &have, "get_info", uint64(3)); err == nil {
+		t.Error("expected error for empty output")
+	}
+
+	from, _ := wasm.Topic("alice")
+	data, _ := wasm.Encode([]interface{}{int64(-5)})
+	var event struct {
+		From   common.Hash
+		Amount int64
+	}
+	log := types.Log{Topics: []common.Hash{parsed.Events["transfer"].ID(), from}, Data: data}
+	if err := contract.UnpackLog(&event, "transfer", log); err != nil {
+		t.Fatalf("failed to unpack log: %v", err)
+	}
+	if event.From != from || event.Amount != -5 {
+		t.Errorf("event mismatch: %+v", event)
+	}
+}
@@ -0,0 +1,401 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package wasm implements the ABI of the WASM contracts: the parsing of the
+// ABI JSON and the RLP encoding of the calls, the results and the events.
+package wasm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/crypto"
+	"github.com/hashkey-chain/hashkey-chain/rlp"
+)
+
+const (
+	// InitFunc is the function called by the deployment of the contract.
+	InitFunc = "init"
+
+	// maxTopics is the max number of topics of a platon_event log, the
+	// event name included.
+	maxTopics = 4
+)
+
+// Magic is the prefix of the WASM modules, the same as vm.WasmInterp.
+var Magic = []byte{0x00, 0x61, 0x73, 0x6d}
+
+// Argument is a named input of a function, an event or a field of a struct.
+type Argument struct {
+	Name string
+	Type Type
+}
+
+// Function is a function exported by the contract.
+type Function struct {
+	Name     string
+	Inputs   []Argument
+	Output   Type
+	Constant bool
+}
+
+// Event is an event emitted by the contract through platon_event. The event
+// name is the first topic, the leading Topics inputs are the next topics and
+// the rest of the inputs are encoded in the log data.
+type Event struct {
+	Name   string
+	Topics int
+	Inputs []Argument
+}
+
+// Struct is a user defined struct of the contract. The base class of a
+// derived struct is serialized as its first field.
+type Struct struct {
+	Name   string
+	Base   string
+	Fields []Argument
+}
+
+// ABI holds the information about a WASM contract's functions, events and
+// structs.
+type ABI struct {
+	Constructor Function
+	Functions   map[string]Function
+	Events      map[string]Event
+	Structs     map[string]Struct
+}
+
+// flexBool accepts both the JSON booleans of the platon-cdt ABIs and the
+// "true"/"false" strings of the legacy ctool ABIs.
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(input []byte) error {
+	switch strings.Trim(string(input), `"`) {
+	case "true":
+		*b = true
+	case "false", "", "null":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean %s", input)
+	}
+	return nil
+}
+
+type jsonArgument struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// jsonEntry is an entry of the ABI JSON. The platon-cdt ABIs declare the
+// functions as "Action" entries with the "input" list and the "output" type,
+// the legacy ctool ABIs declare them as "function" entries with the "inputs"
+// and "outputs" lists.
+type jsonEntry struct {
+	Name      string         `json:"name"`
+	Type      string         `json:"type"`
+	Constant  flexBool       `json:"constant"`
+	Input     []jsonArgument `json:"input"`
+	Output    string         `json:"output"`
+	Inputs    []jsonArgument `json:"inputs"`
+	Outputs   []jsonArgument `json:"outputs"`
+	Topic     int            `json:"topic"`
+	BaseClass []string       `json:"baseclass"`
+	Fields    []jsonArgument `json:"fields"`
+}
+
+// JSON returns a parsed ABI interface and error if it failed.
+func JSON(reader io.Reader) (ABI, error) {
+	var entries []jsonEntry
+	if err := json.NewDecoder(reader).Decode(&entries); err != nil {
+		return ABI{}, err
+	}
+	abi := ABI{
+		Constructor: Function{Name: InitFunc},
+		Functions:   make(map[string]Function),
+		Events:      make(map[string]Event),
+		Structs:     make(map[string]Struct),
+	}
+	for _, entry := range entries {
+		inputs, err := parseArguments(entry.Name, append(entry.Input, entry.Inputs...))
+		if err != nil {
+			return ABI{}, err
+		}
+		switch strings.ToLower(entry.Type) {
+		case "action", "function":
+			output := entry.Output
+			if len(entry.Outputs) > 1 {
+				return ABI{}, fmt.Errorf("wasm: function %s returns more than one value", entry.Name)
+			} else if len(entry.Outputs) == 1 {
+				output = entry.Outputs[0].Type
+			}
+			typ, err := NewType(output)
+			if err != nil {
+				return ABI{}, fmt.Errorf("wasm: output of %s: %v", entry.Name, err)
+			}
+			fn := Function{Name: entry.Name, Inputs: inputs, Output: typ, Constant: bool(entry.Constant)}
+			if entry.Name == InitFunc {
+				abi.Constructor = fn
+				continue
+			}
+			if _, ok := abi.Functions[entry.Name]; ok {
+				return ABI{}, fmt.Errorf("wasm: duplicated function %s", entry.Name)
+			}
+			abi.Functions[entry.Name] = fn
+		case "event":
+			if entry.Topic < 0 || entry.Topic > len(inputs) || entry.Topic >= maxTopics {
+				return ABI{}, fmt.Errorf("wasm: invalid topic count %d of event %s", entry.Topic, entry.Name)
+			}
+			if _, ok := abi.Events[entry.Name]; ok {
+				return ABI{}, fmt.Errorf("wasm: duplicated event %s", entry.Name)
+			}
+			abi.Events[entry.Name] = Event{Name: entry.Name, Topics: entry.Topic, Inputs: inputs}
+		case "struct":
+			fields, err := parseArguments(entry.Name, entry.Fields)
+			if err != nil {
+				return ABI{}, err
+			}
+			if len(entry.BaseClass) > 1 {
+				return ABI{}, fmt.Errorf("wasm: struct %s has more than one base class", entry.Name)
+			}
+			st := Struct{Name: entry.Name, Fields: fields}
+			if len(entry.BaseClass) == 1 {
+				st.Base = entry.BaseClass[0]
+			}
+			abi.Structs[entry.Name] = st
+		default:
+			return ABI{}, fmt.Errorf("wasm: unknown entry type %s of %s", entry.Type, entry.Name)
+		}
+	}
+	return abi, abi.validate()
+}
+
+func parseArguments(owner string, args []jsonArgument) ([]Argument, error) {
+	parsed := make([]Argument, len(args))
+	for i, arg := range args {
+		typ, err := NewType(arg.Type)
+		if err != nil {
+			return nil, fmt.Errorf("wasm: argument %s of %s: %v", arg.Name, owner, err)
+		}
+		if typ.T == VoidTy {
+			return nil, fmt.Errorf("wasm: argument %s of %s is void", arg.Name, owner)
+		}
+		parsed[i] = Argument{Name: arg.Name, Type: typ}
+	}
+	return parsed, nil
+}
+
+// validate checks that all the referred structs are declared.
+func (abi *ABI) validate() error {
+	check := func(owner string, types ...Type) error {
+		for _, typ := range types {
+			for _, name := range typ.Structs() {
+				if _, ok := abi.Structs[name]; !ok {
+					return fmt.Errorf("wasm: undeclared struct %s used by %s", name, owner)
+				}
+			}
+		}
+		return nil
+	}
+	argTypes := func(args []Argument) []Type {
+		types := make([]Type, len(args))
+		for i, arg := range args {
+			types[i] = arg.Type
+		}
+		return types
+	}
+	if err := check(abi.Constructor.Name, argTypes(abi.Constructor.Inputs)...); err != nil {
+		return err
+	}
+	for _, fn := range abi.Functions {
+		if err := check(fn.Name, append(argTypes(fn.Inputs), fn.Output)...); err != nil {
+			return err
+		}
+	}
+	for _, ev := range abi.Events {
+		if err := check(ev.Name, argTypes(ev.Inputs)...); err != nil {
+			return err
+		}
+	}
+	for _, st := range abi.Structs {
+		if st.Base != "" {
+			if _, ok := abi.Structs[st.Base]; !ok {
+				return fmt.Errorf("wasm: undeclared base class %s of %s", st.Base, st.Name)
+			}
+		}
+		if err := check(st.Name, argTypes(st.Fields)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FuncHash returns the identifier of the function in the call input, the
+// contracts dispatch the calls by the FNV-1 64 hash of the function name.
+func FuncHash(name string) uint64 {
+	hash := fnv.New64()
+	hash.Write([]byte(name))
+	return hash.Sum64()
+}
+
+func packCall(fn Function, args []interface{}) ([]byte, error) {
+	if len(args) != len(fn.Inputs) {
+		return nil, fmt.Errorf("wasm: argument count mismatch of %s: have %d, want %d", fn.Name, len(args), len(fn.Inputs))
+	}
+	items := make([]rlp.RawValue, 0, len(args)+1)
+	hash, _ := rlp.EncodeToBytes(FuncHash(fn.Name))
+	items = append(items, hash)
+	for i, arg := range args {
+		item, err := Encode(arg)
+		if err != nil {
+			return nil, fmt.Errorf("wasm: argument %s of %s: %v", fn.Inputs[i].Name, fn.Name, err)
+		}
+		items = append(items, item)
+	}
+	return rlp.EncodeToBytes(items)
+}
+
+// Pack encodes the input calling the function with the arguments, the input
+// of a WASM contract call is rlp([FuncHash(name), args...]).
+func (abi *ABI) Pack(name string, args ...interface{}) ([]byte, error) {
+	fn, ok := abi.Functions[name]
+	if !ok {
+		return nil, fmt.Errorf("wasm: function %s not found", name)
+	}
+	return packCall(fn, args)
+}
+
+// PackDeploy encodes the input deploying the contract code with the arguments
+// of the init function, it is Magic + rlp([code, rlp([FuncHash("init"), args...])]).
+func (abi *ABI) PackDeploy(code []byte, args ...interface{}) ([]byte, error) {
+	if !bytes.HasPrefix(code, Magic) {
+		return nil, errors.New("wasm: code is not a WASM module")
+	}
+	input, err := packCall(abi.Constructor, args)
+	if err != nil {
+		return nil, err
+	}
+	data, err := rlp.EncodeToBytes([][]byte{code, input})
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, Magic...), data...), nil
+}
+
+// Unpack decodes the output of the function into the value pointed to by v.
+func (abi *ABI) Unpack(name string, v interface{}, output []byte) error {
+	fn, ok := abi.Functions[name]
+	if !ok {
+		return fmt.Errorf("wasm: function %s not found", name)
+	}
+	if fn.Output.T == VoidTy {
+		return nil
+	}
+	if len(output) == 0 {
+		return fmt.Errorf("wasm: empty output of %s", name)
+	}
+	return Decode(output, v)
+}
+
+// Topic returns the topic indexing the value in the platon_event logs, it is
+// the RLP encoding of the value, hashed by Keccak256 if longer than a hash.
+func Topic(v interface{}) (common.Hash, error) {
+	enc, err := Encode(v)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(enc) > common.HashLength {
+		return crypto.Keccak256Hash(enc), nil
+	}
+	return common.BytesToHash(enc), nil
+}
+
+// ID returns the first topic of the event logs, the indexed event name.
+func (e Event) ID() common.Hash {
+	id, _ := Topic(e.Name)
+	return id
+}
+
+// MakeTopics converts the filter query of the indexed values into the topics,
+// an empty rule matches any topic.
+func MakeTopics(query ...[]interface{}) ([][]common.Hash, error) {
+	topics := make([][]common.Hash, len(query))
+	for i, filter := range query {
+		for _, rule := range filter {
+			if hash, ok := rule.(common.Hash); ok {
+				topics[i] = append(topics[i], hash)
+				continue
+			}
+			topic, err := Topic(rule)
+			if err != nil {
+				return nil, err
+			}
+			topics[i] = append(topics[i], topic)
+		}
+	}
+	return topics, nil
+}
+
+// UnpackLog decodes the event log into the struct pointed to by out. The
+// leading fields of the struct are the event inputs in order: the indexed
+// inputs are common.Hash fields receiving the topics, the rest are decoded
+// from the log data.
+func (abi *ABI) UnpackLog(out interface{}, name string, topics []common.Hash, data []byte) error {
+	ev, ok := abi.Events[name]
+	if !ok {
+		return fmt.Errorf("wasm: event %s not found", name)
+	}
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("wasm: event target must be a pointer to struct")
+	}
+	rv = rv.Elem()
+	if rv.NumField() < len(ev.Inputs) {
+		return fmt.Errorf("wasm: %v has less fields than the inputs of event %s", rv.Type(), name)
+	}
+	if len(topics) != ev.Topics+1 || topics[0] != ev.ID() {
+		return fmt.Errorf("wasm: log is not event %s", name)
+	}
+	hashType := reflect.TypeOf(common.Hash{})
+	for i := 0; i < ev.Topics; i++ {
+		if rv.Field(i).Type() != hashType {
+			return fmt.Errorf("wasm: indexed field %s of %v is not common.Hash", rv.Type().Field(i).Name, rv.Type())
+		}
+		rv.Field(i).Set(reflect.ValueOf(topics[i+1]))
+	}
+	var items [][]byte
+	if len(data) > 0 {
+		var err error
+		if items, err = decodeList(data); err != nil {
+			return err
+		}
+	}
+	if len(items) != len(ev.Inputs)-ev.Topics {
+		return fmt.Errorf("wasm: log data of event %s has %d values, want %d", name, len(items), len(ev.Inputs)-ev.Topics)
+	}
+	for i, item := range items {
+		if err := decodeValue(item, rv.Field(ev.Topics+i)); err != nil {
+			return fmt.Errorf("wasm: input %s of event %s: %v", ev.Inputs[ev.Topics+i].Name, name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package wasm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/rlp"
+)
+
+const cdtABI = `[
+	{"name":"Base","type":"struct","baseclass":[],"fields":[{"name":"id","type":"uint64"}]},
+	{"name":"Info","type":"struct","baseclass":["Base"],"fields":[{"name":"name","type":"string"},{"name":"tags","type":"map<string,uint32>"}]},
+	{"name":"init","type":"Action","constant":false,"input":[{"name":"owner","type":"FixedHash<20>"}],"output":"void"},
+	{"name":"set_info","type":"Action","constant":false,"input":[{"name":"info","type":"Info"}],"output":"void"},
+	{"name":"get_info","type":"Action","constant":true,"input":[{"name":"id","type":"uint64"}],"output":"Info"},
+	{"name":"balances","type":"Action","constant":true,"input":[],"output":"list<pair<string,uint128>>"},
+	{"name":"transfer","type":"Event","topic":1,"input":[{"name":"from","type":"string"},{"name":"amount","type":"int64"}]}
+]`
+
+const ctoolABI = `[
+	{"name":"atransfer","inputs":[{"name":"from","type":"string"},{"name":"to","type":"string"},{"name":"asset","type":"int32"}],"outputs":[],"constant":"false","type":"function"},
+	{"name":"getBalance","inputs":[{"name":"acc","type":"string"}],"outputs":[{"name":"","type":"int32"}],"constant":"true","type":"function"}
+]`
+
+func TestJSON(t *testing.T) {
+	abi, err := JSON(strings.NewReader(cdtABI))
+	if err != nil {
+		t.Fatalf("failed to parse the platon-cdt ABI: %v", err)
+	}
+	if len(abi.Constructor.Inputs) != 1 || abi.Constructor.Inputs[0].Type.T != AddressTy {
+		t.Errorf("constructor mismatch: %+v", abi.Constructor)
+	}
+	if fn := abi.Functions["get_info"]; !fn.Constant || fn.Output.T != StructTy || fn.Output.Struct != "Info" {
+		t.Errorf("get_info mismatch: %+v", fn)
+	}
+	if fn := abi.Functions["balances"]; fn.Output.T != SliceTy || fn.Output.Elem.T != PairTy || fn.Output.Elem.Elem.T != BigIntTy {
+		t.Errorf("balances mismatch: %+v", fn)
+	}
+	if st := abi.Structs["Info"]; st.Base != "Base" || len(st.Fields) != 2 || st.Fields[1].Type.T != MapTy {
+		t.Errorf("struct Info mismatch: %+v", st)
+	}
+	if ev := abi.Events["transfer"]; ev.Topics != 1 || len(ev.Inputs) != 2 {
+		t.Errorf("event transfer mismatch: %+v", ev)
+	}
+
+	abi, err = JSON(strings.NewReader(ctoolABI))
+	if err != nil {
+		t.Fatalf("failed to parse the ctool ABI: %v", err)
+	}
+	if fn := abi.Functions["atransfer"]; fn.Constant || fn.Output.T != VoidTy || len(fn.Inputs) != 3 {
+		t.Errorf("atransfer mismatch: %+v", fn)
+	}
+	if fn := abi.Functions["getBalance"]; !fn.Constant || fn.Output.T != IntTy || fn.Output.Size != 32 {
+		t.Errorf("getBalance mismatch: %+v", fn)
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	tests := []string{
+		`[{"name":"f","type":"Action","input":[{"name":"a","type":"Missing"}],"output":"void"}]`,
+		`[{"name":"S","type":"struct","baseclass":["Missing"],"fields":[]}]`,
+		`[{"name":"f","type":"Action","input":[],"output":"void"},{"name":"f","type":"Action","input":[],"output":"void"}]`,
+		`[{"name":"e","type":"Event","topic":2,"input":[{"name":"a","type":"string"}]}]`,
+		`[{"name":"f","type":"Action","input":[{"name":"a","type":"map<string>"}],"output":"void"}]`,
+		`[{"name":"f","type":"constructor"}]`,
+	}
+	for i, test := range tests {
+		if _, err := JSON(strings.NewReader(test)); err == nil {
+			t.Errorf("test %d: expected error for %s", i, test)
+		}
+	}
+}
+
+func TestPack(t *testing.T) {
+	abi, err := JSON(strings.NewReader(ctoolABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, err := abi.Pack("atransfer", "alice", "bob", int32(-2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := rlp.EncodeToBytes([]interface{}{FuncHash("atransfer"), "alice", "bob", uint64(3)})
+	if !bytes.Equal(input, want) {
+		t.Errorf("input mismatch: have %x, want %x", input, want)
+	}
+	if _, err := abi.Pack("atransfer", "alice"); err == nil {
+		t.Error("expected error for missing arguments")
+	}
+	if _, err := abi.Pack("missing"); err == nil {
+		t.Error("expected error for unknown function")
+	}
+
+	var balance int32
+	if err := abi.Unpack("getBalance", &balance, []byte{0x05}); err != nil || balance != -3 {
+		t.Errorf("output mismatch: have %d (%v), want -3", balance, err)
+	}
+}
+
+func TestPackDeploy(t *testing.T) {
+	abi, err := JSON(strings.NewReader(cdtABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := append(append([]byte{}, Magic...), 0x01, 0x00, 0x00, 0x00)
+	owner := common.HexToAddress("0x0102030405060708090a0b0c0d0e0f1011121314")
+
+	data, err := abi.PackDeploy(code, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, Magic) {
+		t.Fatalf("deploy data misses the WASM magic: %x", data)
+	}
+	var decoded [][]byte
+	if err := rlp.DecodeBytes(data[len(Magic):], &decoded); err != nil {
+		t.Fatal(err)
+	}
+	init, _ := rlp.EncodeToBytes([]interface{}{FuncHash(InitFunc), owner})
+	if len(decoded) != 2 || !bytes.Equal(decoded[0], code) || !bytes.Equal(decoded[1], init) {
+		t.Errorf("deploy data mismatch: %x", decoded)
+	}
+	if _, err := abi.PackDeploy([]byte{0x60, 0x60}, owner); err == nil {
+		t.Error("expected error for non-WASM code")
+	}
+}
+
+func TestUnpackLog(t *testing.T) {
+	abi, err := JSON(strings.NewReader(cdtABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, _ := Topic("alice")
+	topics, err := MakeTopics([]interface{}{abi.Events["transfer"].ID()}, []interface{}{"alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if topics[1][0] != from {
+		t.Fatalf("topic mismatch: have %x, want %x", topics[1][0], from)
+	}
+	data, _ := rlp.EncodeToBytes([]interface{}{uint64(199)})
+
+	var event struct {
+		From   common.Hash
+		Amount int64
+	}
+	if err := abi.UnpackLog(&event, "transfer", []common.Hash{topics[0][0], topics[1][0]}, data); err != nil {
+		t.Fatal(err)
+	}
+	if event.From != from || event.Amount != -100 {
+		t.Errorf("event mismatch: %+v", event)
+	}
+	if err := abi.UnpackLog(&event, "transfer", []common.Hash{from, from}, data); err == nil {
+		t.Error("expected error for the log of another event")
+	}
+}
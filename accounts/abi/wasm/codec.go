@@ -0,0 +1,316 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package wasm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+
+	"github.com/hashkey-chain/hashkey-chain/rlp"
+)
+
+var bigIntType = reflect.TypeOf(big.Int{})
+
+// Encode encodes the value with the RLP serialization of the platon-cdt:
+//   - the signed integers are zigzag encoded into unsigned ones
+//   - the byte slices and arrays are encoded as strings
+//   - the slices, arrays and structs are encoded as lists, an embedded
+//     struct (the base class of the C++ struct) is a nested list
+//   - the maps are encoded as lists of [key, value] pairs
+func Encode(v interface{}) ([]byte, error) {
+	return encodeValue(reflect.ValueOf(v))
+}
+
+func encodeValue(v reflect.Value) ([]byte, error) {
+	if !v.IsValid() {
+		return nil, errors.New("wasm: cannot encode nil value")
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, errors.New("wasm: cannot encode nil value")
+		}
+		return encodeValue(v.Elem())
+	case reflect.Ptr:
+		if v.Type().Elem() == bigIntType {
+			if v.IsNil() {
+				return rlp.EncodeToBytes(uint(0))
+			}
+			if v.Interface().(*big.Int).Sign() < 0 {
+				return nil, errors.New("wasm: cannot encode negative big.Int")
+			}
+			return rlp.EncodeToBytes(v.Interface())
+		}
+		if v.IsNil() {
+			return nil, fmt.Errorf("wasm: cannot encode nil %v", v.Type())
+		}
+		return encodeValue(v.Elem())
+	case reflect.Bool:
+		return rlp.EncodeToBytes(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x := v.Int()
+		return rlp.EncodeToBytes(uint64((x << 1) ^ (x >> 63)))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rlp.EncodeToBytes(v.Uint())
+	case reflect.String:
+		return rlp.EncodeToBytes(v.String())
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return rlp.EncodeToBytes(b)
+		}
+		items := make([]rlp.RawValue, v.Len())
+		for i := range items {
+			item, err := encodeValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return rlp.EncodeToBytes(items)
+	case reflect.Map:
+		items := make([]rlp.RawValue, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			k, err := encodeValue(key)
+			if err != nil {
+				return nil, err
+			}
+			e, err := encodeValue(v.MapIndex(key))
+			if err != nil {
+				return nil, err
+			}
+			item, _ := rlp.EncodeToBytes([]rlp.RawValue{k, e})
+			items = append(items, item)
+		}
+		// Keep the encoding deterministic
+		sort.Slice(items, func(i, j int) bool { return bytes.Compare(items[i], items[j]) < 0 })
+		return rlp.EncodeToBytes(items)
+	case reflect.Struct:
+		if v.Type() == bigIntType {
+			return encodeValue(v.Addr())
+		}
+		var items []rlp.RawValue
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			item, err := encodeValue(v.Field(i))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return rlp.EncodeToBytes(items)
+	}
+	return nil, fmt.Errorf("wasm: unsupported type %v", v.Type())
+}
+
+// Decode decodes the RLP serialization of the platon-cdt into the value
+// pointed to by v, it is the inverse of Encode.
+func Decode(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("wasm: decode target must be a non-nil pointer")
+	}
+	_, _, rest, err := rlp.Split(data)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 0 {
+		return errors.New("wasm: input contains more than one value")
+	}
+	return decodeValue(data, rv.Elem())
+}
+
+// splitItems splits the content of a RLP list into the encoded items.
+func splitItems(content []byte) ([][]byte, error) {
+	var items [][]byte
+	for len(content) > 0 {
+		_, _, rest, err := rlp.Split(content)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, content[:len(content)-len(rest)])
+		content = rest
+	}
+	return items, nil
+}
+
+func decodeList(data []byte) ([][]byte, error) {
+	content, _, err := rlp.SplitList(data)
+	if err != nil {
+		return nil, err
+	}
+	return splitItems(content)
+}
+
+func decodeValue(data []byte, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.Type().Elem() == bigIntType {
+			content, _, err := rlp.SplitString(data)
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(new(big.Int).SetBytes(content)))
+			return nil
+		}
+		elem := reflect.New(v.Type().Elem())
+		if err := decodeValue(data, elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case reflect.Bool:
+		var b bool
+		if err := rlp.DecodeBytes(data, &b); err != nil {
+			return err
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		u, _, err := rlp.SplitUint64(data)
+		if err != nil {
+			return err
+		}
+		x := int64(u>>1) ^ -int64(u&1)
+		if v.OverflowInt(x) {
+			return fmt.Errorf("wasm: value %d overflows %v", x, v.Type())
+		}
+		v.SetInt(x)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, _, err := rlp.SplitUint64(data)
+		if err != nil {
+			return err
+		}
+		if v.OverflowUint(u) {
+			return fmt.Errorf("wasm: value %d overflows %v", u, v.Type())
+		}
+		v.SetUint(u)
+		return nil
+	case reflect.String:
+		content, _, err := rlp.SplitString(data)
+		if err != nil {
+			return err
+		}
+		v.SetString(string(content))
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			content, _, err := rlp.SplitString(data)
+			if err != nil {
+				return err
+			}
+			v.SetBytes(copyBytes(content))
+			return nil
+		}
+		items, err := decodeList(data)
+		if err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(item, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			content, _, err := rlp.SplitString(data)
+			if err != nil {
+				return err
+			}
+			if len(content) != v.Len() {
+				return fmt.Errorf("wasm: input string length %d mismatches %v", len(content), v.Type())
+			}
+			reflect.Copy(v, reflect.ValueOf(content))
+			return nil
+		}
+		items, err := decodeList(data)
+		if err != nil {
+			return err
+		}
+		if len(items) != v.Len() {
+			return fmt.Errorf("wasm: input list length %d mismatches %v", len(items), v.Type())
+		}
+		for i, item := range items {
+			if err := decodeValue(item, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		items, err := decodeList(data)
+		if err != nil {
+			return err
+		}
+		m := reflect.MakeMapWithSize(v.Type(), len(items))
+		for _, item := range items {
+			pair, err := decodeList(item)
+			if err != nil {
+				return err
+			}
+			if len(pair) != 2 {
+				return fmt.Errorf("wasm: invalid map entry of %v", v.Type())
+			}
+			key, elem := reflect.New(v.Type().Key()).Elem(), reflect.New(v.Type().Elem()).Elem()
+			if err := decodeValue(pair[0], key); err != nil {
+				return err
+			}
+			if err := decodeValue(pair[1], elem); err != nil {
+				return err
+			}
+			m.SetMapIndex(key, elem)
+		}
+		v.Set(m)
+		return nil
+	case reflect.Struct:
+		items, err := decodeList(data)
+		if err != nil {
+			return err
+		}
+		var fields []int
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath == "" {
+				fields = append(fields, i)
+			}
+		}
+		if len(items) != len(fields) {
+			return fmt.Errorf("wasm: input list length %d mismatches %v", len(items), v.Type())
+		}
+		for i, item := range items {
+			if err := decodeValue(item, v.Field(fields[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("wasm: unsupported type %v", v.Type())
+}
+
+// copyBytes copies the bytes so that the decoded value doesn't share the input.
+func copyBytes(b []byte) []byte {
+	return append([]byte{}, b...)
+}
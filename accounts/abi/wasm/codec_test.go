@@ -0,0 +1,134 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package wasm
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/rlp"
+)
+
+type Base struct {
+	ID uint64
+}
+
+type Info struct {
+	Base
+	Name    string
+	Tags    map[string]uint32
+	Owner   common.Address
+	Balance *big.Int
+	Pair    struct {
+		First  int8
+		Second []byte
+	}
+	Hashes [2][4]byte
+	hidden bool
+}
+
+func TestEncodeDecode(t *testing.T) {
+	tests := []interface{}{
+		true,
+		int8(-128),
+		int64(-1 << 62),
+		uint16(65535),
+		"hskchain",
+		[]byte{},
+		[]byte{1, 2, 3},
+		[3]byte{1, 2, 3},
+		[]int32{-1, 0, 1},
+		map[uint64]string{1: "a", 2: "b"},
+		big.NewInt(0),
+		new(big.Int).Lsh(big.NewInt(1), 127),
+		Info{
+			Base:    Base{ID: 7},
+			Name:    "info",
+			Tags:    map[string]uint32{"x": 1, "y": 2},
+			Owner:   common.HexToAddress("0x01"),
+			Balance: big.NewInt(1000),
+			Pair: struct {
+				First  int8
+				Second []byte
+			}{-1, []byte{9}},
+			Hashes: [2][4]byte{{1}, {2}},
+		},
+	}
+	for i, test := range tests {
+		enc, err := Encode(test)
+		if err != nil {
+			t.Fatalf("test %d: encode failed: %v", i, err)
+		}
+		out := reflect.New(reflect.TypeOf(test))
+		if err := Decode(enc, out.Interface()); err != nil {
+			t.Fatalf("test %d: decode failed: %v", i, err)
+		}
+		if !reflect.DeepEqual(out.Elem().Interface(), test) {
+			t.Errorf("test %d: roundtrip mismatch: have %v, want %v", i, out.Elem().Interface(), test)
+		}
+	}
+}
+
+func TestEncodeLayout(t *testing.T) {
+	// The signed integers are zigzag encoded
+	enc, _ := Encode(int32(-1))
+	if !bytes.Equal(enc, []byte{0x01}) {
+		t.Errorf("int32 encoding mismatch: %x", enc)
+	}
+	// The base class is a nested list ahead of the fields
+	enc, _ = Encode(struct {
+		Base
+		Name string
+	}{Base{ID: 1}, "a"})
+	want, _ := rlp.EncodeToBytes([]interface{}{[]interface{}{uint64(1)}, "a"})
+	if !bytes.Equal(enc, want) {
+		t.Errorf("struct encoding mismatch: have %x, want %x", enc, want)
+	}
+	// The map entries are sorted whatever the iteration order
+	first, _ := Encode(map[string]bool{"a": true, "b": false, "c": true})
+	for i := 0; i < 10; i++ {
+		enc, _ := Encode(map[string]bool{"a": true, "b": false, "c": true})
+		if !bytes.Equal(enc, first) {
+			t.Fatalf("map encoding is not deterministic")
+		}
+	}
+	if _, err := Encode(big.NewInt(-1)); err == nil {
+		t.Error("expected error for negative big.Int")
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	var i8 int8
+	enc, _ := Encode(int64(1000))
+	if err := Decode(enc, &i8); err == nil {
+		t.Error("expected overflow error")
+	}
+	var arr [2]uint64
+	enc, _ = Encode([]uint64{1, 2, 3})
+	if err := Decode(enc, &arr); err == nil {
+		t.Error("expected array length error")
+	}
+	if err := Decode(append(enc, 0x01), new([]uint64)); err == nil {
+		t.Error("expected trailing data error")
+	}
+	if err := Decode(enc, arr); err == nil {
+		t.Error("expected non-pointer error")
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package wasm
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Type enumerator
+const (
+	VoidTy byte = iota
+	BoolTy
+	IntTy
+	UintTy
+	BigIntTy
+	StringTy
+	BytesTy
+	FixedBytesTy
+	AddressTy
+	SliceTy
+	ArrayTy
+	MapTy
+	PairTy
+	StructTy
+)
+
+var (
+	// typeRegex parses the sized integer types
+	typeRegex = regexp.MustCompile("^(u?int)([0-9]+)$")
+	// arrayRegex parses the T[] and T[N] types
+	arrayRegex = regexp.MustCompile(`^(.+)\[([0-9]*)\]$`)
+	// genericRegex parses the template types of the C++ contracts, e.g. map<K,V>
+	genericRegex = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_:]*)<(.+)>$`)
+	// identRegex matches the names of the user defined structs
+	identRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Type is the reflection of a type declared in the WASM contract ABI.
+type Type struct {
+	T    byte  // Our own type checking
+	Size int   // Bit size of the integers, length of the arrays and the fixed bytes
+	Elem *Type // Element of the slices and arrays, value of the maps, second of the pairs
+	Key  *Type // Key of the maps, first of the pairs
+
+	Struct string // Name of the user defined struct
+	String string // Declared type string
+}
+
+// NewType parses the type declared in the WASM contract ABI. The types of the
+// platon-cdt contracts and of the legacy ctool ABIs are both accepted.
+func NewType(t string) (Type, error) {
+	t = strings.Join(strings.Fields(t), "")
+	typ := Type{String: t}
+
+	if match := arrayRegex.FindStringSubmatch(t); match != nil {
+		elem, err := NewType(match[1])
+		if err != nil {
+			return Type{}, err
+		}
+		if match[2] == "" {
+			return newSliceType(t, elem), nil
+		}
+		size, err := strconv.Atoi(match[2])
+		if err != nil {
+			return Type{}, fmt.Errorf("invalid array size of %s", t)
+		}
+		return newArrayType(t, elem, size), nil
+	}
+	if match := genericRegex.FindStringSubmatch(t); match != nil {
+		return newGenericType(t, match[1], splitArgs(match[2]))
+	}
+	if match := typeRegex.FindStringSubmatch(t); match != nil {
+		size, _ := strconv.Atoi(match[2])
+		switch {
+		case size == 8 || size == 16 || size == 32 || size == 64:
+			typ.Size = size
+			if match[1] == "int" {
+				typ.T = IntTy
+			} else {
+				typ.T = UintTy
+			}
+			return typ, nil
+		case match[1] == "uint" && (size == 128 || size == 256):
+			typ.T, typ.Size = BigIntTy, size
+			return typ, nil
+		}
+		return Type{}, fmt.Errorf("unsupported integer type %s", t)
+	}
+	switch t {
+	case "", "void":
+		typ.T = VoidTy
+	case "bool":
+		typ.T = BoolTy
+	case "u128", "bigint":
+		typ.T = BigIntTy
+	case "string":
+		typ.T = StringTy
+	case "bytes":
+		typ.T = BytesTy
+	case "Address", "address":
+		typ.T, typ.Size = AddressTy, 20
+	default:
+		if !identRegex.MatchString(t) {
+			return Type{}, fmt.Errorf("unsupported type %s", t)
+		}
+		typ.T, typ.Struct = StructTy, t
+	}
+	return typ, nil
+}
+
+func newSliceType(t string, elem Type) Type {
+	if elem.T == UintTy && elem.Size == 8 {
+		return Type{T: BytesTy, String: t}
+	}
+	return Type{T: SliceTy, Elem: &elem, String: t}
+}
+
+func newArrayType(t string, elem Type, size int) Type {
+	if elem.T == UintTy && elem.Size == 8 {
+		return Type{T: FixedBytesTy, Size: size, String: t}
+	}
+	return Type{T: ArrayTy, Size: size, Elem: &elem, String: t}
+}
+
+func newGenericType(t, name string, args []string) (Type, error) {
+	params := make([]Type, len(args))
+	for i, arg := range args {
+		if name == "array" && i == 1 || name == "FixedHash" {
+			continue
+		}
+		param, err := NewType(arg)
+		if err != nil {
+			return Type{}, err
+		}
+		params[i] = param
+	}
+	arity := map[string]int{"list": 1, "set": 1, "vector": 1, "array": 2, "map": 2, "pair": 2, "FixedHash": 1}
+	if n, ok := arity[name]; !ok {
+		return Type{}, fmt.Errorf("unsupported type %s", t)
+	} else if n != len(args) {
+		return Type{}, fmt.Errorf("invalid type %s, %s takes %d type arguments", t, name, n)
+	}
+	switch name {
+	case "list", "set", "vector":
+		return newSliceType(t, params[0]), nil
+	case "array":
+		size, err := strconv.Atoi(args[1])
+		if err != nil {
+			return Type{}, fmt.Errorf("invalid array size of %s", t)
+		}
+		return newArrayType(t, params[0], size), nil
+	case "FixedHash":
+		size, err := strconv.Atoi(args[0])
+		if err != nil {
+			return Type{}, fmt.Errorf("invalid hash size of %s", t)
+		}
+		if size == 20 {
+			return Type{T: AddressTy, Size: size, String: t}, nil
+		}
+		return Type{T: FixedBytesTy, Size: size, String: t}, nil
+	case "map":
+		return Type{T: MapTy, Key: &params[0], Elem: &params[1], String: t}, nil
+	default:
+		return Type{T: PairTy, Key: &params[0], Elem: &params[1], String: t}, nil
+	}
+}
+
+// splitArgs splits the type arguments of a template type at the top level
+// commas, e.g. "string,map<string,uint64>" is split into two arguments.
+func splitArgs(s string) []string {
+	var (
+		args  []string
+		depth int
+		start int
+	)
+	for i, c := range s {
+		switch c {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(args, s[start:])
+}
+
+// Structs returns the names of the user defined structs the type refers to.
+func (t Type) Structs() []string {
+	var names []string
+	if t.T == StructTy {
+		names = append(names, t.Struct)
+	}
+	if t.Key != nil {
+		names = append(names, t.Key.Structs()...)
+	}
+	if t.Elem != nil {
+		names = append(names, t.Elem.Structs()...)
+	}
+	return names
+}
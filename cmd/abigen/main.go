@@ -17,6 +17,8 @@
 package main
 
 import (
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -30,6 +32,7 @@ import (
 	"gopkg.in/urfave/cli.v1"
 
 	"github.com/hashkey-chain/hashkey-chain/accounts/abi/bind"
+	"github.com/hashkey-chain/hashkey-chain/accounts/abi/wasm"
 	"github.com/hashkey-chain/hashkey-chain/cmd/utils"
 	"github.com/hashkey-chain/hashkey-chain/common/compiler"
 	"github.com/hashkey-chain/hashkey-chain/crypto"
@@ -100,6 +103,10 @@ var (
 		Name:  "alias",
 		Usage: "Comma separated aliases for function and event renaming, e.g. foo=bar",
 	}
+	wasmFlag = cli.BoolFlag{
+		Name:  "wasm",
+		Usage: "Generate the binding of a WASM contract from its ABI (--abi) and optional module (--bin, binary or hex)",
+	}
 )
 
 func init() {
@@ -118,6 +125,7 @@ func init() {
 		outFlag,
 		langFlag,
 		aliasFlag,
+		wasmFlag,
 	}
 	app.Action = utils.MigrateFlags(abigen)
 	cli.CommandHelpTemplate = utils.OriginCommandHelpTemplate
@@ -140,6 +148,15 @@ func abigen(c *cli.Context) error {
 	default:
 		utils.Fatalf("Unsupported destination language \"%s\" (--lang)", c.GlobalString(langFlag.Name))
 	}
+	isWasm := c.GlobalBool(wasmFlag.Name)
+	if isWasm {
+		if c.GlobalString(abiFlag.Name) == "" {
+			utils.Fatalf("WASM binding generation requires the contract ABI (--abi)")
+		}
+		if lang != bind.LangGo {
+			utils.Fatalf("WASM binding generation only supports the go language (--lang)")
+		}
+	}
 	// If the entire solidity code was specified, build and bind based on that
 	var (
 		abis    []string
@@ -171,7 +188,12 @@ func abigen(c *cli.Context) error {
 			if bin, err = ioutil.ReadFile(binFile); err != nil {
 				utils.Fatalf("Failed to read input bytecode: %v", err)
 			}
-			if strings.Contains(string(bin), "//") {
+			if isWasm {
+				// The WASM module may be given as the binary output of the platon-cdt
+				if bytes.HasPrefix(bin, wasm.Magic) {
+					bin = []byte(hex.EncodeToString(bin))
+				}
+			} else if strings.Contains(string(bin), "//") {
 				utils.Fatalf("Contract has additional library references, please use other mode(e.g. --combined-json) to catch library infos")
 			}
 		}
@@ -256,7 +278,15 @@ func abigen(c *cli.Context) error {
 		}
 	}
 	// Generate the contract binding
-	code, err := bind.Bind(types, abis, bins, sigs, c.GlobalString(pkgFlag.Name), lang, libs, aliases)
+	var (
+		code string
+		err  error
+	)
+	if isWasm {
+		code, err = bind.BindWasm(types, abis, bins, c.GlobalString(pkgFlag.Name), aliases)
+	} else {
+		code, err = bind.Bind(types, abis, bins, sigs, c.GlobalString(pkgFlag.Name), lang, libs, aliases)
+	}
 	if err != nil {
 		utils.Fatalf("Failed to generate ABI binding: %v", err)
 	}
@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 
 	"gopkg.in/urfave/cli.v1"
 
@@ -71,6 +72,11 @@ func buildParams(funcType uint16, params ...interface{}) [][]byte {
 	fnType, _ := rlp.EncodeToBytes(funcType)
 	res = append(res, fnType)
 	for _, param := range params {
+		// the absent optional params are sent as empty items
+		if v := reflect.ValueOf(param); v.Kind() == reflect.Ptr && v.IsNil() {
+			res = append(res, []byte{})
+			continue
+		}
 		val, err := rlp.EncodeToBytes(param)
 		if err != nil {
 			panic(err)
@@ -93,6 +99,8 @@ func funcTypeToContractAddress(funcType uint16) common.Address {
 		toadd = vm.RestrictingContractAddr
 	case funcType >= 5000 && funcType < 6000:
 		toadd = vm.DelegateRewardPoolAddr
+	case funcType >= 6000 && funcType < 7000:
+		toadd = vm.ScheduledTxContractAddr
 	}
 	return toadd
 }
@@ -16,7 +16,11 @@
 
 package ppos
 
-import "gopkg.in/urfave/cli.v1"
+import (
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/hashkey-chain/hashkey-chain/accounts"
+)
 
 var (
 	rpcUrlFlag = cli.StringFlag{
@@ -34,3 +38,88 @@ var (
 		Usage: "set address hrp",
 	}
 )
+
+var (
+	keystoreFlag = cli.StringFlag{
+		Name:  "keystore",
+		Usage: "the keystore file signing the transaction",
+	}
+
+	passwordFlag = cli.StringFlag{
+		Name:  "password",
+		Usage: "the file containing the password of the keystore, prompted if not set",
+	}
+
+	usbFlag = cli.BoolFlag{
+		Name:  "usb",
+		Usage: "sign the transaction with the usb hardware wallet",
+	}
+
+	hdPathFlag = cli.StringFlag{
+		Name:  "hdpath",
+		Value: accounts.DefaultBaseDerivationPath.String(),
+		Usage: "the derivation path of the usb wallet account",
+	}
+
+	offlineFlag = cli.BoolFlag{
+		Name:  "offline",
+		Usage: "print the signed raw transaction instead of sending it, --nonce, --gas, --gasPrice and --chainId are required",
+	}
+
+	nonceFlag = cli.Uint64Flag{
+		Name:  "nonce",
+		Usage: "the nonce of the transaction, the pending nonce of the sender if not set",
+	}
+
+	gasFlag = cli.Uint64Flag{
+		Name:  "gas",
+		Usage: "the gas limit of the transaction, estimated if not set",
+	}
+
+	gasPriceFlag = cli.StringFlag{
+		Name:  "gasPrice",
+		Usage: "the gas price in von, suggested by the node if not set",
+	}
+
+	chainIdFlag = cli.Uint64Flag{
+		Name:  "chainId",
+		Usage: "the chain id, queried from the node if not set",
+	}
+
+	noWaitFlag = cli.BoolFlag{
+		Name:  "nowait",
+		Usage: "do not wait for the receipt of the transaction",
+	}
+
+	amountFlag = cli.StringFlag{
+		Name:  "amount",
+		Usage: "the amount in von",
+	}
+)
+
+var (
+	programVersionFlag = cli.Uint64Flag{
+		Name:  "programVersion",
+		Usage: "the program version of the node, queried from the node if not set",
+	}
+
+	programVersionSignFlag = cli.StringFlag{
+		Name:  "programVersionSign",
+		Usage: "the signature of the program version",
+	}
+
+	nodeIdSignFlag = cli.StringFlag{
+		Name:  "nodeIdSign",
+		Usage: "the signature of the new node key on the rlp of [stakingAddress, nodeid, newNodeid, epoch], epoch is the settlement epoch the transaction is packed in",
+	}
+
+	blsPubKeyFlag = cli.StringFlag{
+		Name:  "blsPubKey",
+		Usage: "the bls public key of the node",
+	}
+
+	blsProofFlag = cli.StringFlag{
+		Name:  "blsProof",
+		Usage: "the proof of the bls public key, generated by the node if not set",
+	}
+)
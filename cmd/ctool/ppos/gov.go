@@ -29,6 +29,13 @@ var (
 		Name:  "gov",
 		Usage: "use for gov func",
 		Subcommands: []cli.Command{
+			submitTextCmd,
+			submitVersionCmd,
+			submitParamCmd,
+			voteCmd,
+			declareVersionCmd,
+			submitCancelCmd,
+			submitAllowlistCmd,
 			getProposalCmd,
 			getTallyResultCmd,
 			listProposalCmd,
@@ -36,8 +43,58 @@ var (
 			getGovernParamValueCmd,
 			getAccuVerifiersCountCmd,
 			listGovernParamCmd,
+			listAllowlistCmd,
 		},
 	}
+	submitTextCmd = cli.Command{
+		Name:   "submitText",
+		Usage:  "2000,submit a text proposal,parameter:nodeid,pipID",
+		Before: netCheck,
+		Action: submitText,
+		Flags:  txFlags(nodeIdFlag, pipIDFlag),
+	}
+	submitVersionCmd = cli.Command{
+		Name:   "submitVersion",
+		Usage:  "2001,submit a version proposal,parameter:nodeid,pipID,newVersion,endVotingRounds",
+		Before: netCheck,
+		Action: submitVersion,
+		Flags:  txFlags(nodeIdFlag, pipIDFlag, newVersionFlag, endVotingRoundsFlag),
+	}
+	submitParamCmd = cli.Command{
+		Name:   "submitParam",
+		Usage:  "2002,submit a parameter proposal,parameter:nodeid,pipID,module,name,newValue",
+		Before: netCheck,
+		Action: submitParam,
+		Flags:  txFlags(nodeIdFlag, pipIDFlag, moduleFlag, nameFlag, newValueFlag),
+	}
+	voteCmd = cli.Command{
+		Name:   "vote",
+		Usage:  "2003,vote a proposal,parameter:nodeid,proposalID,option,programVersion,programVersionSign",
+		Before: netCheck,
+		Action: vote,
+		Flags:  txFlags(nodeIdFlag, proposalIDFlag, voteOptionFlag, programVersionFlag, programVersionSignFlag),
+	}
+	declareVersionCmd = cli.Command{
+		Name:   "declareVersion",
+		Usage:  "2004,declare the program version of the node,parameter:nodeid,programVersion,programVersionSign",
+		Before: netCheck,
+		Action: declareVersion,
+		Flags:  txFlags(nodeIdFlag, programVersionFlag, programVersionSignFlag),
+	}
+	submitCancelCmd = cli.Command{
+		Name:   "submitCancel",
+		Usage:  "2005,submit a cancel proposal,parameter:nodeid,pipID,endVotingRounds,tobeCanceled",
+		Before: netCheck,
+		Action: submitCancel,
+		Flags:  txFlags(nodeIdFlag, pipIDFlag, endVotingRoundsFlag, tobeCanceledFlag),
+	}
+	submitAllowlistCmd = cli.Command{
+		Name:   "submitAllowlist",
+		Usage:  "2006,submit an allowlist proposal,parameter:nodeid,pipID,operation,allowlistNode,stakingAddress",
+		Before: netCheck,
+		Action: submitAllowlist,
+		Flags:  txFlags(nodeIdFlag, pipIDFlag, allowlistOperationFlag, allowlistNodeFlag, stakingAddressFlag),
+	}
 	getProposalCmd = cli.Command{
 		Name:   "getProposal",
 		Usage:  "2100,get proposal,parameter:proposalID",
@@ -87,6 +144,13 @@ var (
 		Action: listGovernParam,
 		Flags:  []cli.Flag{rpcUrlFlag, addressHRPFlag, moduleFlag, jsonFlag},
 	}
+	listAllowlistCmd = cli.Command{
+		Name:   "listAllowlist",
+		Usage:  "2107,query the nodes admitted to stake",
+		Before: netCheck,
+		Action: listAllowlist,
+		Flags:  []cli.Flag{rpcUrlFlag, addressHRPFlag, jsonFlag},
+	}
 	proposalIDFlag = cli.StringFlag{
 		Name:  "proposalID",
 		Usage: "proposalID",
@@ -103,6 +167,42 @@ var (
 		Name:  "blockHash",
 		Usage: "blockHash",
 	}
+	pipIDFlag = cli.StringFlag{
+		Name:  "pipID",
+		Usage: "the PIP id of the proposal",
+	}
+	newVersionFlag = cli.Uint64Flag{
+		Name:  "newVersion",
+		Usage: "the new version of the version proposal",
+	}
+	endVotingRoundsFlag = cli.Uint64Flag{
+		Name:  "endVotingRounds",
+		Usage: "the voting consensus rounds of the proposal",
+	}
+	newValueFlag = cli.StringFlag{
+		Name:  "newValue",
+		Usage: "the new value of the parameter",
+	}
+	tobeCanceledFlag = cli.StringFlag{
+		Name:  "tobeCanceled",
+		Usage: "the id of the version proposal to be canceled",
+	}
+	voteOptionFlag = cli.Uint64Flag{
+		Name:  "option",
+		Usage: "vote option, 1: yeas, 2: nays, 3: abstentions",
+	}
+	allowlistOperationFlag = cli.Uint64Flag{
+		Name:  "operation",
+		Usage: "allowlist operation, 1: add, 2: remove",
+	}
+	allowlistNodeFlag = cli.StringFlag{
+		Name:  "allowlistNode",
+		Usage: "the node id to add into the allowlist or remove",
+	}
+	stakingAddressFlag = cli.StringFlag{
+		Name:  "stakingAddress",
+		Usage: "the account allowed to stake the node, ignored on removing",
+	}
 )
 
 func getProposal(c *cli.Context) error {
@@ -161,3 +261,89 @@ func listGovernParam(c *cli.Context) error {
 	module := c.String(moduleFlag.Name)
 	return query(c, 2106, module)
 }
+
+func listAllowlist(c *cli.Context) error {
+	return query(c, 2107)
+}
+
+func submitText(c *cli.Context) error {
+	verifier, err := parseNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	return transact(c, 2000, verifier, c.String(pipIDFlag.Name))
+}
+
+func submitVersion(c *cli.Context) error {
+	verifier, err := parseNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	return transact(c, 2001, verifier, c.String(pipIDFlag.Name), uint32(c.Uint64(newVersionFlag.Name)), c.Uint64(endVotingRoundsFlag.Name))
+}
+
+func submitParam(c *cli.Context) error {
+	verifier, err := parseNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	return transact(c, 2002, verifier, c.String(pipIDFlag.Name), c.String(moduleFlag.Name), c.String(nameFlag.Name), c.String(newValueFlag.Name))
+}
+
+func vote(c *cli.Context) error {
+	verifier, err := parseNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	proposalIDstring := c.String(proposalIDFlag.Name)
+	if proposalIDstring == "" {
+		return errors.New("param proposalID not set")
+	}
+	programVersion, programVersionSign, err := parseProgramVersion(c)
+	if err != nil {
+		return err
+	}
+	return transact(c, 2003, verifier, common.HexToHash(proposalIDstring), uint8(c.Uint64(voteOptionFlag.Name)), programVersion, programVersionSign)
+}
+
+func declareVersion(c *cli.Context) error {
+	node, err := parseNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	programVersion, programVersionSign, err := parseProgramVersion(c)
+	if err != nil {
+		return err
+	}
+	return transact(c, 2004, node, programVersion, programVersionSign)
+}
+
+func submitCancel(c *cli.Context) error {
+	verifier, err := parseNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	tobeCanceled := c.String(tobeCanceledFlag.Name)
+	if tobeCanceled == "" {
+		return errors.New("param tobeCanceled not set")
+	}
+	return transact(c, 2005, verifier, c.String(pipIDFlag.Name), c.Uint64(endVotingRoundsFlag.Name), common.HexToHash(tobeCanceled))
+}
+
+func submitAllowlist(c *cli.Context) error {
+	verifier, err := parseNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	node, err := parseNodeID(c, allowlistNodeFlag)
+	if err != nil {
+		return err
+	}
+	var stakingAddress common.Address
+	if c.IsSet(stakingAddressFlag.Name) {
+		if stakingAddress, err = parseAddress(c, stakingAddressFlag); err != nil {
+			return err
+		}
+	}
+	return transact(c, 2006, verifier, c.String(pipIDFlag.Name), uint8(c.Uint64(allowlistOperationFlag.Name)), node, stakingAddress)
+}
@@ -18,10 +18,15 @@ package ppos
 
 import (
 	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
 
 	"gopkg.in/urfave/cli.v1"
 
 	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/x/restricting"
 )
 
 var (
@@ -29,9 +34,21 @@ var (
 		Name:  "restricting",
 		Usage: "use for restricting",
 		Subcommands: []cli.Command{
+			createRestrictingPlanCmd,
 			getRestrictingInfoCmd,
 		},
 	}
+	createRestrictingPlanCmd = cli.Command{
+		Name:   "createRestrictingPlan",
+		Usage:  "4000,create the restricting plans releasing to the account,parameter:address,plans",
+		Before: netCheck,
+		Action: createRestrictingPlan,
+		Flags:  txFlags(addFlag, plansFlag),
+	}
+	plansFlag = cli.StringSliceFlag{
+		Name:  "plans",
+		Usage: "the restricting plan as epoch:amount, the amount in von",
+	}
 	getRestrictingInfoCmd = cli.Command{
 		Name:   "getRestrictingInfo",
 		Usage:  "4100,get restricting info,parameter:address",
@@ -52,3 +69,30 @@ func getRestrictingInfo(c *cli.Context) error {
 	}
 	return query(c, 4100, add)
 }
+
+func createRestrictingPlan(c *cli.Context) error {
+	account, err := parseAddress(c, addFlag)
+	if err != nil {
+		return err
+	}
+	var plans []restricting.RestrictingPlan
+	for _, plan := range c.StringSlice(plansFlag.Name) {
+		parts := strings.Split(plan, ":")
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid restricting plan: %s", plan)
+		}
+		epoch, err := strconv.ParseUint(parts[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid epoch of the restricting plan %s: %v", plan, err)
+		}
+		amount, ok := new(big.Int).SetString(parts[1], 0)
+		if !ok {
+			return fmt.Errorf("invalid amount of the restricting plan: %s", plan)
+		}
+		plans = append(plans, restricting.RestrictingPlan{Epoch: epoch, Amount: amount})
+	}
+	if len(plans) == 0 {
+		return errors.New("The restricting plans are not set")
+	}
+	return transact(c, 4000, account, plans)
+}
@@ -17,8 +17,11 @@
 package ppos
 
 import (
+	"fmt"
+
 	"gopkg.in/urfave/cli.v1"
 
+	pposclient "github.com/hashkey-chain/hashkey-chain/ethclient/ppos"
 	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
 )
 
@@ -27,9 +30,17 @@ var (
 		Name:  "reward",
 		Usage: "use for reward",
 		Subcommands: []cli.Command{
+			withdrawDelegateRewardCmd,
 			getDelegateRewardCmd,
 		},
 	}
+	withdrawDelegateRewardCmd = cli.Command{
+		Name:   "withdrawDelegateReward",
+		Usage:  "5000,withdraw the delegate rewards of the account",
+		Before: netCheck,
+		Action: withdrawDelegateReward,
+		Flags:  txFlags(),
+	}
 	getDelegateRewardCmd = cli.Command{
 		Name:   "getDelegateReward",
 		Usage:  "5100,query account not withdrawn commission rewards at each node,parameter:nodeList(can empty)",
@@ -43,6 +54,21 @@ var (
 	}
 )
 
+func withdrawDelegateReward(c *cli.Context) error {
+	receipt, err := sendTransaction(c, 5000)
+	if err != nil || receipt == nil {
+		return err
+	}
+	rewards, err := pposclient.WithdrawnDelegateReward(receipt)
+	if err != nil {
+		return err
+	}
+	for _, r := range rewards {
+		fmt.Printf("node: %s, stakingBlock: %d, reward: %v\n", r.NodeID.TerminalString(), r.StakingNum, r.Reward)
+	}
+	return nil
+}
+
 func getDelegateReward(c *cli.Context) error {
 	nodeIDlist := c.StringSlice(nodeList.Name)
 	idlist := make([]discover.NodeID, 0)
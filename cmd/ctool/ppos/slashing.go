@@ -18,6 +18,7 @@ package ppos
 
 import (
 	"errors"
+	"io/ioutil"
 
 	"gopkg.in/urfave/cli.v1"
 
@@ -29,10 +30,24 @@ var (
 		Name:  "slashing",
 		Usage: "use for slashing",
 		Subcommands: []cli.Command{
+			reportDuplicateSignCmd,
 			checkDuplicateSignCmd,
 			zeroProduceNodeListCmd,
 		},
 	}
+	reportDuplicateSignCmd = cli.Command{
+		Name:   "reportDuplicateSign",
+		Usage:  "3000,report the duplicate signing of a node,parameter:duplicateSignType,data",
+		Before: netCheck,
+		Action: reportDuplicateSign,
+		Flags: txFlags(
+			cli.Uint64Flag{
+				Name:  "duplicateSignType",
+				Usage: "duplicateSign type,1：prepareBlock，2：prepareVote，3：viewChange",
+			},
+			evidenceFlag,
+		),
+	}
 	checkDuplicateSignCmd = cli.Command{
 		Name:   "checkDuplicateSign",
 		Usage:  "3001,query whether the node has been reported for too many signatures,parameter:duplicateSignType,nodeid,blockNum",
@@ -58,8 +73,24 @@ var (
 		Name:  "blockNum",
 		Usage: "blockNum",
 	}
+	evidenceFlag = cli.StringFlag{
+		Name:  "evidence",
+		Usage: "the file of the duplicate signing evidence in JSON",
+	}
 )
 
+func reportDuplicateSign(c *cli.Context) error {
+	file := c.String(evidenceFlag.Name)
+	if file == "" {
+		return errors.New("The evidence file is not set")
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return transact(c, 3000, uint8(c.Uint64("duplicateSignType")), string(data))
+}
+
 func checkDuplicateSign(c *cli.Context) error {
 	duplicateSignType := c.Uint64("duplicateSignType")
 
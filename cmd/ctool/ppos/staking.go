@@ -18,6 +18,7 @@ package ppos
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/hashkey-chain/hashkey-chain/common"
 
@@ -31,6 +32,14 @@ var (
 		Name:  "staking",
 		Usage: "use for staking",
 		Subcommands: []cli.Command{
+			createStakingCmd,
+			editCandidateCmd,
+			increaseStakingCmd,
+			withdrewStakingCmd,
+			delegateCmd,
+			withdrewDelegationCmd,
+			redeemDelegationCmd,
+			rotateCandidateKeyCmd,
 			GetVerifierListCmd,
 			getValidatorListCmd,
 			getCandidateListCmd,
@@ -43,6 +52,63 @@ var (
 			getAvgPackTimeCmd,
 		},
 	}
+	createStakingCmd = cli.Command{
+		Name:   "createStaking",
+		Usage:  "1000,stake the node to be a candidate,parameter:type,benefitAddress,nodeid,externalId,nodeName,website,details,amount,rewardPer,programVersion,programVersionSign,blsPubKey,blsProof",
+		Before: netCheck,
+		Action: createStaking,
+		Flags: txFlags(amountTypeFlag, benefitAddressFlag, nodeIdFlag, externalIdFlag, nodeNameFlag, websiteFlag, detailsFlag,
+			amountFlag, rewardPerFlag, programVersionFlag, programVersionSignFlag, blsPubKeyFlag, blsProofFlag),
+	}
+	editCandidateCmd = cli.Command{
+		Name:   "editCandidate",
+		Usage:  "1001,edit the candidate, the unset fields are unchanged,parameter:nodeid,benefitAddress,rewardPer,externalId,nodeName,website,details",
+		Before: netCheck,
+		Action: editCandidate,
+		Flags:  txFlags(nodeIdFlag, benefitAddressFlag, rewardPerFlag, externalIdFlag, nodeNameFlag, websiteFlag, detailsFlag),
+	}
+	increaseStakingCmd = cli.Command{
+		Name:   "increaseStaking",
+		Usage:  "1002,increase the staking of the candidate,parameter:nodeid,type,amount",
+		Before: netCheck,
+		Action: increaseStaking,
+		Flags:  txFlags(nodeIdFlag, amountTypeFlag, amountFlag),
+	}
+	withdrewStakingCmd = cli.Command{
+		Name:   "withdrewStaking",
+		Usage:  "1003,withdraw the staking of the candidate,parameter:nodeid",
+		Before: netCheck,
+		Action: withdrewStaking,
+		Flags:  txFlags(nodeIdFlag),
+	}
+	delegateCmd = cli.Command{
+		Name:   "delegate",
+		Usage:  "1004,delegate to the candidate,parameter:type,nodeid,amount",
+		Before: netCheck,
+		Action: delegate,
+		Flags:  txFlags(amountTypeFlag, nodeIdFlag, amountFlag),
+	}
+	withdrewDelegationCmd = cli.Command{
+		Name:   "withdrewDelegation",
+		Usage:  "1005,withdraw the delegation of the candidate,parameter:stakingBlock,nodeid,amount",
+		Before: netCheck,
+		Action: withdrewDelegation,
+		Flags:  txFlags(stakingBlockNumFlag, nodeIdFlag, amountFlag),
+	}
+	redeemDelegationCmd = cli.Command{
+		Name:   "redeemDelegation",
+		Usage:  "1006,redeem the unlocked delegations",
+		Before: netCheck,
+		Action: redeemDelegation,
+		Flags:  txFlags(),
+	}
+	rotateCandidateKeyCmd = cli.Command{
+		Name:   "rotateCandidateKey",
+		Usage:  "1007,rotate the node key of the candidate,parameter:nodeid,newNodeid,nodeIdSign,blsPubKey,blsProof",
+		Before: netCheck,
+		Action: rotateCandidateKey,
+		Flags:  txFlags(nodeIdFlag, newNodeIdFlag, nodeIdSignFlag, blsPubKeyFlag, blsProofFlag),
+	}
 	GetVerifierListCmd = cli.Command{
 		Name:   "getVerifierList",
 		Usage:  "1100,query the validator queue of the current settlement epoch",
@@ -125,6 +191,38 @@ var (
 		Name:  "nodeid",
 		Usage: "node id",
 	}
+	newNodeIdFlag = cli.StringFlag{
+		Name:  "newNodeid",
+		Usage: "the new node id",
+	}
+	amountTypeFlag = cli.Uint64Flag{
+		Name:  "type",
+		Usage: "the source of the amount, 0: free balance, 1: restricting plan, 2: restricting plan first then free balance",
+	}
+	benefitAddressFlag = cli.StringFlag{
+		Name:  "benefitAddress",
+		Usage: "the account receiving the rewards",
+	}
+	externalIdFlag = cli.StringFlag{
+		Name:  "externalId",
+		Usage: "the external id of the node",
+	}
+	nodeNameFlag = cli.StringFlag{
+		Name:  "nodeName",
+		Usage: "the name of the node",
+	}
+	websiteFlag = cli.StringFlag{
+		Name:  "website",
+		Usage: "the website of the node",
+	}
+	detailsFlag = cli.StringFlag{
+		Name:  "details",
+		Usage: "the description of the node",
+	}
+	rewardPerFlag = cli.Uint64Flag{
+		Name:  "rewardPer",
+		Usage: "the delegate reward ratio in basis points",
+	}
 )
 
 func getVerifierList(c *cli.Context) error {
@@ -207,3 +305,126 @@ func getStakingReward(c *cli.Context) error {
 func getAvgPackTime(c *cli.Context) error {
 	return query(c, 1202)
 }
+
+func createStaking(c *cli.Context) error {
+	benefitAddress, err := parseAddress(c, benefitAddressFlag)
+	if err != nil {
+		return err
+	}
+	nodeid, err := parseNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	amount, err := parseAmount(c)
+	if err != nil {
+		return err
+	}
+	programVersion, programVersionSign, err := parseProgramVersion(c)
+	if err != nil {
+		return err
+	}
+	blsPubKey, blsProof, err := parseBlsKey(c)
+	if err != nil {
+		return err
+	}
+	return transact(c, 1000, uint16(c.Uint64(amountTypeFlag.Name)), benefitAddress, nodeid,
+		c.String(externalIdFlag.Name), c.String(nodeNameFlag.Name), c.String(websiteFlag.Name), c.String(detailsFlag.Name),
+		amount, uint16(c.Uint64(rewardPerFlag.Name)), programVersion, programVersionSign, blsPubKey, blsProof)
+}
+
+func editCandidate(c *cli.Context) error {
+	nodeid, err := parseNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	var benefitAddress *common.Address
+	if c.IsSet(benefitAddressFlag.Name) {
+		addr, err := parseAddress(c, benefitAddressFlag)
+		if err != nil {
+			return err
+		}
+		benefitAddress = &addr
+	}
+	var rewardPer *uint16
+	if c.IsSet(rewardPerFlag.Name) {
+		per := uint16(c.Uint64(rewardPerFlag.Name))
+		rewardPer = &per
+	}
+	optional := func(flag cli.StringFlag) *string {
+		if !c.IsSet(flag.Name) {
+			return nil
+		}
+		s := c.String(flag.Name)
+		return &s
+	}
+	return transact(c, 1001, benefitAddress, nodeid, rewardPer, optional(externalIdFlag), optional(nodeNameFlag),
+		optional(websiteFlag), optional(detailsFlag))
+}
+
+func increaseStaking(c *cli.Context) error {
+	nodeid, err := parseNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	amount, err := parseAmount(c)
+	if err != nil {
+		return err
+	}
+	return transact(c, 1002, nodeid, uint16(c.Uint64(amountTypeFlag.Name)), amount)
+}
+
+func withdrewStaking(c *cli.Context) error {
+	nodeid, err := parseNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	return transact(c, 1003, nodeid)
+}
+
+func delegate(c *cli.Context) error {
+	nodeid, err := parseNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	amount, err := parseAmount(c)
+	if err != nil {
+		return err
+	}
+	return transact(c, 1004, uint16(c.Uint64(amountTypeFlag.Name)), nodeid, amount)
+}
+
+func withdrewDelegation(c *cli.Context) error {
+	nodeid, err := parseNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	amount, err := parseAmount(c)
+	if err != nil {
+		return err
+	}
+	return transact(c, 1005, c.Uint64(stakingBlockNumFlag.Name), nodeid, amount)
+}
+
+func redeemDelegation(c *cli.Context) error {
+	return transact(c, 1006)
+}
+
+func rotateCandidateKey(c *cli.Context) error {
+	nodeid, err := parseNodeID(c, nodeIdFlag)
+	if err != nil {
+		return err
+	}
+	newNodeid, err := parseNodeID(c, newNodeIdFlag)
+	if err != nil {
+		return err
+	}
+	var nodeIdSign common.VersionSign
+	if err := nodeIdSign.UnmarshalText([]byte(c.String(nodeIdSignFlag.Name))); err != nil {
+		return fmt.Errorf("invalid node id sign: %v", err)
+	}
+	blsPubKey, blsProof, err := parseBlsKey(c)
+	if err != nil {
+		return err
+	}
+	return transact(c, 1007, nodeid, newNodeid, nodeIdSign, blsPubKey, blsProof)
+}
@@ -0,0 +1,315 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of PlatON-Go.
+//
+// PlatON-Go is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// PlatON-Go is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with PlatON-Go. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+
+	platon "github.com/hashkey-chain/hashkey-chain"
+	"github.com/hashkey-chain/hashkey-chain/accounts"
+	"github.com/hashkey-chain/hashkey-chain/accounts/abi/bind"
+	"github.com/hashkey-chain/hashkey-chain/accounts/keystore"
+	"github.com/hashkey-chain/hashkey-chain/accounts/usbwallet"
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/common/hexutil"
+	"github.com/hashkey-chain/hashkey-chain/console/prompt"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/crypto/bls"
+	"github.com/hashkey-chain/hashkey-chain/ethclient"
+	pposclient "github.com/hashkey-chain/hashkey-chain/ethclient/ppos"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+	"github.com/hashkey-chain/hashkey-chain/rlp"
+)
+
+// txSigner signs the transactions on behalf of the sender.
+type txSigner struct {
+	from common.Address
+	sign func(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// newTxSigner opens the signer selected by the flags, either the keystore
+// file or the first usb hardware wallet.
+func newTxSigner(c *cli.Context) (*txSigner, error) {
+	switch {
+	case c.Bool(usbFlag.Name):
+		return newUSBSigner(c)
+	case c.String(keystoreFlag.Name) != "":
+		return newKeystoreSigner(c)
+	default:
+		return nil, errors.New("no signer set, use either --keystore or --usb")
+	}
+}
+
+func newKeystoreSigner(c *cli.Context) (*txSigner, error) {
+	keyjson, err := ioutil.ReadFile(c.String(keystoreFlag.Name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the keystore file: %v", err)
+	}
+	var password string
+	if file := c.String(passwordFlag.Name); file != "" {
+		text, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the password file: %v", err)
+		}
+		password = strings.TrimRight(strings.Split(string(text), "\n")[0], "\r")
+	} else if password, err = prompt.Stdin.PromptPassword("Password: "); err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(keyjson, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the key: %v", err)
+	}
+	return &txSigner{
+		from: key.Address,
+		sign: func(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+			return types.SignTx(tx, types.NewEIP155Signer(chainID), key.PrivateKey)
+		},
+	}, nil
+}
+
+func newUSBSigner(c *cli.Context) (*txSigner, error) {
+	path, err := accounts.ParseDerivationPath(c.String(hdPathFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	var wallets []accounts.Wallet
+	if hub, err := usbwallet.NewLedgerHub(); err == nil {
+		wallets = append(wallets, hub.Wallets()...)
+	}
+	if hub, err := usbwallet.NewTrezorHub(); err == nil {
+		wallets = append(wallets, hub.Wallets()...)
+	}
+	if len(wallets) == 0 {
+		return nil, errors.New("no usb wallet found")
+	}
+	wallet := wallets[0]
+	if err := wallet.Open(""); err == usbwallet.ErrTrezorPINNeeded {
+		pin, err := prompt.Stdin.PromptPassword("Please enter the PIN shown on the device: ")
+		if err != nil {
+			return nil, err
+		}
+		if err := wallet.Open(pin); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	account, err := wallet.Derive(path, true)
+	if err != nil {
+		wallet.Close()
+		return nil, err
+	}
+	return &txSigner{
+		from: account.Address,
+		sign: func(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+			return wallet.SignTx(account, tx, chainID)
+		},
+	}, nil
+}
+
+// sendTransaction builds the transaction calling the PPOS function, signs and
+// sends it. The receipt is returned once the transaction is mined, nil if it
+// is not waited for or only printed in the offline mode.
+func sendTransaction(c *cli.Context, funcType uint16, params ...interface{}) (*types.Receipt, error) {
+	data, to := EncodePPOS(funcType, params...)
+	signer, err := newTxSigner(c)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice, ok := new(big.Int).SetString(c.String(gasPriceFlag.Name), 0)
+	if c.IsSet(gasPriceFlag.Name) && !ok {
+		return nil, fmt.Errorf("invalid gas price: %s", c.String(gasPriceFlag.Name))
+	}
+
+	if c.Bool(offlineFlag.Name) {
+		for _, flag := range []string{nonceFlag.Name, gasFlag.Name, gasPriceFlag.Name, chainIdFlag.Name} {
+			if !c.IsSet(flag) {
+				return nil, fmt.Errorf("--%s must be set in the offline mode", flag)
+			}
+		}
+		tx := types.NewTransaction(c.Uint64(nonceFlag.Name), to, new(big.Int), c.Uint64(gasFlag.Name), gasPrice, data)
+		signed, err := signer.sign(tx, new(big.Int).SetUint64(c.Uint64(chainIdFlag.Name)))
+		if err != nil {
+			return nil, err
+		}
+		raw, err := rlp.EncodeToBytes(signed)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Println(hexutil.Encode(raw))
+		return nil, nil
+	}
+
+	url := c.String(rpcUrlFlag.Name)
+	if url == "" {
+		return nil, errors.New("rpc url not set")
+	}
+	client, err := ethclient.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	nonce := c.Uint64(nonceFlag.Name)
+	if !c.IsSet(nonceFlag.Name) {
+		if nonce, err = client.PendingNonceAt(ctx, signer.from); err != nil {
+			return nil, err
+		}
+	}
+	if !c.IsSet(gasPriceFlag.Name) {
+		if gasPrice, err = client.SuggestGasPrice(ctx); err != nil {
+			return nil, err
+		}
+	}
+	chainID := new(big.Int).SetUint64(c.Uint64(chainIdFlag.Name))
+	if !c.IsSet(chainIdFlag.Name) {
+		if chainID, err = client.ChainID(ctx); err != nil {
+			return nil, err
+		}
+	}
+	gas := c.Uint64(gasFlag.Name)
+	if !c.IsSet(gasFlag.Name) {
+		msg := platon.CallMsg{From: signer.from, To: &to, GasPrice: gasPrice, Data: data}
+		if gas, err = client.EstimateGas(ctx, msg); err != nil {
+			return nil, fmt.Errorf("failed to estimate gas: %v", err)
+		}
+	}
+
+	signed, err := signer.sign(types.NewTransaction(nonce, to, new(big.Int), gas, gasPrice, data), chainID)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.SendTransaction(ctx, signed); err != nil {
+		return nil, err
+	}
+	fmt.Println("transaction hash:", signed.Hash().Hex())
+	if c.Bool(noWaitFlag.Name) {
+		return nil, nil
+	}
+	receipt, err := bind.WaitMined(ctx, client, signed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pposclient.TxResult(receipt); err != nil {
+		if be, ok := err.(*common.BizError); ok {
+			return nil, fmt.Errorf("transaction failed in block %d, code: %d, reason: %s", receipt.BlockNumber, be.Code, be.Msg)
+		}
+		return nil, err
+	}
+	fmt.Println("transaction succeeded in block", receipt.BlockNumber)
+	return receipt, nil
+}
+
+func transact(c *cli.Context, funcType uint16, params ...interface{}) error {
+	_, err := sendTransaction(c, funcType, params...)
+	return err
+}
+
+// txFlags returns the signing flags followed by the flags of the function.
+func txFlags(flags ...cli.Flag) []cli.Flag {
+	return append([]cli.Flag{rpcUrlFlag, addressHRPFlag, keystoreFlag, passwordFlag, usbFlag, hdPathFlag,
+		offlineFlag, nonceFlag, gasFlag, gasPriceFlag, chainIdFlag, noWaitFlag}, flags...)
+}
+
+// parseAmount parses the amount in von, either decimal or 0x prefixed hex.
+func parseAmount(c *cli.Context) (*big.Int, error) {
+	s := c.String(amountFlag.Name)
+	if s == "" {
+		return nil, errors.New("amount not set")
+	}
+	amount, ok := new(big.Int).SetString(s, 0)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("invalid amount: %s", s)
+	}
+	return amount, nil
+}
+
+func parseNodeID(c *cli.Context, flag cli.StringFlag) (discover.NodeID, error) {
+	s := c.String(flag.Name)
+	if s == "" {
+		return discover.NodeID{}, fmt.Errorf("--%s not set", flag.Name)
+	}
+	return discover.HexID(s)
+}
+
+func parseAddress(c *cli.Context, flag cli.StringFlag) (common.Address, error) {
+	s := c.String(flag.Name)
+	if s == "" {
+		return common.Address{}, fmt.Errorf("--%s not set", flag.Name)
+	}
+	return common.Bech32ToAddress(s)
+}
+
+// parseProgramVersion returns the program version and its signature from the
+// flags, or from the node if they are not set.
+func parseProgramVersion(c *cli.Context) (uint32, common.VersionSign, error) {
+	var sign common.VersionSign
+	if c.IsSet(programVersionFlag.Name) {
+		if err := sign.UnmarshalText([]byte(c.String(programVersionSignFlag.Name))); err != nil {
+			return 0, sign, fmt.Errorf("invalid program version sign: %v", err)
+		}
+		return uint32(c.Uint64(programVersionFlag.Name)), sign, nil
+	}
+	client, err := ethclient.Dial(c.String(rpcUrlFlag.Name))
+	if err != nil {
+		return 0, sign, err
+	}
+	defer client.Close()
+	version, err := client.GetProgramVersion(context.Background())
+	if err != nil {
+		return 0, sign, fmt.Errorf("failed to get the program version of the node: %v", err)
+	}
+	if err := sign.UnmarshalText([]byte(version.Sign)); err != nil {
+		return 0, sign, err
+	}
+	return version.Version, sign, nil
+}
+
+// parseBlsKey returns the BLS public key and its proof from the flags, the
+// proof is generated by the node if it is not set.
+func parseBlsKey(c *cli.Context) (bls.PublicKeyHex, bls.SchnorrProofHex, error) {
+	var (
+		pubKey bls.PublicKeyHex
+		proof  bls.SchnorrProofHex
+	)
+	if err := pubKey.UnmarshalText([]byte(c.String(blsPubKeyFlag.Name))); err != nil {
+		return pubKey, proof, fmt.Errorf("invalid bls public key: %v", err)
+	}
+	text := c.String(blsProofFlag.Name)
+	if text == "" {
+		client, err := ethclient.Dial(c.String(rpcUrlFlag.Name))
+		if err != nil {
+			return pubKey, proof, err
+		}
+		defer client.Close()
+		if text, err = client.GetSchnorrNIZKProve(context.Background()); err != nil {
+			return pubKey, proof, fmt.Errorf("failed to get the bls proof of the node: %v", err)
+		}
+	}
+	if err := proof.UnmarshalText([]byte(text)); err != nil {
+		return pubKey, proof, fmt.Errorf("invalid bls proof: %v", err)
+	}
+	return pubKey, proof, nil
+}
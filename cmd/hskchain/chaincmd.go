@@ -37,12 +37,15 @@ import (
 
 	"github.com/hashkey-chain/hashkey-chain/cmd/utils"
 	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/common/vm"
 	"github.com/hashkey-chain/hashkey-chain/core"
 	"github.com/hashkey-chain/hashkey-chain/core/state"
 	"github.com/hashkey-chain/hashkey-chain/core/types"
 	"github.com/hashkey-chain/hashkey-chain/eth/downloader"
 	"github.com/hashkey-chain/hashkey-chain/event"
 	"github.com/hashkey-chain/hashkey-chain/log"
+	"github.com/hashkey-chain/hashkey-chain/node"
+	"github.com/hashkey-chain/hashkey-chain/x/staking"
 )
 
 var (
@@ -113,6 +116,7 @@ The export-preimages command export hash preimages to an RLP encoded stream`,
 			//	utils.SyncModeFlag,
 			utils.TestnetFlag,
 			utils.TxLookupLimitFlag,
+			utils.AddressIndexFlag,
 		},
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
@@ -158,6 +162,51 @@ Use "ethereum dump 0" to dump the genesis block.`,
 		},
 		Category: "BLOCKCHAIN COMMANDS",
 	}
+	snapshotdbDryRunFlag = cli.BoolFlag{
+		Name:  "dryrun",
+		Usage: "Report the repair without changing the database",
+	}
+	snapshotdbCommand = cli.Command{
+		Name:     "snapshotdb",
+		Usage:    "Verify and repair the PPOS snapshot database",
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The snapshotdb commands check the journals of the blocks not yet written to the
+PPOS base database against the chain, the node must be stopped.`,
+		Subcommands: []cli.Command{
+			{
+				Action:    utils.MigrateFlags(verifySnapshotDB),
+				Name:      "verify",
+				Usage:     "Verify the snapshot database against the chain",
+				ArgsUsage: " ",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+				},
+				Description: `
+The verify command walks the journals from the base block up to the highest
+committed block. Each journal must follow the previous one, belong to the
+canonical chain and carry the kv hash committed in the state of the block.
+It reports the first diverging block.`,
+			},
+			{
+				Action:    utils.MigrateFlags(repairSnapshotDB),
+				Name:      "repair",
+				Usage:     "Repair the snapshot database from the last good block",
+				ArgsUsage: " ",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+					snapshotdbDryRunFlag,
+				},
+				Description: `
+The repair command verifies the snapshot database, drops the journals from the
+first diverging block onwards and replays the blocks of the chain above the
+last good block. A divergence at or below the base block can't be repaired,
+the node has to resync.`,
+			},
+		},
+	}
 )
 
 // initGenesis will initialise the given JSON format genesis file and writes it as
@@ -448,6 +497,95 @@ func inspect(ctx *cli.Context) error {
 	return rawdb.InspectDatabase(chainDb)
 }
 
+func verifySnapshotDB(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	result, _ := checkSnapshotDB(ctx, stack)
+	if !result.Consistent() {
+		return fmt.Errorf("snapshotdb diverged at block %d", result.Diverged)
+	}
+	return nil
+}
+
+func repairSnapshotDB(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	result, replayable := checkSnapshotDB(ctx, stack)
+	stack.Close()
+
+	if result.Consistent() {
+		fmt.Println("Nothing to repair")
+		return nil
+	}
+	if result.Diverged <= result.Base {
+		return fmt.Errorf("%w at block %d, the node has to resync", snapshotdb.ErrBaseDiverged, result.Diverged)
+	}
+	if !replayable {
+		return fmt.Errorf("no state of the last good block %d to replay the chain from, the node has to resync", result.LastGood)
+	}
+	if ctx.Bool(snapshotdbDryRunFlag.Name) {
+		fmt.Printf("Dry run: drop the journals from block %d and replay the chain from block %d\n", result.Diverged, result.LastGood+1)
+		return nil
+	}
+	if err := snapshotdb.Repair(stack.ResolvePath(snapshotdb.DBPath), result); err != nil {
+		utils.Fatalf("Failed to repair snapshotdb: %v", err)
+	}
+	start := time.Now()
+	fmt.Printf("Replaying the chain from block %d\n", result.LastGood+1)
+
+	// The service replays the blocks above the snapshotdb highest block
+	// while being created.
+	stack, _ = makeFullNode(ctx)
+	highest := snapshotdb.Instance().GetCurrent().GetHighest(false)
+	snapshotdb.Close()
+	stack.Close()
+	fmt.Printf("Repair done in %v, highest block %d (%x)\n", time.Since(start), highest.Num, highest.Hash)
+	return nil
+}
+
+// checkSnapshotDB verifies the snapshotdb of the node against its chain and
+// prints the outcome, it also reports whether the state of the last good block
+// is available to replay the chain from.
+func checkSnapshotDB(ctx *cli.Context, stack *node.Node) (*snapshotdb.VerifyResult, bool) {
+	chain, chainDb := utils.MakeChain(ctx, stack, true)
+	defer chainDb.Close()
+
+	pposHash := func(header *types.Header) (common.Hash, bool) {
+		statedb, err := chain.StateAt(header.Root)
+		if err != nil {
+			return common.Hash{}, false
+		}
+		hash := statedb.GetState(vm.StakingContractAddr, staking.GetPPOSHASHKey())
+		if len(hash) == 0 || statedb.Error() != nil {
+			return common.Hash{}, false
+		}
+		return common.BytesToHash(hash), true
+	}
+	var (
+		start  = time.Now()
+		logged = time.Now()
+	)
+	progress := func(number uint64) {
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Verifying snapshotdb", "number", number, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	result, err := snapshotdb.Verify(stack.ResolvePath(snapshotdb.DBPath), chain, pposHash, progress)
+	if err != nil {
+		utils.Fatalf("Failed to verify snapshotdb: %v", err)
+	}
+	fmt.Printf("Base block %d, highest block %d\n", result.Base, result.Highest)
+	fmt.Printf("Checked %d journals in %v, %d without state to compare the kv hash\n", result.Checked, time.Since(start), result.Unverified)
+	if result.Consistent() {
+		fmt.Println("Snapshotdb is consistent with the chain")
+	} else {
+		fmt.Printf("First divergence at block %d: %s\n", result.Diverged, result.Reason)
+		fmt.Printf("Last good block %d (%x)\n", result.LastGood, result.LastGoodHash)
+	}
+	return result, chain.HasBlockAndState(result.LastGoodHash, result.LastGood)
+}
+
 // hashish returns true for strings that look like hashes.
 func hashish(x string) bool {
 	_, err := strconv.Atoi(x)
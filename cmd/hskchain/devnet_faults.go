@@ -0,0 +1,44 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+
+// +build devnet
+
+package main
+
+import (
+	"errors"
+
+	"github.com/hashkey-chain/hashkey-chain/consensus"
+	"github.com/hashkey-chain/hashkey-chain/consensus/cbft"
+)
+
+// devnetFaultsSupported reports whether the binary can inject devnet faults.
+const devnetFaultsSupported = true
+
+// injectDevnetFaults installs the faults of the validator into its cbft engine.
+func injectDevnetFaults(engine consensus.Engine, faults devnetFaults) error {
+	cbftEngine, ok := engine.(*cbft.Cbft)
+	if !ok {
+		return errors.New("faults can only be injected into the cbft engine")
+	}
+	cbftEngine.InjectFaults(&cbft.Faults{
+		Offline:    faults.offline,
+		Delay:      faults.delay,
+		Equivocate: faults.equivocate,
+	})
+	return nil
+}
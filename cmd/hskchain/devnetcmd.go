@@ -0,0 +1,589 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/hashkey-chain/hashkey-chain/cmd/utils"
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core"
+	"github.com/hashkey-chain/hashkey-chain/crypto"
+	"github.com/hashkey-chain/hashkey-chain/crypto/bls"
+	"github.com/hashkey-chain/hashkey-chain/node"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+	"github.com/hashkey-chain/hashkey-chain/params"
+	"github.com/hashkey-chain/hashkey-chain/x/xcom"
+)
+
+const (
+	devnetAmount        = 10                                     // Blocks produced by a proposer per view
+	devnetMaxValidators = 25                                     // Upper bound of the devnet validators
+	devnetGenesis       = "genesis.json"                         // Path within the devnet directory to the genesis file
+	devnetFaucetKey     = "faucet.key"                           // Path within the devnet directory to the faucet private key
+	devnetLog           = "hskchain.log"                         // Path within the validator directory to its log file
+	devnetAPI           = "hskchain,net,web3,admin,debug,txpool" // RPC modules exposed by the devnet validators
+
+	// devnetGenesisVersion is the genesis version of the devnet, the latest fork so
+	// that every versioned feature is active from the genesis block.
+	devnetGenesisVersion = params.FORKVERSION_1_5_0
+)
+
+var (
+	devnetValidatorsFlag = cli.IntFlag{
+		Name:  "devnet.validators",
+		Usage: "Number of validators of the devnet",
+		Value: 4,
+	}
+	devnetSeedFlag = cli.Uint64Flag{
+		Name:  "devnet.seed",
+		Usage: "Seed of the deterministic node keys, BLS keys and faucet key",
+		Value: 1,
+	}
+	devnetChainIDFlag = cli.Uint64Flag{
+		Name:  "devnet.chainid",
+		Usage: "Chain id of the devnet",
+		Value: 2021,
+	}
+	devnetPeriodFlag = cli.Uint64Flag{
+		Name:  "devnet.period",
+		Usage: "CBFT period of a proposer in milliseconds, during which it proposes 10 blocks",
+		Value: 10000,
+	}
+	devnetP2PPortFlag = cli.IntFlag{
+		Name:  "devnet.port",
+		Usage: "Network listening port of the first validator, the others use the following ports",
+		Value: 17789,
+	}
+	devnetHTTPPortFlag = cli.IntFlag{
+		Name:  "devnet.http.port",
+		Usage: "HTTP-RPC server listening port of the first validator, the others use the following ports",
+		Value: node.DefaultHTTPPort,
+	}
+	devnetWSPortFlag = cli.IntFlag{
+		Name:  "devnet.ws.port",
+		Usage: "WS-RPC server listening port of the first validator, the others use the following ports",
+		Value: node.DefaultHTTPPort + 100,
+	}
+	devnetOfflineFlag = cli.StringFlag{
+		Name:  "devnet.offline",
+		Usage: "Comma separated indexes of the validators withholding all their consensus messages (devnet tag builds only)",
+	}
+	devnetSlowFlag = cli.StringFlag{
+		Name:  "devnet.slow",
+		Usage: "Comma separated indexes of the validators delaying all their consensus messages (devnet tag builds only)",
+	}
+	devnetDelayFlag = cli.DurationFlag{
+		Name:  "devnet.delay",
+		Usage: "Delay of the consensus messages of the slow validators",
+		Value: 3 * time.Second,
+	}
+	devnetEquivocateFlag = cli.StringFlag{
+		Name:  "devnet.equivocate",
+		Usage: "Comma separated indexes of the validators sending a conflicting vote next to each vote (devnet tag builds only)",
+	}
+
+	// Flags of a single devnet validator, set by the devnet command.
+	devnetNodeOfflineFlag = cli.BoolFlag{
+		Name:  "devnet.node.offline",
+		Usage: "Withhold all the consensus messages of the validator",
+	}
+	devnetNodeDelayFlag = cli.DurationFlag{
+		Name:  "devnet.node.delay",
+		Usage: "Delay all the consensus messages of the validator",
+	}
+	devnetNodeEquivocateFlag = cli.BoolFlag{
+		Name:  "devnet.node.equivocate",
+		Usage: "Send a conflicting vote next to each vote of the validator",
+	}
+
+	devnetCommand = cli.Command{
+		Action: utils.MigrateFlags(devnet),
+		Name:   "devnet",
+		Usage:  "Run a deterministic multi-validator CBFT network on the local host, one child process per validator",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			devnetValidatorsFlag,
+			devnetSeedFlag,
+			devnetChainIDFlag,
+			devnetPeriodFlag,
+			devnetP2PPortFlag,
+			devnetHTTPPortFlag,
+			devnetWSPortFlag,
+			devnetOfflineFlag,
+			devnetSlowFlag,
+			devnetDelayFlag,
+			devnetEquivocateFlag,
+		},
+		Category: "DEVELOPER COMMANDS",
+		Description: `
+The devnet command generates the node keys and BLS keys of N validators from a
+seed, writes a PPOS genesis staking all of them through cbft.initialNodes and
+runs every validator with its own data directory, network, HTTP and WS ports.
+The same seed and validator count always produce the same network.
+
+The validators are not run within the devnet process. PPOS keeps its state in
+process wide singletons (snapshotdb.Instance, the plugin instances and the xcom
+economic model), which validators sharing one process would corrupt. Instead
+every validator runs in a child process of the same executable, started by the
+hidden devnet-node command, and logs to the hskchain.log file of its directory.
+Interrupting the devnet command stops all the validators.
+
+Validators can be made faulty by their index with --devnet.offline (all
+consensus messages withheld), --devnet.slow (all consensus messages delayed by
+--devnet.delay) or --devnet.equivocate (a conflicting vote is signed next to
+each vote, which is recorded as duplicate sign evidence by the other validators).
+The faults are injected through the cbft test hooks, which are only built into
+binaries built with the devnet tag (go build -tags devnet ./cmd/hskchain).`,
+	}
+
+	devnetNodeCommand = cli.Command{
+		Action: utils.MigrateFlags(devnetNode),
+		Name:   "devnet-node",
+		Usage:  "Run a single validator of the devnet",
+		Flags: []cli.Flag{
+			devnetNodeOfflineFlag,
+			devnetNodeDelayFlag,
+			devnetNodeEquivocateFlag,
+		},
+		Hidden:   true,
+		Category: "DEVELOPER COMMANDS",
+	}
+)
+
+// devnetConfig is the configuration of the devnet given on the command line.
+type devnetConfig struct {
+	validators int
+	seed       uint64
+	chainID    uint64
+	period     uint64
+	p2pPort    int
+	httpPort   int
+	wsPort     int
+	offline    map[int]bool
+	slow       map[int]bool
+	delay      time.Duration
+	equivocate map[int]bool
+}
+
+// devnetFaults describes the deterministic faults of a devnet validator.
+type devnetFaults struct {
+	offline    bool          // Withhold every outbound consensus message
+	delay      time.Duration // Delay every outbound consensus message
+	equivocate bool          // Send a conflicting prepareVote next to each prepareVote
+}
+
+// faulty checks whether any fault is set.
+func (f devnetFaults) faulty() bool {
+	return f.offline || f.delay > 0 || f.equivocate
+}
+
+// String implements fmt.Stringer.
+func (f devnetFaults) String() string {
+	var faults []string
+	if f.offline {
+		faults = append(faults, "offline")
+	}
+	if f.delay > 0 {
+		faults = append(faults, "slow "+f.delay.String())
+	}
+	if f.equivocate {
+		faults = append(faults, "equivocate")
+	}
+	if len(faults) == 0 {
+		return "none"
+	}
+	return strings.Join(faults, ", ")
+}
+
+// devnetValidator is a validator of the devnet.
+type devnetValidator struct {
+	index    int
+	dir      string
+	key      *ecdsa.PrivateKey
+	blsKey   *bls.SecretKey
+	node     *discover.Node
+	httpPort int
+	wsPort   int
+	faults   devnetFaults
+}
+
+// devnetKey derives a deterministic 32 bytes key from the seed and the given labels.
+func devnetKey(seed uint64, labels ...interface{}) []byte {
+	parts := []string{"hskchain-devnet", strconv.FormatUint(seed, 10)}
+	for _, label := range labels {
+		parts = append(parts, fmt.Sprint(label))
+	}
+	return crypto.Keccak256([]byte(strings.Join(parts, "/")))
+}
+
+// parseDevnetIndexes parses a comma separated list of validator indexes.
+func parseDevnetIndexes(ctx *cli.Context, flag cli.StringFlag, validators int) map[int]bool {
+	indexes := make(map[int]bool)
+	value := ctx.String(flag.Name)
+	if value == "" {
+		return indexes
+	}
+	for _, item := range strings.Split(value, ",") {
+		index, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil || index < 0 || index >= validators {
+			utils.Fatalf("Option %q: invalid validator index %q", flag.Name, item)
+		}
+		indexes[index] = true
+	}
+	return indexes
+}
+
+// makeDevnetConfig reads the configuration of the devnet from the command line flags.
+func makeDevnetConfig(ctx *cli.Context) *devnetConfig {
+	count := ctx.Int(devnetValidatorsFlag.Name)
+	if count < 1 || count > devnetMaxValidators {
+		utils.Fatalf("Option %q: the validator count must be [1, %d]", devnetValidatorsFlag.Name, devnetMaxValidators)
+	}
+	config := &devnetConfig{
+		validators: count,
+		seed:       ctx.Uint64(devnetSeedFlag.Name),
+		chainID:    ctx.Uint64(devnetChainIDFlag.Name),
+		period:     ctx.Uint64(devnetPeriodFlag.Name),
+		p2pPort:    ctx.Int(devnetP2PPortFlag.Name),
+		httpPort:   ctx.Int(devnetHTTPPortFlag.Name),
+		wsPort:     ctx.Int(devnetWSPortFlag.Name),
+		offline:    parseDevnetIndexes(ctx, devnetOfflineFlag, count),
+		slow:       parseDevnetIndexes(ctx, devnetSlowFlag, count),
+		delay:      ctx.Duration(devnetDelayFlag.Name),
+		equivocate: parseDevnetIndexes(ctx, devnetEquivocateFlag, count),
+	}
+	// The economic model requires at least one second per block.
+	if config.period < devnetAmount*1000 {
+		utils.Fatalf("Option %q: the period must be at least %d milliseconds", devnetPeriodFlag.Name, devnetAmount*1000)
+	}
+	if !devnetFaultsSupported && len(config.offline)+len(config.slow)+len(config.equivocate) > 0 {
+		utils.Fatalf("Faults can only be injected by a binary built with the devnet tag")
+	}
+	return config
+}
+
+// makeDevnetValidators derives the validators of the devnet from its configuration.
+func makeDevnetValidators(config *devnetConfig, dir string) ([]*devnetValidator, error) {
+	validators := make([]*devnetValidator, config.validators)
+	for i := 0; i < config.validators; i++ {
+		key, err := crypto.ToECDSA(devnetKey(config.seed, "node", i))
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive the node key of validator %d: %v", i, err)
+		}
+		var blsKey bls.SecretKey
+		if err := blsKey.SetLittleEndian(devnetKey(config.seed, "bls", i)); err != nil {
+			return nil, fmt.Errorf("failed to derive the BLS key of validator %d: %v", i, err)
+		}
+		port := config.p2pPort + i
+		validator := &devnetValidator{
+			index:    i,
+			dir:      filepath.Join(dir, fmt.Sprintf("node-%d", i)),
+			key:      key,
+			blsKey:   &blsKey,
+			node:     discover.NewNode(discover.PubkeyID(&key.PublicKey), net.ParseIP("127.0.0.1"), uint16(port), uint16(port)),
+			httpPort: config.httpPort + i,
+			wsPort:   config.wsPort + i,
+			faults: devnetFaults{
+				offline:    config.offline[i],
+				equivocate: config.equivocate[i],
+			},
+		}
+		if config.slow[i] {
+			validator.faults.delay = config.delay
+		}
+		validators[i] = validator
+	}
+	return validators, nil
+}
+
+// makeDevnetGenesis creates the PPOS genesis of the devnet and its extended economic
+// model, staking every validator through cbft.initialNodes and funding the faucet account.
+func makeDevnetGenesis(config *devnetConfig, validators []*devnetValidator, faucet common.Address) (*core.Genesis, *xcom.EconomicModelExtend) {
+	var (
+		chainID = new(big.Int).SetUint64(config.chainID)
+		period  = config.period
+	)
+
+	initialNodes := make([]params.CbftNode, len(validators))
+	for i, validator := range validators {
+		initialNodes[i] = params.CbftNode{
+			Node:      *validator.node,
+			BlsPubKey: *validator.blsKey.GetPublicKey(),
+		}
+	}
+
+	// Size the epoch and the additional issuance cycle after the validator count so
+	// that the economic model checks still pass with many validators.
+	model := *xcom.GetEc(xcom.DefaultUnitTestNet)
+	consensusVals := uint64(len(validators))
+	if consensusVals < xcom.FloorMaxConsensusVals {
+		consensusVals = xcom.FloorMaxConsensusVals
+	}
+	model.Common.MaxConsensusVals = consensusVals
+	roundMinutes := (consensusVals*period/1000 + 59) / 60
+	if epochMinutes := 5 * roundMinutes; epochMinutes > model.Common.MaxEpochMinutes {
+		model.Common.MaxEpochMinutes = epochMinutes
+	}
+	if cycleMinutes := 5 * model.Common.MaxEpochMinutes; cycleMinutes > model.Common.AdditionalCycleTime {
+		model.Common.AdditionalCycleTime = cycleMinutes
+	}
+	// The extended model is checked against the model by the versions it was added in,
+	// the admission is left open so that anyone can stake on the devnet.
+	modelExtend := *xcom.GetEce()
+	modelExtend.Staking.UnDelegateFreezeDuration = model.Staking.UnStakeFreezeDuration
+	modelExtend.Staking.AdmissionAllowlist = false
+
+	rewardPool, _ := new(big.Int).SetString("200000000000000000000000000", 10)
+	faucetBalance, _ := new(big.Int).SetString("1000000000000000000000000000", 10)
+
+	return &core.Genesis{
+		Config: &params.ChainConfig{
+			ChainID:     chainID,
+			PIP7ChainID: chainID,
+			AddressHRP:  common.DefaultAddressHRP,
+			EmptyBlock:  "on",
+			EIP155Block: big.NewInt(1),
+			Cbft: &params.CbftConfig{
+				Period:        period,
+				Amount:        devnetAmount,
+				InitialNodes:  initialNodes,
+				ValidatorMode: common.PPOS_VALIDATOR_MODE,
+			},
+			GenesisVersion: devnetGenesisVersion,
+		},
+		GasLimit: params.GenesisGasLimit,
+		Alloc: core.GenesisAlloc{
+			vm.RewardManagerPoolAddr: {Balance: rewardPool},
+			faucet:                   {Balance: faucetBalance},
+		},
+		EconomicModel: &model,
+	}, &modelExtend
+}
+
+// writeDevnetValidator writes the keys and the static nodes of the validator into
+// its directory.
+func writeDevnetValidator(validator *devnetValidator, validators []*devnetValidator) error {
+	instanceDir := filepath.Join(validator.dir, clientIdentifier)
+	if err := os.MkdirAll(instanceDir, 0700); err != nil {
+		return err
+	}
+	if err := crypto.SaveECDSA(filepath.Join(instanceDir, "nodekey"), validator.key); err != nil {
+		return err
+	}
+	if err := bls.SaveBLS(filepath.Join(instanceDir, "blskey"), validator.blsKey); err != nil {
+		return err
+	}
+	var static []string
+	for _, peer := range validators {
+		if peer != validator {
+			static = append(static, peer.node.String())
+		}
+	}
+	blob, err := json.MarshalIndent(static, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(instanceDir, "static-nodes.json"), blob, 0600)
+}
+
+// runDevnetInit initializes the chain of the validator with the devnet genesis.
+func runDevnetInit(executable string, validator *devnetValidator, genesis string) error {
+	cmd := exec.Command(executable, "--datadir", validator.dir, "--bootnodes", "", "init", genesis)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, out)
+	}
+	return nil
+}
+
+// startDevnetValidator starts the validator in a child process logging into its
+// directory, the PPOS singletons can't be shared by the validators of one process.
+// The index of the validator is sent to exited once the child exits.
+func startDevnetValidator(ctx *cli.Context, executable string, validator *devnetValidator, exited chan<- int) (*exec.Cmd, error) {
+	args := []string{
+		"--datadir", validator.dir,
+		"--port", strconv.Itoa(int(validator.node.TCP)),
+		"--nodiscover",
+		"--bootnodes", "",
+		"--http", "--http.addr", "127.0.0.1", "--http.port", strconv.Itoa(validator.httpPort), "--http.api", devnetAPI,
+		"--ws", "--ws.addr", "127.0.0.1", "--ws.port", strconv.Itoa(validator.wsPort), "--ws.api", devnetAPI,
+	}
+	if ctx.GlobalIsSet("verbosity") {
+		args = append(args, "--verbosity", strconv.Itoa(ctx.GlobalInt("verbosity")))
+	}
+	args = append(args, devnetNodeCommand.Name)
+	if validator.faults.offline {
+		args = append(args, "--"+devnetNodeOfflineFlag.Name)
+	}
+	if validator.faults.delay > 0 {
+		args = append(args, "--"+devnetNodeDelayFlag.Name, validator.faults.delay.String())
+	}
+	if validator.faults.equivocate {
+		args = append(args, "--"+devnetNodeEquivocateFlag.Name)
+	}
+
+	logfile, err := os.OpenFile(filepath.Join(validator.dir, devnetLog), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(executable, args...)
+	cmd.Stdout = logfile
+	cmd.Stderr = logfile
+	if err := cmd.Start(); err != nil {
+		logfile.Close()
+		return nil, err
+	}
+	go func() {
+		cmd.Wait()
+		logfile.Close()
+		exited <- validator.index
+	}()
+	return cmd, nil
+}
+
+// devnet generates the devnet and runs all its validators until interrupted.
+func devnet(ctx *cli.Context) error {
+	if args := ctx.Args(); len(args) > 0 {
+		return fmt.Errorf("invalid command: %q", args[0])
+	}
+	dir := filepath.Join(node.DefaultDataDir(), "devnet")
+	if ctx.GlobalIsSet(utils.DataDirFlag.Name) {
+		dir = ctx.GlobalString(utils.DataDirFlag.Name)
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		utils.Fatalf("Failed to locate the hskchain executable: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		utils.Fatalf("Failed to create the devnet directory: %v", err)
+	}
+
+	faucetKey, err := crypto.ToECDSA(devnetKey(ctx.Uint64(devnetSeedFlag.Name), "faucet"))
+	if err != nil {
+		utils.Fatalf("Failed to derive the faucet key: %v", err)
+	}
+	faucet := crypto.PubkeyToAddress(faucetKey.PublicKey)
+	if err := crypto.SaveECDSA(filepath.Join(dir, devnetFaucetKey), faucetKey); err != nil {
+		utils.Fatalf("Failed to write the faucet key: %v", err)
+	}
+
+	config := makeDevnetConfig(ctx)
+	validators, err := makeDevnetValidators(config, dir)
+	if err != nil {
+		utils.Fatalf("Failed to create the devnet validators: %v", err)
+	}
+	genesis, modelExtend := makeDevnetGenesis(config, validators, faucet)
+	blob, err := marshalExportedGenesis(genesis, modelExtend)
+	if err != nil {
+		utils.Fatalf("Failed to encode the devnet genesis: %v", err)
+	}
+	genesisPath := filepath.Join(dir, devnetGenesis)
+	if err := ioutil.WriteFile(genesisPath, blob, 0600); err != nil {
+		utils.Fatalf("Failed to write the devnet genesis: %v", err)
+	}
+
+	// Initialize the validators which were not created by a previous run.
+	for _, validator := range validators {
+		if _, err := os.Stat(filepath.Join(validator.dir, clientIdentifier, "chaindata")); err == nil {
+			continue
+		}
+		if err := writeDevnetValidator(validator, validators); err != nil {
+			utils.Fatalf("Failed to write validator %d: %v", validator.index, err)
+		}
+		if err := runDevnetInit(executable, validator, genesisPath); err != nil {
+			utils.Fatalf("Failed to initialize validator %d: %v", validator.index, err)
+		}
+	}
+
+	cmds := make([]*exec.Cmd, 0, len(validators))
+	stop := func() {
+		for _, cmd := range cmds {
+			cmd.Process.Signal(os.Interrupt)
+		}
+	}
+	exited := make(chan int, len(validators))
+	for _, validator := range validators {
+		cmd, err := startDevnetValidator(ctx, executable, validator, exited)
+		if err != nil {
+			stop()
+			utils.Fatalf("Failed to start validator %d: %v", validator.index, err)
+		}
+		cmds = append(cmds, cmd)
+	}
+
+	fmt.Printf("Devnet of %d validators in %s\n", len(validators), dir)
+	fmt.Printf("Faucet account: %s (private key in %s)\n\n", faucet.String(), filepath.Join(dir, devnetFaucetKey))
+	for _, validator := range validators {
+		fmt.Printf("node-%d  %s\n", validator.index, validator.node.String())
+		fmt.Printf("        http://127.0.0.1:%d  ws://127.0.0.1:%d  fault: %s\n", validator.httpPort, validator.wsPort, validator.faults)
+	}
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigc)
+
+	running := len(cmds)
+	select {
+	case <-sigc:
+		fmt.Println("Interrupted, stopping the devnet")
+	case index := <-exited:
+		fmt.Printf("Validator %d exited, see %s; stopping the devnet\n", index, filepath.Join(validators[index].dir, devnetLog))
+		running--
+	}
+	stop()
+	for ; running > 0; running-- {
+		<-exited
+	}
+	return nil
+}
+
+// devnetNode runs a single validator of the devnet, injecting its faults into the
+// consensus engine.
+func devnetNode(ctx *cli.Context) error {
+	stack, backend := makeFullNode(ctx)
+	defer stack.Close()
+
+	faults := devnetFaults{
+		offline:    ctx.Bool(devnetNodeOfflineFlag.Name),
+		delay:      ctx.Duration(devnetNodeDelayFlag.Name),
+		equivocate: ctx.Bool(devnetNodeEquivocateFlag.Name),
+	}
+	if faults.faulty() {
+		if err := injectDevnetFaults(backend.Engine(), faults); err != nil {
+			utils.Fatalf("Failed to inject the faults: %v", err)
+		}
+	}
+	startNode(ctx, stack, backend)
+	stack.Wait()
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashkey-chain/hashkey-chain/core"
+	"github.com/hashkey-chain/hashkey-chain/crypto"
+	"github.com/hashkey-chain/hashkey-chain/params"
+	"github.com/hashkey-chain/hashkey-chain/x/xcom"
+)
+
+func testDevnetConfig(seed uint64) *devnetConfig {
+	return &devnetConfig{
+		validators: 4,
+		seed:       seed,
+		chainID:    2021,
+		period:     10000,
+		p2pPort:    17789,
+		httpPort:   6789,
+		wsPort:     6889,
+		offline:    map[int]bool{1: true},
+		slow:       map[int]bool{2: true},
+		delay:      3 * time.Second,
+		equivocate: map[int]bool{2: true},
+	}
+}
+
+// Tests that the devnet validators are derived deterministically from the seed.
+func TestDevnetValidators(t *testing.T) {
+	a, err := makeDevnetValidators(testDevnetConfig(1), "devnet")
+	if err != nil {
+		t.Fatalf("failed to make validators: %v", err)
+	}
+	b, _ := makeDevnetValidators(testDevnetConfig(1), "devnet")
+	c, _ := makeDevnetValidators(testDevnetConfig(2), "devnet")
+	for i := range a {
+		if a[i].node.ID != b[i].node.ID || a[i].blsKey.GetPublicKey().GetHexString() != b[i].blsKey.GetPublicKey().GetHexString() {
+			t.Errorf("validator %d: keys differ for the same seed", i)
+		}
+		if a[i].node.ID == c[i].node.ID {
+			t.Errorf("validator %d: node keys equal for different seeds", i)
+		}
+		if have, want := int(a[i].node.TCP), 17789+i; have != want {
+			t.Errorf("validator %d: port mismatch: have %d, want %d", i, have, want)
+		}
+	}
+	if want := (devnetFaults{offline: true}); a[1].faults != want {
+		t.Errorf("validator 1: faults mismatch: have %v, want %v", a[1].faults, want)
+	}
+	if want := (devnetFaults{delay: 3 * time.Second, equivocate: true}); a[2].faults != want {
+		t.Errorf("validator 2: faults mismatch: have %v, want %v", a[2].faults, want)
+	}
+	if a[0].faults.faulty() {
+		t.Errorf("validator 0: unexpected faults %v", a[0].faults)
+	}
+}
+
+// Tests that the devnet genesis passes the genesis and economic model checks
+// with every versioned feature active.
+func TestDevnetGenesis(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hskchain-devnet-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := testDevnetConfig(1)
+	config.validators = 7
+	validators, _ := makeDevnetValidators(config, dir)
+	faucetKey, _ := crypto.ToECDSA(devnetKey(config.seed, "faucet"))
+	genesis, modelExtend := makeDevnetGenesis(config, validators, crypto.PubkeyToAddress(faucetKey.PublicKey))
+
+	// Decode the genesis without the defaults of the unit test network, as a node does
+	ec, ece := xcom.GetEc(xcom.DefaultUnitTestNet), *xcom.GetEce()
+	defer func() {
+		xcom.ResetEconomicDefaultConfig(ec)
+		xcom.ResetEconomicExtendConfig(&ece)
+	}()
+	xcom.ResetEconomicExtendConfig(&xcom.EconomicModelExtend{})
+	blob, err := marshalExportedGenesis(genesis, modelExtend)
+	if err != nil {
+		t.Fatalf("failed to encode the genesis: %v", err)
+	}
+	path := filepath.Join(dir, devnetGenesis)
+	if err := ioutil.WriteFile(path, blob, 0600); err != nil {
+		t.Fatal(err)
+	}
+	var decoded core.Genesis
+	if err := decoded.InitGenesisAndSetEconomicConfig(path); err != nil {
+		t.Fatalf("invalid devnet genesis: %v", err)
+	}
+	if decoded.Config.GenesisVersion != params.FORKVERSION_1_5_0 {
+		t.Errorf("genesis version mismatch: have %s, want %s", params.FormatVersion(decoded.Config.GenesisVersion), params.FormatVersion(params.FORKVERSION_1_5_0))
+	}
+	if have, want := len(decoded.Config.Cbft.InitialNodes), len(validators); have != want {
+		t.Errorf("initial nodes mismatch: have %d, want %d", have, want)
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/hashkey-chain/hashkey-chain/cmd/utils"
+	"github.com/hashkey-chain/hashkey-chain/core"
+	"github.com/hashkey-chain/hashkey-chain/core/snapshotdb"
+	"github.com/hashkey-chain/hashkey-chain/crypto"
+	"github.com/hashkey-chain/hashkey-chain/crypto/bls"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+	"github.com/hashkey-chain/hashkey-chain/params"
+	"github.com/hashkey-chain/hashkey-chain/x/xcom"
+)
+
+var (
+	exportGenesisKeysFlag = cli.StringFlag{
+		Name:  "exportgenesis.keys",
+		Usage: "Directory to write newly generated validator keys to, replacing the exported ones",
+	}
+	exportGenesisPortFlag = cli.IntFlag{
+		Name:  "exportgenesis.port",
+		Usage: "Network listening port of the first initial node, the others follow it",
+		Value: 16789,
+	}
+	exportGenesisCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportGenesis),
+		Name:      "exportgenesis",
+		Usage:     "Export the accounts and the PPOS state at a block into a genesis",
+		ArgsUsage: "<blockNum> <genesisPath>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.CacheFlag,
+			exportGenesisKeysFlag,
+			exportGenesisPortFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The exportgenesis command writes a genesis starting a new network from the state
+of the chain at the block: the accounts of the state trie and the PPOS records
+of the snapshot database, the candidates, delegations, restricting plans and
+govern parameters. The block must end an epoch, its state must still be present
+and recorded with the key preimages (--cache.preimages or an archive node), and
+the node must be stopped.
+
+The verifiers of the next epoch become the initial nodes of the genesis, listed
+on the local host from --exportgenesis.port. With --exportgenesis.keys, new node
+keys and BLS keys are generated for them and written to the node-<i>/hskchain
+directories of the given directory, the PPOS records of the validators are
+rewritten for the new keys. Without it the genesis keeps the keys of the chain.
+
+Records scheduled by block, round or epoch number, like pending unstakings,
+restricting releases and proposals, keep the numbers of the exported chain.`,
+	}
+)
+
+// exportGenesis exports the state at the block into a genesis file.
+func exportGenesis(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		utils.Fatalf("This command requires two arguments.")
+	}
+	number, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+	if err != nil {
+		utils.Fatalf("Invalid block number %q: %v", ctx.Args().Get(0), err)
+	}
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, chainDb := utils.MakeChain(ctx, stack, true)
+	defer chainDb.Close()
+
+	header := chain.GetHeaderByNumber(number)
+	if header == nil {
+		utils.Fatalf("Block %d not found", number)
+	}
+	statedb, err := chain.StateAt(header.Root)
+	if err != nil {
+		utils.Fatalf("State of block %d unavailable: %v", number, err)
+	}
+	snapshotdb.SetDBBlockChain(chain)
+	sdb, err := snapshotdb.Open(stack.ResolvePath(snapshotdb.DBPath), 0, 0, false)
+	if err != nil {
+		utils.Fatalf("Failed to open snapshotdb: %v", err)
+	}
+	defer sdb.Close()
+
+	start := time.Now()
+	genesis, err := core.ExportGenesis(chain.Config(), header, statedb, sdb)
+	if err != nil {
+		utils.Fatalf("Failed to export block %d: %v", number, err)
+	}
+	if dir := ctx.String(exportGenesisKeysFlag.Name); dir != "" {
+		nodes, err := makeGenesisValidatorKeys(dir, len(genesis.Config.Cbft.InitialNodes))
+		if err != nil {
+			utils.Fatalf("Failed to generate the validator keys: %v", err)
+		}
+		if err := genesis.ReplaceValidatorKeys(nodes); err != nil {
+			utils.Fatalf("Failed to replace the validator keys: %v", err)
+		}
+		fmt.Printf("Wrote the keys of %d validators to %s\n", len(nodes), dir)
+	}
+	port := ctx.Int(exportGenesisPortFlag.Name)
+	for i := range genesis.Config.Cbft.InitialNodes {
+		node := &genesis.Config.Cbft.InitialNodes[i].Node
+		*node = *discover.NewNode(node.ID, net.ParseIP("127.0.0.1"), uint16(port+i), uint16(port+i))
+	}
+
+	blob, err := marshalExportedGenesis(genesis, xcom.GetEceAt(number))
+	if err != nil {
+		utils.Fatalf("Failed to encode the genesis: %v", err)
+	}
+	if err := ioutil.WriteFile(ctx.Args().Get(1), blob, 0600); err != nil {
+		utils.Fatalf("Failed to write the genesis: %v", err)
+	}
+	fmt.Printf("Exported block %d (%x) in %v: %d accounts, %d PPOS records, %d initial nodes\n",
+		number, header.Hash(), time.Since(start), len(genesis.Alloc), len(genesis.PPOS.Data), len(genesis.Config.Cbft.InitialNodes))
+	return nil
+}
+
+// makeGenesisValidatorKeys generates the node keys and the BLS keys of the
+// validators and writes them into their directories.
+func makeGenesisValidatorKeys(dir string, count int) ([]params.CbftNode, error) {
+	nodes := make([]params.CbftNode, count)
+	for i := range nodes {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		blsKey := bls.GenerateKey()
+		instanceDir := filepath.Join(dir, fmt.Sprintf("node-%d", i), clientIdentifier)
+		if err := os.MkdirAll(instanceDir, 0700); err != nil {
+			return nil, err
+		}
+		if err := crypto.SaveECDSA(filepath.Join(instanceDir, "nodekey"), key); err != nil {
+			return nil, err
+		}
+		if err := bls.SaveBLS(filepath.Join(instanceDir, "blskey"), blsKey); err != nil {
+			return nil, err
+		}
+		nodes[i] = params.CbftNode{
+			Node:      discover.Node{ID: discover.PubkeyID(&key.PublicKey)},
+			BlsPubKey: *blsKey.GetPublicKey(),
+		}
+	}
+	return nodes, nil
+}
+
+// marshalExportedGenesis encodes the genesis with the extended economic model
+// merged into its economic model, the genesis loads both from the same object.
+func marshalExportedGenesis(genesis *core.Genesis, ece *xcom.EconomicModelExtend) ([]byte, error) {
+	model := make(map[string]map[string]json.RawMessage)
+	for _, part := range []interface{}{genesis.EconomicModel, ece} {
+		blob, err := json.Marshal(part)
+		if err != nil {
+			return nil, err
+		}
+		var sections map[string]map[string]json.RawMessage
+		if err := json.Unmarshal(blob, &sections); err != nil {
+			return nil, err
+		}
+		for name, section := range sections {
+			if model[name] == nil {
+				model[name] = make(map[string]json.RawMessage)
+			}
+			for key, value := range section {
+				model[name][key] = value
+			}
+		}
+	}
+	blob, err := json.Marshal(genesis)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(blob, &fields); err != nil {
+		return nil, err
+	}
+	if fields["economicModel"], err = json.Marshal(model); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(fields, "", "  ")
+}
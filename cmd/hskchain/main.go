@@ -81,8 +81,11 @@ var (
 		utils.TxPoolGlobalTxCountFlag,
 		utils.TxPoolLifetimeFlag,
 		utils.TxPoolCacheSizeFlag,
+		utils.TxPoolSystemSlotsFlag,
+		utils.TxPoolSystemTypeSlotsFlag,
 		utils.SyncModeFlag,
 		utils.TxLookupLimitFlag,
+		utils.AddressIndexFlag,
 		utils.LightKDFFlag,
 		utils.CacheFlag,
 		utils.CacheDatabaseFlag,
@@ -147,6 +150,7 @@ var (
 		utils.InsecureUnlockAllowedFlag,
 		utils.RPCGlobalGasCapFlag,
 		utils.RPCGlobalTxFeeCapFlag,
+		utils.RPCGlobalSimulateCapFlag,
 	}
 
 	metricsFlags = []cli.Flag{
@@ -167,6 +171,7 @@ var (
 		utils.CbftWalDisabledFlag,
 		utils.CbftMaxPingLatency,
 		utils.CbftBlsPriKeyFileFlag,
+		utils.CbftNextBlsPriKeyFileFlag,
 		utils.CbftBlacklistDeadlineFlag,
 	}
 
@@ -181,6 +186,8 @@ var (
 	vmFlags = []cli.Flag{
 		utils.VMWasmType,
 		utils.VmTimeoutDuration,
+		utils.VMWasmCacheFlag,
+		utils.VMWasmCacheStoreFlag,
 	}
 )
 
@@ -201,6 +208,8 @@ func init() {
 		dumpCommand,
 		dumpGenesisCommand,
 		inspectCommand,
+		snapshotdbCommand,
+		exportGenesisCommand,
 		// See accountcmd.go:
 		accountCommand,
 		// See consolecmd.go:
@@ -211,6 +220,9 @@ func init() {
 		licenseCommand,
 		// See config.go
 		dumpConfigCommand,
+		// See devnetcmd.go
+		devnetCommand,
+		devnetNodeCommand,
 		// See cmd/utils/flags_legacy.go
 		utils.ShowDeprecated,
 	}
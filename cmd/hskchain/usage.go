@@ -78,6 +78,7 @@ var AppHelpFlagGroups = []flagGroup{
 			//	utils.EthStatsURLFlag,
 			utils.IdentityFlag,
 			utils.TxLookupLimitFlag,
+			utils.AddressIndexFlag,
 			utils.LightKDFFlag,
 		},
 	},
@@ -102,6 +103,8 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TxPoolGlobalTxCountFlag,
 			utils.TxPoolLifetimeFlag,
 			utils.TxPoolCacheSizeFlag,
+			utils.TxPoolSystemSlotsFlag,
+			utils.TxPoolSystemTypeSlotsFlag,
 		},
 	},
 	{
@@ -146,6 +149,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.GraphQLVirtualHostsFlag,
 			utils.RPCGlobalGasCapFlag,
 			utils.RPCGlobalTxFeeCapFlag,
+			utils.RPCGlobalSimulateCapFlag,
 			utils.JSpathFlag,
 			utils.ExecFlag,
 			utils.PreloadJSFlag,
@@ -200,6 +204,7 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.CbftWalDisabledFlag,
 			utils.CbftMaxPingLatency,
 			utils.CbftBlsPriKeyFileFlag,
+			utils.CbftNextBlsPriKeyFileFlag,
 			utils.CbftBlacklistDeadlineFlag,
 		},
 	},
@@ -218,6 +223,8 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.VMWasmType,
 			utils.VmTimeoutDuration,
+			utils.VMWasmCacheFlag,
+			utils.VMWasmCacheStoreFlag,
 		},
 	},
 	{
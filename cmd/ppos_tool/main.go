@@ -95,6 +95,15 @@ type Ppos_1005 struct {
 	Amount          *big.Int
 }
 
+// rotateCandidateKey
+type Ppos_1007 struct {
+	NodeId     discover.NodeID
+	NewNodeId  discover.NodeID
+	NodeIdSign common.VersionSign
+	BlsPubKey  bls.PublicKeyHex
+	BlsProof   bls.SchnorrProofHex
+}
+
 // getRelatedListByDelAddr
 type Ppos_1103 struct {
 	Addr common.Address
@@ -112,6 +121,16 @@ type Ppos_1105 struct {
 	NodeId discover.NodeID
 }
 
+// getCommissionSchedule
+type Ppos_1107 struct {
+	NodeId discover.NodeID
+}
+
+// getCommissionHistory
+type Ppos_1108 struct {
+	NodeId discover.NodeID
+}
+
 // submitText
 type Ppos_2000 struct {
 	Verifier discover.NodeID
@@ -234,9 +253,12 @@ type decDataConfig struct {
 	P1003 Ppos_1003
 	P1004 Ppos_1004
 	P1005 Ppos_1005
+	P1007 Ppos_1007
 	P1103 Ppos_1103
 	P1104 Ppos_1104
 	P1105 Ppos_1105
+	P1107 Ppos_1107
+	P1108 Ppos_1108
 	P2000 Ppos_2000
 	P2001 Ppos_2001
 	P2002 Ppos_2002
@@ -370,6 +392,20 @@ func getRlpData(funcType uint16, cfg *decDataConfig) string {
 			params = append(params, amount)
 		}
 	case 1006:
+	case 1007:
+		{
+			nodeId, _ := rlp.EncodeToBytes(cfg.P1007.NodeId)
+			newNodeId, _ := rlp.EncodeToBytes(cfg.P1007.NewNodeId)
+			nodeIdSign, _ := rlp.EncodeToBytes(cfg.P1007.NodeIdSign)
+			blsPubKey, _ := rlp.EncodeToBytes(cfg.P1007.BlsPubKey)
+			blsProof, _ := rlp.EncodeToBytes(cfg.P1007.BlsProof)
+
+			params = append(params, nodeId)
+			params = append(params, newNodeId)
+			params = append(params, nodeIdSign)
+			params = append(params, blsPubKey)
+			params = append(params, blsProof)
+		}
 	case 1100:
 	case 1101:
 	case 1102:
@@ -394,6 +430,16 @@ func getRlpData(funcType uint16, cfg *decDataConfig) string {
 			params = append(params, nodeId)
 		}
 	case 1106:
+	case 1107:
+		{
+			nodeId, _ := rlp.EncodeToBytes(cfg.P1107.NodeId)
+			params = append(params, nodeId)
+		}
+	case 1108:
+		{
+			nodeId, _ := rlp.EncodeToBytes(cfg.P1108.NodeId)
+			params = append(params, nodeId)
+		}
 
 	case 1200:
 	case 1201:
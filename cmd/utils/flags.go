@@ -198,6 +198,10 @@ var (
 		Usage: "Number of recent blocks to maintain transactions index by-hash for (default = index all blocks)",
 		Value: 0,
 	}
+	AddressIndexFlag = cli.BoolFlag{
+		Name:  "addressindex",
+		Usage: "Maintain the transactions index by-address for the blocks indexed by-hash (see txlookuplimit)",
+	}
 	LightKDFFlag = cli.BoolFlag{
 		Name:  "lightkdf",
 		Usage: "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
@@ -261,6 +265,16 @@ var (
 		Usage: "After receiving the specified number of transactions from the remote, move the transactions in the queen to pending",
 		Value: eth.DefaultConfig.TxPool.TxCacheSize,
 	}
+	TxPoolSystemSlotsFlag = cli.Uint64Flag{
+		Name:  "txpool.systemslots",
+		Usage: "Number of transaction slots reserved for PPOS system-contract calls of active candidates",
+		Value: eth.DefaultConfig.TxPool.SystemSlots,
+	}
+	TxPoolSystemTypeSlotsFlag = cli.Uint64Flag{
+		Name:  "txpool.systemtypeslots",
+		Usage: "Maximum number of reserved slots per account and PPOS system-contract function",
+		Value: eth.DefaultConfig.TxPool.SystemTypeSlots,
+	}
 	// Performance tuning settings
 	CacheFlag = cli.IntFlag{
 		Name:  "cache",
@@ -326,6 +340,11 @@ var (
 		Usage: "Sets a cap on transaction fee (in ether) that can be sent via the RPC APIs (0 = no cap)",
 		Value: eth.DefaultConfig.RPCTxFeeCap,
 	}
+	RPCGlobalSimulateCapFlag = cli.Uint64Flag{
+		Name:  "rpc.simulatecap",
+		Usage: "Sets a cap on the blocks that can be run by hskchain_simulateBlocks, the filled empty blocks included (0=infinite)",
+		Value: eth.DefaultConfig.RPCSimulateCap,
+	}
 	// Logging and debug settings
 	EthStatsURLFlag = cli.StringFlag{
 		Name:  "ethstats",
@@ -592,6 +611,11 @@ var (
 		Usage: "BLS key file",
 	}
 
+	CbftNextBlsPriKeyFileFlag = cli.StringFlag{
+		Name:  "cbft.blskey.next",
+		Usage: "Rotated BLS key file, used once the validators carry its public key",
+	}
+
 	CbftBlacklistDeadlineFlag = cli.StringFlag{
 		Name:  "cbft.blacklist_deadline",
 		Usage: "Blacklist effective time. uint:minute",
@@ -635,6 +659,15 @@ var (
 		EnvVar: "",
 		Value:  eth.DefaultConfig.VmTimeoutDuration,
 	}
+	VMWasmCacheFlag = cli.IntFlag{
+		Name:  "vm.wasm_cache",
+		Usage: "Memory allowance (MB) to use for caching the compiled wasm modules",
+		Value: eth.DefaultConfig.VMWasmCache,
+	}
+	VMWasmCacheStoreFlag = cli.BoolFlag{
+		Name:  "vm.wasm_cache_store",
+		Usage: "Persist the compiled wasm modules to warm up the cache on restart",
+	}
 )
 
 // MakeDataDir retrieves the currently requested data directory, terminating
@@ -1089,6 +1122,12 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolCacheSizeFlag.Name) {
 		cfg.TxCacheSize = ctx.GlobalUint64(TxPoolCacheSizeFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolSystemSlotsFlag.Name) {
+		cfg.SystemSlots = ctx.GlobalUint64(TxPoolSystemSlotsFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolSystemTypeSlotsFlag.Name) {
+		cfg.SystemTypeSlots = ctx.GlobalUint64(TxPoolSystemTypeSlotsFlag.Name)
+	}
 }
 
 func setMiner(ctx *cli.Context, cfg *miner.Config) {
@@ -1131,6 +1170,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(TxLookupLimitFlag.Name) {
 		cfg.TxLookupLimit = ctx.GlobalUint64(TxLookupLimitFlag.Name)
 	}
+	if ctx.GlobalIsSet(AddressIndexFlag.Name) {
+		cfg.AddressIndex = ctx.GlobalBool(AddressIndexFlag.Name)
+	}
 
 	cfg.NoPruning = true
 
@@ -1155,6 +1197,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(RPCGlobalTxFeeCapFlag.Name) {
 		cfg.RPCTxFeeCap = ctx.GlobalFloat64(RPCGlobalTxFeeCapFlag.Name)
 	}
+	if ctx.GlobalIsSet(RPCGlobalSimulateCapFlag.Name) {
+		cfg.RPCSimulateCap = ctx.GlobalUint64(RPCGlobalSimulateCapFlag.Name)
+	}
 
 	// Override any default configs for hard coded networks.
 	switch {
@@ -1200,6 +1245,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(VmTimeoutDuration.Name) {
 		cfg.VmTimeoutDuration = ctx.GlobalUint64(VmTimeoutDuration.Name)
 	}
+	if ctx.GlobalIsSet(VMWasmCacheFlag.Name) {
+		cfg.VMWasmCache = ctx.GlobalInt(VMWasmCacheFlag.Name)
+	}
+	if ctx.GlobalIsSet(VMWasmCacheStoreFlag.Name) {
+		cfg.VMWasmCacheStore = ctx.GlobalBool(VMWasmCacheStoreFlag.Name)
+	}
 
 }
 
@@ -1220,6 +1271,14 @@ func SetCbft(ctx *cli.Context, cfg *types.OptionsConfig, nodeCfg *node.Config) {
 	}
 	nodeCfg.P2P.BlsPublicKey = *(cfg.BlsPriKey.GetPublicKey())
 
+	if ctx.GlobalIsSet(CbftNextBlsPriKeyFileFlag.Name) {
+		priKey, err := bls.LoadBLS(ctx.GlobalString(CbftNextBlsPriKeyFileFlag.Name))
+		if err != nil {
+			Fatalf("Failed to load next bls key from file: %v", err)
+		}
+		cfg.NextBlsPriKey = priKey
+	}
+
 	if ctx.GlobalIsSet(CbftWalDisabledFlag.Name) {
 		cfg.WalMode = !ctx.GlobalBool(CbftWalDisabledFlag.Name)
 	}
@@ -1374,6 +1433,7 @@ func MakeChain(ctx *cli.Context, stack *node.Node, readOnly bool) (chain *core.B
 		BadBlockLimit:   eth.DefaultConfig.BadBlockLimit,
 		TriesInMemory:   eth.DefaultConfig.TriesInMemory,
 		Preimages:       ctx.GlobalBool(CachePreimagesFlag.Name),
+		AddressIndex:    ctx.GlobalBool(AddressIndexFlag.Name) && !readOnly,
 	}
 	if eth.DefaultConfig.DBDisabledGC && !cache.Preimages {
 		cache.Preimages = true
@@ -392,6 +392,8 @@ type MockStateDB struct {
 	Logs         map[common.Hash][]*types.Log
 	Journal      *journal
 
+	BalanceChanges []*types.BalanceChange
+
 	// Per-transaction access list
 	accessList *accessList
 }
@@ -402,6 +404,11 @@ func (s *MockStateDB) Prepare(thash, bhash common.Hash, ti int) {
 	s.TxIndex = ti
 }
 
+func (s *MockStateDB) SetTxContext(thash common.Hash, ti int) {
+	s.Thash = thash
+	s.TxIndex = ti
+}
+
 func (s *MockStateDB) IntermediateRoot(deleteEmptyObjects bool) common.Hash {
 	return common.ZeroHash
 }
@@ -603,6 +610,13 @@ func (s *MockStateDB) AddLog(logInfo *types.Log) {
 	s.logSize++
 }
 
+func (s *MockStateDB) AddBalanceChange(change *types.BalanceChange) {
+	s.Journal.append(addBalanceChange{})
+
+	change.TxHash = s.Thash
+	s.BalanceChanges = append(s.BalanceChanges, change)
+}
+
 func (s *MockStateDB) GetLogs(hash common.Hash) []*types.Log {
 	return s.Logs[hash]
 }
@@ -95,6 +95,7 @@ type (
 	addLogChange struct {
 		txhash common.Hash
 	}
+	addBalanceChange struct{}
 )
 
 func (ch balanceChange) revert(s *MockStateDB) {
@@ -144,6 +145,14 @@ func (ch suicideChange) revert(s *MockStateDB) {
 	s.Balance[*ch.account] = ch.prevbalance
 }
 
+func (ch addBalanceChange) revert(s *MockStateDB) {
+	s.BalanceChanges = s.BalanceChanges[:len(s.BalanceChanges)-1]
+}
+
+func (ch addBalanceChange) dirtied() *common.Address {
+	return nil
+}
+
 func (ch addLogChange) revert(s *MockStateDB) {
 	logs := s.Logs[ch.txhash]
 	if len(logs) == 1 {
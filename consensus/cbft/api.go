@@ -19,6 +19,7 @@ package cbft
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/hashkey-chain/hashkey-chain/consensus/cbft/state"
 	"github.com/hashkey-chain/hashkey-chain/consensus/cbft/types"
@@ -31,6 +32,19 @@ type Status struct {
 	Validator bool             `json:"validator"`
 }
 
+// ViewStatus is a brief of the consensus status reported to the monitoring
+// services, it is cheaper than Status which encodes the whole block tree.
+type ViewStatus struct {
+	Epoch          uint64        `json:"epoch"`
+	ViewNumber     uint64        `json:"viewNumber"`
+	HighestQC      uint64        `json:"highestQCBlock"`
+	HighestCommit  uint64        `json:"highestCommitBlock"`
+	QCLatency      time.Duration `json:"qcLatency"`
+	ViewChanges    uint64        `json:"viewChanges"`
+	Validator      bool          `json:"validator"`
+	ValidatorCount int           `json:"validatorCount"`
+}
+
 // API defines an exposed API function interface.
 type API interface {
 	Status() []byte
@@ -181,10 +181,19 @@ type Cbft struct {
 	netLatencyMap  map[string]*list.List
 	netLatencyLock sync.RWMutex
 
+	// Consensus statistics of the current epoch, only accessed in the main loop.
+	viewChanges uint64        // Views ended by a ViewChangeQC
+	qcLatency   time.Duration // Time from the proposal of the last QC block to its QC
+
+	// QCs of the downloaded blocks verified in one batch, block hash to qc hash,
+	// only accessed in the main loop.
+	verifiedQCs map[common.Hash]common.Hash
+
 	//test
 	insertBlockQCHook  func(block *types.Block, qc *ctypes.QuorumCert)
 	executeFinishHook  func(index uint32)
 	consensusNodesMock func() ([]discover.NodeID, error)
+	broadcastHook      func(msg ctypes.Message) bool
 }
 
 // New returns a new CBFT.
@@ -251,6 +260,7 @@ func (cbft *Cbft) Start(chain consensus.ChainReader, blockCacheWriter consensus.
 
 	//Initialize view state
 	cbft.state = cstate.NewViewState(cbft.config.Sys.Period, cbft.blockTree)
+	cbft.state.SetSchedule(cbft.config.Sys.Period, cbft.config.Sys.Amount)
 	cbft.state.SetHighestQCBlock(block)
 	cbft.state.SetHighestLockBlock(block)
 	cbft.state.SetHighestCommitBlock(block)
@@ -803,7 +813,7 @@ func (cbft *Cbft) OnSeal(block *types.Block, results chan<- *types.Block, stop <
 	if !cbft.isLoading() {
 		cbft.bridge.SendPrepareBlock(prepareBlock)
 	}
-	cbft.network.Broadcast(prepareBlock)
+	cbft.broadcast(prepareBlock)
 	cbft.log.Info("Broadcast PrepareBlock", "prepareBlock", prepareBlock.String())
 
 	if err := cbft.signBlock(block.Hash(), block.NumberU64(), prepareBlock.BlockIndex); err != nil {
@@ -881,6 +891,52 @@ func (cbft *Cbft) NextBaseBlock() *types.Block {
 	return <-result
 }
 
+// BatchVerifyQCs verifies the qc signatures of the downloaded blocks in one
+// batch, InsertChain then only validates the qc of each verified block.
+func (cbft *Cbft) BatchVerifyQCs(blocks types.Blocks) {
+	qcs := make([]*ctypes.QuorumCert, 0, len(blocks))
+	hashes := make([]common.Hash, 0, len(blocks))
+	for _, block := range blocks {
+		_, qc, err := ctypes.DecodeExtra(block.ExtraData())
+		if err != nil || qc == nil {
+			continue
+		}
+		qcs = append(qcs, qc)
+		hashes = append(hashes, block.Hash())
+	}
+	if len(qcs) == 0 {
+		return
+	}
+
+	done := make(chan struct{})
+	cbft.asyncCallCh <- func() {
+		defer close(done)
+		verified := cbft.batchVerifyPrepareQCs(qcs)
+		cbft.verifiedQCs = make(map[common.Hash]common.Hash, len(verified))
+		for i, qc := range qcs {
+			if _, ok := verified[qc]; ok {
+				cbft.verifiedQCs[hashes[i]] = common.RlpHash(qc)
+			}
+		}
+	}
+	select {
+	case <-done:
+	case <-cbft.exitCh:
+	}
+}
+
+// verifyInsertedQC verifies the qc of the block inserted by InsertChain,
+// the signature is skipped if it has been verified by BatchVerifyQCs.
+func (cbft *Cbft) verifyInsertedQC(block *types.Block, qc *ctypes.QuorumCert) error {
+	if hash, ok := cbft.verifiedQCs[block.Hash()]; ok {
+		delete(cbft.verifiedQCs, block.Hash())
+		if hash == common.RlpHash(qc) {
+			return cbft.validatePrepareQC(block.NumberU64(), block.Hash(), qc)
+		}
+	}
+	return cbft.verifyPrepareQC(block.NumberU64(), block.Hash(), qc)
+}
+
 // InsertChain is used to insert the block into the chain.
 func (cbft *Cbft) InsertChain(block *types.Block) error {
 	if block.NumberU64() <= cbft.state.HighestLockBlock().NumberU64() || cbft.HasBlock(block.Hash(), block.NumberU64()) {
@@ -927,7 +983,7 @@ func (cbft *Cbft) InsertChain(block *types.Block) error {
 			return
 		}
 
-		if err := cbft.verifyPrepareQC(block.NumberU64(), block.Hash(), qc); err != nil {
+		if err := cbft.verifyInsertedQC(block, qc); err != nil {
 			cbft.log.Error("Verify prepare QC fail", "number", block.Number(), "hash", block.Hash(), "err", err)
 			result <- err
 			return
@@ -973,6 +1029,36 @@ func (cbft *Cbft) Status() []byte {
 	return <-status
 }
 
+// ViewStatus returns the brief status of the consensus engine, nil if the
+// engine has stopped.
+func (cbft *Cbft) ViewStatus() *ViewStatus {
+	status := make(chan *ViewStatus, 1)
+	fn := func() {
+		epoch := cbft.state.Epoch()
+		status <- &ViewStatus{
+			Epoch:          epoch,
+			ViewNumber:     cbft.state.ViewNumber(),
+			HighestQC:      cbft.state.HighestQCBlock().NumberU64(),
+			HighestCommit:  cbft.state.HighestCommitBlock().NumberU64(),
+			QCLatency:      cbft.qcLatency,
+			ViewChanges:    cbft.viewChanges,
+			Validator:      cbft.IsConsensusNode(),
+			ValidatorCount: cbft.validatorPool.Len(epoch),
+		}
+	}
+	select {
+	case cbft.asyncCallCh <- fn:
+	case <-cbft.exitCh:
+		return nil
+	}
+	select {
+	case s := <-status:
+		return s
+	case <-cbft.exitCh:
+		return nil
+	}
+}
+
 // GetPrepareQC returns the QC data of the specified block height.
 func (cbft *Cbft) GetPrepareQC(number uint64) *ctypes.QuorumCert {
 	cbft.log.Debug("get prepare QC")
@@ -1173,7 +1259,7 @@ func (cbft *Cbft) OnShouldSeal(result chan error) {
 		return
 	}
 
-	if cbft.state.NextViewBlockIndex() >= cbft.config.Sys.Amount {
+	if cbft.state.NextViewBlockIndex() >= cbft.state.Amount() {
 		result <- errors.New("produce block over limit")
 		return
 	}
@@ -1200,7 +1286,8 @@ func (cbft *Cbft) OnShouldSeal(result chan error) {
 
 // CalcBlockDeadline return the deadline of the block.
 func (cbft *Cbft) CalcBlockDeadline(timePoint time.Time) time.Time {
-	produceInterval := time.Duration(cbft.config.Sys.Period/uint64(cbft.config.Sys.Amount)) * time.Millisecond
+	period, amount := cbft.state.Schedule()
+	produceInterval := time.Duration(period/uint64(amount)) * time.Millisecond
 	rtt := cbft.avgRTT()
 	executeTime := (produceInterval - rtt) / 2
 	cbft.log.Debug("Calc block deadline", "timePoint", timePoint, "stateDeadline", cbft.state.Deadline(), "produceInterval", produceInterval, "rtt", rtt, "executeTime", executeTime)
@@ -1212,12 +1299,13 @@ func (cbft *Cbft) CalcBlockDeadline(timePoint time.Time) time.Time {
 
 // CalcNextBlockTime returns the deadline  of the next block.
 func (cbft *Cbft) CalcNextBlockTime(blockTime time.Time) time.Time {
-	produceInterval := time.Duration(cbft.config.Sys.Period/uint64(cbft.config.Sys.Amount)) * time.Millisecond
+	period, amount := cbft.state.Schedule()
+	produceInterval := time.Duration(period/uint64(amount)) * time.Millisecond
 	rtt := cbft.avgRTT()
 	executeTime := (produceInterval - rtt) / 2
 	cbft.log.Debug("Calc next block time",
 		"blockTime", blockTime, "now", time.Now(), "produceInterval", produceInterval,
-		"period", cbft.config.Sys.Period, "amount", cbft.config.Sys.Amount,
+		"period", period, "amount", amount,
 		"interval", time.Since(blockTime), "rtt", rtt, "executeTime", executeTime)
 	if time.Since(blockTime) < produceInterval {
 		return blockTime.Add(executeTime + rtt)
@@ -1349,12 +1437,35 @@ func (cbft *Cbft) signFn(m []byte) ([]byte, error) {
 	return crypto.Sign(m, cbft.config.Option.NodePriKey)
 }
 
+// blsPriKey returns the bls private key in force, the rotated key takes
+// effect once the validators of the current epoch carry its public key.
+func (cbft *Cbft) blsPriKey() *bls.SecretKey {
+	next := cbft.config.Option.NextBlsPriKey
+	if next == nil {
+		return cbft.config.Option.BlsPriKey
+	}
+	node, err := cbft.validatorPool.GetValidatorByNodeID(cbft.state.Epoch(), cbft.config.Option.NodeID)
+	if err == nil && node.BlsPubKey != nil && node.BlsPubKey.IsEqual(next.GetPublicKey()) {
+		return next
+	}
+	return cbft.config.Option.BlsPriKey
+}
+
 // signFn use bls private key to sign byte slice.
 func (cbft *Cbft) signFnByBls(m []byte) ([]byte, error) {
-	sign := cbft.config.Option.BlsPriKey.Sign(string(m))
+	sign := cbft.blsPriKey().Sign(string(m))
 	return sign.Serialize(), nil
 }
 
+// broadcast imports the consensus message into the send queue of the network,
+// unless it is intercepted by the broadcast hook.
+func (cbft *Cbft) broadcast(msg ctypes.Message) {
+	if cbft.broadcastHook != nil && cbft.broadcastHook(msg) {
+		return
+	}
+	cbft.network.Broadcast(msg)
+}
+
 // signMsg use bls private key to sign msg.
 func (cbft *Cbft) signMsgByBls(msg ctypes.ConsensusMsg) error {
 	buf, err := msg.CannibalizeBytes()
@@ -1427,7 +1538,7 @@ func (cbft *Cbft) checkViewChangeQC(pb *protocols.PrepareBlock) error {
 		if localQC != nil && cbft.validatorPool.EqualSwitchPoint(localQC.BlockNumber) {
 			return false
 		}
-		return localQC != nil && localQC.BlockIndex < cbft.config.Sys.Amount-1
+		return localQC != nil && localQC.BlockIndex < cbft.state.Amount()-1
 	}
 	// check if the prepareBlock base on viewChangeQC maxBlock
 	baseViewChangeQC := func(pb *protocols.PrepareBlock) bool {
@@ -1718,6 +1829,24 @@ func (cbft *Cbft) generateViewChangeQC(viewChanges map[uint32]*protocols.ViewCha
 }
 
 func (cbft *Cbft) verifyPrepareQC(oriNum uint64, oriHash common.Hash, qc *ctypes.QuorumCert) error {
+	if err := cbft.validatePrepareQC(oriNum, oriHash, qc); err != nil {
+		return err
+	}
+
+	var cb []byte
+	var err error
+	if cb, err = qc.CannibalizeBytes(); err != nil {
+		return err
+	}
+	if err = cbft.validatorPool.VerifyAggSigByBA(qc.Epoch, qc.ValidatorSet, cb, qc.Signature.Bytes()); err != nil {
+		cbft.log.Error("Verify failed", "qc", qc.String(), "validators", cbft.validatorPool.Validators(qc.Epoch).String())
+		return authFailedError{err: fmt.Errorf("verify prepare qc failed: %v", err)}
+	}
+	return nil
+}
+
+// validatePrepareQC checks everything of the prepare qc except the aggregated signature.
+func (cbft *Cbft) validatePrepareQC(oriNum uint64, oriHash common.Hash, qc *ctypes.QuorumCert) error {
 	if qc == nil {
 		return fmt.Errorf("verify prepare qc failed,qc is nil")
 	}
@@ -1737,17 +1866,41 @@ func (cbft *Cbft) verifyPrepareQC(oriNum uint64, oriHash common.Hash, qc *ctypes
 			err: fmt.Errorf("verify prepare qc failed,not the corresponding qc,oriNum:%d,oriHash:%s,qcNum:%d,qcHash:%s",
 				oriNum, oriHash.String(), qc.BlockNumber, qc.BlockHash.String())}
 	}
+	return nil
+}
 
-	var cb []byte
-	var err error
-	if cb, err = qc.CannibalizeBytes(); err != nil {
-		return err
+// batchVerifyPrepareQCs verifies the aggregated signatures of the prepare qcs in one batch,
+// and returns the qcs whose signature is valid. The qcs of an epoch which can not be verified
+// now are skipped, they are left to verifyPrepareQC.
+func (cbft *Cbft) batchVerifyPrepareQCs(qcs []*ctypes.QuorumCert) map[*ctypes.QuorumCert]struct{} {
+	verified := make(map[*ctypes.QuorumCert]struct{})
+	batch := make([]*ctypes.QuorumCert, 0, len(qcs))
+	sigs := make([]*validator.AggSig, 0, len(qcs))
+	for _, qc := range qcs {
+		if qc == nil || cbft.validatorPool.EnableVerifyEpoch(qc.Epoch) != nil {
+			continue
+		}
+		cb, err := qc.CannibalizeBytes()
+		if err != nil {
+			continue
+		}
+		batch = append(batch, qc)
+		sigs = append(sigs, &validator.AggSig{Epoch: qc.Epoch, VSet: qc.ValidatorSet, Msg: cb, Signature: qc.Signature.Bytes()})
 	}
-	if err = cbft.validatorPool.VerifyAggSigByBA(qc.Epoch, qc.ValidatorSet, cb, qc.Signature.Bytes()); err != nil {
-		cbft.log.Error("Verify failed", "qc", qc.String(), "validators", cbft.validatorPool.Validators(qc.Epoch).String())
-		return authFailedError{err: fmt.Errorf("verify prepare qc failed: %v", err)}
+	if len(sigs) == 0 {
+		return verified
 	}
-	return nil
+
+	bad, err := cbft.validatorPool.BatchVerifyAggSigByBA(sigs)
+	if err != nil {
+		cbft.log.Error("Batch verify prepare qc failed", "qc", batch[bad].String(), "error", err)
+	} else {
+		bad = len(batch)
+	}
+	for _, qc := range batch[:bad] {
+		verified[qc] = struct{}{}
+	}
+	return verified
 }
 
 func (cbft *Cbft) validateViewChangeQC(viewChangeQC *ctypes.ViewChangeQC) error {
@@ -1801,24 +1954,23 @@ func (cbft *Cbft) verifyViewChangeQC(viewChangeQC *ctypes.ViewChangeQC) error {
 		return err
 	}
 
-	var err error
+	sigs := make([]*validator.AggSig, 0, len(viewChangeQC.QCs))
 	for _, vc := range viewChangeQC.QCs {
-		var cb []byte
-		if cb, err = vc.CannibalizeBytes(); err != nil {
-			err = fmt.Errorf("get cannibalize bytes failed")
-			break
+		cb, err := vc.CannibalizeBytes()
+		if err != nil {
+			return fmt.Errorf("get cannibalize bytes failed")
 		}
+		sigs = append(sigs, &validator.AggSig{Epoch: vc.Epoch, VSet: vc.ValidatorSet, Msg: cb, Signature: vc.Signature.Bytes()})
+	}
 
-		if err = cbft.validatorPool.VerifyAggSigByBA(vc.Epoch, vc.ValidatorSet, cb, vc.Signature.Bytes()); err != nil {
-			cbft.log.Debug("verify failed", "qc", vc.String(), "validators", cbft.validatorPool.Validators(vc.Epoch).String())
+	if bad, err := cbft.validatorPool.BatchVerifyAggSigByBA(sigs); err != nil {
+		vc := viewChangeQC.QCs[bad]
+		cbft.log.Debug("verify failed", "qc", vc.String(), "validators", cbft.validatorPool.Validators(vc.Epoch).String())
 
-			err = authFailedError{err: fmt.Errorf("verify viewchange qc failed:number:%d,validators:%s,msg:%s,signature:%s,err:%v",
-				vc.BlockNumber, vc.ValidatorSet.String(), hexutil.Encode(cb), vc.Signature.String(), err)}
-			break
-		}
+		return authFailedError{err: fmt.Errorf("verify viewchange qc failed:number:%d,validators:%s,msg:%s,signature:%s,err:%v",
+			vc.BlockNumber, vc.ValidatorSet.String(), hexutil.Encode(sigs[bad].Msg), vc.Signature.String(), err)}
 	}
-
-	return err
+	return nil
 }
 
 // NodeID returns the ID value of the current node
@@ -1827,7 +1979,8 @@ func (cbft *Cbft) NodeID() discover.NodeID {
 }
 
 func (cbft *Cbft) avgRTT() time.Duration {
-	produceInterval := time.Duration(cbft.config.Sys.Period/uint64(cbft.config.Sys.Amount)) * time.Millisecond
+	period, amount := cbft.state.Schedule()
+	produceInterval := time.Duration(period/uint64(amount)) * time.Millisecond
 	rtt := cbft.AvgLatency() * 2
 	if rtt == 0 || rtt >= produceInterval {
 		rtt = cbft.DefaultAvgLatency() * 2
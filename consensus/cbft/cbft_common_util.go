@@ -67,7 +67,7 @@ func NewBlock(parent common.Hash, number uint64) *types.Block {
 		Time:        uint64(time.Now().UnixNano() / 1e6),
 		Extra:       make([]byte, 97),
 		ReceiptHash: common.BytesToHash(hexutil.MustDecode("0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")),
-		Root:        common.BytesToHash(hexutil.MustDecode("0x3a3fed92c98ca419be7d4a125c30a4e8b3424e46c607b9cc2b6dfa5a81cbef0d")),
+		Root:        common.BytesToHash(hexutil.MustDecode("0x9d355d854005796b854edd65cef35975ddb8054898c4dea48953133d92145cd6")),
 		Coinbase:    common.Address{},
 		GasLimit:    10000000000,
 	}
@@ -84,7 +84,7 @@ func NewBlockWithSign(parent common.Hash, number uint64, node *TestCBFT) *types.
 		Time:        uint64(time.Now().UnixNano() / 1e6),
 		Extra:       make([]byte, 97),
 		ReceiptHash: common.BytesToHash(hexutil.MustDecode("0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")),
-		Root:        common.BytesToHash(hexutil.MustDecode("0x3a3fed92c98ca419be7d4a125c30a4e8b3424e46c607b9cc2b6dfa5a81cbef0d")),
+		Root:        common.BytesToHash(hexutil.MustDecode("0x9d355d854005796b854edd65cef35975ddb8054898c4dea48953133d92145cd6")),
 		Coinbase:    common.Address{},
 		GasLimit:    10000000000,
 	}
@@ -153,7 +153,7 @@ func CreateGenesis(db ethdb.Database) (core.Genesis, *types.Block) {
 			Alloc:  core.GenesisAlloc{},
 		}
 	)
-	gspec.Alloc[xcom.PlatONFundAccount()] = core.GenesisAccount{
+	gspec.Alloc[xcom.PlatONFundAccount(0)] = core.GenesisAccount{
 		Balance: xcom.PlatONFundBalance(),
 	}
 	gspec.Alloc[cvm.RewardManagerPoolAddr] = core.GenesisAccount{
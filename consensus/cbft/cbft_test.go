@@ -641,12 +641,71 @@ func TestShouldSeal(t *testing.T) {
 	assert.False(t, should)
 }
 
+func TestViewStatus(t *testing.T) {
+	pk, sk, cbftnodes := GenerateCbftNode(5)
+	node := MockNode(pk[0], sk[0], cbftnodes[:4], 3000, 10)
+	assert.Nil(t, node.Start())
+
+	status := node.engine.ViewStatus()
+	assert.NotNil(t, status)
+	assert.True(t, status.Validator)
+	assert.Equal(t, 4, status.ValidatorCount)
+	assert.Equal(t, node.engine.state.Epoch(), status.Epoch)
+	assert.Equal(t, node.engine.state.ViewNumber(), status.ViewNumber)
+	assert.Equal(t, uint64(0), status.ViewChanges)
+
+	node5 := MockNode(pk[4], sk[4], cbftnodes[:4], 3000, 10)
+	assert.Nil(t, node5.Start())
+	status = node5.engine.ViewStatus()
+	assert.NotNil(t, status)
+	assert.False(t, status.Validator)
+}
+
 func TestCbft_CreateGenesis(t *testing.T) {
 	var db = rawdb.NewMemoryDatabase()
 	_, block := CreateGenesis(db)
 	fmt.Println(block.Root().Hex())
 }
 
+func TestCbft_BatchVerifyQCs(t *testing.T) {
+	pk, sk, cbftnodes := GenerateCbftNode(4)
+	nodes := make([]*TestCBFT, 0)
+	for i := 0; i < 4; i++ {
+		node := MockNode(pk[i], sk[i], cbftnodes, 200000, 10)
+		assert.Nil(t, node.Start())
+		nodes = append(nodes, node)
+	}
+	blocks, qcs := makeQCBlocks(t, nodes, 3)
+
+	// The downloaded blocks carry their qcs in the extra data, the third
+	// one with the signature of the second.
+	bad := *qcs[2]
+	bad.Signature = qcs[1].Signature
+	qcs[2] = &bad
+	chain := make(types.Blocks, 0, len(blocks))
+	for i, block := range blocks {
+		extra, err := ctypes.EncodeExtra(cbftVersion, qcs[i])
+		assert.Nil(t, err)
+		chain = append(chain, block.WithBody(nil, extra))
+	}
+	nodes[3].engine.BatchVerifyQCs(chain)
+
+	done := make(chan struct{})
+	nodes[3].engine.asyncCallCh <- func() {
+		defer close(done)
+		assert.Len(t, nodes[3].engine.verifiedQCs, 2)
+		for i, block := range chain {
+			_, verified := nodes[3].engine.verifiedQCs[block.Hash()]
+			assert.Equal(t, i < 2, verified)
+		}
+		assert.Nil(t, nodes[3].engine.verifyInsertedQC(chain[0], qcs[0]))
+		assert.Nil(t, nodes[3].engine.verifyInsertedQC(chain[1], qcs[1]))
+		assert.NotNil(t, nodes[3].engine.verifyInsertedQC(chain[2], qcs[2]))
+		assert.Len(t, nodes[3].engine.verifiedQCs, 0)
+	}
+	<-done
+}
+
 func TestInsertChain(t *testing.T) {
 	pk, sk, cbftnodes := GenerateCbftNode(4)
 	nodes := make([]*TestCBFT, 0)
@@ -210,7 +210,7 @@ func (cbft *Cbft) OnViewTimeout() {
 	}
 
 	cbft.state.AddViewChange(uint32(node.Index), viewChange)
-	cbft.network.Broadcast(viewChange)
+	cbft.broadcast(viewChange)
 	cbft.log.Info("Local add viewChange", "index", node.Index, "viewChange", viewChange.String(), "total", cbft.state.ViewChangeLen())
 
 	cbft.tryChangeView()
@@ -263,6 +263,7 @@ func (cbft *Cbft) insertQCBlock(block *types.Block, qc *ctypes.QuorumCert) {
 
 	lock, commit := cbft.blockTree.InsertQCBlock(block, qc)
 	cbft.TrySetHighestQCBlock(block)
+	cbft.qcLatency = time.Since(common.MillisToTime(int64(block.Time())))
 	isOwn := func() bool {
 		node, err := cbft.isCurrentValidator()
 		if err != nil {
@@ -431,7 +432,7 @@ func (cbft *Cbft) trySendPrepareVote() {
 				cbft.bridge.SendPrepareVote(block, p)
 			}
 
-			cbft.network.Broadcast(p)
+			cbft.broadcast(p)
 		} else {
 			break
 		}
@@ -500,7 +501,7 @@ func (cbft *Cbft) findQCBlock() {
 		if qc != nil {
 			cbft.log.Info("New qc block have been created", "qc", qc.String())
 			cbft.insertQCBlock(block, qc)
-			cbft.network.Broadcast(&protocols.BlockQuorumCert{BlockQC: qc})
+			cbft.broadcast(&protocols.BlockQuorumCert{BlockQC: qc})
 			// metrics
 			blockQCCollectedGauage.Update(int64(block.Time()))
 			cbft.trySendPrepareVote()
@@ -570,7 +571,7 @@ func (cbft *Cbft) tryChangeView() {
 	}()
 
 	enough := func() bool {
-		return cbft.state.MaxQCIndex()+1 == cbft.config.Sys.Amount ||
+		return cbft.state.MaxQCIndex()+1 == cbft.state.Amount() ||
 			(qc != nil && qc.Epoch == cbft.state.Epoch() && shouldSwitch)
 	}()
 
@@ -722,6 +723,19 @@ func (cbft *Cbft) generateViewChange(qc *ctypes.QuorumCert) (*protocols.ViewChan
 	return v, nil
 }
 
+// updateSchedule switches the block production period and amount to the
+// ones of the current validators, they change at the epoch boundaries once
+// governed by the param proposals. The schedule is kept in the view state,
+// which is read by the miner as well, the config stays as it's configured.
+func (cbft *Cbft) updateSchedule() {
+	period, amount := cbft.validatorPool.Schedule()
+	if curPeriod, curAmount := cbft.state.Schedule(); period == 0 || amount == 0 || (period == curPeriod && amount == curAmount) {
+		return
+	}
+	cbft.state.SetSchedule(period, amount)
+	cbft.log.Info("Switch block production schedule", "period", period, "amount", amount)
+}
+
 // change view
 func (cbft *Cbft) changeView(epoch, viewNumber uint64, block *types.Block, qc *ctypes.QuorumCert, viewChangeQC *ctypes.ViewChangeQC) {
 	interval := func() uint64 {
@@ -729,7 +743,7 @@ func (cbft *Cbft) changeView(epoch, viewNumber uint64, block *types.Block, qc *c
 			return viewNumber - state.DefaultViewNumber + 1
 		}
 		if qc.ViewNumber+1 == viewNumber {
-			return uint64((cbft.config.Sys.Amount-qc.BlockIndex)/3) + 1
+			return uint64((cbft.state.Amount()-qc.BlockIndex)/3) + 1
 		}
 		minuend := qc.ViewNumber
 		if qc.Epoch != epoch {
@@ -741,6 +755,7 @@ func (cbft *Cbft) changeView(epoch, viewNumber uint64, block *types.Block, qc *c
 	// when cbft is started or fast synchronization ends, the preEpoch, preViewNumber defaults to 0, 0
 	// but cbft is now in the loading state and lastViewChangeQC is nil, does not save the lastViewChangeQC
 	preEpoch, preViewNumber := cbft.state.Epoch(), cbft.state.ViewNumber()
+	cbft.updateSchedule()
 	// syncingCache is belong to last view request, clear all sync cache
 	cbft.syncingCache.Purge()
 	cbft.csPool.Purge(epoch, viewNumber)
@@ -749,6 +764,13 @@ func (cbft *Cbft) changeView(epoch, viewNumber uint64, block *types.Block, qc *c
 	cbft.state.SetViewTimer(interval())
 	cbft.state.SetLastViewChangeQC(viewChangeQC)
 
+	if epoch != preEpoch {
+		cbft.viewChanges = 0
+	}
+	if viewChangeQC != nil {
+		cbft.viewChanges++
+	}
+
 	// metrics.
 	viewNumberGauage.Update(int64(viewNumber))
 	epochNumberGauage.Update(int64(epoch))
@@ -757,6 +779,7 @@ func (cbft *Cbft) changeView(epoch, viewNumber uint64, block *types.Block, qc *c
 	// write confirmed viewChange info to wal
 	if !cbft.isLoading() {
 		cbft.bridge.ConfirmViewChange(epoch, viewNumber, block, qc, viewChangeQC, preEpoch, preViewNumber)
+		cbft.eventMux.Post(cbfttypes.ViewChangedEvent{Epoch: epoch, ViewNumber: viewNumber, Timeout: viewChangeQC != nil})
 	}
 	cbft.clearInvalidBlocks(block)
 	cbft.evPool.Clear(epoch, viewNumber)
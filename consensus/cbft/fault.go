@@ -0,0 +1,90 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+
+// +build test devnet
+
+package cbft
+
+import (
+	"time"
+
+	"github.com/hashkey-chain/hashkey-chain/consensus/cbft/protocols"
+	ctypes "github.com/hashkey-chain/hashkey-chain/consensus/cbft/types"
+	"github.com/hashkey-chain/hashkey-chain/crypto"
+)
+
+// Faults describes the deterministic faults injected into the outbound consensus
+// messages of the engine. It is only built into test and devnet binaries, which
+// use it to reproduce consensus and slashing scenarios.
+type Faults struct {
+	Offline    bool          // Withhold every outbound consensus message
+	Delay      time.Duration // Delay every outbound consensus message
+	Equivocate bool          // Send a conflicting prepareVote next to each prepareVote
+}
+
+// InjectFaults installs the faults through the broadcast hook of the engine, it
+// must be called before the engine is started.
+func (cbft *Cbft) InjectFaults(faults *Faults) {
+	cbft.broadcastHook = func(msg ctypes.Message) bool {
+		for _, m := range faults.outbound(cbft, msg) {
+			if faults.Delay > 0 {
+				m := m
+				time.AfterFunc(faults.Delay, func() { cbft.network.Broadcast(m) })
+			} else {
+				cbft.network.Broadcast(m)
+			}
+		}
+		return true
+	}
+}
+
+// outbound returns the consensus messages actually sent in place of the given one.
+func (f *Faults) outbound(cbft *Cbft, msg ctypes.Message) []ctypes.Message {
+	if f.Offline {
+		cbft.log.Debug("Withhold consensus message", "msg", msg.String())
+		return nil
+	}
+
+	msgs := []ctypes.Message{msg}
+	if vote, ok := msg.(*protocols.PrepareVote); ok && f.Equivocate {
+		if conflict, err := cbft.conflictingPrepareVote(vote); err == nil {
+			cbft.log.Warn("Equivocate prepareVote", "vote", vote.String(), "conflict", conflict.String())
+			msgs = append(msgs, conflict)
+		} else {
+			cbft.log.Error("Sign conflicting prepareVote failed", "err", err)
+		}
+	}
+	return msgs
+}
+
+// conflictingPrepareVote returns a signed prepareVote for the same view and
+// block index as the given vote but for a different block hash.
+func (cbft *Cbft) conflictingPrepareVote(vote *protocols.PrepareVote) (*protocols.PrepareVote, error) {
+	conflict := &protocols.PrepareVote{
+		Epoch:          vote.Epoch,
+		ViewNumber:     vote.ViewNumber,
+		BlockHash:      crypto.Keccak256Hash(vote.BlockHash.Bytes()),
+		BlockNumber:    vote.BlockNumber,
+		BlockIndex:     vote.BlockIndex,
+		ValidatorIndex: vote.ValidatorIndex,
+		ParentQC:       vote.ParentQC,
+	}
+	if err := cbft.signMsgByBls(conflict); err != nil {
+		return nil, err
+	}
+	return conflict, nil
+}
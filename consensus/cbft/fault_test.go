@@ -0,0 +1,68 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+// +build test devnet
+
+package cbft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/consensus/cbft/protocols"
+	ctypes "github.com/hashkey-chain/hashkey-chain/consensus/cbft/types"
+)
+
+func TestFaults(t *testing.T) {
+	pk, sk, cbftnodes := GenerateCbftNode(1)
+	node := MockNode(pk[0], sk[0], cbftnodes, 10000, 10)
+	assert.Nil(t, node.Start())
+	defer node.engine.Close()
+
+	vote := &protocols.PrepareVote{
+		Epoch:       1,
+		ViewNumber:  2,
+		BlockHash:   common.BytesToHash([]byte("block")),
+		BlockNumber: 3,
+		BlockIndex:  4,
+	}
+	assert.Nil(t, node.engine.signMsgByBls(vote))
+
+	// Without faults the broadcast hook is not installed
+	assert.Nil(t, node.engine.broadcastHook)
+
+	offline := &Faults{Offline: true}
+	assert.Empty(t, offline.outbound(node.engine, vote))
+
+	equivocate := &Faults{Equivocate: true}
+	msgs := equivocate.outbound(node.engine, vote)
+	assert.Len(t, msgs, 2)
+	assert.Equal(t, ctypes.Message(vote), msgs[0])
+	conflict := msgs[1].(*protocols.PrepareVote)
+	assert.Equal(t, vote.ViewNumber, conflict.ViewNumber)
+	assert.Equal(t, vote.BlockIndex, conflict.BlockIndex)
+	assert.NotEqual(t, vote.BlockHash, conflict.BlockHash)
+	assert.NotEmpty(t, conflict.Signature.Bytes())
+
+	// Only prepareVotes are equivocated
+	viewChange := &protocols.ViewChange{Epoch: 1, ViewNumber: 2}
+	assert.Len(t, equivocate.outbound(node.engine, viewChange), 1)
+
+	node.engine.InjectFaults(offline)
+	assert.True(t, node.engine.broadcastHook(vote))
+}
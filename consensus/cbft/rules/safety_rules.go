@@ -165,7 +165,7 @@ func (r *baseSafetyRules) PrepareBlockRules(block *protocols.PrepareBlock) Safet
 
 	acceptViewChangeQC := func() bool {
 		if block.ViewChangeQC == nil {
-			return r.config.Sys.Amount == r.viewState.MaxQCIndex()+1 || r.validatorPool.EqualSwitchPoint(block.Block.NumberU64()-1)
+			return r.viewState.Amount() == r.viewState.MaxQCIndex()+1 || r.validatorPool.EqualSwitchPoint(block.Block.NumberU64()-1)
 		}
 		_, _, _, _, hash, number := block.ViewChangeQC.MaxBlock()
 		return number+1 == block.Block.NumberU64() && block.Block.ParentHash() == hash
@@ -217,8 +217,8 @@ func (r *baseSafetyRules) PrepareBlockRules(block *protocols.PrepareBlock) Safet
 	// 4. the previous index block does not exist, discard the msg.
 	// 5. block index is continuous, but number or hash is not, discard the msg.
 	acceptIndexBlock := func() SafetyError {
-		if block.BlockIndex >= r.config.Sys.Amount {
-			return newCommonError(fmt.Sprintf("blockIndex higher than amount(index:%d, amount:%d)", block.BlockIndex, r.config.Sys.Amount))
+		if block.BlockIndex >= r.viewState.Amount() {
+			return newCommonError(fmt.Sprintf("blockIndex higher than amount(index:%d, amount:%d)", block.BlockIndex, r.viewState.Amount()))
 		}
 		if doubtDuplicate() {
 			return nil
@@ -319,8 +319,8 @@ func (r *baseSafetyRules) PrepareVoteRules(vote *protocols.PrepareVote) SafetyEr
 	}
 
 	acceptIndexVote := func() SafetyError {
-		if vote.BlockIndex >= r.config.Sys.Amount {
-			return newCommonError(fmt.Sprintf("voteIndex higher than amount(index:%d, amount:%d)", vote.BlockIndex, r.config.Sys.Amount))
+		if vote.BlockIndex >= r.viewState.Amount() {
+			return newCommonError(fmt.Sprintf("voteIndex higher than amount(index:%d, amount:%d)", vote.BlockIndex, r.viewState.Amount()))
 		}
 		if doubtDuplicate() {
 			return nil
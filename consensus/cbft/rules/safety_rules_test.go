@@ -272,6 +272,7 @@ func testBaseSafetyRulesViewChangeRules(t *testing.T, viewState *state.ViewState
 func TestSafetyError(t *testing.T) {
 	viewState, blockTree := newEpochViewNumberState(Epoch, ViewNumber, 6)
 	amount := uint32(10)
+	viewState.SetSchedule(Period, amount)
 	rules := NewSafetyRules(viewState, blockTree, &ctypes.Config{Sys: &params.CbftConfig{Amount: amount}}, nil)
 	testBaseSafetyRulesPrepareBlockRules(t, viewState, blockTree, rules, amount)
 	testBaseSafetyRulesPrepareVoteRules(t, viewState, blockTree, rules, amount)
@@ -498,9 +498,18 @@ type ViewState struct {
 	//Set the timer of the view time window
 	viewTimer *viewTimer
 
+	// The block production period and amount of the views, they change at
+	// the epoch boundaries once governed by the param proposals
+	schedule atomic.Value
+
 	blockTree *ctypes.BlockTree
 }
 
+type viewSchedule struct {
+	period uint64
+	amount uint32
+}
+
 func NewViewState(period uint64, blockTree *ctypes.BlockTree) *ViewState {
 	return &ViewState{
 		view:      newView(),
@@ -509,6 +518,26 @@ func NewViewState(period uint64, blockTree *ctypes.BlockTree) *ViewState {
 	}
 }
 
+// SetSchedule changes the time window and the blocks of the views.
+func (vs *ViewState) SetSchedule(period uint64, amount uint32) {
+	vs.schedule.Store(&viewSchedule{period: period, amount: amount})
+	vs.viewTimer.setPeriod(period)
+}
+
+// Schedule returns the time window in milliseconds and the blocks of the views.
+func (vs *ViewState) Schedule() (uint64, uint32) {
+	if s, ok := vs.schedule.Load().(*viewSchedule); ok {
+		return s.period, s.amount
+	}
+	return 0, 0
+}
+
+// Amount returns the blocks of the views.
+func (vs *ViewState) Amount() uint32 {
+	_, amount := vs.Schedule()
+	return amount
+}
+
 func (vs *ViewState) ResetView(epoch uint64, viewNumber uint64) {
 	vs.view.Reset()
 	atomic.StoreUint64(&vs.view.epoch, epoch)
@@ -58,6 +58,10 @@ func (t *viewTimer) calViewInterval(viewInterval uint64) uint64 {
 	return viewInterval
 }
 
+func (t *viewTimer) setPeriod(period uint64) {
+	t.timeInterval.baseMs = period * uint64(time.Millisecond)
+}
+
 func (t *viewTimer) setupTimer(viewInterval uint64) {
 	viewInterval = t.calViewInterval(viewInterval)
 	duration := t.timeInterval.getViewTimeInterval(viewInterval)
@@ -84,124 +84,8 @@ func (cbft *Cbft) fetchBlock(id string, hash common.Hash, number uint64, qc *cty
 		if !ok {
 			return
 		}
-
-		var wg sync.WaitGroup
-		var asyncCallErr error
-		// Handle block
-		for i, block := range blockList.Blocks {
-			if asyncCallErr != nil {
-				return
-			}
-			if block.ParentHash() != parentBlock.Hash() {
-				cbft.log.Debug("Response block is error", "blockHash", block.Hash(), "blockNumber", block.NumberU64(), "parentHash", parentBlock.Hash(), "parentNumber", parentBlock.NumberU64())
-				return
-			}
-			if err := cbft.blockCacheWriter.Execute(block, parentBlock); err != nil {
-				cbft.log.Error("Execute block failed", "hash", block.Hash(), "number", block.NumberU64(), "error", err)
-				return
-			}
-			wg.Add(1)
-
-			// Update the results to the CBFT state machine
-			cbft.asyncCallCh <- func() {
-				if err := cbft.verifyPrepareQC(block.NumberU64(), block.Hash(), blockList.QC[i]); err != nil {
-					cbft.log.Error("Verify block prepare qc failed", "hash", block.Hash(), "number", block.NumberU64(), "error", err)
-					asyncCallErr = err
-					wg.Done()
-					return
-				}
-				if err := cbft.OnInsertQCBlock([]*types.Block{block}, []*ctypes.QuorumCert{blockList.QC[i]}); err != nil {
-					cbft.log.Warn("Insert block failed", "error", err)
-					asyncCallErr = err
-				}
-				wg.Done()
-			}
-			wg.Wait()
-			parentBlock = block
-		}
-
-		// Handle forked block
-		if len(blockList.ForkedBlocks) == 0 {
-			cbft.log.Trace("No forked block need to handle")
-			return
-		}
-		// Remove local forks that already exist.
-		filteredForkedBlocks := make([]*types.Block, 0)
-		filteredForkedQCs := make([]*ctypes.QuorumCert, 0)
-		//localForkedBlocks, _ := cbft.blockTree.FindForkedBlocksAndQCs(parentBlock.Hash(), parentBlock.NumberU64())
-		localForkedBlocks, _ := cbft.blockTree.FindBlocksAndQCs(parentBlock.NumberU64())
-
-		if len(localForkedBlocks) > 0 {
-			cbft.log.Debug("LocalForkedBlocks", "number", localForkedBlocks[0].NumberU64(), "hash", localForkedBlocks[0].Hash().TerminalString())
-		}
-
-		for i, forkedBlock := range blockList.ForkedBlocks {
-			for _, localForkedBlock := range localForkedBlocks {
-				if forkedBlock.NumberU64() == localForkedBlock.NumberU64() && forkedBlock.Hash() != localForkedBlock.Hash() {
-					filteredForkedBlocks = append(filteredForkedBlocks, forkedBlock)
-					filteredForkedQCs = append(filteredForkedQCs, blockList.ForkedQC[i])
-					break
-				}
-			}
-		}
-		if len(filteredForkedBlocks) > 0 {
-			cbft.log.Debug("FilteredForkedBlocks", "number", filteredForkedBlocks[0].NumberU64(), "hash", filteredForkedBlocks[0].Hash().TerminalString())
-		}
-
-		// Execution forked block.
-		for _, forkedBlock := range filteredForkedBlocks {
-			if forkedBlock.NumberU64() != parentBlock.NumberU64() {
-				cbft.log.Error("Invalid forked block", "lastParentNumber", parentBlock.NumberU64(), "forkedBlockNumber", forkedBlock.NumberU64())
-				break
-			}
-			//for _, block := range blockList.Blocks {
-			//	if block.Hash() == forkedBlock.ParentHash() && block.NumberU64() == forkedBlock.NumberU64()-1 {
-			//		forkedParentBlock = block
-			//		break
-			//	}
-			//}
-			//if forkedParentBlock != nil {
-			//	break
-			//}
-		}
-
-		// Verify forked block and execute.
-		for i, forkedBlock := range filteredForkedBlocks {
-			if asyncCallErr != nil {
-				return
-			}
-			parentBlock := cbft.blockTree.FindBlockByHash(forkedBlock.ParentHash())
-			if parentBlock == nil {
-				cbft.log.Debug("Response forked block is error", "blockHash", forkedBlock.Hash(), "blockNumber", forkedBlock.NumberU64())
-				return
-			}
-			//if forkedParentBlock == nil || forkedBlock.ParentHash() != forkedParentBlock.Hash() {
-			//	cbft.log.Debug("Response forked block's is error",
-			//		"blockHash", forkedBlock.Hash(), "blockNumber", forkedBlock.NumberU64(),
-			//		"parentHash", parentBlock.Hash(), "parentNumber", parentBlock.NumberU64())
-			//	return
-			//}
-
-			if err := cbft.blockCacheWriter.Execute(forkedBlock, parentBlock); err != nil {
-				cbft.log.Error("Execute forked block failed", "hash", forkedBlock.Hash(), "number", forkedBlock.NumberU64(), "error", err)
-				return
-			}
-			wg.Add(1)
-
-			cbft.asyncCallCh <- func() {
-				if err := cbft.verifyPrepareQC(forkedBlock.NumberU64(), forkedBlock.Hash(), blockList.ForkedQC[i]); err != nil {
-					cbft.log.Error("Verify forked block prepare qc failed", "hash", forkedBlock.Hash(), "number", forkedBlock.NumberU64(), "error", err)
-					asyncCallErr = err
-					wg.Done()
-					return
-				}
-				if err := cbft.OnInsertQCBlock([]*types.Block{forkedBlock}, []*ctypes.QuorumCert{blockList.ForkedQC[i]}); err != nil {
-					cbft.log.Error("Insert forked block failed", "error", err)
-					asyncCallErr = err
-				}
-				wg.Done()
-			}
-			wg.Wait()
+		if err := cbft.OnQCBlockList(id, blockList, parentBlock); err != nil {
+			cbft.log.Debug("Handle QCBlockList failed", "peer", id, "error", err)
 		}
 	}
 
@@ -353,6 +237,153 @@ func (cbft *Cbft) OnGetQCBlockList(id string, msg *protocols.GetQCBlockList) err
 	return nil
 }
 
+// OnQCBlockList handles the QCBlockList responded by the peer for a fetch
+// starting at parent. The signatures of all qcs are verified in one batch
+// before the blocks are executed and inserted in order.
+func (cbft *Cbft) OnQCBlockList(id string, blockList *protocols.QCBlockList, parentBlock *types.Block) error {
+	if len(blockList.Blocks) != len(blockList.QC) || len(blockList.ForkedBlocks) != len(blockList.ForkedQC) {
+		return fmt.Errorf("mismatched QCBlockList, blocks:%d, qcs:%d, forkedBlocks:%d, forkedQCs:%d",
+			len(blockList.Blocks), len(blockList.QC), len(blockList.ForkedBlocks), len(blockList.ForkedQC))
+	}
+
+	var wg sync.WaitGroup
+	var asyncCallErr error
+
+	// Verify the signatures of all qcs in one batch, the qcs that fail or can not
+	// be verified now fall back to verify one by one.
+	var verified map[*ctypes.QuorumCert]struct{}
+	wg.Add(1)
+	cbft.asyncCallCh <- func() {
+		defer wg.Done()
+		verified = cbft.batchVerifyPrepareQCs(append(append([]*ctypes.QuorumCert{}, blockList.QC...), blockList.ForkedQC...))
+	}
+	wg.Wait()
+	verifyPrepareQC := func(block *types.Block, qc *ctypes.QuorumCert) error {
+		if _, ok := verified[qc]; ok {
+			return cbft.validatePrepareQC(block.NumberU64(), block.Hash(), qc)
+		}
+		return cbft.verifyPrepareQC(block.NumberU64(), block.Hash(), qc)
+	}
+
+	// Handle block
+	for i, block := range blockList.Blocks {
+		if asyncCallErr != nil {
+			return asyncCallErr
+		}
+		if block.ParentHash() != parentBlock.Hash() {
+			cbft.log.Debug("Response block is error", "blockHash", block.Hash(), "blockNumber", block.NumberU64(), "parentHash", parentBlock.Hash(), "parentNumber", parentBlock.NumberU64())
+			return fmt.Errorf("response block is not linked to parent, number:%d", block.NumberU64())
+		}
+		if err := cbft.blockCacheWriter.Execute(block, parentBlock); err != nil {
+			cbft.log.Error("Execute block failed", "hash", block.Hash(), "number", block.NumberU64(), "error", err)
+			return err
+		}
+		wg.Add(1)
+
+		// Update the results to the CBFT state machine
+		cbft.asyncCallCh <- func() {
+			if err := verifyPrepareQC(block, blockList.QC[i]); err != nil {
+				cbft.log.Error("Verify block prepare qc failed", "hash", block.Hash(), "number", block.NumberU64(), "error", err)
+				asyncCallErr = err
+				wg.Done()
+				return
+			}
+			if err := cbft.OnInsertQCBlock([]*types.Block{block}, []*ctypes.QuorumCert{blockList.QC[i]}); err != nil {
+				cbft.log.Warn("Insert block failed", "error", err)
+				asyncCallErr = err
+			}
+			wg.Done()
+		}
+		wg.Wait()
+		parentBlock = block
+	}
+
+	// Handle forked block
+	if len(blockList.ForkedBlocks) == 0 {
+		cbft.log.Trace("No forked block need to handle")
+		return asyncCallErr
+	}
+	// Remove local forks that already exist.
+	filteredForkedBlocks := make([]*types.Block, 0)
+	filteredForkedQCs := make([]*ctypes.QuorumCert, 0)
+	//localForkedBlocks, _ := cbft.blockTree.FindForkedBlocksAndQCs(parentBlock.Hash(), parentBlock.NumberU64())
+	localForkedBlocks, _ := cbft.blockTree.FindBlocksAndQCs(parentBlock.NumberU64())
+
+	if len(localForkedBlocks) > 0 {
+		cbft.log.Debug("LocalForkedBlocks", "number", localForkedBlocks[0].NumberU64(), "hash", localForkedBlocks[0].Hash().TerminalString())
+	}
+
+	for i, forkedBlock := range blockList.ForkedBlocks {
+		for _, localForkedBlock := range localForkedBlocks {
+			if forkedBlock.NumberU64() == localForkedBlock.NumberU64() && forkedBlock.Hash() != localForkedBlock.Hash() {
+				filteredForkedBlocks = append(filteredForkedBlocks, forkedBlock)
+				filteredForkedQCs = append(filteredForkedQCs, blockList.ForkedQC[i])
+				break
+			}
+		}
+	}
+	if len(filteredForkedBlocks) > 0 {
+		cbft.log.Debug("FilteredForkedBlocks", "number", filteredForkedBlocks[0].NumberU64(), "hash", filteredForkedBlocks[0].Hash().TerminalString())
+	}
+
+	// Execution forked block.
+	for _, forkedBlock := range filteredForkedBlocks {
+		if forkedBlock.NumberU64() != parentBlock.NumberU64() {
+			cbft.log.Error("Invalid forked block", "lastParentNumber", parentBlock.NumberU64(), "forkedBlockNumber", forkedBlock.NumberU64())
+			break
+		}
+		//for _, block := range blockList.Blocks {
+		//	if block.Hash() == forkedBlock.ParentHash() && block.NumberU64() == forkedBlock.NumberU64()-1 {
+		//		forkedParentBlock = block
+		//		break
+		//	}
+		//}
+		//if forkedParentBlock != nil {
+		//	break
+		//}
+	}
+
+	// Verify forked block and execute.
+	for i, forkedBlock := range filteredForkedBlocks {
+		if asyncCallErr != nil {
+			return asyncCallErr
+		}
+		parentBlock := cbft.blockTree.FindBlockByHash(forkedBlock.ParentHash())
+		if parentBlock == nil {
+			cbft.log.Debug("Response forked block is error", "blockHash", forkedBlock.Hash(), "blockNumber", forkedBlock.NumberU64())
+			return fmt.Errorf("response forked block has no parent, number:%d", forkedBlock.NumberU64())
+		}
+		//if forkedParentBlock == nil || forkedBlock.ParentHash() != forkedParentBlock.Hash() {
+		//	cbft.log.Debug("Response forked block's is error",
+		//		"blockHash", forkedBlock.Hash(), "blockNumber", forkedBlock.NumberU64(),
+		//		"parentHash", parentBlock.Hash(), "parentNumber", parentBlock.NumberU64())
+		//	return
+		//}
+
+		if err := cbft.blockCacheWriter.Execute(forkedBlock, parentBlock); err != nil {
+			cbft.log.Error("Execute forked block failed", "hash", forkedBlock.Hash(), "number", forkedBlock.NumberU64(), "error", err)
+			return err
+		}
+		wg.Add(1)
+
+		cbft.asyncCallCh <- func() {
+			if err := verifyPrepareQC(forkedBlock, blockList.ForkedQC[i]); err != nil {
+				cbft.log.Error("Verify forked block prepare qc failed", "hash", forkedBlock.Hash(), "number", forkedBlock.NumberU64(), "error", err)
+				asyncCallErr = err
+				wg.Done()
+				return
+			}
+			if err := cbft.OnInsertQCBlock([]*types.Block{forkedBlock}, []*ctypes.QuorumCert{blockList.ForkedQC[i]}); err != nil {
+				cbft.log.Error("Insert forked block failed", "error", err)
+				asyncCallErr = err
+			}
+			wg.Done()
+		}
+		wg.Wait()
+	}
+	return asyncCallErr
+}
+
 // OnGetPrepareVote is responsible for processing the business logic
 // of the GetPrepareVote message. It will synchronously return a
 // PrepareVotes message to the sender.
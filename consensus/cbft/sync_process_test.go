@@ -685,4 +685,110 @@ func TestCbft_BlockExists(t *testing.T) {
 			assert.True(t, strings.Contains(err.Error(), v.err))
 		}
 	}
-}
\ No newline at end of file
+}
+// makeQCBlocks seals n blocks on nodes[0] and collects the votes of nodes[1]
+// and nodes[2] for them, nodes[3] stays at the genesis block.
+func makeQCBlocks(t *testing.T, nodes []*TestCBFT, n int) ([]*types.Block, []*types2.QuorumCert) {
+	result := make(chan *types.Block, 1)
+	complete := make(chan struct{}, 1)
+	blocks := make([]*types.Block, 0, n)
+	qcs := make([]*types2.QuorumCert, 0, n)
+	parent := nodes[0].chain.Genesis()
+	for i := 0; i < n; i++ {
+		block := NewBlockWithSign(parent.Hash(), parent.NumberU64()+1, nodes[0])
+		nodes[0].engine.OnSeal(block, result, nil, complete)
+		<-complete
+
+		_, qc := nodes[0].engine.blockTree.FindBlockAndQC(parent.Hash(), parent.NumberU64())
+		b := <-result
+		for j := 1; j < 3; j++ {
+			msg := &protocols.PrepareVote{
+				Epoch:          nodes[0].engine.state.Epoch(),
+				ViewNumber:     nodes[0].engine.state.ViewNumber(),
+				BlockIndex:     uint32(i),
+				BlockHash:      b.Hash(),
+				BlockNumber:    b.NumberU64(),
+				ValidatorIndex: uint32(j),
+				ParentQC:       qc,
+			}
+			pb := nodes[0].engine.state.PrepareBlockByIndex(uint32(i))
+			assert.NotNil(t, pb)
+			execute := make(chan uint32, 1)
+			nodes[j].engine.executeFinishHook = func(index uint32) {
+				execute <- index
+			}
+			assert.Nil(t, nodes[j].engine.OnPrepareBlock("id", pb))
+			<-execute
+			assert.Nil(t, nodes[j].engine.signMsgByBls(msg))
+			assert.Nil(t, nodes[0].engine.OnPrepareVote("id", msg), fmt.Sprintf("number:%d", b.NumberU64()))
+		}
+		block, qc = nodes[0].engine.blockTree.FindBlockAndQC(b.Hash(), b.NumberU64())
+		assert.NotNil(t, qc)
+		blocks = append(blocks, block)
+		qcs = append(qcs, qc)
+		parent = b
+	}
+	return blocks, qcs
+}
+
+func TestCbft_OnGetQCBlockList(t *testing.T) {
+	pk, sk, cbftnodes := GenerateCbftNode(4)
+	nodes := make([]*TestCBFT, 0)
+	for i := 0; i < 4; i++ {
+		node := MockNode(pk[i], sk[i], cbftnodes, 200000, 10)
+		assert.Nil(t, node.Start())
+		nodes = append(nodes, node)
+	}
+	makeQCBlocks(t, nodes, 3)
+	assert.Equal(t, uint64(3), nodes[0].engine.state.HighestQCBlock().NumberU64())
+
+	response := make(chan *protocols.QCBlockList, 1)
+	network.SetSendQueueHook(nodes[0].engine.network, func(msg *types2.MsgPackage) {
+		if blockList, ok := msg.Message().(*protocols.QCBlockList); ok {
+			response <- blockList
+		}
+	})
+	genesis := nodes[0].chain.Genesis()
+	assert.Nil(t, nodes[0].engine.OnGetQCBlockList("id", &protocols.GetQCBlockList{BlockHash: genesis.Hash(), BlockNumber: genesis.NumberU64()}))
+
+	var blockList *protocols.QCBlockList
+	select {
+	case blockList = <-response:
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+	assert.Len(t, blockList.Blocks, 3)
+
+	// All qcs of the response pass the batch verification of the requester.
+	verified := nodes[3].engine.batchVerifyPrepareQCs(blockList.QC)
+	assert.Len(t, verified, 3)
+
+	assert.Nil(t, nodes[3].engine.OnQCBlockList("id", blockList, genesis))
+	assert.Equal(t, uint64(3), nodes[3].engine.state.HighestQCBlock().NumberU64())
+}
+
+func TestCbft_OnQCBlockList(t *testing.T) {
+	pk, sk, cbftnodes := GenerateCbftNode(4)
+	nodes := make([]*TestCBFT, 0)
+	for i := 0; i < 4; i++ {
+		node := MockNode(pk[i], sk[i], cbftnodes, 200000, 10)
+		assert.Nil(t, node.Start())
+		nodes = append(nodes, node)
+	}
+	blocks, qcs := makeQCBlocks(t, nodes, 3)
+
+	// The third qc carries the signature of the second, the batch fails
+	// and the qc is rejected by the fallback verification.
+	bad := *qcs[2]
+	bad.Signature = qcs[1].Signature
+	blockList := &protocols.QCBlockList{Blocks: blocks, QC: []*types2.QuorumCert{qcs[0], qcs[1], &bad}}
+
+	verified := nodes[3].engine.batchVerifyPrepareQCs(blockList.QC)
+	assert.Len(t, verified, 2)
+
+	assert.NotNil(t, nodes[3].engine.OnQCBlockList("id", blockList, nodes[3].chain.Genesis()))
+	assert.Equal(t, uint64(2), nodes[3].engine.state.HighestQCBlock().NumberU64())
+
+	// Mismatched blocks and qcs are rejected before any block is executed.
+	assert.NotNil(t, nodes[3].engine.OnQCBlockList("id", &protocols.QCBlockList{Blocks: blocks, QC: qcs[:2]}, nodes[3].chain.Genesis()))
+}
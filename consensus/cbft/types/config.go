@@ -31,6 +31,10 @@ type OptionsConfig struct {
 	BlsPriKey  *bls.SecretKey    `json:"-"`
 	WalMode    bool              `json:"walMode"`
 
+	// NextBlsPriKey is the rotated bls key of the node, it is used
+	// once the validators of the current epoch carry its public key.
+	NextBlsPriKey *bls.SecretKey `json:"-"`
+
 	PeerMsgQueueSize  uint64 `json:"peerMsgQueueSize"`
 	EvidenceDir       string `json:"evidenceDir"`
 	MaxPingLatency    int64  `json:"maxPingLatency"`    // maxPingLatency is the time in milliseconds between Ping and Pong
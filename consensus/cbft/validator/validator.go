@@ -634,6 +634,35 @@ func (vp *ValidatorPool) VerifyAggSig(epoch uint64, validatorIndexes []uint32, m
 	return sig.Verify(&pub, string(msg))
 }
 
+// AggSig is an aggregated signature of the validators in a bit array.
+type AggSig struct {
+	Epoch     uint64
+	VSet      *utils.BitArray
+	Msg       []byte
+	Signature []byte
+}
+
+// aggregatePubKeyByBA returns the aggregated public key of the validators in the bit array.
+func (vp *ValidatorPool) aggregatePubKeyByBA(epoch uint64, vSet *utils.BitArray) (*bls.PublicKey, error) {
+	vp.lock.RLock()
+	validators := vp.currentValidators
+	if vp.epochToBlockNumber(epoch) <= vp.switchPoint {
+		validators = vp.prevValidators
+	}
+
+	nodeList, err := validators.NodeListByBitArray(vSet)
+	vp.lock.RUnlock()
+	if err != nil || len(nodeList) == 0 {
+		return nil, fmt.Errorf("not found validators: %v", err)
+	}
+
+	pub := *nodeList[0].BlsPubKey
+	for i := 1; i < len(nodeList); i++ {
+		pub.Add(nodeList[i].BlsPubKey)
+	}
+	return &pub, nil
+}
+
 func (vp *ValidatorPool) VerifyAggSigByBA(epoch uint64, vSet *utils.BitArray, msg, signature []byte) error {
 	vp.lock.RLock()
 	validators := vp.currentValidators
@@ -667,6 +696,35 @@ func (vp *ValidatorPool) VerifyAggSigByBA(epoch uint64, vSet *utils.BitArray, ms
 	return nil
 }
 
+// BatchVerifyAggSigByBA verifies the aggregated signatures in one multi-pairing.
+// If the batch fails, the signatures are verified one by one to find the bad one,
+// whose index is returned with the error. The index is -1 if all are valid.
+func (vp *ValidatorPool) BatchVerifyAggSigByBA(sigs []*AggSig) (int, error) {
+	batch := bls.NewSignatureBatch()
+	for i, s := range sigs {
+		pub, err := vp.aggregatePubKeyByBA(s.Epoch, s.VSet)
+		if err != nil {
+			return i, err
+		}
+		var sig bls.Sign
+		if err := sig.Deserialize(s.Signature); err != nil {
+			return i, err
+		}
+		batch.Add(&sig, pub, s.Msg)
+	}
+	if batch.Verify() {
+		return -1, nil
+	}
+
+	log.Debug("Batch verify signatures fail, fallback to verify one by one", "total", len(sigs))
+	for i, s := range sigs {
+		if err := vp.VerifyAggSigByBA(s.Epoch, s.VSet, s.Msg, s.Signature); err != nil {
+			return i, err
+		}
+	}
+	return -1, nil
+}
+
 func (vp *ValidatorPool) epochToBlockNumber(epoch uint64) uint64 {
 	if epoch > vp.epoch {
 		panic(fmt.Sprintf("get unknown epoch, current:%d, request:%d", vp.epoch, epoch))
@@ -677,6 +735,14 @@ func (vp *ValidatorPool) epochToBlockNumber(epoch uint64) uint64 {
 	return vp.switchPoint + 1
 }
 
+// Schedule returns the block production period and amount of the current
+// validators, zeros if they follow the cbft config.
+func (vp *ValidatorPool) Schedule() (uint64, uint32) {
+	vp.lock.RLock()
+	defer vp.lock.RUnlock()
+	return vp.currentValidators.Period, vp.currentValidators.Amount
+}
+
 func (vp *ValidatorPool) Flush(header *types.Header) error {
 	return vp.agency.Flush(header)
 }
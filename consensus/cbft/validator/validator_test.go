@@ -39,6 +39,7 @@ import (
 	"github.com/hashkey-chain/hashkey-chain/common"
 	"github.com/hashkey-chain/hashkey-chain/common/hexutil"
 	vm2 "github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/consensus/cbft/utils"
 	"github.com/hashkey-chain/hashkey-chain/consensus"
 	"github.com/hashkey-chain/hashkey-chain/core"
 	"github.com/hashkey-chain/hashkey-chain/core/types"
@@ -551,6 +552,46 @@ func TestValidatorPoolVerify(t *testing.T) {
 	copy(header.Extra[len(header.Extra)-consensus.ExtraSeal:], sigWrong[:])
 }
 
+func TestValidatorPoolBatchVerify(t *testing.T) {
+	bls.Init(bls.BLS12_381)
+
+	nodes := make([]params.CbftNode, 0)
+	secs := make([]bls.SecretKey, 5)
+	for i := range secs {
+		secs[i].SetByCSPRNG()
+		priKey, _ := crypto.GenerateKey()
+		n, _ := discover.ParseNode(fmt.Sprintf("enode://%s@127.0.0.1:%d", hex.EncodeToString(crypto.FromECDSAPub(&priKey.PublicKey)[1:]), 16789+i))
+		nodes = append(nodes, params.CbftNode{Node: *n, BlsPubKey: *secs[i].GetPublicKey()})
+	}
+	vp := NewValidatorPool(NewStaticAgency(nodes), 0, 0, nodes[0].Node.ID)
+
+	m := "test sig"
+	aggSig := secs[0].Sign(m)
+	vSet := utils.NewBitArray(5)
+	vSet.SetIndex(0, true)
+	for i := 1; i < 4; i++ {
+		aggSig.Add(secs[i].Sign(m))
+		vSet.SetIndex(uint32(i), true)
+	}
+	m1 := "test sig 1"
+	sig4 := secs[4].Sign(m1)
+	vSet1 := utils.NewBitArray(5)
+	vSet1.SetIndex(4, true)
+
+	sigs := []*AggSig{
+		{Epoch: 0, VSet: vSet, Msg: []byte(m), Signature: aggSig.Serialize()},
+		{Epoch: 0, VSet: vSet1, Msg: []byte(m1), Signature: sig4.Serialize()},
+	}
+	bad, err := vp.BatchVerifyAggSigByBA(sigs)
+	assert.Nil(t, err)
+	assert.Equal(t, -1, bad)
+
+	sigs = append(sigs, &AggSig{Epoch: 0, VSet: vSet1, Msg: []byte(m), Signature: sig4.Serialize()})
+	bad, err = vp.BatchVerifyAggSigByBA(sigs)
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, bad)
+}
+
 type mockAgency struct {
 	consensus.Agency
 
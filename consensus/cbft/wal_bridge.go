@@ -352,7 +352,7 @@ func (cbft *Cbft) recoveryChainStateProcess(stateType uint16, s *protocols.State
 		cbft.tryWalChangeView(cbft.state.Epoch()+1, state.DefaultViewNumber, s.Block, s.QuorumCert, nil)
 		return
 	}
-	if s.QuorumCert.BlockIndex+1 == cbft.config.Sys.Amount {
+	if s.QuorumCert.BlockIndex+1 == cbft.state.Amount() {
 		cbft.log.Info("QCBlock is the last index on the view, change view", "state", s.String(), "view", cbft.state.ViewString())
 		cbft.tryWalChangeView(cbft.state.Epoch(), cbft.state.ViewNumber()+1, s.Block, s.QuorumCert, nil)
 		return
@@ -201,3 +201,10 @@ type Bft interface {
 	// NodeID is temporary.
 	NodeID() discover.NodeID
 }
+
+// BatchQCVerifier is implemented by the engines that can verify the
+// signatures of the qcs carried by a batch of blocks at once, before
+// the blocks are inserted one by one.
+type BatchQCVerifier interface {
+	BatchVerifyQCs(blocks types.Blocks)
+}
@@ -0,0 +1,247 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"time"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/core/rawdb"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/core/vm"
+	"github.com/hashkey-chain/hashkey-chain/crypto"
+	"github.com/hashkey-chain/hashkey-chain/ethdb"
+	"github.com/hashkey-chain/hashkey-chain/log"
+)
+
+// addressTxEntries collects the address index entries of the transactions in
+// the block: the sender, the recipient and the created contract of every
+// transaction, the calls of the PPOS system contracts are flagged.
+func addressTxEntries(block *types.Block, signer types.Signer) map[common.Address][]rawdb.AddressTxEntry {
+	entries := make(map[common.Address][]rawdb.AddressTxEntry)
+	for i, tx := range block.Transactions() {
+		roles := make(map[common.Address]uint8)
+		var ppos uint8
+		if to := tx.To(); to != nil {
+			if _, ok := vm.PlatONPrecompiledContracts[*to]; ok {
+				ppos = rawdb.AddressTxPpos
+			}
+			roles[*to] |= rawdb.AddressTxTo | ppos
+		}
+		if from, err := types.Sender(signer, tx); err != nil {
+			log.Warn("Failed to recover the sender of the indexed transaction", "number", block.NumberU64(), "hash", tx.Hash(), "err", err)
+		} else {
+			roles[from] |= rawdb.AddressTxFrom | ppos
+			if tx.To() == nil {
+				roles[crypto.CreateAddress(from, tx.Nonce())] |= rawdb.AddressTxCreate
+			}
+		}
+		for addr, role := range roles {
+			entries[addr] = append(entries[addr], rawdb.AddressTxEntry{
+				BlockNumber: block.NumberU64(),
+				Index:       uint32(i),
+				Hash:        tx.Hash(),
+				Roles:       role,
+			})
+		}
+	}
+	return entries
+}
+
+// writeAddressIndex stores the address index entries of the block.
+func writeAddressIndex(db ethdb.KeyValueWriter, block *types.Block, signer types.Signer) {
+	for addr, entries := range addressTxEntries(block, signer) {
+		for _, entry := range entries {
+			rawdb.WriteAddressTxEntry(db, addr, entry)
+		}
+	}
+}
+
+// deleteAddressIndex removes the address index entries of the block.
+func deleteAddressIndex(db ethdb.KeyValueWriter, block *types.Block, signer types.Signer) {
+	for addr, entries := range addressTxEntries(block, signer) {
+		for _, entry := range entries {
+			rawdb.DeleteAddressTxEntry(db, addr, entry.BlockNumber, entry.Index)
+		}
+	}
+}
+
+// indexAddresses writes the address index of the canonical blocks in the
+// range [from, to), walking down from the newest one. If moveTail is set the
+// index tail follows the progress so that an interrupted backfill resumes
+// where it stopped. It reports whether the whole range is indexed.
+func (bc *BlockChain) indexAddresses(from, to uint64, moveTail bool) bool {
+	var (
+		batch  = bc.db.NewBatch()
+		start  = time.Now()
+		logged = time.Now()
+		blocks = 0
+		number = to
+	)
+loop:
+	for ; number > from; number-- {
+		select {
+		case <-bc.quit:
+			break loop
+		default:
+		}
+		block := bc.GetBlockByNumber(number - 1)
+		if block == nil {
+			log.Warn("Missing block during the address indexing", "number", number-1)
+			break
+		}
+		writeAddressIndex(batch, block, bc.addrSigner)
+		blocks++
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if moveTail {
+				rawdb.WriteAddressTxIndexTail(batch, number-1)
+			}
+			if err := batch.Write(); err != nil {
+				log.Crit("Failed writing batch to db", "error", err)
+			}
+			batch.Reset()
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Indexing transactions by address", "blocks", blocks, "block", number-1, "tail", from, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if moveTail && number == from {
+		rawdb.WriteAddressTxIndexTail(batch, from)
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed writing batch to db", "error", err)
+	}
+	log.Info("Indexed transactions by address", "blocks", blocks, "from", from, "to", to, "elapsed", common.PrettyDuration(time.Since(start)))
+	return number == from
+}
+
+// unindexAddresses removes the address index of the canonical blocks in the
+// range [from, to) and moves the index tail to the oldest remaining block.
+func (bc *BlockChain) unindexAddresses(from, to uint64) {
+	var (
+		batch  = bc.db.NewBatch()
+		start  = time.Now()
+		blocks = 0
+		number = from
+	)
+loop:
+	for ; number < to; number++ {
+		select {
+		case <-bc.quit:
+			break loop
+		default:
+		}
+		if block := bc.GetBlockByNumber(number); block != nil {
+			deleteAddressIndex(batch, block, bc.addrSigner)
+			blocks++
+		}
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			rawdb.WriteAddressTxIndexTail(batch, number+1)
+			if err := batch.Write(); err != nil {
+				log.Crit("Failed writing batch to db", "error", err)
+			}
+			batch.Reset()
+		}
+	}
+	rawdb.WriteAddressTxIndexTail(batch, number)
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed writing batch to db", "error", err)
+	}
+	log.Info("Unindexed transactions by address", "blocks", blocks, "from", from, "to", to, "elapsed", common.PrettyDuration(time.Since(start)))
+}
+
+// maintainAddressIndex is responsible for the construction and deletion of
+// the transaction index by address.
+//
+// The live blocks are indexed on insertion, this routine fills the blocks
+// the index is missing (the history before the index was enabled, the blocks
+// imported by fast sync or while the index was disabled) and removes the
+// blocks below the `txlookuplimit` window the same way as the tx lookups.
+func (bc *BlockChain) maintainAddressIndex() {
+	defer bc.wg.Done()
+
+	// indexBlocks moves the indexed range [tail, head] to the chain state
+	indexBlocks := func(current uint64, done chan struct{}) {
+		defer func() { done <- struct{}{} }()
+
+		var limitTail uint64
+		if bc.txLookupLimit != 0 && current >= bc.txLookupLimit {
+			limitTail = current - bc.txLookupLimit + 1
+		}
+		tail, head := rawdb.ReadAddressTxIndexTail(bc.db), rawdb.ReadAddressTxIndexHead(bc.db)
+		if tail == nil || head == nil {
+			// The index is built for the first time, backfill the whole window
+			rawdb.WriteAddressTxIndexHead(bc.db, current)
+			bc.indexAddresses(limitTail, current+1, true)
+			return
+		}
+		// Fill the blocks above the indexed head, the ones already indexed
+		// on insertion are rewritten which is harmless
+		if *head < current {
+			from := *head + 1
+			if from < limitTail {
+				from = limitTail
+			}
+			if !bc.indexAddresses(from, current+1, false) {
+				return
+			}
+		}
+		if *head != current {
+			rawdb.WriteAddressTxIndexHead(bc.db, current)
+		}
+		// Move the tail to the lookup window
+		if limitTail < *tail {
+			bc.indexAddresses(limitTail, *tail, true)
+		} else if limitTail > *tail {
+			bc.unindexAddresses(*tail, limitTail)
+		}
+	}
+	var (
+		done   chan struct{}                  // Non-nil if background indexing routine is active.
+		headCh = make(chan ChainHeadEvent, 1) // Buffered to avoid locking up the event feed
+	)
+	sub := bc.SubscribeChainHeadEvent(headCh)
+	if sub == nil {
+		return
+	}
+	defer sub.Unsubscribe()
+
+	done = make(chan struct{})
+	go indexBlocks(bc.CurrentBlock().NumberU64(), done)
+	for {
+		select {
+		case head := <-headCh:
+			if done == nil {
+				done = make(chan struct{})
+				go indexBlocks(head.Block.NumberU64(), done)
+			}
+		case <-done:
+			done = nil
+		case <-bc.quit:
+			if done != nil {
+				<-done
+			}
+			return
+		}
+	}
+}
+
+// AddressIndexEnabled reports whether the transactions are indexed by address.
+func (bc *BlockChain) AddressIndexEnabled() bool {
+	return bc.cacheConfig.AddressIndex
+}
@@ -0,0 +1,175 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	cvm "github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/consensus"
+	"github.com/hashkey-chain/hashkey-chain/core/rawdb"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/core/vm"
+	"github.com/hashkey-chain/hashkey-chain/crypto"
+	"github.com/hashkey-chain/hashkey-chain/params"
+	"github.com/hashkey-chain/hashkey-chain/trie"
+)
+
+func TestAddressTxEntries(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		from    = crypto.PubkeyToAddress(key.PublicKey)
+		to      = common.BytesToAddress([]byte{0x11})
+		signer  = types.NewEIP155Signer(big.NewInt(1))
+		created = crypto.CreateAddress(from, 1)
+	)
+	sign := func(tx *types.Transaction) *types.Transaction {
+		signed, err := types.SignTx(tx, signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	txs := types.Transactions{
+		sign(types.NewTransaction(0, to, big.NewInt(1), params.TxGas, big.NewInt(1), nil)),
+		sign(types.NewContractCreation(1, big.NewInt(0), 100000, big.NewInt(1), []byte{0x00})),
+		sign(types.NewTransaction(2, cvm.StakingContractAddr, big.NewInt(0), 100000, big.NewInt(1), []byte{0x01})),
+		sign(types.NewTransaction(3, from, big.NewInt(1), params.TxGas, big.NewInt(1), nil)),
+	}
+	block := types.NewBlock(&types.Header{Number: big.NewInt(7)}, txs, nil, new(trie.Trie))
+
+	entries := addressTxEntries(block, signer)
+	want := map[common.Address][]uint8{
+		from:                    {rawdb.AddressTxFrom, rawdb.AddressTxFrom, rawdb.AddressTxFrom | rawdb.AddressTxPpos, rawdb.AddressTxFrom | rawdb.AddressTxTo},
+		to:                      {rawdb.AddressTxTo},
+		created:                 {rawdb.AddressTxCreate},
+		cvm.StakingContractAddr: {rawdb.AddressTxTo | rawdb.AddressTxPpos},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("indexed addresses mismatch: have %d, want %d", len(entries), len(want))
+	}
+	for addr, roles := range want {
+		if len(entries[addr]) != len(roles) {
+			t.Fatalf("%x: entries count mismatch: have %d, want %d", addr, len(entries[addr]), len(roles))
+		}
+		for i, entry := range entries[addr] {
+			if entry.Roles != roles[i] || entry.BlockNumber != 7 || entry.Hash != txs[entry.Index].Hash() {
+				t.Errorf("%x: entry #%d mismatch: have %+v, want roles %d", addr, i, entry, roles[i])
+			}
+		}
+	}
+}
+
+// Tests that the history of an existing chain is backfilled once the address
+// index is enabled, and that the blocks out of the lookup window are unindexed.
+func TestAddressIndexBackfill(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		from    = crypto.PubkeyToAddress(key.PublicKey)
+		to      = common.BytesToAddress([]byte{0x11})
+		signer  = types.NewEIP155Signer(big.NewInt(1))
+		db      = rawdb.NewMemoryDatabase()
+		gspec   = &Genesis{Config: params.TestChainConfig, Alloc: GenesisAlloc{from: {Balance: big.NewInt(1000000000000000)}}}
+		genesis = gspec.MustCommit(db)
+	)
+	blocks, _ := GenerateChain(gspec.Config, genesis, consensus.NewFaker(), db, 4, func(i int, block *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(from), to, big.NewInt(1), params.TxGas, big.NewInt(1), nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block.AddTx(tx)
+	})
+	// Write the chain without the address index
+	for _, block := range blocks {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteTxLookupEntriesByBlock(db, block)
+	}
+	rawdb.WriteHeadBlockHash(db, blocks[len(blocks)-1].Hash())
+	rawdb.WriteHeadHeaderHash(db, blocks[len(blocks)-1].Hash())
+	if entries := rawdb.ReadAddressTxEntries(db, from, 0, 10); len(entries) != 0 {
+		t.Fatalf("chain indexed before the backfill: %v", entries)
+	}
+
+	wait := func(tail, head uint64) {
+		for i := 0; i < 100; i++ {
+			if have := rawdb.ReadAddressTxIndexTail(db); have != nil && *have == tail {
+				if have := rawdb.ReadAddressTxIndexHead(db); have != nil && *have == head {
+					return
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("address index not moved to [%d, %d]", tail, head)
+	}
+	check := func(addr common.Address, numbers ...uint64) {
+		entries := rawdb.ReadAddressTxEntries(db, addr, 0, 10)
+		if len(entries) != len(numbers) {
+			t.Fatalf("%x: entries count mismatch: have %d, want %d", addr, len(entries), len(numbers))
+		}
+		for i, entry := range entries {
+			if entry.BlockNumber != numbers[i] || entry.Hash != blocks[numbers[i]-1].Transactions()[0].Hash() {
+				t.Fatalf("%x: entry #%d mismatch: have %+v, want block %d", addr, i, entry, numbers[i])
+			}
+		}
+	}
+	cacheConfig := &CacheConfig{TrieCleanLimit: 256, TrieDirtyLimit: 256, TrieTimeLimit: 5 * time.Minute, BodyCacheLimit: 256,
+		BlockCacheLimit: 256, MaxFutureBlocks: 256, BadBlockLimit: 10, TriesInMemory: 128, DBGCInterval: 86400, DBGCTimeout: time.Minute, AddressIndex: true}
+
+	newChain := func(limit uint64) *BlockChain {
+		chain, err := NewBlockChain(db, cacheConfig, gspec.Config, consensus.NewFaker(), vm.Config{}, nil, &limit)
+		if err != nil {
+			t.Fatalf("failed to create the chain: %v", err)
+		}
+		return chain
+	}
+	// Backfill all the blocks
+	chain := newChain(0)
+	wait(0, 4)
+	chain.Stop()
+	check(from, 4, 3, 2, 1)
+	check(to, 4, 3, 2, 1)
+
+	// Unindex the blocks out of the lookup window
+	chain = newChain(2)
+	wait(3, 4)
+	chain.Stop()
+	check(from, 4, 3)
+	check(to, 4, 3)
+
+	// Backfill the unindexed blocks once the window is lifted
+	chain = newChain(0)
+	wait(0, 4)
+	chain.Stop()
+	check(from, 4, 3, 2, 1)
+	check(to, 4, 3, 2, 1)
+
+	// Resume an interrupted backfill from the recorded tail
+	for _, block := range blocks[:2] {
+		deleteAddressIndex(db, block, signer)
+	}
+	rawdb.WriteAddressTxIndexTail(db, 3)
+	check(from, 4, 3)
+	chain = newChain(0)
+	wait(0, 4)
+	chain.Stop()
+	check(from, 4, 3, 2, 1)
+	check(to, 4, 3, 2, 1)
+}
@@ -100,6 +100,8 @@ type CacheConfig struct {
 	DBGCTimeout  time.Duration
 	DBGCMpt      bool
 	DBGCBlock    int
+
+	AddressIndex bool // Whether to index the transactions by address
 }
 
 // mining related configuration
@@ -177,12 +179,17 @@ type BlockChain struct {
 	//  * nil: disable tx reindexer/deleter, but still index new blocks
 	txLookupLimit uint64
 
+	// addrSigner recovers the senders of the transactions indexed by address,
+	// it is only set if the address index is enabled.
+	addrSigner types.Signer
+
 	hc            *HeaderChain
 	rmLogsFeed    event.Feed
 	chainFeed     event.Feed
 	chainSideFeed event.Feed
 	chainHeadFeed event.Feed
 	logsFeed      event.Feed
+	balanceFeed   event.Feed
 	scope         event.SubscriptionScope
 	genesisBlock  *types.Block
 
@@ -271,6 +278,10 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 		badBlocks:      badBlocks,
 	}
 
+	if cacheConfig.AddressIndex {
+		bc.addrSigner = types.MakeSigner(chainConfig, chainConfig.PIP7ChainID != nil)
+	}
+
 	bc.SetValidator(NewBlockValidator(chainConfig, bc, engine))
 	bc.SetProcessor(NewParallelStateProcessor(chainConfig, bc, engine))
 	//bc.SetProcessor(NewStateProcessor(chainConfig, bc, engine))
@@ -371,6 +382,10 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 		bc.wg.Add(1)
 		go bc.maintainTxIndex(txIndexBlock)
 	}
+	if bc.cacheConfig.AddressIndex {
+		bc.wg.Add(1)
+		go bc.maintainAddressIndex()
+	}
 
 	// If periodic cache journal is required, spin it up.
 	if bc.cacheConfig.TrieCleanRejournal > 0 {
@@ -841,6 +856,16 @@ func (bc *BlockChain) GetBlockByNumber(number uint64) *types.Block {
 	return bc.GetBlock(hash, number)
 }
 
+// GetBalanceChangesByHash retrieves the balance changes made by the PPOS
+// plugins in a given block.
+func (bc *BlockChain) GetBalanceChangesByHash(hash common.Hash) []*types.BalanceChange {
+	number := rawdb.ReadHeaderNumber(bc.db, hash)
+	if number == nil {
+		return nil
+	}
+	return rawdb.ReadBalanceChanges(bc.db, hash, *number)
+}
+
 // GetReceiptsByHash retrieves the receipts for all transactions in a given block.
 func (bc *BlockChain) GetReceiptsByHash(hash common.Hash) types.Receipts {
 	if receipts, ok := bc.receiptsCache.Get(hash); ok {
@@ -1510,6 +1535,10 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 	// Write other block data using a batch.
 	batch := bc.db.NewBatch()
 	rawdb.WriteReceipts(batch, block.Hash(), block.NumberU64(), receipts)
+	balanceChanges := state.BalanceChanges()
+	if len(balanceChanges) > 0 {
+		rawdb.WriteBalanceChanges(batch, block.Hash(), block.NumberU64(), balanceChanges)
+	}
 
 	// If the total difficulty is higher than our known, add it to the canonical chain
 	// Second clause in the if statement reduces the vulnerability to selfish mining.
@@ -1548,6 +1577,9 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 	// Write the positional metadata for transaction/receipt lookups and preimages
 	rawdb.WriteTxLookupEntriesByBlock(batch, block)
 	rawdb.WritePreimages(batch, state.Preimages())
+	if bc.cacheConfig.AddressIndex {
+		writeAddressIndex(batch, block, bc.addrSigner)
+	}
 
 	status = CanonStatTy
 	if err := batch.Write(); err != nil {
@@ -1577,6 +1609,9 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 		if len(logs) > 0 {
 			bc.logsFeed.Send(logs)
 		}
+		if len(balanceChanges) > 0 {
+			bc.balanceFeed.Send(BalanceChangesEvent{Block: block, Changes: balanceChanges})
+		}
 		// In theory we should fire a ChainHeadEvent when we inject
 		// a canonical block, but sometimes we can insert a batch of
 		// canonicial blocks. Avoid firing too much ChainHeadEvents,
@@ -1677,6 +1712,12 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 	bc.engine.Pause()
 	defer bc.engine.Resume()
 
+	// Verify the qc signatures of the whole batch at once, InsertChain
+	// only checks the qc of each block against the verified batch.
+	if verifier, ok := bc.engine.(consensus.BatchQCVerifier); ok {
+		verifier.BatchVerifyQCs(chain)
+	}
+
 	// Peek the error for the first block to decide the directing import logic
 	it := newInsertIterator(chain, results, bc.Validator())
 	block, err := it.next()
@@ -1873,6 +1914,17 @@ func (bc *BlockChain) reorg(oldBlock, newBlock *types.Block) error {
 	} else {
 		log.Error("Impossible reorg, please file an issue", "oldnum", oldBlock.Number(), "oldhash", oldBlock.Hash(), "newnum", newBlock.Number(), "newhash", newBlock.Hash())
 	}
+	// Drop the address index of the old chain before the new chain reuses
+	// the same block numbers and transaction positions
+	if bc.cacheConfig.AddressIndex {
+		batch := bc.db.NewBatch()
+		for _, block := range oldChain {
+			deleteAddressIndex(batch, block, bc.addrSigner)
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	}
 	// Insert the new chain(except the head block(reverse order)),
 	// taking care of the proper incremental order.
 	for i := len(newChain) - 1; i >= 1; i-- {
@@ -1884,6 +1936,9 @@ func (bc *BlockChain) reorg(oldBlock, newBlock *types.Block) error {
 
 		// Write lookup entries for hash based transaction/receipt searches
 		rawdb.WriteTxLookupEntriesByBlock(bc.db, newChain[i])
+		if bc.cacheConfig.AddressIndex {
+			writeAddressIndex(bc.db, newChain[i], bc.addrSigner)
+		}
 		addedTxs = append(addedTxs, newChain[i].Transactions()...)
 	}
 	// When transactions get deleted from the database, the receipts that were
@@ -2178,6 +2233,11 @@ func (bc *BlockChain) SubscribeChainSideEvent(ch chan<- ChainSideEvent) event.Su
 	return bc.scope.Track(bc.chainSideFeed.Subscribe(ch))
 }
 
+// SubscribeBalanceChangesEvent registers a subscription of BalanceChangesEvent.
+func (bc *BlockChain) SubscribeBalanceChangesEvent(ch chan<- BalanceChangesEvent) event.Subscription {
+	return bc.scope.Track(bc.balanceFeed.Subscribe(ch))
+}
+
 // SubscribeLogsEvent registers a subscription of []*types.Log.
 func (bc *BlockChain) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription {
 	return bc.scope.Track(bc.logsFeed.Subscribe(ch))
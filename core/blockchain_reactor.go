@@ -32,6 +32,7 @@ import (
 	"github.com/hashkey-chain/hashkey-chain/core/state"
 	"github.com/hashkey-chain/hashkey-chain/core/vm"
 	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+	"github.com/hashkey-chain/hashkey-chain/x/gov"
 	"github.com/hashkey-chain/hashkey-chain/x/handler"
 	"github.com/hashkey-chain/hashkey-chain/x/staking"
 	"github.com/hashkey-chain/hashkey-chain/x/xutil"
@@ -39,6 +40,7 @@ import (
 	"github.com/hashkey-chain/hashkey-chain/core/types"
 	"github.com/hashkey-chain/hashkey-chain/event"
 	"github.com/hashkey-chain/hashkey-chain/log"
+	"github.com/hashkey-chain/hashkey-chain/metrics"
 	"github.com/hashkey-chain/hashkey-chain/x/plugin"
 	"github.com/hashkey-chain/hashkey-chain/x/xcom"
 )
@@ -152,6 +154,11 @@ func (bcr *BlockChainReactor) commit(block *types.Block) error {
 		log.Error("Failed to call snapshotdb commit on blockchain_reactor", "blockNumber", block.Number(), "blockHash", block.Hash(), "err", err)
 		return err
 	}
+	// The block schedules governed by the committed block are used from now on
+	if err := gov.LoadBlockSchedules(block.Hash()); nil != err {
+		log.Error("Failed to load the block schedules on blockchain_reactor", "blockNumber", block.Number(), "blockHash", block.Hash(), "err", err)
+		return err
+	}
 	return nil
 }
 
@@ -185,6 +192,7 @@ func (bcr *BlockChainReactor) SetPrivateKey(privateKey *ecdsa.PrivateKey) {
 		}
 		plugin.SlashInstance().SetPrivateKey(privateKey)
 		bcr.NodeId = discover.PubkeyID(&privateKey.PublicKey)
+		plugin.PPOSCollectorInstance().SetNodeID(bcr.NodeId)
 	}
 }
 
@@ -235,6 +243,9 @@ func (bcr *BlockChainReactor) BeginBlocker(header *types.Header, state xcom.Stat
 		return nil
 	}
 
+	// The balance changes made out of the transactions carry no tx hash
+	state.SetTxContext(common.ZeroHash, 0)
+
 	blockHash := common.ZeroHash
 
 	// store the sign in  header.Extra[32:97]
@@ -265,14 +276,24 @@ func (bcr *BlockChainReactor) BeginBlocker(header *types.Header, state xcom.Stat
 			hex.EncodeToString(header.ParentHash.Bytes()), "err", err)
 		return err
 	}
+	return bcr.beginBlock(blockHash, header, state, false)
+}
 
+func (bcr *BlockChainReactor) beginBlock(blockHash common.Hash, header *types.Header, state xcom.StateDB, simulated bool) error {
 	for _, pluginRule := range bcr.beginRule {
-		if plugin, ok := bcr.basePluginMap[pluginRule]; ok {
-			if err := plugin.BeginBlock(blockHash, header, state); nil != err {
+		if p, ok := bcr.basePluginMap[pluginRule]; ok {
+			if sp, ok := p.(plugin.SimulatedPlugin); ok && simulated {
+				if err := sp.BeginSimulatedBlock(blockHash, header, state); nil != err {
+					return err
+				}
+			} else if err := p.BeginBlock(blockHash, header, state); nil != err {
 				return err
 			}
 		}
 	}
+	if err := bcr.executeScheduledTxs(blockHash, header, state); nil != err {
+		return err
+	}
 
 	// This must not be deleted
 	root := state.IntermediateRoot(true)
@@ -292,6 +313,9 @@ func (bcr *BlockChainReactor) EndBlocker(header *types.Header, state xcom.StateD
 		return nil
 	}
 
+	// The balance changes made out of the transactions carry no tx hash
+	state.SetTxContext(common.ZeroHash, 0)
+
 	blockHash := common.ZeroHash
 
 	if !xutil.IsWorker(header.Extra) {
@@ -313,6 +337,10 @@ func (bcr *BlockChainReactor) EndBlocker(header *types.Header, state xcom.StateD
 		}
 	}
 
+	if metrics.Enabled {
+		plugin.PPOSCollectorInstance().Update(blockHash, header, state)
+	}
+
 	// storage the ppos k-v Hash
 	pposHash := snapshotdb.Instance().GetLastKVHash(blockHash)
 
@@ -331,6 +359,68 @@ func (bcr *BlockChainReactor) EndBlocker(header *types.Header, state xcom.StateD
 	return nil
 }
 
+// IsPPOS reports whether the validators are elected by PPOS.
+func (bcr *BlockChainReactor) IsPPOS() bool {
+	return bcr != nil && bcr.validatorMode == common.PPOS_VALIDATOR_MODE
+}
+
+// BeginSimulation returns the read-only view of the PPOS data at the parent
+// the blocks are simulated on, it is nil if the validators are not elected by
+// PPOS. The simulations on different parents run concurrently, the ones on
+// the same parent wait for each other.
+func (bcr *BlockChainReactor) BeginSimulation(parent *types.Header) (snapshotdb.Replay, error) {
+	if !bcr.IsPPOS() {
+		return nil, nil
+	}
+	return snapshotdb.Instance().BeginReplay(parent.Number, parent.Hash())
+}
+
+// BeginSimulatedBlocker is the BeginBlocker of a block simulated in the view
+// of BeginSimulation, the block is signed by nobody so its VRF proof is not
+// verified. The public key of the producer must be set in the header, and the
+// EndBlocker ends the block like an imported one.
+func (bcr *BlockChainReactor) BeginSimulatedBlocker(replay snapshotdb.Replay, header *types.Header, state xcom.StateDB) error {
+	if !bcr.IsPPOS() {
+		return nil
+	}
+	state.SetTxContext(common.ZeroHash, 0)
+
+	blockHash := header.CacheHash()
+	if err := replay.NewBlock(header.Number, header.ParentHash, blockHash); nil != err {
+		log.Error("Failed to call snapshotDB newReplayBlock on blockchain_reactor", "blockNumber",
+			header.Number.Uint64(), "hash", blockHash, "parentHash", header.ParentHash, "err", err)
+		return err
+	}
+	return bcr.beginBlock(blockHash, header, state, true)
+}
+
+// SimulatedProducer returns the validator producing the block simulated on top
+// of the parent, the given node or the validator in turn if it is nil. The
+// producer must be a validator of the round of the block.
+func (bcr *BlockChainReactor) SimulatedProducer(parentHash common.Hash, blockNumber uint64, nodeID *discover.NodeID) (*staking.ValidatorEx, error) {
+	if !bcr.IsPPOS() {
+		return nil, errors.New("the validators are not elected by PPOS")
+	}
+	validators, err := plugin.StakingInstance().GetValidatorList(parentHash, blockNumber, plugin.CurrentRound, plugin.QueryStartNotIrr)
+	if nil != err {
+		return nil, err
+	}
+	if nodeID != nil {
+		for _, v := range validators {
+			if v.NodeId == *nodeID {
+				return v, nil
+			}
+		}
+		return nil, fmt.Errorf("the node %s is not a validator of the block %d", nodeID.TerminalString(), blockNumber)
+	}
+	roundStart := xutil.CalcRoundEndBlock(xutil.CalculateRound(blockNumber)) - xutil.ConsensusSizeAt(blockNumber) + 1
+	if len(validators) == 0 {
+		return nil, fmt.Errorf("no validators of the block %d", blockNumber)
+	}
+	turn := (blockNumber - roundStart) / xcom.BlockScheduleAt(blockNumber).PerRoundBlocks
+	return validators[turn%uint64(len(validators))], nil
+}
+
 func (bcr *BlockChainReactor) VerifyTx(tx *types.Transaction, to common.Address) error {
 
 	if !vm.IsPlatONPrecompiledContract(to, true) {
@@ -356,6 +446,9 @@ func (bcr *BlockChainReactor) VerifyTx(tx *types.Transaction, to common.Address)
 	case cvm.SlashingContractAddr:
 		c := vm.PlatONPrecompiledContracts[cvm.SlashingContractAddr]
 		contract = c.(vm.PlatONPrecompiledContract)
+	case cvm.ScheduledTxContractAddr:
+		c := vm.PlatONPrecompiledContracts120[cvm.ScheduledTxContractAddr]
+		contract = c.(vm.PlatONPrecompiledContract)
 	default:
 		// pass if the contract is validatorInnerContract
 		return nil
@@ -373,6 +466,15 @@ func (bcr *BlockChainReactor) VerifyTx(tx *types.Transaction, to common.Address)
 	}
 }
 
+// SystemTxSenders returns the staking and benefit addresses of the valid candidates
+// at the given header, their PPOS system-contract txs may use the reserved lane of the tx pool.
+func (bcr *BlockChainReactor) SystemTxSenders(header *types.Header) (map[common.Address]struct{}, error) {
+	if bcr == nil || bcr.validatorMode != common.PPOS_VALIDATOR_MODE {
+		return nil, nil
+	}
+	return plugin.StakingInstance().GetCandidateAccounts(header.Hash())
+}
+
 func (bcr *BlockChainReactor) Sign(msg interface{}) error {
 	return nil
 }
@@ -97,6 +97,14 @@ type RemoveValidatorEvent struct {
 
 type UpdateValidatorEvent struct{}
 
+// ViewChangedEvent is posted when the consensus engine switched to a new view,
+// Timeout is set if the previous view ended with a ViewChangeQC.
+type ViewChangedEvent struct {
+	Epoch      uint64
+	ViewNumber uint64
+	Timeout    bool
+}
+
 type ValidateNode struct {
 	Index     uint32             `json:"index"`
 	Address   common.NodeAddress `json:"address"`
@@ -117,6 +125,11 @@ type Validators struct {
 	Nodes            ValidateNodeMap `json:"validateNodes"`
 	ValidBlockNumber uint64          `json:"validateBlockNumber"`
 
+	// The block production period and amount of the validators once they are
+	// governed, zeros follow the cbft config.
+	Period uint64 `json:"period,omitempty"`
+	Amount uint32 `json:"amount,omitempty"`
+
 	sortedNodes SortedValidatorNode
 }
 
@@ -32,4 +32,16 @@ var (
 	// ErrNonceTooHigh is returned if the nonce of a transaction is higher than the
 	// next one expected based on the local chain.
 	ErrNonceTooHigh = errors.New("nonce too high")
+
+	// ErrSponsoredTxNotActive is returned if a sponsored transaction is applied
+	// before the version activating sponsored transactions.
+	ErrSponsoredTxNotActive = errors.New("sponsored transaction not active")
+
+	// ErrScheduledTx is returned if a transaction signed to be scheduled is
+	// applied as an ordinary transaction, only the chain executes it.
+	ErrScheduledTx = errors.New("scheduled transaction not executable directly")
+
+	// ErrExecutorNotInitialized is returned if the scheduled transactions are
+	// due before the executor of the transactions is set up.
+	ErrExecutorNotInitialized = errors.New("executor not initialized")
 )
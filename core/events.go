@@ -51,3 +51,10 @@ type ChainSideEvent struct {
 }
 
 type ChainHeadEvent struct{ Block *types.Block }
+
+// BalanceChangesEvent is posted when a block with PPOS balance changes is
+// inserted into the canonical chain.
+type BalanceChangesEvent struct {
+	Block   *types.Block
+	Changes []*types.BalanceChange
+}
@@ -23,6 +23,7 @@ func (g Genesis) MarshalJSON() ([]byte, error) {
 		GasLimit      math.HexOrDecimal64               `json:"gasLimit"   gencodec:"required"`
 		Coinbase      common.Address                    `json:"coinbase"`
 		Alloc         map[common.Address]GenesisAccount `json:"alloc"      gencodec:"required"`
+		PPOS          *GenesisPPOS                      `json:"ppos,omitempty"`
 		Number        math.HexOrDecimal64               `json:"number"`
 		GasUsed       math.HexOrDecimal64               `json:"gasUsed"`
 		ParentHash    common.Hash                       `json:"parentHash"`
@@ -36,6 +37,7 @@ func (g Genesis) MarshalJSON() ([]byte, error) {
 	enc.GasLimit = math.HexOrDecimal64(g.GasLimit)
 	enc.Coinbase = g.Coinbase
 	enc.Alloc = g.Alloc
+	enc.PPOS = g.PPOS
 	enc.Number = math.HexOrDecimal64(g.Number)
 	enc.GasUsed = math.HexOrDecimal64(g.GasUsed)
 	enc.ParentHash = g.ParentHash
@@ -53,6 +55,7 @@ func (g *Genesis) UnmarshalJSON(input []byte) error {
 		GasLimit      *math.HexOrDecimal64              `json:"gasLimit"   gencodec:"required"`
 		Coinbase      *common.Address                   `json:"coinbase"`
 		Alloc         map[common.Address]GenesisAccount `json:"alloc"      gencodec:"required"`
+		PPOS          *GenesisPPOS                      `json:"ppos,omitempty"`
 		Number        *math.HexOrDecimal64              `json:"number"`
 		GasUsed       *math.HexOrDecimal64              `json:"gasUsed"`
 		ParentHash    *common.Hash                      `json:"parentHash"`
@@ -89,6 +92,9 @@ func (g *Genesis) UnmarshalJSON(input []byte) error {
 	} else {
 		g.Alloc = dec.Alloc
 	}
+	if dec.PPOS != nil {
+		g.PPOS = dec.PPOS
+	}
 	if dec.Number != nil {
 		g.Number = uint64(*dec.Number)
 	}
@@ -22,6 +22,7 @@ func (g GenesisAccount) MarshalJSON() ([]byte, error) {
 		Balance    *math.HexOrDecimal256       `json:"balance" gencodec:"required"`
 		Nonce      math.HexOrDecimal64         `json:"nonce,omitempty"`
 		PrivateKey hexutil.Bytes               `json:"secretKey,omitempty"`
+		RawStorage []GenesisKV                 `json:"rawStorage,omitempty"`
 	}
 	var enc GenesisAccount
 	enc.Code = g.Code
@@ -34,6 +35,7 @@ func (g GenesisAccount) MarshalJSON() ([]byte, error) {
 	enc.Balance = (*math.HexOrDecimal256)(g.Balance)
 	enc.Nonce = math.HexOrDecimal64(g.Nonce)
 	enc.PrivateKey = g.PrivateKey
+	enc.RawStorage = g.RawStorage
 	return json.Marshal(&enc)
 }
 
@@ -45,6 +47,7 @@ func (g *GenesisAccount) UnmarshalJSON(input []byte) error {
 		Balance    *math.HexOrDecimal256       `json:"balance" gencodec:"required"`
 		Nonce      *math.HexOrDecimal64        `json:"nonce,omitempty"`
 		PrivateKey *hexutil.Bytes              `json:"secretKey,omitempty"`
+		RawStorage []GenesisKV                 `json:"rawStorage,omitempty"`
 	}
 	var dec GenesisAccount
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -69,5 +72,8 @@ func (g *GenesisAccount) UnmarshalJSON(input []byte) error {
 	if dec.PrivateKey != nil {
 		g.PrivateKey = *dec.PrivateKey
 	}
+	if dec.RawStorage != nil {
+		g.RawStorage = dec.RawStorage
+	}
 	return nil
 }
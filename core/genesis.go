@@ -61,6 +61,7 @@ type Genesis struct {
 	GasLimit      uint64              `json:"gasLimit"   gencodec:"required"`
 	Coinbase      common.Address      `json:"coinbase"`
 	Alloc         GenesisAlloc        `json:"alloc"      gencodec:"required"`
+	PPOS          *GenesisPPOS        `json:"ppos,omitempty"`
 
 	// These fields are used for consensus tests. Please don't use them
 	// in actual genesis blocks.
@@ -79,6 +80,24 @@ type GenesisAccount struct {
 	Balance    *big.Int                    `json:"balance" gencodec:"required"`
 	Nonce      uint64                      `json:"nonce,omitempty"`
 	PrivateKey []byte                      `json:"secretKey,omitempty"` // for tests
+	RawStorage []GenesisKV                 `json:"rawStorage,omitempty"`
+}
+
+// GenesisKV is a key-value pair of the genesis whose key and value are
+// arbitrary bytes, like the storage of the PPOS contracts.
+type GenesisKV struct {
+	Key   hexutil.Bytes `json:"key"`
+	Value hexutil.Bytes `json:"value"`
+}
+
+// GenesisPPOS is the PPOS data of a genesis exported from the state of another
+// chain. It replaces the staking data built from the initial nodes and the
+// govern parameters of the economic model, the accounts of the chain are in
+// the alloc.
+type GenesisPPOS struct {
+	Number uint64      `json:"number"` // The block of the chain the data was exported at
+	Hash   common.Hash `json:"hash"`
+	Data   []GenesisKV `json:"data"`
 }
 
 // field type overrides for gencodec
@@ -258,6 +277,11 @@ func SetupGenesisBlock(db ethdb.Database, snapshotBaseDB snapshotdb.BaseDB, gene
 	}
 	xcom.ResetEconomicDefaultConfig(ecCfg)
 	xcom.ResetEconomicExtendConfig(eceCfg)
+	// Restore the economic models activated by the version upgrades
+	xcom.ResetEconomicVersions()
+	for _, ev := range rawdb.ReadEconomicVersions(db) {
+		xcom.RegisterEconomicVersion(ev.Version, ev.BlockNumber, nil, ev.Ece)
+	}
 
 	// Special case: don't change the existing config of a non-mainnet chain if no new
 	// config is supplied. These chains would get AllProtocolChanges (and a compat error)
@@ -399,12 +423,15 @@ func (g *Genesis) ToBlock(db ethdb.Database, sdb snapshotdb.BaseDB) *types.Block
 	genesisIssuance := new(big.Int)
 
 	statedb, _ := state.New(common.Hash{}, state.NewDatabase(db))
-	// First, Store the PlatONFoundation and CommunityDeveloperFoundation
-	statedb.AddBalance(xcom.PlatONFundAccount(), xcom.PlatONFundBalance())
-	statedb.AddBalance(xcom.CDFAccount(), xcom.CDFBalance())
+	// First, Store the PlatONFoundation and CommunityDeveloperFoundation,
+	// the exported PPOS genesis holds their balances in the alloc
+	if g.PPOS == nil {
+		statedb.AddBalance(xcom.PlatONFundAccount(0), xcom.PlatONFundBalance())
+		statedb.AddBalance(xcom.CDFAccount(0), xcom.CDFBalance())
 
-	genesisIssuance = genesisIssuance.Add(genesisIssuance, xcom.PlatONFundBalance())
-	genesisIssuance = genesisIssuance.Add(genesisIssuance, xcom.CDFBalance())
+		genesisIssuance = genesisIssuance.Add(genesisIssuance, xcom.PlatONFundBalance())
+		genesisIssuance = genesisIssuance.Add(genesisIssuance, xcom.CDFBalance())
+	}
 
 	for addr, account := range g.Alloc {
 		statedb.AddBalance(addr, account.Balance)
@@ -414,6 +441,9 @@ func (g *Genesis) ToBlock(db ethdb.Database, sdb snapshotdb.BaseDB) *types.Block
 
 			statedb.SetState(addr, key.Bytes(), value.Bytes())
 		}
+		for _, kv := range account.RawStorage {
+			statedb.SetState(addr, kv.Key, kv.Value)
+		}
 
 		genesisIssuance = genesisIssuance.Add(genesisIssuance, account.Balance)
 	}
@@ -433,12 +463,20 @@ func (g *Genesis) ToBlock(db ethdb.Database, sdb snapshotdb.BaseDB) *types.Block
 		panic("Failed to hash economic config")
 	}
 
-	if initDataStateHash, err = genesisGovernParamData(initDataStateHash, sdb, genesisVersion); err != nil {
-		log.Error("Failed to init govern parameter in snapshotdb", "err", err)
-		panic("Failed to init govern parameter in snapshotdb")
+	// The exported PPOS data holds the govern parameters
+	if g.PPOS == nil {
+		if initDataStateHash, err = genesisGovernParamData(initDataStateHash, sdb, genesisVersion); err != nil {
+			log.Error("Failed to init govern parameter in snapshotdb", "err", err)
+			panic("Failed to init govern parameter in snapshotdb")
+		}
 	}
 
-	if g.configEmpty() {
+	if g.PPOS != nil {
+		// Store the exported PPOS data in place of the staking data
+		if _, err := genesisPPOSData(initDataStateHash, sdb, g.PPOS, statedb); nil != err {
+			panic("Failed Store PPOS data: " + err.Error())
+		}
+	} else if g.configEmpty() {
 		log.Warn("the genesis config or cbft or initialNodes is nil, don't build staking data And don't store plugin genesis state")
 	} else {
 		if g.Config.GenesisVersion == 0 {
@@ -458,7 +496,7 @@ func (g *Genesis) ToBlock(db ethdb.Database, sdb snapshotdb.BaseDB) *types.Block
 	}
 	// 1.3.0
 	if gov.Gte130Version(genesisVersion) {
-		if err := gov.WriteEcHash130(statedb); nil != err {
+		if err := gov.WriteEcHash130(0, statedb); nil != err {
 			panic("Failed Store EcHash130: " + err.Error())
 		}
 	}
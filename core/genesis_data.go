@@ -32,23 +32,26 @@ func genesisStakingData(prevHash common.Hash, snapdb snapshotdb.BaseDB, g *Genes
 
 	var length int
 
-	if int(xcom.MaxConsensusVals()) <= len(g.Config.Cbft.InitialNodes) {
-		length = int(xcom.MaxConsensusVals())
+	if int(xcom.MaxConsensusVals(0)) <= len(g.Config.Cbft.InitialNodes) {
+		length = int(xcom.MaxConsensusVals(0))
 	} else {
 		length = len(g.Config.Cbft.InitialNodes)
 	}
 
 	// Check the balance of Staking Account
 	needStaking := new(big.Int).Mul(xcom.GeneStakingAmount, big.NewInt(int64(length)))
-	remain := stateDB.GetBalance(xcom.CDFAccount())
+	remain := stateDB.GetBalance(xcom.CDFAccount(0))
 
 	if remain.Cmp(needStaking) < 0 {
 		return prevHash, fmt.Errorf("Failed to store genesis staking data, the balance of '%s' is no enough. "+
-			"balance: %s, need staking: %s", xcom.CDFAccount().String(), remain.String(), needStaking.String())
+			"balance: %s, need staking: %s", xcom.CDFAccount(0).String(), remain.String(), needStaking.String())
 	}
 
 	initQueue := g.Config.Cbft.InitialNodes
 
+	// the initial nodes are admitted to stake if the allowlist is on from the genesis
+	allowlist := g.Config.GenesisVersion >= params.FORKVERSION_1_5_0 && xcom.AdmissionAllowlist(0)
+
 	validatorQueue := make(staking.ValidatorQueue, length)
 
 	lastHash := prevHash
@@ -77,7 +80,7 @@ func genesisStakingData(prevHash common.Hash, snapdb snapshotdb.BaseDB, g *Genes
 		base := &staking.CandidateBase{
 			NodeId:          node.Node.ID,
 			BlsPubKey:       keyHex,
-			StakingAddress:  xcom.CDFAccount(),
+			StakingAddress:  xcom.CDFAccount(0),
 			BenefitAddress:  vm.RewardManagerPoolAddr,
 			StakingTxIndex:  uint32(index),           // txIndex from zero to n
 			ProgramVersion:  g.Config.GenesisVersion, // genesis version
@@ -144,6 +147,21 @@ func genesisStakingData(prevHash common.Hash, snapdb snapshotdb.BaseDB, g *Genes
 				base.NodeId.String(), err.Error())
 		}
 
+		// about allowlist entry ...
+		if allowlist {
+			entry := &gov.AllowlistEntry{NodeId: base.NodeId, StakingAddress: base.StakingAddress}
+			if val, err := rlp.EncodeToBytes(entry); nil != err {
+				return lastHash, fmt.Errorf("Failed to Store Allowlist Entry: rlp encodeing failed. nodeId:%s, error:%s",
+					base.NodeId.String(), err.Error())
+			} else {
+				lastHash, err = putbasedbFn(gov.KeyAllowlistEntry(nodeAddr), val, lastHash)
+				if nil != err {
+					return lastHash, fmt.Errorf("Failed to Store Allowlist Entry: PutBaseDB failed. nodeId:%s, error:%s",
+						base.NodeId.String(), err.Error())
+				}
+			}
+		}
+
 		// build validator queue for the first consensus epoch
 		validator := &staking.Validator{
 			NodeAddress:     nodeAddr,
@@ -157,15 +175,15 @@ func genesisStakingData(prevHash common.Hash, snapdb snapshotdb.BaseDB, g *Genes
 		}
 		validatorQueue[index] = validator
 
-		stateDB.SubBalance(xcom.CDFAccount(), new(big.Int).Set(xcom.GeneStakingAmount))
+		stateDB.SubBalance(xcom.CDFAccount(0), new(big.Int).Set(xcom.GeneStakingAmount))
 		stateDB.AddBalance(vm.StakingContractAddr, new(big.Int).Set(xcom.GeneStakingAmount))
 	}
 
 	// store the account staking Reference Count
-	lastHash, err := putbasedbFn(staking.GetAccountStakeRcKey(xcom.CDFAccount()), common.Uint64ToBytes(uint64(length)), lastHash)
+	lastHash, err := putbasedbFn(staking.GetAccountStakeRcKey(xcom.CDFAccount(0)), common.Uint64ToBytes(uint64(length)), lastHash)
 	if nil != err {
 		return lastHash, fmt.Errorf("Failed to Store Staking Account Reference Count. account: %s, error:%s",
-			xcom.CDFAccount().String(), err.Error())
+			xcom.CDFAccount(0).String(), err.Error())
 	}
 
 	validatorArr, err := rlp.EncodeToBytes(validatorQueue)
@@ -247,6 +265,25 @@ func genesisStakingData(prevHash common.Hash, snapdb snapshotdb.BaseDB, g *Genes
 	return lastHash, nil
 }
 
+// genesisPPOSData stores the PPOS data exported from another chain in place of
+// the staking data and the govern parameters built for a new chain.
+func genesisPPOSData(prevHash common.Hash, snapdb snapshotdb.BaseDB, ppos *GenesisPPOS, stateDB *state.StateDB) (common.Hash, error) {
+	lastHash := prevHash
+	for _, kv := range ppos.Data {
+		if err := snapdb.PutBaseDB(kv.Key, kv.Value); nil != err {
+			return lastHash, fmt.Errorf("Failed to Store PPOS data: PutBaseDB failed. key:%x, error:%s", []byte(kv.Key), err.Error())
+		}
+		lastHash = common.GenerateKVHash(kv.Key, kv.Value, lastHash)
+	}
+
+	log.Info("Call genesisPPOSData, Store genesis pposHash by exported data", "number", ppos.Number, "hash", ppos.Hash.Hex(),
+		"records", len(ppos.Data), "pposHash", lastHash.Hex())
+
+	stateDB.SetState(vm.StakingContractAddr, staking.GetPPOSHASHKey(), lastHash.Bytes())
+
+	return lastHash, nil
+}
+
 func genesisPluginState(g *Genesis, statedb *state.StateDB, snapDB snapshotdb.BaseDB, genesisIssue *big.Int) error {
 
 	if g.Config.Cbft.ValidatorMode != common.PPOS_VALIDATOR_MODE {
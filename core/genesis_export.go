@@ -0,0 +1,384 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core/snapshotdb"
+	"github.com/hashkey-chain/hashkey-chain/core/state"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+	"github.com/hashkey-chain/hashkey-chain/params"
+	"github.com/hashkey-chain/hashkey-chain/rlp"
+	"github.com/hashkey-chain/hashkey-chain/x/gov"
+	"github.com/hashkey-chain/hashkey-chain/x/plugin"
+	"github.com/hashkey-chain/hashkey-chain/x/reward"
+	"github.com/hashkey-chain/hashkey-chain/x/staking"
+	"github.com/hashkey-chain/hashkey-chain/x/xcom"
+	"github.com/hashkey-chain/hashkey-chain/x/xutil"
+)
+
+// pposContracts are the accounts of the PPOS contracts, their storage refers to
+// the validators like the PPOS data.
+var pposContracts = []common.Address{
+	vm.RestrictingContractAddr,
+	vm.StakingContractAddr,
+	vm.RewardManagerPoolAddr,
+	vm.SlashingContractAddr,
+	vm.GovContractAddr,
+	vm.DelegateRewardPoolAddr,
+}
+
+// ExportGenesis exports the accounts and the PPOS data of the chain at the block
+// into a genesis, a network initialized with it starts from the economic state
+// of the block: the candidates, delegations, restricting plans and govern
+// parameters. The block must end an epoch, its state must be recorded with the
+// preimages of the trie keys and its PPOS data must be kept by snapshotdb.
+//
+// The verifiers and the validators of the next block become the ones of the
+// first epoch and round of the genesis, the initial nodes list the verifiers
+// without their network addresses. The reward year, the zero produce records
+// and the active blocks of the versions and the govern parameters are rebased
+// onto the genesis. The other records referring to blocks, rounds or epochs by
+// number, like the pending unstakings, restricting releases and proposals,
+// keep the numbers of the exported chain.
+func ExportGenesis(config *params.ChainConfig, header *types.Header, statedb *state.StateDB, sdb snapshotdb.DB) (*Genesis, error) {
+	number := header.Number.Uint64()
+	if config.Cbft == nil || config.Cbft.ValidatorMode != common.PPOS_VALIDATOR_MODE {
+		return nil, errors.New("the chain doesn't run the PPOS validator mode")
+	}
+	if !xutil.IsEndOfEpoch(number) {
+		return nil, fmt.Errorf("block %d doesn't end an epoch", number)
+	}
+
+	alloc := make(genesisAllocCollector)
+	if err := statedb.ExportToCollector(alloc); err != nil {
+		return nil, err
+	}
+	version := gov.GetCurrentActiveVersion(statedb)
+	if version == 0 {
+		return nil, errors.New("no active version in the state")
+	}
+	if err := alloc.rebaseActiveVersions(version); err != nil {
+		return nil, err
+	}
+
+	data, err := exportPPOSData(sdb, header)
+	if err != nil {
+		return nil, err
+	}
+	verifiers, err := data.validators(staking.GetEpochIndexKey(), staking.GetEpochValArrKey, number+1)
+	if err != nil {
+		return nil, fmt.Errorf("epoch verifiers: %v", err)
+	}
+	validators, err := data.validators(staking.GetRoundIndexKey(), staking.GetRoundValArrKey, number+1)
+	if err != nil {
+		return nil, fmt.Errorf("round validators: %v", err)
+	}
+	if err := data.rebase(header, verifiers, validators); err != nil {
+		return nil, err
+	}
+
+	initialNodes := make([]params.CbftNode, len(verifiers))
+	for i, verifier := range verifiers {
+		blsPubKey, err := verifier.BlsPubKey.ParseBlsPubKey()
+		if err != nil {
+			return nil, fmt.Errorf("BLS key of verifier %s: %v", verifier.NodeId.TerminalString(), err)
+		}
+		initialNodes[i] = params.CbftNode{
+			Node:      discover.Node{ID: verifier.NodeId},
+			BlsPubKey: *blsPubKey,
+		}
+	}
+	schedule := xcom.BlockScheduleAt(number + 1)
+	cbft := *config.Cbft
+	cbft.Period = schedule.Period()
+	cbft.Amount = uint32(schedule.PerRoundBlocks)
+	cbft.InitialNodes = initialNodes
+	cfg := *config
+	cfg.Cbft = &cbft
+	cfg.GenesisVersion = version
+
+	return &Genesis{
+		Config:        &cfg,
+		EconomicModel: xcom.GetEcAt(number),
+		Timestamp:     header.Time,
+		GasLimit:      header.GasLimit,
+		Alloc:         GenesisAlloc(alloc),
+		PPOS: &GenesisPPOS{
+			Number: number,
+			Hash:   header.Hash(),
+			Data:   data.sorted(),
+		},
+	}, nil
+}
+
+// ReplaceValidatorKeys replaces the node ids and the BLS keys of the initial
+// nodes of an exported genesis with the ones of the given nodes in the same
+// order. The PPOS data and the storage of the PPOS contracts are rewritten
+// with the new node ids, node addresses and BLS keys, so the records of the
+// candidates and the delegations follow the new keys.
+func (g *Genesis) ReplaceValidatorKeys(nodes []params.CbftNode) error {
+	if g.PPOS == nil || g.configEmpty() {
+		return errors.New("the genesis holds no exported PPOS data")
+	}
+	initialNodes := g.Config.Cbft.InitialNodes
+	if len(nodes) != len(initialNodes) {
+		return fmt.Errorf("have %d nodes, want %d", len(nodes), len(initialNodes))
+	}
+	var oldnew []string
+	for i := range nodes {
+		from, err := validatorKeysOf(initialNodes[i])
+		if err != nil {
+			return err
+		}
+		to, err := validatorKeysOf(nodes[i])
+		if err != nil {
+			return err
+		}
+		for j := range from {
+			oldnew = append(oldnew, string(from[j]), string(to[j]))
+			oldnew = append(oldnew, fmt.Sprintf("%x", from[j]), fmt.Sprintf("%x", to[j]))
+		}
+	}
+	replacer := strings.NewReplacer(oldnew...)
+	replace := func(b []byte) []byte {
+		return []byte(replacer.Replace(string(b)))
+	}
+
+	data := make(pposData, len(g.PPOS.Data))
+	for _, kv := range g.PPOS.Data {
+		data[string(replace(kv.Key))] = replace(kv.Value)
+	}
+	g.PPOS.Data = data.sorted()
+	for _, addr := range pposContracts {
+		account, ok := g.Alloc[addr]
+		if !ok {
+			continue
+		}
+		for i, kv := range account.RawStorage {
+			account.RawStorage[i] = GenesisKV{Key: replace(kv.Key), Value: replace(kv.Value)}
+		}
+	}
+	g.Config.Cbft.InitialNodes = append([]params.CbftNode(nil), nodes...)
+	return nil
+}
+
+// validatorKeysOf returns the node id, the node address and the BLS key of the
+// node as they are stored.
+func validatorKeysOf(node params.CbftNode) ([][]byte, error) {
+	addr, err := xutil.NodeId2Addr(node.Node.ID)
+	if err != nil {
+		return nil, fmt.Errorf("node %s: %v", node.Node.ID.TerminalString(), err)
+	}
+	return [][]byte{node.Node.ID.Bytes(), addr.Bytes(), node.BlsPubKey.Serialize()}, nil
+}
+
+// genesisAllocCollector collects the accounts of the exported state.
+type genesisAllocCollector GenesisAlloc
+
+func (c genesisAllocCollector) OnAccount(addr common.Address, balance *big.Int, nonce uint64, code []byte) error {
+	c[addr] = GenesisAccount{
+		Code:    code,
+		Balance: new(big.Int).Set(balance),
+		Nonce:   nonce,
+	}
+	return nil
+}
+
+func (c genesisAllocCollector) OnStorage(addr common.Address, key, value []byte) error {
+	account := c[addr]
+	account.RawStorage = append(account.RawStorage, GenesisKV{Key: key, Value: value})
+	c[addr] = account
+	return nil
+}
+
+// rebaseActiveVersions makes the active version take effect from the genesis.
+func (c genesisAllocCollector) rebaseActiveVersions(version uint32) error {
+	value, err := json.Marshal([]gov.ActiveVersionValue{{ActiveVersion: version, ActiveBlock: 0}})
+	if err != nil {
+		return err
+	}
+	account := c[vm.GovContractAddr]
+	for i, kv := range account.RawStorage {
+		if string(kv.Key) == string(gov.KeyActiveVersions()) {
+			account.RawStorage[i].Value = value
+			return nil
+		}
+	}
+	return errors.New("no active versions in the state")
+}
+
+// pposData is the PPOS data being exported, keyed by the string of the keys.
+type pposData map[string][]byte
+
+// exportPPOSData returns the PPOS data at the block.
+func exportPPOSData(sdb snapshotdb.DB, header *types.Header) (pposData, error) {
+	replay, err := sdb.BeginReplay(header.Number, header.Hash())
+	if err != nil {
+		return nil, err
+	}
+	defer replay.End()
+
+	data := make(pposData)
+	itr := sdb.Ranking(header.Hash(), nil, 0)
+	defer itr.Release()
+	for itr.Next() {
+		if snapshotdb.IsInternalKey(itr.Key()) || len(itr.Value()) == 0 {
+			continue
+		}
+		data[string(itr.Key())] = common.CopyBytes(itr.Value())
+	}
+	return data, itr.Error()
+}
+
+// validators returns the validators of the block in the list of the index.
+func (d pposData) validators(indexKey []byte, arrKey func(start, end uint64) []byte, number uint64) (staking.ValidatorQueue, error) {
+	var indexes staking.ValArrIndexQueue
+	if err := rlp.DecodeBytes(d[string(indexKey)], &indexes); err != nil {
+		return nil, err
+	}
+	for _, index := range indexes {
+		if index.Start <= number && number <= index.End {
+			var queue staking.ValidatorQueue
+			if err := rlp.DecodeBytes(d[string(arrKey(index.Start, index.End))], &queue); err != nil {
+				return nil, err
+			}
+			if len(queue) == 0 {
+				return nil, fmt.Errorf("no validators of block %d", number)
+			}
+			return queue, nil
+		}
+	}
+	return nil, fmt.Errorf("no validators of block %d", number)
+}
+
+// rebase rewrites the records of the exported block which refer to the blocks,
+// rounds and epochs around it by number, so they refer to the genesis instead.
+func (d pposData) rebase(header *types.Header, verifiers, validators staking.ValidatorQueue) error {
+	number := header.Number.Uint64()
+
+	// The validators of the first epoch and round, like the genesis staking
+	// data. The block schedule at the block is the one of the genesis config.
+	prefixes := append([][]byte{
+		gov.KeyBlockSchedules(),
+		staking.GetEpochIndexKey(),
+		staking.EpochValArrPrefix,
+		staking.GetRoundIndexKey(),
+		staking.RoundValArrPrefix,
+		staking.RoundValAddrArrPrefix,
+		staking.GetRoundAddrBoundaryKey(),
+	}, plugin.ZeroProduceRecordPrefixes()...)
+	for key := range d {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(key, string(prefix)) {
+				delete(d, key)
+				break
+			}
+		}
+	}
+	epochIndex := &staking.ValArrIndex{Start: 1, End: xutil.CalcBlocksEachEpochAt(number + 1)}
+	roundIndexes := staking.ValArrIndexQueue{
+		{Start: 0, End: 0},
+		{Start: 1, End: xutil.ConsensusSizeAt(number + 1)},
+	}
+	if err := d.putRLP(staking.GetEpochIndexKey(), staking.ValArrIndexQueue{epochIndex}); err != nil {
+		return err
+	}
+	if err := d.putRLP(staking.GetEpochValArrKey(epochIndex.Start, epochIndex.End), verifiers); err != nil {
+		return err
+	}
+	if err := d.putRLP(staking.GetRoundIndexKey(), roundIndexes); err != nil {
+		return err
+	}
+	for _, index := range roundIndexes {
+		if err := d.putRLP(staking.GetRoundValArrKey(index.Start, index.End), validators); err != nil {
+			return err
+		}
+	}
+
+	// The reward year restarts at the first block, the additional issuance
+	// scheduled after the block keeps its distance to it
+	d[string(reward.YearStartBlockNumberKey)] = common.Uint64ToBytes(1)
+	d[string(reward.YearStartTimeKey)] = common.Int64ToBytes(int64(header.Time))
+	incIssuanceNumber := uint64(math.MaxUint64) // Not scheduled yet
+	if value, ok := d[string(xcom.IncIssuanceNumberKey)]; ok && common.BytesToUint64(value) > number {
+		incIssuanceNumber = common.BytesToUint64(value) - number
+	}
+	d[string(xcom.IncIssuanceNumberKey)] = common.Uint64ToBytes(incIssuanceNumber)
+
+	// The govern parameters changed before the block are in effect from the
+	// genesis, the pending ones keep their distance to it
+	var items []*gov.ParamItem
+	if value, ok := d[string(gov.KeyParamItems())]; ok {
+		if err := rlp.DecodeBytes(value, &items); err != nil {
+			return fmt.Errorf("govern parameter items: %v", err)
+		}
+	}
+	for _, item := range items {
+		key := string(gov.KeyParamValue(item.Module, item.Name))
+		value, ok := d[key]
+		if !ok {
+			continue
+		}
+		var paramValue gov.ParamValue
+		if err := rlp.DecodeBytes(value, &paramValue); err != nil {
+			return fmt.Errorf("govern parameter %s/%s: %v", item.Module, item.Name, err)
+		}
+		if paramValue.ActiveBlock > number {
+			paramValue.ActiveBlock -= number
+		} else {
+			paramValue.ActiveBlock = 0
+		}
+		if err := d.putRLP([]byte(key), paramValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d pposData) putRLP(key []byte, val interface{}) error {
+	value, err := rlp.EncodeToBytes(val)
+	if err != nil {
+		return err
+	}
+	d[string(key)] = value
+	return nil
+}
+
+// sorted returns the records in the order of the keys.
+func (d pposData) sorted() []GenesisKV {
+	keys := make([]string, 0, len(d))
+	for key := range d {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	data := make([]GenesisKV, len(keys))
+	for i, key := range keys {
+		data[i] = GenesisKV{Key: []byte(key), Value: d[key]}
+	}
+	return data
+}
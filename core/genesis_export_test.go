@@ -0,0 +1,85 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/crypto"
+	"github.com/hashkey-chain/hashkey-chain/crypto/bls"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+	"github.com/hashkey-chain/hashkey-chain/params"
+	"github.com/hashkey-chain/hashkey-chain/x/xutil"
+)
+
+func newExportTestNode(t *testing.T) params.CbftNode {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var blsKey bls.SecretKey
+	blsKey.SetByCSPRNG()
+	return params.CbftNode{
+		Node:      discover.Node{ID: discover.PubkeyID(&key.PublicKey)},
+		BlsPubKey: *blsKey.GetPublicKey(),
+	}
+}
+
+func TestGenesisReplaceValidatorKeys(t *testing.T) {
+	bls.Init(bls.BLS12_381)
+
+	from, to := newExportTestNode(t), newExportTestNode(t)
+	fromAddr, _ := xutil.NodeId2Addr(from.Node.ID)
+	toAddr, _ := xutil.NodeId2Addr(to.Node.ID)
+	concat := func(parts ...[]byte) []byte { return bytes.Join(parts, nil) }
+
+	genesis := &Genesis{
+		Config: &params.ChainConfig{Cbft: &params.CbftConfig{InitialNodes: []params.CbftNode{from}}},
+		Alloc: GenesisAlloc{
+			pposContracts[0]: {RawStorage: []GenesisKV{{Key: concat([]byte("Can"), fromAddr.Bytes()), Value: []byte("other")}}},
+		},
+		PPOS: &GenesisPPOS{Data: []GenesisKV{
+			{Key: concat([]byte("Del"), fromAddr.Bytes()), Value: concat(from.Node.ID.Bytes(), from.BlsPubKey.Serialize())},
+			{Key: []byte("Other"), Value: []byte(common.Bytes2Hex(from.Node.ID.Bytes()))},
+		}},
+	}
+	if err := genesis.ReplaceValidatorKeys([]params.CbftNode{to, to}); err == nil {
+		t.Fatal("replaced the keys of one initial node with two nodes")
+	}
+	if err := genesis.ReplaceValidatorKeys([]params.CbftNode{to}); err != nil {
+		t.Fatalf("failed to replace the keys: %v", err)
+	}
+
+	if id := genesis.Config.Cbft.InitialNodes[0].Node.ID; id != to.Node.ID {
+		t.Errorf("initial node mismatch: have %s, want %s", id.TerminalString(), to.Node.ID.TerminalString())
+	}
+	want := []GenesisKV{
+		{Key: concat([]byte("Del"), toAddr.Bytes()), Value: concat(to.Node.ID.Bytes(), to.BlsPubKey.Serialize())},
+		{Key: []byte("Other"), Value: []byte(common.Bytes2Hex(to.Node.ID.Bytes()))},
+	}
+	for i, kv := range genesis.PPOS.Data {
+		if !bytes.Equal(kv.Key, want[i].Key) || !bytes.Equal(kv.Value, want[i].Value) {
+			t.Errorf("PPOS record %d mismatch: have %x => %x, want %x => %x", i, kv.Key, kv.Value, want[i].Key, want[i].Value)
+		}
+	}
+	storage := genesis.Alloc[pposContracts[0]].RawStorage[0]
+	if !bytes.Equal(storage.Key, concat([]byte("Can"), toAddr.Bytes())) || string(storage.Value) != "other" {
+		t.Errorf("storage mismatch: have %x => %x", storage.Key, storage.Value)
+	}
+}
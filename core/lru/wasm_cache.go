@@ -1,36 +1,53 @@
 package lru
 
 import (
+	"math"
 	"sync"
 
 	"github.com/PlatONnetwork/wagon/exec"
 
-	"github.com/hashkey-chain/hashkey-chain/common"
 	"github.com/hashicorp/golang-lru/simplelru"
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/metrics"
 )
 
 var (
-	DefaultWasmCacheSize = 1024
+	// DefaultWasmCacheSize is the default memory allowance in bytes of the
+	// compiled modules kept in the cache.
+	DefaultWasmCacheSize = 64 * 1024 * 1024
 	wasmCache, _         = NewWasmCache(DefaultWasmCacheSize)
 	DefaultWasmCacheDir  = "wasmcache"
+
+	wasmCacheHitMeter  = metrics.NewRegisteredMeter("vm/wasm/cache/hit", nil)
+	wasmCacheMissMeter = metrics.NewRegisteredMeter("vm/wasm/cache/miss", nil)
+	wasmCacheSizeGauge = metrics.NewRegisteredGauge("vm/wasm/cache/size", nil)
 )
 
+// WasmLDBCache keeps the compiled modules keyed by the hash of the contract
+// code, the contracts sharing the same code share the module. The oldest
+// modules are evicted once the modules exceed the size limit in bytes.
 type WasmLDBCache struct {
-	lru  *simplelru.LRU
-	lock sync.RWMutex
+	lru   *simplelru.LRU
+	size  int
+	limit int
+	store *WasmModuleStore
+	lock  sync.RWMutex
 }
 
 type WasmModule struct {
-	Module *exec.CompiledModule
+	Module   *exec.CompiledModule
+	Size     int  // The estimated memory held by the module in bytes
+	Verified bool // Whether the module is verified, the deployed one must be
 }
 
 func WasmCache() *WasmLDBCache {
 	return wasmCache
 }
 
-func NewWasmCache(size int) (*WasmLDBCache, error) {
-	w := &WasmLDBCache{}
-	lru, err := simplelru.NewLRU(size, nil)
+// NewWasmCache creates a cache holding the modules up to limit bytes.
+func NewWasmCache(limit int) (*WasmLDBCache, error) {
+	w := &WasmLDBCache{limit: limit}
+	lru, err := simplelru.NewLRU(math.MaxInt32, w.onEvict)
 
 	if err != nil {
 		return nil, err
@@ -39,8 +56,40 @@ func NewWasmCache(size int) (*WasmLDBCache, error) {
 	return w, nil
 }
 
-func NewWasmLDBCache(size int) (*WasmLDBCache, error) {
-	return NewWasmCache(size)
+func NewWasmLDBCache(limit int) (*WasmLDBCache, error) {
+	return NewWasmCache(limit)
+}
+
+func (w *WasmLDBCache) onEvict(key interface{}, value interface{}) {
+	w.size -= value.(*WasmModule).Size
+	wasmCacheSizeGauge.Update(int64(w.size))
+}
+
+// SetLimit changes the size limit in bytes, the oldest modules are evicted
+// if the cache exceeds the new limit.
+func (w *WasmLDBCache) SetLimit(limit int) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.limit = limit
+	w.shrink()
+}
+
+// shrink evicts the oldest modules until the cache fits in the limit, the
+// latest module is always kept.
+func (w *WasmLDBCache) shrink() bool {
+	evicted := false
+	for w.size > w.limit && w.lru.Len() > 1 {
+		w.lru.RemoveOldest()
+		evicted = true
+	}
+	return evicted
+}
+
+// SetStore persists the modules added to the cache in the store.
+func (w *WasmLDBCache) SetStore(store *WasmModuleStore) {
+	w.lock.Lock()
+	w.store = store
+	w.lock.Unlock()
 }
 
 // Purge is used to completely clear the cache
@@ -50,67 +99,60 @@ func (w *WasmLDBCache) Purge() {
 	w.lock.Unlock()
 }
 
-// Add adds a value to the cache.  Returns true if an eviction occurred.
-func (w *WasmLDBCache) Add(key common.Address, value *WasmModule) bool {
+// Add adds the module compiled from the code to the cache, the code is
+// written to the store if any. Returns true if an eviction occurred.
+func (w *WasmLDBCache) Add(key common.Hash, value *WasmModule, code []byte) bool {
 	w.lock.Lock()
 	defer w.lock.Unlock()
-	return w.lru.Add(key, value)
+	if old, ok := w.lru.Peek(key); ok {
+		w.size -= old.(*WasmModule).Size
+	}
+	w.size += value.Size
+	w.lru.Add(key, value)
+	evicted := w.shrink()
+	wasmCacheSizeGauge.Update(int64(w.size))
+
+	if w.store != nil && len(code) > 0 {
+		w.store.Put(key, code)
+	}
+	return evicted
 }
 
 // Get looks up a key's value from the cache.
-func (w *WasmLDBCache) Get(key common.Address) (*WasmModule, bool) {
+func (w *WasmLDBCache) Get(key common.Hash) (*WasmModule, bool) {
 	w.lock.Lock()
 	defer w.lock.Unlock()
 	value, ok := w.lru.Get(key)
 	if !ok {
+		wasmCacheMissMeter.Mark(1)
 		return nil, ok
 	}
+	wasmCacheHitMeter.Mark(1)
 	return value.(*WasmModule), ok
 }
 
 // Check if a key is in the cache, without updating the recent-ness
 // or deleting it for being stale.
-func (w *WasmLDBCache) Contains(key common.Address) bool {
+func (w *WasmLDBCache) Contains(key common.Hash) bool {
 	w.lock.RLock()
 	defer w.lock.RUnlock()
-	if !w.lru.Contains(key) {
-		//ok := false
-		//if w.db != nil {
-		//	ok, _ = w.db.Has(key.Bytes(), nil)
-		//}
-		//return ok
-
-		return false
-	}
-	return true
+	return w.lru.Contains(key)
 }
 
 // Returns the key value (or undefined if not found) without updating
 // the "recently used"-ness of the key.
-func (w *WasmLDBCache) Peek(key common.Address) (*WasmModule, bool) {
+func (w *WasmLDBCache) Peek(key common.Hash) (*WasmModule, bool) {
 	w.lock.Lock()
 	defer w.lock.Unlock()
 	value, ok := w.lru.Peek(key)
-	return value.(*WasmModule), ok
-}
-
-// ContainsOrAdd checks if a key is in the cache  without updating the
-// recent-ness or deleting it for being stale,  and if not, adds the value.
-// Returns whether found and whether an eviction occurred.
-func (w *WasmLDBCache) ContainsOrAdd(key common.Address, value *WasmModule) (ok, evict bool) {
-	w.lock.Lock()
-	defer w.lock.Unlock()
-
-	if w.lru.Contains(key) {
-		return true, false
-	} else {
-		evict := w.lru.Add(key, value)
-		return false, evict
+	if !ok {
+		return nil, ok
 	}
+	return value.(*WasmModule), ok
 }
 
 // Remove removes the provided key from the cache.
-func (w *WasmLDBCache) Remove(key common.Address) {
+func (w *WasmLDBCache) Remove(key common.Hash) {
 	w.lock.Lock()
 	w.lru.Remove(key)
 	w.lock.Unlock()
@@ -136,3 +178,10 @@ func (w *WasmLDBCache) Len() int {
 	defer w.lock.RUnlock()
 	return w.lru.Len()
 }
+
+// Size returns the estimated memory held by the modules in bytes.
+func (w *WasmLDBCache) Size() int {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return w.size
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package lru
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/crypto"
+)
+
+func TestWasmCacheSizeLimit(t *testing.T) {
+	cache, err := NewWasmCache(100)
+	assert.Nil(t, err)
+
+	hashes := []common.Hash{{0x1}, {0x2}, {0x3}}
+	assert.False(t, cache.Add(hashes[0], &WasmModule{Size: 40}, nil))
+	assert.False(t, cache.Add(hashes[1], &WasmModule{Size: 40}, nil))
+	assert.Equal(t, 80, cache.Size())
+
+	// Touch the first module so the second one is the oldest
+	_, ok := cache.Get(hashes[0])
+	assert.True(t, ok)
+	assert.True(t, cache.Add(hashes[2], &WasmModule{Size: 40}, nil))
+	assert.Equal(t, 80, cache.Size())
+	assert.True(t, cache.Contains(hashes[0]))
+	assert.False(t, cache.Contains(hashes[1]))
+
+	// Replacing a module accounts the new size only
+	cache.Add(hashes[2], &WasmModule{Size: 10}, nil)
+	assert.Equal(t, 50, cache.Size())
+
+	// A module larger than the limit is kept alone
+	cache.Add(hashes[1], &WasmModule{Size: 200}, nil)
+	assert.Equal(t, 1, cache.Len())
+	assert.Equal(t, 200, cache.Size())
+
+	cache.SetLimit(1000)
+	cache.Purge()
+	assert.Equal(t, 0, cache.Size())
+}
+
+func TestWasmModuleStore(t *testing.T) {
+	store, err := OpenWasmModuleStore(t.TempDir())
+	assert.Nil(t, err)
+
+	cache, err := NewWasmCache(100)
+	assert.Nil(t, err)
+	cache.SetStore(store)
+
+	codes := [][]byte{[]byte("code1"), []byte("code2"), []byte("code3")}
+	for _, code := range codes {
+		cache.Add(crypto.Keccak256Hash(code), &WasmModule{Size: 40}, code)
+	}
+	// A broken module is discarded when loading
+	store.Put(common.Hash{0x1}, []byte("broken"))
+
+	warm, err := NewWasmCache(1000)
+	assert.Nil(t, err)
+	var compiled int32
+	store.Load(warm, func(code []byte) (*WasmModule, error) {
+		atomic.AddInt32(&compiled, 1)
+		return &WasmModule{Size: 40}, nil
+	})
+	for i := 0; i < 100 && warm.Len() < len(codes); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Nil(t, store.Close())
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&compiled))
+	assert.Equal(t, 3, warm.Len())
+	for _, code := range codes {
+		assert.True(t, warm.Contains(crypto.Keccak256Hash(code)))
+	}
+	assert.False(t, warm.Contains(common.Hash{0x1}))
+}
@@ -0,0 +1,110 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package lru
+
+import (
+	"sync"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/crypto"
+	"github.com/hashkey-chain/hashkey-chain/log"
+)
+
+// WasmModuleStore persists the validated code of the modules compiled by the
+// node keyed by the code hash, so the cache is warmed up after a restart.
+// The compiled modules can't be serialized, they are compiled again when
+// they are loaded.
+type WasmModuleStore struct {
+	db   *leveldb.DB
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// OpenWasmModuleStore opens the store at the path, it is created if missing.
+func OpenWasmModuleStore(path string) (*WasmModuleStore, error) {
+	db, err := leveldb.OpenFile(path, &opt.Options{
+		OpenFilesCacheCapacity: 16,
+		BlockCacheCapacity:     8 * opt.MiB,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &WasmModuleStore{db: db, quit: make(chan struct{})}, nil
+}
+
+// Put writes the code of the module if it is not stored yet.
+func (s *WasmModuleStore) Put(hash common.Hash, code []byte) {
+	if ok, _ := s.db.Has(hash.Bytes(), nil); ok {
+		return
+	}
+	if err := s.db.Put(hash.Bytes(), code, nil); err != nil {
+		log.Warn("Failed to store the wasm module", "hash", hash, "err", err)
+	}
+}
+
+// Load compiles the stored modules into the cache in the background until the
+// cache is full, the modules compiled by the contract calls meanwhile are kept.
+func (s *WasmModuleStore) Load(cache *WasmLDBCache, compile func(code []byte) (*WasmModule, error)) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		var loaded int
+		itr := s.db.NewIterator(nil, nil)
+		defer itr.Release()
+		for itr.Next() {
+			select {
+			case <-s.quit:
+				return
+			default:
+			}
+			cache.lock.RLock()
+			full := cache.size >= cache.limit
+			cache.lock.RUnlock()
+			if full {
+				break
+			}
+			hash, code := common.BytesToHash(itr.Key()), common.CopyBytes(itr.Value())
+			if cache.Contains(hash) {
+				continue
+			}
+			if crypto.Keccak256Hash(code) != hash {
+				log.Warn("Discard the broken wasm module", "hash", hash)
+				s.db.Delete(itr.Key(), nil)
+				continue
+			}
+			module, err := compile(code)
+			if err != nil {
+				log.Warn("Failed to compile the stored wasm module", "hash", hash, "err", err)
+				continue
+			}
+			cache.Add(hash, module, nil)
+			loaded++
+		}
+		log.Info("Loaded the stored wasm modules", "modules", loaded, "size", common.StorageSize(cache.Size()))
+	}()
+}
+
+// Close stops the loading and closes the store.
+func (s *WasmModuleStore) Close() error {
+	close(s.quit)
+	s.wg.Wait()
+	return s.db.Close()
+}
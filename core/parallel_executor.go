@@ -248,6 +248,11 @@ func (exe *Executor) executeContractTransaction(ctx *ParallelContext, idx int) {
 }
 
 func (exe *Executor) isContract(tx *types.Transaction, state *state.StateDB, ctx *ParallelContext) bool {
+	// The fee payer of a sponsored transaction isn't tracked by the dag,
+	// execute it serially like a contract transaction.
+	if tx.Sponsored() {
+		return true
+	}
 	address := tx.To()
 	if address == nil { // create contract
 		contractAddress := crypto.CreateAddress(tx.FromAddr(exe.Signer()), tx.Nonce())
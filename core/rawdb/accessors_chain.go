@@ -195,6 +195,44 @@ func WriteTxIndexTail(db ethdb.KeyValueWriter, number uint64) {
 	}
 }
 
+// ReadAddressTxIndexTail retrieves the number of oldest block whose
+// transactions have been indexed by address.
+func ReadAddressTxIndexTail(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(addrTxIndexTailKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteAddressTxIndexTail stores the number of oldest block indexed by address
+// into database.
+func WriteAddressTxIndexTail(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(addrTxIndexTailKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store the address transaction index tail", "err", err)
+	}
+}
+
+// ReadAddressTxIndexHead retrieves the number of newest block whose
+// transactions have been indexed by address.
+func ReadAddressTxIndexHead(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(addrTxIndexHeadKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteAddressTxIndexHead stores the number of newest block indexed by address
+// into database.
+func WriteAddressTxIndexHead(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(addrTxIndexHeadKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store the address transaction index head", "err", err)
+	}
+}
+
 // ReadFastTxLookupLimit retrieves the tx lookup limit used in fast sync.
 func ReadFastTxLookupLimit(db ethdb.KeyValueReader) *uint64 {
 	data, _ := db.Get(fastTxLookupLimitKey)
@@ -511,6 +549,40 @@ func DeleteReceipts(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
 	}
 }
 
+// ReadBalanceChanges retrieves the balance changes made by the PPOS plugins in
+// the block.
+func ReadBalanceChanges(db ethdb.Reader, hash common.Hash, number uint64) []*types.BalanceChange {
+	data, _ := db.Get(blockBalanceChangesKey(number, hash))
+	if len(data) == 0 {
+		return nil
+	}
+	var changes []*types.BalanceChange
+	if err := rlp.DecodeBytes(data, &changes); err != nil {
+		log.Error("Invalid balance changes RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return changes
+}
+
+// WriteBalanceChanges stores the balance changes made by the PPOS plugins in
+// the block.
+func WriteBalanceChanges(db ethdb.KeyValueWriter, hash common.Hash, number uint64, changes []*types.BalanceChange) {
+	bytes, err := rlp.EncodeToBytes(changes)
+	if err != nil {
+		log.Crit("Failed to encode block balance changes", "err", err)
+	}
+	if err := db.Put(blockBalanceChangesKey(number, hash), bytes); err != nil {
+		log.Crit("Failed to store block balance changes", "err", err)
+	}
+}
+
+// DeleteBalanceChanges removes the balance changes associated with a block hash.
+func DeleteBalanceChanges(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(blockBalanceChangesKey(number, hash)); err != nil {
+		log.Crit("Failed to delete block balance changes", "err", err)
+	}
+}
+
 // ReadBlock retrieves an entire block corresponding to the hash, assembling it
 // back from the stored header and body. If either the header or body could not
 // be retrieved nil is returned.
@@ -565,6 +637,7 @@ func WriteAncientBlock(db ethdb.AncientWriter, block *types.Block, receipts type
 // DeleteBlock removes all block data associated with a hash.
 func DeleteBlock(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
 	DeleteReceipts(db, hash, number)
+	DeleteBalanceChanges(db, hash, number)
 	DeleteHeader(db, hash, number)
 	DeleteBody(db, hash, number)
 }
@@ -21,6 +21,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"reflect"
 	"testing"
 
 	"golang.org/x/crypto/sha3"
@@ -335,3 +336,25 @@ func checkReceiptsRLP(have, want types.Receipts) error {
 	}
 	return nil
 }
+
+func TestBalanceChangesStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	changes := []*types.BalanceChange{
+		{To: common.HexToAddress("0x1"), Amount: big.NewInt(1), Module: "reward", Reason: types.BalanceChangeIssuance},
+		{From: common.HexToAddress("0x1"), To: common.HexToAddress("0x2"), Amount: big.NewInt(2), Module: "staking",
+			Reason: types.BalanceChangeStakingLock, TxHash: common.HexToHash("0x3")},
+	}
+	hash := common.BytesToHash([]byte{0x03, 0x14})
+	if entry := ReadBalanceChanges(db, hash, 1); entry != nil {
+		t.Fatalf("non existent balance changes returned: %v", entry)
+	}
+	WriteBalanceChanges(db, hash, 1, changes)
+	if entry := ReadBalanceChanges(db, hash, 1); !reflect.DeepEqual(entry, changes) {
+		t.Fatalf("balance changes mismatch: have %v, want %v", entry, changes)
+	}
+	DeleteBlock(db, hash, 1)
+	if entry := ReadBalanceChanges(db, hash, 1); entry != nil {
+		t.Fatalf("deleted balance changes returned: %v", entry)
+	}
+}
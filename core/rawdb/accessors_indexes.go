@@ -17,6 +17,7 @@
 package rawdb
 
 import (
+	"encoding/binary"
 	"math/big"
 
 	"github.com/hashkey-chain/hashkey-chain/common"
@@ -92,6 +93,65 @@ func DeleteTxLookupEntries(db ethdb.KeyValueWriter, hashes []common.Hash) {
 	}
 }
 
+// The roles an address plays in a transaction indexed by address.
+const (
+	AddressTxFrom   uint8 = 1 << iota // The address is the sender
+	AddressTxTo                       // The address is the recipient
+	AddressTxCreate                   // The address is the contract created by the transaction
+	AddressTxPpos                     // The transaction calls a PPOS system contract
+)
+
+// AddressTxEntry is the positional metadata of a transaction indexed by address.
+type AddressTxEntry struct {
+	BlockNumber uint64
+	Index       uint32
+	Hash        common.Hash
+	Roles       uint8
+}
+
+// WriteAddressTxEntry stores the positional metadata of a transaction for
+// the address, enabling the transaction history lookups of the address.
+func WriteAddressTxEntry(db ethdb.KeyValueWriter, address common.Address, entry AddressTxEntry) {
+	value := append(entry.Hash.Bytes(), entry.Roles)
+	if err := db.Put(addrTxIndexKey(address, entry.BlockNumber, entry.Index), value); err != nil {
+		log.Crit("Failed to store address transaction entry", "err", err)
+	}
+}
+
+// DeleteAddressTxEntry removes the positional metadata of a transaction for the address.
+func DeleteAddressTxEntry(db ethdb.KeyValueWriter, address common.Address, number uint64, index uint32) {
+	if err := db.Delete(addrTxIndexKey(address, number, index)); err != nil {
+		log.Crit("Failed to delete address transaction entry", "err", err)
+	}
+}
+
+// ReadAddressTxEntries retrieves at most limit transaction entries of the address,
+// newest first, after skipping the first offset ones.
+func ReadAddressTxEntries(db ethdb.Iteratee, address common.Address, offset, limit int) []AddressTxEntry {
+	prefix := append(append([]byte{}, addrTxIndexPrefix...), address.Bytes()...)
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	var entries []AddressTxEntry
+	for it.Next() && len(entries) < limit {
+		key, value := it.Key(), it.Value()
+		if len(key) != len(prefix)+12 || len(value) != common.HashLength+1 {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		entries = append(entries, AddressTxEntry{
+			BlockNumber: ^binary.BigEndian.Uint64(key[len(prefix):]),
+			Index:       ^binary.BigEndian.Uint32(key[len(prefix)+8:]),
+			Hash:        common.BytesToHash(value[:common.HashLength]),
+			Roles:       value[common.HashLength],
+		})
+	}
+	return entries
+}
+
 // ReadTransaction retrieves a specific transaction from the database, along with
 // its added positional metadata.
 func ReadTransaction(db ethdb.Reader, hash common.Hash) (*types.Transaction, common.Hash, uint64, uint64) {
@@ -133,3 +133,62 @@ func TestLookupStorage(t *testing.T) {
 		})
 	}
 }
+
+// Tests that the transactions indexed by address are iterated newest first
+// and paginated.
+func TestAddressTxEntries(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	addr := common.BytesToAddress([]byte{0x11})
+	other := common.BytesToAddress([]byte{0x12})
+	var written []AddressTxEntry
+	for number := uint64(1); number <= 3; number++ {
+		for index := uint32(0); index < 2; index++ {
+			entry := AddressTxEntry{
+				BlockNumber: number,
+				Index:       index,
+				Hash:        common.BytesToHash([]byte{byte(number), byte(index)}),
+				Roles:       AddressTxFrom | AddressTxPpos,
+			}
+			WriteAddressTxEntry(db, addr, entry)
+			WriteAddressTxEntry(db, other, entry)
+			written = append(written, entry)
+		}
+	}
+	DeleteAddressTxEntry(db, other, 3, 1)
+
+	entries := ReadAddressTxEntries(db, addr, 0, 10)
+	if len(entries) != len(written) {
+		t.Fatalf("entries count mismatch: have %d, want %d", len(entries), len(written))
+	}
+	for i, entry := range entries {
+		if want := written[len(written)-1-i]; entry != want {
+			t.Fatalf("entry #%d mismatch: have %v, want %v", i, entry, want)
+		}
+	}
+	page := ReadAddressTxEntries(db, addr, 2, 3)
+	if len(page) != 3 || page[0] != entries[2] || page[2] != entries[4] {
+		t.Fatalf("page mismatch: have %v, want %v", page, entries[2:5])
+	}
+	if entries := ReadAddressTxEntries(db, other, 0, 10); len(entries) != len(written)-1 || entries[0].BlockNumber != 3 || entries[0].Index != 0 {
+		t.Fatalf("deleted entry still indexed: %v", entries)
+	}
+	if entries := ReadAddressTxEntries(db, common.BytesToAddress([]byte{0x13}), 0, 10); len(entries) != 0 {
+		t.Fatalf("unknown address has entries: %v", entries)
+	}
+	// The index metadata must stay out of the index key space
+	WriteAddressTxIndexTail(db, 1)
+	WriteAddressTxIndexHead(db, 3)
+	it := db.NewIterator(addrTxIndexPrefix, nil)
+	defer it.Release()
+	var keys int
+	for it.Next() {
+		if len(it.Key()) != len(addrTxIndexPrefix)+common.AddressLength+12 {
+			t.Fatalf("foreign key in the address index: %q", it.Key())
+		}
+		keys++
+	}
+	if keys != 2*len(written)-1 {
+		t.Fatalf("index keys count mismatch: have %d, want %d", keys, 2*len(written)-1)
+	}
+}
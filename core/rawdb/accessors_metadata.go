@@ -17,6 +17,7 @@
 package rawdb
 
 import (
+	"encoding/binary"
 	"encoding/json"
 
 	"github.com/hashkey-chain/hashkey-chain/ethdb"
@@ -144,3 +145,52 @@ func ReadEconomicModelExtend(db ethdb.Reader, hash common.Hash) *xcom.EconomicMo
 	}
 	return &ec
 }
+
+// economicVersionEntry is the stored entry of an activated economic version,
+// the model is stored by WriteEconomicModelExtend at the block hash.
+type economicVersionEntry struct {
+	Version uint32
+	Hash    common.Hash
+}
+
+// WriteEconomicVersion stores the economic version activated at the block.
+func WriteEconomicVersion(db ethdb.KeyValueWriter, version uint32, number uint64, hash common.Hash) {
+	data, err := rlp.EncodeToBytes(&economicVersionEntry{Version: version, Hash: hash})
+	if err != nil {
+		log.Crit("Failed to RLP encode economic version", "err", err)
+	}
+	if err := db.Put(economicVersionKey(number), data); err != nil {
+		log.Crit("Failed to store economic version", "err", err)
+	}
+}
+
+// ReadEconomicVersions retrieves the activated economic versions in the order
+// of the active blocks, with the extended economic models stored at the blocks.
+func ReadEconomicVersions(db ethdb.Database) []*xcom.EconomicVersion {
+	it := db.NewIterator(economicVersionPrefix, nil)
+	defer it.Release()
+
+	var versions []*xcom.EconomicVersion
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(economicVersionPrefix)+8 {
+			continue
+		}
+		var entry economicVersionEntry
+		if err := rlp.DecodeBytes(it.Value(), &entry); err != nil {
+			log.Error("Invalid economic version RLP", "key", key, "err", err)
+			continue
+		}
+		ece := ReadEconomicModelExtend(db, entry.Hash)
+		if ece == nil {
+			log.Error("Missing EconomicModelExtend of economic version", "version", entry.Version, "hash", entry.Hash)
+			continue
+		}
+		versions = append(versions, &xcom.EconomicVersion{
+			Version:     entry.Version,
+			BlockNumber: binary.BigEndian.Uint64(key[len(economicVersionPrefix):]),
+			Ece:         ece,
+		})
+	}
+	return versions
+}
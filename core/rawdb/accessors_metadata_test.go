@@ -27,6 +27,32 @@ func TestReadWriteEconomicModel(t *testing.T) {
 	t.Log(string(b))
 }
 
+func TestReadWriteEconomicVersions(t *testing.T) {
+
+	chainDb := NewMemoryDatabase()
+	assert.Empty(t, ReadEconomicVersions(chainDb))
+
+	ece130 := &xcom.EconomicModelExtend{}
+	ece130.Staking.UnDelegateFreezeDuration = 2
+	ece140 := ece130.Copy()
+	ece140.Staking.RewardPerNoticeEpochs = 1
+
+	hash130, hash140 := common.Hash{1}, common.Hash{2}
+	WriteEconomicModelExtend(chainDb, hash140, ece140)
+	WriteEconomicVersion(chainDb, params.FORKVERSION_1_4_0, 2000, hash140)
+	WriteEconomicModelExtend(chainDb, hash130, ece130)
+	WriteEconomicVersion(chainDb, params.FORKVERSION_1_3_0, 1000, hash130)
+
+	versions := ReadEconomicVersions(chainDb)
+	assert.Len(t, versions, 2)
+	assert.Equal(t, params.FORKVERSION_1_3_0, versions[0].Version)
+	assert.Equal(t, uint64(1000), versions[0].BlockNumber)
+	assert.Equal(t, ece130, versions[0].Ece)
+	assert.Equal(t, params.FORKVERSION_1_4_0, versions[1].Version)
+	assert.Equal(t, uint64(2000), versions[1].BlockNumber)
+	assert.Equal(t, ece140, versions[1].Ece)
+}
+
 func TestReadWriteChainConfig(t *testing.T) {
 
 	chainDb := NewMemoryDatabase()
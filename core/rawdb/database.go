@@ -271,6 +271,7 @@ func InspectDatabase(db ethdb.Database) error {
 		tries           stat
 		codes           stat
 		txLookups       stat
+		balanceChanges  stat
 		accountSnaps    stat
 		storageSnaps    stat
 		preimages       stat
@@ -308,6 +309,8 @@ func InspectDatabase(db ethdb.Database) error {
 			bodies.Add(size)
 		case bytes.HasPrefix(key, blockReceiptsPrefix) && len(key) == (len(blockReceiptsPrefix)+8+common.HashLength):
 			receipts.Add(size)
+		case bytes.HasPrefix(key, blockBalanceChangesPrefix) && len(key) == (len(blockBalanceChangesPrefix)+8+common.HashLength):
+			balanceChanges.Add(size)
 		case bytes.HasPrefix(key, headerPrefix) && bytes.HasSuffix(key, headerHashSuffix):
 			numHashPairings.Add(size)
 		case bytes.HasPrefix(key, headerNumberPrefix) && len(key) == (len(headerNumberPrefix)+common.HashLength):
@@ -365,6 +368,7 @@ func InspectDatabase(db ethdb.Database) error {
 		{"Key-Value store", "Headers", headers.Size(), headers.Count()},
 		{"Key-Value store", "Bodies", bodies.Size(), bodies.Count()},
 		{"Key-Value store", "Receipt lists", receipts.Size(), receipts.Count()},
+		{"Key-Value store", "Balance change lists", balanceChanges.Size(), balanceChanges.Count()},
 		{"Key-Value store", "Block number->hash", numHashPairings.Size(), numHashPairings.Count()},
 		{"Key-Value store", "Block hash->number", hashNumPairings.Size(), hashNumPairings.Count()},
 		{"Key-Value store", "Transaction index", txLookups.Size(), txLookups.Count()},
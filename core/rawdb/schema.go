@@ -51,21 +51,30 @@ var (
 	// fastTxLookupLimitKey tracks the transaction lookup limit during fast sync.
 	fastTxLookupLimitKey = []byte("FastTransactionLookupLimit")
 
+	// addrTxIndexTailKey tracks the oldest block whose transactions have been indexed by address.
+	addrTxIndexTailKey = []byte("AddressTransactionIndexTail")
+
+	// addrTxIndexHeadKey tracks the newest block whose transactions have been indexed by address.
+	addrTxIndexHeadKey = []byte("AddressTransactionIndexHead")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerHashSuffix   = []byte("n") // headerPrefix + num (uint64 big endian) + headerHashSuffix -> hash
 	headerNumberPrefix = []byte("H") // headerNumberPrefix + hash -> num (uint64 big endian)
 
-	blockBodyPrefix     = []byte("b") // blockBodyPrefix + num (uint64 big endian) + hash -> block body
-	blockReceiptsPrefix = []byte("r") // blockReceiptsPrefix + num (uint64 big endian) + hash -> block receipts
+	blockBodyPrefix           = []byte("b") // blockBodyPrefix + num (uint64 big endian) + hash -> block body
+	blockReceiptsPrefix       = []byte("r") // blockReceiptsPrefix + num (uint64 big endian) + hash -> block receipts
+	blockBalanceChangesPrefix = []byte("C") // blockBalanceChangesPrefix + num (uint64 big endian) + hash -> block balance changes
 
 	txLookupPrefix            = []byte("l")                        // txLookupPrefix + hash -> transaction/receipt lookup metadata
+	addrTxIndexPrefix         = []byte("addrTx-key-")              // addrTxIndexPrefix + address + ^num (uint64 big endian) + ^index (uint32 big endian) -> tx hash + roles
 	bloomBitsPrefix           = []byte("B")                        // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
 	codePrefix                = []byte("c")                        // codePrefix + code hash -> account code
 	preimagePrefix            = []byte("secure-key-")              // preimagePrefix + hash -> preimage
 	configPrefix              = []byte("ethereum-config-")         // config prefix for the db
 	economicModelPrefix       = []byte("economicModel-key-")       // economicModel prefix for the db
 	economicModelExtendPrefix = []byte("economicModelExtend-key-") // economicModelExtend prefix for the db
+	economicVersionPrefix     = []byte("economicVersion-key-")     // economicVersionPrefix + num (uint64 big endian) -> activated version and block hash
 
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
@@ -142,11 +151,29 @@ func blockReceiptsKey(number uint64, hash common.Hash) []byte {
 	return append(append(blockReceiptsPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
 }
 
+// blockBalanceChangesKey = blockBalanceChangesPrefix + num (uint64 big endian) + hash
+func blockBalanceChangesKey(number uint64, hash common.Hash) []byte {
+	return append(append(blockBalanceChangesPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
 // txLookupKey = txLookupPrefix + hash
 func txLookupKey(hash common.Hash) []byte {
 	return append(txLookupPrefix, hash.Bytes()...)
 }
 
+// addrTxIndexKey = addrTxIndexPrefix + address + ^num (uint64 big endian) + ^index (uint32 big endian)
+//
+// The number and the index are inverted so that the newest transactions of an
+// address are iterated first.
+func addrTxIndexKey(address common.Address, number uint64, index uint32) []byte {
+	key := make([]byte, len(addrTxIndexPrefix)+common.AddressLength+12)
+	copy(key, addrTxIndexPrefix)
+	copy(key[len(addrTxIndexPrefix):], address.Bytes())
+	binary.BigEndian.PutUint64(key[len(addrTxIndexPrefix)+common.AddressLength:], ^number)
+	binary.BigEndian.PutUint32(key[len(addrTxIndexPrefix)+common.AddressLength+8:], ^index)
+	return key
+}
+
 // bloomBitsKey = bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash
 func bloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	key := append(append(bloomBitsPrefix, make([]byte, 10)...), hash.Bytes()...)
@@ -190,3 +217,8 @@ func economicModelKey(hash common.Hash) []byte {
 func economicModelExtendKey(hash common.Hash) []byte {
 	return append(economicModelExtendPrefix, hash.Bytes()...)
 }
+
+// economicVersionKey = economicVersionPrefix + num (uint64 big endian)
+func economicVersionKey(number uint64) []byte {
+	return append(append([]byte{}, economicVersionPrefix...), encodeBlockNumber(number)...)
+}
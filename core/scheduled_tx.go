@@ -0,0 +1,141 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	cvm "github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core/snapshotdb"
+	"github.com/hashkey-chain/hashkey-chain/core/state"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/core/vm"
+	"github.com/hashkey-chain/hashkey-chain/log"
+	"github.com/hashkey-chain/hashkey-chain/rlp"
+	"github.com/hashkey-chain/hashkey-chain/x/gov"
+	"github.com/hashkey-chain/hashkey-chain/x/plugin"
+	"github.com/hashkey-chain/hashkey-chain/x/scheduled"
+	"github.com/hashkey-chain/hashkey-chain/x/xcom"
+)
+
+// executeScheduledTxs executes the scheduled transactions due at the block
+// before the transactions of the block, in the order they were scheduled. The
+// ones exceeding the governed count or gas of the block are deferred to the
+// next block, a transaction that cannot be applied, like one with a stale
+// nonce, is dropped and its escrow refunded.
+//
+// The transactions are executed as sponsored by the scheduled transaction
+// contract holding the escrow. Their fee is paid to the reward pool, since the
+// producer is not set to the header of the worker at the beginning of the
+// block, and their gas is not counted in the gas used by the block. They have
+// no receipt, the logs of an executed one are kept in its record instead.
+func (bcr *BlockChainReactor) executeScheduledTxs(blockHash common.Hash, header *types.Header, stateDB xcom.StateDB) error {
+	if !gov.Gte150VersionState(stateDB) {
+		return nil
+	}
+	exe := GetExecutor()
+	if exe.chainConfig == nil {
+		return ErrExecutorNotInitialized
+	}
+	statedb, ok := stateDB.(*state.StateDB)
+	if !ok {
+		return nil
+	}
+
+	blockNumber := header.Number.Uint64()
+	stp := plugin.ScheduledTxInstance()
+	due, err := stp.TakeDueScheduledTxs(blockNumber, stateDB)
+	if nil != err || len(due) == 0 {
+		return err
+	}
+	maxTxs, err := gov.GovernMaxScheduledTxsPerBlock(blockNumber, blockHash)
+	if nil != err {
+		return err
+	}
+	maxGas, err := gov.GovernMaxScheduledGasPerBlock(blockNumber, blockHash)
+	if nil != err {
+		return err
+	}
+
+	blockContext := NewEVMBlockContext(header, exe.chainContext)
+	blockContext.Coinbase = cvm.RewardManagerPoolAddr
+	evm := vm.NewEVM(blockContext, vm.TxContext{}, snapshotdb.Instance(), statedb, exe.chainConfig, exe.vmCfg)
+	gp := new(GasPool).AddGas(maxGas)
+
+	// The balance changes of the plugins made for a scheduled transaction
+	// carry its hash, the PPOS contracts take a zero hash for an estimation
+	defer statedb.SetTxContext(common.ZeroHash, 0)
+
+	var executed uint32
+	for i, id := range due {
+		if executed >= maxTxs {
+			return stp.DeferScheduledTxs(due[i:], stateDB)
+		}
+		stx, err := stp.GetScheduledTx(id, stateDB)
+		if nil != err {
+			return err
+		}
+		if !stx.Pending() {
+			continue
+		}
+		statedb.SetTxContext(stx.TxHash, 0)
+
+		tx := new(types.Transaction)
+		if err := rlp.DecodeBytes(stx.Tx, tx); nil != err {
+			if err := stp.FinishScheduledTx(stx, scheduled.StatusDropped, 0, common.Big0, err.Error(), nil, blockNumber, stateDB); nil != err {
+				return err
+			}
+			continue
+		}
+		if tx.Gas() > maxGas {
+			if err := stp.FinishScheduledTx(stx, scheduled.StatusDropped, 0, common.Big0, scheduled.ErrTxGasTooHigh.Msg, nil, blockNumber, stateDB); nil != err {
+				return err
+			}
+			continue
+		}
+		if gp.Gas() < tx.Gas() {
+			return stp.DeferScheduledTxs(due[i:], stateDB)
+		}
+
+		msg := types.NewSponsoredMessage(stx.Sender, cvm.ScheduledTxContractAddr, tx.To(), tx.Nonce(), tx.Value(),
+			tx.Gas(), tx.GasPrice(), tx.Data(), true)
+		snap := statedb.Snapshot()
+		evm.Reset(NewEVMTxContext(msg), statedb)
+		result, err := ApplyMessage(evm, msg, gp)
+		if nil != err {
+			statedb.RevertToSnapshot(snap)
+			log.Debug("Drop the scheduled transaction", "blockNumber", blockNumber, "id", id, "txHash", stx.TxHash, "err", err)
+			if err := stp.FinishScheduledTx(stx, scheduled.StatusDropped, 0, common.Big0, err.Error(), nil, blockNumber, stateDB); nil != err {
+				return err
+			}
+			continue
+		}
+		statedb.Finalise(true)
+		executed++
+
+		status, failure := scheduled.StatusExecuted, ""
+		if result.Failed() {
+			status, failure = scheduled.StatusReverted, result.Err.Error()
+		}
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(result.UsedGas), tx.GasPrice())
+		if err := stp.FinishScheduledTx(stx, status, result.UsedGas, fee, failure, statedb.GetLogs(stx.TxHash), blockNumber, stateDB); nil != err {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	cvm "github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core/rawdb"
+	"github.com/hashkey-chain/hashkey-chain/core/snapshotdb"
+	"github.com/hashkey-chain/hashkey-chain/core/state"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/core/vm"
+	"github.com/hashkey-chain/hashkey-chain/crypto"
+	"github.com/hashkey-chain/hashkey-chain/params"
+	"github.com/hashkey-chain/hashkey-chain/x/gov"
+	"github.com/hashkey-chain/hashkey-chain/x/plugin"
+	"github.com/hashkey-chain/hashkey-chain/x/scheduled"
+)
+
+func TestExecuteScheduledTxs(t *testing.T) {
+	sdb := snapshotdb.Instance()
+	defer sdb.Clear()
+	if _, err := gov.InitGenesisGovernParam(common.ZeroHash, sdb, params.FORKVERSION_1_5_0); err != nil {
+		t.Fatal(err)
+	}
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	if err := gov.AddActiveVersion(params.FORKVERSION_1_5_0, 0, statedb); err != nil {
+		t.Fatal(err)
+	}
+	NewExecutor(chainConfig, nil, vm.Config{}, nil)
+
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	submitter := common.HexToAddress("0x1000000000000000000000000000000000000101")
+	recipient := common.HexToAddress("0x1000000000000000000000000000000000000102")
+	gasPrice := big.NewInt(params.GHashi)
+	statedb.AddBalance(sender, big.NewInt(params.HSK))
+	statedb.AddBalance(submitter, big.NewInt(params.HSK))
+
+	signer := types.MakeScheduledSigner(chainConfig)
+	stp := plugin.ScheduledTxInstance()
+	var ids []uint64
+	// The second transaction has a nonce the sender won't reach, it's dropped
+	for _, nonce := range []uint64{0, 5, 1} {
+		tx, err := types.SignTx(types.NewTransaction(nonce, recipient, big.NewInt(1000), params.TxGas, gasPrice, nil), signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stx, err := stp.AddScheduledTx(submitter, sender, tx, scheduled.TargetBlock, 2, 1, common.ZeroHash, statedb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, stx.ID)
+	}
+	escrow := new(big.Int).Mul(new(big.Int).SetUint64(params.TxGas), gasPrice)
+	assert.Equal(t, new(big.Int).Mul(escrow, big.NewInt(3)), statedb.GetBalance(cvm.ScheduledTxContractAddr))
+
+	header := &types.Header{Number: big.NewInt(2), GasLimit: params.GenesisGasLimit, Coinbase: recipient}
+	bcr := &BlockChainReactor{}
+	if err := bcr.executeScheduledTxs(common.ZeroHash, header, statedb); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, status := range []scheduled.Status{scheduled.StatusExecuted, scheduled.StatusDropped, scheduled.StatusExecuted} {
+		stx, err := stp.GetScheduledTx(ids[i], statedb)
+		if assert.Nil(t, err) {
+			assert.Equal(t, status, stx.Status, "id %d", ids[i])
+			assert.Equal(t, uint64(2), stx.ExecBlock)
+		}
+	}
+	assert.Equal(t, uint64(2), statedb.GetNonce(sender))
+	assert.Equal(t, big.NewInt(2000), statedb.GetBalance(recipient))
+	// The fees are paid to the reward pool out of the escrow, the dropped one is refunded
+	fees := new(big.Int).Mul(escrow, big.NewInt(2))
+	assert.Equal(t, fees, statedb.GetBalance(cvm.RewardManagerPoolAddr))
+	assert.Equal(t, new(big.Int).Sub(big.NewInt(params.HSK), fees), statedb.GetBalance(submitter))
+	assert.Equal(t, 0, statedb.GetBalance(cvm.ScheduledTxContractAddr).Sign())
+	assert.Equal(t, common.ZeroHash, statedb.TxHash())
+
+	list, err := stp.GetScheduledTxsByAccount(submitter, statedb)
+	assert.Nil(t, err)
+	assert.Len(t, list, 0)
+	due, err := stp.TakeDueScheduledTxs(2, statedb)
+	assert.Nil(t, err)
+	assert.Len(t, due, 0)
+}
@@ -38,6 +38,7 @@ type blockData struct {
 	data       *memdb.DB
 	readOnly   bool
 	kvHash     common.Hash
+	writes     []journalData // The writes in order, kept until the journal is written
 
 	//only use for not commit block
 	journal        []journalEntry // Current changes tracked by the journal
@@ -74,7 +75,9 @@ func (b *blockData) EncodeRLP(w io.Writer) error {
 	jk.BlockNumber = new(big.Int).Set(b.Number)
 	jk.KvHash = b.kvHash
 	jk.Data = make([]journalData, 0)
-	if b.data.Size() != 0 {
+	if b.writes != nil {
+		jk.Data, jk.Ordered = b.writes, true
+	} else if b.data.Size() != 0 {
 		itr := b.data.NewIterator(nil)
 		defer itr.Release()
 		for itr.Next() {
@@ -101,6 +104,18 @@ func (b *blockData) BlockVal() []byte {
 	return val
 }
 
+// keepWrites keeps the writes of the block in order before the journal is
+// cleaned, so that the kv hash can be recomputed from the written journal.
+func (b *blockData) keepWrites() {
+	if len(b.journal) == 0 {
+		return
+	}
+	b.writes = make([]journalData, 0, len(b.journal))
+	for _, en := range b.journal {
+		b.writes = append(b.writes, journalData{Key: common.CopyBytes(en.key), Value: common.CopyBytes(en.newVal)})
+	}
+}
+
 func (b *blockData) cleanJournal() {
 	b.journal = nil
 	b.validRevisions = nil
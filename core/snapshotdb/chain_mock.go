@@ -35,7 +35,7 @@ func newTestchain(path string) *testchain {
 	}
 	ch.db = db
 	SetDBBlockChain(ch)
-	go ch.db.loopWriteWal()
+	go ch.db.loopWriteWal(ch.db.walCh, ch.db.walExitCh)
 
 	return ch
 }
@@ -52,7 +52,7 @@ func (c *testchain) reOpenSnapshotDB() {
 		panic(err)
 	}
 	c.db = db
-	go c.db.loopWriteWal()
+	go c.db.loopWriteWal(c.db.walCh, c.db.walExitCh)
 
 }
 
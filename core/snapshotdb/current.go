@@ -18,6 +18,7 @@
 package snapshotdb
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 
@@ -36,6 +37,17 @@ const (
 	CurrentSet          = "snapshotdbCurrentSet"
 )
 
+// IsInternalKey reports whether the key of baseDB belongs to the bookkeeping
+// of snapshotDB, the journals, the histories and the current, rather than to
+// the data of the blocks.
+func IsInternalKey(key []byte) bool {
+	switch string(key) {
+	case CurrentHighestBlock, CurrentBaseNum, CurrentAll, CurrentSet:
+		return true
+	}
+	return bytes.HasPrefix(key, []byte(WalKeyPrefix)) || bytes.HasPrefix(key, []byte(HistoryKeyPrefix))
+}
+
 func (s *snapshotDB) loadCurrent() error {
 	ct := new(current)
 	if err := ct.loadFromBaseDB(s.baseDB); err != nil {
@@ -143,6 +143,9 @@ func (s *snapshotDB) getUnRecognizedHash() common.Hash {
 }
 
 func (s *snapshotDB) put(hash common.Hash, key, value []byte) error {
+	if r := s.replayOf(hash); r != nil {
+		return r.put(hash, key, value)
+	}
 	s.unCommit.Lock()
 	defer s.unCommit.Unlock()
 	block, ok := s.unCommit.blocks[hash]
@@ -0,0 +1,460 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/memdb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+)
+
+const (
+	HistoryKeyPrefix = "history-"
+
+	// HistoryRemain is the number of blocks written to baseDB whose former
+	// values are retained for the replay. The history of a block is deleted
+	// when the block HistoryRemain above it is written to baseDB, so a replay
+	// can only begin on a block at most HistoryRemain below the base.
+	HistoryRemain = 2048
+)
+
+// ErrHistoryUnavailable is returned when the data of the block to replay on
+// is no longer retained.
+var ErrHistoryUnavailable = fmt.Errorf("snapshotDB: historical data unavailable, only the blocks at most %d below the base of baseDB can be replayed", HistoryRemain)
+
+func EncodeHistoryKey(blockNum *big.Int) []byte {
+	return append([]byte(HistoryKeyPrefix), blockNum.Bytes()...)
+}
+
+// HistoryKey is the key of the former values of the block in baseDB.
+func (b *blockData) HistoryKey() []byte {
+	return EncodeHistoryKey(b.Number)
+}
+
+// historyOf returns the values of the keys changed by the block before it is
+// written to baseDB, an empty value means the key doesn't exist. The former
+// holds the values of all the keys of the batch, see formerValues.
+func historyOf(block *blockData, former map[string][]byte) (*blockData, error) {
+	history := &blockData{
+		BlockHash:  block.BlockHash,
+		ParentHash: block.ParentHash,
+		Number:     new(big.Int).Set(block.Number),
+		data:       memdb.New(DefaultComparer, block.data.Len()),
+		readOnly:   true,
+	}
+	itr := block.data.NewIterator(nil)
+	defer itr.Release()
+	for itr.Next() {
+		if err := history.data.Put(common.CopyBytes(itr.Key()), common.CopyBytes(former[string(itr.Key())])); err != nil {
+			return nil, err
+		}
+	}
+	return history, nil
+}
+
+// formerValues reads the values in baseDB of the keys changed by the blocks
+// in one pass in key order, the missing keys have empty values.
+func (s *snapshotDB) formerValues(blocks []*blockData) (map[string][]byte, error) {
+	former := make(map[string][]byte)
+	var keys []string
+	for _, block := range blocks {
+		itr := block.data.NewIterator(nil)
+		for itr.Next() {
+			if _, ok := former[string(itr.Key())]; !ok {
+				former[string(itr.Key())] = nil
+				keys = append(keys, string(itr.Key()))
+			}
+		}
+		itr.Release()
+	}
+	sort.Strings(keys)
+
+	itr := s.baseDB.NewIterator(nil, nil)
+	defer itr.Release()
+	for _, key := range keys {
+		if itr.Seek([]byte(key)) && string(itr.Key()) == key {
+			former[key] = common.CopyBytes(itr.Value())
+		}
+	}
+	return former, itr.Error()
+}
+
+// Replay is a read-only view of the data at a committed block. The blocks
+// re-executed on top of the block, by the chain or NewBlock, keep their
+// writes in the view, the live data, the compaction and the other views are
+// not affected. A block belongs to one view at a time, a view beginning on
+// or creating the block of another one waits until that one ends.
+type Replay interface {
+	// NewBlock creates a block simulated on top of the view, unlike the
+	// blocks of the chain it is kept in the view whatever its number.
+	NewBlock(blockNumber *big.Int, parentHash common.Hash, hash common.Hash) error
+
+	// BlockData returns the data written by the block of the view, the
+	// deleted keys have empty values.
+	BlockData(hash common.Hash) ([][2][]byte, error)
+
+	// End drops the view.
+	End()
+}
+
+// replay is the view of the data at a committed block.
+type replay struct {
+	db     *snapshotDB
+	number *big.Int
+	hash   common.Hash
+	base   *leveldb.Snapshot // baseDB when the view began
+
+	blocks    map[common.Hash]*blockData // The blocks re-executed on the view
+	committed []*blockData               // The committed blocks at or below the view, the latest first
+	history   []*blockData               // The former values of the blocks in baseDB above the view, ascending
+
+	lock sync.Mutex
+}
+
+// BeginReplay returns the view of the data at the committed block, so the
+// historical blocks read and write the data as they did when they were
+// executed. The view must be ended once the re-execution is done.
+func (s *snapshotDB) BeginReplay(number *big.Int, hash common.Hash) (Replay, error) {
+	if number == nil {
+		return nil, errors.New("[SnapshotDB]the blockNumber must not be nil ")
+	}
+	r := &replay{
+		db:     s,
+		number: new(big.Int).Set(number),
+		hash:   hash,
+		blocks: make(map[common.Hash]*blockData),
+	}
+	s.own(r, hash)
+	if err := r.load(); err != nil {
+		r.End()
+		return nil, err
+	}
+	logger.Info("begin replay", "num", number, "hash", hash)
+	return r, nil
+}
+
+// load takes the committed blocks and the baseDB at the same time, then the
+// history of the blocks above the view from that baseDB.
+func (r *replay) load() error {
+	s := r.db
+	highest := s.current.GetHighest(true)
+	if r.number.Cmp(highest.Num) > 0 {
+		return fmt.Errorf("[SnapshotDB]replay fail,the block num %v is greater than HighestNum %v", r.number, highest.Num)
+	}
+
+	// The block is known once the block at or above it is found
+	known := false
+	s.commitLock.RLock()
+	base := s.current.GetBase(true).Num.Uint64()
+	if base > r.number.Uint64()+HistoryRemain {
+		s.commitLock.RUnlock()
+		return ErrHistoryUnavailable
+	}
+	snapshot, err := s.baseDB.GetSnapshot()
+	if err != nil {
+		s.commitLock.RUnlock()
+		return err
+	}
+	r.base = snapshot
+	for i := len(s.committed) - 1; i >= 0; i-- {
+		block := s.committed[i]
+		if block.Number.Cmp(r.number) > 0 {
+			if block.Number.Uint64() == r.number.Uint64()+1 && block.ParentHash != r.hash {
+				s.commitLock.RUnlock()
+				return fmt.Errorf("[SnapshotDB]replay fail,the block %v is not committed, committed %v", r.hash.String(), block.ParentHash.String())
+			}
+			known = known || block.Number.Uint64() == r.number.Uint64()+1
+			continue
+		}
+		if block.Number.Cmp(r.number) == 0 {
+			if block.BlockHash != r.hash {
+				s.commitLock.RUnlock()
+				return fmt.Errorf("[SnapshotDB]replay fail,the block %v is not committed, committed %v", r.hash.String(), block.BlockHash.String())
+			}
+			known = true
+		}
+		r.committed = append(r.committed, block)
+	}
+	s.commitLock.RUnlock()
+
+	// The blocks above the base may be written to baseDB before they are
+	// removed from the committed blocks, their history is in baseDB too.
+	for num := r.number.Uint64() + 1; ; num++ {
+		val, err := r.base.Get(EncodeHistoryKey(new(big.Int).SetUint64(num)), nil)
+		if err == leveldb.ErrNotFound {
+			if num <= base {
+				return ErrHistoryUnavailable
+			}
+			break
+		} else if err != nil {
+			return err
+		}
+		block, err := s.getBlockFromWal(val)
+		if err != nil {
+			return err
+		}
+		if num == r.number.Uint64()+1 && block.ParentHash != r.hash {
+			return fmt.Errorf("[SnapshotDB]replay fail,the block %v is not committed, committed %v", r.hash.String(), block.ParentHash.String())
+		}
+		known = true
+		r.history = append(r.history, block)
+	}
+	if known {
+		return nil
+	}
+	// The block is the base, its own history is kept unless it is pruned
+	val, err := r.base.Get(EncodeHistoryKey(r.number), nil)
+	if err == leveldb.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	block, err := s.getBlockFromWal(val)
+	if err != nil {
+		return err
+	}
+	if block.BlockHash != r.hash {
+		return fmt.Errorf("[SnapshotDB]replay fail,the block %v is not committed, committed %v", r.hash.String(), block.BlockHash.String())
+	}
+	return nil
+}
+
+// own makes the block belong to the view, it waits until the view owning
+// the block ends. The views own the blocks in ascending order, so they never
+// wait for each other.
+func (s *snapshotDB) own(r *replay, hash common.Hash) {
+	s.replayMu.Lock()
+	defer s.replayMu.Unlock()
+	if s.replays == nil {
+		s.replays = make(map[common.Hash]*replay)
+		s.replayCond = sync.NewCond(&s.replayMu)
+	}
+	for {
+		if owner, ok := s.replays[hash]; !ok || owner == r {
+			break
+		}
+		s.replayCond.Wait()
+	}
+	s.replays[hash] = r
+}
+
+// replayOf returns the view owning the block.
+func (s *snapshotDB) replayOf(hash common.Hash) *replay {
+	if hash == common.ZeroHash {
+		return nil
+	}
+	s.replayMu.Lock()
+	defer s.replayMu.Unlock()
+	return s.replays[hash]
+}
+
+func (r *replay) End() {
+	s := r.db
+	s.replayMu.Lock()
+	for hash, owner := range s.replays {
+		if owner == r {
+			delete(s.replays, hash)
+		}
+	}
+	s.replayCond.Broadcast()
+	s.replayMu.Unlock()
+
+	if r.base != nil {
+		r.base.Release()
+	}
+	logger.Info("end replay", "num", r.number, "hash", r.hash, "blocks", len(r.blocks))
+}
+
+// newBlock creates the block re-executed on top of the view, a block of the
+// chain replaces the one of a former re-execution.
+func (r *replay) newBlock(blockNumber *big.Int, parentHash common.Hash, hash common.Hash) {
+	r.db.own(r, hash)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.blocks[hash] = &blockData{
+		BlockHash:      hash,
+		ParentHash:     parentHash,
+		Number:         new(big.Int).Set(blockNumber),
+		data:           memdb.New(DefaultComparer, 100),
+		journal:        make([]journalEntry, 0),
+		validRevisions: make([]revision, 0),
+	}
+}
+
+func (r *replay) NewBlock(blockNumber *big.Int, parentHash common.Hash, hash common.Hash) error {
+	if blockNumber == nil {
+		return errors.New("[SnapshotDB]the blockNumber must not be nil ")
+	}
+	if hash == common.ZeroHash {
+		return errors.New("[SnapshotDB]the block of the replay must have a hash")
+	}
+	r.lock.Lock()
+	_, exist := r.blocks[hash]
+	_, parent := r.blocks[parentHash]
+	r.lock.Unlock()
+	if exist || hash == r.hash {
+		return fmt.Errorf("[SnapshotDB]the block %v is exist in the replay", hash.String())
+	}
+	if !parent && parentHash != r.hash {
+		return fmt.Errorf("[SnapshotDB]the parent %v is not in the replay", parentHash.String())
+	}
+	r.newBlock(blockNumber, parentHash, hash)
+	logger.Debug("NewBlock on replay", "num", blockNumber, "hash", hash, "parent", parentHash)
+	return nil
+}
+
+func (r *replay) BlockData(hash common.Hash) ([][2][]byte, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	block, ok := r.blocks[hash]
+	if !ok {
+		return nil, fmt.Errorf("[SnapshotDB]the block %v is not in the replay", hash.String())
+	}
+	var kvs [][2][]byte
+	itr := block.data.NewIterator(nil)
+	defer itr.Release()
+	for itr.Next() {
+		kvs = append(kvs, [2][]byte{common.CopyBytes(itr.Key()), common.CopyBytes(itr.Value())})
+	}
+	return kvs, nil
+}
+
+func (r *replay) put(hash common.Hash, key, value []byte) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	block, ok := r.blocks[hash]
+	if !ok {
+		return errors.New("can't put read only block")
+	}
+	return block.Write(key, value)
+}
+
+// get returns the value at the block of the view.
+func (r *replay) get(hash common.Hash, key []byte) ([]byte, error) {
+	v, err := r.find(hash, key)
+	if err == memdb.ErrNotFound {
+		v, err = r.base.Get(key, nil)
+		if err == leveldb.ErrNotFound {
+			return nil, ErrNotFound
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(v) == 0 {
+		return nil, ErrNotFound
+	}
+	return v, nil
+}
+
+// find returns the value changed above the baseDB of the view.
+func (r *replay) find(hash common.Hash, key []byte) ([]byte, error) {
+	r.lock.Lock()
+	for {
+		block, ok := r.blocks[hash]
+		if !ok {
+			break
+		}
+		if v, err := block.data.Get(key); err != memdb.ErrNotFound {
+			r.lock.Unlock()
+			return v, err
+		}
+		hash = block.ParentHash
+	}
+	r.lock.Unlock()
+
+	for _, block := range r.committed {
+		if v, err := block.data.Get(key); err != memdb.ErrNotFound {
+			return v, err
+		}
+	}
+	for _, block := range r.history {
+		if v, err := block.data.Get(key); err != memdb.ErrNotFound {
+			return v, err
+		}
+	}
+	return nil, memdb.ErrNotFound
+}
+
+// iterators returns the iterators of the view at the block above baseDB, the
+// latest first.
+func (r *replay) iterators(hash common.Hash, prefix *util.Range) []iterator.Iterator {
+	var itrs []iterator.Iterator
+	r.lock.Lock()
+	for {
+		block, ok := r.blocks[hash]
+		if !ok {
+			break
+		}
+		itrs = append(itrs, block.data.NewIterator(prefix))
+		hash = block.ParentHash
+	}
+	r.lock.Unlock()
+	for _, block := range r.committed {
+		itrs = append(itrs, block.data.NewIterator(prefix))
+	}
+	if len(r.history) > 0 {
+		// The former value of the earliest block wins
+		mdb := memdb.New(DefaultComparer, 100)
+		for _, block := range r.history {
+			itr := block.data.NewIterator(prefix)
+			for itr.Next() {
+				if !mdb.Contains(itr.Key()) {
+					mdb.Put(itr.Key(), itr.Value())
+				}
+			}
+			itr.Release()
+		}
+		itrs = append(itrs, mdb.NewIterator(nil))
+	}
+	return itrs
+}
+
+func (r *replay) lastKVHash(hash common.Hash) []byte {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if block, ok := r.blocks[hash]; ok {
+		return block.kvHash.Bytes()
+	}
+	return nil
+}
+
+func (r *replay) snapshot(hash common.Hash) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if block, ok := r.blocks[hash]; ok {
+		return block.Snapshot()
+	}
+	return 0
+}
+
+func (r *replay) revertToSnapshot(hash common.Hash, revid int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if block, ok := r.blocks[hash]; ok {
+		block.RevertToSnapshot(revid)
+	}
+}
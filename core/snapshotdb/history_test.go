@@ -0,0 +1,248 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+)
+
+func TestSnapshotDB_Replay(t *testing.T) {
+	ch := newTestchain(dbpath)
+	defer ch.clear()
+
+	var (
+		k1, k2, k3 = []byte("k1"), []byte("k2"), []byte("k3")
+	)
+	blocks := []struct {
+		kvs kvs
+		f   func(db *snapshotDB, kvs kvs, head *types.Header) error
+	}{
+		{kvs{kv{k1, []byte("v1")}, kv{k2, []byte("a")}}, newBlockBaseDB},
+		{kvs{kv{k1, []byte("v2")}, kv{k3, []byte("c")}}, newBlockBaseDB},
+		{kvs{kv{k2, nil}}, newBlockBaseDB},
+		{kvs{kv{k1, []byte("v4")}}, newBlockCommited},
+	}
+	for _, b := range blocks {
+		if err := ch.insert(true, b.kvs, b.f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ch.db.walSync.Wait()
+
+	expect := func(hash common.Hash, key, want []byte) {
+		t.Helper()
+		v, err := ch.db.Get(hash, key)
+		if want == nil {
+			if err != ErrNotFound {
+				t.Errorf("%s: want not found, have %s %v", key, v, err)
+			}
+			return
+		}
+		if err != nil || !bytes.Equal(v, want) {
+			t.Errorf("%s: want %s, have %s %v", key, want, v, err)
+		}
+	}
+
+	t.Run("replay on baseDB", func(t *testing.T) {
+		h1, h2 := ch.h[0], ch.h[1]
+		replay, err := ch.db.BeginReplay(h1.Number, h1.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect(h1.Hash(), k1, []byte("v1"))
+		expect(h1.Hash(), k2, []byte("a"))
+		expect(h1.Hash(), k3, nil)
+
+		itr := ch.db.Ranking(h1.Hash(), []byte("k"), 0)
+		var keys []string
+		for itr.Next() {
+			keys = append(keys, string(itr.Key()))
+		}
+		itr.Release()
+		if len(keys) != 2 || keys[0] != "k1" || keys[1] != "k2" {
+			t.Errorf("ranking on replay, have %v", keys)
+		}
+
+		if err := ch.db.NewBlock(h2.Number, h2.ParentHash, h2.Hash()); err != nil {
+			t.Fatal(err)
+		}
+		if err := ch.db.Put(h2.Hash(), k1, []byte("x")); err != nil {
+			t.Fatal(err)
+		}
+		expect(h2.Hash(), k1, []byte("x"))
+		expect(h2.Hash(), k2, []byte("a"))
+		if len(ch.db.GetLastKVHash(h2.Hash())) == 0 {
+			t.Error("kv hash of the replayed block is empty")
+		}
+
+		// The compaction goes on, the view keeps the baseDB it began on
+		if err := ch.db.Compaction(); err != nil {
+			t.Fatal(err)
+		}
+		if base := ch.db.current.GetBase(false).Num; base.Cmp(ch.h[3].Number) != 0 {
+			t.Errorf("compaction is paused by the replay, base %v", base)
+		}
+		expect(h1.Hash(), k1, []byte("v1"))
+		expect(h2.Hash(), k1, []byte("x"))
+		replay.End()
+
+		if v, err := ch.db.GetBaseDB(k1); err != nil || !bytes.Equal(v, []byte("v4")) {
+			t.Errorf("baseDB is not compacted, have %s %v", v, err)
+		}
+		if v, err := ch.db.Get(h2.Hash(), k1); err != nil || !bytes.Equal(v, []byte("v4")) {
+			t.Errorf("the replayed block is kept after the replay, have %s %v", v, err)
+		}
+	})
+
+	t.Run("replay on committed", func(t *testing.T) {
+		for _, c := range []struct {
+			index int
+			v1    []byte
+			v2    []byte
+		}{
+			{2, []byte("v2"), nil},
+			{3, []byte("v4"), nil},
+		} {
+			h := ch.h[c.index]
+			replay, err := ch.db.BeginReplay(h.Number, h.Hash())
+			if err != nil {
+				t.Fatal(err)
+			}
+			expect(h.Hash(), k1, c.v1)
+			expect(h.Hash(), k2, c.v2)
+			expect(h.Hash(), k3, []byte("c"))
+			replay.End()
+		}
+	})
+
+	t.Run("simulate on replay", func(t *testing.T) {
+		h := ch.h[3]
+		sim := generateHash("simulated")
+		replay, err := ch.db.BeginReplay(h.Number, h.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := replay.NewBlock(new(big.Int).Add(h.Number, common.Big1), generateHash("fork"), sim); err == nil {
+			t.Error("simulated a block on the parent out of the replay")
+		}
+		if err := replay.NewBlock(new(big.Int).Add(h.Number, common.Big1), h.Hash(), sim); err != nil {
+			t.Fatal(err)
+		}
+		if err := ch.db.Put(sim, k2, []byte("b")); err != nil {
+			t.Fatal(err)
+		}
+		if err := ch.db.Del(sim, k3); err != nil {
+			t.Fatal(err)
+		}
+		expect(sim, k1, []byte("v4"))
+		expect(sim, k2, []byte("b"))
+		expect(sim, k3, nil)
+
+		kvs, err := replay.BlockData(sim)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(kvs) != 2 || string(kvs[0][0]) != "k2" || string(kvs[0][1]) != "b" || string(kvs[1][0]) != "k3" || len(kvs[1][1]) != 0 {
+			t.Errorf("data of the simulated block, have %q", kvs)
+		}
+		replay.End()
+
+		if err := ch.db.Put(sim, k2, []byte("b")); err == nil {
+			t.Error("the simulated block is kept after the replay")
+		}
+		expect(h.Hash(), k3, []byte("c"))
+	})
+
+	t.Run("concurrent replays", func(t *testing.T) {
+		h1, h2, h3 := ch.h[0], ch.h[1], ch.h[2]
+		r1, err := ch.db.BeginReplay(h1.Number, h1.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		r3, err := ch.db.BeginReplay(h3.Number, h3.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect(h1.Hash(), k1, []byte("v1"))
+		expect(h3.Hash(), k1, []byte("v2"))
+
+		// The block re-executed on the first view is owned by it
+		if err := ch.db.NewBlock(h2.Number, h2.ParentHash, h2.Hash()); err != nil {
+			t.Fatal(err)
+		}
+		began := make(chan Replay)
+		go func() {
+			r2, err := ch.db.BeginReplay(h2.Number, h2.Hash())
+			if err != nil {
+				t.Error(err)
+			}
+			began <- r2
+		}()
+		select {
+		case <-began:
+			t.Fatal("the replay began on the block of another one")
+		case <-time.After(50 * time.Millisecond):
+		}
+		r1.End()
+		r2 := <-began
+		if r2 == nil {
+			t.FailNow()
+		}
+		expect(h2.Hash(), k1, []byte("v2"))
+		expect(h2.Hash(), k2, []byte("a"))
+		r2.End()
+		r3.End()
+	})
+
+	t.Run("replay on unknown block", func(t *testing.T) {
+		if replay, err := ch.db.BeginReplay(ch.h[0].Number, generateHash("fork")); err == nil {
+			t.Error("replay on the block not committed")
+			replay.End()
+		}
+		if replay, err := ch.db.BeginReplay(ch.h[3].Number, generateHash("fork")); err == nil {
+			t.Error("replay on the block not committed")
+			replay.End()
+		}
+	})
+
+	t.Run("history is pruned", func(t *testing.T) {
+		if err := ch.db.DelBaseDB(EncodeHistoryKey(ch.h[1].Number)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ch.db.BeginReplay(ch.h[0].Number, ch.h[0].Hash()); err != ErrHistoryUnavailable {
+			t.Errorf("want %v, have %v", ErrHistoryUnavailable, err)
+		}
+		if itr := ch.db.baseDB.NewIterator(util.BytesPrefix([]byte(HistoryKeyPrefix)), nil); true {
+			defer itr.Release()
+			count := 0
+			for itr.Next() {
+				count++
+			}
+			if count != 3 {
+				t.Errorf("want 3 history, have %d", count)
+			}
+		}
+	})
+}
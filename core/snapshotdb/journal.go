@@ -43,12 +43,18 @@ type blockWal struct {
 	BlockNumber *big.Int `rlp:"nil"`
 	KvHash      common.Hash
 	Data        []journalData
+	// Ordered is set if Data holds the writes in the order they were made,
+	// the kv hash of the block can then be recomputed from the journal
+	Ordered bool `rlp:"optional"`
 }
 
-func (s *snapshotDB) loopWriteWal() {
+// loopWriteWal writes the committed blocks to the journal until the db is
+// closed. The channels are the ones the db was started with, the fields are
+// replaced when the instance is opened again.
+func (s *snapshotDB) loopWriteWal(walCh chan *blockData, walExitCh chan struct{}) {
 	for {
 		select {
-		case block := <-s.walCh:
+		case block := <-walCh:
 			if err := s.writeWal(block); err != nil {
 				logger.Error("asynchronous write Journal fail", "err", err, "block", block.Number, "hash", block.BlockHash.String())
 				s.dbError = err
@@ -63,9 +69,9 @@ func (s *snapshotDB) loopWriteWal() {
 				continue
 			}
 			s.walSync.Done()
-		case <-s.walExitCh:
+		case <-walExitCh:
 			logger.Info("loopWriteWal exist")
-			close(s.walCh)
+			close(walCh)
 			return
 		}
 	}
@@ -77,5 +83,9 @@ func (s *snapshotDB) writeBlockToWalAsynchronous(block *blockData) {
 }
 
 func (s *snapshotDB) writeWal(block *blockData) error {
-	return s.baseDB.Put(block.BlockKey(), block.BlockVal(), nil)
+	if err := s.baseDB.Put(block.BlockKey(), block.BlockVal(), nil); err != nil {
+		return err
+	}
+	block.writes = nil
+	return nil
 }
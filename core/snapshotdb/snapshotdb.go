@@ -101,6 +101,10 @@ type DB interface {
 	//ues to Revert failed tx
 	RevertToSnapshot(hash common.Hash, revid int)
 	Snapshot(hash common.Hash) int
+
+	// BeginReplay returns a read-only view of the data at the committed block,
+	// the blocks re-executed on top of it are kept in the view
+	BeginReplay(number *big.Int, hash common.Hash) (Replay, error)
 }
 
 type BaseDB interface {
@@ -154,6 +158,10 @@ type snapshotDB struct {
 
 	corn *cron.Cron
 
+	replays    map[common.Hash]*replay // The views by the blocks they own
+	replayMu   sync.Mutex
+	replayCond *sync.Cond
+
 	closed bool
 
 	dbError error
@@ -317,7 +325,7 @@ func (s *snapshotDB) Start() error {
 	if err := s.cornStart(); err != nil {
 		return err
 	}
-	go s.loopWriteWal()
+	go s.loopWriteWal(s.walCh, s.walExitCh)
 	return nil
 }
 
@@ -404,6 +412,9 @@ func (s *snapshotDB) DelBaseDB(key []byte) error {
 // if hash is nil ,get unRecognized block lastkv hash,
 // else, get recognized block lastkv  hash
 func (s *snapshotDB) GetLastKVHash(blockHash common.Hash) []byte {
+	if r := s.replayOf(blockHash); r != nil {
+		return r.lastKVHash(blockHash)
+	}
 	block := s.unCommit.Get(blockHash)
 	if block == nil {
 		return nil
@@ -519,7 +530,15 @@ func (s *snapshotDB) findToWrite() int {
 
 func (s *snapshotDB) writeToBasedb(commitNum int) error {
 	batch := new(leveldb.Batch)
+	written, err := s.formerValues(s.committed[:commitNum])
+	if err != nil {
+		return err
+	}
 	for i := 0; i < commitNum; i++ {
+		history, err := historyOf(s.committed[i], written)
+		if err != nil {
+			return err
+		}
 		itr := s.committed[i].data.NewIterator(nil)
 		for itr.Next() {
 			if itr.Value() == nil || len(itr.Value()) == 0 {
@@ -527,9 +546,15 @@ func (s *snapshotDB) writeToBasedb(commitNum int) error {
 			} else {
 				batch.Put(itr.Key(), itr.Value())
 			}
+			written[string(itr.Key())] = common.CopyBytes(itr.Value())
 		}
 		batch.Delete(s.committed[i].BlockKey())
 		itr.Release()
+
+		batch.Put(history.HistoryKey(), history.BlockVal())
+		if num := s.committed[i].Number.Uint64(); num > HistoryRemain {
+			batch.Delete(EncodeHistoryKey(new(big.Int).SetUint64(num - HistoryRemain)))
+		}
 	}
 	logger.Debug("write to basedb", "from", s.committed[0].Number, "to", s.committed[commitNum-1].Number, "len", len(s.committed), "commitNum", commitNum)
 	if err := s.baseDB.Write(batch, nil); err != nil {
@@ -547,6 +572,14 @@ func (s *snapshotDB) NewBlock(blockNumber *big.Int, parentHash common.Hash, hash
 	if blockNumber == nil {
 		return errors.New("[SnapshotDB]the blockNumber must not be nil ")
 	}
+	// the blocks of the chain are never created again except on a replay
+	if hash != common.ZeroHash && s.current.GetHighest(false).Num.Cmp(blockNumber) >= 0 {
+		if r := s.replayOf(parentHash); r != nil {
+			r.newBlock(blockNumber, parentHash, hash)
+			logger.Debug("NewBlock on replay", "num", blockNumber, "hash", hash, "parent", parentHash)
+			return nil
+		}
+	}
 	if s.current.GetHighest(false).Num.Cmp(blockNumber) >= 0 {
 		logger.Error("the block is less than commit highest", "commit", s.current.GetHighest(false).Num, "new", blockNumber)
 		return ErrBlockTooLow
@@ -565,6 +598,10 @@ func (s *snapshotDB) NewBlock(blockNumber *big.Int, parentHash common.Hash, hash
 }
 
 func (s *snapshotDB) RevertToSnapshot(hash common.Hash, revid int) {
+	if r := s.replayOf(hash); r != nil {
+		r.revertToSnapshot(hash, revid)
+		return
+	}
 	s.unCommit.Lock()
 	defer s.unCommit.Unlock()
 	block, ok := s.unCommit.blocks[hash]
@@ -573,6 +610,9 @@ func (s *snapshotDB) RevertToSnapshot(hash common.Hash, revid int) {
 	}
 }
 func (s *snapshotDB) Snapshot(hash common.Hash) int {
+	if r := s.replayOf(hash); r != nil {
+		return r.snapshot(hash)
+	}
 	s.unCommit.Lock()
 	defer s.unCommit.Unlock()
 	block, ok := s.unCommit.blocks[hash]
@@ -637,6 +677,9 @@ func (s *snapshotDB) getFromCommit(key []byte) ([]byte, error) {
 // if hash is nil, unRecognizedBlockData > RecognizedBlockData > CommittedBlockData > baseDB
 // if hash is not nil,it will find from the chain, RecognizedBlockData > CommittedBlockData > baseDB
 func (s *snapshotDB) Get(hash common.Hash, key []byte) ([]byte, error) {
+	if r := s.replayOf(hash); r != nil {
+		return r.get(hash, key)
+	}
 	v, err := s.getFromUnCommit(hash, key)
 	if err != nil && err != ErrNotFound {
 		return nil, err
@@ -709,6 +752,7 @@ func (s *snapshotDB) Flush(hash common.Hash, blockNumber *big.Int) error {
 	s.unCommit.Lock()
 	block.BlockHash = hash
 	block.readOnly = true
+	block.keepWrites()
 	block.cleanJournal()
 	s.unCommit.blocks[hash] = block
 	delete(s.unCommit.blocks, common.ZeroHash)
@@ -763,6 +807,7 @@ func (s *snapshotDB) Commit(hash common.Hash) error {
 	}
 
 	block.readOnly = true
+	block.keepWrites()
 	s.writeBlockToWalAsynchronous(block)
 
 	s.commitLock.Lock()
@@ -828,6 +873,9 @@ func (s *snapshotDB) Clear() error {
 func (s *snapshotDB) Ranking(hash common.Hash, key []byte, rangeNumber int) iterator.Iterator {
 	prefix := util.BytesPrefix(key)
 	var itrs []iterator.Iterator
+	if r := s.replayOf(hash); r != nil {
+		return s.ranking(r.iterators(hash, prefix), r.base.NewIterator(prefix, nil), rangeNumber)
+	}
 	var parentHash common.Hash
 	parentHash = hash
 	s.unCommit.RLock()
@@ -849,14 +897,20 @@ func (s *snapshotDB) Ranking(hash common.Hash, key []byte, rangeNumber int) iter
 		}
 	}
 	s.commitLock.RUnlock()
+	return s.ranking(itrs, s.baseDB.NewIterator(prefix, nil), rangeNumber)
+}
+
+// ranking merges the iterators of the blocks, the latest first, with the one
+// of baseDB.
+func (s *snapshotDB) ranking(itrs []iterator.Iterator, base iterator.Iterator, rangeNumber int) iterator.Iterator {
 	//put  unCommit and commit itr to heap
 	rankingHeap := newRankingHeap(rangeNumber)
 	for i := 0; i < len(itrs); i++ {
 		rankingHeap.itr2Heap(itrs[i], false, false)
 	}
 	//put baseDB itr to heap
-	itr := s.baseDB.NewIterator(prefix, nil)
-	rankingHeap.itr2Heap(itr, true, true)
+	rankingHeap.itr2Heap(base, true, true)
+	base.Release()
 	//generate memdb Iterator
 	mdb := memdb.New(DefaultComparer, rangeNumber)
 	for rankingHeap.heap.Len() > 0 {
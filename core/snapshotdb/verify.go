@@ -0,0 +1,185 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/rlp"
+)
+
+// ErrBaseDiverged is returned when the repair is asked for a divergence at or
+// below the base num, the data already written to baseDB can't be rebuilt
+// from the journals and the node has to resync.
+var ErrBaseDiverged = errors.New("snapshotDB: baseDB diverged from the chain")
+
+// PPOSHashReader returns the ppos hash committed in the state of the header,
+// ok is false if the state is not available.
+type PPOSHashReader func(header *types.Header) (hash common.Hash, ok bool)
+
+// VerifyResult is the outcome of walking the journals of the blocks not yet
+// written to baseDB.
+type VerifyResult struct {
+	Base         uint64      // The block number written to baseDB
+	Highest      uint64      // The highest committed block number
+	Checked      uint64      // The number of journals checked
+	Unverified   uint64      // The number of journals whose ppos hash isn't in the state
+	LastGood     uint64      // The last block number consistent with the chain
+	LastGoodHash common.Hash // The hash of the last consistent block
+	Diverged     uint64      // The first block number diverging from the chain, zero if none
+	Reason       string      // Why the block diverged
+}
+
+// Consistent reports whether no divergence was found.
+func (r *VerifyResult) Consistent() bool {
+	return r.Diverged == 0
+}
+
+// Verify walks the journals of the snapshotDB at path from the base num up to
+// the highest block, the journals must be linked by the parent hash, belong
+// to the canonical chain and carry the kv hash committed in the block state.
+// The kv hash is also recomputed from the data of the journals written in
+// order, the journals written before are only checked against the state.
+// The progress is called with each block number checked, it may be nil.
+func Verify(path string, chain Chain, pposHash PPOSHashReader, progress func(number uint64)) (*VerifyResult, error) {
+	baseDB, err := openBaseDB(path, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer baseDB.Close()
+	return verify(baseDB, chain, pposHash, progress)
+}
+
+func verify(baseDB *leveldb.DB, chain Chain, pposHash PPOSHashReader, progress func(number uint64)) (*VerifyResult, error) {
+	c := new(current)
+	if err := c.loadFromBaseDB(baseDB); err != nil {
+		return nil, err
+	}
+	if err := c.Valid(); err != nil {
+		return nil, err
+	}
+	result := &VerifyResult{
+		Base:     c.base.Num.Uint64(),
+		Highest:  c.highest.Num.Uint64(),
+		LastGood: c.base.Num.Uint64(),
+	}
+	diverge := func(number uint64, format string, args ...interface{}) (*VerifyResult, error) {
+		result.Diverged, result.Reason = number, fmt.Sprintf(format, args...)
+		return result, nil
+	}
+
+	header := chain.GetHeaderByNumber(result.Base)
+	if header == nil {
+		return diverge(result.Base, "base block not in chain")
+	}
+	result.LastGoodHash = header.Hash()
+
+	for number := result.Base + 1; number <= result.Highest; number++ {
+		if progress != nil {
+			progress(number)
+		}
+		val, err := baseDB.Get(EncodeWalKey(new(big.Int).SetUint64(number)), nil)
+		if err == leveldb.ErrNotFound {
+			return diverge(number, "journal missing")
+		} else if err != nil {
+			return nil, err
+		}
+		wal := new(blockWal)
+		if err := rlp.DecodeBytes(val, wal); err != nil {
+			return diverge(number, "journal broken: %v", err)
+		}
+		if wal.BlockNumber == nil || wal.BlockNumber.Uint64() != number {
+			return diverge(number, "journal of block %v", wal.BlockNumber)
+		}
+		if wal.ParentHash != result.LastGoodHash {
+			return diverge(number, "parent hash %v, want %v", wal.ParentHash.TerminalString(), result.LastGoodHash.TerminalString())
+		}
+		header := chain.GetHeaderByNumber(number)
+		if header == nil {
+			return diverge(number, "block not in chain")
+		}
+		if header.Hash() != wal.BlockHash {
+			return diverge(number, "block hash %v, chain has %v", wal.BlockHash.TerminalString(), header.Hash().TerminalString())
+		}
+		if wal.Ordered {
+			var kvHash common.Hash
+			for _, kv := range wal.Data {
+				kvHash = generateKVHash(kv.Key, kv.Value, kvHash)
+			}
+			if kvHash != wal.KvHash {
+				return diverge(number, "kv hash %v, journal data has %v", wal.KvHash.TerminalString(), kvHash.TerminalString())
+			}
+		}
+		// The kv hash is only stored in the state if the block wrote any data
+		if wal.KvHash != (common.Hash{}) {
+			if hash, ok := pposHash(header); !ok {
+				result.Unverified++
+			} else if hash != wal.KvHash {
+				return diverge(number, "kv hash %v, state has %v", wal.KvHash.TerminalString(), hash.TerminalString())
+			}
+		}
+		result.Checked++
+		result.LastGood, result.LastGoodHash = number, wal.BlockHash
+	}
+	return result, nil
+}
+
+// Repair drops the journals of the snapshotDB at path from the diverged block
+// onwards and moves the highest block back to the last good one, the blocks
+// of the chain above it are replayed the next time the node starts.
+func Repair(path string, result *VerifyResult) error {
+	baseDB, err := openBaseDB(path, 0, 0)
+	if err != nil {
+		return err
+	}
+	defer baseDB.Close()
+	return repair(baseDB, result)
+}
+
+func repair(baseDB *leveldb.DB, result *VerifyResult) error {
+	if result.Consistent() {
+		return nil
+	}
+	if result.Diverged <= result.Base {
+		return fmt.Errorf("%w at %d: %s", ErrBaseDiverged, result.Diverged, result.Reason)
+	}
+	batch := new(leveldb.Batch)
+	itr := baseDB.NewIterator(util.BytesPrefix([]byte(WalKeyPrefix)), nil)
+	for itr.Next() {
+		if DecodeWalKey(itr.Key()).Uint64() >= result.Diverged {
+			batch.Delete(common.CopyBytes(itr.Key()))
+		}
+	}
+	itr.Release()
+	if err := itr.Error(); err != nil {
+		return err
+	}
+	c := newCurrent(new(big.Int).SetUint64(result.LastGood), nil, result.LastGoodHash)
+	batch.Put([]byte(CurrentHighestBlock), c.EncodeHighest())
+	if err := baseDB.Write(batch, nil); err != nil {
+		return fmt.Errorf("repair baseDB fail:%v", err)
+	}
+	logger.Info("Repaired snapshotdb", "highest", result.LastGood, "hash", result.LastGoodHash, "removed", batch.Len()-1)
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshotdb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/rlp"
+)
+
+func TestVerifyAndRepair(t *testing.T) {
+	ch := newTestchain(dbpath)
+	defer ch.clear()
+
+	if err := ch.insert(true, generatekv(10), newBlockBaseDB); err != nil {
+		t.Fatal(err)
+	}
+	pposHashes := make(map[common.Hash]common.Hash)
+	for i := 0; i < 3; i++ {
+		if err := ch.insert(true, generatekv(10), func(db *snapshotDB, kvs kvs, head *types.Header) error {
+			if err := newBlockRecognizedDirect(db, kvs, head); err != nil {
+				return err
+			}
+			pposHashes[head.Hash()] = common.BytesToHash(db.GetLastKVHash(head.Hash()))
+			return db.Commit(head.Hash())
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ch.db.walSync.Wait()
+
+	stateOf := func(header *types.Header) (common.Hash, bool) {
+		hash, ok := pposHashes[header.Hash()]
+		return hash, ok
+	}
+	verifyDB := func(pposHash PPOSHashReader) *VerifyResult {
+		t.Helper()
+		result, err := verify(ch.db.baseDB, ch, pposHash, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	result := verifyDB(stateOf)
+	if !result.Consistent() || result.Base != 1 || result.Highest != 4 || result.Checked != 3 || result.Unverified != 0 {
+		t.Fatalf("consistent snapshotdb, have %+v", result)
+	}
+	if result.LastGood != 4 || result.LastGoodHash != ch.h[3].Hash() {
+		t.Errorf("last good block, have %d %v", result.LastGood, result.LastGoodHash)
+	}
+
+	result = verifyDB(func(header *types.Header) (common.Hash, bool) { return common.Hash{}, false })
+	if !result.Consistent() || result.Unverified != 3 {
+		t.Errorf("unavailable state, have %+v", result)
+	}
+
+	// A corrupted value is found from the journal alone
+	key := EncodeWalKey(ch.h[2].Number)
+	val, err := ch.db.baseDB.Get(key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wal := new(blockWal)
+	if err := rlp.DecodeBytes(val, wal); err != nil {
+		t.Fatal(err)
+	}
+	if !wal.Ordered {
+		t.Fatal("journal written without the order of the writes")
+	}
+	wal.Data[0].Value = append(common.CopyBytes(wal.Data[0].Value), 1)
+	corrupted, err := rlp.EncodeToBytes(wal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ch.db.baseDB.Put(key, corrupted, nil); err != nil {
+		t.Fatal(err)
+	}
+	result = verifyDB(func(header *types.Header) (common.Hash, bool) { return common.Hash{}, false })
+	if result.Consistent() || result.Diverged != 3 || result.LastGood != 2 {
+		t.Errorf("corrupted journal, have %+v", result)
+	}
+	if err := ch.db.baseDB.Put(key, val, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	pposHashes[ch.h[2].Hash()] = common.Hash{1}
+	result = verifyDB(stateOf)
+	if result.Consistent() || result.Diverged != 3 || result.LastGood != 2 || result.LastGoodHash != ch.h[1].Hash() {
+		t.Fatalf("diverged kv hash, have %+v", result)
+	}
+
+	if err := repair(ch.db.baseDB, &VerifyResult{Base: 1, Diverged: 1}); !errors.Is(err, ErrBaseDiverged) {
+		t.Errorf("repair of baseDB, have %v", err)
+	}
+	if err := repair(ch.db.baseDB, result); err != nil {
+		t.Fatal(err)
+	}
+	if err := ch.db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	ch.reOpenSnapshotDB()
+	if highest := ch.db.current.GetHighest(false); highest.Num.Uint64() != 2 || highest.Hash != ch.h[1].Hash() {
+		t.Errorf("repaired highest, have %v %v", highest.Num, highest.Hash)
+	}
+	if len(ch.db.committed) != 1 {
+		t.Errorf("repaired journals, have %d", len(ch.db.committed))
+	}
+	if result := verifyDB(stateOf); !result.Consistent() || result.Highest != 2 {
+		t.Errorf("repaired snapshotdb, have %+v", result)
+	}
+}
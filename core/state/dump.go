@@ -18,7 +18,9 @@ package state
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/hashkey-chain/hashkey-chain/common"
 	"github.com/hashkey-chain/hashkey-chain/common/hexutil"
@@ -232,3 +234,78 @@ func (s *StateDB) IteratorDump(opts *DumpConfig) IteratorDump {
 	iterator.Next = s.DumpToCollector(iterator, opts)
 	return *iterator
 }
+
+// ExportCollector is the callback interface of ExportToCollector.
+type ExportCollector interface {
+	// OnAccount is called once for each account in the trie, before its storage
+	OnAccount(addr common.Address, balance *big.Int, nonce uint64, code []byte) error
+	// OnStorage is called for each storage item of the account, the value is
+	// the one given to SetState
+	OnStorage(addr common.Address, key, value []byte) error
+}
+
+// errMissingPreimage is returned by ExportToCollector if the key of an account
+// or a storage item can't be recovered.
+var errMissingPreimage = errors.New("missing trie key preimage, the state must be recorded with preimages")
+
+// ExportToCollector iterates the accounts and the storage of the state as
+// they were written, so that they can be set into another state. Unlike the
+// dump, the storage keys are arbitrary bytes and the values are stripped of
+// their prefixes. It fails on a missing preimage of a trie key.
+func (s *StateDB) ExportToCollector(c ExportCollector) error {
+	var (
+		accounts uint64
+		start    = time.Now()
+		logged   = time.Now()
+	)
+	log.Info("Trie exporting started", "root", s.trie.Hash())
+
+	it := trie.NewIterator(s.trie.NodeIterator(nil))
+	for it.Next() {
+		var data Account
+		if err := rlp.DecodeBytes(it.Value, &data); err != nil {
+			return err
+		}
+		addrBytes := s.trie.GetKey(it.Key)
+		if addrBytes == nil {
+			return fmt.Errorf("account %x: %w", it.Key, errMissingPreimage)
+		}
+		addr := common.BytesToAddress(addrBytes)
+		obj := newObject(s, addr, data)
+		if err := c.OnAccount(addr, data.Balance, data.Nonce, obj.Code(s.db)); err != nil {
+			return err
+		}
+		storageIt := trie.NewIterator(obj.getTrie(s.db).NodeIterator(nil))
+		for storageIt.Next() {
+			key := s.trie.GetKey(storageIt.Key)
+			if key == nil {
+				return fmt.Errorf("storage %x of account %x: %w", storageIt.Key, addr, errMissingPreimage)
+			}
+			_, content, _, err := rlp.Split(storageIt.Value)
+			if err != nil {
+				return err
+			}
+			if err := c.OnStorage(addr, common.CopyBytes(key), common.CopyBytes(obj.removePrefixValue(content))); err != nil {
+				return err
+			}
+		}
+		if storageIt.Err != nil {
+			return storageIt.Err
+		}
+		if err := obj.dbErr; err != nil {
+			return err
+		}
+		accounts++
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Trie exporting in progress", "at", it.Key, "accounts", accounts,
+				"elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if it.Err != nil {
+		return it.Err
+	}
+	log.Info("Trie exporting complete", "accounts", accounts,
+		"elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
@@ -129,6 +129,8 @@ type (
 	touchChange struct {
 		account *common.Address
 	}
+	// Changes to the balance changes of the PPOS plugins
+	addBalanceChange struct{}
 	// Changes to the access list
 	accessListAddAccountChange struct {
 		address *common.Address
@@ -231,6 +233,14 @@ func (ch addLogChange) dirtied() *common.Address {
 	return nil
 }
 
+func (ch addBalanceChange) revert(s *StateDB) {
+	s.balanceChanges = s.balanceChanges[:len(s.balanceChanges)-1]
+}
+
+func (ch addBalanceChange) dirtied() *common.Address {
+	return nil
+}
+
 func (ch addPreimageChange) revert(s *StateDB) {
 	delete(s.preimages, ch.hash)
 }
@@ -80,6 +80,9 @@ type StateDB struct {
 	logs         map[common.Hash][]*types.Log
 	logSize      uint
 
+	// The balance changes made by the PPOS plugins outside of the EVM
+	balanceChanges []*types.BalanceChange
+
 	preimages map[common.Hash][]byte
 
 	// Per-transaction access list
@@ -210,6 +213,7 @@ func (s *StateDB) Reset(root common.Hash) error {
 	s.txIndex = 0
 	s.logs = make(map[common.Hash][]*types.Log)
 	s.logSize = 0
+	s.balanceChanges = nil
 	s.preimages = make(map[common.Hash][]byte)
 	s.clearJournalAndRefund()
 	s.accessList = newAccessList()
@@ -239,6 +243,20 @@ func (s *StateDB) Logs() []*types.Log {
 	return logs
 }
 
+// AddBalanceChange records a balance change made by the PPOS plugins, it is
+// bound to the current transaction if any.
+func (s *StateDB) AddBalanceChange(change *types.BalanceChange) {
+	s.journal.append(addBalanceChange{})
+
+	change.TxHash = s.thash
+	s.balanceChanges = append(s.balanceChanges, change)
+}
+
+// BalanceChanges returns the balance changes recorded in the state.
+func (s *StateDB) BalanceChanges() []*types.BalanceChange {
+	return s.balanceChanges
+}
+
 // AddPreimage records a SHA3 preimage seen by the VM.
 func (s *StateDB) AddPreimage(hash common.Hash, preimage []byte) {
 	if _, ok := s.preimages[hash]; !ok {
@@ -883,6 +901,13 @@ func (s *StateDB) Copy() *StateDB {
 		}
 		state.logs[hash] = cpy
 	}
+	if len(s.balanceChanges) > 0 {
+		state.balanceChanges = make([]*types.BalanceChange, len(s.balanceChanges))
+		for i, c := range s.balanceChanges {
+			cpy := *c
+			state.balanceChanges[i] = &cpy
+		}
+	}
 	for hash, preimage := range s.preimages {
 		state.preimages[hash] = preimage
 	}
@@ -1018,6 +1043,13 @@ func (s *StateDB) Prepare(thash, bhash common.Hash, ti int) {
 	s.accessList = newAccessList()
 }
 
+// SetTxContext sets the current transaction hash and index, the block
+// phase of the PPOS plugins clears them.
+func (s *StateDB) SetTxContext(thash common.Hash, ti int) {
+	s.thash = thash
+	s.txIndex = ti
+}
+
 func (s *StateDB) clearJournalAndRefund() {
 	if len(s.journal.entries) > 0 {
 		s.journal = newJournal()
@@ -1196,3 +1196,18 @@ func TestStateDBAccessList(t *testing.T) {
 		t.Fatalf("expected empty, got %d", got)
 	}
 }
+
+func TestBalanceChangesRevert(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()))
+
+	state.SetTxContext(common.Hash{1}, 0)
+	state.AddBalanceChange(&types.BalanceChange{To: toAddr([]byte{1}), Amount: big.NewInt(1)})
+	id := state.Snapshot()
+	state.AddBalanceChange(&types.BalanceChange{To: toAddr([]byte{2}), Amount: big.NewInt(2)})
+	state.RevertToSnapshot(id)
+
+	changes := state.Copy().BalanceChanges()
+	if len(changes) != 1 || changes[0].Amount.Cmp(big.NewInt(1)) != 0 || changes[0].TxHash != (common.Hash{1}) {
+		t.Fatalf("balance changes mismatch: %v", changes)
+	}
+}
@@ -116,6 +116,12 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 }
 
 func applyTransaction(msg types.Message, config *params.ChainConfig, bc ChainContext, gp *GasPool, statedb *state.StateDB, header *types.Header, tx *types.Transaction, usedGas *uint64, evm *vm.EVM) (*types.Receipt, error) {
+	if types.IsScheduledTx(tx, config) {
+		return nil, ErrScheduledTx
+	}
+	if tx.Sponsored() && !gov.Gte150VersionState(statedb) {
+		return nil, ErrSponsoredTxNotActive
+	}
 	// Create a new context to be used in the EVM environment
 	txContext := NewEVMTxContext(msg)
 	// Add addresses to access list if applicable
@@ -143,6 +149,10 @@ func applyTransaction(msg types.Message, config *params.ChainConfig, bc ChainCon
 	if msg.To() == nil {
 		receipt.ContractAddress = crypto.CreateAddress(evm.TxContext.Origin, tx.Nonce())
 	}
+	if tx.Sponsored() {
+		payer := msg.Payer()
+		receipt.Payer = &payer
+	}
 	// Set the receipt logs
 	if result.Failed() {
 		if bizError, ok := result.Err.(*common.BizError); ok {
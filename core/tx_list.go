@@ -482,9 +482,16 @@ func (l *txPricedList) Removed(count int) {
 	heap.Init(l.items)
 }
 
+// txExemption reports the transactions which are exempt from the price rules,
+// namely the local ones and the reserved-lane ones.
+type txExemption interface {
+	containsTx(tx *types.Transaction) bool
+	empty() bool
+}
+
 // Cap finds all the transactions below the given price threshold, drops them
 // from the priced list and returns them for further removal from the entire pool.
-func (l *txPricedList) Cap(threshold *big.Int, local *accountSet) types.Transactions {
+func (l *txPricedList) Cap(threshold *big.Int, local txExemption) types.Transactions {
 	drop := make(types.Transactions, 0, 128) // Remote underpriced transactions to drop
 	save := make(types.Transactions, 0, 64)  // Local underpriced transactions to keep
 
@@ -515,7 +522,7 @@ func (l *txPricedList) Cap(threshold *big.Int, local *accountSet) types.Transact
 
 // Underpriced checks whether a transaction is cheaper than (or as cheap as) the
 // lowest priced transaction currently being tracked.
-func (l *txPricedList) Underpriced(tx *types.Transaction, local *accountSet) bool {
+func (l *txPricedList) Underpriced(tx *types.Transaction, local txExemption) bool {
 	// Local transactions cannot be underpriced
 	if local.containsTx(tx) {
 		return false
@@ -541,7 +548,7 @@ func (l *txPricedList) Underpriced(tx *types.Transaction, local *accountSet) boo
 
 // Discard finds a number of most underpriced transactions, removes them from the
 // priced list and returns them for further removal from the entire pool.
-func (l *txPricedList) Discard(slots int, local *accountSet) (types.Transactions, bool) {
+func (l *txPricedList) Discard(slots int, local txExemption) (types.Transactions, bool) {
 	// If we have some local accountset, those will not be discarded
 	if !local.empty() {
 		// In case the list is filled to the brim with 'local' txs, we do this
@@ -17,6 +17,7 @@
 package core
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"github.com/hashkey-chain/hashkey-chain/x/gov"
@@ -35,6 +36,7 @@ import (
 	"github.com/hashkey-chain/hashkey-chain/log"
 	"github.com/hashkey-chain/hashkey-chain/metrics"
 	"github.com/hashkey-chain/hashkey-chain/params"
+	"github.com/hashkey-chain/hashkey-chain/x/xutil"
 )
 
 const (
@@ -85,6 +87,14 @@ var (
 	// is higher than the balance of the user's account.
 	ErrInsufficientFunds = errors.New("insufficient funds for gas * price + value")
 
+	// ErrInvalidPayer is returned if the sponsored transaction isn't signed
+	// properly by its fee payer.
+	ErrInvalidPayer = errors.New("invalid fee payer")
+
+	// ErrInsufficientPayerFunds is returned if the gas of a sponsored transaction
+	// is higher than the balance of the fee payer's account.
+	ErrInsufficientPayerFunds = errors.New("insufficient payer funds for gas * price")
+
 	// ErrIntrinsicGas is returned if the transaction is specified to use less gas
 	// than required to start the invocation.
 	ErrIntrinsicGas = errors.New("intrinsic gas too low")
@@ -141,6 +151,10 @@ var (
 	slotsGauge   = metrics.NewRegisteredGauge("txpool/slots", nil)
 
 	reheapTimer = metrics.NewRegisteredTimer("txpool/reheap", nil)
+
+	// Metrics for the reserved lane of PPOS system-contract transactions
+	systemGauge          = metrics.NewRegisteredGauge("txpool/system", nil)
+	systemRateLimitMeter = metrics.NewRegisteredMeter("txpool/system/ratelimit", nil) // Fallen back to the ordinary rules
 )
 
 // TxStatus is the current status of a transaction as seen by the pool.
@@ -206,6 +220,9 @@ type TxPoolConfig struct {
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
 
 	TxCacheSize uint64 //After receiving the specified number of transactions from the remote, move the transactions in the queen to pending
+
+	SystemSlots     uint64 // Number of transaction slots reserved for PPOS system-contract calls of active candidates
+	SystemTypeSlots uint64 // Maximum number of reserved slots per account and system-contract function
 }
 
 // DefaultTxPoolConfig contains the default configurations for the transaction
@@ -224,6 +241,9 @@ var DefaultTxPoolConfig = TxPoolConfig{
 
 	Lifetime:    3 * time.Hour,
 	TxCacheSize: 0,
+
+	SystemSlots:     1024,
+	SystemTypeSlots: 4,
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -258,6 +278,14 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultTxPoolConfig.Lifetime)
 		conf.Lifetime = DefaultTxPoolConfig.Lifetime
 	}
+	if conf.SystemSlots < 1 {
+		log.Warn("Sanitizing invalid txpool system slots", "provided", conf.SystemSlots, "updated", DefaultTxPoolConfig.SystemSlots)
+		conf.SystemSlots = DefaultTxPoolConfig.SystemSlots
+	}
+	if conf.SystemTypeSlots < 1 {
+		log.Warn("Sanitizing invalid txpool system type slots", "provided", conf.SystemTypeSlots, "updated", DefaultTxPoolConfig.SystemTypeSlots)
+		conf.SystemTypeSlots = DefaultTxPoolConfig.SystemTypeSlots
+	}
 	return conf
 }
 
@@ -292,6 +320,9 @@ type TxPool struct {
 	beats   map[common.Address]time.Time // Last heartbeat from each known account
 	all     *txLookup                    // All transactions to allow lookups
 	priced  *txPricedList                // All transactions sorted by price
+	system  *txSystemLane                // Reserved lane of PPOS system-contract transactions
+
+	systemEpoch uint64 // Epoch the senders of the reserved lane were loaded for
 
 	wg sync.WaitGroup // for shutdown sync
 
@@ -339,6 +370,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain txPoo
 		queue:       make(map[common.Address]*txList),
 		beats:       make(map[common.Address]time.Time),
 		all:         newTxLookup(),
+		system:      newTxSystemLane(config.SystemSlots, config.SystemTypeSlots),
 
 		gasPrice:  new(big.Int),
 		resetHead: chain.CurrentBlock(),
@@ -506,6 +538,7 @@ func (pool *TxPool) ForkedReset(newHeader *types.Header, rollback []*types.Block
 	pool.currentState = statedb
 	pool.pendingNonces = newTxNoncer(statedb)
 	pool.currentMaxGas = newHeader.GasLimit
+	pool.resetSystemSenders(newHeader)
 
 	// reset signer
 	if gov.Gte120VersionState(statedb) {
@@ -533,6 +566,7 @@ func (pool *TxPool) ForkedReset(newHeader *types.Header, rollback []*types.Block
 	// Ensure pool.queue and pool.pending sizes stay within the configured limits.
 	pool.truncatePending()
 	pool.truncateQueue()
+	pool.system.prune(pool.all)
 
 	// Update all accounts to the latest known pending nonce
 	for addr, list := range pool.pending {
@@ -655,7 +689,8 @@ func (pool *TxPool) PendingLimited() (map[common.Address]types.Transactions, err
 	defer pool.mu.Unlock()
 
 	txCount := 0
-	pending := make(map[common.Address]types.Transactions)
+	// Reserved-lane transactions are always served
+	pending := pool.systemPending()
 	for addr, list := range pool.pending {
 		pending[addr] = list.Flatten()
 		txCount += len(pending[addr])
@@ -667,6 +702,39 @@ func (pool *TxPool) PendingLimited() (map[common.Address]types.Transactions, err
 	return pending, nil
 }
 
+// SystemPending retrieves the executable transactions of the reserved lane of
+// PPOS system-contract calls, grouped by origin account and sorted by nonce.
+// Only the leading lane transactions of every account are returned, the ones
+// following an ordinary transaction are left to the ordinary rules.
+func (pool *TxPool) SystemPending() map[common.Address]types.Transactions {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return pool.systemPending()
+}
+
+// systemPending is the lock-free version of SystemPending.
+func (pool *TxPool) systemPending() map[common.Address]types.Transactions {
+	pending := make(map[common.Address]types.Transactions)
+	for _, addr := range pool.system.accounts() {
+		if list := pool.pending[addr]; list != nil {
+			if txs := pool.system.leading(list.Flatten()); len(txs) > 0 {
+				pending[addr] = txs
+			}
+		}
+	}
+	return pending
+}
+
+// exempted returns the transactions exempt from being discarded to make room in
+// a full pool, namely the local and the reserved-lane ones.
+func (pool *TxPool) exempted() txExemption {
+	if pool.system.len() == 0 {
+		return pool.locals
+	}
+	return &txPriceExemption{locals: pool.locals, lane: pool.system}
+}
+
 // Locals retrieves the accounts currently considered local by the pool.
 func (pool *TxPool) Locals() []common.Address {
 	pool.mu.Lock()
@@ -723,10 +791,29 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 		return ErrNonceTooLow
 	}
 	// Transactor should have enough funds to cover the costs
-	// cost == V + GP * GL
+	// cost == V + GP * GL, or V if the transaction is sponsored
 	if pool.currentState.GetBalance(from).Cmp(tx.Cost()) < 0 {
 		return ErrInsufficientFunds
 	}
+	// The fee payer of a sponsored transaction should have signed it
+	// and have enough funds to cover the gas of all its transactions
+	if tx.Sponsored() {
+		if !gov.Gte150VersionState(pool.currentState) {
+			return ErrSponsoredTxNotActive
+		}
+		payer, err := types.Payer(pool.signer, tx)
+		if err != nil {
+			return ErrInvalidPayer
+		}
+		fees := pool.all.PayerFees(payer)
+		// The replaced transaction is not paid
+		if old := pool.poolTx(from, tx.Nonce()); old != nil && old.Payer() != nil && *old.Payer() == payer {
+			fees.Sub(fees, old.Fee())
+		}
+		if pool.currentState.GetBalance(payer).Cmp(fees.Add(fees, tx.Fee())) < 0 {
+			return ErrInsufficientPayerFunds
+		}
+	}
 	intrGas, err := IntrinsicGas(tx.Data(), tx.To() == nil, pool.currentState)
 	if err != nil {
 		return err
@@ -746,6 +833,20 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	return nil
 }
 
+// poolTx returns the pending or queued transaction of the account with the
+// nonce, nil if there is none.
+func (pool *TxPool) poolTx(addr common.Address, nonce uint64) *types.Transaction {
+	if list := pool.pending[addr]; list != nil {
+		if tx := list.txs.Get(nonce); tx != nil {
+			return tx
+		}
+	}
+	if list := pool.queue[addr]; list != nil {
+		return list.txs.Get(nonce)
+	}
+	return nil
+}
+
 // add validates a transaction and inserts it into the non-executable queue for later
 // pending promotion and execution. If the transaction is a replacement for an already
 // pending or queued one, it overwrites the previous transaction if its price is higher.
@@ -771,10 +872,18 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		invalidTxMeter.Mark(1)
 		return false, err
 	}
+	from, _ := types.Sender(pool.signer, tx) // already validated
+	// Reserved-lane transactions have their own capacity and never make room in the ordinary one
+	key, reserved := pool.system.eligible(tx, from)
+	if !reserved && key.fcode != 0 {
+		systemRateLimitMeter.Mark(1)
+	}
+
 	// If the transaction pool is full, discard underpriced transactions
-	if uint64(pool.all.Count()) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
+	if !reserved && uint64(pool.all.Count()-pool.system.len()) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
+		exempted := pool.exempted()
 		// If the new transaction is underpriced, don't accept it
-		if !local && pool.priced.Underpriced(tx, pool.locals) {
+		if !local && pool.priced.Underpriced(tx, exempted) {
 			if log.GetWasmLogLevel() == log.LvlTrace {
 				log.Trace("Discarding underpriced transaction", "hash", hash, "price", tx.GasPrice())
 			}
@@ -782,7 +891,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 			return false, ErrUnderpriced
 		}
 		// New transaction is better than our worse ones, make room for it
-		drop, success := pool.priced.Discard(pool.all.Slots()-int(pool.config.GlobalSlots+pool.config.GlobalQueue)+numSlots(tx), pool.locals)
+		drop, success := pool.priced.Discard(pool.all.Slots()-pool.system.slotsUsed()-int(pool.config.GlobalSlots+pool.config.GlobalQueue)+numSlots(tx), exempted)
 		// Special case, we still can't make the room for the new remote one.
 		if !local && !success {
 			log.Trace("Discarding overflown transaction", "hash", hash)
@@ -800,7 +909,6 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		}
 	}
 	// Try to replace an existing transaction in the pending pool
-	from, _ := types.Sender(pool.signer, tx) // already validated
 	if list := pool.pending[from]; list != nil && list.Overlaps(tx) {
 		// Nonce already pending, check if required price bump is met
 		inserted, old := list.Add(tx, pool.config.PriceBump)
@@ -811,11 +919,15 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		// New transaction is better, replace old one
 		if old != nil {
 			pool.all.Remove(old.Hash())
+			pool.system.remove(old.Hash())
 			pool.priced.Removed(1)
 			pendingReplaceMeter.Mark(1)
 		}
 		pool.all.Add(tx)
 		pool.priced.Put(tx)
+		if reserved {
+			pool.system.add(tx, key)
+		}
 		pool.journalTx(from, tx)
 		pool.queueTxEvent(tx)
 		if log.GetWasmLogLevel() == log.LvlTrace {
@@ -830,6 +942,9 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 	if err != nil {
 		return false, err
 	}
+	if reserved {
+		pool.system.add(tx, key)
+	}
 	// Mark local addresses and journal local transactions
 	if local {
 		if !pool.locals.contains(from) {
@@ -866,6 +981,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction) (bool, er
 	// Discard any previous transaction and mark this
 	if old != nil {
 		pool.all.Remove(old.Hash())
+		pool.system.remove(old.Hash())
 		pool.priced.Removed(1)
 		queuedReplaceMeter.Mark(1)
 	} else {
@@ -1017,6 +1133,12 @@ func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
 			knownTxMeter.Mark(1)
 			continue
 		}
+		// The transactions signed to be scheduled are only executed by the chain
+		if types.IsScheduledTx(tx, pool.chainconfig) {
+			errs[i] = ErrScheduledTx
+			invalidTxMeter.Mark(1)
+			continue
+		}
 		// Exclude transactions with invalid signatures as soon as
 		// possible and cache senders in transactions before
 		// obtaining lock
@@ -1119,6 +1241,7 @@ func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) {
 
 	// Remove it from the list of known transactions
 	pool.all.Remove(hash)
+	pool.system.remove(hash)
 	if outofbound {
 		pool.priced.Removed(1)
 	}
@@ -1304,6 +1427,7 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 	// Ensure pool.queue and pool.pending sizes stay within the configured limits.
 	pool.truncatePending()
 	pool.truncateQueue()
+	pool.system.prune(pool.all)
 
 	if reset != nil {
 		for addr, list := range pool.pending {
@@ -1420,6 +1544,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.currentState = statedb
 	pool.pendingNonces = newTxNoncer(statedb)
 	pool.currentMaxGas = newHead.GasLimit
+	pool.resetSystemSenders(newHead)
 	// reset signer
 	if gov.Gte120VersionState(statedb) {
 		pool.signer = types.MakeSigner(pool.chainconfig, true)
@@ -1434,6 +1559,28 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 
 }
 
+// resetSystemSenders refreshes the accounts allowed to use the reserved lane from
+// the candidates at the given head. The candidates are loaded once per epoch, the
+// ones staking in the middle of an epoch can use the lane from the next one. The
+// previous accounts are kept on failure. The lane is only open on a PPOS chain,
+// the epochs are not defined without its economic model.
+func (pool *TxPool) resetSystemSenders(head *types.Header) {
+	if !bcr.IsPPOS() {
+		return
+	}
+	epoch := xutil.CalculateEpoch(head.Number.Uint64())
+	if epoch == pool.systemEpoch {
+		return
+	}
+	senders, err := bcr.SystemTxSenders(head)
+	if err != nil {
+		log.Warn("Failed to reset txpool system senders", "number", head.Number.Uint64(), "hash", head.Hash(), "err", err)
+		return
+	}
+	pool.system.setSenders(senders)
+	pool.systemEpoch = epoch
+}
+
 // promoteExecutables moves transactions that have become processable from the
 // future queue to the set of pending transactions. During this process, all
 // invalidated transactions (low nonce, low balance) are deleted.
@@ -1528,6 +1675,8 @@ func (pool *TxPool) truncatePending() {
 	for _, list := range pool.pending {
 		pending += uint64(list.Len())
 	}
+	// Reserved-lane transactions don't take up the ordinary slots
+	pending -= uint64(pool.system.held(pool.pending))
 	if pending <= pool.config.GlobalSlots {
 		return
 	}
@@ -1536,8 +1685,8 @@ func (pool *TxPool) truncatePending() {
 	// Assemble a spam order to penalize large transactors first
 	spammers := prque.New(nil)
 	for addr, list := range pool.pending {
-		// Only evict transactions from high rollers
-		txLength := list.Len()
+		// Only evict transactions from high rollers, the reserved-lane ones don't count
+		txLength := list.Len() - pool.system.heldBy(addr, list)
 		if !pool.locals.contains(addr) && uint64(txLength) > pool.config.AccountSlots {
 			spammers.Push(addr, int64(txLength))
 		}
@@ -1564,6 +1713,7 @@ func (pool *TxPool) truncatePending() {
 						// Drop the transaction from the global pools too
 						hash := tx.Hash()
 						pool.all.Remove(hash)
+						pool.system.remove(hash)
 						pool.knowns.Delete(hash)
 
 						// Update the account nonce to the dropped transaction
@@ -1594,6 +1744,7 @@ func (pool *TxPool) truncatePending() {
 					// Drop the transaction from the global pools too
 					hash := tx.Hash()
 					pool.all.Remove(hash)
+					pool.system.remove(hash)
 					pool.knowns.Delete(hash)
 
 					// Update the account nonce to the dropped transaction
@@ -1620,6 +1771,8 @@ func (pool *TxPool) truncateQueue() {
 	for _, list := range pool.queue {
 		queued += uint64(list.Len())
 	}
+	// Reserved-lane transactions don't take up the ordinary slots
+	queued -= uint64(pool.system.held(pool.queue))
 	if queued <= pool.config.GlobalQueue {
 		return
 	}
@@ -1640,9 +1793,12 @@ func (pool *TxPool) truncateQueue() {
 
 		addresses = addresses[:len(addresses)-1]
 
-		// Drop all transactions if they are less than the overflow
-		if size := uint64(list.Len()); size <= drop {
+		// Drop all transactions if they are less than the overflow, the reserved-lane ones don't count
+		if size := uint64(list.Len() - pool.system.heldBy(addr.address, list)); size <= drop {
 			for _, tx := range list.Flatten() {
+				if pool.system.contains(tx.Hash()) {
+					continue
+				}
 				pool.removeTx(tx.Hash(), true)
 				pool.knowns.Delete(tx.Hash())
 			}
@@ -1653,6 +1809,9 @@ func (pool *TxPool) truncateQueue() {
 		// Otherwise drop only last few transactions
 		txs := list.Flatten()
 		for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
+			if pool.system.contains(txs[i].Hash()) {
+				continue
+			}
 			pool.removeTx(txs[i].Hash(), true)
 			pool.knowns.Delete(txs[i].Hash())
 			drop--
@@ -1718,6 +1877,44 @@ func (pool *TxPool) demoteUnexecutables() {
 			delete(pool.pending, addr)
 		}
 	}
+	pool.dropUnpayableSponsored()
+}
+
+// dropUnpayableSponsored removes the sponsored transactions whose fee payers
+// can't pay for them any more, the ones of the lowest nonces are kept first.
+// The later transactions of their senders are postponed.
+func (pool *TxPool) dropUnpayableSponsored() {
+	for _, payer := range pool.all.Payers() {
+		balance := pool.currentState.GetBalance(payer)
+		if balance.Cmp(pool.all.PayerFees(payer)) >= 0 {
+			continue
+		}
+		var sponsored types.Transactions
+		pool.all.Range(func(hash common.Hash, tx *types.Transaction) bool {
+			if p := tx.Payer(); p != nil && *p == payer {
+				sponsored = append(sponsored, tx)
+			}
+			return true
+		})
+		sort.Slice(sponsored, func(i, j int) bool {
+			if sponsored[i].Nonce() != sponsored[j].Nonce() {
+				return sponsored[i].Nonce() < sponsored[j].Nonce()
+			}
+			return bytes.Compare(sponsored[i].Hash().Bytes(), sponsored[j].Hash().Bytes()) < 0
+		})
+		paid := new(big.Int)
+		for _, tx := range sponsored {
+			if fees := new(big.Int).Add(paid, tx.Fee()); fees.Cmp(balance) <= 0 {
+				paid = fees
+				continue
+			}
+			if log.GetWasmLogLevel() == log.LvlTrace {
+				log.Trace("Removed unpayable sponsored transaction", "hash", tx.Hash(), "payer", payer)
+			}
+			pool.removeTx(tx.Hash(), true)
+			pendingNofundsMeter.Mark(1)
+		}
+	}
 }
 
 // addressByHeartbeat is an account address tagged with its last activity timestamp.
@@ -1820,15 +2017,17 @@ func (as *accountSet) merge(other *accountSet) {
 // peeking into the pool in TxPool.Get without having to acquire the widely scoped
 // TxPool.mu mutex.
 type txLookup struct {
-	all   map[common.Hash]*types.Transaction
-	slots int
-	lock  sync.RWMutex
+	all    map[common.Hash]*types.Transaction
+	payers map[common.Address]*big.Int // The fees the payers of the sponsored transactions have to pay
+	slots  int
+	lock   sync.RWMutex
 }
 
 // newTxLookup returns a new txLookup structure.
 func newTxLookup() *txLookup {
 	return &txLookup{
-		all: make(map[common.Hash]*types.Transaction),
+		all:    make(map[common.Hash]*types.Transaction),
+		payers: make(map[common.Address]*big.Int),
 	}
 }
 
@@ -1876,6 +2075,14 @@ func (t *txLookup) Add(tx *types.Transaction) {
 	t.slots += numSlots(tx)
 	slotsGauge.Update(int64(t.slots))
 
+	if payer := tx.Payer(); payer != nil {
+		fees, ok := t.payers[*payer]
+		if !ok {
+			fees = new(big.Int)
+			t.payers[*payer] = fees
+		}
+		fees.Add(fees, tx.Fee())
+	}
 	t.all[tx.Hash()] = tx
 }
 
@@ -1884,12 +2091,42 @@ func (t *txLookup) Remove(hash common.Hash) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
-	t.slots -= numSlots(t.all[hash])
+	tx := t.all[hash]
+	t.slots -= numSlots(tx)
 	slotsGauge.Update(int64(t.slots))
 
+	if payer := tx.Payer(); payer != nil {
+		if fees := t.payers[*payer]; fees.Sub(fees, tx.Fee()).Sign() <= 0 {
+			delete(t.payers, *payer)
+		}
+	}
 	delete(t.all, hash)
 }
 
+// PayerFees returns the fees the payer has to pay for the sponsored
+// transactions in the lookup.
+func (t *txLookup) PayerFees(payer common.Address) *big.Int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if fees, ok := t.payers[payer]; ok {
+		return new(big.Int).Set(fees)
+	}
+	return new(big.Int)
+}
+
+// Payers returns the payers of the sponsored transactions in the lookup.
+func (t *txLookup) Payers() []common.Address {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	payers := make([]common.Address, 0, len(t.payers))
+	for payer := range t.payers {
+		payers = append(payers, payer)
+	}
+	return payers
+}
+
 // numSlots calculates the number of slots needed for a single transaction.
 func numSlots(tx *types.Transaction) int {
 	return int((tx.Size() + txSlotSize - 1) / txSlotSize)
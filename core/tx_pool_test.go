@@ -32,11 +32,15 @@ import (
 	"github.com/hashkey-chain/hashkey-chain/trie"
 
 	"github.com/hashkey-chain/hashkey-chain/common"
+	cvm "github.com/hashkey-chain/hashkey-chain/common/vm"
 	"github.com/hashkey-chain/hashkey-chain/core/state"
 	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/core/vm"
 	"github.com/hashkey-chain/hashkey-chain/crypto"
 	"github.com/hashkey-chain/hashkey-chain/event"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
 	"github.com/hashkey-chain/hashkey-chain/params"
+	"github.com/hashkey-chain/hashkey-chain/x/gov"
 )
 
 // testTxPoolConfig is a transaction pool configuration without stateful disk
@@ -118,6 +122,25 @@ func validateTxPoolInternals(pool *TxPool) error {
 			return fmt.Errorf("pending nonce mismatch: have %v, want %v", nonce, last+1)
 		}
 	}
+	// Ensure the fees of the payers are the ones of their sponsored transactions
+	fees := make(map[common.Address]*big.Int)
+	pool.all.Range(func(hash common.Hash, tx *types.Transaction) bool {
+		if payer := tx.Payer(); payer != nil {
+			if fees[*payer] == nil {
+				fees[*payer] = new(big.Int)
+			}
+			fees[*payer].Add(fees[*payer], tx.Fee())
+		}
+		return true
+	})
+	if payers := pool.all.Payers(); len(payers) != len(fees) {
+		return fmt.Errorf("payer count mismatch: have %d, want %d", len(payers), len(fees))
+	}
+	for payer, fee := range fees {
+		if have := pool.all.PayerFees(payer); have.Cmp(fee) != 0 {
+			return fmt.Errorf("payer %x fees mismatch: have %v, want %v", payer, have, fee)
+		}
+	}
 	return nil
 }
 
@@ -358,6 +381,138 @@ func TestTransactionNegativeValue(t *testing.T) {
 	}
 }
 
+func TestSponsoredTransactions(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()))
+	blockchain := &testBlockChain{statedb, 10000000, new(event.Feed)}
+	pool := NewTxPool(testTxPoolConfig, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	senderKey, _ := crypto.GenerateKey()
+	payerKey, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(senderKey.PublicKey)
+	payer := crypto.PubkeyToAddress(payerKey.PublicKey)
+	signer := types.NewPIP7Signer(pool.chainconfig.ChainID, pool.chainconfig.PIP7ChainID)
+
+	sponsored := func(nonce uint64, payerKey *ecdsa.PrivateKey) *types.Transaction {
+		tx := types.NewTransaction(nonce, common.Address{}, big.NewInt(100), 21000, big.NewInt(1), nil).WithPayer(payer)
+		tx, _ = types.SignTx(tx, signer, senderKey)
+		if payerKey != nil {
+			tx, _ = types.SignPayer(tx, signer, payerKey)
+		}
+		return tx
+	}
+	pool.currentState.AddBalance(sender, big.NewInt(100))
+
+	// Rejected until the version activating sponsored transactions. The pool
+	// remembers the hashes it has seen, so every attempt uses its own nonce.
+	if err := pool.AddRemote(sponsored(0, payerKey)); err != ErrSponsoredTxNotActive {
+		t.Error("expected", ErrSponsoredTxNotActive, "got", err)
+	}
+	gov.AddActiveVersion(params.FORKVERSION_1_5_0, 0, pool.currentState)
+	pool.signer = types.MakeSigner(pool.chainconfig, true)
+
+	if err := pool.AddRemote(sponsored(1, nil)); err != ErrInvalidPayer {
+		t.Error("expected", ErrInvalidPayer, "got", err)
+	}
+	if err := pool.AddRemote(sponsored(2, senderKey)); err != ErrInvalidPayer {
+		t.Error("expected", ErrInvalidPayer, "got", err)
+	}
+	if err := pool.AddRemote(sponsored(3, payerKey)); err != ErrInsufficientPayerFunds {
+		t.Error("expected", ErrInsufficientPayerFunds, "got", err)
+	}
+	// The sender only needs to fund the value, the payer the gas
+	pool.currentState.AddBalance(payer, big.NewInt(21000))
+	if err := pool.AddRemote(sponsored(4, payerKey)); err != nil {
+		t.Error("expected the sponsored transaction to be accepted, got", err)
+	}
+}
+
+// Tests that the fee payer has to pay for all its sponsored transactions in
+// the pool, and the ones it can't pay for any more are dropped on reset.
+func TestSponsoredTransactionsPayerFunds(t *testing.T) {
+	t.Parallel()
+
+	pool, _ := setupTxPool()
+	defer pool.Stop()
+
+	gov.AddActiveVersion(params.FORKVERSION_1_5_0, 0, pool.currentState)
+	pool.signer = types.MakeSigner(pool.chainconfig, true)
+	signer := types.NewPIP7Signer(pool.chainconfig.ChainID, pool.chainconfig.PIP7ChainID)
+
+	payerKey, _ := crypto.GenerateKey()
+	payer := crypto.PubkeyToAddress(payerKey.PublicKey)
+	senderKeys := make([]*ecdsa.PrivateKey, 3)
+	for i := range senderKeys {
+		senderKeys[i], _ = crypto.GenerateKey()
+		pool.currentState.AddBalance(crypto.PubkeyToAddress(senderKeys[i].PublicKey), big.NewInt(100))
+	}
+	sponsored := func(nonce uint64, gasPrice int64, key *ecdsa.PrivateKey) *types.Transaction {
+		tx := types.NewTransaction(nonce, common.Address{}, big.NewInt(100), 21000, big.NewInt(gasPrice), nil).WithPayer(payer)
+		tx, _ = types.SignTx(tx, signer, key)
+		tx, _ = types.SignPayer(tx, signer, payerKey)
+		return tx
+	}
+	// The payer pays for two transactions
+	pool.currentState.AddBalance(payer, big.NewInt(2*21000))
+	<-pool.requestReset(nil, nil)
+
+	if err := pool.addRemoteSync(sponsored(0, 1, senderKeys[0])); err != nil {
+		t.Fatal("expected the first sponsored transaction to be accepted, got", err)
+	}
+	if err := pool.addRemoteSync(sponsored(1, 1, senderKeys[0])); err != nil {
+		t.Fatal("expected the second sponsored transaction to be accepted, got", err)
+	}
+	if err := pool.addRemoteSync(sponsored(0, 1, senderKeys[1])); err != ErrInsufficientPayerFunds {
+		t.Error("expected", ErrInsufficientPayerFunds, "got", err)
+	}
+	if fees := pool.all.PayerFees(payer); fees.Cmp(big.NewInt(2*21000)) != 0 {
+		t.Errorf("payer fees mismatched: have %v, want %v", fees, 2*21000)
+	}
+	pending, queued := pool.Stats()
+	if pending != 2 || queued != 0 {
+		t.Fatalf("transaction count mismatched: have %d/%d, want 2/0", pending, queued)
+	}
+	// The payer can only pay for the first transaction after it spent some funds
+	pool.currentState.SubBalance(payer, big.NewInt(21000))
+	<-pool.requestReset(nil, nil)
+
+	if pending, queued = pool.Stats(); pending != 1 || queued != 0 {
+		t.Fatalf("transaction count mismatched: have %d/%d, want 1/0", pending, queued)
+	}
+	if fees := pool.all.PayerFees(payer); fees.Cmp(big.NewInt(21000)) != 0 {
+		t.Errorf("payer fees mismatched: have %v, want %v", fees, 21000)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+	// The dropped transactions are no longer paid for
+	if err := pool.addRemoteSync(sponsored(0, 1, senderKeys[2])); err != ErrInsufficientPayerFunds {
+		t.Error("expected", ErrInsufficientPayerFunds, "got", err)
+	}
+}
+
+// Tests that the transactions signed to be scheduled are rejected by the pool,
+// so a scheduled payload cannot be replayed as an ordinary transaction.
+func TestScheduledTransactionsRejected(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000))
+
+	signer := types.MakeScheduledSigner(pool.chainconfig)
+	tx, _ := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(100), 100000, big.NewInt(1), nil), signer, key)
+	if err := pool.AddRemote(tx); err != ErrScheduledTx {
+		t.Error("expected", ErrScheduledTx, "got", err)
+	}
+	msg, _ := tx.AsMessage(signer)
+	if _, err := applyTransaction(msg, pool.chainconfig, nil, new(GasPool).AddGas(100000), pool.currentState, &types.Header{Number: big.NewInt(1)}, tx, new(uint64), nil); err != ErrScheduledTx {
+		t.Error("expected", ErrScheduledTx, "got", err)
+	}
+}
+
 func TestTransactionDoubleNonce(t *testing.T) {
 	t.Parallel()
 
@@ -1416,6 +1571,126 @@ func TestTransactionPoolUnderpricing(t *testing.T) {
 	}
 }
 
+func declareTransaction(nonce uint64, gasprice *big.Int, key *ecdsa.PrivateKey, chainId *big.Int) *types.Transaction {
+	var input [][]byte
+	input = append(input, common.MustRlpEncode(uint16(vm.Declare)))
+	input = append(input, common.MustRlpEncode(discover.PubkeyID(&key.PublicKey)))
+	input = append(input, common.MustRlpEncode(uint32(1)))
+	input = append(input, common.MustRlpEncode(common.VersionSign{}))
+	tx, _ := types.SignTx(types.NewTransaction(nonce, cvm.GovContractAddr, big.NewInt(0), 100000, gasprice, common.MustRlpEncode(input)), types.NewEIP155Signer(chainId), key)
+	return tx
+}
+
+// Tests that the PPOS system-contract transactions of the candidates use the
+// reserved lane: they bypass the price rules of a full pool, cannot be pushed
+// out by expensive ones and are rate limited per function type, but are still
+// subject to the price floor.
+func TestTransactionPoolSystemLane(t *testing.T) {
+	t.Parallel()
+
+	config := testTxPoolConfig
+	config.GlobalSlots = 2
+	config.GlobalQueue = 2
+	config.SystemTypeSlots = 2
+
+	pool := newTestTxPool(config, params.TestChainConfig)
+	defer pool.Stop()
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+		pool.currentState.AddBalance(crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(100000000))
+	}
+	candidate := crypto.PubkeyToAddress(keys[2].PublicKey)
+
+	pool.mu.Lock()
+	pool.system.setSenders(map[common.Address]struct{}{candidate: {}})
+	pool.mu.Unlock()
+	pool.SetGasPrice(big.NewInt(1))
+
+	// Fill up the ordinary slots with well priced transactions
+	for i := uint64(0); i < 2; i++ {
+		if err := pool.addRemoteSync(pricedTransaction(i, 100000, big.NewInt(10), keys[0], pool.chainconfig.ChainID)); err != nil {
+			t.Fatalf("failed to add transaction %d: %v", i, err)
+		}
+		if err := pool.addRemoteSync(pricedTransaction(i, 100000, big.NewInt(10), keys[1], pool.chainconfig.ChainID)); err != nil {
+			t.Fatalf("failed to add transaction %d: %v", i, err)
+		}
+	}
+	// A cheap declaration from a non candidate is rejected, from a candidate it is reserved
+	if err := pool.addRemoteSync(declareTransaction(2, big.NewInt(1), keys[0], pool.chainconfig.ChainID)); err != ErrUnderpriced {
+		t.Fatalf("adding non reserved transaction error mismatch: have %v, want %v", err, ErrUnderpriced)
+	}
+	if err := pool.addRemoteSync(declareTransaction(0, big.NewInt(0), keys[2], pool.chainconfig.ChainID)); err != ErrUnderpriced {
+		t.Fatalf("adding reserved transaction below the price floor error mismatch: have %v, want %v", err, ErrUnderpriced)
+	}
+	for i := uint64(0); i < 2; i++ {
+		if err := pool.addRemoteSync(declareTransaction(i, big.NewInt(1), keys[2], pool.chainconfig.ChainID)); err != nil {
+			t.Fatalf("failed to add reserved transaction %d: %v", i, err)
+		}
+	}
+	if have := pool.system.len(); have != 2 {
+		t.Fatalf("reserved transactions mismatched: have %d, want %d", have, 2)
+	}
+	// The per type limit is exhausted, the next declaration falls back to the ordinary rules
+	if err := pool.addRemoteSync(declareTransaction(2, big.NewInt(1), keys[2], pool.chainconfig.ChainID)); err != ErrUnderpriced {
+		t.Fatalf("adding rate limited transaction error mismatch: have %v, want %v", err, ErrUnderpriced)
+	}
+	// Expensive transactions must not push out the reserved ones
+	if err := pool.addRemoteSync(pricedTransaction(2, 100000, big.NewInt(20), keys[1], pool.chainconfig.ChainID)); err != nil {
+		t.Fatalf("failed to add well priced transaction: %v", err)
+	}
+	if pending := pool.pending[candidate]; pending == nil || pending.Len() != 2 {
+		t.Fatalf("reserved transactions evicted from the pending pool")
+	}
+	if pending := pool.SystemPending(); len(pending) != 1 || len(pending[candidate]) != 2 {
+		t.Fatalf("system pending mismatched: have %v, want %d transactions of %x", pending, 2, candidate)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the reserved lane doesn't shield the ordinary transactions of its
+// senders from the eviction rules, only the lane transactions themselves.
+func TestTransactionPoolSystemLaneTruncation(t *testing.T) {
+	t.Parallel()
+
+	config := testTxPoolConfig
+	config.GlobalSlots = 2
+	config.AccountSlots = 1
+
+	pool := newTestTxPool(config, params.TestChainConfig)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	candidate := crypto.PubkeyToAddress(key.PublicKey)
+	pool.currentState.AddBalance(candidate, big.NewInt(100000000))
+
+	pool.mu.Lock()
+	pool.system.setSenders(map[common.Address]struct{}{candidate: {}})
+	pool.mu.Unlock()
+
+	if err := pool.addRemoteSync(declareTransaction(0, big.NewInt(1), key, pool.chainconfig.ChainID)); err != nil {
+		t.Fatalf("failed to add reserved transaction: %v", err)
+	}
+	for i := uint64(1); i < 5; i++ {
+		if err := pool.addRemoteSync(pricedTransaction(i, 100000, big.NewInt(1), key, pool.chainconfig.ChainID)); err != nil {
+			t.Fatalf("failed to add transaction %d: %v", i, err)
+		}
+	}
+	pending := pool.pending[candidate]
+	if pending == nil || pending.Len() != 3 {
+		t.Fatalf("pending transactions of the lane sender mismatched: have %v, want %d", pending, 3)
+	}
+	if !pool.system.contains(pending.txs.Get(0).Hash()) {
+		t.Fatalf("reserved transaction evicted from the pending pool")
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Tests that more expensive transactions push out cheap ones from the pool, but
 // without producing instability by creating gaps that start jumping transactions
 // back and forth between queued/pending.
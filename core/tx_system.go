@@ -0,0 +1,264 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	cvm "github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/core/vm"
+	"github.com/hashkey-chain/hashkey-chain/rlp"
+)
+
+// systemTxFuncs lists the PPOS system-contract functions which may use the
+// reserved lane of the pool, grouped by contract address.
+var systemTxFuncs = map[common.Address]map[uint16]struct{}{
+	cvm.StakingContractAddr: {
+		vm.TxEditorCandidate:    {},
+		vm.TxIncreaseStaking:    {},
+		vm.TxWithdrewCandidate:  {},
+		vm.TxRotateCandidateKey: {},
+	},
+	cvm.GovContractAddr: {
+		vm.SubmitText:      {},
+		vm.SubmitVersion:   {},
+		vm.SubmitParam:     {},
+		vm.Vote:            {},
+		vm.Declare:         {},
+		vm.SubmitCancel:    {},
+		vm.SubmitAllowlist: {},
+	},
+	cvm.SlashingContractAddr: {
+		vm.TxReportDuplicateSign: {},
+	},
+}
+
+// systemTxFuncCode returns the function code of a PPOS system-contract
+// transaction which may use the reserved lane.
+func systemTxFuncCode(tx *types.Transaction) (uint16, bool) {
+	if tx.To() == nil {
+		return 0, false
+	}
+	funcs, ok := systemTxFuncs[*tx.To()]
+	if !ok || len(tx.Data()) == 0 {
+		return 0, false
+	}
+	var args [][]byte
+	if err := rlp.Decode(bytes.NewReader(tx.Data()), &args); nil != err || len(args) == 0 {
+		return 0, false
+	}
+	var fcode uint16
+	if err := rlp.DecodeBytes(args[0], &fcode); nil != err {
+		return 0, false
+	}
+	if _, ok := funcs[fcode]; !ok {
+		return 0, false
+	}
+	return fcode, true
+}
+
+// systemTxKey identifies the rate limited bucket of a reserved-lane transaction.
+type systemTxKey struct {
+	from  common.Address
+	fcode uint16
+}
+
+// systemTx is a transaction admitted into the reserved lane.
+type systemTx struct {
+	tx  *types.Transaction
+	key systemTxKey
+}
+
+// txSystemLane is the reserved lane of the pool for the PPOS system-contract
+// transactions sent by the staking and benefit addresses of the active candidates.
+// The lane has its own capacity, the lane transactions themselves don't take up the
+// ordinary slots of their senders and the miner executes them first. The price floor
+// of the pool applies to them as to any other transaction.
+type txSystemLane struct {
+	slots     int // Maximum number of transactions held by the lane
+	typeSlots int // Maximum number of transactions per account and function type
+
+	senders map[common.Address]struct{} // Accounts allowed to use the lane
+	txs     map[common.Hash]*systemTx   // Transactions admitted into the lane
+	counts  map[systemTxKey]int         // Admitted transactions per account and function type
+	holders map[common.Address]int      // Admitted transactions per account
+}
+
+// newTxSystemLane creates a new reserved lane with the given capacity.
+func newTxSystemLane(slots, typeSlots uint64) *txSystemLane {
+	return &txSystemLane{
+		slots:     int(slots),
+		typeSlots: int(typeSlots),
+		senders:   make(map[common.Address]struct{}),
+		txs:       make(map[common.Hash]*systemTx),
+		counts:    make(map[systemTxKey]int),
+		holders:   make(map[common.Address]int),
+	}
+}
+
+// setSenders replaces the accounts allowed to use the lane. Transactions
+// already admitted stay in the lane until they leave the pool.
+func (l *txSystemLane) setSenders(senders map[common.Address]struct{}) {
+	if senders == nil {
+		senders = make(map[common.Address]struct{})
+	}
+	l.senders = senders
+}
+
+// eligible checks whether the transaction can be admitted into the lane, which
+// requires an allowed sender, a lane function, and free per type and global slots.
+// The returned key is set for every lane function called by an allowed sender, so
+// it is also set if the transaction was rejected by the slot limits.
+func (l *txSystemLane) eligible(tx *types.Transaction, from common.Address) (systemTxKey, bool) {
+	if _, ok := l.senders[from]; !ok {
+		return systemTxKey{}, false
+	}
+	fcode, ok := systemTxFuncCode(tx)
+	if !ok {
+		return systemTxKey{}, false
+	}
+	key := systemTxKey{from: from, fcode: fcode}
+	if len(l.txs) >= l.slots || l.counts[key] >= l.typeSlots {
+		return key, false
+	}
+	return key, true
+}
+
+// add admits the transaction into the lane.
+func (l *txSystemLane) add(tx *types.Transaction, key systemTxKey) {
+	if _, ok := l.txs[tx.Hash()]; ok {
+		return
+	}
+	l.txs[tx.Hash()] = &systemTx{tx: tx, key: key}
+	l.counts[key]++
+	l.holders[key.from]++
+	systemGauge.Update(int64(len(l.txs)))
+}
+
+// remove drops the transaction from the lane if it was admitted.
+func (l *txSystemLane) remove(hash common.Hash) {
+	stx, ok := l.txs[hash]
+	if !ok {
+		return
+	}
+	delete(l.txs, hash)
+	if l.counts[stx.key]--; l.counts[stx.key] <= 0 {
+		delete(l.counts, stx.key)
+	}
+	if l.holders[stx.key.from]--; l.holders[stx.key.from] <= 0 {
+		delete(l.holders, stx.key.from)
+	}
+	systemGauge.Update(int64(len(l.txs)))
+}
+
+// prune drops every lane transaction which is no longer tracked by the pool.
+func (l *txSystemLane) prune(all *txLookup) {
+	for hash := range l.txs {
+		if all.Get(hash) == nil {
+			l.remove(hash)
+		}
+	}
+}
+
+// contains checks whether the transaction was admitted into the lane.
+func (l *txSystemLane) contains(hash common.Hash) bool {
+	_, ok := l.txs[hash]
+	return ok
+}
+
+// len returns the number of transactions held by the lane.
+func (l *txSystemLane) len() int {
+	return len(l.txs)
+}
+
+// slotsUsed returns the number of data slots taken up by the lane transactions.
+func (l *txSystemLane) slotsUsed() int {
+	slots := 0
+	for _, stx := range l.txs {
+		slots += numSlots(stx.tx)
+	}
+	return slots
+}
+
+// held returns the number of lane transactions held by the given lists.
+func (l *txSystemLane) held(lists map[common.Address]*txList) int {
+	count := 0
+	for hash, stx := range l.txs {
+		if list := lists[stx.key.from]; list != nil {
+			if tx := list.txs.Get(stx.tx.Nonce()); tx != nil && tx.Hash() == hash {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// heldBy returns the number of lane transactions of the account held by the given list.
+func (l *txSystemLane) heldBy(addr common.Address, list *txList) int {
+	if l.holders[addr] == 0 {
+		return 0
+	}
+	count := 0
+	for hash, stx := range l.txs {
+		if stx.key.from != addr {
+			continue
+		}
+		if tx := list.txs.Get(stx.tx.Nonce()); tx != nil && tx.Hash() == hash {
+			count++
+		}
+	}
+	return count
+}
+
+// leading returns the lane transactions at the head of the given nonce sorted
+// transactions, up to the first transaction which was not admitted into the lane.
+func (l *txSystemLane) leading(txs types.Transactions) types.Transactions {
+	for i, tx := range txs {
+		if !l.contains(tx.Hash()) {
+			return txs[:i]
+		}
+	}
+	return txs
+}
+
+// accounts returns the accounts currently holding lane transactions.
+func (l *txSystemLane) accounts() []common.Address {
+	accounts := make([]common.Address, 0, len(l.holders))
+	for addr := range l.holders {
+		accounts = append(accounts, addr)
+	}
+	return accounts
+}
+
+// txPriceExemption exempts the local and the reserved-lane transactions from
+// being discarded to make room for better priced ones in a full pool.
+type txPriceExemption struct {
+	locals *accountSet
+	lane   *txSystemLane
+}
+
+// containsTx checks if the transaction is local or admitted into the lane.
+func (e *txPriceExemption) containsTx(tx *types.Transaction) bool {
+	return e.lane.contains(tx.Hash()) || e.locals.containsTx(tx)
+}
+
+// empty checks if there are no exempt transactions.
+func (e *txPriceExemption) empty() bool {
+	return e.lane.len() == 0 && e.locals.empty()
+}
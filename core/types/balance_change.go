@@ -0,0 +1,103 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/common/hexutil"
+)
+
+// BalanceChangeReason is the reason of a balance change made by the PPOS plugins.
+type BalanceChangeReason uint8
+
+const (
+	BalanceChangeIssuance               BalanceChangeReason = iota + 1 // The additional issuance credited to the foundations and the reward pool
+	BalanceChangeGenesisAllowance                                      // The genesis allowance moved to the reward pool
+	BalanceChangeBlockReward                                           // The block reward paid to the block producer
+	BalanceChangeStakingReward                                         // The staking reward paid to the benefit address of the validator
+	BalanceChangeDelegateReward                                        // The delegation reward moved to the delegate reward pool
+	BalanceChangeDelegateRewardWithdraw                                // The delegation reward withdrawn by the delegator
+	BalanceChangeStakingLock                                           // The free amount locked by staking
+	BalanceChangeStakingRefund                                         // The staking amount returned to the staking address
+	BalanceChangeDelegationLock                                        // The free amount locked by delegation
+	BalanceChangeDelegationRefund                                      // The delegation amount returned to the delegator
+	BalanceChangeRestrictingLock                                       // The amount locked by a restricting plan
+	BalanceChangeRestrictingRelease                                    // The restricting amount released to the account
+	BalanceChangeRestrictingPledge                                     // The restricting amount pledged to staking or delegation
+	BalanceChangeRestrictingReturn                                     // The pledged restricting amount returned from staking
+	BalanceChangeSlashing                                              // The staking amount slashed
+	BalanceChangeScheduledTxEscrow                                     // The gas of a scheduled transaction escrowed from the submitter
+	BalanceChangeScheduledTxRefund                                     // The unused escrow of a scheduled transaction refunded to the submitter
+)
+
+var balanceChangeReasonNames = map[BalanceChangeReason]string{
+	BalanceChangeIssuance:               "issuance",
+	BalanceChangeGenesisAllowance:       "genesisAllowance",
+	BalanceChangeBlockReward:            "blockReward",
+	BalanceChangeStakingReward:          "stakingReward",
+	BalanceChangeDelegateReward:         "delegateReward",
+	BalanceChangeDelegateRewardWithdraw: "delegateRewardWithdraw",
+	BalanceChangeStakingLock:            "stakingLock",
+	BalanceChangeStakingRefund:          "stakingRefund",
+	BalanceChangeDelegationLock:         "delegationLock",
+	BalanceChangeDelegationRefund:       "delegationRefund",
+	BalanceChangeRestrictingLock:        "restrictingLock",
+	BalanceChangeRestrictingRelease:     "restrictingRelease",
+	BalanceChangeRestrictingPledge:      "restrictingPledge",
+	BalanceChangeRestrictingReturn:      "restrictingReturn",
+	BalanceChangeSlashing:               "slashing",
+	BalanceChangeScheduledTxEscrow:      "scheduledTxEscrow",
+	BalanceChangeScheduledTxRefund:      "scheduledTxRefund",
+}
+
+func (r BalanceChangeReason) String() string {
+	if name, ok := balanceChangeReasonNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(r))
+}
+
+// BalanceChange records a balance movement made by the PPOS plugins outside
+// of the EVM, e.g. the rewards paid in BeginBlock/EndBlock or the refunds of
+// the unstaked amounts. Amount is moved from From to To, a zero From means
+// newly issued amount. TxHash is the transaction making the change, it is
+// zero for the changes made in BeginBlock/EndBlock.
+type BalanceChange struct {
+	From   common.Address
+	To     common.Address
+	Amount *big.Int
+	Module string
+	Reason BalanceChangeReason
+	TxHash common.Hash
+}
+
+// MarshalJSON marshals the balance change with the reason name.
+func (c *BalanceChange) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		From       common.Address `json:"from"`
+		To         common.Address `json:"to"`
+		Amount     *hexutil.Big   `json:"amount"`
+		Module     string         `json:"module"`
+		Reason     string         `json:"reason"`
+		ReasonCode hexutil.Uint   `json:"reasonCode"`
+		TxHash     common.Hash    `json:"transactionHash"`
+	}{c.From, c.To, (*hexutil.Big)(c.Amount), c.Module, c.Reason.String(), hexutil.Uint(c.Reason), c.TxHash})
+}
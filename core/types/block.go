@@ -244,6 +244,12 @@ func (h *Header) CachePublicKey() *ecdsa.PublicKey {
 	return pk
 }
 
+// SetPublicKey sets the public key of the producer in place of the one recovered
+// from the signature, the simulated blocks carry no signature of their producer.
+func (h *Header) SetPublicKey(pk *ecdsa.PublicKey) {
+	h.publicKey.Store(pk)
+}
+
 // SealHash returns the keccak256 seal hash of b's header.
 // The seal hash is computed on the first call and cached thereafter.
 func (h *Header) SealHash() (hash common.Hash) {
@@ -25,6 +25,10 @@ func (t txdata) MarshalJSON() ([]byte, error) {
 		V            *hexutil.Big    `json:"v" gencodec:"required"`
 		R            *hexutil.Big    `json:"r" gencodec:"required"`
 		S            *hexutil.Big    `json:"s" gencodec:"required"`
+		Payer        *common.Address `json:"payer,omitempty"  rlp:"optional"`
+		PayerV       *hexutil.Big    `json:"payerV,omitempty" rlp:"optional"`
+		PayerR       *hexutil.Big    `json:"payerR,omitempty" rlp:"optional"`
+		PayerS       *hexutil.Big    `json:"payerS,omitempty" rlp:"optional"`
 		Hash         *common.Hash    `json:"hash" rlp:"-"`
 	}
 	var enc txdata
@@ -37,6 +41,10 @@ func (t txdata) MarshalJSON() ([]byte, error) {
 	enc.V = (*hexutil.Big)(t.V)
 	enc.R = (*hexutil.Big)(t.R)
 	enc.S = (*hexutil.Big)(t.S)
+	enc.Payer = t.Payer
+	enc.PayerV = (*hexutil.Big)(t.PayerV)
+	enc.PayerR = (*hexutil.Big)(t.PayerR)
+	enc.PayerS = (*hexutil.Big)(t.PayerS)
 	enc.Hash = t.Hash
 	return json.Marshal(&enc)
 }
@@ -53,6 +61,10 @@ func (t *txdata) UnmarshalJSON(input []byte) error {
 		V            *hexutil.Big    `json:"v" gencodec:"required"`
 		R            *hexutil.Big    `json:"r" gencodec:"required"`
 		S            *hexutil.Big    `json:"s" gencodec:"required"`
+		Payer        *common.Address `json:"payer,omitempty"  rlp:"optional"`
+		PayerV       *hexutil.Big    `json:"payerV,omitempty" rlp:"optional"`
+		PayerR       *hexutil.Big    `json:"payerR,omitempty" rlp:"optional"`
+		PayerS       *hexutil.Big    `json:"payerS,omitempty" rlp:"optional"`
 		Hash         *common.Hash    `json:"hash" rlp:"-"`
 	}
 	var dec txdata
@@ -94,6 +106,18 @@ func (t *txdata) UnmarshalJSON(input []byte) error {
 		return errors.New("missing required field 's' for txdata")
 	}
 	t.S = (*big.Int)(dec.S)
+	if dec.Payer != nil {
+		t.Payer = dec.Payer
+	}
+	if dec.PayerV != nil {
+		t.PayerV = (*big.Int)(dec.PayerV)
+	}
+	if dec.PayerR != nil {
+		t.PayerR = (*big.Int)(dec.PayerR)
+	}
+	if dec.PayerS != nil {
+		t.PayerS = (*big.Int)(dec.PayerS)
+	}
 	if dec.Hash != nil {
 		t.Hash = dec.Hash
 	}
@@ -19,6 +19,7 @@ package types
 import (
 	"container/heap"
 	"errors"
+	"fmt"
 	"io"
 	"math/big"
 	"sync/atomic"
@@ -37,6 +38,10 @@ import (
 
 var (
 	ErrInvalidSig = errors.New("invalid transaction v, r, s values")
+
+	// ErrInvalidPayerSig is returned if the fee payer's signature of the sponsored
+	// transaction is missing, malformed, or signed by an account other than the payer.
+	ErrInvalidPayerSig = errors.New("invalid fee payer v, r, s values")
 )
 
 type Transaction struct {
@@ -44,9 +49,10 @@ type Transaction struct {
 	time time.Time // Time first seen locally (spam avoidance)
 
 	// caches
-	hash atomic.Value
-	size atomic.Value
-	from atomic.Value
+	hash  atomic.Value
+	size  atomic.Value
+	from  atomic.Value
+	payer atomic.Value
 
 	//for parallel executor only
 	intrinsicGas uint64
@@ -65,6 +71,14 @@ type txdata struct {
 	R *big.Int `json:"r" gencodec:"required"`
 	S *big.Int `json:"s" gencodec:"required"`
 
+	// The fee payer of the sponsored transaction and its signature values. The
+	// sender signs the payer in, the payer signs the transaction signed by the
+	// sender. They're omitted from the encoding of an ordinary transaction.
+	Payer  *common.Address `json:"payer,omitempty"  rlp:"optional"`
+	PayerV *big.Int        `json:"payerV,omitempty" rlp:"optional"`
+	PayerR *big.Int        `json:"payerR,omitempty" rlp:"optional"`
+	PayerS *big.Int        `json:"payerS,omitempty" rlp:"optional"`
+
 	// This is only used when marshaling to JSON.
 	Hash *common.Hash `json:"hash" rlp:"-"`
 }
@@ -81,6 +95,10 @@ func (t txdata) MarshalJSON2() ([]byte, error) {
 		V            *hexutil.Big    `json:"v" gencodec:"required"`
 		R            *hexutil.Big    `json:"r" gencodec:"required"`
 		S            *hexutil.Big    `json:"s" gencodec:"required"`
+		Payer        *common.Address `json:"payer,omitempty"  rlp:"optional"`
+		PayerV       *hexutil.Big    `json:"payerV,omitempty" rlp:"optional"`
+		PayerR       *hexutil.Big    `json:"payerR,omitempty" rlp:"optional"`
+		PayerS       *hexutil.Big    `json:"payerS,omitempty" rlp:"optional"`
 		Hash         *common.Hash    `json:"hash" rlp:"-"`
 	}
 	var enc txdata
@@ -93,6 +111,10 @@ func (t txdata) MarshalJSON2() ([]byte, error) {
 	enc.V = (*hexutil.Big)(t.V)
 	enc.R = (*hexutil.Big)(t.R)
 	enc.S = (*hexutil.Big)(t.S)
+	enc.Payer = t.Payer
+	enc.PayerV = (*hexutil.Big)(t.PayerV)
+	enc.PayerR = (*hexutil.Big)(t.PayerR)
+	enc.PayerS = (*hexutil.Big)(t.PayerS)
 	enc.Hash = t.Hash
 	return json2.Marshal(&enc)
 }
@@ -106,6 +128,9 @@ type txdataMarshaling struct {
 	V            *hexutil.Big
 	R            *hexutil.Big
 	S            *hexutil.Big
+	PayerV       *hexutil.Big
+	PayerR       *hexutil.Big
+	PayerS       *hexutil.Big
 }
 
 func NewTransaction(nonce uint64, to common.Address, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte) *Transaction {
@@ -186,6 +211,12 @@ func (tx *Transaction) UnmarshalJSON(input []byte) error {
 	if !crypto.ValidateSignatureValues(V, dec.R, dec.S, false) {
 		return ErrInvalidSig
 	}
+	if dec.PayerV != nil {
+		payerV := byte(dec.PayerV.Uint64() - 35 - 2*chainID)
+		if dec.Payer == nil || !crypto.ValidateSignatureValues(payerV, dec.PayerR, dec.PayerS, false) {
+			return ErrInvalidPayerSig
+		}
+	}
 	*tx = Transaction{
 		data: dec,
 		time: time.Now(),
@@ -216,6 +247,33 @@ func (tx *Transaction) To() *common.Address {
 	return &to
 }
 
+// Sponsored reports whether the gas of the transaction is paid by a fee payer
+// instead of its sender.
+func (tx *Transaction) Sponsored() bool { return tx.data.Payer != nil }
+
+// Payer returns the fee payer of the sponsored transaction.
+// It returns nil if the transaction is paid by its sender.
+func (tx *Transaction) Payer() *common.Address {
+	if tx.data.Payer == nil {
+		return nil
+	}
+	payer := *tx.data.Payer
+	return &payer
+}
+
+// WithPayer returns a copy of the transaction whose gas is paid by the payer.
+// The copy is unsigned, as the sender signs the payer in.
+func (tx *Transaction) WithPayer(payer common.Address) *Transaction {
+	cpy := &Transaction{
+		data: tx.data,
+		time: tx.time,
+	}
+	cpy.data.Payer = &payer
+	cpy.data.V, cpy.data.R, cpy.data.S = new(big.Int), new(big.Int), new(big.Int)
+	cpy.data.PayerV, cpy.data.PayerR, cpy.data.PayerS = nil, nil, nil
+	return cpy
+}
+
 // Hash hashes the RLP encoding of tx.
 // It uniquely identifies the transaction.
 func (tx *Transaction) Hash() common.Hash {
@@ -257,6 +315,13 @@ func (tx *Transaction) AsMessage(s Signer) (Message, error) {
 
 	var err error
 	msg.from, err = Sender(s, tx)
+	if err != nil {
+		return msg, err
+	}
+	msg.payer = msg.from
+	if tx.Sponsored() {
+		msg.payer, err = Payer(s, tx)
+	}
 	return msg, err
 }
 
@@ -272,20 +337,60 @@ func (tx *Transaction) WithSignature(signer Signer, sig []byte) (*Transaction, e
 		time: tx.time,
 	}
 	cpy.data.R, cpy.data.S, cpy.data.V = r, s, v
+	// The fee payer signs the sender's signature, a new one voids it.
+	cpy.data.PayerV, cpy.data.PayerR, cpy.data.PayerS = nil, nil, nil
 	return cpy, nil
 }
 
-// Cost returns amount + gasprice * gaslimit.
+// WithPayerSignature returns a new sponsored transaction with the given fee
+// payer's signature. The payer signs with the chain id the sender signed with.
+func (tx *Transaction) WithPayerSignature(signer PayerSigner, sig []byte) (*Transaction, error) {
+	if !tx.Sponsored() {
+		return nil, ErrSponsoredTxNotSupported
+	}
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("wrong size for signature: got %d, want %d", len(sig), crypto.SignatureLength)
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	v := new(big.Int).SetBytes([]byte{sig[64] + 35})
+	v.Add(v, new(big.Int).Mul(tx.ChainId(), big.NewInt(2)))
+
+	cpy := &Transaction{
+		data: tx.data,
+		time: tx.time,
+	}
+	cpy.data.PayerR, cpy.data.PayerS, cpy.data.PayerV = r, s, v
+	return cpy, nil
+}
+
+// Cost returns the amount the sender is charged, amount + gasprice * gaslimit,
+// or only the amount if the transaction is sponsored.
 func (tx *Transaction) Cost() *big.Int {
-	total := new(big.Int).Mul(tx.data.Price, new(big.Int).SetUint64(tx.data.GasLimit))
+	if tx.Sponsored() {
+		return new(big.Int).Set(tx.data.Amount)
+	}
+	total := tx.Fee()
 	total.Add(total, tx.data.Amount)
 	return total
 }
 
+// Fee returns gasprice * gaslimit, charged to the fee payer if the transaction
+// is sponsored.
+func (tx *Transaction) Fee() *big.Int {
+	return new(big.Int).Mul(tx.data.Price, new(big.Int).SetUint64(tx.data.GasLimit))
+}
+
 func (tx *Transaction) RawSignatureValues() (*big.Int, *big.Int, *big.Int) {
 	return tx.data.V, tx.data.R, tx.data.S
 }
 
+// RawPayerSignatureValues returns the fee payer's signature values of the
+// sponsored transaction, nil if it isn't signed by the payer yet.
+func (tx *Transaction) RawPayerSignatureValues() (*big.Int, *big.Int, *big.Int) {
+	return tx.data.PayerV, tx.data.PayerR, tx.data.PayerS
+}
+
 func (tx *Transaction) CacheFromAddr(signer Signer, addr common.Address) {
 	tx.from.Store(sigCache{signer: signer, from: addr})
 }
@@ -459,6 +564,7 @@ func (t *TransactionsByPriceAndNonce) Pop() {
 type Message struct {
 	to         *common.Address
 	from       common.Address
+	payer      common.Address
 	nonce      uint64
 	amount     *big.Int
 	gasLimit   uint64
@@ -470,6 +576,7 @@ type Message struct {
 func NewMessage(from common.Address, to *common.Address, nonce uint64, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, checkNonce bool) Message {
 	return Message{
 		from:       from,
+		payer:      from,
 		to:         to,
 		nonce:      nonce,
 		amount:     amount,
@@ -480,11 +587,19 @@ func NewMessage(from common.Address, to *common.Address, nonce uint64, amount *b
 	}
 }
 
-func (m Message) From() common.Address { return m.from }
-func (m Message) To() *common.Address  { return m.to }
-func (m Message) GasPrice() *big.Int   { return m.gasPrice }
-func (m Message) Value() *big.Int      { return m.amount }
-func (m Message) Gas() uint64          { return m.gasLimit }
-func (m Message) Nonce() uint64        { return m.nonce }
-func (m Message) Data() []byte         { return m.data }
-func (m Message) CheckNonce() bool     { return m.checkNonce }
+// NewSponsoredMessage returns the message whose gas is paid by the payer.
+func NewSponsoredMessage(from, payer common.Address, to *common.Address, nonce uint64, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, checkNonce bool) Message {
+	msg := NewMessage(from, to, nonce, amount, gasLimit, gasPrice, data, checkNonce)
+	msg.payer = payer
+	return msg
+}
+
+func (m Message) From() common.Address  { return m.from }
+func (m Message) Payer() common.Address { return m.payer }
+func (m Message) To() *common.Address   { return m.to }
+func (m Message) GasPrice() *big.Int    { return m.gasPrice }
+func (m Message) Value() *big.Int       { return m.amount }
+func (m Message) Gas() uint64           { return m.gasLimit }
+func (m Message) Nonce() uint64         { return m.nonce }
+func (m Message) Data() []byte          { return m.data }
+func (m Message) CheckNonce() bool      { return m.checkNonce }
@@ -29,6 +29,10 @@ import (
 
 var (
 	ErrInvalidChainId = errors.New("invalid chain id for signer")
+
+	// ErrSponsoredTxNotSupported is returned if the signer can't recover the fee
+	// payer of a sponsored transaction.
+	ErrSponsoredTxNotSupported = errors.New("sponsored transaction not supported by signer")
 )
 
 // sigCache is used to cache the derived sender and contains
@@ -38,6 +42,13 @@ type sigCache struct {
 	from   common.Address
 }
 
+// payerCache is used to cache the derived fee payer and contains
+// the signer used to derive it.
+type payerCache struct {
+	signer Signer
+	payer  common.Address
+}
+
 // MakeSigner returns a Signer based on the given chain config and block number.
 func MakeSigner(config *params.ChainConfig, pip7 bool) Signer {
 	var signer Signer
@@ -49,6 +60,33 @@ func MakeSigner(config *params.ChainConfig, pip7 bool) Signer {
 	return signer
 }
 
+// scheduledChainIdOffset separates the chain id the scheduled transactions are
+// signed with from the one of the chain.
+var scheduledChainIdOffset = new(big.Int).Lsh(big.NewInt(1), 32)
+
+// ScheduledChainId returns the chain id the transactions scheduled to be
+// executed by the chain are signed with, they can't be replayed as ordinary
+// transactions of the chain.
+func ScheduledChainId(chainId *big.Int) *big.Int {
+	if chainId == nil {
+		chainId = new(big.Int)
+	}
+	return new(big.Int).Add(chainId, scheduledChainIdOffset)
+}
+
+// MakeScheduledSigner returns the Signer of the transactions scheduled to be
+// executed by the chain.
+func MakeScheduledSigner(config *params.ChainConfig) Signer {
+	return NewEIP155Signer(ScheduledChainId(config.ChainID))
+}
+
+// IsScheduledTx reports whether the transaction is signed to be scheduled on
+// the chain, it can't be applied as an ordinary transaction.
+func IsScheduledTx(tx *Transaction, config *params.ChainConfig) bool {
+	chainId := tx.ChainId()
+	return chainId != nil && chainId.Cmp(ScheduledChainId(config.ChainID)) == 0
+}
+
 // SignTx signs the transaction using the given signer and private key
 func SignTx(tx *Transaction, s Signer, prv *ecdsa.PrivateKey) (*Transaction, error) {
 	h := s.Hash(tx, nil)
@@ -85,6 +123,44 @@ func Sender(signer Signer, tx *Transaction) (common.Address, error) {
 	return addr, nil
 }
 
+// SignPayer signs the sponsored transaction as its fee payer using the given
+// signer and private key. The transaction must be signed by its sender first.
+func SignPayer(tx *Transaction, s PayerSigner, prv *ecdsa.PrivateKey) (*Transaction, error) {
+	h := s.PayerHash(tx, tx.ChainId())
+	sig, err := crypto.Sign(h[:], prv)
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithPayerSignature(s, sig)
+}
+
+// Payer returns the address paying the gas of the transaction: the fee payer
+// derived from the payer's signature of a sponsored transaction, otherwise the
+// sender.
+//
+// Payer caches the address like Sender does.
+func Payer(signer Signer, tx *Transaction) (common.Address, error) {
+	if !tx.Sponsored() {
+		return Sender(signer, tx)
+	}
+	if pc := tx.payer.Load(); pc != nil {
+		payerCache := pc.(payerCache)
+		if payerCache.signer.Equal(signer) {
+			return payerCache.payer, nil
+		}
+	}
+	ps, ok := signer.(PayerSigner)
+	if !ok {
+		return common.Address{}, ErrSponsoredTxNotSupported
+	}
+	addr, err := ps.Payer(tx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	tx.payer.Store(payerCache{signer: signer, payer: addr})
+	return addr, nil
+}
+
 // Signer encapsulates transaction signature handling. Note that this interface is not a
 // stable API and may change at any time to accommodate new protocol rules.
 type Signer interface {
@@ -101,6 +177,34 @@ type Signer interface {
 	//	SignatureAndSender(tx *Transaction) (common.Address, []byte, error)
 }
 
+// PayerSigner is a Signer which also handles the fee payer's signature of
+// sponsored transactions.
+type PayerSigner interface {
+	Signer
+	// Payer returns the fee payer address of the sponsored transaction.
+	Payer(tx *Transaction) (common.Address, error)
+	// PayerHash returns the hash to be signed by the fee payer.
+	PayerHash(tx *Transaction, chainId *big.Int) common.Hash
+}
+
+// sigHashFields returns the transaction fields signed by the sender. The fee
+// payer is signed in only if the transaction is sponsored, leaving the hash of
+// ordinary transactions unchanged.
+func sigHashFields(tx *Transaction, chainId *big.Int) []interface{} {
+	fields := []interface{}{
+		tx.data.AccountNonce,
+		tx.data.Price,
+		tx.data.GasLimit,
+		tx.data.Recipient,
+		tx.data.Amount,
+		tx.data.Payload,
+	}
+	if tx.data.Payer != nil {
+		fields = append(fields, tx.data.Payer)
+	}
+	return append(fields, chainId, uint(0), uint(0))
+}
+
 // EIP155Transaction implements Signer using the EIP155 rules.
 type EIP155Signer struct {
 	chainId, chainIdMul *big.Int
@@ -155,15 +259,7 @@ func (s EIP155Signer) Hash(tx *Transaction, chainId *big.Int) common.Hash {
 	if chainId == nil {
 		cid = s.chainId
 	}
-	return rlpHash([]interface{}{
-		tx.data.AccountNonce,
-		tx.data.Price,
-		tx.data.GasLimit,
-		tx.data.Recipient,
-		tx.data.Amount,
-		tx.data.Payload,
-		cid, uint(0), uint(0),
-	})
+	return rlpHash(sigHashFields(tx, cid))
 }
 
 func (s EIP155Signer) SignatureAndSender(tx *Transaction) (common.Address, []byte, error) {
@@ -221,15 +317,7 @@ func (s PIP7Signer) Hash(tx *Transaction, chainId *big.Int) common.Hash {
 	if chainId == nil {
 		cid = s.chainId
 	}
-	return rlpHash([]interface{}{
-		tx.data.AccountNonce,
-		tx.data.Price,
-		tx.data.GasLimit,
-		tx.data.Recipient,
-		tx.data.Amount,
-		tx.data.Payload,
-		cid, uint(0), uint(0),
-	})
+	return rlpHash(sigHashFields(tx, cid))
 }
 
 func (s PIP7Signer) SignatureAndSender(tx *Transaction) (common.Address, []byte, error) {
@@ -244,6 +332,57 @@ func (s PIP7Signer) SignatureAndSender(tx *Transaction) (common.Address, []byte,
 	return recoverPubKeyAndSender(s.Hash(tx, txChainId), tx.data.R, tx.data.S, V, true)
 }
 
+// Payer returns the fee payer of the sponsored transaction, recovered from the
+// payer's signature. The recovered address must match the payer signed in by
+// the sender.
+func (s PIP7Signer) Payer(tx *Transaction) (common.Address, error) {
+	if tx.data.Payer == nil {
+		return common.Address{}, ErrSponsoredTxNotSupported
+	}
+	if tx.data.PayerV == nil || tx.data.PayerR == nil || tx.data.PayerS == nil {
+		return common.Address{}, ErrInvalidPayerSig
+	}
+	txChainId := tx.ChainId()
+	if txChainId.Cmp(s.chainId) != 0 && txChainId.Cmp(s.PIP7ChainId) != 0 {
+		return common.Address{}, ErrInvalidChainId
+	}
+	h := s.PayerHash(tx, txChainId)
+
+	V := new(big.Int).Sub(tx.data.PayerV, new(big.Int).Mul(txChainId, big.NewInt(2)))
+	V.Sub(V, big8)
+
+	payer, err := recoverPlain(h, tx.data.PayerR, tx.data.PayerS, V, true)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if payer != *tx.data.Payer {
+		return common.Address{}, ErrInvalidPayerSig
+	}
+	return payer, nil
+}
+
+// PayerHash returns the hash to be signed by the fee payer, which covers the
+// transaction along with the sender's signature.
+func (s PIP7Signer) PayerHash(tx *Transaction, chainId *big.Int) common.Hash {
+	cid := chainId
+	if chainId == nil {
+		cid = s.chainId
+	}
+	return rlpHash([]interface{}{
+		tx.data.AccountNonce,
+		tx.data.Price,
+		tx.data.GasLimit,
+		tx.data.Recipient,
+		tx.data.Amount,
+		tx.data.Payload,
+		tx.data.Payer,
+		tx.data.V,
+		tx.data.R,
+		tx.data.S,
+		cid, uint(0), uint(0),
+	})
+}
+
 // SignatureValues returns the raw R, S, V values corresponding to the
 // given signature.This signature
 // needs to be in the [R || S || V] format where V is 0 or 1.
@@ -22,6 +22,7 @@ import (
 
 	"github.com/hashkey-chain/hashkey-chain/common"
 	"github.com/hashkey-chain/hashkey-chain/crypto"
+	"github.com/hashkey-chain/hashkey-chain/params"
 	"github.com/hashkey-chain/hashkey-chain/rlp"
 )
 
@@ -93,6 +94,31 @@ func TestPIP7ChainId(t *testing.T) {
 	}
 
 }
+func TestScheduledChainId(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	config := &params.ChainConfig{ChainID: big.NewInt(100), PIP7ChainID: big.NewInt(210425)}
+
+	tx, err := SignTx(NewTransaction(0, addr, new(big.Int), 0, new(big.Int), nil), MakeScheduledSigner(config), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsScheduledTx(tx, config) {
+		t.Error("expected a scheduled transaction, chain id", tx.ChainId())
+	}
+	if _, err := Sender(MakeSigner(config, true), tx); err == nil {
+		t.Error("expected the signer of the chain to reject the scheduled transaction")
+	}
+
+	tx, err = SignTx(NewTransaction(0, addr, new(big.Int), 0, new(big.Int), nil), MakeSigner(config, true), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if IsScheduledTx(tx, config) {
+		t.Error("expected an ordinary transaction, chain id", tx.ChainId())
+	}
+}
+
 func TestEIP155SigningVitalik(t *testing.T) {
 	// Test vectors come from http://vitalik.ca/files/eip155_testvec.txt
 	for i, test := range []struct {
@@ -153,3 +179,112 @@ func TestChainId(t *testing.T) {
 		t.Error("expected no error")
 	}
 }
+
+func TestSponsoredSigning(t *testing.T) {
+	senderKey, _ := crypto.GenerateKey()
+	payerKey, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(senderKey.PublicKey)
+	payer := crypto.PubkeyToAddress(payerKey.PublicKey)
+
+	signer := NewPIP7Signer(big.NewInt(100), big.NewInt(210425))
+	tx := NewTransaction(0, sender, big.NewInt(10), 21000, big.NewInt(1), nil).WithPayer(payer)
+	if !tx.Sponsored() || *tx.Payer() != payer {
+		t.Fatalf("expected the transaction to be sponsored by %x", payer)
+	}
+	if tx.Cost().Cmp(big.NewInt(10)) != 0 {
+		t.Errorf("sender cost mismatch: have %v, want %v", tx.Cost(), 10)
+	}
+	if tx.Fee().Cmp(big.NewInt(21000)) != 0 {
+		t.Errorf("payer fee mismatch: have %v, want %v", tx.Fee(), 21000)
+	}
+
+	tx, err := SignTx(tx, signer, senderKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Payer(signer, tx); err != ErrInvalidPayerSig {
+		t.Errorf("expected %v before the payer signs, got %v", ErrInvalidPayerSig, err)
+	}
+	// Signed by an account other than the payer
+	forged, err := SignPayer(tx, signer, senderKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Payer(signer, forged); err != ErrInvalidPayerSig {
+		t.Errorf("expected %v for a forged payer signature, got %v", ErrInvalidPayerSig, err)
+	}
+
+	tx, err = SignPayer(tx, signer, payerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := Sender(signer, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != sender {
+		t.Errorf("sender mismatch: have %x, want %x", from, sender)
+	}
+	have, err := Payer(signer, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have != payer {
+		t.Errorf("payer mismatch: have %x, want %x", have, payer)
+	}
+	if _, err := Payer(NewEIP155Signer(big.NewInt(100)), tx); err != ErrSponsoredTxNotSupported {
+		t.Errorf("expected %v from the EIP155 signer, got %v", ErrSponsoredTxNotSupported, err)
+	}
+
+	// The sender signs the payer in, swapping it voids the sender's signature
+	swapped := &Transaction{data: tx.data}
+	swapped.data.Payer = &sender
+	if from, err := Sender(signer, swapped); err == nil && from == sender {
+		t.Error("expected the sender signature to cover the payer")
+	}
+
+	// Round trip through the RLP encoding
+	enc, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := new(Transaction)
+	if err := rlp.DecodeBytes(enc, dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec.Hash() != tx.Hash() {
+		t.Errorf("hash mismatch after decoding: have %x, want %x", dec.Hash(), tx.Hash())
+	}
+	if have, err := Payer(signer, dec); err != nil || have != payer {
+		t.Errorf("payer mismatch after decoding: have %x, want %x, err %v", have, payer, err)
+	}
+}
+
+func TestSponsoredLegacyEncoding(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	signer := NewPIP7Signer(big.NewInt(100), big.NewInt(210425))
+	tx, err := SignTx(NewTransaction(0, addr, new(big.Int), 21000, new(big.Int), nil), signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An ordinary transaction is encoded as the nine legacy fields
+	enc, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields []rlp.RawValue
+	if err := rlp.DecodeBytes(enc, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if len(fields) != 9 {
+		t.Errorf("legacy encoding mismatch: have %d fields, want 9", len(fields))
+	}
+	if tx.Sponsored() {
+		t.Error("expected an ordinary transaction")
+	}
+	if payer, err := Payer(signer, tx); err != nil || payer != addr {
+		t.Errorf("expected the sender to pay, have %x, err %v", payer, err)
+	}
+}
@@ -142,6 +142,7 @@ var PlatONPrecompiledContracts120 = map[common.Address]PrecompiledContract{
 	vm.RewardManagerPoolAddr:   &rewardEmpty{},
 	vm.DelegateRewardPoolAddr:  &DelegateRewardContract{},
 	vm.VrfInnerContractAddr:    &vrf{},
+	vm.ScheduledTxContractAddr: &ScheduledTxContract{},
 }
 
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
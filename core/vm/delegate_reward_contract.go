@@ -106,9 +106,9 @@ func (rc *DelegateRewardContract) withdrawDelegateReward() ([]byte, error) {
 			"blockHash", blockHash.TerminalString(), "txHash", txHash.Hex(), "from", from.String())
 		return txResultHandler(vm.DelegateRewardPoolAddr, rc.Evm, FuncNameWithdrawDelegateReward, reward.ErrDelegationNotFound.Msg, TxWithdrawDelegateReward, reward.ErrDelegationNotFound)
 	}
-	if len(list) > int(xcom.TheNumberOfDelegationsReward()) {
+	if len(list) > int(xcom.TheNumberOfDelegationsReward(blockNum.Uint64())) {
 		sort.Sort(staking.DelByDelegateEpoch(list))
-		list = list[:xcom.TheNumberOfDelegationsReward()]
+		list = list[:xcom.TheNumberOfDelegationsReward(blockNum.Uint64())]
 	}
 
 	if !rc.Contract.UseGas(params.WithdrawDelegateNodeGas * uint64(len(list))) {
@@ -422,7 +422,7 @@ func TestWithdrawDelegateRewardWithMultiNode(t *testing.T) {
 		if len(rewards) == 0 {
 			return errors.New("rewards must not be zero")
 		}
-		assert.True(t, len(rewards) == int(xcom.TheNumberOfDelegationsReward()))
+		assert.True(t, len(rewards) == int(xcom.TheNumberOfDelegationsReward(0)))
 		assert.True(t, rewards[0].NodeID == can2.NodeId)
 		assert.True(t, rewards[1].NodeID == can3.NodeId)
 		return nil
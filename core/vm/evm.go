@@ -114,7 +114,15 @@ func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, err
 					Evm:       evm,
 				}
 				return RunPlatONPrecompiledContract(delegateRewardContract, input, contract)
-
+			case *ScheduledTxContract:
+				if gov.Gte150VersionState(evm.StateDB) {
+					scheduledTxContract := &ScheduledTxContract{
+						Plugin:   plugin.ScheduledTxInstance(),
+						Contract: contract,
+						Evm:      evm,
+					}
+					return RunPlatONPrecompiledContract(scheduledTxContract, input, contract)
+				}
 			}
 		}
 	}
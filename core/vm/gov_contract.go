@@ -37,6 +37,7 @@ const (
 	Vote                  = uint16(2003)
 	Declare               = uint16(2004)
 	SubmitCancel          = uint16(2005)
+	SubmitAllowlist       = uint16(2006)
 	GetProposal           = uint16(2100)
 	GetResult             = uint16(2101)
 	ListProposal          = uint16(2102)
@@ -44,6 +45,7 @@ const (
 	GetGovernParamValue   = uint16(2104)
 	GetAccuVerifiersCount = uint16(2105)
 	ListGovernParam       = uint16(2106)
+	ListAllowlist         = uint16(2107)
 )
 
 var (
@@ -73,12 +75,13 @@ func (gc *GovContract) Run(input []byte) ([]byte, error) {
 func (gc *GovContract) FnSigns() map[uint16]interface{} {
 	return map[uint16]interface{}{
 		// Set
-		SubmitText:    gc.submitText,
-		SubmitVersion: gc.submitVersion,
-		Vote:          gc.vote,
-		Declare:       gc.declareVersion,
-		SubmitCancel:  gc.submitCancel,
-		SubmitParam:   gc.submitParam,
+		SubmitText:      gc.submitText,
+		SubmitVersion:   gc.submitVersion,
+		Vote:            gc.vote,
+		Declare:         gc.declareVersion,
+		SubmitCancel:    gc.submitCancel,
+		SubmitParam:     gc.submitParam,
+		SubmitAllowlist: gc.submitAllowlist,
 
 		// Get
 		GetProposal:           gc.getProposal,
@@ -88,6 +91,7 @@ func (gc *GovContract) FnSigns() map[uint16]interface{} {
 		GetGovernParamValue:   gc.getGovernParamValue,
 		GetAccuVerifiersCount: gc.getAccuVerifiersCount,
 		ListGovernParam:       gc.listGovernParam,
+		ListAllowlist:         gc.listAllowlist,
 	}
 }
 
@@ -105,7 +109,7 @@ func (gc *GovContract) CheckGasPrice(gasPrice *big.Int, fcode uint16) error {
 		if gasPrice.Cmp(params.SubmitCancelProposalGasPrice) < 0 {
 			return common.InvalidParameter.Wrap(ErrUnderPrice.Error())
 		}
-	case SubmitParam:
+	case SubmitParam, SubmitAllowlist:
 		if gasPrice.Cmp(params.SubmitParamProposalGasPrice) < 0 {
 			return common.InvalidParameter.Wrap(ErrUnderPrice.Error())
 		}
@@ -274,6 +278,49 @@ func (gc *GovContract) submitParam(verifier discover.NodeID, pipID string, modul
 	return gc.nonCallHandler("submitParam", SubmitParam, err)
 }
 
+// submitAllowlist submits a proposal to add the node into the allowlist or remove it.
+func (gc *GovContract) submitAllowlist(verifier discover.NodeID, pipID string, operation uint8, nodeId discover.NodeID, stakingAddress common.Address) ([]byte, error) {
+	from := gc.Contract.CallerAddress
+	blockNumber := gc.Evm.Context.BlockNumber.Uint64()
+	blockHash := gc.Evm.Context.BlockHash
+	txHash := gc.Evm.StateDB.TxHash()
+
+	log.Debug("call submitAllowlist of GovContract",
+		"from", from,
+		"txHash", txHash,
+		"blockNumber", blockNumber,
+		"PIPID", pipID,
+		"verifierID", verifier.TerminalString(),
+		"operation", operation,
+		"nodeId", nodeId.TerminalString(),
+		"stakingAddress", stakingAddress)
+
+	if !gc.Contract.UseGas(params.SubmitParamProposalGas) {
+		return nil, ErrOutOfGas
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	if gc.Evm.GasPrice.Cmp(params.SubmitParamProposalGasPrice) < 0 {
+		return nil, ErrUnderPrice
+	}
+
+	p := &gov.AllowlistProposal{
+		PIPID:          pipID,
+		ProposalType:   gov.Allowlist,
+		SubmitBlock:    blockNumber,
+		ProposalID:     txHash,
+		Proposer:       verifier,
+		Operation:      gov.AllowlistOperation(operation),
+		NodeId:         nodeId,
+		StakingAddress: stakingAddress,
+	}
+	err := gov.Submit(from, p, blockHash, blockNumber, plugin.StakingInstance(), gc.Evm.StateDB, gc.Evm.chainConfig.ChainID)
+	return gc.nonCallHandler("submitAllowlist", SubmitAllowlist, err)
+}
+
 func (gc *GovContract) vote(verifier discover.NodeID, proposalID common.Hash, op uint8, programVersion uint32, programVersionSign common.VersionSign) ([]byte, error) {
 	from := gc.Contract.CallerAddress
 	blockNumber := gc.Evm.Context.BlockNumber.Uint64()
@@ -469,6 +516,22 @@ func (gc *GovContract) listGovernParam(module string) ([]byte, error) {
 	return gc.callHandler("listGovernParam", paramList, err)
 }
 
+// listAllowlist returns the nodes admitted to stake
+func (gc *GovContract) listAllowlist() ([]byte, error) {
+	from := gc.Contract.CallerAddress
+	blockNumber := gc.Evm.Context.BlockNumber.Uint64()
+	blockHash := gc.Evm.Context.BlockHash
+	txHash := gc.Evm.StateDB.TxHash()
+	log.Debug("call listAllowlist of GovContract",
+		"from", from,
+		"txHash", txHash,
+		"blockNumber", blockNumber)
+
+	allowlist, err := gov.ListAllowlist(blockHash)
+
+	return gc.callHandler("listAllowlist", allowlist, err)
+}
+
 func (gc *GovContract) nonCallHandler(funcName string, fcode uint16, err error) ([]byte, error) {
 	if err != nil {
 		if bizErr, ok := err.(*common.BizError); ok {
@@ -75,6 +75,8 @@ type StateDB interface {
 	AddLog(*types.Log)
 	GetLogs(hash common.Hash) []*types.Log
 	AddPreimage(common.Hash, []byte)
+	AddBalanceChange(*types.BalanceChange)
+	SetTxContext(common.Hash, int)
 
 	ForEachStorage(common.Address, func([]byte, []byte) bool)
 	MigrateStorage(from, to common.Address)
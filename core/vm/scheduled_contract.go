@@ -0,0 +1,181 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/log"
+	"github.com/hashkey-chain/hashkey-chain/params"
+	"github.com/hashkey-chain/hashkey-chain/rlp"
+	"github.com/hashkey-chain/hashkey-chain/x/plugin"
+	"github.com/hashkey-chain/hashkey-chain/x/scheduled"
+)
+
+const (
+	TxScheduleTx             = 6000
+	TxCancelScheduledTx      = 6001
+	GetScheduledTx           = 6100
+	GetScheduledTxsByAccount = 6101
+)
+
+// ScheduledTxContract schedules signed transactions executed by the chain at
+// the beginning of a target block or epoch, it's active since the version
+// 1.5.0.
+type ScheduledTxContract struct {
+	Plugin   *plugin.ScheduledTxPlugin
+	Contract *Contract
+	Evm      *EVM
+}
+
+func (sc *ScheduledTxContract) RequiredGas(input []byte) uint64 {
+	if checkInputEmpty(input) {
+		return 0
+	}
+	return params.ScheduledTxGas
+}
+
+func (sc *ScheduledTxContract) Run(input []byte) ([]byte, error) {
+	if checkInputEmpty(input) {
+		return nil, nil
+	}
+	return execPlatonContract(input, sc.FnSigns())
+}
+
+func (sc *ScheduledTxContract) FnSigns() map[uint16]interface{} {
+	return map[uint16]interface{}{
+		// Set
+		TxScheduleTx:        sc.scheduleTx,
+		TxCancelScheduledTx: sc.cancelScheduledTx,
+
+		// Get
+		GetScheduledTx:           sc.getScheduledTx,
+		GetScheduledTxsByAccount: sc.getScheduledTxsByAccount,
+	}
+}
+
+func (sc *ScheduledTxContract) CheckGasPrice(gasPrice *big.Int, fcode uint16) error {
+	return nil
+}
+
+// scheduleTx schedules the RLP encoded signed transaction at the target block
+// or epoch, the caller pays the gas of the transaction in advance. The
+// transaction must be signed by the scheduled signer, so that it can't be
+// replayed as an ordinary transaction.
+func (sc *ScheduledTxContract) scheduleTx(encodedTx []byte, targetType uint8, target uint64) ([]byte, error) {
+	from := sc.Contract.CallerAddress
+	txHash := sc.Evm.StateDB.TxHash()
+	blockNumber := sc.Evm.Context.BlockNumber.Uint64()
+	blockHash := sc.Evm.Context.BlockHash
+	state := sc.Evm.StateDB
+
+	log.Debug("Call scheduleTx of ScheduledTxContract", "blockNumber", blockNumber,
+		"blockHash", blockHash.TerminalString(), "txHash", txHash.Hex(), "from", from, "targetType", targetType, "target", target)
+
+	if !sc.Contract.UseGas(params.ScheduleTxGas) {
+		return nil, ErrOutOfGas
+	}
+
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); nil != err {
+		return txResultHandler(vm.ScheduledTxContractAddr, sc.Evm, "scheduleTx",
+			err.Error(), TxScheduleTx, scheduled.ErrInvalidScheduledTx)
+	}
+	sender, err := types.Sender(types.MakeScheduledSigner(sc.Evm.ChainConfig()), tx)
+	if nil != err {
+		return txResultHandler(vm.ScheduledTxContractAddr, sc.Evm, "scheduleTx",
+			err.Error(), TxScheduleTx, scheduled.ErrInvalidTxSender)
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	stx, err := sc.Plugin.AddScheduledTx(from, sender, tx, targetType, target, blockNumber, blockHash, state)
+	if nil != err {
+		if bizErr, ok := err.(*common.BizError); ok {
+			return txResultHandler(vm.ScheduledTxContractAddr, sc.Evm, "scheduleTx",
+				bizErr.Error(), TxScheduleTx, bizErr)
+		}
+		log.Error("Failed to scheduleTx", "txHash", txHash, "blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+	return txResultHandlerWithRes(vm.ScheduledTxContractAddr, sc.Evm, "", "", TxScheduleTx, int(common.NoErr.Code), stx.ID), nil
+}
+
+// cancelScheduledTx cancels the pending transaction and refunds the escrowed
+// gas to the submitter, the submitter or the sender can cancel it.
+func (sc *ScheduledTxContract) cancelScheduledTx(id uint64) ([]byte, error) {
+	from := sc.Contract.CallerAddress
+	txHash := sc.Evm.StateDB.TxHash()
+	blockNumber := sc.Evm.Context.BlockNumber.Uint64()
+	blockHash := sc.Evm.Context.BlockHash
+	state := sc.Evm.StateDB
+
+	log.Debug("Call cancelScheduledTx of ScheduledTxContract", "blockNumber", blockNumber,
+		"blockHash", blockHash.TerminalString(), "txHash", txHash.Hex(), "from", from, "id", id)
+
+	if !sc.Contract.UseGas(params.CancelScheduledTxGas) {
+		return nil, ErrOutOfGas
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	err := sc.Plugin.CancelScheduledTx(from, id, blockNumber, state)
+	switch err.(type) {
+	case nil:
+		return txResultHandler(vm.ScheduledTxContractAddr, sc.Evm, "",
+			"", TxCancelScheduledTx, common.NoErr)
+	case *common.BizError:
+		bizErr := err.(*common.BizError)
+		return txResultHandler(vm.ScheduledTxContractAddr, sc.Evm, "cancelScheduledTx",
+			bizErr.Error(), TxCancelScheduledTx, bizErr)
+	default:
+		log.Error("Failed to cancelScheduledTx", "txHash", txHash, "blockNumber", blockNumber, "err", err)
+		return nil, err
+	}
+}
+
+func (sc *ScheduledTxContract) getScheduledTx(id uint64) ([]byte, error) {
+	title := fmt.Sprintf("getScheduledTx, id: %d", id)
+	stx, err := sc.Plugin.GetScheduledTx(id, sc.Evm.StateDB)
+	if nil != err {
+		if bizErr, ok := err.(*common.BizError); ok {
+			return callResultHandler(sc.Evm, title, nil, bizErr), nil
+		}
+		return callResultHandler(sc.Evm, title, nil, common.InternalError.Wrap(err.Error())), nil
+	}
+	return callResultHandler(sc.Evm, title, stx, nil), nil
+}
+
+func (sc *ScheduledTxContract) getScheduledTxsByAccount(account common.Address) ([]byte, error) {
+	title := fmt.Sprintf("getScheduledTxsByAccount, account: %s", account.String())
+	list, err := sc.Plugin.GetScheduledTxsByAccount(account, sc.Evm.StateDB)
+	if nil != err {
+		if bizErr, ok := err.(*common.BizError); ok {
+			return callResultHandler(sc.Evm, title, nil, bizErr), nil
+		}
+		return callResultHandler(sc.Evm, title, nil, common.InternalError.Wrap(err.Error())), nil
+	}
+	return callResultHandler(sc.Evm, title, list, nil), nil
+}
@@ -46,23 +46,26 @@ import (
 )
 
 const (
-	TxCreateStaking      = 1000
-	TxEditorCandidate    = 1001
-	TxIncreaseStaking    = 1002
-	TxWithdrewCandidate  = 1003
-	TxDelegate           = 1004
-	TxWithdrewDelegation = 1005
-	TxRedeemDelegation   = 1006
-	QueryVerifierList    = 1100
-	QueryValidatorList   = 1101
-	QueryCandidateList   = 1102
-	QueryRelateList      = 1103
-	QueryDelegateInfo    = 1104
-	QueryCandidateInfo   = 1105
-	QueryDelegationLock  = 1106
-	GetPackageReward     = 1200
-	GetStakingReward     = 1201
-	GetAvgPackTime       = 1202
+	TxCreateStaking         = 1000
+	TxEditorCandidate       = 1001
+	TxIncreaseStaking       = 1002
+	TxWithdrewCandidate     = 1003
+	TxDelegate              = 1004
+	TxWithdrewDelegation    = 1005
+	TxRedeemDelegation      = 1006
+	TxRotateCandidateKey    = 1007
+	QueryVerifierList       = 1100
+	QueryValidatorList      = 1101
+	QueryCandidateList      = 1102
+	QueryRelateList         = 1103
+	QueryDelegateInfo       = 1104
+	QueryCandidateInfo      = 1105
+	QueryDelegationLock     = 1106
+	QueryCommissionSchedule = 1107
+	QueryCommissionHistory  = 1108
+	GetPackageReward        = 1200
+	GetStakingReward        = 1201
+	GetAvgPackTime          = 1202
 )
 
 const (
@@ -125,6 +128,12 @@ func (stkc *StakingContract) FnSigns() map[uint16]interface{} {
 	fnSigns := stkc.FnSignsV1()
 	fnSigns[TxRedeemDelegation] = stkc.redeemDelegation
 	fnSigns[QueryDelegationLock] = stkc.getDelegateLock
+	// The functions of 1.4.0 are only callable after the version is active
+	if stkc.Evm == nil || gov.Gte140VersionState(stkc.Evm.StateDB) {
+		fnSigns[TxRotateCandidateKey] = stkc.rotateCandidateKey
+		fnSigns[QueryCommissionSchedule] = stkc.getCommissionSchedule
+		fnSigns[QueryCommissionHistory] = stkc.getCommissionHistory
+	}
 	return fnSigns
 }
 
@@ -255,6 +264,29 @@ func (stkc *StakingContract) createStaking(typ uint16, benefitAddress common.Add
 			"can is not nil",
 			TxCreateStaking, staking.ErrCanAlreadyExist)
 	}
+
+	// the nodeId may be the rotated nodeId of another candidate
+	if used, err := stkc.Plugin.HasNodeIdAlias(blockHash, canAddr, canAddr); nil != err {
+		log.Error("Failed to createStaking by HasNodeIdAlias", "txHash", txHash,
+			"blockNumber", blockNumber, "err", err)
+		return nil, err
+	} else if used {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "createStaking",
+			"the nodeId is the rotated nodeId of another candidate",
+			TxCreateStaking, staking.ErrNodeIdAlreadyUsed)
+	}
+
+	// the node must be in the allowlist and staked by the address allowed for it
+	if err := stkc.Plugin.CheckAdmission(blockNumber.Uint64(), blockHash, state, nodeId, &from); nil != err {
+		if bizErr, ok := err.(*common.BizError); ok {
+			return txResultHandler(vm.StakingContractAddr, stkc.Evm, "createStaking",
+				bizErr.Error(), TxCreateStaking, bizErr)
+		} else {
+			log.Error("Failed to createStaking by CheckAdmission", "txHash", txHash,
+				"blockNumber", blockNumber, "err", err)
+			return nil, err
+		}
+	}
 	if txHash == common.ZeroHash {
 		return nil, nil
 	}
@@ -424,7 +456,24 @@ func (stkc *StakingContract) editCandidate(benefitAddress *common.Address, nodeI
 		canOld.RewardPer = canOld.NextRewardPer
 	}
 
-	if rewardPer != nil && *rewardPer != canOld.NextRewardPer {
+	// Since 1.4.0 the commission changes are announced ahead,
+	// an increase takes effect after the notice epochs and a decrease at the next epoch
+	isGte140 := gov.Gte140VersionState(stkc.Evm.StateDB)
+	nextRewardPer := canOld.NextRewardPer
+	if isGte140 {
+		pending, err := stkc.Plugin.GetCommissionSchedule(blockHash, canAddr)
+		if snapshotdb.NonDbNotFoundErr(err) {
+			log.Error("Failed to editCandidate by GetCommissionSchedule", "txHash", txHash,
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "err", err)
+			return nil, err
+		}
+		if nil == err {
+			nextRewardPer = pending.RewardPer
+		}
+	}
+
+	var schedule *staking.CommissionSchedule
+	if rewardPer != nil && *rewardPer != nextRewardPer {
 		if !verifyRewardPer(*rewardPer) {
 			return txResultHandler(vm.StakingContractAddr, stkc.Evm, "editCandidate",
 				fmt.Sprintf("invalid rewardPer: %d", rewardPer),
@@ -457,6 +506,26 @@ func (stkc *StakingContract) editCandidate(benefitAddress *common.Address, nodeI
 				fmt.Sprintf("invalid rewardPer: %d, modified by more than: %d", rewardPer, rewardPerMaxChangeRange),
 				TxEditorCandidate, staking.ErrRewardPerChangeRange)
 		}
+		if isGte140 {
+			noticeEpochs := uint16(1)
+			if canOld.NextRewardPer > canOld.RewardPer {
+				noticeEpochs, err = gov.GovernRewardPerNoticeEpochs(blockNumber.Uint64(), blockHash)
+				if nil != err {
+					log.Error("Failed to editCandidate, call GovernRewardPerNoticeEpochs is failed", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(),
+						"err", err)
+					return nil, err
+				}
+			}
+			// The increase keeps the current ratio until the notice epochs passed
+			if noticeEpochs > 1 {
+				canOld.NextRewardPer = canOld.RewardPer
+			}
+			schedule = &staking.CommissionSchedule{
+				RewardPer:      *rewardPer,
+				AnnounceEpoch:  currentEpoch,
+				EffectiveEpoch: currentEpoch + uint32(noticeEpochs),
+			}
+		}
 		canOld.RewardPerChangeEpoch = currentEpoch
 	}
 
@@ -475,10 +544,130 @@ func (stkc *StakingContract) editCandidate(benefitAddress *common.Address, nodeI
 		}
 	}
 
+	if nil != schedule {
+		if err := stkc.Plugin.ScheduleCommission(blockHash, blockNumber, canAddr, nodeId, schedule); nil != err {
+			log.Error("Failed to editCandidate by ScheduleCommission", "txHash", txHash,
+				"blockNumber", blockNumber, "err", err)
+			return nil, err
+		}
+	}
+
 	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
 		"", TxEditorCandidate, common.NoErr)
 }
 
+func (stkc *StakingContract) rotateCandidateKey(nodeId, newNodeId discover.NodeID, nodeIdSign common.VersionSign,
+	blsPubKey bls.PublicKeyHex, blsProof bls.SchnorrProofHex) ([]byte, error) {
+
+	txHash := stkc.Evm.StateDB.TxHash()
+	blockNumber := stkc.Evm.Context.BlockNumber
+	blockHash := stkc.Evm.Context.BlockHash
+	from := stkc.Contract.CallerAddress
+
+	log.Debug("Call rotateCandidateKey of stakingContract", "txHash", txHash.Hex(),
+		"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(), "nodeId", nodeId.String(),
+		"newNodeId", newNodeId.String(), "nodeIdSign", nodeIdSign.Hex(),
+		"blsPubKey", blsPubKey, "blsProof", blsProof, "from", from)
+
+	if !stkc.Contract.UseGas(params.RotateCandidateKeyGas) {
+		return nil, ErrOutOfGas
+	}
+
+	if len(blsPubKey) != BLSPUBKEYLEN {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateCandidateKey",
+			fmt.Sprintf("got blsKey length: %d, must be: %d", len(blsPubKey), BLSPUBKEYLEN),
+			TxRotateCandidateKey, staking.ErrWrongBlsPubKey)
+	}
+
+	if len(blsProof) != BLSPROOFLEN {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateCandidateKey",
+			fmt.Sprintf("got blsProof length: %d, must be: %d", len(blsProof), BLSPROOFLEN),
+			TxRotateCandidateKey, staking.ErrWrongBlsPubKeyProof)
+	}
+
+	// parse bls publickey
+	blsPk, err := blsPubKey.ParseBlsPubKey()
+	if nil != err {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateCandidateKey",
+			fmt.Sprintf("failed to parse blspubkey: %s", err.Error()),
+			TxRotateCandidateKey, staking.ErrWrongBlsPubKey)
+	}
+
+	// verify bls proof, the proof of possession of the new bls key
+	if err := verifyBlsProof(blsProof, blsPk); nil != err {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateCandidateKey",
+			fmt.Sprintf("failed to verify bls proof: %s", err.Error()),
+			TxRotateCandidateKey, staking.ErrWrongBlsPubKeyProof)
+	}
+
+	canAddr, err := xutil.NodeId2Addr(nodeId)
+	if nil != err {
+		log.Error("Failed to rotateCandidateKey by parse nodeId", "txHash", txHash,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateCandidateKey",
+			fmt.Sprintf("nodeid %s to address fail: %s",
+				nodeId.String(), err.Error()),
+			TxRotateCandidateKey, staking.ErrNodeID2Addr)
+	}
+
+	canOld, err := stkc.Plugin.GetCandidateInfo(blockHash, canAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to rotateCandidateKey by GetCandidateInfo", "txHash", txHash,
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "err", err)
+		return nil, err
+	}
+
+	if canOld.IsEmpty() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateCandidateKey",
+			"can is nil", TxRotateCandidateKey, staking.ErrCanNoExist)
+	}
+
+	if canOld.IsInvalid() {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateCandidateKey",
+			fmt.Sprintf("can status is: %d", canOld.Status),
+			TxRotateCandidateKey, staking.ErrCanStatusInvalid)
+	}
+
+	if from != canOld.StakingAddress {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateCandidateKey",
+			fmt.Sprintf("contract sender: %s, can stake addr: %s", from, canOld.StakingAddress),
+			TxRotateCandidateKey, staking.ErrNoSameStakingAddr)
+	}
+
+	// validate the sign of the new node key, the proof of possession of the new node key,
+	// it can not be replayed by other candidates or in other Epochs
+	proof := &staking.RotateKeyProof{
+		StakingAddress: canOld.StakingAddress,
+		NodeId:         canOld.NodeId,
+		NewNodeId:      newNodeId,
+		Epoch:          xutil.CalculateEpoch(blockNumber.Uint64()),
+	}
+	if !node.GetCryptoHandler().IsSignedByNodeID(proof, nodeIdSign.Bytes(), newNodeId) {
+		return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateCandidateKey",
+			"call IsSignedByNodeID is failed",
+			TxRotateCandidateKey, staking.ErrWrongNodeIdSign)
+	}
+
+	if txHash == common.ZeroHash {
+		return nil, nil
+	}
+
+	_, err = stkc.Plugin.RotateCandidateKey(blockHash, blockNumber, canAddr, canOld.CandidateBase, newNodeId, blsPubKey)
+	if nil != err {
+		if bizErr, ok := err.(*common.BizError); ok {
+			return txResultHandler(vm.StakingContractAddr, stkc.Evm, "rotateCandidateKey",
+				bizErr.Error(), TxRotateCandidateKey, bizErr)
+		} else {
+			log.Error("Failed to rotateCandidateKey by RotateCandidateKey", "txHash", txHash,
+				"blockNumber", blockNumber, "err", err)
+			return nil, err
+		}
+	}
+
+	return txResultHandler(vm.StakingContractAddr, stkc.Evm, "",
+		"", TxRotateCandidateKey, common.NoErr)
+}
+
 func (stkc *StakingContract) increaseStaking(nodeId discover.NodeID, typ uint16, amount *big.Int) ([]byte, error) {
 
 	txHash := stkc.Evm.StateDB.TxHash()
@@ -667,6 +856,18 @@ func (stkc *StakingContract) delegate(typ uint16, nodeId discover.NodeID, amount
 			TxDelegate, staking.ErrCanStatusInvalid)
 	}
 
+	// the node removed from the allowlist can't be delegated
+	if err := stkc.Plugin.CheckAdmission(blockNumber.Uint64(), blockHash, state, nodeId, nil); nil != err {
+		if bizErr, ok := err.(*common.BizError); ok {
+			return txResultHandler(vm.StakingContractAddr, stkc.Evm, "delegate",
+				bizErr.Error(), TxDelegate, bizErr)
+		} else {
+			log.Error("Failed to delegate by CheckAdmission", "txHash", txHash,
+				"blockNumber", blockNumber, "err", err)
+			return nil, err
+		}
+	}
+
 	// the can base must exist if canMutable is exist,so no need check if canBase==nil
 	canBase, err := stkc.Plugin.GetCanBase(blockHash, canAddr)
 	if snapshotdb.NonDbNotFoundErr(err) {
@@ -1022,6 +1223,50 @@ func (stkc *StakingContract) getCandidateInfo(nodeId discover.NodeID) ([]byte, e
 		nodeId), can, nil), nil
 }
 
+func (stkc *StakingContract) getCommissionSchedule(nodeId discover.NodeID) ([]byte, error) {
+	blockHash := stkc.Evm.Context.BlockHash
+
+	canAddr, err := xutil.NodeId2Addr(nodeId)
+	if nil != err {
+		return callResultHandler(stkc.Evm, fmt.Sprintf("getCommissionSchedule, nodeId: %s",
+			nodeId), nil, staking.ErrQueryCommissionInfo.Wrap(err.Error())), nil
+	}
+	schedule, err := stkc.Plugin.GetCommissionSchedule(blockHash, canAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return callResultHandler(stkc.Evm, fmt.Sprintf("getCommissionSchedule, nodeId: %s",
+			nodeId), nil, staking.ErrQueryCommissionInfo.Wrap(err.Error())), nil
+	}
+	if snapshotdb.IsDbNotFoundErr(err) {
+		return callResultHandler(stkc.Evm, fmt.Sprintf("getCommissionSchedule, nodeId: %s",
+			nodeId), nil, staking.ErrQueryCommissionInfo.Wrap("Commission schedule is not found")), nil
+	}
+
+	return callResultHandler(stkc.Evm, fmt.Sprintf("getCommissionSchedule, nodeId: %s",
+		nodeId), schedule, nil), nil
+}
+
+func (stkc *StakingContract) getCommissionHistory(nodeId discover.NodeID) ([]byte, error) {
+	blockHash := stkc.Evm.Context.BlockHash
+
+	canAddr, err := xutil.NodeId2Addr(nodeId)
+	if nil != err {
+		return callResultHandler(stkc.Evm, fmt.Sprintf("getCommissionHistory, nodeId: %s",
+			nodeId), nil, staking.ErrQueryCommissionInfo.Wrap(err.Error())), nil
+	}
+	history, err := stkc.Plugin.GetCommissionHistory(blockHash, canAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return callResultHandler(stkc.Evm, fmt.Sprintf("getCommissionHistory, nodeId: %s",
+			nodeId), nil, staking.ErrQueryCommissionInfo.Wrap(err.Error())), nil
+	}
+	if snapshotdb.IsDbNotFoundErr(err) {
+		return callResultHandler(stkc.Evm, fmt.Sprintf("getCommissionHistory, nodeId: %s",
+			nodeId), nil, staking.ErrQueryCommissionInfo.Wrap("Commission history is not found")), nil
+	}
+
+	return callResultHandler(stkc.Evm, fmt.Sprintf("getCommissionHistory, nodeId: %s",
+		nodeId), history, nil), nil
+}
+
 func (stkc *StakingContract) getPackageReward() ([]byte, error) {
 	packageReward, err := plugin.LoadNewBlockReward(common.ZeroHash, stkc.Evm.SnapshotDB)
 	if nil != err {
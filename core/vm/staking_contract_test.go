@@ -34,6 +34,7 @@ import (
 	"github.com/hashkey-chain/hashkey-chain/x/gov"
 
 	"github.com/hashkey-chain/hashkey-chain/node"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
 
 	"github.com/stretchr/testify/assert"
 
@@ -907,6 +908,101 @@ func TestStakingContract_withdrewCandidate(t *testing.T) {
 
 }
 
+func TestStakingContract_rotateCandidateKey(t *testing.T) {
+
+	chain := newMockChain()
+	defer chain.SnapDB.Clear()
+	newPlugins()
+
+	index := 1
+
+	if err := chain.SnapDB.NewBlock(blockNumber, chain.Genesis.Hash(), blockHash); nil != err {
+		t.Error("newBlock err", err)
+		return
+	}
+
+	chain.StateDB.Prepare(txHashArr[0], blockHash, 0)
+	create_staking(blockNumber, blockHash, chain, index, t)
+
+	if err := chain.SnapDB.Commit(blockHash); nil != err {
+		t.Errorf("Failed to commit snapshotdb, blockNumber: %d, blockHash: %s, err: %v", blockNumber, blockHash.Hex(), err)
+		return
+	}
+
+	if err := chain.SnapDB.NewBlock(blockNumber2, blockHash, blockHash2); nil != err {
+		t.Errorf("newBlock failed, blockNumber2: %d, err:%v", blockNumber2, err)
+		return
+	}
+
+	contract := &StakingContract{
+		Plugin:   plugin.StakingInstance(),
+		Contract: newContract(common.Big0, sender),
+		Evm:      newEvm(blockNumber2, blockHash2, chain),
+	}
+	assert.Nil(t, gov.AddActiveVersion(params.FORKVERSION_1_4_0, blockNumber2.Uint64(), chain.StateDB))
+	chain.StateDB.Prepare(txHashArr[1], blockHash2, 1)
+
+	newKey, _ := crypto.GenerateKey()
+	newNodeId := discover.PubkeyID(&newKey.PublicKey)
+
+	var blsKey bls.SecretKey
+	blsKey.SetByCSPRNG()
+	var blsPubKey bls.PublicKeyHex
+	blsPubKey.UnmarshalText([]byte(hex.EncodeToString(blsKey.GetPublicKey().Serialize())))
+	proof, _ := blsKey.MakeSchnorrNIZKP()
+	proofByte, _ := proof.MarshalText()
+	var blsProof bls.SchnorrProofHex
+	blsProof.UnmarshalText(proofByte)
+
+	rotate := func(msg *staking.RotateKeyProof) uint32 {
+		sig, err := crypto.Sign(node.RlpHash(msg).Bytes(), newKey)
+		assert.Nil(t, err)
+		var nodeIdSign common.VersionSign
+		nodeIdSign.SetBytes(sig)
+
+		fnType, _ := rlp.EncodeToBytes(uint16(TxRotateCandidateKey))
+		nodeId, _ := rlp.EncodeToBytes(nodeIdArr[index])
+		newNodeIdBytes, _ := rlp.EncodeToBytes(newNodeId)
+		sign, _ := rlp.EncodeToBytes(nodeIdSign)
+		blsPubKeyBytes, _ := rlp.EncodeToBytes(blsPubKey)
+		blsProofBytes, _ := rlp.EncodeToBytes(blsProof)
+
+		buf := new(bytes.Buffer)
+		assert.Nil(t, rlp.Encode(buf, [][]byte{fnType, nodeId, newNodeIdBytes, sign, blsPubKeyBytes, blsProofBytes}))
+		res, err := contract.Run(buf.Bytes())
+		if bizErr, ok := err.(*common.BizError); ok {
+			return bizErr.Code
+		}
+		assert.Nil(t, err)
+		var r uint32
+		assert.Nil(t, json.Unmarshal(res, &r))
+		return r
+	}
+
+	epoch := xutil.CalculateEpoch(blockNumber2.Uint64())
+
+	// the sign for another candidate
+	assert.Equal(t, staking.ErrWrongNodeIdSign.Code, rotate(&staking.RotateKeyProof{
+		StakingAddress: sender,
+		NodeId:         nodeIdArr[index+1],
+		NewNodeId:      newNodeId,
+		Epoch:          epoch,
+	}))
+	// the sign made in another Epoch
+	assert.Equal(t, staking.ErrWrongNodeIdSign.Code, rotate(&staking.RotateKeyProof{
+		StakingAddress: sender,
+		NodeId:         nodeIdArr[index],
+		NewNodeId:      newNodeId,
+		Epoch:          epoch + 1,
+	}))
+	assert.Equal(t, common.OkCode, rotate(&staking.RotateKeyProof{
+		StakingAddress: sender,
+		NodeId:         nodeIdArr[index],
+		NewNodeId:      newNodeId,
+		Epoch:          epoch,
+	}))
+}
+
 func TestStakingContract_delegate(t *testing.T) {
 
 	chain := newMockChain()
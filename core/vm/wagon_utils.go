@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/core/lru"
 
 	"github.com/hashkey-chain/hashkey-chain/rlp"
 
@@ -40,6 +41,35 @@ func ReadWasmModule(Code []byte, verify bool) (*exec.CompiledModule, error) {
 	return compiled, nil
 }
 
+// wasmModuleSize estimates the memory held by the module compiled from the
+// code, the compiled functions take about the size of the code besides the
+// code itself and the initial linear memory.
+func wasmModuleSize(code []byte, module *exec.CompiledModule) int {
+	size := 2 * len(code)
+	if mem := module.RawModule.Memory; mem != nil && len(mem.Entries) != 0 {
+		size += int(mem.Entries[0].Limits.Initial) * wasmPageSize
+	}
+	return size
+}
+
+// OpenWasmModuleStore persists the compiled wasm modules at the path and loads
+// the stored modules into the cache in the background.
+func OpenWasmModuleStore(path string) (*lru.WasmModuleStore, error) {
+	store, err := lru.OpenWasmModuleStore(path)
+	if err != nil {
+		return nil, err
+	}
+	lru.WasmCache().SetStore(store)
+	store.Load(lru.WasmCache(), func(code []byte) (*lru.WasmModule, error) {
+		module, err := ReadWasmModule(code, unVerifyModule)
+		if err != nil {
+			return nil, err
+		}
+		return &lru.WasmModule{Module: module, Size: wasmModuleSize(code, module)}, nil
+	})
+	return store, nil
+}
+
 func decodeFuncAndParams(input []byte) (uint64, []byte, error) {
 	content, _, err := rlp.SplitList(input)
 	if nil != err {
@@ -10,7 +10,9 @@ import (
 	"github.com/hashkey-chain/hashkey-chain/log"
 	"github.com/hashkey-chain/hashkey-chain/rlp"
 
+	"github.com/hashkey-chain/hashkey-chain/common"
 	"github.com/hashkey-chain/hashkey-chain/core/lru"
+	"github.com/hashkey-chain/hashkey-chain/crypto"
 
 	"github.com/PlatONnetwork/wagon/exec"
 	"github.com/pkg/errors"
@@ -22,6 +24,9 @@ const (
 )
 const memoryLimit = 16 * 1024 * 1024
 
+// wasmPageSize is the size of a page of the wasm linear memory.
+const wasmPageSize = 64 * 1024
+
 const (
 	verifyModule   = true
 	unVerifyModule = false
@@ -220,11 +225,21 @@ func validateCallFunc(input []byte) error {
 
 func (engine *wagonEngine) makeModuleWithDeploy() (*exec.CompiledModule, int64, error) {
 
-	cache := &lru.WasmModule{}
-	module, err := ReadWasmModule(engine.Contract().Code, verifyModule)
-	if nil != err {
-		return nil, 0, err
+	// The module compiled for the calls or loaded from the store is not
+	// verified, it's compiled again with the verification
+	code := engine.Contract().Code
+	hash := crypto.Keccak256Hash(code)
+	cache, ok := lru.WasmCache().Get(hash)
+	if !ok || (ok && nil == cache.Module) || !cache.Verified {
+		module, err := ReadWasmModule(code, verifyModule)
+		if nil != err {
+			return nil, 0, err
+		}
+		cache = &lru.WasmModule{Module: module, Size: wasmModuleSize(code, module), Verified: true}
+		lru.WasmCache().Add(hash, cache, code)
 	}
+
+	module := cache.Module
 	// Short circuit if the `invoke` function is not existing in the module
 	entry, ok := module.RawModule.Export.Entries[callEntryName]
 	if !ok {
@@ -237,26 +252,25 @@ func (engine *wagonEngine) makeModuleWithDeploy() (*exec.CompiledModule, int64,
 	if len(ftype.ParamTypes) > 0 || len(ftype.ReturnTypes) > 0 {
 		return nil, 0, errors.New("function sig error")
 	}
-
-	cache.Module = module
-	lru.WasmCache().Add(*(engine.Contract().CodeAddr), cache)
 	return module, index, nil
 }
 
 func (engine *wagonEngine) makeModuleWithCall() (*exec.CompiledModule, int64, error) {
 
 	// load module
-	cache, ok := lru.WasmCache().Get(*(engine.Contract().CodeAddr))
+	code := engine.Contract().Code
+	hash := engine.Contract().CodeHash
+	if hash == (common.Hash{}) {
+		hash = crypto.Keccak256Hash(code)
+	}
+	cache, ok := lru.WasmCache().Get(hash)
 	if !ok || (ok && nil == cache.Module) {
-		cache = &lru.WasmModule{}
-
-		module, err := ReadWasmModule(engine.Contract().Code, unVerifyModule)
+		module, err := ReadWasmModule(code, unVerifyModule)
 		if nil != err {
 			return nil, 0, err
 		}
-
-		cache.Module = module
-		lru.WasmCache().Add(*(engine.Contract().CodeAddr), cache)
+		cache = &lru.WasmModule{Module: module, Size: wasmModuleSize(code, module)}
+		lru.WasmCache().Add(hash, cache, code)
 	}
 
 	mod := cache.Module
@@ -12,6 +12,8 @@ import (
 
 	"github.com/hashkey-chain/hashkey-chain/common"
 	"github.com/hashkey-chain/hashkey-chain/common/mock"
+	"github.com/hashkey-chain/hashkey-chain/core/lru"
+	"github.com/hashkey-chain/hashkey-chain/crypto"
 )
 
 func TestWasmRun(t *testing.T) {
@@ -191,6 +193,32 @@ func TestWasmRun(t *testing.T) {
 	assert.Nil(t, ret)
 }
 
+func TestWasmDeployVerifiesCachedModule(t *testing.T) {
+	code, err := ioutil.ReadFile("./testdata/contract_hello.wasm")
+	assert.Nil(t, err)
+
+	// The module compiled for the calls is cached unverified
+	hash := crypto.Keccak256Hash(code)
+	module, err := ReadWasmModule(code, unVerifyModule)
+	assert.Nil(t, err)
+	lru.WasmCache().Add(hash, &lru.WasmModule{Module: module, Size: wasmModuleSize(code, module)}, nil)
+
+	engine := &wagonEngine{contract: &Contract{Code: code, DeployContract: true}}
+	_, _, err = engine.MakeModule(true)
+	assert.Nil(t, err)
+	cache, ok := lru.WasmCache().Get(hash)
+	assert.True(t, ok)
+	assert.True(t, cache.Verified)
+	assert.True(t, module != cache.Module)
+
+	// The verified module is kept for the calls
+	engine.contract.DeployContract = false
+	_, _, err = engine.MakeModule(false)
+	assert.Nil(t, err)
+	cache, _ = lru.WasmCache().Get(hash)
+	assert.True(t, cache.Verified)
+}
+
 func deployData(t *testing.T, funcName, filePath string) []byte {
 
 	buf, err := ioutil.ReadFile(filePath)
@@ -0,0 +1,103 @@
+package bls
+
+import (
+	"crypto/rand"
+)
+
+// batchScalarBytes is the size of the random weights of a signature batch, 64
+// bits bound the chance of a forged batch passing to 2^-64 while keeping the
+// scalar multiplications cheap.
+const batchScalarBytes = 8
+
+// SignatureBatch collects signatures of distinct messages and public keys to
+// verify them together. Every signature is weighted by a random scalar r_i, so
+// that the batch holds only if each signature is valid, and all the pairing
+// checks are merged into one multi-pairing with a single final exponentiation:
+//
+//	e(-sum(r_i*sig_i), Q) * prod(e(r_i*H(m_i), pub_i)) == 1
+type SignatureBatch struct {
+	sigs []Sign
+	pubs []PublicKey
+	msgs [][]byte
+}
+
+// NewSignatureBatch creates an empty signature batch.
+func NewSignatureBatch() *SignatureBatch {
+	return &SignatureBatch{}
+}
+
+// Add appends the signature of the message by the (aggregated) public key to the batch.
+func (b *SignatureBatch) Add(sig *Sign, pub *PublicKey, msg []byte) {
+	b.sigs = append(b.sigs, *sig)
+	b.pubs = append(b.pubs, *pub)
+	b.msgs = append(b.msgs, msg)
+}
+
+// Len returns the number of signatures in the batch.
+func (b *SignatureBatch) Len() int {
+	return len(b.sigs)
+}
+
+// Verify checks all the signatures of the batch at once, it returns false if
+// any of them is invalid without telling which one.
+func (b *SignatureBatch) Verify() bool {
+	switch len(b.sigs) {
+	case 0:
+		return true
+	case 1:
+		if len(b.msgs[0]) == 0 {
+			return false
+		}
+		return b.sigs[0].Verify(&b.pubs[0], string(b.msgs[0]))
+	}
+
+	var (
+		aggSig G1
+		e, ml  GT
+	)
+	aggSig.Clear()
+	e.SetInt64(1)
+	for i := range b.sigs {
+		if len(b.msgs[i]) == 0 {
+			return false
+		}
+		r, err := batchScalar()
+		if err != nil {
+			return false
+		}
+
+		var weighted, hm G1
+		G1Mul(&weighted, &b.sigs[i].v, r)
+		G1Add(&aggSig, &aggSig, &weighted)
+
+		if err := hm.HashAndMapTo(b.msgs[i]); err != nil {
+			return false
+		}
+		G1Mul(&hm, &hm, r)
+		MillerLoop(&ml, &hm, &b.pubs[i].v)
+		GTMul(&e, &e, &ml)
+	}
+
+	G1Neg(&aggSig, &aggSig)
+	MillerLoop(&ml, &aggSig, &GetGeneratorOfG2().v)
+	GTMul(&e, &e, &ml)
+	FinalExp(&e, &e)
+	return e.IsOne()
+}
+
+// batchScalar returns a random non-zero weight for a signature of a batch.
+func batchScalar() (*Fr, error) {
+	var (
+		r   Fr
+		buf [batchScalarBytes]byte
+	)
+	for r.IsZero() {
+		if _, err := rand.Read(buf[:]); err != nil {
+			return nil, err
+		}
+		if err := r.SetLittleEndian(buf[:]); err != nil {
+			return nil, err
+		}
+	}
+	return &r, nil
+}
@@ -0,0 +1,97 @@
+package bls
+
+import (
+	"fmt"
+	"testing"
+)
+
+func makeSignatureBatch(t testing.TB, n int) (*SignatureBatch, []SecretKey) {
+	secs := make([]SecretKey, n)
+	batch := NewSignatureBatch()
+	for i := 0; i < n; i++ {
+		secs[i].SetByCSPRNG()
+		msg := fmt.Sprintf("qc%d", i)
+		batch.Add(secs[i].Sign(msg), secs[i].GetPublicKey(), []byte(msg))
+	}
+	return batch, secs
+}
+
+func TestSignatureBatch(t *testing.T) {
+	if err := Init(BLS12_381); err != nil {
+		t.Fatal(err)
+	}
+	if !NewSignatureBatch().Verify() {
+		t.Fatal("empty batch should be valid")
+	}
+	for _, n := range []int{1, 2, 10} {
+		batch, secs := makeSignatureBatch(t, n)
+		if batch.Len() != n {
+			t.Fatalf("batch length mismatch, have %d, want %d", batch.Len(), n)
+		}
+		if !batch.Verify() {
+			t.Fatalf("batch of %d valid signatures failed", n)
+		}
+
+		// A signature of another message must break the batch.
+		bad, _ := makeSignatureBatch(t, 0)
+		for i := 0; i < n; i++ {
+			msg := fmt.Sprintf("qc%d", i)
+			sig := secs[i].Sign(msg)
+			if i == n-1 {
+				sig = secs[i].Sign("forged")
+			}
+			bad.Add(sig, secs[i].GetPublicKey(), []byte(msg))
+		}
+		if bad.Verify() {
+			t.Fatalf("batch of %d signatures with a bad one passed", n)
+		}
+	}
+}
+
+func TestSignatureBatchAggregated(t *testing.T) {
+	if err := Init(BLS12_381); err != nil {
+		t.Fatal(err)
+	}
+	batch := NewSignatureBatch()
+	for i := 0; i < 4; i++ {
+		msg := fmt.Sprintf("qc%d", i)
+		var (
+			pub PublicKey
+			sig Sign
+		)
+		for j := 0; j < 3; j++ {
+			var sec SecretKey
+			sec.SetByCSPRNG()
+			pub.Add(sec.GetPublicKey())
+			sig.Add(sec.Sign(msg))
+		}
+		batch.Add(&sig, &pub, []byte(msg))
+	}
+	if !batch.Verify() {
+		t.Fatal("batch of aggregated signatures failed")
+	}
+}
+
+func BenchmarkSignatureBatch(b *testing.B) {
+	if err := Init(BLS12_381); err != nil {
+		b.Fatal(err)
+	}
+	batch, _ := makeSignatureBatch(b, 32)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		batch.Verify()
+	}
+}
+
+func BenchmarkSignatureSeparate(b *testing.B) {
+	if err := Init(BLS12_381); err != nil {
+		b.Fatal(err)
+	}
+	batch, _ := makeSignatureBatch(b, 32)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range batch.sigs {
+			batch.sigs[j].Verify(&batch.pubs[j], string(batch.msgs[j]))
+		}
+	}
+}
@@ -318,15 +318,19 @@ func (api *PrivateDebugAPI) AccountRange(ctx context.Context, start *common.Hash
 	block := api.eth.blockchain.CurrentBlock()
 
 	if len(block.Transactions()) == 0 {
-		statedb, err = api.computeStateDB(block, defaultTraceReexec)
+		var release func()
+		statedb, release, err = api.computeStateDB(block, defaultTraceReexec)
 		if err != nil {
 			return AccountRangeResult{}, err
 		}
+		release()
 	} else {
-		_, _, statedb, err = api.computeTxEnv(block, len(block.Transactions())-1, 0)
+		var release func()
+		_, _, statedb, release, err = api.computeTxEnv(block, len(block.Transactions())-1, 0)
 		if err != nil {
 			return AccountRangeResult{}, err
 		}
+		release()
 	}
 
 	trie, err := statedb.Database().OpenTrie(block.Header().Root)
@@ -357,10 +361,11 @@ func (api *PrivateDebugAPI) StorageRangeAt(ctx context.Context, blockHash common
 	if block == nil {
 		return StorageRangeResult{}, fmt.Errorf("block %#x not found", blockHash)
 	}
-	_, _, statedb, err := api.computeTxEnv(block, txIndex, 0)
+	_, _, statedb, release, err := api.computeTxEnv(block, txIndex, 0)
 	if err != nil {
 		return StorageRangeResult{}, err
 	}
+	release()
 	st := statedb.StorageTrie(contractAddress)
 	if st == nil {
 		return StorageRangeResult{}, fmt.Errorf("account %x doesn't exist", contractAddress)
@@ -182,6 +182,10 @@ func (b *EthAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (type
 	return b.eth.blockchain.GetReceiptsByHash(hash), nil
 }
 
+func (b *EthAPIBackend) GetBalanceChanges(ctx context.Context, hash common.Hash) ([]*types.BalanceChange, error) {
+	return b.eth.blockchain.GetBalanceChangesByHash(hash), nil
+}
+
 func (b *EthAPIBackend) GetLogs(ctx context.Context, hash common.Hash) ([][]*types.Log, error) {
 	receipts := b.eth.blockchain.GetReceiptsByHash(hash)
 	if receipts == nil {
@@ -222,6 +226,10 @@ func (b *EthAPIBackend) SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) e
 	return b.eth.BlockChain().SubscribeChainSideEvent(ch)
 }
 
+func (b *EthAPIBackend) SubscribeBalanceChangesEvent(ch chan<- core.BalanceChangesEvent) event.Subscription {
+	return b.eth.BlockChain().SubscribeBalanceChangesEvent(ch)
+}
+
 func (b *EthAPIBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription {
 	return b.eth.BlockChain().SubscribeLogsEvent(ch)
 }
@@ -251,6 +259,13 @@ func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash)
 	return tx, blockHash, blockNumber, index, nil
 }
 
+func (b *EthAPIBackend) GetAddressTransactions(ctx context.Context, address common.Address, offset, limit int) ([]rawdb.AddressTxEntry, error) {
+	if !b.eth.blockchain.AddressIndexEnabled() {
+		return nil, errors.New("transactions are not indexed by address, enable it with --addressindex")
+	}
+	return rawdb.ReadAddressTxEntries(b.eth.ChainDb(), address, offset, limit), nil
+}
+
 func (b *EthAPIBackend) GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error) {
 	return b.eth.txPool.Nonce(addr), nil
 }
@@ -307,6 +322,10 @@ func (b *EthAPIBackend) RPCTxFeeCap() float64 {
 	return b.eth.config.RPCTxFeeCap
 }
 
+func (b *EthAPIBackend) RPCSimulateCap() uint64 {
+	return b.eth.config.RPCSimulateCap
+}
+
 func (b *EthAPIBackend) BloomStatus() (uint64, uint64) {
 	sections, _, _ := b.eth.bloomIndexer.Sections()
 	return params.BloomBitsBlocks, sections
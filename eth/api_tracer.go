@@ -185,6 +185,11 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 			}
 		}
 	}
+	// The PPOS data must be regenerated from the same point
+	release, err := api.replayPPOS(start)
+	if err != nil {
+		return nil, err
+	}
 	// Execute all the transaction contained within the chain concurrently for each block
 	blocks := int(end.NumberU64() - origin)
 
@@ -242,6 +247,7 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 		defer func() {
 			close(tasks)
 			pend.Wait()
+			release()
 
 			switch {
 			case failed != nil:
@@ -454,10 +460,14 @@ func (api *PrivateDebugAPI) traceBlock(ctx context.Context, block *types.Block,
 	if config != nil && config.Reexec != nil {
 		reexec = *config.Reexec
 	}
-	statedb, err := api.computeStateDB(parent, reexec)
+	statedb, release, err := api.computeStateDB(parent, reexec)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
+	if err := api.beginBlock(block, statedb); err != nil {
+		return nil, err
+	}
 	// Execute all the transaction contained within the block concurrently
 	var (
 		signer = types.MakeSigner(api.eth.blockchain.Config(), false)
@@ -539,10 +549,14 @@ func (api *PrivateDebugAPI) standardTraceBlockToFile(ctx context.Context, block
 	if config != nil && config.Reexec != nil {
 		reexec = *config.Reexec
 	}
-	statedb, err := api.computeStateDB(parent, reexec)
+	statedb, release, err := api.computeStateDB(parent, reexec)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
+	if err := api.beginBlock(block, statedb); err != nil {
+		return nil, err
+	}
 	// Retrieve the tracing configurations, or use default values
 	var (
 		logConfig vm.LogConfig
@@ -644,11 +658,15 @@ func containsTx(block *types.Block, hash common.Hash) bool {
 // computeStateDB retrieves the state database associated with a certain block.
 // If no state is locally available for the given block, a number of blocks are
 // attempted to be reexecuted to generate the desired state.
-func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*state.StateDB, error) {
+func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*state.StateDB, func(), error) {
 	// If we have the state fully available, use that
 	statedb, err := api.eth.blockchain.StateAt(block.Root())
 	if err == nil {
-		return statedb, nil
+		release, err := api.replayPPOS(block)
+		if err != nil {
+			return nil, nil, err
+		}
+		return statedb, release, nil
 	}
 	// Otherwise try to reexec blocks until we find a state or reach our limit
 	origin := block.NumberU64()
@@ -666,11 +684,16 @@ func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*
 	if err != nil {
 		switch err.(type) {
 		case *trie.MissingNodeError:
-			return nil, fmt.Errorf("required historical state unavailable (reexec=%d)", reexec)
+			return nil, nil, fmt.Errorf("required historical state unavailable (reexec=%d)", reexec)
 		default:
-			return nil, err
+			return nil, nil, err
 		}
 	}
+	// The PPOS data must be regenerated from the same point
+	release, err := api.replayPPOS(block)
+	if err != nil {
+		return nil, nil, err
+	}
 	// State was available at historical point, regenerate
 	var (
 		start  = time.Now()
@@ -684,20 +707,26 @@ func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*
 			logged = time.Now()
 		}
 		// Retrieve the next block to regenerate and process it
-		if block = api.eth.blockchain.GetBlockByNumber(block.NumberU64() + 1); block == nil {
-			return nil, fmt.Errorf("block #%d not found", block.NumberU64()+1)
+		next := api.eth.blockchain.GetBlockByNumber(block.NumberU64() + 1)
+		if next == nil {
+			release()
+			return nil, nil, fmt.Errorf("block #%d not found", block.NumberU64()+1)
 		}
+		block = next
 		_, _, _, err := api.eth.blockchain.Processor().Process(block, statedb, vm.Config{})
 		if err != nil {
-			return nil, fmt.Errorf("processing block %d failed: %v", block.NumberU64(), err)
+			release()
+			return nil, nil, fmt.Errorf("processing block %d failed: %v", block.NumberU64(), err)
 		}
 		// Finalize the state so any modifications are written to the trie
 		root, err := statedb.Commit(true)
 		if err != nil {
-			return nil, err
+			release()
+			return nil, nil, err
 		}
 		if err := statedb.Reset(root); err != nil {
-			return nil, fmt.Errorf("state reset after block %d failed: %v", block.NumberU64(), err)
+			release()
+			return nil, nil, fmt.Errorf("state reset after block %d failed: %v", block.NumberU64(), err)
 		}
 		database.TrieDB().Reference(root, common.Hash{})
 		if proot != (common.Hash{}) {
@@ -707,7 +736,34 @@ func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*
 	}
 	nodes, imgs := database.TrieDB().Size()
 	log.Info("Historical state regenerated", "block", block.NumberU64(), "elapsed", time.Since(start), "nodes", nodes, "preimages", imgs)
-	return statedb, nil
+	return statedb, release, nil
+}
+
+// replayPPOS re-executes the blocks on top of the given one in a read-only
+// view of the PPOS data at that block, so the historical blocks don't see the
+// data committed after them. The returned function drops the view, it must be
+// called once the re-execution is done.
+func (api *PrivateDebugAPI) replayPPOS(block *types.Block) (func(), error) {
+	replay, err := snapshotdb.Instance().BeginReplay(block.Number(), block.Hash())
+	if err != nil {
+		if err == snapshotdb.ErrHistoryUnavailable {
+			return nil, fmt.Errorf("required historical PPOS data unavailable (block %d, only %d blocks below the snapshot base are retained)", block.NumberU64(), snapshotdb.HistoryRemain)
+		}
+		return nil, err
+	}
+	return replay.End, nil
+}
+
+// beginBlock applies the PPOS changes made before the transactions of the
+// block, the block is created in the view of the replay.
+func (api *PrivateDebugAPI) beginBlock(block *types.Block, statedb *state.StateDB) error {
+	if bcr := core.GetReactorInstance(); bcr != nil {
+		if err := bcr.BeginBlocker(block.Header(), statedb); err != nil {
+			return fmt.Errorf("begin block %d failed: %v", block.NumberU64(), err)
+		}
+		statedb.Finalise(true)
+	}
+	return nil
 }
 
 // TraceTransaction returns the structured logs created during the execution of EVM
@@ -727,10 +783,11 @@ func (api *PrivateDebugAPI) TraceTransaction(ctx context.Context, hash common.Ha
 	if block == nil {
 		return nil, fmt.Errorf("block %#x not found", blockHash)
 	}
-	msg, vmctx, statedb, err := api.computeTxEnv(block, int(index), reexec)
+	msg, vmctx, statedb, release, err := api.computeTxEnv(block, int(index), reexec)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 	// Trace the transaction and return
 	return api.traceTx(ctx, msg, vmctx, statedb, config)
 }
@@ -757,10 +814,12 @@ func (api *PrivateDebugAPI) TraceCall(ctx context.Context, args ethapi.CallArgs,
 		if config != nil && config.Reexec != nil {
 			reexec = *config.Reexec
 		}
-		_, _, statedb, err = api.computeTxEnv(block, 0, reexec)
+		var release func()
+		_, _, statedb, release, err = api.computeTxEnv(block, 0, reexec)
 		if err != nil {
 			return nil, err
 		}
+		defer release()
 	}
 
 	// Execute the trace
@@ -837,19 +896,23 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 }
 
 // computeTxEnv returns the execution environment of a certain transaction.
-func (api *PrivateDebugAPI) computeTxEnv(block *types.Block, txIndex int, reexec uint64) (core.Message, vm.BlockContext, *state.StateDB, error) {
+func (api *PrivateDebugAPI) computeTxEnv(block *types.Block, txIndex int, reexec uint64) (core.Message, vm.BlockContext, *state.StateDB, func(), error) {
 	// Create the parent state database
 	parent := api.eth.blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1)
 	if parent == nil {
-		return nil, vm.BlockContext{}, nil, fmt.Errorf("parent %#x not found", block.ParentHash())
+		return nil, vm.BlockContext{}, nil, nil, fmt.Errorf("parent %#x not found", block.ParentHash())
 	}
-	statedb, err := api.computeStateDB(parent, reexec)
+	statedb, release, err := api.computeStateDB(parent, reexec)
 	if err != nil {
-		return nil, vm.BlockContext{}, nil, err
+		return nil, vm.BlockContext{}, nil, nil, err
 	}
 
 	if txIndex == 0 && len(block.Transactions()) == 0 {
-		return nil, vm.BlockContext{}, statedb, nil
+		return nil, vm.BlockContext{}, statedb, release, nil
+	}
+	if err := api.beginBlock(block, statedb); err != nil {
+		release()
+		return nil, vm.BlockContext{}, nil, nil, err
 	}
 
 	// Recompute transactions up to the target index.
@@ -860,15 +923,17 @@ func (api *PrivateDebugAPI) computeTxEnv(block *types.Block, txIndex int, reexec
 		txContext := core.NewEVMTxContext(msg)
 		context := core.NewEVMBlockContext(block.Header(), api.eth.blockchain)
 		if idx == txIndex {
-			return msg, context, statedb, nil
+			return msg, context, statedb, release, nil
 		}
 		// Not yet the searched for transaction, execute on top of the current state
 		vmenv := vm.NewEVM(context, txContext, snapshotdb.Instance(), statedb, api.eth.blockchain.Config(), vm.Config{})
 		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(tx.Gas())); err != nil {
-			return nil, vm.BlockContext{}, nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
+			release()
+			return nil, vm.BlockContext{}, nil, nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
 		}
 		// Ensure any modifications are committed to the state
 		statedb.Finalise(true)
 	}
-	return nil, vm.BlockContext{}, nil, fmt.Errorf("transaction index %d out of range for block %#x", txIndex, block.Hash())
+	release()
+	return nil, vm.BlockContext{}, nil, nil, fmt.Errorf("transaction index %d out of range for block %#x", txIndex, block.Hash())
 }
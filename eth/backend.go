@@ -24,6 +24,7 @@ import (
 	"os"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/hashkey-chain/hashkey-chain/consensus/cbft/wal"
 
@@ -43,6 +44,7 @@ import (
 	"github.com/hashkey-chain/hashkey-chain/consensus/cbft/validator"
 	"github.com/hashkey-chain/hashkey-chain/core"
 	"github.com/hashkey-chain/hashkey-chain/core/bloombits"
+	"github.com/hashkey-chain/hashkey-chain/core/lru"
 	"github.com/hashkey-chain/hashkey-chain/core/rawdb"
 	"github.com/hashkey-chain/hashkey-chain/core/types"
 	"github.com/hashkey-chain/hashkey-chain/core/vm"
@@ -92,6 +94,8 @@ type Ethereum struct {
 
 	p2pServer *p2p.Server
 
+	wasmStore *lru.WasmModuleStore // Persisted compiled wasm modules, nil if disabled
+
 	lock sync.RWMutex // Protects the variadic fields (e.g. gas price and etherbase)
 }
 
@@ -235,6 +239,14 @@ func New(stack *node.Node, config *Config) (*Ethereum, error) {
 		}
 	}
 
+	if config.VMWasmCache > 0 {
+		lru.WasmCache().SetLimit(config.VMWasmCache * 1024 * 1024)
+	}
+	if config.VMWasmCacheStore {
+		if eth.wasmStore, err = vm.OpenWasmModuleStore(stack.ResolvePath(lru.DefaultWasmCacheDir)); err != nil {
+			return nil, err
+		}
+	}
 	var (
 		vmConfig = vm.Config{
 			ConsoleOutput: config.Debug,
@@ -248,6 +260,7 @@ func New(stack *node.Node, config *Config) (*Ethereum, error) {
 			TrieCleanRejournal: config.TrieCleanCacheRejournal,
 			DBGCInterval:       config.DBGCInterval, DBGCTimeout: config.DBGCTimeout,
 			DBGCMpt: config.DBGCMpt, DBGCBlock: config.DBGCBlock,
+			AddressIndex: config.AddressIndex,
 		}
 
 		minningConfig = &core.MiningConfig{MiningLogAtDepth: config.MiningLogAtDepth, TxChanSize: config.TxChanSize,
@@ -332,6 +345,11 @@ func New(stack *node.Node, config *Config) (*Ethereum, error) {
 			gov.RegisterGovernParamVerifiers()
 		}
 
+		// The block schedules governed by the committed blocks
+		if err := gov.LoadBlockSchedules(common.ZeroHash); err != nil {
+			log.Error("Failed to load the block schedules", "error", err)
+			return nil, err
+		}
 		if err := recoverSnapshotDB(blockChainCache); err != nil {
 			log.Error("recover SnapshotDB fail", "error", err)
 			return nil, errors.New("Failed to recover SnapshotDB")
@@ -369,7 +387,13 @@ func recoverSnapshotDB(blockChainCache *core.BlockChainCache) error {
 	ch := sdb.GetCurrent().GetHighest(false).Num.Uint64()
 	blockChanHegiht := blockChainCache.CurrentHeader().Number.Uint64()
 	if ch < blockChanHegiht {
+		log.Info("Replaying blocks into snapshotdb", "from", ch+1, "to", blockChanHegiht)
+		logged := time.Now()
 		for i := ch + 1; i <= blockChanHegiht; i++ {
+			if time.Since(logged) > 8*time.Second {
+				log.Info("Replaying blocks into snapshotdb", "number", i, "to", blockChanHegiht)
+				logged = time.Now()
+			}
 			block, parentBlock := blockChainCache.GetBlockByNumber(i), blockChainCache.GetBlockByNumber(i-1)
 			log.Debug("snapshotdb recover block from blockchain", "num", block.Number(), "hash", block.Hash())
 			if err := blockChainCache.Execute(block, parentBlock); err != nil {
@@ -380,6 +404,10 @@ func recoverSnapshotDB(blockChainCache *core.BlockChainCache) error {
 				log.Error("snapshotdb recover block from blockchain  Commit fail", "error", err)
 				return err
 			}
+			if err := gov.LoadBlockSchedules(block.Hash()); err != nil {
+				log.Error("snapshotdb recover block from blockchain  load block schedules fail", "error", err)
+				return err
+			}
 		}
 	}
 	return nil
@@ -438,6 +466,11 @@ func (s *Ethereum) APIs() []rpc.API {
 			Namespace: "debug",
 			Version:   "1.0",
 			Service:   xplugin.NewPublicPPOSAPI(),
+		}, {
+			Namespace: "ppos",
+			Version:   "1.0",
+			Service:   xplugin.NewPublicPPOSQueryAPI(s.APIBackend),
+			Public:    true,
 		}, {
 			Namespace: "net",
 			Version:   "1.0",
@@ -602,6 +635,9 @@ func (s *Ethereum) Stop() error {
 	s.blockchain.Stop()
 	s.engine.Close()
 	core.GetReactorInstance().Close()
+	if s.wasmStore != nil {
+		s.wasmStore.Close()
+	}
 	s.chainDb.Close()
 	s.eventMux.Stop()
 	return nil
@@ -61,6 +61,7 @@ var DefaultConfig = Config{
 	DBGCBlock:               10,
 	VMWasmType:              "wagon",
 	VmTimeoutDuration:       0, // default 0 ms for vm exec timeout
+	VMWasmCache:             64,
 	TrieCleanCacheJournal:   "triecache",
 	TrieCleanCacheRejournal: 60 * time.Minute,
 	Miner: miner.Config{
@@ -93,6 +94,8 @@ var DefaultConfig = Config{
 	RPCGasCap:   25000000,
 	GPO:         DefaultFullGPOConfig,
 	RPCTxFeeCap: 1, // 1 lat
+
+	RPCSimulateCap: 256,
 }
 
 //go:generate gencodec -type Config -formats toml -out gen_config.go
@@ -118,6 +121,7 @@ type Config struct {
 	DatabaseFreezer         string
 
 	TxLookupLimit uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
+	AddressIndex  bool   `toml:",omitempty"` // Whether to index the transactions by address, within the TxLookupLimit window.
 
 	TrieCache    int
 	TrieTimeout  time.Duration
@@ -132,6 +136,8 @@ type Config struct {
 	// VM options
 	VMWasmType        string
 	VmTimeoutDuration uint64
+	VMWasmCache       int  // Memory allowance (MB) for the compiled wasm modules
+	VMWasmCacheStore  bool // Persist the compiled wasm modules to warm the cache on restart
 
 	// Mining options
 	Miner miner.Config
@@ -179,4 +185,8 @@ type Config struct {
 	// RPCTxFeeCap is the global transaction fee(price * gaslimit) cap for
 	// send-transction variants. The unit is ether.
 	RPCTxFeeCap float64 `toml:",omitempty"`
+
+	// RPCSimulateCap is the most blocks run by a simulation, the empty blocks
+	// filled between the given ones included.
+	RPCSimulateCap uint64 `toml:",omitempty"`
 }
@@ -27,6 +27,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		TrieCleanCacheRejournal  time.Duration `toml:",omitempty"`
 		DatabaseFreezer          string
 		TxLookupLimit            uint64 `toml:",omitempty"`
+		AddressIndex             bool   `toml:",omitempty"`
 		TrieCache                int
 		TrieTimeout              time.Duration
 		TrieDBCache              int
@@ -38,6 +39,8 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		DBGCBlock                int
 		VMWasmType               string
 		VmTimeoutDuration        uint64
+		VMWasmCache              int
+		VMWasmCacheStore         bool
 		Miner                    miner.Config
 		MiningLogAtDepth         uint
 		TxChanSize               int
@@ -65,6 +68,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		Debug                    bool
 		RPCGasCap                uint64  `toml:",omitempty"`
 		RPCTxFeeCap              float64 `toml:",omitempty"`
+		RPCSimulateCap           uint64  `toml:",omitempty"`
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -79,6 +83,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.TrieCleanCacheRejournal = c.TrieCleanCacheRejournal
 	enc.DatabaseFreezer = c.DatabaseFreezer
 	enc.TxLookupLimit = c.TxLookupLimit
+	enc.AddressIndex = c.AddressIndex
 	enc.TrieCache = c.TrieCache
 	enc.TrieTimeout = c.TrieTimeout
 	enc.TrieDBCache = c.TrieDBCache
@@ -90,6 +95,8 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.DBGCBlock = c.DBGCBlock
 	enc.VMWasmType = c.VMWasmType
 	enc.VmTimeoutDuration = c.VmTimeoutDuration
+	enc.VMWasmCache = c.VMWasmCache
+	enc.VMWasmCacheStore = c.VMWasmCacheStore
 	enc.Miner = c.Miner
 	enc.MiningLogAtDepth = c.MiningLogAtDepth
 	enc.TxChanSize = c.TxChanSize
@@ -117,6 +124,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.Debug = c.Debug
 	enc.RPCGasCap = c.RPCGasCap
 	enc.RPCTxFeeCap = c.RPCTxFeeCap
+	enc.RPCSimulateCap = c.RPCSimulateCap
 	return &enc, nil
 }
 
@@ -135,6 +143,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		TrieCleanCacheRejournal  *time.Duration `toml:",omitempty"`
 		DatabaseFreezer          *string
 		TxLookupLimit            *uint64 `toml:",omitempty"`
+		AddressIndex             *bool   `toml:",omitempty"`
 		TrieCache                *int
 		TrieTimeout              *time.Duration
 		TrieDBCache              *int
@@ -146,6 +155,8 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		DBGCBlock                *int
 		VMWasmType               *string
 		VmTimeoutDuration        *uint64
+		VMWasmCache              *int
+		VMWasmCacheStore         *bool
 		Miner                    *miner.Config
 		MiningLogAtDepth         *uint
 		TxChanSize               *int
@@ -173,6 +184,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		Debug                    *bool
 		RPCGasCap                *uint64  `toml:",omitempty"`
 		RPCTxFeeCap              *float64 `toml:",omitempty"`
+		RPCSimulateCap           *uint64  `toml:",omitempty"`
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -214,6 +226,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.TxLookupLimit != nil {
 		c.TxLookupLimit = *dec.TxLookupLimit
 	}
+	if dec.AddressIndex != nil {
+		c.AddressIndex = *dec.AddressIndex
+	}
 	if dec.TrieCache != nil {
 		c.TrieCache = *dec.TrieCache
 	}
@@ -247,6 +262,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.VmTimeoutDuration != nil {
 		c.VmTimeoutDuration = *dec.VmTimeoutDuration
 	}
+	if dec.VMWasmCache != nil {
+		c.VMWasmCache = *dec.VMWasmCache
+	}
+	if dec.VMWasmCacheStore != nil {
+		c.VMWasmCacheStore = *dec.VMWasmCacheStore
+	}
 	if dec.Miner != nil {
 		c.Miner = *dec.Miner
 	}
@@ -328,5 +349,8 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.RPCTxFeeCap != nil {
 		c.RPCTxFeeCap = *dec.RPCTxFeeCap
 	}
+	if dec.RPCSimulateCap != nil {
+		c.RPCSimulateCap = *dec.RPCSimulateCap
+	}
 	return nil
 }
@@ -503,7 +503,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 			)
 			ps.KVs = make([]downloader.PPOSStorageKV, 0)
 			for iter.Next() {
-				if bytes.Equal(iter.Key(), []byte(snapshotdb.CurrentHighestBlock)) || bytes.Equal(iter.Key(), []byte(snapshotdb.CurrentBaseNum)) || bytes.HasPrefix(iter.Key(), []byte(snapshotdb.WalKeyPrefix)) {
+				if bytes.Equal(iter.Key(), []byte(snapshotdb.CurrentHighestBlock)) || bytes.Equal(iter.Key(), []byte(snapshotdb.CurrentBaseNum)) || bytes.HasPrefix(iter.Key(), []byte(snapshotdb.WalKeyPrefix)) || bytes.HasPrefix(iter.Key(), []byte(snapshotdb.HistoryKeyPrefix)) {
 					continue
 				}
 				byteSize = byteSize + len(iter.Key()) + len(iter.Value())
@@ -0,0 +1,166 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/x/gov"
+	"github.com/hashkey-chain/hashkey-chain/x/restricting"
+	"github.com/hashkey-chain/hashkey-chain/x/reward"
+	"github.com/hashkey-chain/hashkey-chain/x/slashing"
+	"github.com/hashkey-chain/hashkey-chain/x/staking"
+)
+
+// bizErrors maps the codes returned by the PPOS system contracts to the
+// business errors declared by the plugins, so that the callers can compare
+// the errors with the declared values.
+var bizErrors = make(map[uint32]*common.BizError)
+
+func init() {
+	for _, err := range []*common.BizError{
+		common.InternalError,
+		common.NotFound,
+		common.InvalidParameter,
+
+		// staking
+		staking.ErrWrongBlsPubKey,
+		staking.ErrWrongBlsPubKeyProof,
+		staking.ErrDescriptionLen,
+		staking.ErrWrongProgramVersionSign,
+		staking.ErrProgramVersionTooLow,
+		staking.ErrDeclVsFialedCreateCan,
+		staking.ErrNoSameStakingAddr,
+		staking.ErrInvalidRewardPer,
+		staking.ErrRewardPerInterval,
+		staking.ErrRewardPerChangeRange,
+		staking.ErrStakeVonTooLow,
+		staking.ErrCanAlreadyExist,
+		staking.ErrCanNoExist,
+		staking.ErrCanStatusInvalid,
+		staking.ErrIncreaseStakeVonTooLow,
+		staking.ErrDelegateVonTooLow,
+		staking.ErrAccountNoAllowToDelegate,
+		staking.ErrCanNoAllowDelegate,
+		staking.ErrWithdrewDelegationVonTooLow,
+		staking.ErrDelegateNoExist,
+		staking.ErrWrongVonOptType,
+		staking.ErrAccountVonNoEnough,
+		staking.ErrBlockNumberDisordered,
+		staking.ErrDelegateVonNoEnough,
+		staking.ErrWrongWithdrewDelVonCalc,
+		staking.ErrValidatorNoExist,
+		staking.ErrWrongFuncParams,
+		staking.ErrWrongSlashType,
+		staking.ErrSlashVonOverflow,
+		staking.ErrWrongSlashVonCalc,
+		staking.ErrNodeIdAlreadyUsed,
+		staking.ErrCanKeysNotChanged,
+		staking.ErrGetVerifierList,
+		staking.ErrGetValidatorList,
+		staking.ErrGetCandidateList,
+		staking.ErrGetDelegateRelated,
+		staking.ErrQueryCandidateInfo,
+		staking.ErrQueryDelegateInfo,
+		staking.ErrNodeID2Addr,
+		staking.ErrDelegateLockBalanceNotEnough,
+		staking.ErrQueryDelegationLockInfo,
+		staking.ErrQueryCommissionInfo,
+
+		// gov
+		gov.ActiveVersionError,
+		gov.VoteOptionError,
+		gov.ProposalTypeError,
+		gov.ProposalIDEmpty,
+		gov.ProposalIDExist,
+		gov.ProposalNotFound,
+		gov.PIPIDEmpty,
+		gov.PIPIDExist,
+		gov.EndVotingRoundsTooSmall,
+		gov.EndVotingRoundsTooLarge,
+		gov.NewVersionError,
+		gov.VotingVersionProposalExist,
+		gov.PreActiveVersionProposalExist,
+		gov.VotingCancelProposalExist,
+		gov.TobeCanceledProposalNotFound,
+		gov.TobeCanceledProposalTypeError,
+		gov.TobeCanceledProposalNotAtVoting,
+		gov.ProposerEmpty,
+		gov.VerifierInfoNotFound,
+		gov.VerifierStatusInvalid,
+		gov.TxSenderDifferFromStaking,
+		gov.TxSenderIsNotVerifier,
+		gov.TxSenderIsNotCandidate,
+		gov.VersionSignError,
+		gov.VerifierNotUpgraded,
+		gov.ProposalNotAtVoting,
+		gov.VoteDuplicated,
+		gov.DeclareVersionError,
+		gov.NotifyStakingDeclaredVersionError,
+		gov.TallyResultNotFound,
+		gov.UnsupportedGovernParam,
+		gov.VotingParamProposalExist,
+		gov.GovernParamValueError,
+		gov.ParamProposalIsSameValue,
+
+		// slashing
+		slashing.ErrDuplicateSignVerify,
+		slashing.ErrSlashingExist,
+		slashing.ErrBlockNumberTooHigh,
+		slashing.ErrIntervalTooLong,
+		slashing.ErrGetCandidate,
+		slashing.ErrAddrMismatch,
+		slashing.ErrNodeIdMismatch,
+		slashing.ErrBlsPubKeyMismatch,
+		slashing.ErrSlashingFail,
+		slashing.ErrNotValidator,
+		slashing.ErrSameAddr,
+
+		// restricting
+		restricting.ErrParamEpochInvalid,
+		restricting.ErrCountRestrictPlansInvalid,
+		restricting.ErrLockedAmountTooLess,
+		restricting.ErrBalanceNotEnough,
+		restricting.ErrAccountNotFound,
+		restricting.ErrSlashingTooMuch,
+		restricting.ErrStakingAmountEmpty,
+		restricting.ErrAdvanceLockedFundsAmountLessThanZero,
+		restricting.ErrReturnLockFundsAmountLessThanZero,
+		restricting.ErrSlashingAmountLessThanZero,
+		restricting.ErrCreatePlanAmountLessThanZero,
+		restricting.ErrStakingAmountInvalid,
+		restricting.ErrRestrictBalanceNotEnough,
+		restricting.ErrCreatePlanAmountLessThanMiniAmount,
+		restricting.ErrRestrictBalanceAndFreeNotEnough,
+
+		// reward
+		reward.ErrDelegationNotFound,
+	} {
+		bizErrors[err.Code] = err
+	}
+}
+
+// bizError returns the business error declared with the code, the message
+// is only used for the codes unknown to this client.
+func bizError(code uint32, msg string) *common.BizError {
+	if err, ok := bizErrors[code]; ok {
+		return err
+	}
+	if msg == "" {
+		msg = "Unknown business error"
+	}
+	return common.NewBizError(code, msg)
+}
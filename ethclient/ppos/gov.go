@@ -0,0 +1,183 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashkey-chain/hashkey-chain/accounts/abi/bind"
+	"github.com/hashkey-chain/hashkey-chain/common"
+	cvm "github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/core/vm"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+	"github.com/hashkey-chain/hashkey-chain/x/gov"
+)
+
+// AccuVerifiers is the accumulated verifiers of the proposal and their votes.
+type AccuVerifiers struct {
+	Total       uint64
+	Yeas        uint64
+	Nays        uint64
+	Abstentions uint64
+}
+
+// SubmitText submits the text proposal.
+func (c *Client) SubmitText(opts *bind.TransactOpts, verifier discover.NodeID, pipID string) (*types.Transaction, error) {
+	return c.transact(opts, cvm.GovContractAddr, vm.SubmitText, verifier, pipID)
+}
+
+// SubmitVersion submits the version proposal.
+func (c *Client) SubmitVersion(opts *bind.TransactOpts, verifier discover.NodeID, pipID string, newVersion uint32, endVotingRounds uint64) (*types.Transaction, error) {
+	return c.transact(opts, cvm.GovContractAddr, vm.SubmitVersion, verifier, pipID, newVersion, endVotingRounds)
+}
+
+// SubmitParam submits the proposal changing the govern parameter.
+func (c *Client) SubmitParam(opts *bind.TransactOpts, verifier discover.NodeID, pipID, module, name, newValue string) (*types.Transaction, error) {
+	return c.transact(opts, cvm.GovContractAddr, vm.SubmitParam, verifier, pipID, module, name, newValue)
+}
+
+// SubmitCancel submits the proposal canceling the version proposal.
+func (c *Client) SubmitCancel(opts *bind.TransactOpts, verifier discover.NodeID, pipID string, endVotingRounds uint64, tobeCanceled common.Hash) (*types.Transaction, error) {
+	return c.transact(opts, cvm.GovContractAddr, vm.SubmitCancel, verifier, pipID, endVotingRounds, tobeCanceled)
+}
+
+// SubmitAllowlist submits the proposal adding the node into the allowlist or
+// removing it.
+func (c *Client) SubmitAllowlist(opts *bind.TransactOpts, verifier discover.NodeID, pipID string, operation gov.AllowlistOperation,
+	nodeId discover.NodeID, stakingAddress common.Address) (*types.Transaction, error) {
+	return c.transact(opts, cvm.GovContractAddr, vm.SubmitAllowlist, verifier, pipID, uint8(operation), nodeId, stakingAddress)
+}
+
+// Vote votes the proposal on behalf of the verifier.
+func (c *Client) Vote(opts *bind.TransactOpts, verifier discover.NodeID, proposalID common.Hash, option gov.VoteOption,
+	programVersion uint32, programVersionSign common.VersionSign) (*types.Transaction, error) {
+	return c.transact(opts, cvm.GovContractAddr, vm.Vote, verifier, proposalID, uint8(option), programVersion, programVersionSign)
+}
+
+// DeclareVersion declares the program version of the node.
+func (c *Client) DeclareVersion(opts *bind.TransactOpts, activeNode discover.NodeID, programVersion uint32, programVersionSign common.VersionSign) (*types.Transaction, error) {
+	return c.transact(opts, cvm.GovContractAddr, vm.Declare, activeNode, programVersion, programVersionSign)
+}
+
+// GetProposal returns the proposal, the concrete type is decided by its
+// proposal type.
+func (c *Client) GetProposal(opts *bind.CallOpts, proposalID common.Hash) (gov.Proposal, error) {
+	var raw json.RawMessage
+	if err := c.call(opts, cvm.GovContractAddr, &raw, vm.GetProposal, proposalID); err != nil {
+		return nil, err
+	}
+	return decodeProposal(raw)
+}
+
+// GetTallyResult returns the tally result of the proposal.
+func (c *Client) GetTallyResult(opts *bind.CallOpts, proposalID common.Hash) (*gov.TallyResult, error) {
+	var result gov.TallyResult
+	if err := c.call(opts, cvm.GovContractAddr, &result, vm.GetResult, proposalID); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListProposal returns all the proposals.
+func (c *Client) ListProposal(opts *bind.CallOpts) ([]gov.Proposal, error) {
+	var raws []json.RawMessage
+	if err := c.call(opts, cvm.GovContractAddr, &raws, vm.ListProposal); err != nil {
+		return nil, err
+	}
+	proposals := make([]gov.Proposal, 0, len(raws))
+	for _, raw := range raws {
+		p, err := decodeProposal(raw)
+		if err != nil {
+			return nil, err
+		}
+		proposals = append(proposals, p)
+	}
+	return proposals, nil
+}
+
+// GetActiveVersion returns the active version of the chain.
+func (c *Client) GetActiveVersion(opts *bind.CallOpts) (uint32, error) {
+	var version uint32
+	err := c.call(opts, cvm.GovContractAddr, &version, vm.GetActiveVersion)
+	return version, err
+}
+
+// GetGovernParamValue returns the current value of the govern parameter.
+func (c *Client) GetGovernParamValue(opts *bind.CallOpts, module, name string) (string, error) {
+	var value string
+	err := c.call(opts, cvm.GovContractAddr, &value, vm.GetGovernParamValue, module, name)
+	return value, err
+}
+
+// GetAccuVerifiersCount returns the accumulated verifiers of the proposal at
+// the block.
+func (c *Client) GetAccuVerifiersCount(opts *bind.CallOpts, proposalID, blockHash common.Hash) (*AccuVerifiers, error) {
+	var counts []uint64
+	if err := c.call(opts, cvm.GovContractAddr, &counts, vm.GetAccuVerifiersCount, proposalID, blockHash); err != nil {
+		return nil, err
+	}
+	if len(counts) != 4 {
+		return nil, fmt.Errorf("invalid accumulated verifiers: %v", counts)
+	}
+	return &AccuVerifiers{Total: counts[0], Yeas: counts[1], Nays: counts[2], Abstentions: counts[3]}, nil
+}
+
+// ListGovernParam returns the govern parameters of the module, all of them
+// if the module is empty.
+func (c *Client) ListGovernParam(opts *bind.CallOpts, module string) ([]*gov.GovernParam, error) {
+	var params []*gov.GovernParam
+	err := c.call(opts, cvm.GovContractAddr, &params, vm.ListGovernParam, module)
+	return params, err
+}
+
+// ListAllowlist returns the nodes admitted to stake.
+func (c *Client) ListAllowlist(opts *bind.CallOpts) ([]*gov.AllowlistEntry, error) {
+	var entries []*gov.AllowlistEntry
+	err := c.call(opts, cvm.GovContractAddr, &entries, vm.ListAllowlist)
+	return entries, err
+}
+
+// decodeProposal decodes the JSON proposal by its proposal type.
+func decodeProposal(raw json.RawMessage) (gov.Proposal, error) {
+	var typ struct {
+		ProposalType gov.ProposalType
+	}
+	if err := json.Unmarshal(raw, &typ); err != nil {
+		return nil, err
+	}
+	var p gov.Proposal
+	switch typ.ProposalType {
+	case gov.Text:
+		p = new(gov.TextProposal)
+	case gov.Version:
+		p = new(gov.VersionProposal)
+	case gov.Param:
+		p = new(gov.ParamProposal)
+	case gov.Cancel:
+		p = new(gov.CancelProposal)
+	case gov.Allowlist:
+		p = new(gov.AllowlistProposal)
+	default:
+		return nil, fmt.Errorf("unknown proposal type: %d", typ.ProposalType)
+	}
+	if err := json.Unmarshal(raw, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package ppos provides a typed client for the PPOS system contracts: the
+// staking, governance, slashing, restricting and delegate reward contracts.
+//
+// The transactions are signed and sent through the bind.TransactOpts, the
+// business error of a mined transaction is read from its receipt by TxResult.
+// The queries decode the results into the types of the plugins and return the
+// business errors declared by the plugins.
+package ppos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	platon "github.com/hashkey-chain/hashkey-chain"
+	"github.com/hashkey-chain/hashkey-chain/accounts/abi"
+	"github.com/hashkey-chain/hashkey-chain/accounts/abi/bind"
+	"github.com/hashkey-chain/hashkey-chain/common"
+	cvm "github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/rlp"
+)
+
+var errNoResult = errors.New("no result of the PPOS contract in the receipt")
+
+// Backend wraps the functionality needed by the PPOS client, it is
+// implemented by both ethclient.Client and backends.SimulatedBackend.
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// Client is a typed client of the PPOS system contracts.
+type Client struct {
+	backend Backend
+}
+
+// NewClient creates a client of the PPOS system contracts on the backend.
+func NewClient(backend Backend) *Client {
+	return &Client{backend: backend}
+}
+
+// encodeInput encodes the input of the PPOS function, it is the RLP list of
+// the function code and the RLP encoded params. A nil pointer param is
+// encoded as an empty item, which the contracts decode as an absent value.
+func encodeInput(fnCode uint16, params ...interface{}) ([]byte, error) {
+	items := make([][]byte, 0, len(params)+1)
+	code, _ := rlp.EncodeToBytes(fnCode)
+	items = append(items, code)
+	for i, param := range params {
+		if v := reflect.ValueOf(param); v.Kind() == reflect.Ptr && v.IsNil() {
+			items = append(items, []byte{})
+			continue
+		}
+		item, err := rlp.EncodeToBytes(param)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode param %d of function %d: %v", i, fnCode, err)
+		}
+		items = append(items, item)
+	}
+	return rlp.EncodeToBytes(items)
+}
+
+// transact signs and sends the transaction calling the PPOS function. The
+// gas is estimated here since the system contracts have no code, which the
+// bound contracts require for the estimation. A transaction failing with a
+// business error in the estimation is not sent.
+func (c *Client) transact(opts *bind.TransactOpts, contract common.Address, fnCode uint16, params ...interface{}) (*types.Transaction, error) {
+	input, err := encodeInput(fnCode, params...)
+	if err != nil {
+		return nil, err
+	}
+	if opts.GasLimit == 0 {
+		ctx := opts.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		msg := platon.CallMsg{From: opts.From, To: &contract, GasPrice: opts.GasPrice, Value: opts.Value, Data: input}
+		gas, err := c.backend.EstimateGas(ctx, msg)
+		if be, ok := err.(*common.BizError); ok {
+			return nil, bizError(be.Code, be.Msg)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate gas needed: %v", err)
+		}
+		estimated := *opts
+		estimated.GasLimit = gas
+		opts = &estimated
+	}
+	return bind.NewBoundContract(contract, abi.ABI{}, c.backend, c.backend, c.backend).RawTransact(opts, input)
+}
+
+// callResult is the JSON result of the PPOS queries, the same as xcom.Result.
+type callResult struct {
+	Code uint32
+	Ret  json.RawMessage
+}
+
+// call queries the PPOS function and decodes the result into out.
+func (c *Client) call(opts *bind.CallOpts, contract common.Address, out interface{}, fnCode uint16, params ...interface{}) error {
+	if opts == nil {
+		opts = new(bind.CallOpts)
+	}
+	input, err := encodeInput(fnCode, params...)
+	if err != nil {
+		return err
+	}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	msg := platon.CallMsg{From: opts.From, To: &contract, Data: input}
+
+	var output []byte
+	if opts.Pending {
+		pb, ok := c.backend.(bind.PendingContractCaller)
+		if !ok {
+			return bind.ErrNoPendingState
+		}
+		output, err = pb.PendingCallContract(ctx, msg)
+	} else {
+		output, err = c.backend.CallContract(ctx, msg, opts.BlockNumber)
+	}
+	if err != nil {
+		return err
+	}
+	return decodeResult(output, out)
+}
+
+// decodeResult decodes the JSON result of the PPOS queries.
+func decodeResult(output []byte, out interface{}) error {
+	var res callResult
+	if err := json.Unmarshal(output, &res); err != nil {
+		return fmt.Errorf("invalid result of the PPOS contract: %v", err)
+	}
+	if res.Code != common.OkCode {
+		var msg string
+		json.Unmarshal(res.Ret, &msg)
+		return bizError(res.Code, msg)
+	}
+	return json.Unmarshal(res.Ret, out)
+}
+
+// TxResult returns the business error of the mined PPOS transaction, nil if
+// the transaction succeeded. The extra results of the transaction are
+// returned in the order they were logged, each is RLP encoded.
+func TxResult(receipt *types.Receipt) ([][]byte, error) {
+	for _, log := range receipt.Logs {
+		if !isPposContract(log.Address) {
+			continue
+		}
+		var items [][]byte
+		if err := rlp.DecodeBytes(log.Data, &items); err != nil || len(items) == 0 {
+			return nil, fmt.Errorf("invalid result of the PPOS contract: %v", err)
+		}
+		code, err := strconv.ParseUint(string(items[0]), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid result code of the PPOS contract: %v", err)
+		}
+		if uint32(code) != common.OkCode {
+			return nil, bizError(uint32(code), "")
+		}
+		return items[1:], nil
+	}
+	if receipt.Status == types.ReceiptStatusFailed {
+		return nil, errors.New("PPOS transaction failed")
+	}
+	return nil, errNoResult
+}
+
+// isPposContract reports whether the address is one of the PPOS contracts
+// sending the transactions.
+func isPposContract(addr common.Address) bool {
+	switch addr {
+	case cvm.StakingContractAddr, cvm.GovContractAddr, cvm.SlashingContractAddr,
+		cvm.RestrictingContractAddr, cvm.DelegateRewardPoolAddr, cvm.ScheduledTxContractAddr:
+		return true
+	}
+	return false
+}
+
+// WaitTxResult waits for the PPOS transaction to be mined and returns its
+// business error, nil if the transaction succeeded.
+func (c *Client) WaitTxResult(ctx context.Context, tx *types.Transaction) error {
+	receipt, err := bind.WaitMined(ctx, c.backend, tx)
+	if err != nil {
+		return err
+	}
+	_, err = TxResult(receipt)
+	return err
+}
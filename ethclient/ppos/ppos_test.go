@@ -0,0 +1,174 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/hashkey-chain/hashkey-chain/accounts/abi/bind"
+	"github.com/hashkey-chain/hashkey-chain/accounts/abi/bind/backends"
+	"github.com/hashkey-chain/hashkey-chain/common"
+	cvm "github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/core/vm"
+	"github.com/hashkey-chain/hashkey-chain/crypto"
+	"github.com/hashkey-chain/hashkey-chain/crypto/bls"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+	"github.com/hashkey-chain/hashkey-chain/rlp"
+	"github.com/hashkey-chain/hashkey-chain/x/plugin"
+	"github.com/hashkey-chain/hashkey-chain/x/restricting"
+	"github.com/hashkey-chain/hashkey-chain/x/reward"
+	"github.com/hashkey-chain/hashkey-chain/x/staking"
+)
+
+func TestEncodeInput(t *testing.T) {
+	nodeId := discover.MustHexID("0x362003c50ed3a523cdede37a001803b8f0fed27cb402b3d6127a1a96661ec202318f68f4c76d9b0bfbabfd551a178d4335eaeaa9b7981a4df30dfc8c0bfe3384")
+	rewardPer := uint16(500)
+
+	input, err := encodeInput(vm.TxEditorCandidate, (*common.Address)(nil), nodeId, &rewardPer, (*string)(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var items [][]byte
+	if err := rlp.DecodeBytes(input, &items); err != nil {
+		t.Fatal(err)
+	}
+	code, _ := rlp.EncodeToBytes(uint16(vm.TxEditorCandidate))
+	node, _ := rlp.EncodeToBytes(nodeId)
+	per, _ := rlp.EncodeToBytes(rewardPer)
+	want := [][]byte{code, {}, node, per, {}}
+	if len(items) != len(want) {
+		t.Fatalf("items mismatch: have %d, want %d", len(items), len(want))
+	}
+	for i := range want {
+		if !bytes.Equal(items[i], want[i]) {
+			t.Errorf("item %d mismatch: have %x, want %x", i, items[i], want[i])
+		}
+	}
+}
+
+func TestDecodeResult(t *testing.T) {
+	var version uint32
+	if err := decodeResult([]byte(`{"Code":0,"Ret":65536}`), &version); err != nil || version != 65536 {
+		t.Errorf("result mismatch: have %d (%v), want 65536", version, err)
+	}
+	// The declared business errors are returned as is
+	err := decodeResult([]byte(`{"Code":301204,"Ret":"Query candidate info failed:not found"}`), &version)
+	if err != staking.ErrQueryCandidateInfo {
+		t.Errorf("error mismatch: have %v, want %v", err, staking.ErrQueryCandidateInfo)
+	}
+	err = decodeResult([]byte(`{"Code":399999,"Ret":"something wrong"}`), &version)
+	if be, ok := err.(*common.BizError); !ok || be.Code != 399999 || be.Msg != "something wrong" {
+		t.Errorf("unknown error mismatch: %v", err)
+	}
+}
+
+func TestTxResult(t *testing.T) {
+	rewards := []reward.NodeDelegateReward{{StakingNum: 10, Reward: big.NewInt(100)}}
+	extra, _ := rlp.EncodeToBytes(rewards)
+	data, _ := rlp.EncodeToBytes([][]byte{[]byte("0"), extra})
+	receipt := &types.Receipt{
+		Status: types.ReceiptStatusSuccessful,
+		Logs:   []*types.Log{{Address: cvm.DelegateRewardPoolAddr, Data: data}},
+	}
+	have, err := WithdrawnDelegateReward(receipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(have) != 1 || have[0].StakingNum != 10 || have[0].Reward.Cmp(big.NewInt(100)) != 0 {
+		t.Errorf("rewards mismatch: %+v", have)
+	}
+
+	data, _ = rlp.EncodeToBytes([][]byte{[]byte("305001")})
+	receipt.Logs[0].Data = data
+	if _, err := TxResult(receipt); err != reward.ErrDelegationNotFound {
+		t.Errorf("error mismatch: have %v, want %v", err, reward.ErrDelegationNotFound)
+	}
+	receipt.Logs = nil
+	if _, err := TxResult(receipt); err != errNoResult {
+		t.Errorf("error mismatch: have %v, want %v", err, errNoResult)
+	}
+}
+
+func TestSimulatedBackend(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	auth, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{auth.From: {Balance: big.NewInt(1e18)}}, 10000000)
+	defer sim.Close()
+
+	client := NewClient(sim)
+	if _, err := client.GetRestrictingInfo(nil, auth.From); err != restricting.ErrAccountNotFound {
+		t.Fatalf("error mismatch: have %v, want %v", err, restricting.ErrAccountNotFound)
+	}
+
+	// The business error is returned by the gas estimation
+	if _, err := client.WithdrawDelegateReward(auth); err != reward.ErrDelegationNotFound {
+		t.Fatalf("error mismatch: have %v, want %v", err, reward.ErrDelegationNotFound)
+	}
+	// and from the receipt of the transaction sent without the estimation
+	auth.GasLimit = 100000
+	tx, err := client.WithdrawDelegateReward(auth)
+	if err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	sim.Commit()
+	if err := client.WaitTxResult(context.Background(), tx); err != reward.ErrDelegationNotFound {
+		t.Fatalf("error mismatch: have %v, want %v", err, reward.ErrDelegationNotFound)
+	}
+}
+
+func TestRotateCandidateKey(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	auth, _ := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{auth.From: {Balance: big.NewInt(1e18)}}, 10000000)
+	defer sim.Close()
+
+	params := &RotateCandidateKeyParams{
+		NodeId:    discover.MustHexID("0x362003c50ed3a523cdede37a001803b8f0fed27cb402b3d6127a1a96661ec202318f68f4c76d9b0bfbabfd551a178d4335eaeaa9b7981a4df30dfc8c0bfe3384"),
+		NewNodeId: discover.MustHexID("0xced880d4769331f47af07a8d1b79de1e40c95a37ea1890bb9d3f0da8349e1a7c0ea4cadbb9c5bf185b051061eef8e5eadca251c24e1db1d9faf0fae046686a8d"),
+		BlsPubKey: bls.PublicKeyHex{1, 2, 3},
+		BlsProof:  bls.SchnorrProofHex{4, 5, 6},
+	}
+	params.NodeIdSign[0] = 7
+
+	// The input sent by the client is decoded by the staking contract
+	auth.GasLimit = 100000
+	tx, err := NewClient(sim).RotateCandidateKey(auth, params)
+	if err != nil {
+		t.Fatalf("failed to send transaction: %v", err)
+	}
+	fnCode, _, values, err := plugin.VerifyTxData(tx.Data(), (&vm.StakingContract{}).FnSigns())
+	if err != nil {
+		t.Fatalf("failed to decode the input: %v", err)
+	}
+	if fnCode != vm.TxRotateCandidateKey {
+		t.Fatalf("function mismatch: have %d, want %d", fnCode, vm.TxRotateCandidateKey)
+	}
+	want := []interface{}{params.NodeId, params.NewNodeId, params.NodeIdSign, params.BlsPubKey, params.BlsProof}
+	if len(values) != len(want) {
+		t.Fatalf("params mismatch: have %d, want %d", len(values), len(want))
+	}
+	for i := range want {
+		if have := values[i].Interface(); have != want[i] {
+			t.Errorf("param %d mismatch: have %v, want %v", i, have, want[i])
+		}
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"github.com/hashkey-chain/hashkey-chain/accounts/abi/bind"
+	"github.com/hashkey-chain/hashkey-chain/common"
+	cvm "github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/core/vm"
+	"github.com/hashkey-chain/hashkey-chain/x/restricting"
+)
+
+// CreateRestrictingPlan creates the restricting plans releasing to the account.
+func (c *Client) CreateRestrictingPlan(opts *bind.TransactOpts, account common.Address, plans []restricting.RestrictingPlan) (*types.Transaction, error) {
+	return c.transact(opts, cvm.RestrictingContractAddr, vm.TxCreateRestrictingPlan, account, plans)
+}
+
+// GetRestrictingInfo returns the restricting information of the account.
+func (c *Client) GetRestrictingInfo(opts *bind.CallOpts, account common.Address) (*restricting.Result, error) {
+	var result restricting.Result
+	if err := c.call(opts, cvm.RestrictingContractAddr, &result, vm.QueryRestrictingInfo, account); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"github.com/hashkey-chain/hashkey-chain/accounts/abi/bind"
+	"github.com/hashkey-chain/hashkey-chain/common"
+	cvm "github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/core/vm"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+	"github.com/hashkey-chain/hashkey-chain/rlp"
+	"github.com/hashkey-chain/hashkey-chain/x/reward"
+)
+
+// WithdrawDelegateReward withdraws the delegate rewards of the sender, the
+// withdrawn rewards are read from the receipt by WithdrawnDelegateReward.
+func (c *Client) WithdrawDelegateReward(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return c.transact(opts, cvm.DelegateRewardPoolAddr, vm.TxWithdrawDelegateReward)
+}
+
+// WithdrawnDelegateReward returns the delegate rewards withdrawn by the mined
+// transaction, or its business error.
+func WithdrawnDelegateReward(receipt *types.Receipt) ([]reward.NodeDelegateReward, error) {
+	extra, err := TxResult(receipt)
+	if err != nil {
+		return nil, err
+	}
+	if len(extra) == 0 {
+		return nil, errNoResult
+	}
+	var rewards []reward.NodeDelegateReward
+	if err := rlp.DecodeBytes(extra[0], &rewards); err != nil {
+		return nil, err
+	}
+	return rewards, nil
+}
+
+// GetDelegateReward returns the delegate rewards of the address not withdrawn
+// yet, of the given nodes or of all the delegated nodes if none is given.
+func (c *Client) GetDelegateReward(opts *bind.CallOpts, address common.Address, nodeIDs []discover.NodeID) ([]reward.NodeDelegateRewardPresenter, error) {
+	if nodeIDs == nil {
+		nodeIDs = []discover.NodeID{}
+	}
+	var rewards []reward.NodeDelegateRewardPresenter
+	err := c.call(opts, cvm.DelegateRewardPoolAddr, &rewards, vm.QueryDelegateReward, address, nodeIDs)
+	return rewards, err
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"github.com/hashkey-chain/hashkey-chain/accounts/abi/bind"
+	"github.com/hashkey-chain/hashkey-chain/common"
+	cvm "github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/core/vm"
+	"github.com/hashkey-chain/hashkey-chain/rlp"
+	"github.com/hashkey-chain/hashkey-chain/x/scheduled"
+)
+
+// ScheduleTx schedules the signed transaction at the target block or epoch,
+// the sender pays its gas in advance. The transaction is signed with the
+// types.MakeScheduledSigner, the pool rejects it so it can't be replayed. The id
+// of the scheduled transaction is read from the receipt by ScheduledTxID.
+func (c *Client) ScheduleTx(opts *bind.TransactOpts, tx *types.Transaction, targetType uint8, target uint64) (*types.Transaction, error) {
+	encoded, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	return c.transact(opts, cvm.ScheduledTxContractAddr, vm.TxScheduleTx, encoded, targetType, target)
+}
+
+// ScheduledTxID returns the id of the transaction scheduled by the mined
+// transaction, or its business error.
+func ScheduledTxID(receipt *types.Receipt) (uint64, error) {
+	extra, err := TxResult(receipt)
+	if err != nil {
+		return 0, err
+	}
+	if len(extra) == 0 {
+		return 0, errNoResult
+	}
+	var id uint64
+	if err := rlp.DecodeBytes(extra[0], &id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// CancelScheduledTx cancels the pending scheduled transaction and refunds its
+// escrowed gas to the submitter.
+func (c *Client) CancelScheduledTx(opts *bind.TransactOpts, id uint64) (*types.Transaction, error) {
+	return c.transact(opts, cvm.ScheduledTxContractAddr, vm.TxCancelScheduledTx, id)
+}
+
+// GetScheduledTx returns the scheduled transaction of the id.
+func (c *Client) GetScheduledTx(opts *bind.CallOpts, id uint64) (*scheduled.ScheduledTx, error) {
+	var stx scheduled.ScheduledTx
+	if err := c.call(opts, cvm.ScheduledTxContractAddr, &stx, vm.GetScheduledTx, id); err != nil {
+		return nil, err
+	}
+	return &stx, nil
+}
+
+// GetScheduledTxsByAccount returns the pending transactions scheduled by the
+// account.
+func (c *Client) GetScheduledTxsByAccount(opts *bind.CallOpts, account common.Address) ([]*scheduled.ScheduledTx, error) {
+	var list []*scheduled.ScheduledTx
+	err := c.call(opts, cvm.ScheduledTxContractAddr, &list, vm.GetScheduledTxsByAccount, account)
+	return list, err
+}
@@ -0,0 +1,46 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"github.com/hashkey-chain/hashkey-chain/accounts/abi/bind"
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/common/consensus"
+	cvm "github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/core/vm"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+)
+
+// ReportDuplicateSign reports the duplicate signing evidence in JSON.
+func (c *Client) ReportDuplicateSign(opts *bind.TransactOpts, dupType consensus.EvidenceType, data string) (*types.Transaction, error) {
+	return c.transact(opts, cvm.SlashingContractAddr, vm.TxReportDuplicateSign, uint8(dupType), data)
+}
+
+// CheckDuplicateSign returns the hash of the transaction reporting the
+// duplicate signing of the node at the block, the zero hash if not reported.
+func (c *Client) CheckDuplicateSign(opts *bind.CallOpts, dupType consensus.EvidenceType, nodeId discover.NodeID, blockNumber uint64) (common.Hash, error) {
+	var txHash string
+	err := c.call(opts, cvm.SlashingContractAddr, &txHash, vm.CheckDuplicateSign, uint8(dupType), nodeId, blockNumber)
+	if err == common.NotFound {
+		return common.Hash{}, nil
+	}
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.HexToHash(txHash), nil
+}
@@ -0,0 +1,212 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"math/big"
+
+	"github.com/hashkey-chain/hashkey-chain/accounts/abi/bind"
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/common/hexutil"
+	cvm "github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/core/vm"
+	"github.com/hashkey-chain/hashkey-chain/crypto/bls"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+	"github.com/hashkey-chain/hashkey-chain/x/staking"
+)
+
+// CreateStakingParams is the params of the staking creation.
+type CreateStakingParams struct {
+	Typ                uint16 // 0: free balance, 1: restricting plan, 2: both of them
+	BenefitAddress     common.Address
+	NodeId             discover.NodeID
+	ExternalId         string
+	NodeName           string
+	Website            string
+	Details            string
+	Amount             *big.Int
+	RewardPer          uint16 // the delegate reward ratio in basis points
+	ProgramVersion     uint32
+	ProgramVersionSign common.VersionSign
+	BlsPubKey          bls.PublicKeyHex
+	BlsProof           bls.SchnorrProofHex
+}
+
+// EditCandidateParams is the params of the candidate edition, the nil fields
+// are kept unchanged.
+type EditCandidateParams struct {
+	BenefitAddress *common.Address
+	NodeId         discover.NodeID
+	RewardPer      *uint16
+	ExternalId     *string
+	NodeName       *string
+	Website        *string
+	Details        *string
+}
+
+// RotateCandidateKeyParams is the params of the candidate key rotation.
+type RotateCandidateKeyParams struct {
+	NodeId     discover.NodeID
+	NewNodeId  discover.NodeID
+	NodeIdSign common.VersionSign // the sign of the staking.RotateKeyProof by the new node key
+	BlsPubKey  bls.PublicKeyHex
+	BlsProof   bls.SchnorrProofHex
+}
+
+// CreateStaking stakes the node to be a candidate.
+func (c *Client) CreateStaking(opts *bind.TransactOpts, params *CreateStakingParams) (*types.Transaction, error) {
+	return c.transact(opts, cvm.StakingContractAddr, vm.TxCreateStaking, params.Typ, params.BenefitAddress, params.NodeId,
+		params.ExternalId, params.NodeName, params.Website, params.Details, params.Amount, params.RewardPer,
+		params.ProgramVersion, params.ProgramVersionSign, params.BlsPubKey, params.BlsProof)
+}
+
+// EditCandidate edits the information of the candidate.
+func (c *Client) EditCandidate(opts *bind.TransactOpts, params *EditCandidateParams) (*types.Transaction, error) {
+	return c.transact(opts, cvm.StakingContractAddr, vm.TxEditorCandidate, params.BenefitAddress, params.NodeId,
+		params.RewardPer, params.ExternalId, params.NodeName, params.Website, params.Details)
+}
+
+// IncreaseStaking increases the staking amount of the candidate.
+func (c *Client) IncreaseStaking(opts *bind.TransactOpts, nodeId discover.NodeID, typ uint16, amount *big.Int) (*types.Transaction, error) {
+	return c.transact(opts, cvm.StakingContractAddr, vm.TxIncreaseStaking, nodeId, typ, amount)
+}
+
+// WithdrewStaking withdraws the staking of the candidate.
+func (c *Client) WithdrewStaking(opts *bind.TransactOpts, nodeId discover.NodeID) (*types.Transaction, error) {
+	return c.transact(opts, cvm.StakingContractAddr, vm.TxWithdrewCandidate, nodeId)
+}
+
+// Delegate delegates the amount to the candidate.
+func (c *Client) Delegate(opts *bind.TransactOpts, typ uint16, nodeId discover.NodeID, amount *big.Int) (*types.Transaction, error) {
+	return c.transact(opts, cvm.StakingContractAddr, vm.TxDelegate, typ, nodeId, amount)
+}
+
+// WithdrewDelegation withdraws the amount from the delegation of the
+// candidate staked at the block.
+func (c *Client) WithdrewDelegation(opts *bind.TransactOpts, stakingBlockNum uint64, nodeId discover.NodeID, amount *big.Int) (*types.Transaction, error) {
+	return c.transact(opts, cvm.StakingContractAddr, vm.TxWithdrewDelegation, stakingBlockNum, nodeId, amount)
+}
+
+// RedeemDelegation redeems the unlocked delegations of the sender.
+func (c *Client) RedeemDelegation(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return c.transact(opts, cvm.StakingContractAddr, vm.TxRedeemDelegation)
+}
+
+// RotateCandidateKey rotates the node key of the candidate.
+func (c *Client) RotateCandidateKey(opts *bind.TransactOpts, params *RotateCandidateKeyParams) (*types.Transaction, error) {
+	return c.transact(opts, cvm.StakingContractAddr, vm.TxRotateCandidateKey, params.NodeId, params.NewNodeId,
+		params.NodeIdSign, params.BlsPubKey, params.BlsProof)
+}
+
+// GetVerifierList returns the verifiers of the current settlement epoch.
+func (c *Client) GetVerifierList(opts *bind.CallOpts) (staking.ValidatorExQueue, error) {
+	var list staking.ValidatorExQueue
+	err := c.call(opts, cvm.StakingContractAddr, &list, vm.QueryVerifierList)
+	return list, err
+}
+
+// GetValidatorList returns the validators of the current consensus round.
+func (c *Client) GetValidatorList(opts *bind.CallOpts) (staking.ValidatorExQueue, error) {
+	var list staking.ValidatorExQueue
+	err := c.call(opts, cvm.StakingContractAddr, &list, vm.QueryValidatorList)
+	return list, err
+}
+
+// GetCandidateList returns all the candidates.
+func (c *Client) GetCandidateList(opts *bind.CallOpts) (staking.CandidateHexQueue, error) {
+	var list staking.CandidateHexQueue
+	err := c.call(opts, cvm.StakingContractAddr, &list, vm.QueryCandidateList)
+	return list, err
+}
+
+// GetRelatedListByDelAddr returns the candidates delegated by the address.
+func (c *Client) GetRelatedListByDelAddr(opts *bind.CallOpts, addr common.Address) (staking.DelRelatedQueue, error) {
+	var list staking.DelRelatedQueue
+	err := c.call(opts, cvm.StakingContractAddr, &list, vm.QueryRelateList, addr)
+	return list, err
+}
+
+// GetDelegateInfo returns the delegation of the address to the candidate
+// staked at the block.
+func (c *Client) GetDelegateInfo(opts *bind.CallOpts, stakingBlockNum uint64, delAddr common.Address, nodeId discover.NodeID) (*staking.DelegationEx, error) {
+	var del staking.DelegationEx
+	if err := c.call(opts, cvm.StakingContractAddr, &del, vm.QueryDelegateInfo, stakingBlockNum, delAddr, nodeId); err != nil {
+		return nil, err
+	}
+	return &del, nil
+}
+
+// GetDelegateLock returns the locked delegations of the address.
+func (c *Client) GetDelegateLock(opts *bind.CallOpts, delAddr common.Address) (*staking.DelegationLockHex, error) {
+	var lock staking.DelegationLockHex
+	if err := c.call(opts, cvm.StakingContractAddr, &lock, vm.QueryDelegationLock, delAddr); err != nil {
+		return nil, err
+	}
+	return &lock, nil
+}
+
+// GetCandidateInfo returns the candidate of the node.
+func (c *Client) GetCandidateInfo(opts *bind.CallOpts, nodeId discover.NodeID) (*staking.CandidateHex, error) {
+	var can staking.CandidateHex
+	if err := c.call(opts, cvm.StakingContractAddr, &can, vm.QueryCandidateInfo, nodeId); err != nil {
+		return nil, err
+	}
+	return &can, nil
+}
+
+// GetCommissionSchedule returns the commission change announced by the
+// candidate and not effective yet.
+func (c *Client) GetCommissionSchedule(opts *bind.CallOpts, nodeId discover.NodeID) (*staking.CommissionSchedule, error) {
+	var schedule staking.CommissionSchedule
+	if err := c.call(opts, cvm.StakingContractAddr, &schedule, vm.QueryCommissionSchedule, nodeId); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// GetCommissionHistory returns the commission changes of the candidate.
+func (c *Client) GetCommissionHistory(opts *bind.CallOpts, nodeId discover.NodeID) (staking.CommissionHistory, error) {
+	var history staking.CommissionHistory
+	err := c.call(opts, cvm.StakingContractAddr, &history, vm.QueryCommissionHistory, nodeId)
+	return history, err
+}
+
+// GetPackageReward returns the block reward of the current annual.
+func (c *Client) GetPackageReward(opts *bind.CallOpts) (*big.Int, error) {
+	var reward hexutil.Big
+	if err := c.call(opts, cvm.StakingContractAddr, &reward, vm.GetPackageReward); err != nil {
+		return nil, err
+	}
+	return reward.ToInt(), nil
+}
+
+// GetStakingReward returns the staking reward of the current settlement epoch.
+func (c *Client) GetStakingReward(opts *bind.CallOpts) (*big.Int, error) {
+	var reward hexutil.Big
+	if err := c.call(opts, cvm.StakingContractAddr, &reward, vm.GetStakingReward); err != nil {
+		return nil, err
+	}
+	return reward.ToInt(), nil
+}
+
+// GetAvgPackTime returns the average block time in milliseconds.
+func (c *Client) GetAvgPackTime(opts *bind.CallOpts) (uint64, error) {
+	var avg uint64
+	err := c.call(opts, cvm.StakingContractAddr, &avg, vm.GetAvgPackTime)
+	return avg, err
+}
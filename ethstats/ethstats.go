@@ -34,7 +34,9 @@ import (
 	"github.com/hashkey-chain/hashkey-chain/common"
 	"github.com/hashkey-chain/hashkey-chain/common/mclock"
 	"github.com/hashkey-chain/hashkey-chain/consensus"
+	"github.com/hashkey-chain/hashkey-chain/consensus/cbft"
 	"github.com/hashkey-chain/hashkey-chain/core"
+	"github.com/hashkey-chain/hashkey-chain/core/cbfttypes"
 	"github.com/hashkey-chain/hashkey-chain/core/types"
 	"github.com/hashkey-chain/hashkey-chain/eth"
 	"github.com/hashkey-chain/hashkey-chain/eth/downloader"
@@ -43,7 +45,11 @@ import (
 	"github.com/hashkey-chain/hashkey-chain/miner"
 	"github.com/hashkey-chain/hashkey-chain/node"
 	"github.com/hashkey-chain/hashkey-chain/p2p"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+	"github.com/hashkey-chain/hashkey-chain/params"
 	"github.com/hashkey-chain/hashkey-chain/rpc"
+	"github.com/hashkey-chain/hashkey-chain/x/plugin"
+	"github.com/hashkey-chain/hashkey-chain/x/xutil"
 	"github.com/gorilla/websocket"
 )
 
@@ -57,6 +63,10 @@ const (
 	txChanSize = 4096
 	// chainHeadChanSize is the size of channel listening to ChainHeadEvent.
 	chainHeadChanSize = 10
+
+	// consensusCapability is the capability acknowledged by the stats servers
+	// accepting the consensus reports.
+	consensusCapability = "consensus"
 )
 
 // backend encompasses the bare-minimum functionality needed for ethstats reporting
@@ -79,6 +89,19 @@ type fullNodeBackend interface {
 	SuggestPrice(ctx context.Context) (*big.Int, error)
 }
 
+// muxBackend is the backend posting the consensus events, such as the view
+// changes of CBFT.
+type muxBackend interface {
+	EventMux() *event.TypeMux
+}
+
+// consensusEngine is the consensus engine able to report its consensus and
+// validator status, namely CBFT.
+type consensusEngine interface {
+	ViewStatus() *cbft.ViewStatus
+	NodeID() discover.NodeID
+}
+
 // Service implements an Ethereum netstats reporting daemon that pushes local
 // chain statistics up to a monitoring server.
 type Service struct {
@@ -92,6 +115,10 @@ type Service struct {
 
 	pongCh chan struct{} // Pong notifications are fed into this channel
 	histCh chan []uint64 // History request block numbers are fed into this channel
+
+	// Whether the connected server accepts the consensus reports. The stock
+	// servers don't, the consensus stats are attached to the node stats instead.
+	consensusReport bool
 }
 
 // connWrapper is a wrapper to prevent concurrent-write or concurrent-read on the
@@ -188,11 +215,20 @@ func (s *Service) loop() {
 	txSub := s.backend.SubscribeNewTxsEvent(txEventCh)
 	defer txSub.Unsubscribe()
 
+	// Subscribe to the view changes if the consensus engine reports them
+	var viewEventCh <-chan *event.TypeMuxEvent
+	if mb, ok := s.backend.(muxBackend); ok && s.isConsensusEngine() {
+		viewSub := mb.EventMux().Subscribe(cbfttypes.ViewChangedEvent{})
+		defer viewSub.Unsubscribe()
+		viewEventCh = viewSub.Chan()
+	}
+
 	// Start a goroutine that exhausts the subscriptions to avoid events piling up
 	var (
 		quitCh = make(chan struct{})
 		headCh = make(chan *types.Block, 1)
 		txCh   = make(chan struct{}, 1)
+		viewCh = make(chan struct{}, 1)
 	)
 	go func() {
 		var lastTx mclock.AbsTime
@@ -219,6 +255,17 @@ func (s *Service) loop() {
 				default:
 				}
 
+			// Notify of view changes, but drop if too frequent
+			case _, ok := <-viewEventCh:
+				if !ok {
+					viewEventCh = nil
+					continue
+				}
+				select {
+				case viewCh <- struct{}{}:
+				default:
+				}
+
 			// node stopped
 			case <-txSub.Err():
 				break HandleLoop
@@ -309,6 +356,13 @@ func (s *Service) loop() {
 					if err = s.reportPending(conn); err != nil {
 						log.Warn("Post-block transaction stats report failed", "err", err)
 					}
+					if err = s.reportConsensus(conn); err != nil {
+						log.Warn("Post-block consensus stats report failed", "err", err)
+					}
+				case <-viewCh:
+					if err = s.reportConsensus(conn); err != nil {
+						log.Warn("View change consensus stats report failed", "err", err)
+					}
 				case <-txCh:
 					if err = s.reportPending(conn); err != nil {
 						log.Warn("Transaction stats report failed", "err", err)
@@ -426,6 +480,8 @@ type nodeInfo struct {
 	OsVer    string `json:"os_v"`
 	Client   string `json:"client"`
 	History  bool   `json:"canUpdateHistory"`
+
+	Consensus string `json:"consensus,omitempty"`
 }
 
 // authMsg is the authentication infos needed to login to a monitoring server.
@@ -463,17 +519,27 @@ func (s *Service) login(conn *connWrapper) error {
 		},
 		Secret: s.pass,
 	}
+	if s.isConsensusEngine() {
+		auth.Info.Consensus = "cbft"
+	}
 	login := map[string][]interface{}{
 		"emit": {"hello", auth},
 	}
 	if err := conn.WriteJSON(login); err != nil {
 		return err
 	}
-	// Retrieve the remote ack or connection termination
+	// Retrieve the remote ack or connection termination, the servers accepting
+	// the extended reports list their capabilities after the ready message.
 	var ack map[string][]string
-	if err := conn.ReadJSON(&ack); err != nil || len(ack["emit"]) != 1 || ack["emit"][0] != "ready" {
+	if err := conn.ReadJSON(&ack); err != nil || len(ack["emit"]) == 0 || ack["emit"][0] != "ready" {
 		return errors.New("unauthorized")
 	}
+	s.consensusReport = false
+	for _, capability := range ack["emit"][1:] {
+		if capability == consensusCapability {
+			s.consensusReport = true
+		}
+	}
 	return nil
 }
 
@@ -493,6 +559,9 @@ func (s *Service) report(conn *connWrapper) error {
 	if err := s.reportStats(conn); err != nil {
 		return err
 	}
+	if err := s.reportConsensus(conn); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -708,6 +777,8 @@ type nodeStats struct {
 	Peers    int  `json:"peers"`
 	GasPrice int  `json:"gasPrice"`
 	Uptime   int  `json:"uptime"`
+
+	Consensus *consensusStats `json:"consensus,omitempty"`
 }
 
 // reportStats retrieves various stats about the node at the networking and
@@ -736,19 +807,108 @@ func (s *Service) reportStats(conn *connWrapper) error {
 	// Assemble the node stats and send it to the server
 	log.Trace("Sending node details to ethstats")
 
+	details := &nodeStats{
+		Active:   true,
+		Mining:   mining,
+		Peers:    s.server.PeerCount(),
+		GasPrice: gasprice,
+		Syncing:  syncing,
+		Uptime:   100,
+	}
+	// The stock servers ignore the unknown fields of the node stats
+	if !s.consensusReport {
+		details.Consensus = s.assembleConsensusStats()
+	}
 	stats := map[string]interface{}{
-		"id": s.node,
-		"stats": &nodeStats{
-			Active:   true,
-			Mining:   mining,
-			Peers:    s.server.PeerCount(),
-			GasPrice: gasprice,
-			Syncing:  syncing,
-			Uptime:   100,
-		},
+		"id":    s.node,
+		"stats": details,
 	}
 	report := map[string][]interface{}{
 		"emit": {"stats", stats},
 	}
 	return conn.WriteJSON(report)
 }
+
+// consensusStats is the information to report about the consensus and the
+// staking of the local node.
+type consensusStats struct {
+	Epoch          uint64 `json:"epoch"`
+	ViewNumber     uint64 `json:"viewNumber"`
+	HighestQC      uint64 `json:"highestQCBlock"`
+	HighestCommit  uint64 `json:"highestCommitBlock"`
+	QCLatency      int64  `json:"qcLatency"` // Milliseconds
+	ViewChanges    uint64 `json:"viewChanges"`
+	Validator      bool   `json:"validator"`
+	ValidatorCount int    `json:"validatorCount"`
+	ProgramVersion string `json:"programVersion"`
+
+	Candidate       bool   `json:"candidate"`
+	CandidateStatus uint32 `json:"candidateStatus"`
+	StakingVersion  string `json:"stakingVersion,omitempty"`
+}
+
+// isConsensusEngine reports whether the consensus engine reports its status.
+func (s *Service) isConsensusEngine() bool {
+	_, ok := s.engine.(consensusEngine)
+	return ok
+}
+
+// assembleConsensusStats retrieves the consensus status of the engine and
+// the candidate of the local node, nil if the engine doesn't report them.
+func (s *Service) assembleConsensusStats() *consensusStats {
+	engine, ok := s.engine.(consensusEngine)
+	if !ok {
+		return nil
+	}
+	status := engine.ViewStatus()
+	if status == nil {
+		return nil
+	}
+	stats := &consensusStats{
+		Epoch:          status.Epoch,
+		ViewNumber:     status.ViewNumber,
+		HighestQC:      status.HighestQC,
+		HighestCommit:  status.HighestCommit,
+		QCLatency:      status.QCLatency.Milliseconds(),
+		ViewChanges:    status.ViewChanges,
+		Validator:      status.Validator,
+		ValidatorCount: status.ValidatorCount,
+		ProgramVersion: params.FormatVersion(params.CodeVersion()),
+	}
+	// The candidate is only available on the nodes keeping the staking data
+	if _, ok := s.backend.(fullNodeBackend); !ok {
+		return stats
+	}
+	addr, err := xutil.NodeId2Addr(engine.NodeID())
+	if err != nil {
+		return stats
+	}
+	if can, err := plugin.StakingInstance().GetCandidateInfo(s.backend.CurrentHeader().Hash(), addr); err == nil && can != nil {
+		stats.Candidate = true
+		stats.CandidateStatus = uint32(can.Status)
+		stats.StakingVersion = params.FormatVersion(can.ProgramVersion)
+	}
+	return stats
+}
+
+// reportConsensus retrieves the consensus status and reports it to the stats
+// server, if the server accepts the consensus reports.
+func (s *Service) reportConsensus(conn *connWrapper) error {
+	if !s.consensusReport {
+		return nil
+	}
+	details := s.assembleConsensusStats()
+	if details == nil {
+		return nil
+	}
+	log.Trace("Sending consensus details to ethstats", "epoch", details.Epoch, "view", details.ViewNumber)
+
+	stats := map[string]interface{}{
+		"id":        s.node,
+		"consensus": details,
+	}
+	report := map[string][]interface{}{
+		"emit": {"consensus", stats},
+	}
+	return conn.WriteJSON(report)
+}
@@ -674,6 +674,69 @@ func (s *PublicBlockChainAPI) GetBlockByNumber(ctx context.Context, number rpc.B
 	return nil, err
 }
 
+// GetBalanceChanges returns the balance changes made by the PPOS system
+// contracts in the requested canonical block, such as the rewards, the refunds
+// of the staking and the restricting releases, which are invisible in the
+// transactions of the block.
+func (s *PublicBlockChainAPI) GetBalanceChanges(ctx context.Context, number rpc.BlockNumber) ([]*types.BalanceChange, error) {
+	if number == rpc.PendingBlockNumber {
+		return nil, errors.New("balance changes of the pending block are not available")
+	}
+	header, err := s.b.HeaderByNumber(ctx, number)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	changes, err := s.b.GetBalanceChanges(ctx, header.Hash())
+	if err != nil {
+		return nil, err
+	}
+	if changes == nil {
+		changes = []*types.BalanceChange{}
+	}
+	return changes, nil
+}
+
+// BlockBalanceChanges is the notification of the BalanceChanges subscription.
+type BlockBalanceChanges struct {
+	BlockNumber hexutil.Uint64         `json:"blockNumber"`
+	BlockHash   common.Hash            `json:"blockHash"`
+	Changes     []*types.BalanceChange `json:"changes"`
+}
+
+// BalanceChanges creates a subscription that fires the balance changes made
+// by the PPOS system contracts in every new canonical block that has any.
+func (s *PublicBlockChainAPI) BalanceChanges(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	var (
+		rpcSub   = notifier.CreateSubscription()
+		changes  = make(chan core.BalanceChangesEvent, 16)
+		eventSub = s.b.SubscribeBalanceChangesEvent(changes)
+	)
+	go func() {
+		defer eventSub.Unsubscribe()
+		for {
+			select {
+			case ev := <-changes:
+				notifier.Notify(rpcSub.ID, &BlockBalanceChanges{
+					BlockNumber: hexutil.Uint64(ev.Block.NumberU64()),
+					BlockHash:   ev.Block.Hash(),
+					Changes:     ev.Changes,
+				})
+			case <-rpcSub.Err(): // client send an unsubscribe request
+				return
+			case <-notifier.Closed(): // connection dropped
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // GetBlockByHash returns the requested block. When fullTx is true all transactions in the block are returned in full
 // detail, otherwise only the transaction hash is returned.
 func (s *PublicBlockChainAPI) GetBlockByHash(ctx context.Context, hash common.Hash, fullTx bool) (map[string]interface{}, error) {
@@ -716,6 +779,7 @@ type CallArgs struct {
 	GasPrice *hexutil.Big    `json:"gasPrice"`
 	Value    *hexutil.Big    `json:"value"`
 	Data     *hexutil.Bytes  `json:"data"`
+	Payer    *common.Address `json:"payer"`
 }
 
 // ToMessage converts CallArgs to the Message type used by the core evm
@@ -753,6 +817,9 @@ func (args *CallArgs) ToMessage(globalGasCap uint64) types.Message {
 		data = []byte(*args.Data)
 	}
 
+	if args.Payer != nil {
+		return types.NewSponsoredMessage(addr, *args.Payer, args.To, 0, value, gas, gasPrice, data, false)
+	}
 	msg := types.NewMessage(addr, args.To, 0, value, gas, gasPrice, data, false)
 	return msg
 }
@@ -920,6 +987,11 @@ func DoEstimateGas(ctx context.Context, b Backend, args CallArgs, blockNrOrHash
 			}
 			available.Sub(available, args.Value.ToInt())
 		}
+		// The gas of a sponsored call is funded by the fee payer alone
+		if args.Payer != nil {
+			balance = state.GetBalance(*args.Payer)
+			available = new(big.Int).Set(balance)
+		}
 		allowance := new(big.Int).Div(available, args.GasPrice.ToInt())
 
 		// If the allowance is larger than maximum uint64, skip checking
@@ -1201,6 +1273,10 @@ type RPCTransaction struct {
 	V                *hexutil.Big    `json:"v"`
 	R                *hexutil.Big    `json:"r"`
 	S                *hexutil.Big    `json:"s"`
+	Payer            *common.Address `json:"payer,omitempty"`
+	PayerV           *hexutil.Big    `json:"payerV,omitempty"`
+	PayerR           *hexutil.Big    `json:"payerR,omitempty"`
+	PayerS           *hexutil.Big    `json:"payerS,omitempty"`
 }
 
 // newRPCTransaction returns a transaction that will serialize to the RPC
@@ -1223,6 +1299,13 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		R:        (*hexutil.Big)(r),
 		S:        (*hexutil.Big)(s),
 	}
+	if tx.Sponsored() {
+		payerV, payerR, payerS := tx.RawPayerSignatureValues()
+		result.Payer = tx.Payer()
+		result.PayerV = (*hexutil.Big)(payerV)
+		result.PayerR = (*hexutil.Big)(payerR)
+		result.PayerS = (*hexutil.Big)(payerS)
+	}
 	if blockHash != (common.Hash{}) {
 		result.BlockHash = &blockHash
 		result.BlockNumber = (*hexutil.Big)(new(big.Int).SetUint64(blockNumber))
@@ -1390,6 +1473,77 @@ func (s *PublicTransactionPoolAPI) GetRawTransactionByHash(ctx context.Context,
 	return rlp.EncodeToBytes(tx)
 }
 
+// maxAddressTxPageSize is the maximum number of transactions returned by
+// one page of GetTransactionsByAddress.
+const maxAddressTxPageSize = 100
+
+// RPCAddressTransaction is a transaction of the history of an address along
+// with the roles the address plays in it ("from", "to", "create", "ppos").
+type RPCAddressTransaction struct {
+	*RPCTransaction
+	Roles []string `json:"roles"`
+}
+
+// AddressTransactions is a page of the transaction history of an address.
+type AddressTransactions struct {
+	Transactions []*RPCAddressTransaction `json:"transactions"`
+	HasMore      bool                     `json:"hasMore"`
+}
+
+// addressTxRoles converts the roles of an indexed transaction to their names.
+func addressTxRoles(roles uint8) []string {
+	names := make([]string, 0, 2)
+	if roles&rawdb.AddressTxFrom != 0 {
+		names = append(names, "from")
+	}
+	if roles&rawdb.AddressTxTo != 0 {
+		names = append(names, "to")
+	}
+	if roles&rawdb.AddressTxCreate != 0 {
+		names = append(names, "create")
+	}
+	if roles&rawdb.AddressTxPpos != 0 {
+		names = append(names, "ppos")
+	}
+	return names
+}
+
+// GetTransactionsByAddress returns a page of the transactions sent from or to
+// the given address, the contracts it created and its PPOS system contract
+// calls, newest first. Only the blocks kept by the transaction index are
+// covered and the node must run with the address index enabled.
+func (s *PublicTransactionPoolAPI) GetTransactionsByAddress(ctx context.Context, address common.Address, page hexutil.Uint, pageSize hexutil.Uint) (*AddressTransactions, error) {
+	if pageSize == 0 || pageSize > maxAddressTxPageSize {
+		return nil, fmt.Errorf("page size must be between 1 and %d", maxAddressTxPageSize)
+	}
+	// Read one more entry to know whether there is a next page
+	entries, err := s.b.GetAddressTransactions(ctx, address, int(page)*int(pageSize), int(pageSize)+1)
+	if err != nil {
+		return nil, err
+	}
+	result := &AddressTransactions{Transactions: make([]*RPCAddressTransaction, 0, len(entries))}
+	if len(entries) > int(pageSize) {
+		entries, result.HasMore = entries[:pageSize], true
+	}
+	var block *types.Block
+	for _, entry := range entries {
+		if block == nil || block.NumberU64() != entry.BlockNumber {
+			if block, err = s.b.BlockByNumber(ctx, rpc.BlockNumber(entry.BlockNumber)); err != nil {
+				return nil, err
+			}
+		}
+		if block == nil || int(entry.Index) >= len(block.Transactions()) || block.Transactions()[entry.Index].Hash() != entry.Hash {
+			log.Warn("Stale address transaction entry", "address", address, "number", entry.BlockNumber, "index", entry.Index, "hash", entry.Hash)
+			continue
+		}
+		result.Transactions = append(result.Transactions, &RPCAddressTransaction{
+			RPCTransaction: newRPCTransaction(block.Transactions()[entry.Index], block.Hash(), entry.BlockNumber, uint64(entry.Index)),
+			Roles:          addressTxRoles(entry.Roles),
+		})
+	}
+	return result, nil
+}
+
 // GetTransactionReceipt returns the transaction receipt for the given transaction hash.
 func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
 	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(s.b.ChainDb(), hash)
@@ -1431,6 +1585,9 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	if receipt.ContractAddress != (common.Address{}) {
 		fields["contractAddress"] = receipt.ContractAddress
 	}
+	if receipt.Payer != nil {
+		fields["payer"] = receipt.Payer
+	}
 	return fields, nil
 }
 
@@ -1451,6 +1608,35 @@ func (s *PublicTransactionPoolAPI) sign(addr common.Address, tx *types.Transacti
 	return wallet.SignTx(account, tx, chainID)
 }
 
+// signPayer is a helper function that co-signs a sponsored transaction with the
+// private key of its fee payer. The transaction must be signed by its sender.
+func (s *PublicTransactionPoolAPI) signPayer(payer common.Address, encodedTx hexutil.Bytes) (*types.Transaction, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return nil, err
+	}
+	if !tx.Sponsored() || *tx.Payer() != payer {
+		return nil, fmt.Errorf("transaction isn't sponsored by %s", payer.String())
+	}
+	signer := types.NewPIP7Signer(s.b.ChainConfig().ChainID, s.b.ChainConfig().PIP7ChainID)
+	if _, err := types.Sender(signer, tx); err != nil {
+		return nil, err
+	}
+	// Look up the wallet containing the fee payer
+	account := accounts.Account{Address: payer}
+
+	wallet, err := s.b.AccountManager().Find(account)
+	if err != nil {
+		return nil, err
+	}
+	hash := signer.PayerHash(tx, tx.ChainId())
+	sig, err := wallet.SignHash(account, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithPayerSignature(signer, sig)
+}
+
 // SendTxArgs represents the arguments to sumbit a new transaction into the transaction pool.
 type SendTxArgs struct {
 	From     common.Address  `json:"from"`
@@ -1463,6 +1649,9 @@ type SendTxArgs struct {
 	// newer name and should be preferred by clients.
 	Data  *hexutil.Bytes `json:"data"`
 	Input *hexutil.Bytes `json:"input"`
+	// Payer sponsors the gas of the transaction, which must be co-signed
+	// by the payer before it's submitted.
+	Payer *common.Address `json:"payer"`
 }
 
 // setDefaults is a helper function that fills in default values for unspecified tx fields.
@@ -1513,6 +1702,7 @@ func (args *SendTxArgs) setDefaults(ctx context.Context, b Backend) error {
 			GasPrice: args.GasPrice,
 			Value:    args.Value,
 			Data:     input,
+			Payer:    args.Payer,
 		}
 		pendingBlockNr := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
 		estimated, err := DoEstimateGas(ctx, b, callArgs, pendingBlockNr, b.RPCGasCap())
@@ -1532,10 +1722,16 @@ func (args *SendTxArgs) toTransaction() *types.Transaction {
 	} else if args.Data != nil {
 		input = *args.Data
 	}
+	var tx *types.Transaction
 	if args.To == nil {
-		return types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
+		tx = types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
+	} else {
+		tx = types.NewTransaction(uint64(*args.Nonce), *args.To, (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
 	}
-	return types.NewTransaction(uint64(*args.Nonce), *args.To, (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
+	if args.Payer != nil {
+		tx = tx.WithPayer(*args.Payer)
+	}
+	return tx
 }
 
 // SubmitTransaction is a helper function that submits tx to txPool and logs a message.
@@ -1602,6 +1798,17 @@ func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args Sen
 	if err != nil {
 		return common.Hash{}, err
 	}
+	// A sponsored transaction is co-signed by the fee payer, which must be
+	// managed by this node as well
+	if args.Payer != nil {
+		data, err := rlp.EncodeToBytes(signed)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if signed, err = s.signPayer(*args.Payer, data); err != nil {
+			return common.Hash{}, err
+		}
+	}
 	return SubmitTransaction(ctx, s.b, signed)
 }
 
@@ -1689,6 +1896,30 @@ func (s *PublicTransactionPoolAPI) SignTransaction(ctx context.Context, args Sen
 	return &SignTransactionResult{data, tx}, nil
 }
 
+// SignPayerTransaction co-signs a sponsored transaction signed by its sender with
+// the key of the fee payer, without submitting it. The payer must be unlocked.
+func (s *PublicTransactionPoolAPI) SignPayerTransaction(ctx context.Context, payer common.Address, encodedTx hexutil.Bytes) (*SignTransactionResult, error) {
+	tx, err := s.signPayer(payer, encodedTx)
+	if err != nil {
+		return nil, err
+	}
+	data, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	return &SignTransactionResult{data, tx}, nil
+}
+
+// SendPayerTransaction co-signs a sponsored transaction signed by its sender with
+// the key of the fee payer and submits it to the transaction pool.
+func (s *PublicTransactionPoolAPI) SendPayerTransaction(ctx context.Context, payer common.Address, encodedTx hexutil.Bytes) (common.Hash, error) {
+	tx, err := s.signPayer(payer, encodedTx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return SubmitTransaction(ctx, s.b, tx)
+}
+
 // PendingTransactions returns the transactions that are in the transaction pool
 // and have a from address that is one of the accounts this node manages.
 func (s *PublicTransactionPoolAPI) PendingTransactions() ([]*RPCTransaction, error) {
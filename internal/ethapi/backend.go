@@ -26,6 +26,7 @@ import (
 	"github.com/hashkey-chain/hashkey-chain/consensus"
 	"github.com/hashkey-chain/hashkey-chain/core"
 	"github.com/hashkey-chain/hashkey-chain/core/bloombits"
+	"github.com/hashkey-chain/hashkey-chain/core/rawdb"
 	"github.com/hashkey-chain/hashkey-chain/core/state"
 	"github.com/hashkey-chain/hashkey-chain/core/types"
 	"github.com/hashkey-chain/hashkey-chain/core/vm"
@@ -46,8 +47,9 @@ type Backend interface {
 	ChainDb() ethdb.Database
 	AccountManager() *accounts.Manager
 	ExtRPCEnabled() bool
-	RPCTxFeeCap() float64 // global tx fee cap for all transaction related APIs
-	RPCGasCap() uint64    // global gas cap for eth_call over rpc: DoS protection
+	RPCTxFeeCap() float64   // global tx fee cap for all transaction related APIs
+	RPCGasCap() uint64      // global gas cap for eth_call over rpc: DoS protection
+	RPCSimulateCap() uint64 // global cap on the blocks run by a simulation over rpc
 
 	// Blockchain API
 	//SetHead(number uint64)
@@ -62,14 +64,17 @@ type Backend interface {
 	StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*state.StateDB, *types.Header, error)
 	StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error)
 	GetReceipts(ctx context.Context, blockHash common.Hash) (types.Receipts, error)
+	GetBalanceChanges(ctx context.Context, blockHash common.Hash) ([]*types.BalanceChange, error)
 	GetEVM(ctx context.Context, msg core.Message, state *state.StateDB, header *types.Header) (*vm.EVM, func() error, error)
 	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
 	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
 	SubscribeChainSideEvent(ch chan<- core.ChainSideEvent) event.Subscription
+	SubscribeBalanceChangesEvent(ch chan<- core.BalanceChangesEvent) event.Subscription
 
 	// Transaction pool API
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
 	GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, common.Hash, uint64, uint64, error)
+	GetAddressTransactions(ctx context.Context, address common.Address, offset, limit int) ([]rawdb.AddressTxEntry, error)
 	GetPoolTransactions() (types.Transactions, error)
 	GetPoolTransaction(txHash common.Hash) *types.Transaction
 	GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error)
@@ -0,0 +1,376 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/common/hexutil"
+	"github.com/hashkey-chain/hashkey-chain/core"
+	"github.com/hashkey-chain/hashkey-chain/core/snapshotdb"
+	"github.com/hashkey-chain/hashkey-chain/core/state"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/crypto"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+	"github.com/hashkey-chain/hashkey-chain/rpc"
+	"github.com/hashkey-chain/hashkey-chain/x/xutil"
+)
+
+// SimulatedBlock is a block executed by SimulateBlocks.
+//
+// The number and the timestamp default to the ones following the previous
+// block, a number further ahead fills the gap with empty blocks. The producer
+// defaults to the validator in turn. The overrides are applied after the
+// begin-block logic of PPOS, before the calls.
+type SimulatedBlock struct {
+	Number         *hexutil.Uint64  `json:"number"`
+	Timestamp      *hexutil.Uint64  `json:"timestamp"`
+	Producer       *discover.NodeID `json:"producer"`
+	StateOverrides *StateOverride   `json:"stateOverrides"`
+	PPOSOverrides  []PPOSValue      `json:"pposOverrides"`
+	Calls          []CallArgs       `json:"calls"`
+}
+
+// PPOSValue is a key of the PPOS data and its value, an empty value deletes
+// the key.
+type PPOSValue struct {
+	Key   hexutil.Bytes `json:"key"`
+	Value hexutil.Bytes `json:"value"`
+}
+
+// PPOSChange is a key of the PPOS data changed by a simulated block.
+type PPOSChange struct {
+	Key      hexutil.Bytes `json:"key"`
+	Previous hexutil.Bytes `json:"previous"`
+	Value    hexutil.Bytes `json:"value"`
+}
+
+// SimulatedCallResult is the result of a call of a simulated block.
+type SimulatedCallResult struct {
+	Status     hexutil.Uint64 `json:"status"`
+	ReturnData hexutil.Bytes  `json:"returnData"`
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+	Logs       []*types.Log   `json:"logs"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// SimulatedBlockResult is the result of a simulated block. The PPOS changes are
+// the ones since the previous simulated block, including the changes of the
+// empty blocks filled before the block.
+type SimulatedBlockResult struct {
+	Number      hexutil.Uint64         `json:"number"`
+	Hash        common.Hash            `json:"hash"`
+	ParentHash  common.Hash            `json:"parentHash"`
+	Timestamp   hexutil.Uint64         `json:"timestamp"`
+	Producer    discover.NodeID        `json:"producer"`
+	Miner       common.Address         `json:"miner"`
+	GasUsed     hexutil.Uint64         `json:"gasUsed"`
+	Calls       []*SimulatedCallResult `json:"calls"`
+	PPOSChanges []*PPOSChange          `json:"pposChanges"`
+}
+
+// SimulateBlocks executes the blocks one after another on top of the given
+// block, the latest by default, and returns their results. The blocks run the
+// begin-block and end-block logic of PPOS like the imported ones, so the
+// settlements, elections and proposals take effect at their boundaries. The
+// state and the PPOS data are forked in memory, nothing is written to the
+// chain.
+func (s *PublicBlockChainAPI) SimulateBlocks(ctx context.Context, blocks []SimulatedBlock, blockNrOrHash *rpc.BlockNumberOrHash) ([]*SimulatedBlockResult, error) {
+	if len(blocks) == 0 {
+		return nil, errors.New("no block to simulate")
+	}
+	if blockNrOrHash == nil {
+		latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &latest
+	}
+	statedb, parent, err := s.b.StateAndHeaderByNumberOrHash(ctx, *blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	defer statedb.ClearParentReference()
+
+	number := parent.Number.Uint64()
+	for i, block := range blocks {
+		if block.Number == nil {
+			number++
+		} else if uint64(*block.Number) <= number {
+			return nil, fmt.Errorf("block %d: number %d is not after %d", i, *block.Number, number)
+		} else {
+			number = uint64(*block.Number)
+		}
+	}
+	if max := s.b.RPCSimulateCap(); max != 0 && number-parent.Number.Uint64() > max {
+		return nil, fmt.Errorf("too many blocks to simulate, have %d, max %d", number-parent.Number.Uint64(), max)
+	}
+
+	bcr := core.GetReactorInstance()
+	replay, err := bcr.BeginSimulation(parent)
+	if err != nil {
+		if err == snapshotdb.ErrHistoryUnavailable {
+			return nil, fmt.Errorf("required historical PPOS data unavailable (block %d, only %d blocks below the snapshot base are retained)", parent.Number.Uint64(), snapshotdb.HistoryRemain)
+		}
+		return nil, err
+	}
+	if replay != nil {
+		defer replay.End()
+	}
+
+	sim := &blockSimulator{
+		b:       s.b,
+		bcr:     bcr,
+		replay:  replay,
+		state:   statedb,
+		head:    parent,
+		changed: make(map[string]struct{}),
+	}
+	results := make([]*SimulatedBlockResult, 0, len(blocks))
+	for i := range blocks {
+		block := &blocks[i]
+		for block.Number != nil && sim.head.Number.Uint64()+1 < uint64(*block.Number) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if _, err := sim.next(ctx, &SimulatedBlock{}); err != nil {
+				return nil, fmt.Errorf("block %d: %v", sim.head.Number.Uint64()+1, err)
+			}
+		}
+		result, err := sim.next(ctx, block)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %v", sim.head.Number.Uint64()+1, err)
+		}
+		if result.PPOSChanges, err = sim.pposChanges(parent.Hash()); err != nil {
+			return nil, err
+		}
+		parent = sim.head
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// blockSimulator executes the simulated blocks one after another.
+type blockSimulator struct {
+	b      Backend
+	bcr    *core.BlockChainReactor
+	replay snapshotdb.Replay // The view of the PPOS data, nil if it's not elected by PPOS
+	state  *state.StateDB
+	head   *types.Header // The last simulated block
+
+	changed map[string]struct{} // The PPOS keys changed since the last result
+}
+
+// next executes the block on top of the head.
+func (sim *blockSimulator) next(ctx context.Context, block *SimulatedBlock) (*SimulatedBlockResult, error) {
+	header, producer, err := sim.makeHeader(block)
+	if err != nil {
+		return nil, err
+	}
+	if err := sim.bcr.BeginSimulatedBlocker(sim.replay, header, sim.state); err != nil {
+		return nil, err
+	}
+	blockHash := header.CacheHash()
+	if err := block.StateOverrides.Apply(sim.state); err != nil {
+		return nil, err
+	}
+	if len(block.PPOSOverrides) > 0 {
+		if !sim.bcr.IsPPOS() {
+			return nil, errors.New("the PPOS data is unavailable")
+		}
+		for _, kv := range block.PPOSOverrides {
+			if err := snapshotdb.Instance().Put(blockHash, kv.Key, kv.Value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result := &SimulatedBlockResult{
+		Number:     hexutil.Uint64(header.Number.Uint64()),
+		Hash:       blockHash,
+		ParentHash: header.ParentHash,
+		Timestamp:  hexutil.Uint64(header.Time),
+		Producer:   producer,
+		Miner:      header.Coinbase,
+		Calls:      make([]*SimulatedCallResult, 0, len(block.Calls)),
+	}
+	gp := new(core.GasPool).AddGas(header.GasLimit)
+	for i, args := range block.Calls {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		call, err := sim.call(ctx, header, i, args, gp)
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %v", i, err)
+		}
+		result.GasUsed += call.GasUsed
+		result.Calls = append(result.Calls, call)
+	}
+
+	if sim.bcr.IsPPOS() {
+		if err := sim.bcr.EndBlocker(header, sim.state); err != nil {
+			return nil, err
+		}
+		kvs, err := sim.replay.BlockData(blockHash)
+		if err != nil {
+			return nil, err
+		}
+		for _, kv := range kvs {
+			sim.changed[string(kv[0])] = struct{}{}
+		}
+	}
+	sim.head = header
+	return result, nil
+}
+
+// makeHeader makes the header of the block following the head, the header is
+// unsigned and its hash is fixed once made.
+func (sim *blockSimulator) makeHeader(block *SimulatedBlock) (*types.Header, discover.NodeID, error) {
+	parent := sim.head
+	number := parent.Number.Uint64() + 1
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).SetUint64(number),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + xutil.IntervalAt(number)*1000,
+		Extra:      make([]byte, 32+common.ExtraSeal),
+		Nonce:      types.EncodeNonce(simulatedNonce(parent)),
+	}
+	if block.Timestamp != nil {
+		if uint64(*block.Timestamp) <= parent.Time {
+			return nil, discover.NodeID{}, fmt.Errorf("timestamp %d is not after %d", *block.Timestamp, parent.Time)
+		}
+		header.Time = uint64(*block.Timestamp)
+	}
+	// The seal is not empty, or the block is taken as the one being mined
+	if len(parent.Extra) >= 32 {
+		copy(header.Extra, parent.Extra[:32])
+	}
+	copy(header.Extra[32:], bytes.Repeat([]byte{0xff}, common.ExtraSeal))
+
+	var producer discover.NodeID
+	if sim.bcr.IsPPOS() {
+		v, err := sim.bcr.SimulatedProducer(parent.Hash(), number, block.Producer)
+		if err != nil {
+			return nil, producer, err
+		}
+		producer, header.Coinbase = v.NodeId, v.BenefitAddress
+	} else if block.Producer != nil {
+		producer = *block.Producer
+	}
+	if producer != (discover.NodeID{}) {
+		pk, err := producer.Pubkey()
+		if err != nil {
+			return nil, producer, err
+		}
+		header.SetPublicKey(pk)
+	}
+	return header, producer, nil
+}
+
+// call applies the call of the block to the state.
+func (sim *blockSimulator) call(ctx context.Context, header *types.Header, index int, args CallArgs, gp *core.GasPool) (*SimulatedCallResult, error) {
+	gasCap := gp.Gas()
+	if globalGasCap := sim.b.RPCGasCap(); globalGasCap != 0 && globalGasCap < gasCap {
+		gasCap = globalGasCap
+	}
+	msg := args.ToMessage(gasCap)
+
+	// The calls are not transactions, their logs are grouped by a made up hash
+	blockHash := header.CacheHash()
+	callHash := crypto.Keccak256Hash(blockHash.Bytes(), new(big.Int).SetInt64(int64(index)).Bytes())
+	sim.state.Prepare(callHash, blockHash, index)
+
+	evm, vmError, err := sim.b.GetEVM(ctx, msg, sim.state, header)
+	if err != nil {
+		return nil, err
+	}
+	result, err := core.ApplyMessage(evm, msg, gp)
+	if err := vmError(); err != nil {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("err: %w (supplied gas %d)", err, msg.Gas())
+	}
+	sim.state.Finalise(true)
+
+	call := &SimulatedCallResult{
+		Status:     hexutil.Uint64(types.ReceiptStatusSuccessful),
+		ReturnData: result.Return(),
+		GasUsed:    hexutil.Uint64(result.UsedGas),
+		Logs:       sim.state.GetLogs(callHash),
+	}
+	if call.Logs == nil {
+		call.Logs = []*types.Log{}
+	}
+	if result.Failed() {
+		call.Status = hexutil.Uint64(types.ReceiptStatusFailed)
+		call.Error = result.Err.Error()
+		if len(result.Revert()) > 0 {
+			call.Error = newRevertError(result).Error()
+		}
+	}
+	return call, nil
+}
+
+// pposChanges returns the PPOS keys changed between the block and the head.
+func (sim *blockSimulator) pposChanges(since common.Hash) ([]*PPOSChange, error) {
+	keys := make([]string, 0, len(sim.changed))
+	for key := range sim.changed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	sim.changed = make(map[string]struct{})
+
+	db := snapshotdb.Instance()
+	get := func(hash common.Hash, key []byte) ([]byte, error) {
+		v, err := db.Get(hash, key)
+		if snapshotdb.IsDbNotFoundErr(err) {
+			return nil, nil
+		}
+		return v, err
+	}
+	changes := make([]*PPOSChange, 0, len(keys))
+	for _, key := range keys {
+		prev, err := get(since, []byte(key))
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(sim.head.CacheHash(), []byte(key))
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(prev, value) {
+			changes = append(changes, &PPOSChange{Key: []byte(key), Previous: prev, Value: value})
+		}
+	}
+	return changes, nil
+}
+
+// simulatedNonce makes up the VRF proof of the block following the parent,
+// only its random number is taken from the simulated blocks.
+func simulatedNonce(parent *types.Header) []byte {
+	nonce := make([]byte, len(types.BlockNonce{}))
+	seed := make([]byte, 8)
+	binary.BigEndian.PutUint64(seed, parent.Number.Uint64()+1)
+	copy(nonce[1:], crypto.Keccak256(parent.Hash().Bytes(), seed))
+	return nonce
+}
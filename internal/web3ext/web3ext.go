@@ -24,6 +24,7 @@ var Modules = map[string]string{
 	"miner":    MinerJs,
 	"net":      NetJs,
 	"personal": PersonalJs,
+	"ppos":     PposJs,
 	"rpc":      RpcJs,
 	"txpool":   TxpoolJs,
 }
@@ -460,6 +461,36 @@ web3._extend({
 			params: 2,
 			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.utils.toHex]
 		}),
+		new web3._extend.Method({
+			name: 'getTransactionsByAddress',
+			call: 'hskchain_getTransactionsByAddress',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.utils.toHex, web3._extend.utils.toHex]
+		}),
+		new web3._extend.Method({
+			name: 'getBalanceChanges',
+			call: 'hskchain_getBalanceChanges',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'signPayerTransaction',
+			call: 'hskchain_signPayerTransaction',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'sendPayerTransaction',
+			call: 'hskchain_sendPayerTransaction',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'simulateBlocks',
+			call: 'hskchain_simulateBlocks',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -591,3 +622,72 @@ web3._extend({
 	]
 });
 `
+
+const PposJs = `
+web3._extend({
+	property: 'ppos',
+	methods: [
+		new web3._extend.Method({
+			name: 'getCandidate',
+			call: 'ppos_getCandidate',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'listCandidates',
+			call: 'ppos_listCandidates',
+			params: 3,
+			inputFormatter: [web3._extend.utils.toHex, web3._extend.utils.toHex, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getDelegations',
+			call: 'ppos_getDelegations',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getDelegationLock',
+			call: 'ppos_getDelegationLock',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getValidatorsByRound',
+			call: 'ppos_getValidatorsByRound',
+			params: 2,
+			inputFormatter: [web3._extend.utils.toHex, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getValidatorsByEpoch',
+			call: 'ppos_getValidatorsByEpoch',
+			params: 2,
+			inputFormatter: [web3._extend.utils.toHex, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getProposal',
+			call: 'ppos_getProposal',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'listVotes',
+			call: 'ppos_listVotes',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getRestrictingInfo',
+			call: 'ppos_getRestrictingInfo',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getDelegateReward',
+			call: 'ppos_getDelegateReward',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+	],
+	properties: []
+});
+`
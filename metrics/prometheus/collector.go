@@ -19,6 +19,7 @@ package prometheus
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -31,6 +32,9 @@ var (
 	typeSummaryTpl         = "# TYPE %s summary\n"
 	keyValueTpl            = "%s %v\n\n"
 	keyQuantileTagValueTpl = "%s {quantile=\"%s\"} %v\n"
+	keyLabelsValueTpl      = "%s{%s} %v\n"
+
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
 )
 
 // collector is a collection of byte buffers that aggregate Prometheus reports
@@ -98,6 +102,27 @@ func (c *collector) addResettingTimer(name string, m metrics.ResettingTimer) {
 	c.buff.WriteRune('\n')
 }
 
+func (c *collector) addLabeledGauge(name string, samples []Sample) {
+	if len(samples) == 0 {
+		return
+	}
+	name = mutateKey(name)
+	c.buff.WriteString(fmt.Sprintf(typeGaugeTpl, name))
+	for _, sample := range samples {
+		keys := make([]string, 0, len(sample.Labels))
+		for key := range sample.Labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		labels := make([]string, len(keys))
+		for i, key := range keys {
+			labels[i] = fmt.Sprintf("%s=\"%s\"", key, labelValueEscaper.Replace(sample.Labels[key]))
+		}
+		c.buff.WriteString(fmt.Sprintf(keyLabelsValueTpl, name, strings.Join(labels, ","), sample.Value))
+	}
+	c.buff.WriteRune('\n')
+}
+
 func (c *collector) writeGaugeCounter(name string, value interface{}) {
 	name = mutateKey(name)
 	c.buff.WriteString(fmt.Sprintf(typeGaugeTpl, name))
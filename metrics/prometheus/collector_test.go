@@ -108,3 +108,21 @@ test_resetting_timer {quantile="0.99"} 120000000
 		t.Fatal("unexpected collector output")
 	}
 }
+
+func TestLabeledGauge(t *testing.T) {
+	c := newCollector()
+	c.addLabeledGauge("test/labeled", []Sample{
+		{Labels: map[string]string{"node": "a", "kind": "x\"y"}, Value: 1},
+		{Labels: map[string]string{"node": "b"}, Value: 2.5},
+	})
+	c.addLabeledGauge("test/empty", nil)
+
+	const expectedOutput = `# TYPE test_labeled gauge
+test_labeled{kind="x\"y",node="a"} 1
+test_labeled{node="b"} 2.5
+
+`
+	if c.buff.String() != expectedOutput {
+		t.Fatalf("labeled gauge mismatch:\n%s", c.buff.String())
+	}
+}
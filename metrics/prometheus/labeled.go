@@ -0,0 +1,65 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package prometheus
+
+import (
+	"sync"
+)
+
+// Sample is a value of a labeled gauge.
+type Sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// LabeledCollector reports the gauges labeled per instance, e.g. per node,
+// which can not be kept in the go-metrics registry.
+type LabeledCollector interface {
+	// Collect returns the samples of the gauges by metric name.
+	Collect() map[string][]Sample
+}
+
+var (
+	labeledLock       sync.RWMutex
+	labeledCollectors []LabeledCollector
+)
+
+// RegisterLabeled registers the collector whose gauges are exported by the
+// Handler after the metrics of the registry.
+func RegisterLabeled(c LabeledCollector) {
+	labeledLock.Lock()
+	defer labeledLock.Unlock()
+	labeledCollectors = append(labeledCollectors, c)
+}
+
+// UnregisterLabeled removes the collector registered by RegisterLabeled.
+func UnregisterLabeled(c LabeledCollector) {
+	labeledLock.Lock()
+	defer labeledLock.Unlock()
+	for i, registered := range labeledCollectors {
+		if registered == c {
+			labeledCollectors = append(labeledCollectors[:i], labeledCollectors[i+1:]...)
+			return
+		}
+	}
+}
+
+func registeredLabeled() []LabeledCollector {
+	labeledLock.RLock()
+	defer labeledLock.RUnlock()
+	return append([]LabeledCollector{}, labeledCollectors...)
+}
@@ -61,6 +61,18 @@ func Handler(reg metrics.Registry) http.Handler {
 				log.Warn("Unknown Prometheus metric type", "type", fmt.Sprintf("%T", i))
 			}
 		}
+		// Append the labeled gauges of the external collectors
+		for _, lc := range registeredLabeled() {
+			gauges := lc.Collect()
+			names := make([]string, 0, len(gauges))
+			for name := range gauges {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				c.addLabeledGauge(name, gauges[name])
+			}
+		}
 		w.Header().Add("Content-Type", "text/plain")
 		w.Header().Add("Content-Length", fmt.Sprint(c.buff.Len()))
 		w.Write(c.buff.Bytes())
@@ -1063,28 +1063,61 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 	for _, accTxs := range pending {
 		txsCount = txsCount + len(accTxs)
 	}
-	// Split the pending transactions into locals and remotes
-	localTxs, remoteTxs := make(map[common.Address]types.Transactions), pending
+	// Split the pending transactions into reserved PPOS system-contract calls, locals and remotes
+	systemTxs, localTxs, remoteTxs := make(map[common.Address]types.Transactions), make(map[common.Address]types.Transactions), pending
+	for account, stxs := range w.eth.TxPool().SystemPending() {
+		// Only the leading reserved calls are served first, the rest of the account follows the ordinary rules
+		txs, n := remoteTxs[account], 0
+		for n < len(txs) && n < len(stxs) && txs[n].Hash() == stxs[n].Hash() {
+			n++
+		}
+		if n == 0 {
+			continue
+		}
+		systemTxs[account] = txs[:n]
+		if n == len(txs) {
+			delete(remoteTxs, account)
+		} else {
+			remoteTxs[account] = txs[n:]
+		}
+	}
 	for _, account := range w.eth.TxPool().Locals() {
 		if txs := remoteTxs[account]; len(txs) > 0 {
 			delete(remoteTxs, account)
 			localTxs[account] = txs
 		}
 	}
+	systemTxsCount := 0
 	localTxsCount := 0
 	remoteTxsCount := 0
+	for _, saccTxs := range systemTxs {
+		systemTxsCount = systemTxsCount + len(saccTxs)
+	}
 	for _, laccTxs := range localTxs {
 		localTxsCount = localTxsCount + len(laccTxs)
 	}
 	for _, raccTxs := range remoteTxs {
 		remoteTxsCount = remoteTxsCount + len(raccTxs)
 	}
-	log.Debug("Execute pending transactions", "number", header.Number, "localTxCount", localTxsCount, "remoteTxCount", remoteTxsCount, "txsCount", txsCount)
+	log.Debug("Execute pending transactions", "number", header.Number, "systemTxCount", systemTxsCount, "localTxCount", localTxsCount, "remoteTxCount", remoteTxsCount, "txsCount", txsCount)
 
 	startTime = time.Now()
-	var localTimeout = false
+	var systemTimeout, localTimeout = false, false
 	tempContractCache := make(map[common.Address]struct{})
-	if len(localTxs) > 0 {
+	if len(systemTxs) > 0 {
+		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, systemTxs)
+		if failed, timeout := w.committer.CommitTransactions(header, txs, interrupt, timestamp, blockDeadline, tempContractCache); failed {
+			return fmt.Errorf("commit transactions error")
+		} else {
+			systemTimeout = timeout
+		}
+	}
+
+	commitSystemTxCount := w.current.tcount
+	log.Debug("System transactions executing stat", "number", header.Number, "involvedTxCount", commitSystemTxCount, "time", time.Since(startTime))
+
+	startTime = time.Now()
+	if !systemTimeout && len(localTxs) > 0 {
 		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, localTxs)
 		if failed, timeout := w.committer.CommitTransactions(header, txs, interrupt, timestamp, blockDeadline, tempContractCache); failed {
 			return fmt.Errorf("commit transactions error")
@@ -1093,18 +1126,18 @@ func (w *worker) commitNewWork(interrupt *int32, noempty bool, timestamp int64,
 		}
 	}
 
-	commitLocalTxCount := w.current.tcount
+	commitLocalTxCount := w.current.tcount - commitSystemTxCount
 	log.Debug("Local transactions executing stat", "number", header.Number, "involvedTxCount", commitLocalTxCount, "time", time.Since(startTime))
 
 	startTime = time.Now()
-	if !localTimeout && len(remoteTxs) > 0 {
+	if !systemTimeout && !localTimeout && len(remoteTxs) > 0 {
 		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, remoteTxs)
 
 		if failed, _ := w.committer.CommitTransactions(header, txs, interrupt, timestamp, blockDeadline, tempContractCache); failed {
 			return fmt.Errorf("commit transactions error")
 		}
 	}
-	commitRemoteTxCount := w.current.tcount - commitLocalTxCount
+	commitRemoteTxCount := w.current.tcount - commitSystemTxCount - commitLocalTxCount
 	log.Debug("Remote transactions executing stat", "number", header.Number, "involvedTxCount", commitRemoteTxCount, "time", time.Since(startTime))
 
 	if err := w.commit(w.fullTaskHook, true, tstart); nil != err {
@@ -163,6 +163,7 @@ const (
 	DelegateGas           uint64 = 16000 // Gas needed for delegate
 	WithdrewDelegationGas uint64 = 8000  // Gas needed for withdrewDelegate
 	RedeemDelegationGas   uint64 = 6000  // Gas needed for RedeemDelegation
+	RotateCandidateKeyGas uint64 = 32000 // Gas needed for rotateCandidateKey
 
 	GovGas                   uint64 = 9000   // Gas needed for precompiled contract: govContract
 	SubmitTextProposalGas    uint64 = 320000 // Gas needed for submitText
@@ -183,6 +184,10 @@ const (
 	WithdrawDelegateRewardGas uint64 = 8000 // Gas needed for withdraw  delegate reward
 	WithdrawDelegateNodeGas   uint64 = 1000 // Gas needed for withdraw  delegate reward Node Count
 	WithdrawDelegateEpochGas  uint64 = 100  // Gas needed for withdraw  delegate reward epoch Count
+
+	ScheduledTxGas       uint64 = 21000 // Gas needed for precompiled contract: scheduledTxContract
+	ScheduleTxGas        uint64 = 20000 // Gas needed for scheduleTx
+	CancelScheduledTxGas uint64 = 6000  // Gas needed for cancelScheduledTx
 )
 
 // Gas discount table for BLS12-381 G1 and G2 multi exponentiation operations
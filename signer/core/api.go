@@ -222,6 +222,7 @@ type (
 	SignTxRequest struct {
 		Transaction SendTxArgs       `json:"transaction"`
 		Callinfo    []ValidationInfo `json:"call_info"`
+		Ppos        *PposCall        `json:"ppos,omitempty"`
 		Meta        Metadata         `json:"meta"`
 	}
 	// SignTxResponse result from SignTxRequest
@@ -535,6 +536,7 @@ func (api *SignerAPI) SignTransaction(ctx context.Context, args SendTxArgs, meth
 		Transaction: args,
 		Meta:        MetadataFromContext(ctx),
 		Callinfo:    msgs.Messages,
+		Ppos:        msgs.PposCall,
 	}
 	// Process approval
 	result, err = api.UI.ApproveTx(&req)
@@ -125,6 +125,18 @@ func (ui *CommandlineUI) ApproveTx(request *SignTxRequest) (SignTxResponse, erro
 			fmt.Printf("data:     %v\n", hexutil.Encode(d))
 		}
 	}
+	if call := request.Ppos; call != nil {
+		fmt.Printf("\nPPOS call:\n")
+		fmt.Printf("  contract: %s\n", call.Contract)
+		fmt.Printf("  function: %s (%d)\n", call.FuncName, call.FuncType)
+		for _, param := range call.Params {
+			value, err := json.Marshal(param.Value)
+			if err != nil {
+				value = []byte(fmt.Sprintf("%v", param.Value))
+			}
+			fmt.Printf("    %s (%s): %s\n", param.Name, param.Type, value)
+		}
+	}
 	if request.Callinfo != nil {
 		fmt.Printf("\nTransaction validation:\n")
 		for _, m := range request.Callinfo {
@@ -34,6 +34,36 @@ type ValidationInfo struct {
 }
 type ValidationMessages struct {
 	Messages []ValidationInfo
+	// PposCall is the decoded call to a PPOS system contract, nil for other transactions
+	PposCall *PposCall
+}
+
+// PposCall is the human-readable form of a call to a PPOS system contract,
+// the function types are the same as the ones of the contracts in core/vm.
+type PposCall struct {
+	Contract string      `json:"contract"`
+	FuncType uint16      `json:"funcType"`
+	FuncName string      `json:"funcName"`
+	Query    bool        `json:"query"`
+	Params   []PposParam `json:"params"`
+}
+
+type PposParam struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+func (call *PposCall) String() string {
+	params := make([]string, len(call.Params))
+	for i, param := range call.Params {
+		value, err := json.Marshal(param.Value)
+		if err != nil {
+			value = []byte(fmt.Sprintf("%v", param.Value))
+		}
+		params[i] = fmt.Sprintf("%s=%s", param.Name, value)
+	}
+	return fmt.Sprintf("%s.%s(%s)", call.Contract, call.FuncName, strings.Join(params, ", "))
 }
 
 const (
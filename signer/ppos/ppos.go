@@ -0,0 +1,204 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package ppos decodes the calls to the PPOS system contracts for the review
+// of the signer.
+package ppos
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	cvm "github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core/vm"
+	"github.com/hashkey-chain/hashkey-chain/signer/core"
+	"github.com/hashkey-chain/hashkey-chain/x/plugin"
+)
+
+var errNotPposContract = errors.New("not a PPOS system contract")
+
+// function describes the name and the param names of a PPOS function,
+// the param types are taken from the contract in core/vm.
+type function struct {
+	name   string
+	params []string
+	query  bool
+}
+
+type contract struct {
+	name      string
+	fnSigns   map[uint16]interface{}
+	functions map[uint16]function
+}
+
+var contracts = map[common.Address]*contract{
+	cvm.StakingContractAddr: {
+		name:    "staking",
+		fnSigns: (&vm.StakingContract{}).FnSigns(),
+		functions: map[uint16]function{
+			vm.TxCreateStaking: {name: "createStaking", params: []string{"typ", "benefitAddress", "nodeId", "externalId",
+				"nodeName", "website", "details", "amount", "rewardPer", "programVersion", "programVersionSign", "blsPubKey", "blsProof"}},
+			vm.TxEditorCandidate: {name: "editCandidate", params: []string{"benefitAddress", "nodeId", "rewardPer", "externalId",
+				"nodeName", "website", "details"}},
+			vm.TxIncreaseStaking:    {name: "increaseStaking", params: []string{"nodeId", "typ", "amount"}},
+			vm.TxWithdrewCandidate:  {name: "withdrewStaking", params: []string{"nodeId"}},
+			vm.TxDelegate:           {name: "delegate", params: []string{"typ", "nodeId", "amount"}},
+			vm.TxWithdrewDelegation: {name: "withdrewDelegation", params: []string{"stakingBlockNum", "nodeId", "amount"}},
+			vm.TxRedeemDelegation:   {name: "redeemDelegation"},
+			vm.TxRotateCandidateKey: {name: "rotateCandidateKey", params: []string{"nodeId", "newNodeId", "nodeIdSign",
+				"blsPubKey", "blsProof"}},
+			vm.QueryVerifierList:       {name: "getVerifierList", query: true},
+			vm.QueryValidatorList:      {name: "getValidatorList", query: true},
+			vm.QueryCandidateList:      {name: "getCandidateList", query: true},
+			vm.QueryRelateList:         {name: "getRelatedListByDelAddr", params: []string{"addr"}, query: true},
+			vm.QueryDelegateInfo:       {name: "getDelegateInfo", params: []string{"stakingBlockNum", "delAddr", "nodeId"}, query: true},
+			vm.QueryCandidateInfo:      {name: "getCandidateInfo", params: []string{"nodeId"}, query: true},
+			vm.QueryDelegationLock:     {name: "getDelegateLock", params: []string{"delAddr"}, query: true},
+			vm.QueryCommissionSchedule: {name: "getCommissionSchedule", params: []string{"nodeId"}, query: true},
+			vm.QueryCommissionHistory:  {name: "getCommissionHistory", params: []string{"nodeId"}, query: true},
+			vm.GetPackageReward:        {name: "getPackageReward", query: true},
+			vm.GetStakingReward:        {name: "getStakingReward", query: true},
+			vm.GetAvgPackTime:          {name: "getAvgPackTime", query: true},
+		},
+	},
+	cvm.GovContractAddr: {
+		name:    "gov",
+		fnSigns: (&vm.GovContract{}).FnSigns(),
+		functions: map[uint16]function{
+			vm.SubmitText:            {name: "submitText", params: []string{"verifier", "pipID"}},
+			vm.SubmitVersion:         {name: "submitVersion", params: []string{"verifier", "pipID", "newVersion", "endVotingRounds"}},
+			vm.SubmitParam:           {name: "submitParam", params: []string{"verifier", "pipID", "module", "name", "newValue"}},
+			vm.Vote:                  {name: "vote", params: []string{"verifier", "proposalID", "option", "programVersion", "programVersionSign"}},
+			vm.Declare:               {name: "declareVersion", params: []string{"activeNode", "programVersion", "programVersionSign"}},
+			vm.SubmitCancel:          {name: "submitCancel", params: []string{"verifier", "pipID", "endVotingRounds", "tobeCanceledProposalID"}},
+			vm.SubmitAllowlist:       {name: "submitAllowlist", params: []string{"verifier", "pipID", "operation", "nodeId", "stakingAddress"}},
+			vm.GetProposal:           {name: "getProposal", params: []string{"proposalID"}, query: true},
+			vm.GetResult:             {name: "getTallyResult", params: []string{"proposalID"}, query: true},
+			vm.ListProposal:          {name: "listProposal", query: true},
+			vm.GetActiveVersion:      {name: "getActiveVersion", query: true},
+			vm.GetGovernParamValue:   {name: "getGovernParamValue", params: []string{"module", "name"}, query: true},
+			vm.GetAccuVerifiersCount: {name: "getAccuVerifiersCount", params: []string{"proposalID", "blockHash"}, query: true},
+			vm.ListGovernParam:       {name: "listGovernParam", params: []string{"module"}, query: true},
+			vm.ListAllowlist:         {name: "listAllowlist", query: true},
+		},
+	},
+	cvm.SlashingContractAddr: {
+		name:    "slashing",
+		fnSigns: (&vm.SlashingContract{}).FnSigns(),
+		functions: map[uint16]function{
+			vm.TxReportDuplicateSign: {name: "reportDuplicateSign", params: []string{"dupType", "data"}},
+			vm.CheckDuplicateSign:    {name: "checkDuplicateSign", params: []string{"dupType", "nodeId", "blockNumber"}, query: true},
+		},
+	},
+	cvm.RestrictingContractAddr: {
+		name:    "restricting",
+		fnSigns: (&vm.RestrictingContract{}).FnSigns(),
+		functions: map[uint16]function{
+			vm.TxCreateRestrictingPlan: {name: "createRestrictingPlan", params: []string{"account", "plans"}},
+			vm.QueryRestrictingInfo:    {name: "getRestrictingInfo", params: []string{"account"}, query: true},
+		},
+	},
+	cvm.DelegateRewardPoolAddr: {
+		name:    "delegateReward",
+		fnSigns: (&vm.DelegateRewardContract{}).FnSigns(),
+		functions: map[uint16]function{
+			vm.TxWithdrawDelegateReward: {name: "withdrawDelegateReward"},
+			vm.QueryDelegateReward:      {name: "getDelegateReward", params: []string{"address", "nodeIDs"}, query: true},
+		},
+	},
+	cvm.ScheduledTxContractAddr: {
+		name:    "scheduledTx",
+		fnSigns: (&vm.ScheduledTxContract{}).FnSigns(),
+		functions: map[uint16]function{
+			vm.TxScheduleTx:             {name: "scheduleTx", params: []string{"encodedTx", "targetType", "target"}},
+			vm.TxCancelScheduledTx:      {name: "cancelScheduledTx", params: []string{"id"}},
+			vm.GetScheduledTx:           {name: "getScheduledTx", params: []string{"id"}, query: true},
+			vm.GetScheduledTxsByAccount: {name: "getScheduledTxsByAccount", params: []string{"account"}, query: true},
+		},
+	},
+}
+
+// IsPposContract reports whether the address is one of the PPOS system contracts.
+func IsPposContract(addr common.Address) bool {
+	_, ok := contracts[addr]
+	return ok
+}
+
+// ContractName returns the name of the PPOS system contract, or an empty string.
+func ContractName(addr common.Address) string {
+	if c, ok := contracts[addr]; ok {
+		return c.name
+	}
+	return ""
+}
+
+// Decode parses the RLP encoded input of a call to the PPOS system contract
+// the same way as the contract does.
+func Decode(to common.Address, input []byte) (*core.PposCall, error) {
+	c, ok := contracts[to]
+	if !ok {
+		return nil, errNotPposContract
+	}
+	if len(input) == 0 {
+		return nil, errors.New("empty input")
+	}
+	fnCode, _, values, err := plugin.VerifyTxData(input, c.fnSigns)
+	if err != nil {
+		return nil, err
+	}
+	fn, ok := c.functions[fnCode]
+	if !ok {
+		fn = function{name: fmt.Sprintf("func%d", fnCode)}
+	}
+	fnType := reflect.TypeOf(c.fnSigns[fnCode])
+
+	call := &core.PposCall{
+		Contract: c.name,
+		FuncType: fnCode,
+		FuncName: fn.name,
+		Query:    fn.query,
+		Params:   make([]core.PposParam, len(values)),
+	}
+	for i, value := range values {
+		name := fmt.Sprintf("arg%d", i)
+		if i < len(fn.params) {
+			name = fn.params[i]
+		}
+		call.Params[i] = core.PposParam{
+			Name:  name,
+			Type:  fnType.In(i).String(),
+			Value: readable(value),
+		}
+	}
+	return call, nil
+}
+
+// readable converts the decoded value for the review, the amounts are shown
+// in decimal strings to keep the precision in the JS rules.
+func readable(value reflect.Value) interface{} {
+	if value.Kind() == reflect.Ptr && value.IsNil() {
+		return nil
+	}
+	switch v := value.Interface().(type) {
+	case *big.Int:
+		return v.String()
+	default:
+		return v
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/common/hexutil"
+	cvm "github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core/vm"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+	"github.com/hashkey-chain/hashkey-chain/rlp"
+	"github.com/hashkey-chain/hashkey-chain/signer/core"
+	"github.com/hashkey-chain/hashkey-chain/x/restricting"
+)
+
+var nodeId = discover.MustHexID("0x362003c50ed3a523cdede37a001803b8f0fed27cb402b3d6127a1a96661ec202318f68f4c76d9b0bfbabfd551a178d4335eaeaa9b7981a4df30dfc8c0bfe3384")
+
+func encodeInput(fnType uint16, params ...interface{}) []byte {
+	var data [][]byte
+	data = append(data, common.MustRlpEncode(fnType))
+	for _, param := range params {
+		data = append(data, common.MustRlpEncode(param))
+	}
+	input, _ := rlp.EncodeToBytes(data)
+	return input
+}
+
+func TestDecode(t *testing.T) {
+	call, err := Decode(cvm.StakingContractAddr, encodeInput(vm.TxDelegate, uint16(0), nodeId, big.NewInt(1000)))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "staking", call.Contract)
+	assert.Equal(t, uint16(vm.TxDelegate), call.FuncType)
+	assert.Equal(t, "delegate", call.FuncName)
+	assert.False(t, call.Query)
+	assert.Equal(t, []core.PposParam{
+		{Name: "typ", Type: "uint16", Value: uint16(0)},
+		{Name: "nodeId", Type: "discover.NodeID", Value: nodeId},
+		{Name: "amount", Type: "*big.Int", Value: "1000"},
+	}, call.Params)
+
+	account := common.HexToAddress("0x740ce31b3fac20dac379db243021a51e80ad00d7")
+	plans := []restricting.RestrictingPlan{{Epoch: 1, Amount: big.NewInt(10)}}
+	call, err = Decode(cvm.RestrictingContractAddr, encodeInput(vm.TxCreateRestrictingPlan, account, plans))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "restricting.createRestrictingPlan(account=\""+account.String()+"\", plans=[{\"epoch\":1,\"amount\":10}])", call.String())
+
+	call, err = Decode(cvm.GovContractAddr, encodeInput(vm.ListProposal))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.True(t, call.Query)
+
+	_, err = Decode(cvm.SlashingContractAddr, encodeInput(9999))
+	assert.NotNil(t, err)
+	_, err = Decode(common.HexToAddress("0x01"), encodeInput(vm.TxDelegate))
+	assert.Equal(t, errNotPposContract, err)
+}
+
+// TestFunctions checks that the functions of the contracts are all named with the same arity.
+func TestFunctions(t *testing.T) {
+	for addr, c := range contracts {
+		for fnCode, fn := range c.fnSigns {
+			f, ok := c.functions[fnCode]
+			if !assert.True(t, ok, "function %d of %s is not named", fnCode, addr.String()) {
+				continue
+			}
+			assert.Equal(t, reflect.TypeOf(fn).NumIn(), len(f.params), "params of %s.%s", c.name, f.name)
+		}
+	}
+}
+
+func TestValidator(t *testing.T) {
+	to := common.NewMixedcaseAddress(cvm.StakingContractAddr)
+	data := hexutil.Bytes(encodeInput(vm.TxWithdrewCandidate, nodeId))
+	tx := &core.SendTxArgs{To: &to, Data: &data}
+
+	msgs, err := NewValidator(nil).ValidateTransaction(nil, tx)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "withdrewStaking", msgs.PposCall.FuncName)
+	assert.Len(t, msgs.Messages, 1)
+	assert.Equal(t, core.INFO, msgs.Messages[0].Typ)
+
+	tx.Value = hexutil.Big(*big.NewInt(1))
+	msgs, err = NewValidator(nil).ValidateTransaction(nil, tx)
+	assert.Nil(t, err)
+	assert.Equal(t, core.WARN, msgs.Messages[0].Typ)
+
+	bad := hexutil.Bytes{0x01}
+	tx = &core.SendTxArgs{To: &to, Data: &bad}
+	msgs, err = NewValidator(nil).ValidateTransaction(nil, tx)
+	assert.Nil(t, err)
+	assert.Nil(t, msgs.PposCall)
+	assert.Equal(t, core.CRIT, msgs.Messages[0].Typ)
+
+	other := common.NewMixedcaseAddress(common.HexToAddress("0x740ce31b3fac20dac379db243021a51e80ad00d7"))
+	msgs, err = NewValidator(nil).ValidateTransaction(nil, &core.SendTxArgs{To: &other, Data: &data})
+	assert.Nil(t, err)
+	assert.Nil(t, msgs.PposCall)
+	assert.Empty(t, msgs.Messages)
+}
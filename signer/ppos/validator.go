@@ -0,0 +1,80 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package ppos
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/hashkey-chain/hashkey-chain/signer/core"
+)
+
+// Validator decodes the transactions to the PPOS system contracts,
+// the other transactions are passed to the next validator.
+type Validator struct {
+	next core.Validator
+}
+
+// NewValidator creates a PPOS aware validator, next may be nil
+// if no further validation is wanted for the other transactions.
+func NewValidator(next core.Validator) *Validator {
+	return &Validator{next: next}
+}
+
+// ValidateTransaction implements core.Validator.
+func (v *Validator) ValidateTransaction(selector *string, tx *core.SendTxArgs) (*core.ValidationMessages, error) {
+	if tx.To == nil || !IsPposContract(tx.To.Address()) {
+		if v.next == nil {
+			return new(core.ValidationMessages), nil
+		}
+		return v.next.ValidateTransaction(selector, tx)
+	}
+
+	// Prevent accidental erroneous usage of both 'input' and 'data' (show stopper)
+	if tx.Data != nil && tx.Input != nil && !bytes.Equal(*tx.Data, *tx.Input) {
+		return nil, errors.New(`ambiguous request: both "data" and "input" are set and are not identical`)
+	}
+	if tx.Input != nil {
+		tx.Data = tx.Input
+		tx.Input = nil
+	}
+	var data []byte
+	if tx.Data != nil {
+		data = *tx.Data
+	}
+
+	messages := new(core.ValidationMessages)
+	name := ContractName(tx.To.Address())
+	if tx.Value.ToInt().Sign() > 0 {
+		messages.Warn(fmt.Sprintf("Transaction sends value to the %s contract, the amounts of PPOS are given by the params", name))
+	}
+	if selector != nil {
+		messages.Warn(fmt.Sprintf("Transaction to the %s contract is not ABI encoded, the method selector is ignored", name))
+	}
+	call, err := Decode(tx.To.Address(), data)
+	if err != nil {
+		messages.Crit(fmt.Sprintf("Transaction to the %s contract can not be decoded: %v", name, err))
+		return messages, nil
+	}
+	if call.Query {
+		messages.Warn(fmt.Sprintf("Transaction invokes the query function %s.%s, it changes nothing", call.Contract, call.FuncName))
+	}
+	messages.Info(fmt.Sprintf("Transaction invokes the PPOS function %s", call))
+	messages.PposCall = call
+	return messages, nil
+}
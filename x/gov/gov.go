@@ -53,6 +53,7 @@ const (
 	ModuleTxPool      = "txPool"
 	ModuleReward      = "reward"
 	ModuleRestricting = "restricting"
+	ModuleScheduledTx = "scheduledTx"
 )
 
 const (
@@ -74,6 +75,13 @@ const (
 	KeyZeroProduceFreezeDuration  = "zeroProduceFreezeDuration"
 	KeyRestrictingMinimumAmount   = "minimumRelease"
 	KeyUnDelegateFreezeDuration   = "unDelegateFreezeDuration"
+	KeyRewardPerNoticeEpochs      = "rewardPerNoticeEpochs"
+	KeyNodeBlockTimeWindow        = "nodeBlockTimeWindow"
+	KeyPerRoundBlocks             = "perRoundBlocks"
+	KeyElectionPolicy             = "electionPolicy"
+	KeyAdmissionAllowlist         = "admissionAllowlist"
+	KeyMaxScheduledTxsPerBlock    = "maxTxsPerBlock"
+	KeyMaxScheduledGasPerBlock    = "maxGasPerBlock"
 )
 
 func Gte110VersionState(state xcom.StateDB) bool {
@@ -108,8 +116,18 @@ func Gte140Version(version uint32) bool {
 	return version >= params.FORKVERSION_1_4_0
 }
 
-func WriteEcHash130(state xcom.StateDB) error {
-	if data, err := xcom.EcParams130(); nil != err {
+func Gte150VersionState(state xcom.StateDB) bool {
+	return Gte150Version(GetCurrentActiveVersion(state))
+}
+
+func Gte150Version(version uint32) bool {
+	return version >= params.FORKVERSION_1_5_0
+}
+
+// WriteEcHash130 folds the params added by the version 1.3.0, in effect at
+// the block, into the PPOS hash.
+func WriteEcHash130(blockNumber uint64, state xcom.StateDB) error {
+	if data, err := xcom.EcParams130(blockNumber); nil != err {
 		return err
 	} else {
 		SetEcParametersHash(state, data)
@@ -852,6 +870,90 @@ func GovernMaxBlockGasLimit(blockNumber uint64, blockHash common.Hash) (int, err
 	return gasLimit, nil
 }
 
+// GovernNodeBlockTimeWindow returns the governed NodeBlockTimeWindow in
+// seconds, the value is active from the block schedule it begins.
+func GovernNodeBlockTimeWindow(blockNumber uint64, blockHash common.Hash) (uint64, error) {
+	valueStr, err := GetGovernParamValue(ModuleBlock, KeyNodeBlockTimeWindow, blockNumber, blockHash)
+	if nil != err {
+		return 0, err
+	}
+
+	value, err := strconv.ParseUint(valueStr, 10, 64)
+	if nil != err {
+		return 0, err
+	}
+
+	return value, nil
+}
+
+// GovernPerRoundBlocks returns the governed PerRoundBlocks, the value is
+// active from the block schedule it begins.
+func GovernPerRoundBlocks(blockNumber uint64, blockHash common.Hash) (uint64, error) {
+	valueStr, err := GetGovernParamValue(ModuleBlock, KeyPerRoundBlocks, blockNumber, blockHash)
+	if nil != err {
+		return 0, err
+	}
+
+	value, err := strconv.ParseUint(valueStr, 10, 64)
+	if nil != err {
+		return 0, err
+	}
+
+	return value, nil
+}
+
+// GovernElectionPolicy returns the name of the governed policy selecting the
+// validators of the consensus rounds.
+func GovernElectionPolicy(blockNumber uint64, blockHash common.Hash) (string, error) {
+	return GetGovernParamValue(ModuleStaking, KeyElectionPolicy, blockNumber, blockHash)
+}
+
+// AllowlistEnabled reports whether the nodes must be in the allowlist to
+// stake, to be delegated and to be elected. It's off before the version
+// 1.5.0 and by default.
+func AllowlistEnabled(blockNumber uint64, blockHash common.Hash, state xcom.StateDB) (bool, error) {
+	if !Gte150VersionState(state) {
+		return false, nil
+	}
+	valueStr, err := GetGovernParamValue(ModuleStaking, KeyAdmissionAllowlist, blockNumber, blockHash)
+	if nil != err {
+		return false, err
+	}
+	return strconv.ParseBool(valueStr)
+}
+
+// GovernMaxScheduledTxsPerBlock returns the governed maximum number of the
+// scheduled transactions executed in a block.
+func GovernMaxScheduledTxsPerBlock(blockNumber uint64, blockHash common.Hash) (uint32, error) {
+	valueStr, err := GetGovernParamValue(ModuleScheduledTx, KeyMaxScheduledTxsPerBlock, blockNumber, blockHash)
+	if nil != err {
+		return 0, err
+	}
+
+	value, err := strconv.ParseUint(valueStr, 10, 32)
+	if nil != err {
+		return 0, err
+	}
+
+	return uint32(value), nil
+}
+
+// GovernMaxScheduledGasPerBlock returns the governed maximum gas of the
+// scheduled transactions executed in a block.
+func GovernMaxScheduledGasPerBlock(blockNumber uint64, blockHash common.Hash) (uint64, error) {
+	valueStr, err := GetGovernParamValue(ModuleScheduledTx, KeyMaxScheduledGasPerBlock, blockNumber, blockHash)
+	if nil != err {
+		return 0, err
+	}
+
+	value, err := strconv.ParseUint(valueStr, 10, 64)
+	if nil != err {
+		return 0, err
+	}
+
+	return value, nil
+}
+
 //func GovernMaxTxDataLimit(blockNumber uint64, blockHash common.Hash) (int, error) {
 //	sizeStr, err := GetGovernParamValue(ModuleTxPool, KeyMaxTxDataLimit, blockNumber, blockHash)
 //	if nil != err {
@@ -922,6 +1024,20 @@ func GovernRewardPerChangeInterval(blockNumber uint64, blockHash common.Hash) (u
 	return uint16(value), nil
 }
 
+func GovernRewardPerNoticeEpochs(blockNumber uint64, blockHash common.Hash) (uint16, error) {
+	valueStr, err := GetGovernParamValue(ModuleStaking, KeyRewardPerNoticeEpochs, blockNumber, blockHash)
+	if nil != err {
+		return 0, err
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if nil != err {
+		return 0, err
+	}
+
+	return uint16(value), nil
+}
+
 func GovernIncreaseIssuanceRatio(blockNumber uint64, blockHash common.Hash) (uint16, error) {
 	valueStr, err := GetGovernParamValue(ModuleReward, KeyIncreaseIssuanceRatio, blockNumber, blockHash)
 	if nil != err {
@@ -30,7 +30,9 @@ import (
 	"github.com/hashkey-chain/hashkey-chain/common/vm"
 	"github.com/hashkey-chain/hashkey-chain/log"
 	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+	"github.com/hashkey-chain/hashkey-chain/params"
 	"github.com/hashkey-chain/hashkey-chain/x/xcom"
+	"github.com/hashkey-chain/hashkey-chain/x/xutil"
 )
 
 var (
@@ -85,6 +87,13 @@ func GetProposal(proposalID common.Hash, state xcom.StateDB) (Proposal, error) {
 			return nil, e
 		}
 		return &proposal, nil
+	} else if pType == byte(Allowlist) {
+		var proposal AllowlistProposal
+		if e := json.Unmarshal(pData, &proposal); e != nil {
+			log.Error("cannot parse data to allowlist proposal")
+			return nil, e
+		}
+		return &proposal, nil
 	} else {
 		return nil, common.InternalError.Wrap("Incorrect proposal type.")
 	}
@@ -285,10 +294,138 @@ func Set130Param(blockNumber uint64, hash common.Hash, db snapshotdb.DB, chainDB
 	if nil != err {
 		return fmt.Errorf("Parsed UnDelegateFreezeDuration is failed: %v", err)
 	}
+	ev := xcom.UpgradeEconomicExtend(params.FORKVERSION_1_3_0, blockNumber, func(ece *xcom.EconomicModelExtend) {
+		ece.Staking.UnDelegateFreezeDuration = uint64(num)
+	})
+	if chainDB != nil {
+		rawdb.WriteEconomicModelExtend(chainDB, hash, ev.Ece)
+		rawdb.WriteEconomicVersion(chainDB, ev.Version, ev.BlockNumber, hash)
+	}
+	return nil
+}
+
+func Set140Param(blockNumber uint64, hash common.Hash, db snapshotdb.DB, chainDB ethdb.Writer) error {
+	list, err := db.Get(hash, KeyParamItems())
+	if err != nil {
+		return err
+	}
+	var paramItemList []*ParamItem
+	if err := rlp.DecodeBytes(list, &paramItemList); err != nil {
+		return err
+	}
+	rewardPerNoticeEpochsParam := initRewardPerNoticeEpochsParamVersionUpdate(blockNumber)
+	paramItemList = append(paramItemList, rewardPerNoticeEpochsParam.ParamItem)
+	value := common.MustRlpEncode(rewardPerNoticeEpochsParam.ParamValue)
+	if err := db.Put(hash, KeyParamValue(rewardPerNoticeEpochsParam.ParamItem.Module, rewardPerNoticeEpochsParam.ParamItem.Name), value); err != nil {
+		return fmt.Errorf("failed to Store govern 140 parameter. error:%s", err.Error())
+	}
+	RegGovernParamVerifier(rewardPerNoticeEpochsParam.ParamItem.Module, rewardPerNoticeEpochsParam.ParamItem.Name, rewardPerNoticeEpochsParam.ParamVerifier)
+
+	valueList := common.MustRlpEncode(paramItemList)
+	if err := db.Put(hash, KeyParamItems(), valueList); err != nil {
+		return fmt.Errorf("failed to Store govern 140 parameter list. error:%s", err.Error())
+	}
+	num, err := strconv.Atoi(rewardPerNoticeEpochsParam.ParamValue.Value)
+	if nil != err {
+		return fmt.Errorf("Parsed RewardPerNoticeEpochs is failed: %v", err)
+	}
+	ev := xcom.UpgradeEconomicExtend(params.FORKVERSION_1_4_0, blockNumber, func(ece *xcom.EconomicModelExtend) {
+		ece.Staking.RewardPerNoticeEpochs = uint16(num)
+	})
 	if chainDB != nil {
-		xcom.ResetEconomicExtendConfigUnDelegateFreezeDuration(uint64(num))
-		rawdb.WriteEconomicModelExtend(chainDB, hash, xcom.GetEce())
+		rawdb.WriteEconomicModelExtend(chainDB, hash, ev.Ece)
+		rawdb.WriteEconomicVersion(chainDB, ev.Version, ev.BlockNumber, hash)
+	}
+	return nil
+}
+
+func Set150Param(blockNumber uint64, hash common.Hash, db snapshotdb.DB) error {
+	list, err := db.Get(hash, KeyParamItems())
+	if err != nil {
+		return err
+	}
+	var paramItemList []*ParamItem
+	if err := rlp.DecodeBytes(list, &paramItemList); err != nil {
+		return err
+	}
+	for _, param := range init150Params(blockNumber) {
+		paramItemList = append(paramItemList, param.ParamItem)
+		value := common.MustRlpEncode(param.ParamValue)
+		if err := db.Put(hash, KeyParamValue(param.ParamItem.Module, param.ParamItem.Name), value); err != nil {
+			return fmt.Errorf("failed to Store govern 150 parameter. error:%s", err.Error())
+		}
+		RegGovernParamVerifier(param.ParamItem.Module, param.ParamItem.Name, param.ParamVerifier)
+	}
+
+	valueList := common.MustRlpEncode(paramItemList)
+	if err := db.Put(hash, KeyParamItems(), valueList); err != nil {
+		return fmt.Errorf("failed to Store govern 150 parameter list. error:%s", err.Error())
+	}
+	return nil
+}
+
+// IsBlockScheduleParam reports whether the param is of the block schedule.
+func IsBlockScheduleParam(module, name string) bool {
+	return module == ModuleBlock && (name == KeyNodeBlockTimeWindow || name == KeyPerRoundBlocks)
+}
+
+// UpdateBlockSchedule stores the block schedule changed by the param passed
+// at the end of the epoch with the data of the block, returns the first block
+// of the schedule. The validators of the next epoch are elected by the
+// current schedule before the tally, so the new schedule begins the epoch
+// after the next. The schedule is used once the block is committed.
+func UpdateBlockSchedule(name, value string, blockNumber uint64, blockHash common.Hash) (uint64, error) {
+	num, err := strconv.ParseUint(value, 10, 64)
+	if nil != err {
+		return 0, fmt.Errorf("Parsed %s is failed: %v", name, err)
+	}
+	schedules, err := ListBlockSchedules(blockHash)
+	if nil != err {
+		return 0, err
+	}
+	activeBlock := xutil.CalcEpochEndBlock(xutil.CalculateEpoch(blockNumber)+1) + 1
+	schedules, s, err := schedules.Update(activeBlock, func(s *xcom.BlockSchedule) {
+		switch name {
+		case KeyNodeBlockTimeWindow:
+			s.NodeBlockTimeWindow = num
+		case KeyPerRoundBlocks:
+			s.PerRoundBlocks = num
+		}
+	})
+	if nil != err {
+		return 0, err
 	}
+	if err := put(blockHash, KeyBlockSchedules(), schedules); err != nil {
+		return 0, err
+	}
+	log.Info("Update block schedule", "blockNumber", blockNumber, "activeBlock", activeBlock, "epoch", s.Epoch,
+		"nodeBlockTimeWindow", s.NodeBlockTimeWindow, "perRoundBlocks", s.PerRoundBlocks)
+	return activeBlock, nil
+}
+
+// ListBlockSchedules returns the block schedules governed at the block.
+func ListBlockSchedules(blockHash common.Hash) (xcom.BlockSchedules, error) {
+	value, err := get(blockHash, KeyBlockSchedules())
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return nil, err
+	}
+	var schedules xcom.BlockSchedules
+	if len(value) > 0 {
+		if err := rlp.DecodeBytes(value, &schedules); err != nil {
+			return nil, err
+		}
+	}
+	return schedules, nil
+}
+
+// LoadBlockSchedules switches the schedules used by the blocks to the ones
+// governed at the committed block, the zero hash loads the latest committed.
+func LoadBlockSchedules(blockHash common.Hash) error {
+	schedules, err := ListBlockSchedules(blockHash)
+	if nil != err {
+		return err
+	}
+	xcom.SetBlockSchedules(schedules)
 	return nil
 }
 
@@ -521,3 +658,88 @@ func ListActiveVersion(state xcom.StateDB) ([]ActiveVersionValue, error) {
 	}
 	return avList, nil
 }
+
+// GetAllowlistEntry returns the allowlist entry of the node, nil if the node
+// is not in the allowlist.
+func GetAllowlistEntry(blockHash common.Hash, nodeAddr common.NodeAddress) (*AllowlistEntry, error) {
+	value, err := get(blockHash, KeyAllowlistEntry(nodeAddr))
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return nil, err
+	}
+	if len(value) == 0 {
+		return nil, nil
+	}
+	var entry AllowlistEntry
+	if err := rlp.DecodeBytes(value, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ListAllowlist returns the entries of the allowlist, sorted by the node
+// address.
+func ListAllowlist(blockHash common.Hash) ([]*AllowlistEntry, error) {
+	iter := snapshotdb.Instance().Ranking(blockHash, KeyAllowlistPrefix(), 0)
+	defer iter.Release()
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	entries := make([]*AllowlistEntry, 0)
+	for iter.Valid(); iter.Next(); {
+		var entry AllowlistEntry
+		if err := rlp.DecodeBytes(iter.Value(), &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+// AddAllowlistEntry admits the node to stake by the staking address, an
+// existing entry of the node is replaced.
+func AddAllowlistEntry(blockHash common.Hash, entry *AllowlistEntry) error {
+	nodeAddr, err := xutil.NodeId2Addr(entry.NodeId)
+	if err != nil {
+		return err
+	}
+	return put(blockHash, KeyAllowlistEntry(nodeAddr), entry)
+}
+
+// RemoveAllowlistEntry removes the node from the allowlist, the staking
+// plugin withdraws the candidate of the node at the end of the epoch.
+func RemoveAllowlistEntry(blockHash common.Hash, nodeAddr common.NodeAddress) error {
+	if err := del(blockHash, KeyAllowlistEntry(nodeAddr)); err != nil {
+		return err
+	}
+	removed, err := ListAllowlistRemoved(blockHash)
+	if err != nil {
+		return err
+	}
+	for _, addr := range removed {
+		if addr == nodeAddr {
+			return nil
+		}
+	}
+	return put(blockHash, KeyAllowlistRemoved(), append(removed, nodeAddr))
+}
+
+// ListAllowlistRemoved returns the nodes removed from the allowlist since the
+// last ClearAllowlistRemoved.
+func ListAllowlistRemoved(blockHash common.Hash) ([]common.NodeAddress, error) {
+	value, err := get(blockHash, KeyAllowlistRemoved())
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return nil, err
+	}
+	var removed []common.NodeAddress
+	if len(value) > 0 {
+		if err := rlp.DecodeBytes(value, &removed); err != nil {
+			return nil, err
+		}
+	}
+	return removed, nil
+}
+
+// ClearAllowlistRemoved clears the nodes removed from the allowlist.
+func ClearAllowlistRemoved(blockHash common.Hash) error {
+	return del(blockHash, KeyAllowlistRemoved())
+}
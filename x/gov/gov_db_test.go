@@ -19,6 +19,8 @@ package gov
 import (
 	"bytes"
 	"fmt"
+	"strconv"
+
 	"github.com/hashkey-chain/hashkey-chain/core/types"
 	"github.com/hashkey-chain/hashkey-chain/log"
 	"golang.org/x/crypto/sha3"
@@ -34,6 +36,8 @@ import (
 	"github.com/hashkey-chain/hashkey-chain/core/snapshotdb"
 	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
 	"github.com/hashkey-chain/hashkey-chain/rlp"
+	"github.com/hashkey-chain/hashkey-chain/x/xcom"
+	"github.com/hashkey-chain/hashkey-chain/x/xutil"
 )
 
 var (
@@ -924,6 +928,13 @@ func TestGovDB_Version(t *testing.T) {
 	assert.Equal(t, common.Hash{0x00}, hash)
 }
 
+// newTestChain returns a mock chain on an empty snapshotdb, the untagged
+// builds have no path of their own, the data of the tests before is left.
+func newTestChain(t *testing.T) *mock.Chain {
+	snapshotdb.SetDBPathWithNode(t.TempDir())
+	return mock.NewChain()
+}
+
 func newBlock(chain *mock.Chain) common.Hash {
 	chain.AddBlock()
 	chain.SnapDB.NewBlock(chain.CurrentHeader().Number, chain.CurrentHeader().ParentHash, chain.CurrentHeader().Hash())
@@ -1023,3 +1034,126 @@ func rlpHash(x interface{}) (h common.Hash) {
 	hw.Sum(h[:0])
 	return h
 }
+
+func TestGovDB_UpdateBlockSchedule(t *testing.T) {
+	xcom.GetEc(xcom.DefaultUnitTestNet)
+	xcom.ResetBlockSchedules()
+	defer xcom.ResetBlockSchedules()
+
+	chain := newTestChain(t)
+	defer chain.SnapDB.Clear()
+
+	genesis := xcom.BlockScheduleAt(0)
+	tallyBlock := xutil.CalcEpochEndBlock(1)
+	expectActive := xutil.CalcEpochEndBlock(2) + 1
+
+	blockHash := newBlock(chain)
+	window := strconv.FormatUint(genesis.NodeBlockTimeWindow*2, 10)
+	activeBlock, err := UpdateBlockSchedule(KeyNodeBlockTimeWindow, window, tallyBlock, blockHash)
+	assert.Nil(t, err)
+	assert.Equal(t, expectActive, activeBlock)
+	activeBlock, err = UpdateBlockSchedule(KeyPerRoundBlocks, strconv.FormatUint(genesis.PerRoundBlocks/2, 10), tallyBlock, blockHash)
+	assert.Nil(t, err)
+	assert.Equal(t, expectActive, activeBlock)
+
+	schedules, err := ListBlockSchedules(blockHash)
+	assert.Nil(t, err)
+	assert.Len(t, schedules, 1)
+	assert.Equal(t, activeBlock, schedules[0].BlockNumber)
+
+	// The schedule is used once the block is committed
+	assert.Equal(t, genesis, xcom.BlockScheduleAt(activeBlock))
+	assert.Nil(t, commitBlock(chain.SnapDB, blockHash))
+	assert.Nil(t, LoadBlockSchedules(blockHash))
+
+	// The next epoch keeps the genesis schedule
+	assert.Equal(t, genesis, xcom.BlockScheduleAt(activeBlock-1))
+	s := xcom.BlockScheduleAt(activeBlock)
+	assert.Equal(t, genesis.NodeBlockTimeWindow*2, s.NodeBlockTimeWindow)
+	assert.Equal(t, genesis.PerRoundBlocks/2, s.PerRoundBlocks)
+	assert.Equal(t, uint64(3), s.Epoch)
+
+	// The latest committed ones are loaded by the zero hash
+	xcom.ResetBlockSchedules()
+	assert.Nil(t, LoadBlockSchedules(common.ZeroHash))
+	assert.Equal(t, s, xcom.BlockScheduleAt(activeBlock))
+}
+
+func TestGovDB_Allowlist(t *testing.T) {
+	chain := newTestChain(t)
+	defer chain.SnapDB.Clear()
+
+	blockHash := newBlock(chain)
+
+	for i, nodeID := range NodeIDList[:3] {
+		entry := &AllowlistEntry{NodeId: nodeID, StakingAddress: common.Address{byte(i + 1)}}
+		assert.Nil(t, AddAllowlistEntry(blockHash, entry))
+	}
+	entries, err := ListAllowlist(blockHash)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 3)
+
+	nodeAddr, _ := xutil.NodeId2Addr(NodeIDList[1])
+	entry, err := GetAllowlistEntry(blockHash, nodeAddr)
+	assert.Nil(t, err)
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, NodeIDList[1], entry.NodeId)
+		assert.Equal(t, common.Address{0x2}, entry.StakingAddress)
+	}
+
+	// replace the staking address
+	assert.Nil(t, AddAllowlistEntry(blockHash, &AllowlistEntry{NodeId: NodeIDList[1], StakingAddress: common.Address{0x9}}))
+	entry, err = GetAllowlistEntry(blockHash, nodeAddr)
+	assert.Nil(t, err)
+	assert.Equal(t, common.Address{0x9}, entry.StakingAddress)
+
+	// remove the node twice, it's recorded once
+	assert.Nil(t, RemoveAllowlistEntry(blockHash, nodeAddr))
+	assert.Nil(t, RemoveAllowlistEntry(blockHash, nodeAddr))
+	entry, err = GetAllowlistEntry(blockHash, nodeAddr)
+	assert.Nil(t, err)
+	assert.Nil(t, entry)
+	entries, err = ListAllowlist(blockHash)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+
+	removed, err := ListAllowlistRemoved(blockHash)
+	assert.Nil(t, err)
+	assert.Equal(t, []common.NodeAddress{nodeAddr}, removed)
+
+	assert.Nil(t, ClearAllowlistRemoved(blockHash))
+	removed, err = ListAllowlistRemoved(blockHash)
+	assert.Nil(t, err)
+	assert.Len(t, removed, 0)
+}
+
+func TestGovDB_SetProposal_GetProposal_allowlist(t *testing.T) {
+	chain := newTestChain(t)
+	defer chain.SnapDB.Clear()
+
+	proposal := &AllowlistProposal{
+		ProposalID:     common.Hash{0x06},
+		ProposalType:   Allowlist,
+		PIPID:          "em6",
+		SubmitBlock:    uint64(1000),
+		Proposer:       discover.NodeID{},
+		Operation:      AllowlistAdd,
+		NodeId:         NodeIDList[0],
+		StakingAddress: common.Address{0x1},
+	}
+	if e := SetProposal(proposal, chain.StateDB); e != nil {
+		t.Errorf("set proposal error,%s", e)
+	}
+
+	if proposalGet, e := GetProposal(proposal.ProposalID, chain.StateDB); e != nil {
+		t.Errorf("get proposal error,%s", e)
+	} else {
+		ap, ok := proposalGet.(*AllowlistProposal)
+		if assert.True(t, ok) {
+			assert.Equal(t, proposal.PIPID, ap.PIPID)
+			assert.Equal(t, AllowlistAdd, ap.Operation)
+			assert.Equal(t, NodeIDList[0], ap.NodeId)
+			assert.Equal(t, proposal.StakingAddress, ap.StakingAddress)
+		}
+	}
+}
@@ -54,4 +54,11 @@ var (
 	VotingParamProposalExist          = common.NewBizError(302032, "Another parameter proposal already existed at voting stage")
 	GovernParamValueError             = common.NewBizError(302033, "Govern parameter value error")
 	ParamProposalIsSameValue          = common.NewBizError(302034, "The new value of the parameter proposal is the same as the old one")
+	AllowlistNotSupported             = common.NewBizError(302035, "The allowlist proposal is not supported by the active version")
+	AllowlistOperationError           = common.NewBizError(302036, "The operation of the allowlist proposal is invalid")
+	AllowlistNodeEmpty                = common.NewBizError(302037, "The node of the allowlist proposal is null")
+	AllowlistStakingAddressEmpty      = common.NewBizError(302038, "The staking address of the allowlist proposal is null")
+	AllowlistEntryExist               = common.NewBizError(302039, "The node is already in the allowlist")
+	AllowlistEntryNotFound            = common.NewBizError(302040, "The node is not in the allowlist")
+	VotingAllowlistProposalExist      = common.NewBizError(302041, "Another allowlist proposal already existed at voting stage")
 )
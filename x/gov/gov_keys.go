@@ -39,6 +39,9 @@ var (
 	keyPrefixParamItems        = []byte("ParamItems")
 	keyPrefixParamValue        = []byte("ParamValue")
 	keyGovernHASHKey           = []byte("GovernHASH")
+	keyPrefixAllowlist         = []byte("Allowlist")
+	keyAllowlistRemoved        = []byte("AllowlistRmv")
+	keyBlockSchedules          = []byte("BlockSchedules")
 )
 
 func KeyProposal(proposalID common.Hash) []byte {
@@ -114,3 +117,30 @@ func KeyParamValue(module, name string) []byte {
 func KeyGovernHASHKey() []byte {
 	return keyGovernHASHKey
 }
+
+func KeyAllowlistEntry(nodeAddr common.NodeAddress) []byte {
+	return bytes.Join([][]byte{
+		keyPrefixAllowlist,
+		nodeAddr.Bytes(),
+	}, KeyDelimiter)
+}
+
+// KeyAllowlistPrefix is the prefix of the allowlist entries.
+func KeyAllowlistPrefix() []byte {
+	return bytes.Join([][]byte{
+		keyPrefixAllowlist,
+		{},
+	}, KeyDelimiter)
+}
+
+// KeyAllowlistRemoved is the key of the nodes removed from the allowlist
+// whose candidates are not withdrawn yet.
+func KeyAllowlistRemoved() []byte {
+	return keyAllowlistRemoved
+}
+
+// KeyBlockSchedules is the key of the block schedules governed by the param
+// proposals.
+func KeyBlockSchedules() []byte {
+	return keyBlockSchedules
+}
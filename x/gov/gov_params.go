@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math/big"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/hashkey-chain/hashkey-chain/params"
@@ -92,7 +93,7 @@ func initParam() []*GovernParam {
 
 		{
 			ParamItem: &ParamItem{ModuleStaking, KeyMaxValidators,
-				fmt.Sprintf("maximum amount of validator, range: [%d, %d]", xcom.MaxConsensusVals(), xcom.CeilMaxValidators)},
+				fmt.Sprintf("maximum amount of validator, range: [%d, %d]", xcom.MaxConsensusVals(0), xcom.CeilMaxValidators)},
 			ParamValue: &ParamValue{"", strconv.Itoa(int(xcom.MaxValidators())), 0},
 			ParamVerifier: func(blockNumber uint64, blockHash common.Hash, value string) error {
 
@@ -396,6 +397,14 @@ func InitGenesisGovernParam(prevHash common.Hash, snapDB snapshotdb.BaseDB, gene
 		log.Info("init 1.3.0 params")
 		initParamList = append(initParamList, initUnDelegateFreezeDurationParamGenesis())
 	}
+	if genesisVersion >= params.FORKVERSION_1_4_0 {
+		log.Info("init 1.4.0 params")
+		initParamList = append(initParamList, initRewardPerNoticeEpochsParamGenesis())
+	}
+	if genesisVersion >= params.FORKVERSION_1_5_0 {
+		log.Info("init 1.5.0 params")
+		initParamList = append(initParamList, init150Params(0)...)
+	}
 
 	putBasedb_genKVHash_Fn := func(key, val []byte, hash common.Hash) (common.Hash, error) {
 		if err := snapDB.PutBaseDB(key, val); nil != err {
@@ -433,7 +442,7 @@ func initUnDelegateFreezeDurationParamGenesis() *GovernParam {
 	return &GovernParam{
 		ParamItem: &ParamItem{ModuleStaking, KeyUnDelegateFreezeDuration,
 			fmt.Sprintf("quantity of epoch for delegate withdrawal, range:  [%d, UnStakeFreezeDuration]", 1)},
-		ParamValue:    &ParamValue{"", strconv.Itoa(int(xcom.UnDelegateFreezeDuration())), 0},
+		ParamValue:    &ParamValue{"", strconv.Itoa(int(xcom.UnDelegateFreezeDuration(0))), 0},
 		ParamVerifier: UnDelegateFreezeDurationVerifier,
 	}
 }
@@ -473,12 +482,181 @@ var UnDelegateFreezeDurationVerifier = func(blockNumber uint64, blockHash common
 	return nil
 }
 
+func initRewardPerNoticeEpochsParamGenesis() *GovernParam {
+	return &GovernParam{
+		ParamItem: &ParamItem{ModuleStaking, KeyRewardPerNoticeEpochs,
+			fmt.Sprintf("quantity of epoch a commission increase is announced ahead, range: [%d, %d]", xcom.RewardPerNoticeEpochsLowerLimit, xcom.RewardPerNoticeEpochsUpperLimit)},
+		ParamValue:    &ParamValue{"", strconv.Itoa(int(xcom.RewardPerNoticeEpochs(0))), 0},
+		ParamVerifier: RewardPerNoticeEpochsVerifier,
+	}
+}
+
+// The chains upgraded to 1.4.0 keep the former behavior,
+// a commission increase takes effect at the next epoch until the param is governed
+func initRewardPerNoticeEpochsParamVersionUpdate(blockNumber uint64) *GovernParam {
+	return &GovernParam{
+		ParamItem: &ParamItem{ModuleStaking, KeyRewardPerNoticeEpochs,
+			fmt.Sprintf("quantity of epoch a commission increase is announced ahead, range: [%d, %d]", xcom.RewardPerNoticeEpochsLowerLimit, xcom.RewardPerNoticeEpochsUpperLimit)},
+		ParamValue:    &ParamValue{"", strconv.Itoa(xcom.RewardPerNoticeEpochsLowerLimit), blockNumber},
+		ParamVerifier: RewardPerNoticeEpochsVerifier,
+	}
+}
+
+var RewardPerNoticeEpochsVerifier = func(blockNumber uint64, blockHash common.Hash, value string) error {
+	num, err := strconv.Atoi(value)
+	if nil != err {
+		return fmt.Errorf("Parsed RewardPerNoticeEpochs is failed: %v", err)
+	}
+	if err := xcom.CheckRewardPerNoticeEpochs(uint16(num)); nil != err {
+		return err
+	}
+	return nil
+}
+
+// initBlockScheduleParams returns the params of the block schedule in effect
+// at the block.
+func initBlockScheduleParams(blockNumber uint64) []*GovernParam {
+	s := xcom.BlockScheduleAt(blockNumber)
+	return []*GovernParam{
+		{
+			ParamItem: &ParamItem{ModuleBlock, KeyNodeBlockTimeWindow,
+				"node block time window in seconds, it must be integer multiples of PerRoundBlocks, takes effect from the second epoch after the proposal passes"},
+			ParamValue:    &ParamValue{"", strconv.FormatUint(s.NodeBlockTimeWindow, 10), blockNumber},
+			ParamVerifier: NodeBlockTimeWindowVerifier,
+		},
+		{
+			ParamItem: &ParamItem{ModuleBlock, KeyPerRoundBlocks,
+				"blocks each validator will create per consensus round, takes effect from the second epoch after the proposal passes"},
+			ParamValue:    &ParamValue{"", strconv.FormatUint(s.PerRoundBlocks, 10), blockNumber},
+			ParamVerifier: PerRoundBlocksVerifier,
+		},
+	}
+}
+
+// initElectionPolicyParam returns the param of the election policy, it is
+// initialized by the genesis.
+func initElectionPolicyParam(blockNumber uint64) *GovernParam {
+	return &GovernParam{
+		ParamItem: &ParamItem{ModuleStaking, KeyElectionPolicy,
+			fmt.Sprintf("the policy selecting the validators of each consensus round, one of [%s]", strings.Join(xcom.ElectionPolicies(), ", "))},
+		ParamValue:    &ParamValue{"", xcom.ElectionPolicy(blockNumber), blockNumber},
+		ParamVerifier: ElectionPolicyVerifier,
+	}
+}
+
+// initAdmissionAllowlistParam returns the param switching the allowlist of
+// the nodes admitted to stake, it is initialized by the genesis.
+func initAdmissionAllowlistParam(blockNumber uint64) *GovernParam {
+	return &GovernParam{
+		ParamItem: &ParamItem{ModuleStaking, KeyAdmissionAllowlist,
+			"whether only the nodes in the allowlist can stake, be delegated and be elected, the candidates not in the allowlist are not withdrawn when it's switched on"},
+		ParamValue:    &ParamValue{"", strconv.FormatBool(xcom.AdmissionAllowlist(blockNumber)), blockNumber},
+		ParamVerifier: AdmissionAllowlistVerifier,
+	}
+}
+
+// initScheduledTxParams returns the params limiting the scheduled
+// transactions executed in a block, they are initialized by the genesis.
+func initScheduledTxParams(blockNumber uint64) []*GovernParam {
+	return []*GovernParam{
+		{
+			ParamItem: &ParamItem{ModuleScheduledTx, KeyMaxScheduledTxsPerBlock,
+				fmt.Sprintf("maximum number of the scheduled transactions executed in a block, the rest are deferred to the next block, range: [%d, %d]", xcom.ScheduledMaxTxsPerBlockLowerLimit, xcom.ScheduledMaxTxsPerBlockUpperLimit)},
+			ParamValue:    &ParamValue{"", strconv.FormatUint(uint64(xcom.ScheduledMaxTxsPerBlock(blockNumber)), 10), blockNumber},
+			ParamVerifier: MaxScheduledTxsPerBlockVerifier,
+		},
+		{
+			ParamItem: &ParamItem{ModuleScheduledTx, KeyMaxScheduledGasPerBlock,
+				fmt.Sprintf("maximum gas of the scheduled transactions executed in a block, the rest are deferred to the next block, range: [%d, %d]", xcom.ScheduledMaxGasPerBlockLowerLimit, xcom.ScheduledMaxGasPerBlockUpperLimit)},
+			ParamValue:    &ParamValue{"", strconv.FormatUint(xcom.ScheduledMaxGasPerBlock(blockNumber), 10), blockNumber},
+			ParamVerifier: MaxScheduledGasPerBlockVerifier,
+		},
+	}
+}
+
+// init150Params returns the params added by the version 1.5.0.
+func init150Params(blockNumber uint64) []*GovernParam {
+	return append(append(initBlockScheduleParams(blockNumber), initElectionPolicyParam(blockNumber), initAdmissionAllowlistParam(blockNumber)),
+		initScheduledTxParams(blockNumber)...)
+}
+
+// latestParamValue returns the latest value of the param, it may be not
+// active yet.
+func latestParamValue(module, name string, blockHash common.Hash) (uint64, error) {
+	param, err := FindGovernParam(module, name, blockHash)
+	if nil != err {
+		return 0, err
+	}
+	if param == nil {
+		return 0, UnsupportedGovernParam
+	}
+	return strconv.ParseUint(param.ParamValue.Value, 10, 64)
+}
+
+var NodeBlockTimeWindowVerifier = func(blockNumber uint64, blockHash common.Hash, value string) error {
+	window, err := strconv.ParseUint(value, 10, 64)
+	if nil != err {
+		return fmt.Errorf("Parsed NodeBlockTimeWindow is failed: %v", err)
+	}
+	amount, err := latestParamValue(ModuleBlock, KeyPerRoundBlocks, blockHash)
+	if nil != err {
+		return err
+	}
+	return xcom.CheckBlockSchedule(blockNumber, window, amount)
+}
+
+var PerRoundBlocksVerifier = func(blockNumber uint64, blockHash common.Hash, value string) error {
+	amount, err := strconv.ParseUint(value, 10, 64)
+	if nil != err {
+		return fmt.Errorf("Parsed PerRoundBlocks is failed: %v", err)
+	}
+	window, err := latestParamValue(ModuleBlock, KeyNodeBlockTimeWindow, blockHash)
+	if nil != err {
+		return err
+	}
+	return xcom.CheckBlockSchedule(blockNumber, window, amount)
+}
+
+var ElectionPolicyVerifier = func(blockNumber uint64, blockHash common.Hash, value string) error {
+	return xcom.CheckElectionPolicy(value)
+}
+
+var AdmissionAllowlistVerifier = func(blockNumber uint64, blockHash common.Hash, value string) error {
+	if _, err := strconv.ParseBool(value); nil != err {
+		return fmt.Errorf("Parsed AdmissionAllowlist is failed: %v", err)
+	}
+	return nil
+}
+
+var MaxScheduledTxsPerBlockVerifier = func(blockNumber uint64, blockHash common.Hash, value string) error {
+	num, err := strconv.ParseUint(value, 10, 32)
+	if nil != err {
+		return fmt.Errorf("Parsed MaxTxsPerBlock is failed: %v", err)
+	}
+	return xcom.CheckScheduledMaxTxsPerBlock(uint32(num))
+}
+
+var MaxScheduledGasPerBlockVerifier = func(blockNumber uint64, blockHash common.Hash, value string) error {
+	gas, err := strconv.ParseUint(value, 10, 64)
+	if nil != err {
+		return fmt.Errorf("Parsed MaxGasPerBlock is failed: %v", err)
+	}
+	return xcom.CheckScheduledMaxGasPerBlock(gas)
+}
+
 func RegisterGovernParamVerifiers() {
 	for _, param := range queryInitParam() {
 		RegGovernParamVerifier(param.ParamItem.Module, param.ParamItem.Name, param.ParamVerifier)
 	}
 
 	RegGovernParamVerifier(ModuleStaking, KeyUnDelegateFreezeDuration, UnDelegateFreezeDurationVerifier)
+	RegGovernParamVerifier(ModuleStaking, KeyRewardPerNoticeEpochs, RewardPerNoticeEpochsVerifier)
+	RegGovernParamVerifier(ModuleBlock, KeyNodeBlockTimeWindow, NodeBlockTimeWindowVerifier)
+	RegGovernParamVerifier(ModuleBlock, KeyPerRoundBlocks, PerRoundBlocksVerifier)
+	RegGovernParamVerifier(ModuleStaking, KeyElectionPolicy, ElectionPolicyVerifier)
+	RegGovernParamVerifier(ModuleStaking, KeyAdmissionAllowlist, AdmissionAllowlistVerifier)
+	RegGovernParamVerifier(ModuleScheduledTx, KeyMaxScheduledTxsPerBlock, MaxScheduledTxsPerBlockVerifier)
+	RegGovernParamVerifier(ModuleScheduledTx, KeyMaxScheduledGasPerBlock, MaxScheduledGasPerBlockVerifier)
 }
 
 func RegGovernParamVerifier(module, name string, callback ParamVerifier) {
@@ -976,7 +976,7 @@ func TestFork130EcHash(t *testing.T) {
 	chain := setup(t)
 	defer clear(chain, t)
 	if Gte130VersionState(chain.StateDB) {
-		if err := WriteEcHash130(chain.StateDB); nil != err {
+		if err := WriteEcHash130(chain.CurrentHeader().Number.Uint64(), chain.StateDB); nil != err {
 			t.Fatal(err)
 		}
 	}
@@ -988,7 +988,7 @@ func TestFork130EcHash(t *testing.T) {
 	}
 
 	if Gte130VersionState(chain.StateDB) {
-		if err := WriteEcHash130(chain.StateDB); nil != err {
+		if err := WriteEcHash130(chain.CurrentHeader().Number.Uint64(), chain.StateDB); nil != err {
 			t.Fatal(err)
 		}
 	}
@@ -43,6 +43,12 @@ type VoteValue struct {
 	VoteOption VoteOption      `json:"voteOption"`
 }
 
+// AllowlistEntry is a node admitted to stake by the staking address.
+type AllowlistEntry struct {
+	NodeId         discover.NodeID `json:"nodeId"`
+	StakingAddress common.Address  `json:"stakingAddress"`
+}
+
 type ActiveVersionValue struct {
 	ActiveVersion uint32 `json:"ActiveVersion"`
 	ActiveBlock   uint64 `json:"ActiveBlock"`
@@ -33,10 +33,11 @@ const RateCoefficient = uint64(10000)
 type ProposalType uint8
 
 const (
-	Text    ProposalType = 0x01
-	Version ProposalType = 0x02
-	Param   ProposalType = 0x03
-	Cancel  ProposalType = 0x04
+	Text      ProposalType = 0x01
+	Version   ProposalType = 0x02
+	Param     ProposalType = 0x03
+	Cancel    ProposalType = 0x04
+	Allowlist ProposalType = 0x05
 )
 
 type ProposalStatus uint8
@@ -148,14 +149,14 @@ func (tp *TextProposal) Verify(submitBlock uint64, blockHash common.Hash, state
 		return err
 	}
 
-	endVotingBlock := xutil.CalEndVotingBlock(submitBlock, xutil.EstimateConsensusRoundsForGov(xcom.TextProposalVote_DurationSeconds()))
+	endVotingBlock := xutil.CalEndVotingBlock(submitBlock, xutil.EstimateConsensusRoundsForGov(xcom.TextProposalVote_DurationSeconds(submitBlock)))
 	if endVotingBlock <= submitBlock {
 		log.Error("the end-voting-block is lower than submit-block. Please check configuration")
 		return common.InternalError
 	}
 	tp.EndVotingBlock = endVotingBlock
 
-	log.Debug("verify Text Proposal", "PIPID", tp.PIPID, "voteDuration", xcom.TextProposalVote_DurationSeconds(), "endVotingBlock", endVotingBlock, "blockNumber", submitBlock, "blockHash", blockHash)
+	log.Debug("verify Text Proposal", "PIPID", tp.PIPID, "voteDuration", xcom.TextProposalVote_DurationSeconds(submitBlock), "endVotingBlock", endVotingBlock, "blockNumber", submitBlock, "blockHash", blockHash)
 	return nil
 }
 
@@ -227,7 +228,7 @@ func (vp *VersionProposal) Verify(submitBlock uint64, blockHash common.Hash, sta
 		return EndVotingRoundsTooSmall
 	}
 
-	if vp.EndVotingRounds > xutil.EstimateConsensusRoundsForGov(xcom.VersionProposalVote_DurationSeconds()) {
+	if vp.EndVotingRounds > xutil.EstimateConsensusRoundsForGov(xcom.VersionProposalVote_DurationSeconds(submitBlock)) {
 		return EndVotingRoundsTooLarge
 	}
 
@@ -269,7 +270,7 @@ func (vp *VersionProposal) Verify(submitBlock uint64, blockHash common.Hash, sta
 		return PreActiveVersionProposalExist
 	}
 
-	log.Debug("verify Version Proposal", "PIPID", vp.PIPID, "voteDuration", xcom.VersionProposalVote_DurationSeconds(), "endVotingBlock", endVotingBlock, "activeBlock", activeBlock, "blockNumber", submitBlock, "blockHash", blockHash)
+	log.Debug("verify Version Proposal", "PIPID", vp.PIPID, "voteDuration", xcom.VersionProposalVote_DurationSeconds(submitBlock), "endVotingBlock", endVotingBlock, "activeBlock", activeBlock, "blockNumber", submitBlock, "blockHash", blockHash)
 	return nil
 }
 
@@ -470,7 +471,7 @@ func (pp *ParamProposal) Verify(submitBlock uint64, blockHash common.Hash, state
 		return PreActiveVersionProposalExist
 	}
 
-	var voteDuration = xcom.ParamProposalVote_DurationSeconds()
+	var voteDuration = xcom.ParamProposalVote_DurationSeconds(submitBlock)
 
 	endVotingBlock := xutil.EstimateEndVotingBlockForParaProposal(submitBlock, voteDuration)
 	if endVotingBlock <= submitBlock {
@@ -496,6 +497,129 @@ func (pp *ParamProposal) String() string {
 		pp.ProposalID, pp.ProposalType, pp.PIPID, pp.Proposer, pp.SubmitBlock, pp.EndVotingBlock, pp.Module, pp.Name, pp.NewValue)
 }
 
+type AllowlistOperation uint8
+
+const (
+	AllowlistAdd    AllowlistOperation = 0x01
+	AllowlistRemove AllowlistOperation = 0x02
+)
+
+// AllowlistProposal adds a node to the allowlist of the nodes admitted to
+// stake, or removes it. The proposal is tallied at the end of the epoch, the
+// candidate of a removed node is withdrawn at the end of that epoch.
+type AllowlistProposal struct {
+	ProposalID     common.Hash
+	ProposalType   ProposalType
+	PIPID          string
+	SubmitBlock    uint64
+	EndVotingBlock uint64
+	Proposer       discover.NodeID
+	Result         TallyResult `json:"-"`
+	Operation      AllowlistOperation
+	NodeId         discover.NodeID
+	StakingAddress common.Address
+}
+
+func (ap *AllowlistProposal) GetProposalID() common.Hash {
+	return ap.ProposalID
+}
+
+func (ap *AllowlistProposal) GetProposalType() ProposalType {
+	return ap.ProposalType
+}
+
+func (ap *AllowlistProposal) GetPIPID() string {
+	return ap.PIPID
+}
+
+func (ap *AllowlistProposal) GetSubmitBlock() uint64 {
+	return ap.SubmitBlock
+}
+
+func (ap *AllowlistProposal) GetEndVotingBlock() uint64 {
+	return ap.EndVotingBlock
+}
+
+func (ap *AllowlistProposal) GetProposer() discover.NodeID {
+	return ap.Proposer
+}
+
+func (ap *AllowlistProposal) GetTallyResult() TallyResult {
+	return ap.Result
+}
+
+func (ap *AllowlistProposal) Verify(submitBlock uint64, blockHash common.Hash, state xcom.StateDB) error {
+	if ap.ProposalType != Allowlist {
+		return ProposalTypeError
+	}
+	if !Gte150VersionState(state) {
+		return AllowlistNotSupported
+	}
+	if err := verifyBasic(ap, blockHash, state); err != nil {
+		return err
+	}
+
+	if ap.NodeId == discover.ZeroNodeID {
+		return AllowlistNodeEmpty
+	}
+	nodeAddr, err := xutil.NodeId2Addr(ap.NodeId)
+	if err != nil {
+		return AllowlistNodeEmpty
+	}
+	entry, err := GetAllowlistEntry(blockHash, nodeAddr)
+	if err != nil {
+		log.Error("find allowlist entry error", "nodeId", ap.NodeId.TerminalString(), "err", err)
+		return err
+	}
+	switch ap.Operation {
+	case AllowlistAdd:
+		if ap.StakingAddress == (common.Address{}) {
+			return AllowlistStakingAddressEmpty
+		}
+		if entry != nil && entry.NodeId == ap.NodeId && entry.StakingAddress == ap.StakingAddress {
+			return AllowlistEntryExist
+		}
+	case AllowlistRemove:
+		if entry == nil {
+			return AllowlistEntryNotFound
+		}
+	default:
+		return AllowlistOperationError
+	}
+
+	if exist, err := FindVotingProposal(blockHash, state, Allowlist); err != nil {
+		log.Error("find voting allowlist proposal error", "err", err)
+		return err
+	} else if exist != nil {
+		return VotingAllowlistProposalExist
+	}
+
+	var voteDuration = xcom.ParamProposalVote_DurationSeconds(submitBlock)
+
+	endVotingBlock := xutil.EstimateEndVotingBlockForParaProposal(submitBlock, voteDuration)
+	if endVotingBlock <= submitBlock {
+		log.Error("the end-voting-block is lower than submit-block. Please check configuration")
+		return common.InternalError
+	}
+	ap.EndVotingBlock = endVotingBlock
+	log.Debug("verify Allowlist Proposal", "PIPID", ap.PIPID, "voteDuration", voteDuration, "endVotingBlock", endVotingBlock, "blockNumber", submitBlock, "blockHash", blockHash)
+
+	return nil
+}
+
+func (ap *AllowlistProposal) String() string {
+	return fmt.Sprintf(`Proposal %x: 
+  Type:               	%x
+  PIPID:			    %s
+  Proposer:            	%x
+  SubmitBlock:        	%d
+  EndVotingBlock:   	%d
+  Operation:   			%d
+  NodeId:   			%x
+  StakingAddress:   	%s`,
+		ap.ProposalID, ap.ProposalType, ap.PIPID, ap.Proposer, ap.SubmitBlock, ap.EndVotingBlock, ap.Operation, ap.NodeId, ap.StakingAddress.String())
+}
+
 func verifyBasic(p Proposal, blockHash common.Hash, state xcom.StateDB) error {
 	log.Debug("verify proposal basic parameters", "proposalID", p.GetProposalID(), "proposer", p.GetProposer(), "pipID", p.GetPIPID(), "endVotingBlock", p.GetEndVotingBlock(), "submitBlock", p.GetSubmitBlock())
 
@@ -0,0 +1,53 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package plugin
+
+import (
+	"math/big"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/x/xcom"
+)
+
+// transferBalance moves the amount from one account to the other and records
+// the balance change, every balance mutation of the plugins goes through it
+// or issueBalance so that the accounts can be reconciled.
+func transferBalance(state xcom.StateDB, from, to common.Address, amount *big.Int, module string, reason types.BalanceChangeReason) {
+	state.SubBalance(from, amount)
+	state.AddBalance(to, amount)
+	addBalanceChange(state, from, to, amount, module, reason)
+}
+
+// issueBalance credits the newly issued amount to the account.
+func issueBalance(state xcom.StateDB, to common.Address, amount *big.Int, module string, reason types.BalanceChangeReason) {
+	state.AddBalance(to, amount)
+	addBalanceChange(state, common.ZeroAddr, to, amount, module, reason)
+}
+
+func addBalanceChange(state xcom.StateDB, from, to common.Address, amount *big.Int, module string, reason types.BalanceChangeReason) {
+	if amount.Sign() == 0 {
+		return
+	}
+	state.AddBalanceChange(&types.BalanceChange{
+		From:   from,
+		To:     to,
+		Amount: new(big.Int).Set(amount),
+		Module: module,
+		Reason: reason,
+	})
+}
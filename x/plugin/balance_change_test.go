@@ -0,0 +1,89 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package plugin
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/common/mock"
+	"github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/x/gov"
+	"github.com/hashkey-chain/hashkey-chain/x/staking"
+)
+
+func TestBalanceChanges(t *testing.T) {
+	chain := mock.NewChain()
+	state := chain.StateDB
+	account := common.HexToAddress("0x740ce31b3fac20dac379db243021a51e80ad00d7")
+	txHash := common.HexToHash("0x01")
+
+	state.AddBalance(account, big.NewInt(100))
+	state.SetTxContext(txHash, 0)
+	transferBalance(state, account, vm.StakingContractAddr, big.NewInt(60), gov.ModuleStaking, types.BalanceChangeStakingLock)
+	// The zero amounts are not recorded
+	transferBalance(state, account, vm.StakingContractAddr, big.NewInt(0), gov.ModuleStaking, types.BalanceChangeStakingLock)
+
+	state.SetTxContext(common.ZeroHash, 0)
+	issueBalance(state, vm.RewardManagerPoolAddr, big.NewInt(10), gov.ModuleReward, types.BalanceChangeIssuance)
+
+	// The reverted changes are dropped
+	snapshot := state.Snapshot()
+	transferBalance(state, vm.RewardManagerPoolAddr, account, big.NewInt(5), gov.ModuleReward, types.BalanceChangeBlockReward)
+	state.RevertToSnapshot(snapshot)
+
+	assert.Equal(t, big.NewInt(40), state.GetBalance(account))
+	assert.Equal(t, big.NewInt(10), state.GetBalance(vm.RewardManagerPoolAddr))
+	assert.Equal(t, []*types.BalanceChange{
+		{From: account, To: vm.StakingContractAddr, Amount: big.NewInt(60), Module: gov.ModuleStaking, Reason: types.BalanceChangeStakingLock, TxHash: txHash},
+		{From: common.ZeroAddr, To: vm.RewardManagerPoolAddr, Amount: big.NewInt(10), Module: gov.ModuleReward, Reason: types.BalanceChangeIssuance},
+	}, chain.StateDB.BalanceChanges)
+}
+
+func TestBalanceChanges_RewardStaking(t *testing.T) {
+	chain := mock.NewChain()
+	state := chain.StateDB
+	benefits := []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")}
+	list := []*staking.Candidate{
+		{
+			CandidateBase: &staking.CandidateBase{BenefitAddress: benefits[0]},
+			CandidateMutable: &staking.CandidateMutable{RewardPer: 1000, DelegateTotal: big.NewInt(1),
+				CurrentEpochDelegateReward: new(big.Int)},
+		},
+		{
+			CandidateBase:    &staking.CandidateBase{BenefitAddress: benefits[1]},
+			CandidateMutable: &staking.CandidateMutable{DelegateTotal: new(big.Int), CurrentEpochDelegateReward: new(big.Int)},
+		},
+	}
+	state.AddBalance(vm.RewardManagerPoolAddr, big.NewInt(1000))
+	assert.Nil(t, RewardMgrInstance().rewardStakingByValidatorList(state, list, big.NewInt(200)))
+
+	// The pools are settled once, every validator share is recorded
+	assert.Equal(t, big.NewInt(800), state.GetBalance(vm.RewardManagerPoolAddr))
+	assert.Equal(t, big.NewInt(10), state.GetBalance(vm.DelegateRewardPoolAddr))
+	assert.Equal(t, big.NewInt(90), state.GetBalance(benefits[0]))
+	assert.Equal(t, big.NewInt(100), state.GetBalance(benefits[1]))
+	assert.Equal(t, []*types.BalanceChange{
+		{From: vm.RewardManagerPoolAddr, To: vm.DelegateRewardPoolAddr, Amount: big.NewInt(10), Module: gov.ModuleReward, Reason: types.BalanceChangeDelegateReward},
+		{From: vm.RewardManagerPoolAddr, To: benefits[0], Amount: big.NewInt(90), Module: gov.ModuleReward, Reason: types.BalanceChangeStakingReward},
+		{From: vm.RewardManagerPoolAddr, To: benefits[1], Amount: big.NewInt(100), Module: gov.ModuleReward, Reason: types.BalanceChangeStakingReward},
+	}, chain.StateDB.BalanceChanges)
+}
@@ -39,6 +39,13 @@ type BasePlugin interface {
 	Confirmed(nodeId discover.NodeID, block *types.Block) error
 }
 
+// SimulatedPlugin is implemented by the plugins beginning a simulated block
+// apart, the simulated blocks are never committed so the data of the blocks
+// before must be read at the block instead of from the irreversible ones.
+type SimulatedPlugin interface {
+	BeginSimulatedBlock(blockHash common.Hash, header *types.Header, state xcom.StateDB) error
+}
+
 var (
 	DecodeTxDataErr = errors.New("decode tx data is err")
 	FuncNotExistErr = errors.New("the func is not exist")
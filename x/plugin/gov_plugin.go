@@ -138,12 +138,26 @@ func (govPlugin *GovPlugin) BeginBlock(blockHash common.Hash, header *types.Head
 					log.Error("save  version 130 Param failed.", "blockNumber", blockNumber, "blockHash", blockHash, "preActiveProposalID", preActiveVersionProposalID, "err", err)
 					return err
 				}
-				if err := gov.WriteEcHash130(state); nil != err {
+				if err := gov.WriteEcHash130(blockNumber, state); nil != err {
 					log.Error("save EcHash130 to stateDB failed.", "blockNumber", blockNumber, "blockHash", blockHash, "preActiveProposalID", preActiveVersionProposalID)
 					return err
 				}
 				log.Info("Successfully upgraded the new version 1.3.0", "blockNumber", blockNumber, "blockHash", blockHash, "preActiveProposalID", preActiveVersionProposalID)
 			}
+			if versionProposal.NewVersion == params.FORKVERSION_1_4_0 {
+				if err = gov.Set140Param(header.Number.Uint64(), blockHash, snapshotdb.Instance(), govPlugin.chainDB); err != nil {
+					log.Error("save  version 140 Param failed.", "blockNumber", blockNumber, "blockHash", blockHash, "preActiveProposalID", preActiveVersionProposalID, "err", err)
+					return err
+				}
+				log.Info("Successfully upgraded the new version 1.4.0", "blockNumber", blockNumber, "blockHash", blockHash, "preActiveProposalID", preActiveVersionProposalID)
+			}
+			if versionProposal.NewVersion == params.FORKVERSION_1_5_0 {
+				if err = gov.Set150Param(header.Number.Uint64(), blockHash, snapshotdb.Instance()); err != nil {
+					log.Error("save  version 150 Param failed.", "blockNumber", blockNumber, "blockHash", blockHash, "preActiveProposalID", preActiveVersionProposalID, "err", err)
+					return err
+				}
+				log.Info("Successfully upgraded the new version 1.5.0", "blockNumber", blockNumber, "blockHash", blockHash, "preActiveProposalID", preActiveVersionProposalID)
+			}
 
 			log.Info("version proposal is active", "blockNumber", blockNumber, "proposalID", versionProposal.ProposalID, "newVersion", versionProposal.NewVersion, "newVersionString", xutil.ProgramVersion2Str(versionProposal.NewVersion))
 		}
@@ -157,7 +171,7 @@ func (govPlugin *GovPlugin) EndBlock(blockHash common.Hash, header *types.Header
 	//log.Debug("call EndBlock()", "blockNumber", blockNumber, "blockHash", blockHash)
 
 	//text/version/cancel proposal's end voting block is ElectionBlock
-	//param proposal's end voting block is end of Epoch
+	//param/allowlist proposal's end voting block is end of Epoch
 	isEndOfEpoch := false
 	isElection := false
 	if xutil.IsElection(blockNumber) {
@@ -208,6 +222,11 @@ func (govPlugin *GovPlugin) EndBlock(blockHash common.Hash, header *types.Header
 				if err != nil {
 					return err
 				}
+			} else if votingProposal.GetProposalType() == gov.Allowlist && isEndOfEpoch {
+				_, err := tallyAllowlist(votingProposal.(*gov.AllowlistProposal), blockHash, blockNumber, state)
+				if err != nil {
+					return err
+				}
 			} else {
 				log.Error("invalid proposal type", "type", votingProposal.GetProposalType())
 				return gov.ProposalTypeError
@@ -280,7 +299,7 @@ func tallyVersion(proposal *gov.VersionProposal, blockHash common.Hash, blockNum
 
 	//log.Debug("version proposal", "supportRate", supportRate, "required", Decimal(xcom.VersionProposalSupportRate()))
 
-	if supportRate >= xcom.VersionProposal_SupportRate() {
+	if supportRate >= xcom.VersionProposal_SupportRate(blockNumber) {
 		status = gov.PreActive
 
 		if err := gov.AddPIPID(proposal.GetPIPID(), state); err != nil {
@@ -423,9 +442,37 @@ func tallyParam(pp *gov.ParamProposal, blockHash common.Hash, blockNumber uint64
 	if pass, err := tally(gov.Param, pp.ProposalID, pp.PIPID, blockHash, blockNumber, state); err != nil {
 		return false, err
 	} else if pass {
-		if err := gov.UpdateGovernParamValue(pp.Module, pp.Name, pp.NewValue, blockNumber+1, blockHash); err != nil {
+		activeBlock := blockNumber + 1
+		if gov.IsBlockScheduleParam(pp.Module, pp.Name) {
+			if activeBlock, err = gov.UpdateBlockSchedule(pp.Name, pp.NewValue, blockNumber, blockHash); err != nil {
+				return false, err
+			}
+		}
+		if err := gov.UpdateGovernParamValue(pp.Module, pp.Name, pp.NewValue, activeBlock, blockHash); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func tallyAllowlist(ap *gov.AllowlistProposal, blockHash common.Hash, blockNumber uint64, state xcom.StateDB) (pass bool, err error) {
+	if pass, err := tally(gov.Allowlist, ap.ProposalID, ap.PIPID, blockHash, blockNumber, state); err != nil {
+		return false, err
+	} else if pass {
+		switch ap.Operation {
+		case gov.AllowlistAdd:
+			err = gov.AddAllowlistEntry(blockHash, &gov.AllowlistEntry{NodeId: ap.NodeId, StakingAddress: ap.StakingAddress})
+		case gov.AllowlistRemove:
+			var nodeAddr common.NodeAddress
+			if nodeAddr, err = xutil.NodeId2Addr(ap.NodeId); err == nil {
+				err = gov.RemoveAllowlistEntry(blockHash, nodeAddr)
+			}
+		}
+		if err != nil {
 			return false, err
 		}
+		log.Info("allowlist proposal passed", "blockNumber", blockNumber, "blockHash", blockHash, "proposalID", ap.ProposalID,
+			"operation", ap.Operation, "nodeId", ap.NodeId.TerminalString(), "stakingAddress", ap.StakingAddress)
 	}
 	return true, nil
 }
@@ -456,21 +503,21 @@ func tally(proposalType gov.ProposalType, proposalID common.Hash, pipID string,
 	switch proposalType {
 	case gov.Text:
 		//log.Debug("text proposal", "voteRate", voteRate, "required", xcom.TextProposalVoteRate(), "supportRate", supportRate, "required", Decimal(xcom.TextProposalSupportRate()))
-		if voteRate > xcom.TextProposal_VoteRate() && supportRate >= xcom.TextProposal_SupportRate() {
+		if voteRate > xcom.TextProposal_VoteRate(blockNumber) && supportRate >= xcom.TextProposal_SupportRate(blockNumber) {
 			status = gov.Pass
 		} else {
 			status = gov.Failed
 		}
 	case gov.Cancel:
 		//log.Debug("cancel proposal", "voteRate", voteRate, "required", xcom.CancelProposalVoteRate(), "supportRate", supportRate, "required", Decimal(xcom.CancelProposalSupportRate()))
-		if voteRate > xcom.CancelProposal_VoteRate() && supportRate >= xcom.CancelProposal_SupportRate() {
+		if voteRate > xcom.CancelProposal_VoteRate(blockNumber) && supportRate >= xcom.CancelProposal_SupportRate(blockNumber) {
 			status = gov.Pass
 		} else {
 			status = gov.Failed
 		}
-	case gov.Param:
+	case gov.Param, gov.Allowlist:
 		//log.Debug("param proposal", "voteRate", voteRate, "required", xcom.ParamProposalVoteRate(), "supportRate", supportRate, "required", Decimal(xcom.ParamProposalSupportRate()))
-		if voteRate > xcom.ParamProposal_VoteRate() && supportRate >= xcom.ParamProposal_SupportRate() {
+		if voteRate > xcom.ParamProposal_VoteRate(blockNumber) && supportRate >= xcom.ParamProposal_SupportRate(blockNumber) {
 			status = gov.Pass
 		} else {
 			status = gov.Failed
@@ -0,0 +1,228 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package plugin
+
+import (
+	"math/big"
+	"math/bits"
+	"sync"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/core/snapshotdb"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/log"
+	"github.com/hashkey-chain/hashkey-chain/metrics"
+	"github.com/hashkey-chain/hashkey-chain/metrics/prometheus"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+	"github.com/hashkey-chain/hashkey-chain/x/gov"
+	"github.com/hashkey-chain/hashkey-chain/x/xcom"
+	"github.com/hashkey-chain/hashkey-chain/x/xutil"
+)
+
+var (
+	epochGauge       = metrics.NewRegisteredGauge("ppos/gauge/epoch", nil)
+	roundGauge       = metrics.NewRegisteredGauge("ppos/gauge/round", nil)
+	yearGauge        = metrics.NewRegisteredGauge("ppos/gauge/year", nil)
+	blockNumberGauge = metrics.NewRegisteredGauge("ppos/gauge/block/number", nil)
+)
+
+// The names of the labeled PPOS gauges
+const (
+	candidateStatusGauge        = "ppos/candidate/status"
+	candidateSharesGauge        = "ppos/candidate/shares"
+	candidateDelegateTotalGauge = "ppos/candidate/delegate_total"
+	candidateRewardPerGauge     = "ppos/candidate/reward_per"
+	candidateNextRewardPerGauge = "ppos/candidate/next_reward_per"
+
+	roundBlocksProducedGauge = "ppos/round/blocks_produced"
+	roundBlocksExpectedGauge = "ppos/round/blocks_expected"
+
+	slashingZeroProduceRoundsGauge = "ppos/slashing/zero_produce_rounds"
+	slashingFirstZeroRoundGauge    = "ppos/slashing/first_zero_produce_round"
+
+	proposalYeasGauge           = "ppos/proposal/yeas"
+	proposalNaysGauge           = "ppos/proposal/nays"
+	proposalAbstentionsGauge    = "ppos/proposal/abstentions"
+	proposalAccuVerifiersGauge  = "ppos/proposal/accu_verifiers"
+	proposalEndVotingBlockGauge = "ppos/proposal/end_voting_block"
+)
+
+var proposalTypeNames = map[gov.ProposalType]string{
+	gov.Text:      "text",
+	gov.Version:   "version",
+	gov.Param:     "param",
+	gov.Cancel:    "cancel",
+	gov.Allowlist: "allowlist",
+}
+
+// PPOSCollector exports the PPOS state of the last executed block, the
+// gauges of the candidates, validators and proposals are labeled per node or
+// per proposal.
+type PPOSCollector struct {
+	lock   sync.RWMutex
+	nodeId discover.NodeID
+	gauges map[string][]prometheus.Sample
+}
+
+var (
+	pposCollectorOnce sync.Once
+	pposCollector     *PPOSCollector
+)
+
+// PPOSCollectorInstance returns the PPOS collector, it is registered with the
+// Prometheus endpoint if the metrics are enabled.
+func PPOSCollectorInstance() *PPOSCollector {
+	pposCollectorOnce.Do(func() {
+		pposCollector = &PPOSCollector{gauges: make(map[string][]prometheus.Sample)}
+		if metrics.Enabled {
+			prometheus.RegisterLabeled(pposCollector)
+		}
+	})
+	return pposCollector
+}
+
+// SetNodeID sets the node whose candidate is reported.
+func (pc *PPOSCollector) SetNodeID(nodeId discover.NodeID) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+	pc.nodeId = nodeId
+}
+
+// Collect implements prometheus.LabeledCollector.
+func (pc *PPOSCollector) Collect() map[string][]prometheus.Sample {
+	pc.lock.RLock()
+	defer pc.lock.RUnlock()
+	gauges := make(map[string][]prometheus.Sample, len(pc.gauges))
+	for name, samples := range pc.gauges {
+		gauges[name] = samples
+	}
+	return gauges
+}
+
+// Update refreshes the gauges with the state at the end of the block. The
+// failures are only logged, the metrics never affect the block processing.
+func (pc *PPOSCollector) Update(blockHash common.Hash, header *types.Header, state xcom.StateDB) {
+	blockNumber := header.Number.Uint64()
+	blockNumberGauge.Update(int64(blockNumber))
+	epochGauge.Update(int64(xutil.CalculateEpoch(blockNumber)))
+	roundGauge.Update(int64(xutil.CalculateRound(blockNumber)))
+	if year, err := LoadChainYearNumber(blockHash, snapshotdb.Instance()); nil == err {
+		yearGauge.Update(int64(year))
+	}
+
+	pc.lock.RLock()
+	nodeId := pc.nodeId
+	pc.lock.RUnlock()
+
+	gauges := make(map[string][]prometheus.Sample)
+	add := func(name string, labels map[string]string, value float64) {
+		gauges[name] = append(gauges[name], prometheus.Sample{Labels: labels, Value: value})
+	}
+
+	// The candidate of the current node
+	if nodeId != (discover.NodeID{}) {
+		if addr, err := xutil.NodeId2Addr(nodeId); nil == err {
+			can, err := StakingInstance().GetCandidateInfo(blockHash, addr)
+			switch {
+			case nil == err && nil != can:
+				labels := map[string]string{"node_id": nodeId.String()}
+				add(candidateStatusGauge, labels, float64(can.Status))
+				add(candidateSharesGauge, labels, bigToFloat(can.Shares))
+				add(candidateDelegateTotalGauge, labels, bigToFloat(can.DelegateTotal))
+				add(candidateRewardPerGauge, labels, float64(can.RewardPer))
+				add(candidateNextRewardPerGauge, labels, float64(can.NextRewardPer))
+			case snapshotdb.NonDbNotFoundErr(err):
+				log.Warn("Failed to collect the candidate metrics", "blockNumber", blockNumber, "nodeId", nodeId.TerminalString(), "err", err)
+			}
+		}
+	}
+
+	// The blocks produced by the validators of the current round
+	if vals, err := StakingInstance().getCurrValList(blockHash, blockNumber, QueryStartNotIrr); nil == err {
+		for _, val := range vals.Arr {
+			amount, err := SlashInstance().getPackAmount(blockNumber, blockHash, val.NodeId)
+			if nil != err {
+				continue
+			}
+			labels := map[string]string{"node_id": val.NodeId.String()}
+			add(roundBlocksProducedGauge, labels, float64(amount))
+			add(roundBlocksExpectedGauge, labels, float64(xcom.BlockScheduleAt(blockNumber).PerRoundBlocks))
+		}
+	} else {
+		log.Warn("Failed to collect the validator metrics", "blockNumber", blockNumber, "err", err)
+	}
+
+	// The nodes waiting for the zero production slashing
+	if list, err := SlashInstance().getWaitSlashingNodeList(blockNumber, blockHash); nil == err {
+		for _, node := range list {
+			labels := map[string]string{"node_id": node.NodeId.String()}
+			add(slashingZeroProduceRoundsGauge, labels, float64(bits.OnesCount64(node.CountBit)))
+			add(slashingFirstZeroRoundGauge, labels, float64(node.Round))
+		}
+	} else {
+		log.Warn("Failed to collect the slashing metrics", "blockNumber", blockNumber, "err", err)
+	}
+
+	// The voting progress of the active proposals
+	if ids, err := gov.ListVotingProposalID(blockHash); nil == err {
+		for _, id := range ids {
+			proposal, err := gov.GetProposal(id, state)
+			if nil != err || nil == proposal {
+				continue
+			}
+			labels := map[string]string{
+				"proposal_id": id.Hex(),
+				"pip_id":      proposal.GetPIPID(),
+				"type":        proposalTypeNames[proposal.GetProposalType()],
+			}
+			var yeas, nays, abstentions float64
+			if votes, err := gov.ListVoteValue(id, blockHash); nil == err {
+				for _, vote := range votes {
+					switch vote.VoteOption {
+					case gov.Yes:
+						yeas++
+					case gov.No:
+						nays++
+					case gov.Abstention:
+						abstentions++
+					}
+				}
+			}
+			add(proposalYeasGauge, labels, yeas)
+			add(proposalNaysGauge, labels, nays)
+			add(proposalAbstentionsGauge, labels, abstentions)
+			if accu, err := gov.ListAccuVerifier(blockHash, id); nil == err {
+				add(proposalAccuVerifiersGauge, labels, float64(len(accu)))
+			}
+			add(proposalEndVotingBlockGauge, labels, float64(proposal.GetEndVotingBlock()))
+		}
+	} else {
+		log.Warn("Failed to collect the proposal metrics", "blockNumber", blockNumber, "err", err)
+	}
+
+	pc.lock.Lock()
+	pc.gauges = gauges
+	pc.lock.Unlock()
+}
+
+func bigToFloat(value *big.Int) float64 {
+	if nil == value {
+		return 0
+	}
+	f, _ := new(big.Float).SetInt(value).Float64()
+	return f
+}
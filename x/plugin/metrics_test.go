@@ -0,0 +1,54 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/metrics/prometheus"
+)
+
+func TestPPOSCollector(t *testing.T) {
+	defer setup(t)()
+	submitText(t, txHashArr[0])
+
+	collector := PPOSCollectorInstance()
+	collector.SetNodeID(nodeIdArr[0])
+	collector.Update(lastBlockHash, &types.Header{Number: blockNumber}, stateDB)
+	gauges := collector.Collect()
+
+	nodeLabels := map[string]string{"node_id": nodeIdArr[0].String()}
+	if assert.Len(t, gauges[candidateStatusGauge], 1) {
+		assert.Equal(t, nodeLabels, gauges[candidateStatusGauge][0].Labels)
+		assert.True(t, gauges[candidateSharesGauge][0].Value > 0)
+	}
+
+	vals, err := StakingInstance().getCurrValList(lastBlockHash, lastBlockNumber, QueryStartNotIrr)
+	assert.Nil(t, err)
+	assert.Len(t, gauges[roundBlocksExpectedGauge], len(vals.Arr))
+	assert.Len(t, gauges[roundBlocksProducedGauge], len(vals.Arr))
+
+	if assert.Len(t, gauges[proposalYeasGauge], 1) {
+		assert.Equal(t, map[string]string{"proposal_id": txHashArr[0].Hex(), "pip_id": "textPIPID", "type": "text"},
+			gauges[proposalYeasGauge][0].Labels)
+		assert.Equal(t, prometheus.Sample{Labels: gauges[proposalYeasGauge][0].Labels, Value: 0}, gauges[proposalNaysGauge][0])
+	}
+	assert.Empty(t, gauges[slashingZeroProduceRoundsGauge])
+}
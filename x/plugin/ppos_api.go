@@ -0,0 +1,293 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/common/hexutil"
+	"github.com/hashkey-chain/hashkey-chain/core/snapshotdb"
+	"github.com/hashkey-chain/hashkey-chain/core/state"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+	"github.com/hashkey-chain/hashkey-chain/rpc"
+	"github.com/hashkey-chain/hashkey-chain/x/gov"
+	"github.com/hashkey-chain/hashkey-chain/x/restricting"
+	"github.com/hashkey-chain/hashkey-chain/x/reward"
+	"github.com/hashkey-chain/hashkey-chain/x/staking"
+	"github.com/hashkey-chain/hashkey-chain/x/xutil"
+)
+
+// maxCandidatePageSize is the maximum number of candidates returned by a
+// page of ppos_listCandidates.
+const maxCandidatePageSize = 100
+
+// PPOSBackend is the chain access needed by the PPOS query API to resolve the
+// block selectors.
+type PPOSBackend interface {
+	HeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*types.Header, error)
+	StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error)
+}
+
+// PublicPPOSQueryAPI provides the read-only queries of the PPOS data in the
+// ppos namespace, the results are read from the plugins at the selected block.
+type PublicPPOSQueryAPI struct {
+	b PPOSBackend
+}
+
+// NewPublicPPOSQueryAPI creates the PPOS query API on the backend.
+func NewPublicPPOSQueryAPI(b PPOSBackend) *PublicPPOSQueryAPI {
+	return &PublicPPOSQueryAPI{b: b}
+}
+
+func (api *PublicPPOSQueryAPI) header(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*types.Header, error) {
+	header, err := api.b.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errors.New("header not found")
+	}
+	return header, nil
+}
+
+func (api *PublicPPOSQueryAPI) stateAndHeader(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	state, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if state == nil || header == nil {
+		return nil, nil, errors.New("state not found")
+	}
+	return state, header, nil
+}
+
+// isNotFound reports whether the error means the queried data doesn't exist.
+func isNotFound(err error) bool {
+	return snapshotdb.IsDbNotFoundErr(err) || err == staking.ErrValidatorNoExist
+}
+
+// GetCandidate returns the candidate of the node, nil if the node is not a
+// candidate.
+func (api *PublicPPOSQueryAPI) GetCandidate(ctx context.Context, nodeId discover.NodeID, blockNrOrHash rpc.BlockNumberOrHash) (*staking.CandidateHex, error) {
+	header, err := api.header(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	canAddr, err := xutil.NodeId2Addr(nodeId)
+	if err != nil {
+		return nil, err
+	}
+	can, err := StakingInstance().GetCandidateCompactInfo(header.Hash(), header.Number.Uint64(), canAddr)
+	if isNotFound(err) || (err == nil && can.IsEmpty()) {
+		return nil, nil
+	}
+	return can, err
+}
+
+// CandidatePage is a page of the candidates.
+type CandidatePage struct {
+	Total      int                       `json:"total"`
+	Candidates staking.CandidateHexQueue `json:"candidates"`
+}
+
+// ListCandidates returns the page of the candidates starting at the offset,
+// with at most limit candidates.
+func (api *PublicPPOSQueryAPI) ListCandidates(ctx context.Context, offset, limit hexutil.Uint64, blockNrOrHash rpc.BlockNumberOrHash) (*CandidatePage, error) {
+	if limit == 0 || limit > maxCandidatePageSize {
+		return nil, fmt.Errorf("invalid limit %d, should be in [1, %d]", limit, maxCandidatePageSize)
+	}
+	header, err := api.header(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	list, err := StakingInstance().GetCandidateList(header.Hash(), header.Number.Uint64())
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	page := &CandidatePage{Total: len(list), Candidates: staking.CandidateHexQueue{}}
+	if uint64(offset) < uint64(len(list)) {
+		end := uint64(offset) + uint64(limit)
+		if end > uint64(len(list)) {
+			end = uint64(len(list))
+		}
+		page.Candidates = list[offset:end]
+	}
+	return page, nil
+}
+
+// GetDelegations returns the delegations of the account.
+func (api *PublicPPOSQueryAPI) GetDelegations(ctx context.Context, delAddr common.Address, blockNrOrHash rpc.BlockNumberOrHash) ([]*staking.DelegationEx, error) {
+	header, err := api.header(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	blockHash, blockNumber := header.Hash(), header.Number.Uint64()
+	related, err := StakingInstance().GetRelatedListByDelAddr(blockHash, delAddr)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	dels := make([]*staking.DelegationEx, 0, len(related))
+	for _, r := range related {
+		del, err := StakingInstance().GetDelegateExCompactInfo(blockHash, blockNumber, r.Addr, r.NodeId, r.StakingBlockNum)
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		dels = append(dels, del)
+	}
+	return dels, nil
+}
+
+// GetDelegationLock returns the locked delegations of the account.
+func (api *PublicPPOSQueryAPI) GetDelegationLock(ctx context.Context, delAddr common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*staking.DelegationLockHex, error) {
+	header, err := api.header(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return StakingInstance().GetGetDelegationLockCompactInfo(header.Hash(), header.Number.Uint64(), delAddr)
+}
+
+// ValidatorList is the validators of a consensus round or an epoch.
+type ValidatorList struct {
+	Start      uint64                   `json:"start"`
+	End        uint64                   `json:"end"`
+	Validators staking.ValidatorExQueue `json:"validators"`
+}
+
+// GetValidatorsByRound returns the validators of the consensus round, nil if
+// the round is not recorded at the block. Only the rounds around the block
+// are recorded.
+func (api *PublicPPOSQueryAPI) GetValidatorsByRound(ctx context.Context, round hexutil.Uint64, blockNrOrHash rpc.BlockNumberOrHash) (*ValidatorList, error) {
+	if round == 0 {
+		return nil, errors.New("invalid round 0")
+	}
+	header, err := api.header(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	start, end := xutil.CalcRoundEndBlock(uint64(round)-1)+1, xutil.CalcRoundEndBlock(uint64(round))
+	vals, err := StakingInstance().GetValidatorList(header.Hash(), start, CurrentRound, QueryStartNotIrr)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorList{Start: start, End: end, Validators: vals}, nil
+}
+
+// GetValidatorsByEpoch returns the verifiers of the epoch, nil if the epoch
+// is not recorded at the block. Only the epochs around the block are recorded.
+func (api *PublicPPOSQueryAPI) GetValidatorsByEpoch(ctx context.Context, epoch hexutil.Uint64, blockNrOrHash rpc.BlockNumberOrHash) (*ValidatorList, error) {
+	if epoch == 0 {
+		return nil, errors.New("invalid epoch 0")
+	}
+	header, err := api.header(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	start, end := xutil.CalcEpochEndBlock(uint64(epoch)-1)+1, xutil.CalcEpochEndBlock(uint64(epoch))
+	vals, err := StakingInstance().GetVerifierList(header.Hash(), start, QueryStartNotIrr)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorList{Start: start, End: end, Validators: vals}, nil
+}
+
+// ProposalResult is a proposal with its tally result.
+type ProposalResult struct {
+	Proposal    gov.Proposal     `json:"proposal"`
+	TallyResult *gov.TallyResult `json:"tallyResult"`
+}
+
+// GetProposal returns the proposal and its tally result, the tally result is
+// nil until the voting ends. Nil is returned if the proposal doesn't exist.
+func (api *PublicPPOSQueryAPI) GetProposal(ctx context.Context, proposalID common.Hash, blockNrOrHash rpc.BlockNumberOrHash) (*ProposalResult, error) {
+	state, _, err := api.stateAndHeader(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	proposal, err := gov.GetProposal(proposalID, state)
+	if err != nil {
+		return nil, err
+	}
+	if proposal == nil {
+		return nil, nil
+	}
+	tally, err := gov.GetTallyResult(proposalID, state)
+	if err != nil {
+		return nil, err
+	}
+	return &ProposalResult{Proposal: proposal, TallyResult: tally}, nil
+}
+
+// ListVotes returns the votes of the proposal, the votes are only kept until
+// the voting ends.
+func (api *PublicPPOSQueryAPI) ListVotes(ctx context.Context, proposalID common.Hash, blockNrOrHash rpc.BlockNumberOrHash) ([]gov.VoteValue, error) {
+	header, err := api.header(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	votes, err := gov.ListVoteValue(proposalID, header.Hash())
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	if votes == nil {
+		votes = []gov.VoteValue{}
+	}
+	return votes, nil
+}
+
+// GetRestrictingInfo returns the restricting plans of the account, nil if
+// the account has no restricting plans.
+func (api *PublicPPOSQueryAPI) GetRestrictingInfo(ctx context.Context, account common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*restricting.Result, error) {
+	state, _, err := api.stateAndHeader(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	result, bizErr := RestrictingInstance().GetRestrictingInfo(account, state)
+	if bizErr == restricting.ErrAccountNotFound {
+		return nil, nil
+	}
+	if bizErr != nil {
+		return nil, bizErr
+	}
+	return result, nil
+}
+
+// GetDelegateReward returns the unclaimed delegate rewards of the account,
+// nodeIDs filters the delegated nodes, all nodes if it is empty.
+func (api *PublicPPOSQueryAPI) GetDelegateReward(ctx context.Context, account common.Address, nodeIDs []discover.NodeID, blockNrOrHash rpc.BlockNumberOrHash) ([]reward.NodeDelegateRewardPresenter, error) {
+	state, header, err := api.stateAndHeader(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	rewards, err := RewardMgrInstance().GetDelegateReward(header.Hash(), header.Number.Uint64(), account, nodeIDs, state)
+	if err == reward.ErrDelegationNotFound {
+		return []reward.NodeDelegateRewardPresenter{}, nil
+	}
+	return rewards, err
+}
@@ -0,0 +1,95 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hashkey-chain/hashkey-chain/common/hexutil"
+	"github.com/hashkey-chain/hashkey-chain/core/snapshotdb"
+	"github.com/hashkey-chain/hashkey-chain/core/state"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/rpc"
+)
+
+type mockPPOSBackend struct {
+	header *types.Header
+}
+
+func (b *mockPPOSBackend) HeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*types.Header, error) {
+	return b.header, nil
+}
+
+func (b *mockPPOSBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
+	return nil, b.header, nil
+}
+
+func TestPublicPPOSQueryAPI_Candidates(t *testing.T) {
+	state, genesis, err := newChainState()
+	if nil != err {
+		t.Fatal("Failed to build the state", err)
+	}
+	newPlugins()
+	build_gov_data(state)
+
+	sndb := snapshotdb.Instance()
+	defer sndb.Clear()
+
+	header := &types.Header{ParentHash: genesis.Hash(), Number: blockNumber}
+	if err := sndb.NewBlock(blockNumber, genesis.Hash(), header.Hash()); nil != err {
+		t.Fatal("newBlock err", err)
+	}
+	for i := 0; i < 4; i++ {
+		if err := create_staking(state, blockNumber, header.Hash(), i, 0, t); nil != err {
+			t.Fatal("Failed to Create Staking", err)
+		}
+	}
+	if err := sndb.Commit(header.Hash()); nil != err {
+		t.Fatal("Commit err", err)
+	}
+
+	api := NewPublicPPOSQueryAPI(&mockPPOSBackend{header: header})
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	can, err := api.GetCandidate(context.Background(), nodeIdArr[0], latest)
+	assert.Nil(t, err)
+	if assert.NotNil(t, can) {
+		assert.Equal(t, nodeIdArr[0], can.NodeId)
+	}
+	can, err = api.GetCandidate(context.Background(), nodeIdArr[len(nodeIdArr)-1], latest)
+	assert.Nil(t, err)
+	assert.Nil(t, can)
+
+	page, err := api.ListCandidates(context.Background(), 0, 2, latest)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, page.Total)
+	assert.Len(t, page.Candidates, 2)
+
+	page, err = api.ListCandidates(context.Background(), 3, 2, latest)
+	assert.Nil(t, err)
+	assert.Len(t, page.Candidates, 1)
+
+	page, err = api.ListCandidates(context.Background(), 4, 2, latest)
+	assert.Nil(t, err)
+	assert.Len(t, page.Candidates, 0)
+
+	_, err = api.ListCandidates(context.Background(), 0, hexutil.Uint64(maxCandidatePageSize+1), latest)
+	assert.NotNil(t, err)
+}
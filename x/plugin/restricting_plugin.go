@@ -148,9 +148,8 @@ func (rp *RestrictingPlugin) initEpochInfo(state xcom.StateDB, epoch uint64, acc
 	rp.storeAmount2ReleaseAmount(state, epoch, account, amount)
 }
 
-func (rp *RestrictingPlugin) transferAmount(state xcom.StateDB, from, to common.Address, mount *big.Int) {
-	state.SubBalance(from, mount)
-	state.AddBalance(to, mount)
+func (rp *RestrictingPlugin) transferAmount(state xcom.StateDB, from, to common.Address, mount *big.Int, reason types.BalanceChangeReason) {
+	transferBalance(state, from, to, mount, gov.ModuleRestricting, reason)
 }
 
 // update genesis restricting plans
@@ -180,13 +179,11 @@ func (rp *RestrictingPlugin) InitGenesisRestrictingPlans(statedb xcom.StateDB) e
 
 	//initial release from genesis restricting plans(62215742LAT)
 	initialRelease := new(big.Int).Mul(big.NewInt(62215742), big.NewInt(1e18))
-	statedb.SubBalance(xcom.CDFAccount(), initialRelease)
-	statedb.AddBalance(vm.RewardManagerPoolAddr, initialRelease)
+	rp.transferAmount(statedb, xcom.CDFAccount(0), vm.RewardManagerPoolAddr, initialRelease, types.BalanceChangeGenesisAllowance)
 
 	//transfer 259096239LAT from CDFAccount to vm.RestrictingContractAddr
 	totalRestrictingPlan := new(big.Int).Mul(big.NewInt(259096239), big.NewInt(1e18))
-	statedb.SubBalance(xcom.CDFAccount(), totalRestrictingPlan)
-	statedb.AddBalance(vm.RestrictingContractAddr, totalRestrictingPlan)
+	rp.transferAmount(statedb, xcom.CDFAccount(0), vm.RestrictingContractAddr, totalRestrictingPlan, types.BalanceChangeRestrictingLock)
 
 	if err := rp.updateGenesisRestrictingPlans(genesisAllowancePlans, statedb); nil != err {
 		return err
@@ -207,8 +204,7 @@ func (rp *RestrictingPlugin) releaseGenesisRestrictingPlans(blockHash common.Has
 			remains := len(genesisAllowancePlans)
 			if remains > 0 {
 				allowance := genesisAllowancePlans[0]
-				statedb.SubBalance(vm.RestrictingContractAddr, allowance)
-				statedb.AddBalance(vm.RewardManagerPoolAddr, allowance)
+				rp.transferAmount(statedb, vm.RestrictingContractAddr, vm.RewardManagerPoolAddr, allowance, types.BalanceChangeGenesisAllowance)
 				rp.log.Info("Genesis restricting plan release", "remains", remains, "allowance", allowance)
 				genesisAllowancePlans = append(genesisAllowancePlans[:0], genesisAllowancePlans[1:]...)
 				if err := rp.updateGenesisRestrictingPlans(genesisAllowancePlans, statedb); nil != err {
@@ -259,7 +255,7 @@ func (rp *RestrictingPlugin) AddRestrictingRecord(from, account common.Address,
 		restrictInfo restricting.RestrictingInfo
 	)
 
-	rp.transferAmount(state, from, vm.RestrictingContractAddr, totalAmount)
+	rp.transferAmount(state, from, vm.RestrictingContractAddr, totalAmount, types.BalanceChangeRestrictingLock)
 
 	restrictingKey, restrictInfoByte := rp.getRestrictingInfo(state, account)
 	if len(restrictInfoByte) == 0 {
@@ -281,9 +277,9 @@ func (rp *RestrictingPlugin) AddRestrictingRecord(from, account common.Address,
 		if restrictInfo.NeedRelease.Cmp(common.Big0) > 0 {
 			if restrictInfo.NeedRelease.Cmp(totalAmount) >= 0 {
 				restrictInfo.NeedRelease.Sub(restrictInfo.NeedRelease, totalAmount)
-				rp.transferAmount(state, vm.RestrictingContractAddr, account, totalAmount)
+				rp.transferAmount(state, vm.RestrictingContractAddr, account, totalAmount, types.BalanceChangeRestrictingRelease)
 			} else {
-				rp.transferAmount(state, vm.RestrictingContractAddr, account, restrictInfo.NeedRelease)
+				rp.transferAmount(state, vm.RestrictingContractAddr, account, restrictInfo.NeedRelease, types.BalanceChangeRestrictingRelease)
 				totalAmount.Sub(totalAmount, restrictInfo.NeedRelease)
 				restrictInfo.CachePlanAmount.Add(restrictInfo.CachePlanAmount, totalAmount)
 				restrictInfo.NeedRelease = new(big.Int).SetInt64(0)
@@ -344,7 +340,7 @@ func (rp *RestrictingPlugin) AdvanceLockedFunds(account common.Address, amount *
 
 	// save restricting account info
 	rp.storeRestrictingInfo(state, restrictingKey, restrictInfo)
-	rp.transferAmount(state, vm.RestrictingContractAddr, vm.StakingContractAddr, amount)
+	rp.transferAmount(state, vm.RestrictingContractAddr, vm.StakingContractAddr, amount, types.BalanceChangeRestrictingPledge)
 
 	rp.log.Debug("Call AdvanceLockedFunds finished", "RestrictingContractBalance", state.GetBalance(vm.RestrictingContractAddr), "StakingContractBalance", state.GetBalance(vm.StakingContractAddr), "new info", restrictInfo)
 	return nil
@@ -361,7 +357,7 @@ func (rp *RestrictingPlugin) MixAdvanceLockedFunds(account common.Address, amoun
 			if origin.Cmp(amount) < 0 {
 				return nil, nil, staking.ErrAccountVonNoEnough
 			}
-			rp.transferAmount(state, account, vm.StakingContractAddr, amount)
+			rp.transferAmount(state, account, vm.StakingContractAddr, amount, types.BalanceChangeRestrictingPledge)
 			return new(big.Int), new(big.Int).Set(amount), nil
 		} else {
 			return nil, nil, err
@@ -392,13 +388,13 @@ func (rp *RestrictingPlugin) MixAdvanceLockedFunds(account common.Address, amoun
 	if canStakingRestricting.Cmp(amount) < 0 {
 		forRestricting.Set(canStakingRestricting)
 		forFree = new(big.Int).Sub(amount, canStakingRestricting)
-		rp.transferAmount(state, account, vm.StakingContractAddr, forFree)
+		rp.transferAmount(state, account, vm.StakingContractAddr, forFree, types.BalanceChangeRestrictingPledge)
 	}
 
 	restrictInfo.AdvanceAmount.Add(restrictInfo.AdvanceAmount, forRestricting)
 	// save restricting account info
 	rp.storeRestrictingInfo(state, restrictingKey, restrictInfo)
-	rp.transferAmount(state, vm.RestrictingContractAddr, vm.StakingContractAddr, forRestricting)
+	rp.transferAmount(state, vm.RestrictingContractAddr, vm.StakingContractAddr, forRestricting, types.BalanceChangeRestrictingPledge)
 
 	rp.log.Debug("Call mixAdvanceLockedFunds finished", "RestrictingContractBalance", state.GetBalance(vm.RestrictingContractAddr), "StakingContractBalance", state.GetBalance(vm.StakingContractAddr), "new info", restrictInfo, "for free", forFree, "for restricting", forRestricting)
 	return forRestricting, forFree, nil
@@ -422,14 +418,14 @@ func (rp *RestrictingPlugin) ReturnLockFunds(account common.Address, amount *big
 		return restricting.ErrStakingAmountInvalid
 	}
 
-	rp.transferAmount(state, vm.StakingContractAddr, vm.RestrictingContractAddr, amount)
+	rp.transferAmount(state, vm.StakingContractAddr, vm.RestrictingContractAddr, amount, types.BalanceChangeRestrictingReturn)
 	if restrictInfo.NeedRelease.Cmp(common.Big0) > 0 {
 		if restrictInfo.NeedRelease.Cmp(amount) >= 0 {
 			restrictInfo.NeedRelease.Sub(restrictInfo.NeedRelease, amount)
 			restrictInfo.CachePlanAmount.Sub(restrictInfo.CachePlanAmount, amount)
-			rp.transferAmount(state, vm.RestrictingContractAddr, account, amount)
+			rp.transferAmount(state, vm.RestrictingContractAddr, account, amount, types.BalanceChangeRestrictingRelease)
 		} else {
-			rp.transferAmount(state, vm.RestrictingContractAddr, account, restrictInfo.NeedRelease)
+			rp.transferAmount(state, vm.RestrictingContractAddr, account, restrictInfo.NeedRelease, types.BalanceChangeRestrictingRelease)
 			restrictInfo.CachePlanAmount.Sub(restrictInfo.CachePlanAmount, restrictInfo.NeedRelease)
 			restrictInfo.NeedRelease = big.NewInt(0)
 		}
@@ -582,11 +578,11 @@ func (rp *RestrictingPlugin) releaseRestricting(epoch uint64, state xcom.StateDB
 		} else {
 			canRelease := new(big.Int).Sub(restrictInfo.CachePlanAmount, restrictInfo.AdvanceAmount)
 			if canRelease.Cmp(releaseAmount) >= 0 {
-				rp.transferAmount(state, vm.RestrictingContractAddr, account, releaseAmount)
+				rp.transferAmount(state, vm.RestrictingContractAddr, account, releaseAmount, types.BalanceChangeRestrictingRelease)
 				restrictInfo.CachePlanAmount.Sub(restrictInfo.CachePlanAmount, releaseAmount)
 			} else {
 				needRelease := new(big.Int).Sub(releaseAmount, canRelease)
-				rp.transferAmount(state, vm.RestrictingContractAddr, account, canRelease)
+				rp.transferAmount(state, vm.RestrictingContractAddr, account, canRelease, types.BalanceChangeRestrictingRelease)
 				restrictInfo.NeedRelease.Add(restrictInfo.NeedRelease, needRelease)
 				restrictInfo.CachePlanAmount.Sub(restrictInfo.CachePlanAmount, canRelease)
 			}
@@ -655,5 +651,5 @@ func (rp *RestrictingPlugin) GetRestrictingInfo(account common.Address, state xc
 }
 
 func GetBlockNumberByEpoch(epoch uint64) uint64 {
-	return epoch * xutil.CalcBlocksEachEpoch()
+	return xutil.CalcEpochEndBlock(epoch)
 }
@@ -157,25 +157,25 @@ func (rmp *RewardMgrPlugin) SetCurrentNodeID(nodeId discover.NodeID) {
 	rmp.nodeADD = add
 }
 
-func (rmp *RewardMgrPlugin) isLessThanFoundationYear(thisYear uint32) bool {
-	if thisYear < xcom.PlatONFoundationYear()-1 {
+func (rmp *RewardMgrPlugin) isLessThanFoundationYear(thisYear uint32, blockNumber uint64) bool {
+	if thisYear < xcom.PlatONFoundationYear(blockNumber)-1 {
 		return true
 	}
 	return false
 }
 
-func (rmp *RewardMgrPlugin) addPlatONFoundation(state xcom.StateDB, currIssuance *big.Int, allocateRate uint32) {
+func (rmp *RewardMgrPlugin) addPlatONFoundation(state xcom.StateDB, currIssuance *big.Int, allocateRate uint32, blockNumber uint64) {
 	platonFoundationIncr := percentageCalculation(currIssuance, uint64(allocateRate))
-	state.AddBalance(xcom.PlatONFundAccount(), platonFoundationIncr)
+	issueBalance(state, xcom.PlatONFundAccount(blockNumber), platonFoundationIncr, gov.ModuleReward, types.BalanceChangeIssuance)
 }
 
-func (rmp *RewardMgrPlugin) addCommunityDeveloperFoundation(state xcom.StateDB, currIssuance *big.Int, allocateRate uint32) {
+func (rmp *RewardMgrPlugin) addCommunityDeveloperFoundation(state xcom.StateDB, currIssuance *big.Int, allocateRate uint32, blockNumber uint64) {
 	developerFoundationIncr := percentageCalculation(currIssuance, uint64(allocateRate))
-	state.AddBalance(xcom.CDFAccount(), developerFoundationIncr)
+	issueBalance(state, xcom.CDFAccount(blockNumber), developerFoundationIncr, gov.ModuleReward, types.BalanceChangeIssuance)
 }
 func (rmp *RewardMgrPlugin) addRewardPoolIncreaseIssuance(state xcom.StateDB, currIssuance *big.Int, allocateRate uint32) {
 	rewardpoolIncr := percentageCalculation(currIssuance, uint64(allocateRate))
-	state.AddBalance(vm.RewardManagerPoolAddr, rewardpoolIncr)
+	issueBalance(state, vm.RewardManagerPoolAddr, rewardpoolIncr, gov.ModuleReward, types.BalanceChangeIssuance)
 }
 
 // increaseIssuance used for increase issuance at the end of each year
@@ -201,15 +201,15 @@ func (rmp *RewardMgrPlugin) increaseIssuance(thisYear, lastYear uint32, state xc
 
 	}
 	rewardpoolIncr := percentageCalculation(currIssuance, uint64(RewardPoolIncreaseRate))
-	state.AddBalance(vm.RewardManagerPoolAddr, rewardpoolIncr)
+	issueBalance(state, vm.RewardManagerPoolAddr, rewardpoolIncr, gov.ModuleReward, types.BalanceChangeIssuance)
 	lessBalance := new(big.Int).Sub(currIssuance, rewardpoolIncr)
-	if rmp.isLessThanFoundationYear(thisYear) {
+	if rmp.isLessThanFoundationYear(thisYear, blockNumber) {
 		log.Debug("Call EndBlock on reward_plugin: increase issuance to developer", "thisYear", thisYear, "developBalance", lessBalance)
-		rmp.addCommunityDeveloperFoundation(state, lessBalance, LessThanFoundationYearDeveloperRate)
+		rmp.addCommunityDeveloperFoundation(state, lessBalance, LessThanFoundationYearDeveloperRate, blockNumber)
 	} else {
 		log.Debug("Call EndBlock on reward_plugin: increase issuance to developer and hskchain", "thisYear", thisYear, "develop and hskchain Balance", lessBalance)
-		rmp.addCommunityDeveloperFoundation(state, lessBalance, AfterFoundationYearDeveloperRewardRate)
-		rmp.addPlatONFoundation(state, lessBalance, AfterFoundationYearFoundRewardRate)
+		rmp.addCommunityDeveloperFoundation(state, lessBalance, AfterFoundationYearDeveloperRewardRate, blockNumber)
+		rmp.addPlatONFoundation(state, lessBalance, AfterFoundationYearFoundRewardRate, blockNumber)
 	}
 	balance := state.GetBalance(vm.RewardManagerPoolAddr)
 	SetYearEndBalance(state, thisYear, balance)
@@ -243,8 +243,7 @@ func (rmp *RewardMgrPlugin) ReturnDelegateReward(address common.Address, amount
 			return fmt.Errorf("DelegateRewardPool balance is not enougth,want %v have %v", amount, DelegateRewardPool)
 		}
 
-		state.SubBalance(vm.DelegateRewardPoolAddr, amount)
-		state.AddBalance(address, amount)
+		transferBalance(state, vm.DelegateRewardPoolAddr, address, amount, gov.ModuleReward, types.BalanceChangeDelegateRewardWithdraw)
 	}
 	return nil
 }
@@ -363,7 +362,7 @@ func (rmp *RewardMgrPlugin) GetDelegateReward(blockHash common.Hash, blockNum ui
 			return nil, reward.ErrDelegationNotFound
 		}
 	} else {
-		if len(dls) > int(xcom.TheNumberOfDelegationsReward()) {
+		if len(dls) > int(xcom.TheNumberOfDelegationsReward(blockNum)) {
 			sort.Sort(staking.DelByDelegateEpoch(dls))
 		}
 	}
@@ -413,6 +412,7 @@ func (rmp *RewardMgrPlugin) rewardStakingByValidatorList(state xcom.StateDB, lis
 		if value.ShouldGiveDelegateReward() {
 			delegateReward, stakingReward = rmp.CalDelegateRewardAndNodeReward(everyValidatorReward, value.RewardPer)
 			totalValidatorDelegateReward.Add(totalValidatorDelegateReward, delegateReward)
+			addBalanceChange(state, vm.RewardManagerPoolAddr, vm.DelegateRewardPoolAddr, delegateReward, gov.ModuleReward, types.BalanceChangeDelegateReward)
 			log.Debug("allocate delegate reward of staking one-by-one", "nodeId", value.NodeId.TerminalString(), "staking reward", stakingReward, "per", value.RewardPer, "delegateReward", delegateReward)
 			//the  CurrentEpochDelegateReward will use by cal delegate reward Per
 			value.CurrentEpochDelegateReward.Add(value.CurrentEpochDelegateReward, delegateReward)
@@ -422,8 +422,11 @@ func (rmp *RewardMgrPlugin) rewardStakingByValidatorList(state xcom.StateDB, lis
 				"benefitAddress", value.BenefitAddress.String(), "staking reward", stakingReward)
 			state.AddBalance(value.BenefitAddress, stakingReward)
 			totalValidatorReward.Add(totalValidatorReward, stakingReward)
+			addBalanceChange(state, vm.RewardManagerPoolAddr, value.BenefitAddress, stakingReward, gov.ModuleReward, types.BalanceChangeStakingReward)
 		}
 	}
+	// the pools are settled once for the whole list, the balance changes above
+	// record the per validator share of the transfers
 	state.AddBalance(vm.DelegateRewardPoolAddr, totalValidatorDelegateReward)
 	state.SubBalance(vm.RewardManagerPoolAddr, new(big.Int).Add(totalValidatorDelegateReward, totalValidatorReward))
 	return nil
@@ -447,6 +450,11 @@ func (rmp *RewardMgrPlugin) AllocatePackageBlock(blockHash common.Hash, head *ty
 		log.Error("AllocatePackageBlock getBlockMinderAddress fail", "err", err, "blockNumber", head.Number, "blockHash", blockHash)
 		return err
 	}
+	// the block may be produced with the rotated nodeId of the candidate
+	if add, err = rmp.stakingPlugin.resolveCanAddr(blockHash, add, QueryStartNotIrr); err != nil {
+		log.Error("AllocatePackageBlock resolveCanAddr fail", "err", err, "blockNumber", head.Number, "blockHash", blockHash)
+		return err
+	}
 
 	currVerifier, err := rmp.stakingPlugin.IsCurrVerifier(blockHash, head.Number.Uint64(), nodeID, false)
 	if err != nil {
@@ -463,8 +471,7 @@ func (rmp *RewardMgrPlugin) AllocatePackageBlock(blockHash common.Hash, head *ty
 			delegateReward := new(big.Int).SetUint64(0)
 			delegateReward, reward = rmp.CalDelegateRewardAndNodeReward(reward, cm.RewardPer)
 
-			state.SubBalance(vm.RewardManagerPoolAddr, delegateReward)
-			state.AddBalance(vm.DelegateRewardPoolAddr, delegateReward)
+			transferBalance(state, vm.RewardManagerPoolAddr, vm.DelegateRewardPoolAddr, delegateReward, gov.ModuleReward, types.BalanceChangeDelegateReward)
 			cm.CurrentEpochDelegateReward.Add(cm.CurrentEpochDelegateReward, delegateReward)
 			log.Debug("allocate package reward, delegate reward", "blockNumber", head.Number, "blockHash", blockHash, "delegateReward", delegateReward, "epochDelegateReward", cm.CurrentEpochDelegateReward)
 
@@ -480,8 +487,7 @@ func (rmp *RewardMgrPlugin) AllocatePackageBlock(blockHash common.Hash, head *ty
 		log.Debug("allocate package reward,block reward", "blockNumber", head.Number, "blockHash", blockHash,
 			"coinBase", head.Coinbase.String(), "reward", reward)
 
-		state.SubBalance(vm.RewardManagerPoolAddr, reward)
-		state.AddBalance(head.Coinbase, reward)
+		transferBalance(state, vm.RewardManagerPoolAddr, head.Coinbase, reward, gov.ModuleReward, types.BalanceChangeBlockReward)
 	}
 	return nil
 }
@@ -662,7 +668,7 @@ func (rmp *RewardMgrPlugin) runIncreaseIssuance(blockHash common.Hash, head *typ
 			log.Error("Failed to execute runIncreaseIssuance function", "currentBlockNumber", head.Number, "currentBlockHash", blockHash.TerminalString(), "err", err)
 			return err
 		}
-		if err := xcom.StorageIncIssuanceTime(blockHash, rmp.db, incIssuanceTime+int64(xcom.AdditionalCycleTime()*uint64(minutes))); nil != err {
+		if err := xcom.StorageIncIssuanceTime(blockHash, rmp.db, incIssuanceTime+int64(xcom.AdditionalCycleTime(head.Number.Uint64())*uint64(minutes))); nil != err {
 			log.Error("storage incIssuanceTime fail", "currentBlockNumber", head.Number, "currentBlockHash", blockHash.TerminalString(), "err", err)
 			return err
 		}
@@ -699,7 +705,7 @@ func (rmp *RewardMgrPlugin) CalcEpochReward(blockHash common.Hash, head *types.H
 	if yearStartTime == 0 {
 		yearStartBlockNumber = head.Number.Uint64()
 		yearStartTime = int64(head.Time)
-		incIssuanceTime = yearStartTime + int64(xcom.AdditionalCycleTime()*uint64(minutes))
+		incIssuanceTime = yearStartTime + int64(xcom.AdditionalCycleTime(head.Number.Uint64())*uint64(minutes))
 		if err := xcom.StorageIncIssuanceTime(blockHash, rmp.db, incIssuanceTime); nil != err {
 			log.Error("storage incIssuanceTime fail", "currentBlockNumber", head.Number, "currentBlockHash", blockHash.TerminalString(), "err", err)
 			return nil, nil, err
@@ -720,7 +726,9 @@ func (rmp *RewardMgrPlugin) CalcEpochReward(blockHash common.Hash, head *types.H
 	// When the first issuance is completed
 	// Each settlement cycle needs to update the year start time,
 	// which is used to calculate the average annual block production rate
-	epochBlocks := xutil.CalcBlocksEachEpoch()
+	// The blocks of the next epoch, the epochs may have different sizes once
+	// the block schedule is governed.
+	epochBlocks := xutil.CalcBlocksEachEpochAt(head.Number.Uint64() + 1)
 	if yearNumber > 0 {
 		incIssuanceNumber, err := xcom.LoadIncIssuanceNumber(blockHash, rmp.db)
 		if nil != err {
@@ -734,9 +742,9 @@ func (rmp *RewardMgrPlugin) CalcEpochReward(blockHash common.Hash, head *types.H
 		}
 		if addition {
 			if yearStartBlockNumber == 1 {
-				yearStartBlockNumber += epochBlocks - 1
+				yearStartBlockNumber += xutil.CalcBlocksEachEpochAt(yearStartBlockNumber) - 1
 			} else {
-				yearStartBlockNumber += epochBlocks
+				yearStartBlockNumber += xutil.CalcBlocksEachEpochAt(yearStartBlockNumber + 1)
 			}
 			yearStartTime = int64(snapshotdb.GetDBBlockChain().GetHeaderByNumber(yearStartBlockNumber).Time)
 			if err := StorageYearStartTime(blockHash, rmp.db, yearStartBlockNumber, yearStartTime); nil != err {
@@ -750,7 +758,7 @@ func (rmp *RewardMgrPlugin) CalcEpochReward(blockHash common.Hash, head *types.H
 
 	// First calculation, calculated according to the default block interval.
 	// In each subsequent settlement cycle, an average block generation interval needs to be calculated.
-	avgPackTime := xcom.Interval() * uint64(millisecond)
+	avgPackTime := xutil.IntervalAt(head.Number.Uint64()+1) * uint64(millisecond)
 	if head.Number.Uint64() > yearStartBlockNumber {
 		diffNumber := head.Number.Uint64() - yearStartBlockNumber
 		diffTime := int64(head.Time) - yearStartTime
@@ -799,7 +807,7 @@ func (rmp *RewardMgrPlugin) CalcEpochReward(blockHash common.Hash, head *types.H
 			"epochBlocks", epochBlocks, "incIssuanceNumber", incIssuanceNumber)
 	}
 	// Get the total block reward and staking reward for each settlement cycle
-	epochTotalNewBlockReward := percentageCalculation(epochTotalReward, xcom.NewBlockRewardRate())
+	epochTotalNewBlockReward := percentageCalculation(epochTotalReward, xcom.NewBlockRewardRate(head.Number.Uint64()))
 	epochTotalStakingReward := new(big.Int).Sub(epochTotalReward, epochTotalNewBlockReward)
 	if err := StorageRemainingReward(blockHash, rmp.db, remainReward); nil != err {
 		log.Error("Failed to execute CalcEpochReward function", "currentBlockNumber", head.Number, "currentBlockHash", blockHash.TerminalString(), "err", err)
@@ -815,7 +823,7 @@ func (rmp *RewardMgrPlugin) CalcEpochReward(blockHash common.Hash, head *types.H
 		return nil, nil, err
 	}
 	log.Debug("Call CalcEpochReward, Cycle reward", "currBlockNumber", head.Number, "currBlockHash", blockHash, "currBlockTime", head.Time,
-		"epochTotalReward", epochTotalReward, "newBlockRewardRate", xcom.NewBlockRewardRate(), "epochTotalNewBlockReward", epochTotalNewBlockReward,
+		"epochTotalReward", epochTotalReward, "newBlockRewardRate", xcom.NewBlockRewardRate(head.Number.Uint64()), "epochTotalNewBlockReward", epochTotalNewBlockReward,
 		"epochTotalStakingReward", epochTotalStakingReward, "epochBlocks", epochBlocks, "newBlockReward", newBlockReward)
 	return newBlockReward, epochTotalStakingReward, nil
 }
@@ -318,12 +318,12 @@ func TestIncreaseIssuance(t *testing.T) {
 	assert.Equal(t, increaseIssuanceRatio, initIncreaseIssuanceRatio)
 	assert.Equal(t, tmp, new(big.Int).Div(new(big.Int).Mul(lastIssue, big.NewInt(int64(initIncreaseIssuanceRatio))), big.NewInt(int64(10000))))
 
-	if plugin.isLessThanFoundationYear(thisYear) {
-		mockDB.GetBalance(xcom.CDFAccount())
+	if plugin.isLessThanFoundationYear(thisYear, 1) {
+		mockDB.GetBalance(xcom.CDFAccount(0))
 
 	} else {
-		mockDB.GetBalance(xcom.CDFAccount())
-		mockDB.GetBalance(xcom.PlatONFundAccount())
+		mockDB.GetBalance(xcom.CDFAccount(0))
+		mockDB.GetBalance(xcom.PlatONFundAccount(0))
 	}
 
 }
@@ -390,7 +390,7 @@ func TestCDFAccountOneYearIncreaseIssuance(t *testing.T) {
 
 	mockDB.AddBalance(vm.RestrictingContractAddr, genesisIssue)
 
-	CDFAccountBalance := mockDB.GetBalance(xcom.CDFAccount())
+	CDFAccountBalance := mockDB.GetBalance(xcom.CDFAccount(0))
 	if err := plugin.increaseIssuance(thisYear, lastYear, mockDB, 1, common.ZeroHash); nil != err {
 		t.Fatal(err)
 	}
@@ -399,7 +399,7 @@ func TestCDFAccountOneYearIncreaseIssuance(t *testing.T) {
 
 	currIssue := new(big.Int).Sub(newIssue, lastIssue)
 
-	currCDFAccountBalance := new(big.Int).Sub(mockDB.GetBalance(xcom.CDFAccount()), CDFAccountBalance)
+	currCDFAccountBalance := new(big.Int).Sub(mockDB.GetBalance(xcom.CDFAccount(0)), CDFAccountBalance)
 	rewardpoolIncr := percentageCalculation(currIssue, uint64(RewardPoolIncreaseRate))
 	assert.Equal(t, currCDFAccountBalance, new(big.Int).Sub(currIssue, rewardpoolIncr))
 
@@ -421,8 +421,8 @@ func TestCDFAccountTenYearIncreaseIssuance(t *testing.T) {
 
 	mockDB.AddBalance(vm.RestrictingContractAddr, genesisIssue)
 
-	CDFAccountBalance := mockDB.GetBalance(xcom.CDFAccount())
-	PlatONFundAccountBalance := mockDB.GetBalance(xcom.PlatONFundAccount())
+	CDFAccountBalance := mockDB.GetBalance(xcom.CDFAccount(0))
+	PlatONFundAccountBalance := mockDB.GetBalance(xcom.PlatONFundAccount(0))
 	if err := plugin.increaseIssuance(thisYear, lastYear, mockDB, 1, common.ZeroHash); nil != err {
 		t.Fatal(err)
 	}
@@ -431,8 +431,8 @@ func TestCDFAccountTenYearIncreaseIssuance(t *testing.T) {
 
 	currIssue := new(big.Int).Sub(newIssue, lastIssue)
 
-	currCDFAccountBalance := new(big.Int).Sub(mockDB.GetBalance(xcom.CDFAccount()), CDFAccountBalance)
-	currPlatONFundAccountBalance := new(big.Int).Sub(mockDB.GetBalance(xcom.PlatONFundAccount()), PlatONFundAccountBalance)
+	currCDFAccountBalance := new(big.Int).Sub(mockDB.GetBalance(xcom.CDFAccount(0)), CDFAccountBalance)
+	currPlatONFundAccountBalance := new(big.Int).Sub(mockDB.GetBalance(xcom.PlatONFundAccount(0)), PlatONFundAccountBalance)
 
 	lessBalance := new(big.Int).Sub(currIssue, percentageCalculation(currIssue, uint64(RewardPoolIncreaseRate)))
 	assert.Equal(t, currCDFAccountBalance, percentageCalculation(lessBalance, uint64(AfterFoundationYearDeveloperRewardRate)))
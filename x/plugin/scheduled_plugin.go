@@ -0,0 +1,304 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package plugin
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/log"
+	"github.com/hashkey-chain/hashkey-chain/rlp"
+	"github.com/hashkey-chain/hashkey-chain/x/gov"
+	"github.com/hashkey-chain/hashkey-chain/x/scheduled"
+	"github.com/hashkey-chain/hashkey-chain/x/xcom"
+	"github.com/hashkey-chain/hashkey-chain/x/xutil"
+)
+
+// ScheduledTxPlugin keeps the signed transactions scheduled at a block or an
+// epoch. Their gas is escrowed by the contract account until the blockchain
+// reactor executes them at the beginning of the target block, it's not a
+// BasePlugin since the execution needs the EVM.
+type ScheduledTxPlugin struct {
+	log log.Logger
+}
+
+var (
+	scheduledOnce sync.Once
+	stp           *ScheduledTxPlugin
+)
+
+func ScheduledTxInstance() *ScheduledTxPlugin {
+	scheduledOnce.Do(func() {
+		scheduledLog := log.Root().New("package", "ScheduledTxPlugin")
+		scheduledLog.Info("Init ScheduledTx plugin ...")
+		stp = &ScheduledTxPlugin{scheduledLog}
+	})
+	return stp
+}
+
+// AddScheduledTx escrows the gas of the transaction signed by the sender from
+// the submitter and schedules it at the target.
+func (sp *ScheduledTxPlugin) AddScheduledTx(submitter, sender common.Address, tx *types.Transaction, targetType uint8, target uint64,
+	blockNumber uint64, blockHash common.Hash, state xcom.StateDB) (*scheduled.ScheduledTx, error) {
+
+	if tx.Sponsored() {
+		return nil, scheduled.ErrSponsoredTxScheduled
+	}
+	var dueKey []byte
+	switch targetType {
+	case scheduled.TargetBlock:
+		if target <= blockNumber {
+			return nil, scheduled.ErrTargetNotInFuture
+		}
+		dueKey = scheduled.GetBlockKey(target)
+	case scheduled.TargetEpoch:
+		if target <= xutil.CalculateEpoch(blockNumber) {
+			return nil, scheduled.ErrTargetNotInFuture
+		}
+		dueKey = scheduled.GetEpochKey(target)
+	default:
+		return nil, scheduled.ErrInvalidTargetType
+	}
+	if tx.GasPrice().Sign() <= 0 {
+		return nil, scheduled.ErrScheduledTxGasPriceLow
+	}
+	maxGas, err := gov.GovernMaxScheduledGasPerBlock(blockNumber, blockHash)
+	if nil != err {
+		return nil, err
+	}
+	if tx.Gas() > maxGas {
+		return nil, scheduled.ErrTxGasTooHigh
+	}
+
+	accountKey := scheduled.GetAccountKey(submitter)
+	pending, err := sp.getIDs(state, accountKey)
+	if nil != err {
+		return nil, err
+	}
+	if len(pending) >= scheduled.MaxPendingPerAccount {
+		return nil, scheduled.ErrTooManyPendingTxs
+	}
+	escrow := new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasPrice())
+	if state.GetBalance(submitter).Cmp(escrow) < 0 {
+		return nil, scheduled.ErrEscrowNotEnough
+	}
+	encoded, err := rlp.EncodeToBytes(tx)
+	if nil != err {
+		return nil, common.InternalError.Wrap(err.Error())
+	}
+
+	// The nonce keeps the account and its storage from being deleted as an
+	// empty account once all the escrow is refunded
+	if state.GetNonce(vm.ScheduledTxContractAddr) == 0 {
+		state.SetNonce(vm.ScheduledTxContractAddr, 1)
+	}
+	id := common.BytesToUint64(state.GetState(vm.ScheduledTxContractAddr, scheduled.ScheduledTxSeqKey)) + 1
+	state.SetState(vm.ScheduledTxContractAddr, scheduled.ScheduledTxSeqKey, common.Uint64ToBytes(id))
+
+	stx := &scheduled.ScheduledTx{
+		ID:         id,
+		Submitter:  submitter,
+		Sender:     sender,
+		TxHash:     tx.Hash(),
+		Tx:         encoded,
+		TargetType: targetType,
+		Target:     target,
+		Escrow:     escrow,
+		Status:     scheduled.StatusPending,
+	}
+	if err := sp.storeScheduledTx(state, stx); nil != err {
+		return nil, err
+	}
+	if err := sp.appendID(state, dueKey, id); nil != err {
+		return nil, err
+	}
+	if err := sp.storeIDs(state, accountKey, append(pending, id)); nil != err {
+		return nil, err
+	}
+	transferBalance(state, submitter, vm.ScheduledTxContractAddr, escrow, gov.ModuleScheduledTx, types.BalanceChangeScheduledTxEscrow)
+
+	sp.log.Debug("Scheduled transaction", "id", id, "submitter", submitter, "sender", sender, "txHash", stx.TxHash,
+		"targetType", targetType, "target", target, "escrow", escrow)
+	return stx, nil
+}
+
+// CancelScheduledTx cancels the pending transaction and refunds the escrow,
+// only the submitter or the sender of the transaction can cancel it.
+func (sp *ScheduledTxPlugin) CancelScheduledTx(caller common.Address, id uint64, blockNumber uint64, state xcom.StateDB) error {
+	stx, err := sp.GetScheduledTx(id, state)
+	if nil != err {
+		return err
+	}
+	if !stx.Pending() {
+		return scheduled.ErrScheduledTxNotPending
+	}
+	if caller != stx.Submitter && caller != stx.Sender {
+		return scheduled.ErrNotScheduledTxOwner
+	}
+	// The id stays in the due list, it's skipped since it's not pending
+	return sp.FinishScheduledTx(stx, scheduled.StatusCancelled, 0, common.Big0, "", nil, blockNumber, state)
+}
+
+// GetScheduledTx returns the scheduled transaction of the id.
+func (sp *ScheduledTxPlugin) GetScheduledTx(id uint64, state xcom.StateDB) (*scheduled.ScheduledTx, error) {
+	b := state.GetState(vm.ScheduledTxContractAddr, scheduled.GetScheduledTxKey(id))
+	if len(b) == 0 {
+		return nil, scheduled.ErrScheduledTxNotFound
+	}
+	var stx scheduled.ScheduledTx
+	if err := rlp.DecodeBytes(b, &stx); nil != err {
+		sp.log.Error("Failed to rlp decode the scheduled transaction", "id", id, "err", err)
+		return nil, common.InternalError.Wrap(err.Error())
+	}
+	return &stx, nil
+}
+
+// GetScheduledTxsByAccount returns the pending transactions scheduled by the
+// account.
+func (sp *ScheduledTxPlugin) GetScheduledTxsByAccount(account common.Address, state xcom.StateDB) ([]*scheduled.ScheduledTx, error) {
+	ids, err := sp.getIDs(state, scheduled.GetAccountKey(account))
+	if nil != err {
+		return nil, err
+	}
+	list := make([]*scheduled.ScheduledTx, 0, len(ids))
+	for _, id := range ids {
+		stx, err := sp.GetScheduledTx(id, state)
+		if nil != err {
+			return nil, err
+		}
+		list = append(list, stx)
+	}
+	return list, nil
+}
+
+// TakeDueScheduledTxs returns the ids of the transactions due at the block in
+// the execution order: the ones deferred by the former blocks, the ones
+// scheduled at the epoch if the block begins it, then the ones scheduled at
+// the block. The due lists are cleared, the ids not executed in the block must
+// be deferred by DeferScheduledTxs.
+func (sp *ScheduledTxPlugin) TakeDueScheduledTxs(blockNumber uint64, state xcom.StateDB) ([]uint64, error) {
+	keys := [][]byte{scheduled.ScheduledBacklogKey}
+	if xutil.IsBeginOfEpoch(blockNumber) {
+		keys = append(keys, scheduled.GetEpochKey(xutil.CalculateEpoch(blockNumber)))
+	}
+	keys = append(keys, scheduled.GetBlockKey(blockNumber))
+
+	var due []uint64
+	for _, key := range keys {
+		ids, err := sp.getIDs(state, key)
+		if nil != err {
+			return nil, err
+		}
+		if len(ids) > 0 {
+			due = append(due, ids...)
+			state.SetState(vm.ScheduledTxContractAddr, key, []byte{})
+		}
+	}
+	return due, nil
+}
+
+// DeferScheduledTxs defers the due transactions exceeding the limits of the
+// block to the next block, they are executed before the ones due there.
+func (sp *ScheduledTxPlugin) DeferScheduledTxs(ids []uint64, state xcom.StateDB) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return sp.storeIDs(state, scheduled.ScheduledBacklogKey, ids)
+}
+
+// FinishScheduledTx records the result and the logs of the transaction and
+// refunds the escrow except the fee charged by its execution to the submitter.
+func (sp *ScheduledTxPlugin) FinishScheduledTx(stx *scheduled.ScheduledTx, status scheduled.Status, gasUsed uint64, fee *big.Int,
+	failure string, logs []*types.Log, blockNumber uint64, state xcom.StateDB) error {
+
+	accountKey := scheduled.GetAccountKey(stx.Submitter)
+	pending, err := sp.getIDs(state, accountKey)
+	if nil != err {
+		return err
+	}
+	for i, id := range pending {
+		if id == stx.ID {
+			pending = append(pending[:i], pending[i+1:]...)
+			break
+		}
+	}
+	if err := sp.storeIDs(state, accountKey, pending); nil != err {
+		return err
+	}
+
+	stx.Status = status
+	stx.ExecBlock = blockNumber
+	stx.GasUsed = gasUsed
+	stx.Failure = failure
+	stx.Logs = logs
+	if err := sp.storeScheduledTx(state, stx); nil != err {
+		return err
+	}
+	if refund := new(big.Int).Sub(stx.Escrow, fee); refund.Sign() > 0 {
+		transferBalance(state, vm.ScheduledTxContractAddr, stx.Submitter, refund, gov.ModuleScheduledTx, types.BalanceChangeScheduledTxRefund)
+	}
+	sp.log.Debug("Finished scheduled transaction", "id", stx.ID, "txHash", stx.TxHash, "status", status,
+		"blockNumber", blockNumber, "gasUsed", gasUsed, "failure", failure)
+	return nil
+}
+
+func (sp *ScheduledTxPlugin) storeScheduledTx(state xcom.StateDB, stx *scheduled.ScheduledTx) error {
+	b, err := rlp.EncodeToBytes(stx)
+	if nil != err {
+		sp.log.Error("Failed to rlp encode the scheduled transaction", "id", stx.ID, "err", err)
+		return common.InternalError.Wrap(err.Error())
+	}
+	state.SetState(vm.ScheduledTxContractAddr, scheduled.GetScheduledTxKey(stx.ID), b)
+	return nil
+}
+
+func (sp *ScheduledTxPlugin) getIDs(state xcom.StateDB, key []byte) ([]uint64, error) {
+	b := state.GetState(vm.ScheduledTxContractAddr, key)
+	if len(b) == 0 {
+		return nil, nil
+	}
+	var ids []uint64
+	if err := rlp.DecodeBytes(b, &ids); nil != err {
+		sp.log.Error("Failed to rlp decode the scheduled transaction ids", "key", key, "err", err)
+		return nil, common.InternalError.Wrap(err.Error())
+	}
+	return ids, nil
+}
+
+func (sp *ScheduledTxPlugin) storeIDs(state xcom.StateDB, key []byte, ids []uint64) error {
+	if len(ids) == 0 {
+		state.SetState(vm.ScheduledTxContractAddr, key, []byte{})
+		return nil
+	}
+	b, err := rlp.EncodeToBytes(ids)
+	if nil != err {
+		return common.InternalError.Wrap(err.Error())
+	}
+	state.SetState(vm.ScheduledTxContractAddr, key, b)
+	return nil
+}
+
+func (sp *ScheduledTxPlugin) appendID(state xcom.StateDB, key []byte, id uint64) error {
+	ids, err := sp.getIDs(state, key)
+	if nil != err {
+		return err
+	}
+	return sp.storeIDs(state, key, append(ids, id))
+}
@@ -0,0 +1,182 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package plugin
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/common/vm"
+	"github.com/hashkey-chain/hashkey-chain/core/snapshotdb"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/x/scheduled"
+	"github.com/hashkey-chain/hashkey-chain/x/xutil"
+)
+
+func newScheduledTx(nonce uint64, gas uint64) *types.Transaction {
+	return types.NewTransaction(nonce, addrArr[1], big.NewInt(1), gas, big.NewInt(10), nil)
+}
+
+func TestScheduledTxPlugin_AddScheduledTx(t *testing.T) {
+	chain := newAllowlistChain(t)
+	defer chain.SnapDB.Clear()
+	sp := ScheduledTxInstance()
+	chain.StateDB.AddBalance(addrArr[0], big.NewInt(10000000))
+
+	if err := chain.AddBlockWithSnapDB(false, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
+		blockNumber := header.Number.Uint64()
+		state := chain.StateDB
+
+		_, err := sp.AddScheduledTx(addrArr[0], addrArr[2], newScheduledTx(0, 21000), scheduled.TargetBlock, blockNumber, blockNumber, hash, state)
+		assert.Equal(t, scheduled.ErrTargetNotInFuture, err)
+		_, err = sp.AddScheduledTx(addrArr[0], addrArr[2], newScheduledTx(0, 21000), scheduled.TargetEpoch, xutil.CalculateEpoch(blockNumber), blockNumber, hash, state)
+		assert.Equal(t, scheduled.ErrTargetNotInFuture, err)
+		_, err = sp.AddScheduledTx(addrArr[0], addrArr[2], newScheduledTx(0, 21000), 2, blockNumber+1, blockNumber, hash, state)
+		assert.Equal(t, scheduled.ErrInvalidTargetType, err)
+		_, err = sp.AddScheduledTx(addrArr[0], addrArr[2], newScheduledTx(0, 100000000), scheduled.TargetBlock, blockNumber+1, blockNumber, hash, state)
+		assert.Equal(t, scheduled.ErrTxGasTooHigh, err)
+		_, err = sp.AddScheduledTx(addrArr[3], addrArr[2], newScheduledTx(0, 21000), scheduled.TargetBlock, blockNumber+1, blockNumber, hash, state)
+		assert.Equal(t, scheduled.ErrEscrowNotEnough, err)
+
+		stx, err := sp.AddScheduledTx(addrArr[0], addrArr[2], newScheduledTx(0, 21000), scheduled.TargetBlock, blockNumber+1, blockNumber, hash, state)
+		if !assert.Nil(t, err) {
+			return err
+		}
+		assert.Equal(t, uint64(1), stx.ID)
+		assert.Equal(t, big.NewInt(210000), stx.Escrow)
+		assert.Equal(t, big.NewInt(9790000), state.GetBalance(addrArr[0]))
+		assert.Equal(t, big.NewInt(210000), state.GetBalance(vm.ScheduledTxContractAddr))
+		assert.Equal(t, uint64(1), state.GetNonce(vm.ScheduledTxContractAddr))
+
+		stored, err := sp.GetScheduledTx(stx.ID, state)
+		assert.Nil(t, err)
+		assert.Equal(t, stx, stored)
+		list, err := sp.GetScheduledTxsByAccount(addrArr[0], state)
+		assert.Nil(t, err)
+		assert.Len(t, list, 1)
+
+		for i := 1; i < scheduled.MaxPendingPerAccount; i++ {
+			if _, err := sp.AddScheduledTx(addrArr[0], addrArr[2], newScheduledTx(uint64(i), 21000), scheduled.TargetBlock, blockNumber+1, blockNumber, hash, state); nil != err {
+				return err
+			}
+		}
+		_, err = sp.AddScheduledTx(addrArr[0], addrArr[2], newScheduledTx(0, 21000), scheduled.TargetBlock, blockNumber+1, blockNumber, hash, state)
+		assert.Equal(t, scheduled.ErrTooManyPendingTxs, err)
+		return nil
+	}, nil, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestScheduledTxPlugin_CancelScheduledTx(t *testing.T) {
+	chain := newAllowlistChain(t)
+	defer chain.SnapDB.Clear()
+	sp := ScheduledTxInstance()
+	chain.StateDB.AddBalance(addrArr[0], big.NewInt(1000000))
+
+	if err := chain.AddBlockWithSnapDB(false, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
+		blockNumber := header.Number.Uint64()
+		state := chain.StateDB
+
+		stx, err := sp.AddScheduledTx(addrArr[0], addrArr[2], newScheduledTx(0, 21000), scheduled.TargetBlock, blockNumber+1, blockNumber, hash, state)
+		if nil != err {
+			return err
+		}
+		assert.Equal(t, scheduled.ErrScheduledTxNotFound, sp.CancelScheduledTx(addrArr[0], stx.ID+1, blockNumber, state))
+		assert.Equal(t, scheduled.ErrNotScheduledTxOwner, sp.CancelScheduledTx(addrArr[1], stx.ID, blockNumber, state))
+
+		// The sender of the transaction can cancel it as well as the submitter
+		assert.Nil(t, sp.CancelScheduledTx(addrArr[2], stx.ID, blockNumber, state))
+		assert.Equal(t, big.NewInt(1000000), state.GetBalance(addrArr[0]))
+		assert.Equal(t, 0, state.GetBalance(vm.ScheduledTxContractAddr).Sign())
+		assert.Equal(t, scheduled.ErrScheduledTxNotPending, sp.CancelScheduledTx(addrArr[0], stx.ID, blockNumber, state))
+
+		cancelled, err := sp.GetScheduledTx(stx.ID, state)
+		assert.Nil(t, err)
+		assert.Equal(t, scheduled.StatusCancelled, cancelled.Status)
+		assert.Equal(t, blockNumber, cancelled.ExecBlock)
+		list, err := sp.GetScheduledTxsByAccount(addrArr[0], state)
+		assert.Nil(t, err)
+		assert.Len(t, list, 0)
+		return nil
+	}, nil, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestScheduledTxPlugin_TakeDueScheduledTxs(t *testing.T) {
+	chain := newAllowlistChain(t)
+	defer chain.SnapDB.Clear()
+	sp := ScheduledTxInstance()
+	chain.StateDB.AddBalance(addrArr[0], big.NewInt(1000000))
+
+	if err := chain.AddBlockWithSnapDB(false, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
+		blockNumber := header.Number.Uint64()
+		state := chain.StateDB
+		epochBegin := xutil.CalcEpochEndBlock(xutil.CalculateEpoch(blockNumber)) + 1
+		nextEpoch := xutil.CalculateEpoch(epochBegin)
+
+		var ids []uint64
+		for i, target := range []struct {
+			typ   uint8
+			value uint64
+		}{
+			{scheduled.TargetBlock, epochBegin},
+			{scheduled.TargetEpoch, nextEpoch},
+			{scheduled.TargetBlock, epochBegin + 1},
+		} {
+			stx, err := sp.AddScheduledTx(addrArr[0], addrArr[2], newScheduledTx(uint64(i), 21000), target.typ, target.value, blockNumber, hash, state)
+			if nil != err {
+				return err
+			}
+			ids = append(ids, stx.ID)
+		}
+
+		// The transactions of the epoch are executed before the ones of the block
+		due, err := sp.TakeDueScheduledTxs(epochBegin, state)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{ids[1], ids[0]}, due)
+		due, err = sp.TakeDueScheduledTxs(epochBegin, state)
+		assert.Nil(t, err)
+		assert.Len(t, due, 0)
+
+		// The deferred transactions are executed first in the next block
+		assert.Nil(t, sp.DeferScheduledTxs([]uint64{ids[0]}, state))
+		due, err = sp.TakeDueScheduledTxs(epochBegin+1, state)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{ids[0], ids[2]}, due)
+
+		stx, err := sp.GetScheduledTx(ids[0], state)
+		if nil != err {
+			return err
+		}
+		// The fee is paid out of the escrow by the execution, the rest is refunded
+		state.SubBalance(vm.ScheduledTxContractAddr, big.NewInt(150000))
+		assert.Nil(t, sp.FinishScheduledTx(stx, scheduled.StatusExecuted, 15000, big.NewInt(150000), "", nil, epochBegin+1, state))
+		assert.Equal(t, big.NewInt(1000000-3*210000+60000), state.GetBalance(addrArr[0]))
+		assert.Equal(t, big.NewInt(2*210000), state.GetBalance(vm.ScheduledTxContractAddr))
+		list, err := sp.GetScheduledTxsByAccount(addrArr[0], state)
+		assert.Nil(t, err)
+		assert.Len(t, list, 2)
+		return nil
+	}, nil, nil); err != nil {
+		t.Error(err)
+	}
+}
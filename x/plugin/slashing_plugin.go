@@ -104,6 +104,16 @@ func (sp *SlashingPlugin) SetDecodeEvidenceFun(f func(dupType consensus.Evidence
 }
 
 func (sp *SlashingPlugin) BeginBlock(blockHash common.Hash, header *types.Header, state xcom.StateDB) error {
+	return sp.beginBlock(blockHash, header, state, QueryStartIrr)
+}
+
+// BeginSimulatedBlock begins a block simulated on top of the chain, the
+// validators of the previous round are read at the block.
+func (sp *SlashingPlugin) BeginSimulatedBlock(blockHash common.Hash, header *types.Header, state xcom.StateDB) error {
+	return sp.beginBlock(blockHash, header, state, QueryStartNotIrr)
+}
+
+func (sp *SlashingPlugin) beginBlock(blockHash common.Hash, header *types.Header, state xcom.StateDB, isCommit bool) error {
 	// If it is the first block in each round, Delete old pack amount record.
 	// Do this from the second consensus round
 	if xutil.IsBeginOfConsensus(header.Number.Uint64()) && header.Number.Uint64() > 1 {
@@ -119,9 +129,9 @@ func (sp *SlashingPlugin) BeginBlock(blockHash common.Hash, header *types.Header
 	// If it is the 230th block of each round,
 	// it will punish the node with abnormal block rate.
 	// Do this from the second consensus round
-	if header.Number.Uint64() > xutil.ConsensusSize() && xutil.IsElection(header.Number.Uint64()) {
+	if xutil.CalculateRound(header.Number.Uint64()) > 1 && xutil.IsElection(header.Number.Uint64()) {
 		log.Debug("Call GetPrePackAmount", "blockNumber", header.Number.Uint64(), "blockHash",
-			blockHash.TerminalString(), "consensusSize", xutil.ConsensusSize(), "electionDistance", xcom.ElectionDistance())
+			blockHash.TerminalString(), "consensusSize", xutil.ConsensusSizeAt(header.Number.Uint64()), "electionDistance", xutil.ElectionDistanceAt(header.Number.Uint64()))
 		if result, err := sp.GetPrePackAmount(header.Number.Uint64(), header.ParentHash); nil != err {
 			return err
 		} else {
@@ -130,7 +140,7 @@ func (sp *SlashingPlugin) BeginBlock(blockHash common.Hash, header *types.Header
 				return errors.New("packAmount data not found")
 			}
 
-			preRoundVal, err := stk.getPreValList(blockHash, header.Number.Uint64(), QueryStartIrr)
+			preRoundVal, err := stk.getPreValList(blockHash, header.Number.Uint64(), isCommit)
 			if nil != err {
 				log.Error("Failed to BeginBlock, query previous round validators is failed", "blockNumber", header.Number.Uint64(), "blockHash", blockHash.TerminalString(), "err", err)
 				return err
@@ -366,6 +376,12 @@ func (sp *SlashingPlugin) checkSlashing(blockNumber uint64, blockHash common.Has
 				log.Error("Failed to convert nodeID to address", "nodeId", nodeId.TerminalString(), "error", err)
 				return nil, err
 			}
+			// the nodeId may be the rotated nodeId of the candidate
+			if nodeAddr, err = stk.resolveCanAddr(blockHash, nodeAddr, QueryStartIrr); nil != err {
+				log.Error("Failed to zeroProduceProcess, resolve candidate address is failed", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(),
+					"nodeId", nodeId.TerminalString(), "err", err)
+				return nil, err
+			}
 			canMutable, err := stk.GetCanMutableByIrr(nodeAddr)
 			if nil != err {
 				log.Error("Failed to zeroProduceProcess, call candidate mutable info is failed", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(),
@@ -530,8 +546,7 @@ func (sp *SlashingPlugin) Slash(evidence consensus.Evidence, blockHash common.Ha
 		return slashing.ErrBlockNumberTooHigh
 	}
 	evidenceEpoch := xutil.CalculateEpoch(evidence.BlockNumber())
-	blocksOfEpoch := xutil.CalcBlocksEachEpoch()
-	invalidNum := evidenceEpoch * blocksOfEpoch
+	invalidNum := xutil.CalcEpochEndBlock(evidenceEpoch)
 	if invalidNum < blockNumber {
 
 		evidenceAge, err := gov.GovernMaxEvidenceAge(blockNumber, blockHash)
@@ -541,10 +556,10 @@ func (sp *SlashingPlugin) Slash(evidence consensus.Evidence, blockHash common.Ha
 			return err
 		}
 
-		if validSize := blocksOfEpoch * uint64(evidenceAge); blockNumber-invalidNum > validSize {
+		if validNum := xutil.CalcEpochEndBlock(evidenceEpoch + uint64(evidenceAge)); blockNumber > validNum {
 			log.Warn("Failed to Slash, Evidence time expired", "blockNumber", blockNumber,
 				"blockHash", blockHash.TerminalString(), "evidenceBlockNum", evidence.BlockNumber(),
-				"blocksOfEpoch", blocksOfEpoch, "the end blockNum of evidenceEpoch", invalidNum)
+				"the end blockNum of evidenceEpoch", invalidNum, "the end blockNum of validity", validNum)
 			return slashing.ErrIntervalTooLong
 		}
 	}
@@ -561,7 +576,13 @@ func (sp *SlashingPlugin) Slash(evidence consensus.Evidence, blockHash common.Ha
 			"evidenceBlockNumber", evidence.BlockNumber(), "evidenceNodeId", evidence.NodeID().TerminalString(), "err", err)
 		return slashing.ErrDuplicateSignVerify
 	}
-	canAddr := crypto.PubkeyToNodeAddress(*evidencePubKey)
+	// the evidence may be signed with the rotated nodeId of the candidate
+	canAddr, err := stk.resolveCanAddr(blockHash, crypto.PubkeyToNodeAddress(*evidencePubKey), QueryStartNotIrr)
+	if nil != err {
+		log.Error("Failed to Slash, resolve candidate address is failed", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(),
+			"evidenceBlockNumber", evidence.BlockNumber(), "evidenceNodeId", evidence.NodeID().TerminalString(), "err", err)
+		return slashing.ErrGetCandidate
+	}
 	canBase, err := stk.GetCanBase(blockHash, canAddr)
 	if nil != err {
 		log.Error("Failed to Slash, query CandidateBase info is failed", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(),
@@ -581,13 +602,21 @@ func (sp *SlashingPlugin) Slash(evidence consensus.Evidence, blockHash common.Ha
 		return slashing.ErrSameAddr
 	}
 
-	if canBase.NodeId != evidence.NodeID() {
+	// the keys of the candidate in force when the evidence happened
+	nodeId, blsPubKey, err := stk.getCanKey(blockHash, canAddr, canBase, xutil.CalculateEpoch(evidence.BlockNumber()))
+	if nil != err {
+		log.Error("Failed to Slash, query candidate keys is failed", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(),
+			"evidenceBlockNumber", evidence.BlockNumber(), "canAddr", canAddr.Hex(), "err", err)
+		return slashing.ErrGetCandidate
+	}
+
+	if nodeId != evidence.NodeID() {
 		log.Error("Failed to Slash, Mismatch nodeId", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(),
-			"can nodeId", canBase.NodeId.TerminalString(), "evidence nodeId", evidence.NodeID().TerminalString(), "evidenceType", evidence.Type())
+			"can nodeId", nodeId.TerminalString(), "evidence nodeId", evidence.NodeID().TerminalString(), "evidenceType", evidence.Type())
 		return slashing.ErrNodeIdMismatch
 	}
 
-	blsKey, _ := canBase.BlsPubKey.ParseBlsPubKey()
+	blsKey, _ := blsPubKey.ParseBlsPubKey()
 	if !bytes.Equal(blsKey.Serialize(), evidence.BlsPubKey().Serialize()) {
 		log.Error("Failed to Slash, Mismatch blsPubKey", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(),
 			"nodeId", canBase.NodeId.TerminalString(), "can blsKey", hex.EncodeToString(blsKey.Serialize()),
@@ -694,6 +723,12 @@ func buildPrefixByRound(round uint64) []byte {
 	return append(packAmountPrefix, common.Uint64ToBytes(round)...)
 }
 
+// ZeroProduceRecordPrefixes returns the key prefixes of the records tracking the
+// blocks packed by the validators, they refer to the consensus rounds by number.
+func ZeroProduceRecordPrefixes() [][]byte {
+	return [][]byte{packAmountPrefix, waitSlashingNodeListKey}
+}
+
 func getNodeId(prefix []byte, key []byte) (discover.NodeID, error) {
 	key = key[len(prefix):]
 	nodeId, err := discover.BytesID(key)
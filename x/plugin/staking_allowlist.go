@@ -0,0 +1,118 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package plugin
+
+import (
+	"math/big"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/core/snapshotdb"
+	"github.com/hashkey-chain/hashkey-chain/log"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+	"github.com/hashkey-chain/hashkey-chain/x/gov"
+	"github.com/hashkey-chain/hashkey-chain/x/staking"
+	"github.com/hashkey-chain/hashkey-chain/x/xcom"
+	"github.com/hashkey-chain/hashkey-chain/x/xutil"
+)
+
+// CheckAdmission checks that the node is in the allowlist, and is staked by
+// the address allowed for it if the stakingAddr is not nil. Any node is
+// admitted when the allowlist is off.
+func (sk *StakingPlugin) CheckAdmission(blockNumber uint64, blockHash common.Hash, state xcom.StateDB,
+	nodeId discover.NodeID, stakingAddr *common.Address) error {
+
+	enabled, err := gov.AllowlistEnabled(blockNumber, blockHash, state)
+	if nil != err || !enabled {
+		return err
+	}
+	nodeAddr, err := xutil.NodeId2Addr(nodeId)
+	if nil != err {
+		return staking.ErrNodeID2Addr
+	}
+	entry, err := gov.GetAllowlistEntry(blockHash, nodeAddr)
+	if nil != err {
+		return err
+	}
+	if entry == nil {
+		return staking.ErrNodeNotInAllowlist
+	}
+	if stakingAddr != nil && *stakingAddr != entry.StakingAddress {
+		return staking.ErrStakingAddrNotInAllowlist
+	}
+	return nil
+}
+
+// admission returns the check whether a candidate can be elected, every
+// candidate can be elected when the allowlist is off. The round election drops
+// the validators not admitted at once, the epoch ones are kept to its end.
+func (sk *StakingPlugin) admission(blockNumber uint64, blockHash common.Hash, state xcom.StateDB) (func(nodeAddr common.NodeAddress) (bool, error), error) {
+	enabled, err := gov.AllowlistEnabled(blockNumber, blockHash, state)
+	if nil != err {
+		return nil, err
+	}
+	return func(nodeAddr common.NodeAddress) (bool, error) {
+		if !enabled {
+			return true, nil
+		}
+		entry, err := gov.GetAllowlistEntry(blockHash, nodeAddr)
+		return entry != nil, err
+	}, nil
+}
+
+// withdrawRemovedFromAllowlist withdraws the candidates of the nodes removed
+// from the allowlist, as their staking addresses do by withdrewStaking. It's
+// called at the end of the epoch, the tally of the allowlist proposals is
+// before it, the candidates are not elected for the next epoch.
+func (sk *StakingPlugin) withdrawRemovedFromAllowlist(state xcom.StateDB, blockHash common.Hash, blockNumber uint64) error {
+	removed, err := gov.ListAllowlistRemoved(blockHash)
+	if nil != err || len(removed) == 0 {
+		return err
+	}
+	if err := gov.ClearAllowlistRemoved(blockHash); nil != err {
+		return err
+	}
+
+	admitted, err := sk.admission(blockNumber, blockHash, state)
+	if nil != err {
+		return err
+	}
+	for _, nodeAddr := range removed {
+		// added back, or the allowlist is off
+		if ok, err := admitted(nodeAddr); nil != err {
+			return err
+		} else if ok {
+			continue
+		}
+
+		can, err := sk.db.GetCandidateStore(blockHash, nodeAddr)
+		if snapshotdb.NonDbNotFoundErr(err) {
+			return err
+		}
+		if can.IsEmpty() || can.IsInvalid() {
+			continue
+		}
+
+		if err := sk.WithdrewStaking(state, blockHash, new(big.Int).SetUint64(blockNumber), nodeAddr, can); nil != err {
+			log.Error("Failed to withdraw the candidate removed from the allowlist", "blockNumber", blockNumber,
+				"blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
+			return err
+		}
+		log.Info("Withdrew the candidate removed from the allowlist", "blockNumber", blockNumber,
+			"blockHash", blockHash.Hex(), "nodeId", can.NodeId.String())
+	}
+	return nil
+}
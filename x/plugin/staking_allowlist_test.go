@@ -0,0 +1,133 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package plugin
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/common/mock"
+	"github.com/hashkey-chain/hashkey-chain/core/snapshotdb"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/params"
+	"github.com/hashkey-chain/hashkey-chain/x/gov"
+	"github.com/hashkey-chain/hashkey-chain/x/staking"
+	"github.com/hashkey-chain/hashkey-chain/x/xutil"
+)
+
+func newAllowlistChain(t *testing.T) *mock.Chain {
+	chain := mock.NewChain()
+	newPlugins()
+	gov.AddActiveVersion(params.FORKVERSION_1_5_0, 0, chain.StateDB)
+	if _, err := gov.InitGenesisGovernParam(common.ZeroHash, chain.SnapDB, params.FORKVERSION_1_5_0); nil != err {
+		t.Fatal(err)
+	}
+	return chain
+}
+
+func enableAllowlist(blockNumber uint64, blockHash common.Hash) error {
+	return gov.UpdateGovernParamValue(gov.ModuleStaking, gov.KeyAdmissionAllowlist, "true", blockNumber, blockHash)
+}
+
+func TestStakingPlugin_CheckAdmission(t *testing.T) {
+	chain := newAllowlistChain(t)
+	defer chain.SnapDB.Clear()
+	sk := StakingInstance()
+
+	if err := chain.AddBlockWithSnapDB(false, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
+		blockNumber := header.Number.Uint64()
+
+		// Any node is admitted when the allowlist is off
+		assert.Nil(t, sk.CheckAdmission(blockNumber, hash, chain.StateDB, nodeIdArr[0], &addrArr[0]))
+
+		if err := enableAllowlist(blockNumber, hash); nil != err {
+			return err
+		}
+		assert.Equal(t, staking.ErrNodeNotInAllowlist, sk.CheckAdmission(blockNumber, hash, chain.StateDB, nodeIdArr[0], &addrArr[0]))
+		assert.Equal(t, staking.ErrNodeNotInAllowlist, sk.CheckAdmission(blockNumber, hash, chain.StateDB, nodeIdArr[0], nil))
+
+		if err := gov.AddAllowlistEntry(hash, &gov.AllowlistEntry{NodeId: nodeIdArr[0], StakingAddress: addrArr[0]}); nil != err {
+			return err
+		}
+		assert.Nil(t, sk.CheckAdmission(blockNumber, hash, chain.StateDB, nodeIdArr[0], &addrArr[0]))
+		assert.Nil(t, sk.CheckAdmission(blockNumber, hash, chain.StateDB, nodeIdArr[0], nil))
+		assert.Equal(t, staking.ErrStakingAddrNotInAllowlist, sk.CheckAdmission(blockNumber, hash, chain.StateDB, nodeIdArr[0], &addrArr[1]))
+		assert.Equal(t, staking.ErrNodeNotInAllowlist, sk.CheckAdmission(blockNumber, hash, chain.StateDB, nodeIdArr[1], &addrArr[0]))
+		return nil
+	}, nil, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStakingPlugin_withdrawRemovedFromAllowlist(t *testing.T) {
+	chain := newAllowlistChain(t)
+	defer chain.SnapDB.Clear()
+	sk := StakingInstance()
+	sBalance, _ := new(big.Int).SetString(senderBalance, 10)
+	chain.StateDB.AddBalance(sender, sBalance)
+
+	if err := chain.AddBlockWithSnapDB(false, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
+		for _, index := range []int{1, 2} {
+			if err := create_staking(chain.StateDB, header.Number, hash, index, FreeVon, t); nil != err {
+				return err
+			}
+			if err := gov.AddAllowlistEntry(hash, &gov.AllowlistEntry{NodeId: nodeIdArr[index], StakingAddress: sender}); nil != err {
+				return err
+			}
+		}
+		return enableAllowlist(header.Number.Uint64(), hash)
+	}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := chain.AddBlockWithSnapDB(false, func(hash common.Hash, header *types.Header, sdb snapshotdb.DB) error {
+		for _, index := range []int{1, 2} {
+			nodeAddr, _ := xutil.NodeId2Addr(nodeIdArr[index])
+			if err := gov.RemoveAllowlistEntry(hash, nodeAddr); nil != err {
+				return err
+			}
+		}
+		// the node 2 is added back before the end of the epoch
+		if err := gov.AddAllowlistEntry(hash, &gov.AllowlistEntry{NodeId: nodeIdArr[2], StakingAddress: sender}); nil != err {
+			return err
+		}
+
+		if err := sk.withdrawRemovedFromAllowlist(chain.StateDB, hash, header.Number.Uint64()); nil != err {
+			return err
+		}
+
+		removed, err := gov.ListAllowlistRemoved(hash)
+		assert.Nil(t, err)
+		assert.Len(t, removed, 0)
+
+		can, err := getCandidate(hash, 1)
+		if nil == err {
+			assert.True(t, can.IsInvalid())
+		} else {
+			assert.Equal(t, snapshotdb.ErrNotFound, err)
+		}
+		can, err = getCandidate(hash, 2)
+		assert.Nil(t, err)
+		assert.True(t, can.IsValid())
+		return nil
+	}, nil, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/log"
+	"github.com/hashkey-chain/hashkey-chain/x/gov"
+	"github.com/hashkey-chain/hashkey-chain/x/staking"
+	"github.com/hashkey-chain/hashkey-chain/x/xcom"
+)
+
+// ElectionContext is the block the next consensus round or epoch is elected
+// at, the VRF proof and the parent hash are not set for an epoch.
+type ElectionContext struct {
+	BlockNumber    uint64
+	ParentHash     common.Hash
+	Nonce          []byte // The VRF proof of the election block
+	CurrentVersion uint32 // The active version of the chain
+}
+
+// ElectionPolicy selects the validators of the next consensus round from the
+// validators of the epoch. The Election keeps the validators of the current
+// round that are still valid and replaces at most ShiftValidatorNum of them by
+// the elected ones. The validators of the next epoch are also elected by the
+// policy at the end of the epoch. Every node runs the policy at the election
+// block, so it must be deterministic.
+type ElectionPolicy interface {
+	// ElectVerifiers returns at most size validators of the next epoch from
+	// the candidates, ranked by their power.
+	ElectVerifiers(ctx *ElectionContext, candidates staking.ValidatorQueue, size int) (staking.ValidatorQueue, error)
+
+	// Elect returns at most size validators from the candidates, the
+	// validators of the epoch that are not in the current round.
+	Elect(ctx *ElectionContext, candidates staking.ValidatorQueue, size int) (staking.ValidatorQueue, error)
+
+	// Order returns the validators of the next round in the order they
+	// produce blocks.
+	Order(ctx *ElectionContext, queue staking.ValidatorQueue) (staking.ValidatorQueue, error)
+}
+
+var electionPolicies = map[string]ElectionPolicy{
+	xcom.ElectionPolicyVRF:   vrfElectionPolicy{},
+	xcom.ElectionPolicyStake: stakeElectionPolicy{},
+}
+
+// RegisterElectionPolicy adds a policy that the genesis and the param
+// proposals can choose by the name, it must be called before the chain is
+// loaded, e.g. from an init function.
+func RegisterElectionPolicy(name string, policy ElectionPolicy) {
+	if _, ok := electionPolicies[name]; ok {
+		panic(fmt.Sprintf("election policy %s is registered twice", name))
+	}
+	electionPolicies[name] = policy
+	xcom.AddElectionPolicy(name)
+}
+
+// electionPolicy returns the policy in effect at the block, the chains not
+// upgraded to the version 1.5.0 elect by VRF.
+func (sk *StakingPlugin) electionPolicy(blockNumber uint64, blockHash common.Hash, currentVersion uint32) (ElectionPolicy, error) {
+	if !gov.Gte150Version(currentVersion) {
+		return electionPolicies[xcom.ElectionPolicyVRF], nil
+	}
+	name, err := gov.GovernElectionPolicy(blockNumber, blockHash)
+	if nil != err {
+		return nil, err
+	}
+	policy, ok := electionPolicies[name]
+	if !ok {
+		log.Error("Failed to find the election policy", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "policy", name)
+		return nil, fmt.Errorf("unknown election policy %s", name)
+	}
+	return policy, nil
+}
+
+// vrfElectionPolicy elects the validators with the probability weighted by
+// the square root of their shares, the VRF proofs of the previous blocks
+// decide the result. The validators of the epoch are the ones of the highest
+// power. It's the policy of the chains before the version 1.5.0.
+type vrfElectionPolicy struct{}
+
+func (vrfElectionPolicy) ElectVerifiers(ctx *ElectionContext, candidates staking.ValidatorQueue, size int) (staking.ValidatorQueue, error) {
+	if len(candidates) > size {
+		candidates = candidates[:size]
+	}
+	return candidates, nil
+}
+
+func (vrfElectionPolicy) Elect(ctx *ElectionContext, candidates staking.ValidatorQueue, size int) (staking.ValidatorQueue, error) {
+	return vrfElection(candidates, size, ctx.Nonce, ctx.ParentHash, ctx.BlockNumber, ctx.CurrentVersion)
+}
+
+func (vrfElectionPolicy) Order(ctx *ElectionContext, queue staking.ValidatorQueue) (staking.ValidatorQueue, error) {
+	return randomOrderValidatorQueue(ctx.BlockNumber, ctx.ParentHash, queue)
+}
+
+// stakeElectionPolicy elects the validators ranked by the program version and
+// the shares, the earlier staking wins a tie. The validators of the round
+// produce blocks in the same order.
+type stakeElectionPolicy struct{}
+
+func (p stakeElectionPolicy) ElectVerifiers(ctx *ElectionContext, candidates staking.ValidatorQueue, size int) (staking.ValidatorQueue, error) {
+	return p.Elect(ctx, candidates, size)
+}
+
+func (stakeElectionPolicy) Elect(ctx *ElectionContext, candidates staking.ValidatorQueue, size int) (staking.ValidatorQueue, error) {
+	ranked := rankByStake(candidates)
+	if len(ranked) > size {
+		ranked = ranked[:size]
+	}
+	return ranked, nil
+}
+
+func (stakeElectionPolicy) Order(ctx *ElectionContext, queue staking.ValidatorQueue) (staking.ValidatorQueue, error) {
+	return rankByStake(queue), nil
+}
+
+func rankByStake(queue staking.ValidatorQueue) staking.ValidatorQueue {
+	ranked := make(staking.ValidatorQueue, len(queue))
+	copy(ranked, queue)
+	ranked.ValidatorSort(nil, staking.CompareDefault)
+	return ranked
+}
@@ -0,0 +1,145 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package plugin
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/core/snapshotdb"
+	"github.com/hashkey-chain/hashkey-chain/p2p/discover"
+	"github.com/hashkey-chain/hashkey-chain/params"
+	"github.com/hashkey-chain/hashkey-chain/x/gov"
+	"github.com/hashkey-chain/hashkey-chain/x/staking"
+	"github.com/hashkey-chain/hashkey-chain/x/xcom"
+)
+
+func newElectionValidator(i byte, shares int64, stakingBlockNum uint64, version uint32) *staking.Validator {
+	return &staking.Validator{
+		NodeAddress:     common.NodeAddress{i},
+		NodeId:          discover.NodeID{i},
+		ProgramVersion:  version,
+		Shares:          big.NewInt(shares),
+		StakingBlockNum: stakingBlockNum,
+	}
+}
+
+func TestStakeElectionPolicy(t *testing.T) {
+	candidates := staking.ValidatorQueue{
+		newElectionValidator(1, 100, 10, params.FORKVERSION_1_5_0),
+		newElectionValidator(2, 300, 10, params.FORKVERSION_1_5_0),
+		newElectionValidator(3, 200, 20, params.FORKVERSION_1_5_0),
+		newElectionValidator(4, 200, 5, params.FORKVERSION_1_5_0),
+		newElectionValidator(5, 900, 1, params.FORKVERSION_1_4_0),
+	}
+	policy := electionPolicies[xcom.ElectionPolicyStake]
+	ctx := &ElectionContext{BlockNumber: 100, CurrentVersion: params.FORKVERSION_1_5_0}
+
+	elected, err := policy.Elect(ctx, candidates, 3)
+	assert.Nil(t, err)
+	// The higher version ranks first, then the shares and the earlier staking
+	assert.Equal(t, []byte{2, 4, 3}, electionIds(elected))
+	// The candidates are not reordered
+	assert.Equal(t, []byte{1, 2, 3, 4, 5}, electionIds(candidates))
+
+	elected, err = policy.Elect(ctx, candidates, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{2, 4, 3, 1, 5}, electionIds(elected))
+
+	ordered, err := policy.Order(ctx, staking.ValidatorQueue{candidates[0], candidates[3], candidates[1]})
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{2, 4, 1}, electionIds(ordered))
+}
+
+func TestElectionPolicy_ElectVerifiers(t *testing.T) {
+	// The candidates ranked by their power
+	candidates := staking.ValidatorQueue{
+		newElectionValidator(5, 900, 1, params.FORKVERSION_1_5_0),
+		newElectionValidator(2, 300, 10, params.FORKVERSION_1_5_0),
+		newElectionValidator(4, 200, 5, params.FORKVERSION_1_5_0),
+		newElectionValidator(3, 200, 20, params.FORKVERSION_1_5_0),
+	}
+	ctx := &ElectionContext{BlockNumber: 100, CurrentVersion: params.FORKVERSION_1_5_0}
+
+	elected, err := electionPolicies[xcom.ElectionPolicyVRF].ElectVerifiers(ctx, candidates, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{5, 2, 4}, electionIds(elected))
+
+	candidates[0].ProgramVersion = params.FORKVERSION_1_4_0
+	elected, err = electionPolicies[xcom.ElectionPolicyStake].ElectVerifiers(ctx, candidates, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{2, 4, 3}, electionIds(elected))
+
+	elected, err = electionPolicies[xcom.ElectionPolicyStake].ElectVerifiers(ctx, candidates, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{2, 4, 3, 5}, electionIds(elected))
+}
+
+func TestRegisterElectionPolicy(t *testing.T) {
+	assert.NotNil(t, xcom.CheckElectionPolicy("roundRobin"))
+
+	RegisterElectionPolicy("roundRobin", stakeElectionPolicy{})
+	defer func() {
+		delete(electionPolicies, "roundRobin")
+	}()
+	assert.Nil(t, xcom.CheckElectionPolicy("roundRobin"))
+	assert.Contains(t, xcom.ElectionPolicies(), "roundRobin")
+
+	assert.Panics(t, func() { RegisterElectionPolicy(xcom.ElectionPolicyVRF, stakeElectionPolicy{}) })
+}
+
+func TestStakingPlugin_electionPolicy(t *testing.T) {
+	newPlugins()
+	sdb := snapshotdb.Instance()
+	defer sdb.Clear()
+	if _, err := gov.InitGenesisGovernParam(common.ZeroHash, sdb, params.FORKVERSION_1_5_0); nil != err {
+		t.Fatal(err)
+	}
+	sk := StakingInstance()
+	blockHash := common.HexToHash("0x01")
+	assert.Nil(t, sdb.NewBlock(big.NewInt(1), common.ZeroHash, blockHash))
+
+	// The chains before the version 1.5.0 have no param
+	policy, err := sk.electionPolicy(1, blockHash, params.FORKVERSION_1_4_0)
+	assert.Nil(t, err)
+	assert.Equal(t, vrfElectionPolicy{}, policy)
+
+	policy, err = sk.electionPolicy(1, blockHash, params.FORKVERSION_1_5_0)
+	assert.Nil(t, err)
+	assert.Equal(t, vrfElectionPolicy{}, policy)
+
+	assert.Nil(t, gov.UpdateGovernParamValue(gov.ModuleStaking, gov.KeyElectionPolicy, xcom.ElectionPolicyStake, 2, blockHash))
+	policy, err = sk.electionPolicy(1, blockHash, params.FORKVERSION_1_5_0)
+	assert.Nil(t, err)
+	assert.Equal(t, vrfElectionPolicy{}, policy)
+	policy, err = sk.electionPolicy(2, blockHash, params.FORKVERSION_1_5_0)
+	assert.Nil(t, err)
+	assert.Equal(t, stakeElectionPolicy{}, policy)
+
+	assert.NotNil(t, gov.ElectionPolicyVerifier(2, blockHash, "random"))
+}
+
+func electionIds(queue staking.ValidatorQueue) []byte {
+	ids := make([]byte, len(queue))
+	for i, v := range queue {
+		ids[i] = v.NodeId[0]
+	}
+	return ids
+}
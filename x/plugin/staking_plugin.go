@@ -42,6 +42,7 @@ import (
 	"github.com/hashkey-chain/hashkey-chain/core/cbfttypes"
 	"github.com/hashkey-chain/hashkey-chain/core/snapshotdb"
 	"github.com/hashkey-chain/hashkey-chain/core/types"
+	"github.com/hashkey-chain/hashkey-chain/crypto/bls"
 	"github.com/hashkey-chain/hashkey-chain/crypto/vrf"
 	"github.com/hashkey-chain/hashkey-chain/event"
 	"github.com/hashkey-chain/hashkey-chain/log"
@@ -107,6 +108,12 @@ func (sk *StakingPlugin) BeginBlock(blockHash common.Hash, header *types.Header,
 	// adjust rewardPer and nextRewardPer
 	blockNumber := header.Number.Uint64()
 	if xutil.IsBeginOfEpoch(blockNumber) {
+		// the announced commission changes take effect before adjusting the validators
+		if err := sk.applyCommissionSchedules(blockHash, blockNumber, xutil.CalculateEpoch(blockNumber)); nil != err {
+			log.Error("Failed to call applyCommissionSchedules on stakingPlugin BeginBlock",
+				"blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "err", err)
+			return err
+		}
 		current, err := sk.getVerifierList(blockHash, blockNumber, QueryStartNotIrr)
 		if err != nil {
 			log.Error("Failed to query current round validators on stakingPlugin BeginBlock",
@@ -160,6 +167,20 @@ func (sk *StakingPlugin) EndBlock(blockHash common.Hash, header *types.Header, s
 			return err
 		}
 
+		// the candidates removed from the allowlist leave before the next epoch
+		if err := sk.withdrawRemovedFromAllowlist(state, blockHash, header.Number.Uint64()); nil != err {
+			log.Error("Failed to call withdrawRemovedFromAllowlist on stakingPlugin EndBlock",
+				"blockNumber", header.Number.Uint64(), "blockHash", blockHash.Hex(), "err", err)
+			return err
+		}
+
+		// the rotated keys of candidates take effect at next epoch
+		if err := sk.applyKeyRotations(blockHash, header.Number.Uint64(), epoch+1); nil != err {
+			log.Error("Failed to call applyKeyRotations on stakingPlugin EndBlock",
+				"blockNumber", header.Number.Uint64(), "blockHash", blockHash.Hex(), "err", err)
+			return err
+		}
+
 		// Election next epoch validators
 		if err := sk.ElectNextVerifierList(blockHash, header.Number.Uint64(), state); nil != err {
 			log.Error("Failed to call ElectNextVerifierList on stakingPlugin EndBlock",
@@ -294,8 +315,7 @@ func (sk *StakingPlugin) CreateCandidate(state xcom.StateDB, blockHash common.Ha
 				"stakeAddr", can.StakingAddress, "originVon", origin, "stakingVon", amount)
 			return staking.ErrAccountVonNoEnough
 		}
-		state.SubBalance(can.StakingAddress, amount)
-		state.AddBalance(vm.StakingContractAddr, amount)
+		transferBalance(state, can.StakingAddress, vm.StakingContractAddr, amount, gov.ModuleStaking, types.BalanceChangeStakingLock)
 		can.ReleasedHes = amount
 
 	} else if typ == RestrictVon { //  from account RestrictingPlan von
@@ -370,8 +390,7 @@ func (sk *StakingPlugin) RollBackStaking(state xcom.StateDB, blockHash common.Ha
 
 	if typ == FreeVon {
 
-		state.AddBalance(can.StakingAddress, can.ReleasedHes)
-		state.SubBalance(vm.StakingContractAddr, can.ReleasedHes)
+		transferBalance(state, vm.StakingContractAddr, can.StakingAddress, can.ReleasedHes, gov.ModuleStaking, types.BalanceChangeStakingRefund)
 
 	} else if typ == RestrictVon {
 
@@ -428,6 +447,149 @@ func (sk *StakingPlugin) EditCandidate(blockHash common.Hash, blockNumber *big.I
 	return nil
 }
 
+// RotateCandidateKey records the new consensus keys of the candidate and returns the Epoch they take effect at.
+// The new keys take effect from the first Epoch whose first round validators is not elected yet,
+// the candidate keeps the staking nodeId as its identity, only the consensus nodeId and bls key are changed.
+func (sk *StakingPlugin) RotateCandidateKey(blockHash common.Hash, blockNumber *big.Int, canAddr common.NodeAddress,
+	can *staking.CandidateBase, nodeId discover.NodeID, blsPubKey bls.PublicKeyHex) (uint64, error) {
+
+	number := blockNumber.Uint64()
+	epoch := xutil.CalculateEpoch(number)
+
+	// The first round validators of next Epoch had been elected after the last election block of current Epoch
+	effectiveEpoch := epoch + 1
+	if epochEnd := xutil.CalcEpochEndBlock(epoch); number > epochEnd-xutil.ElectionDistanceAt(epochEnd) {
+		effectiveEpoch++
+	}
+
+	currNodeId, currBlsPubKey, err := sk.getCanKey(blockHash, canAddr, can, effectiveEpoch)
+	if nil != err {
+		log.Error("Failed to RotateCandidateKey on stakingPlugin: Query candidate keys is failed",
+			"blockNumber", number, "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
+		return 0, err
+	}
+	if currNodeId == nodeId && bytes.Equal(currBlsPubKey.Bytes(), blsPubKey.Bytes()) {
+		return 0, staking.ErrCanKeysNotChanged
+	}
+
+	newAddr, err := xutil.NodeId2Addr(nodeId)
+	if nil != err {
+		return 0, staking.ErrNodeID2Addr
+	}
+
+	if newAddr != canAddr {
+		// the nodeId can not be the staking nodeId of other candidate
+		canBase, err := sk.db.GetCanBaseStore(blockHash, newAddr)
+		if snapshotdb.NonDbNotFoundErr(err) {
+			log.Error("Failed to RotateCandidateKey on stakingPlugin: Query CandidateBase info is failed",
+				"blockNumber", number, "blockHash", blockHash.Hex(), "newNodeId", nodeId.String(), "err", err)
+			return 0, err
+		}
+		if nil == err && canBase.IsNotEmpty() {
+			return 0, staking.ErrNodeIdAlreadyUsed
+		}
+
+		// nor the consensus nodeId of other candidate, now or ever
+		if used, err := sk.HasNodeIdAlias(blockHash, newAddr, canAddr); nil != err {
+			log.Error("Failed to RotateCandidateKey on stakingPlugin: Query nodeId alias is failed",
+				"blockNumber", number, "blockHash", blockHash.Hex(), "newNodeId", nodeId.String(), "err", err)
+			return 0, err
+		} else if used {
+			return 0, staking.ErrNodeIdAlreadyUsed
+		}
+
+		// Reserve the nodeId for the candidate right now,
+		// so that two candidates can not rotate to the same nodeId
+		if err := sk.db.SetNodeIdAliasStore(blockHash, newAddr, canAddr); nil != err {
+			log.Error("Failed to RotateCandidateKey on stakingPlugin: Store nodeId alias is failed",
+				"blockNumber", number, "blockHash", blockHash.Hex(), "newNodeId", nodeId.String(), "err", err)
+			return 0, err
+		}
+	}
+
+	// The nodeId of the pending rotation replaced by this one is released,
+	// unless the candidate uses it now or in the other pending rotation.
+	if pending, err := sk.db.GetKeyRotationStore(blockHash, effectiveEpoch, canAddr); nil == err {
+		inUse := map[discover.NodeID]struct{}{nodeId: {}}
+		for e := epoch; e < effectiveEpoch; e++ {
+			id, _, err := sk.getCanKey(blockHash, canAddr, can, e)
+			if nil != err {
+				return 0, err
+			}
+			inUse[id] = struct{}{}
+			if other, err := sk.db.GetKeyRotationStore(blockHash, e, canAddr); nil == err {
+				inUse[other.NodeId] = struct{}{}
+			} else if snapshotdb.NonDbNotFoundErr(err) {
+				return 0, err
+			}
+		}
+		if err := sk.releaseNodeIdAlias(blockHash, canAddr, pending.NodeId, inUse); nil != err {
+			log.Error("Failed to RotateCandidateKey on stakingPlugin: Release nodeId alias is failed",
+				"blockNumber", number, "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
+			return 0, err
+		}
+	} else if snapshotdb.NonDbNotFoundErr(err) {
+		return 0, err
+	}
+
+	key := &staking.CandidateKey{
+		Epoch:     effectiveEpoch,
+		NodeId:    nodeId,
+		BlsPubKey: blsPubKey,
+	}
+	if err := sk.db.SetKeyRotationStore(blockHash, canAddr, key); nil != err {
+		log.Error("Failed to RotateCandidateKey on stakingPlugin: Store key rotation is failed",
+			"blockNumber", number, "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "key", key, "err", err)
+		return 0, err
+	}
+
+	log.Info("Call RotateCandidateKey on stakingPlugin", "blockNumber", number, "blockHash", blockHash.Hex(),
+		"nodeId", can.NodeId.String(), "newNodeId", nodeId.String(), "effectiveEpoch", effectiveEpoch)
+	return effectiveEpoch, nil
+}
+
+// ScheduleCommission records the commission change announced by the candidate,
+// it replaces the change which the candidate is still waiting for.
+func (sk *StakingPlugin) ScheduleCommission(blockHash common.Hash, blockNumber *big.Int, canAddr common.NodeAddress,
+	nodeId discover.NodeID, schedule *staking.CommissionSchedule) error {
+
+	pending, err := sk.db.GetCommissionScheduleStore(blockHash, canAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		log.Error("Failed to ScheduleCommission: Query commission schedule is failed", "blockNumber", blockNumber.Uint64(),
+			"blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+		return err
+	}
+	if nil == err {
+		if err := sk.db.DelCommissionScheduleStore(blockHash, canAddr, pending); nil != err {
+			log.Error("Failed to ScheduleCommission: Delete commission schedule is failed", "blockNumber", blockNumber.Uint64(),
+				"blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+			return err
+		}
+	}
+
+	if err := sk.db.SetCommissionScheduleStore(blockHash, canAddr, schedule); nil != err {
+		log.Error("Failed to ScheduleCommission: Store commission schedule is failed", "blockNumber", blockNumber.Uint64(),
+			"blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "err", err)
+		return err
+	}
+
+	log.Debug("Call ScheduleCommission, the commission change is announced", "blockNumber", blockNumber.Uint64(),
+		"blockHash", blockHash.Hex(), "nodeId", nodeId.String(), "schedule", schedule)
+
+	sk.postEvent(staking.CommissionScheduledEvent{NodeId: nodeId, Schedule: schedule})
+	return nil
+}
+
+func (sk *StakingPlugin) GetCommissionSchedule(blockHash common.Hash, canAddr common.NodeAddress) (*staking.CommissionSchedule, error) {
+	return sk.db.GetCommissionScheduleStore(blockHash, canAddr)
+}
+
+// GetCommissionHistory returns the commission ratios in force since 1.4.0,
+// the candidates staked before keep no record until their first change.
+func (sk *StakingPlugin) GetCommissionHistory(blockHash common.Hash, canAddr common.NodeAddress) (staking.CommissionHistory, error) {
+	return sk.db.GetCommissionHistoryStore(blockHash, canAddr)
+}
+
 func (sk *StakingPlugin) IncreaseStaking(state xcom.StateDB, blockHash common.Hash, blockNumber,
 	amount *big.Int, typ uint16, canAddr common.NodeAddress, can *staking.Candidate) error {
 
@@ -444,8 +606,7 @@ func (sk *StakingPlugin) IncreaseStaking(state xcom.StateDB, blockHash common.Ha
 				"originVon", origin, "stakingVon", amount)
 			return staking.ErrAccountVonNoEnough
 		}
-		state.SubBalance(can.StakingAddress, amount)
-		state.AddBalance(vm.StakingContractAddr, amount)
+		transferBalance(state, can.StakingAddress, vm.StakingContractAddr, amount, gov.ModuleStaking, types.BalanceChangeStakingLock)
 		can.ReleasedHes = new(big.Int).Add(can.ReleasedHes, amount)
 
 	} else if typ == RestrictVon {
@@ -521,6 +682,12 @@ func (sk *StakingPlugin) WithdrewStaking(state xcom.StateDB, blockHash common.Ha
 		}
 	} else {
 
+		if err := sk.releaseCanKeys(blockHash, blockNumber.Uint64(), canAddr, can.CandidateBase); nil != err {
+			log.Error("Failed to WithdrewStaking on stakingPlugin: Release the rotated keys is failed",
+				"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
+			return err
+		}
+
 		if err := sk.db.DelCandidateStore(blockHash, canAddr); nil != err {
 			log.Error("Failed to WithdrewStaking on stakingPlugin: Delete Candidate info is failed",
 				"blockNumber", blockNumber.Uint64(), "blockHash", blockHash.Hex(), "nodeId", can.NodeId.String(), "err", err)
@@ -545,8 +712,7 @@ func (sk *StakingPlugin) withdrewStakeAmount(state xcom.StateDB, blockHash commo
 	// Direct return of money during the hesitation period
 	// Return according to the way of coming
 	if can.ReleasedHes.Cmp(common.Big0) > 0 {
-		state.AddBalance(can.StakingAddress, can.ReleasedHes)
-		state.SubBalance(vm.StakingContractAddr, can.ReleasedHes)
+		transferBalance(state, vm.StakingContractAddr, can.StakingAddress, can.ReleasedHes, gov.ModuleStaking, types.BalanceChangeStakingRefund)
 		can.ReleasedHes = new(big.Int).SetInt64(0)
 	}
 
@@ -729,8 +895,7 @@ func (sk *StakingPlugin) handleUnStake(state xcom.StateDB, blockNumber uint64, b
 
 	refundReleaseFn := func(balance *big.Int) *big.Int {
 		if balance.Cmp(common.Big0) > 0 {
-			state.AddBalance(can.StakingAddress, balance)
-			state.SubBalance(vm.StakingContractAddr, balance)
+			transferBalance(state, vm.StakingContractAddr, can.StakingAddress, balance, gov.ModuleStaking, types.BalanceChangeStakingRefund)
 			return new(big.Int).SetInt64(0)
 		}
 		return balance
@@ -765,6 +930,13 @@ func (sk *StakingPlugin) handleUnStake(state xcom.StateDB, blockNumber uint64, b
 		can.RestrictingPlan = balance
 	}
 
+	if err := sk.releaseCanKeys(blockHash, blockNumber, addr, can.CandidateBase); nil != err {
+		log.Error("Failed to HandleUnCandidateItem: Release the rotated keys failed",
+			"blockNumber", blockNumber, "blockHash", blockHash.Hex(),
+			"nodeId", can.NodeId.String(), "err", err)
+		return err
+	}
+
 	if err := sk.db.DelCandidateStore(blockHash, addr); nil != err {
 		log.Error("Failed to HandleUnCandidateItem: Delete candidate info failed",
 			"blockNumber", blockNumber, "blockHash", blockHash.Hex(),
@@ -845,8 +1017,7 @@ func (sk *StakingPlugin) Delegate(state xcom.StateDB, blockHash common.Hash, blo
 				"originVon", origin, "delegateVon", amount)
 			return staking.ErrAccountVonNoEnough
 		}
-		state.SubBalance(delAddr, amount)
-		state.AddBalance(vm.StakingContractAddr, amount)
+		transferBalance(state, delAddr, vm.StakingContractAddr, amount, gov.ModuleStaking, types.BalanceChangeDelegationLock)
 		del.ReleasedHes = new(big.Int).Add(del.ReleasedHes, amount)
 
 	} else if typ == RestrictVon { //  from account RestrictingPlan von
@@ -1181,8 +1352,7 @@ func (sk *StakingPlugin) RedeemDelegation(state xcom.StateDB, blockHash common.H
 	redeem := false
 
 	if delegationLock.Released.Cmp(common.Big0) > 0 {
-		state.SubBalance(vm.StakingContractAddr, delegationLock.Released)
-		state.AddBalance(delAddr, delegationLock.Released)
+		transferBalance(state, vm.StakingContractAddr, delAddr, delegationLock.Released, gov.ModuleStaking, types.BalanceChangeDelegationRefund)
 		released.Set(delegationLock.Released)
 		delegationLock.Released = new(big.Int)
 		redeem = true
@@ -1215,8 +1385,7 @@ func rufundDelegateFn(refundBalance, aboutRelease, aboutRestrictingPlan *big.Int
 	restrictingPlanTmp := aboutRestrictingPlan
 
 	subDelegateFn := func(source, sub *big.Int) (*big.Int, *big.Int) {
-		state.AddBalance(delAddr, sub)
-		state.SubBalance(vm.StakingContractAddr, sub)
+		transferBalance(state, vm.StakingContractAddr, delAddr, sub, gov.ModuleStaking, types.BalanceChangeDelegationRefund)
 		return new(big.Int).Sub(source, sub), new(big.Int).SetInt64(0)
 	}
 
@@ -1310,8 +1479,9 @@ func (sk *StakingPlugin) ElectNextVerifierList(blockHash common.Hash, blockNumbe
 	// caculate the new epoch start and end
 	newVerifierArr := &staking.ValidatorArray{
 		Start: oldIndex.End + 1,
-		End:   oldIndex.End + xutil.CalcBlocksEachEpoch(),
+		End:   oldIndex.End + xutil.CalcBlocksEachEpochAt(oldIndex.End+1),
 	}
+	newEpoch := xutil.CalculateEpoch(newVerifierArr.Start)
 
 	currOriginVersion := gov.GetVersionForStaking(blockHash, state)
 	currVersion := xutil.CalcVersion(currOriginVersion)
@@ -1329,7 +1499,25 @@ func (sk *StakingPlugin) ElectNextVerifierList(blockHash common.Hash, blockNumbe
 		return err
 	}
 
-	iter := sk.db.IteratorCandidatePowerByBlockHash(blockHash, int(maxvalidators))
+	admitted, err := sk.admission(blockNumber, blockHash, state)
+	if nil != err {
+		log.Error("Failed to ElectNextVerifierList: query the allowlist is failed", "blockNumber",
+			blockNumber, "blockHash", blockHash.Hex(), "err", err)
+		return err
+	}
+
+	policy, err := sk.electionPolicy(blockNumber, blockHash, currentVersion)
+	if nil != err {
+		return err
+	}
+
+	// The policy chooses among all the candidates since the version 1.5.0,
+	// the chains before take the ones of the highest power
+	ranges := int(maxvalidators)
+	if gov.Gte150Version(currentVersion) {
+		ranges = 0
+	}
+	iter := sk.db.IteratorCandidatePowerByBlockHash(blockHash, ranges)
 	if err := iter.Error(); nil != err {
 		log.Error("Failed to ElectNextVerifierList: take iter by candidate power is failed", "blockNumber",
 			blockNumber, "blockHash", blockHash.Hex(), "err", err)
@@ -1368,6 +1556,15 @@ func (sk *StakingPlugin) ElectNextVerifierList(blockHash common.Hash, blockNumbe
 
 		addr := common.BytesToNodeAddress(addrSuffix)
 
+		// The node not in the allowlist cannot be elected for epoch validator
+		if ok, err := admitted(addr); nil != err {
+			return err
+		} else if !ok {
+			log.Warn("Warn ElectNextVerifierList: the can is not in the allowlist",
+				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "nodeId", canBase.NodeId.String(), "canAddr", addr.Hex())
+			continue
+		}
+
 		canMutable, err := sk.db.GetCanMutableStoreWithSuffix(blockHash, addrSuffix)
 		if nil != err {
 			log.Error("Failed to ElectNextVerifierList: Query CandidateMutable info is failed", "blockNumber", blockNumber,
@@ -1375,10 +1572,18 @@ func (sk *StakingPlugin) ElectNextVerifierList(blockHash common.Hash, blockNumbe
 			return err
 		}
 
+		// the candidate may have rotated its consensus keys
+		nodeId, blsPubKey, err := sk.getCanKey(blockHash, addr, canBase, newEpoch)
+		if nil != err {
+			log.Error("Failed to ElectNextVerifierList: Query candidate keys is failed", "blockNumber", blockNumber,
+				"blockHash", blockHash.Hex(), "canAddr", addr.Hex(), "err", err)
+			return err
+		}
+
 		val := &staking.Validator{
 			NodeAddress:     addr,
-			NodeId:          canBase.NodeId,
-			BlsPubKey:       canBase.BlsPubKey,
+			NodeId:          nodeId,
+			BlsPubKey:       blsPubKey,
 			ProgramVersion:  canBase.ProgramVersion,
 			Shares:          canMutable.Shares,
 			StakingBlockNum: canBase.StakingBlockNum,
@@ -1388,6 +1593,13 @@ func (sk *StakingPlugin) ElectNextVerifierList(blockHash common.Hash, blockNumbe
 		queue = append(queue, val)
 	}
 
+	queue, err = policy.ElectVerifiers(&ElectionContext{BlockNumber: blockNumber, CurrentVersion: currentVersion}, queue, int(maxvalidators))
+	if nil != err {
+		log.Error("Failed to ElectNextVerifierList: elect the verifiers is failed", "blockNumber",
+			blockNumber, "blockHash", blockHash.Hex(), "err", err)
+		return err
+	}
+
 	if len(queue) == 0 {
 		panic("Failed to ElectNextVerifierList: Select zero size validators~")
 	}
@@ -1534,7 +1746,16 @@ func (sk *StakingPlugin) ListVerifierNodeID(blockHash common.Hash, blockNumber u
 	queue := make([]discover.NodeID, len(verifierList.Arr))
 
 	for i, v := range verifierList.Arr {
-		queue[i] = v.NodeId
+		// the governance identifies the verifier by the staking nodeId, which differs from the rotated one
+		canBase, err := sk.db.GetCanBaseStore(blockHash, v.NodeAddress)
+		if snapshotdb.NonDbNotFoundErr(err) {
+			return nil, err
+		}
+		if nil == err && canBase.IsNotEmpty() {
+			queue[i] = canBase.NodeId
+		} else {
+			queue[i] = v.NodeId
+		}
 	}
 	return queue, nil
 }
@@ -1802,6 +2023,34 @@ func (sk *StakingPlugin) GetCanBaseList(blockHash common.Hash, blockNumber uint6
 	return queue, nil
 }
 
+// GetCandidateAccounts returns the staking and benefit addresses of all the valid candidates
+func (sk *StakingPlugin) GetCandidateAccounts(blockHash common.Hash) (map[common.Address]struct{}, error) {
+
+	iter := sk.db.IteratorCandidatePowerByBlockHash(blockHash, 0)
+	if err := iter.Error(); nil != err {
+		return nil, err
+	}
+	defer iter.Release()
+
+	accounts := make(map[common.Address]struct{})
+
+	for iter.Valid(); iter.Next(); {
+
+		addrSuffix := iter.Value()
+		can, err := sk.db.GetCandidateStoreWithSuffix(blockHash, addrSuffix)
+		if nil != err {
+			return nil, err
+		}
+		if can.IsInvalid() {
+			continue
+		}
+		accounts[can.StakingAddress] = struct{}{}
+		accounts[can.BenefitAddress] = struct{}{}
+	}
+
+	return accounts, nil
+}
+
 func (sk *StakingPlugin) IsCandidate(blockHash common.Hash, nodeId discover.NodeID, isCommit bool) (bool, error) {
 
 	var can *staking.Candidate
@@ -1891,9 +2140,9 @@ func (sk *StakingPlugin) Election(blockHash common.Hash, header *types.Header, s
 		return staking.ErrValidatorNoExist
 	}
 
-	if blockNumber != (curr.End - xcom.ElectionDistance()) {
+	if blockNumber != (curr.End - xutil.ElectionDistanceAt(curr.End)) {
 		log.Error("Failed to Election: Current blockNumber invalid", "blockNumber", blockNumber, "blockHash", blockHash.Hex(),
-			"Target blockNumber", curr.End-xcom.ElectionDistance())
+			"Target blockNumber", curr.End-xutil.ElectionDistanceAt(curr.End))
 		return staking.ErrBlockNumberDisordered
 	}
 
@@ -1904,7 +2153,7 @@ func (sk *StakingPlugin) Election(blockHash common.Hash, header *types.Header, s
 
 	// caculate the next round start and end
 	start := curr.End + 1
-	end := curr.End + xutil.ConsensusSize()
+	end := curr.End + xutil.ConsensusSizeAt(start)
 
 	hasSlashLen := 0 // duplicateSign And lowRatio No enough von
 	needRMwithdrewLen := 0
@@ -1941,11 +2190,17 @@ func (sk *StakingPlugin) Election(blockHash common.Hash, header *types.Header, s
 		return errors.New("Failed to get CurrentActiveVersion")
 	}
 
+	admitted, err := sk.admission(blockNumber, blockHash, state)
+	if nil != err {
+		log.Error("Failed to Election, query the allowlist is failed", "blockNumber", blockNumber, "blockHash", blockHash.TerminalString(), "err", err)
+		return err
+	}
+
 	currMap := make(map[discover.NodeID]*big.Int, len(curr.Arr))
 	currqueen := make([]*staking.Validator, 0)
 	for _, v := range curr.Arr {
 
-		canAddr, _ := xutil.NodeId2Addr(v.NodeId)
+		canAddr := v.NodeAddress
 		can, err := sk.db.GetCandidateStore(blockHash, canAddr)
 		if nil != err {
 			log.Error("Failed to Query Candidate Info on Election", "blockNumber", blockNumber,
@@ -1987,6 +2242,17 @@ func (sk *StakingPlugin) Election(blockHash common.Hash, header *types.Header, s
 			needRMLowVersionLen++
 		}
 
+		// Collect candidate who need to be removed
+		// from the validators because it's not in the allowlist.
+		// The validator removed from the allowlist is dropped at the next round,
+		// it's still a verifier of the epoch until the end of the epoch withdraws it
+		if ok, err := admitted(canAddr); nil != err {
+			return err
+		} else if !ok {
+			removeCans[v.NodeId] = can
+			invalidCan[can.NodeId] = struct{}{}
+		}
+
 		currMap[v.NodeId] = v.Shares
 		currqueen = append(currqueen, v)
 	}
@@ -2004,8 +2270,7 @@ func (sk *StakingPlugin) Election(blockHash common.Hash, header *types.Header, s
 			continue
 		}
 
-		addr, _ := xutil.NodeId2Addr(v.NodeId)
-		can, err := sk.db.GetCandidateStore(blockHash, addr)
+		can, err := sk.db.GetCandidateStore(blockHash, v.NodeAddress)
 		if nil != err {
 			log.Error("Failed to Get Candidate on Election", "blockNumber", blockNumber,
 				"blockHash", blockHash.Hex(), "nodeId", v.NodeId.String(), "err", err)
@@ -2026,6 +2291,13 @@ func (sk *StakingPlugin) Election(blockHash common.Hash, header *types.Header, s
 			continue
 		}
 
+		// Ignore the node not in the allowlist
+		if ok, err := admitted(v.NodeAddress); nil != err {
+			return err
+		} else if !ok {
+			continue
+		}
+
 		diffQueue = append(diffQueue, v)
 	}
 
@@ -2058,7 +2330,18 @@ func (sk *StakingPlugin) Election(blockHash common.Hash, header *types.Header, s
 	//invalidLen = hasSlashLen + needRMwithdrewLen + needRMLowVersionLen
 	invalidLen = len(invalidCan)
 
-	shuffle := func(invalidLen int, currQueue, vrfQueue staking.ValidatorQueue, blockNumber uint64, parentHash common.Hash) (staking.ValidatorQueue, error) {
+	policy, err := sk.electionPolicy(blockNumber, blockHash, currentVersion)
+	if nil != err {
+		return err
+	}
+	electionCtx := &ElectionContext{
+		BlockNumber:    blockNumber,
+		ParentHash:     header.ParentHash,
+		Nonce:          header.Nonce.Bytes(),
+		CurrentVersion: currentVersion,
+	}
+
+	shuffle := func(invalidLen int, currQueue, vrfQueue staking.ValidatorQueue) (staking.ValidatorQueue, error) {
 
 		// increase term and use new shares  one by one
 		for i, v := range currQueue {
@@ -2073,20 +2356,20 @@ func (sk *StakingPlugin) Election(blockHash common.Hash, header *types.Header, s
 		copyCurrQueue := make(staking.ValidatorQueue, len(currQueue)-invalidLen)
 		// Remove the invalid validators
 		copy(copyCurrQueue, currQueue[invalidLen:])
-		return shuffleQueue(copyCurrQueue, vrfQueue, blockNumber, parentHash)
+		return shuffleQueue(copyCurrQueue, vrfQueue, policy, electionCtx)
 	}
 
 	var vrfQueue staking.ValidatorQueue
 	var vrfLen int
-	if len(diffQueue) > int(xcom.MaxConsensusVals()) {
-		vrfLen = int(xcom.MaxConsensusVals())
+	if len(diffQueue) > int(xcom.MaxConsensusVals(blockNumber)) {
+		vrfLen = int(xcom.MaxConsensusVals(blockNumber))
 	} else {
 		vrfLen = len(diffQueue)
 	}
 
 	if vrfLen != 0 {
-		if queue, err := vrfElection(diffQueue, vrfLen, header.Nonce.Bytes(), header.ParentHash, blockNumber, currentVersion); nil != err {
-			log.Error("Failed to VrfElection on Election",
+		if queue, err := policy.Elect(electionCtx, diffQueue, vrfLen); nil != err {
+			log.Error("Failed to elect validators on Election",
 				"blockNumber", blockNumber, "blockHash", blockHash.Hex(), "err", err)
 			return err
 		} else {
@@ -2098,11 +2381,11 @@ func (sk *StakingPlugin) Election(blockHash common.Hash, header *types.Header, s
 		"has slash count", hasSlashLen, "withdrew and need remove count",
 		needRMwithdrewLen, "low version need remove count", needRMLowVersionLen,
 		"total remove count", invalidLen, "remove map size", len(removeCans),
-		"current validators Size", len(curr.Arr), "MaxConsensusVals", xcom.MaxConsensusVals(),
-		"ShiftValidatorNum", xcom.ShiftValidatorNum(), "diffQueueLen", len(diffQueue),
+		"current validators Size", len(curr.Arr), "MaxConsensusVals", xcom.MaxConsensusVals(blockNumber),
+		"ShiftValidatorNum", xcom.ShiftValidatorNum(blockNumber), "diffQueueLen", len(diffQueue),
 		"vrfQueueLen", len(vrfQueue))
 
-	nextQueue, err := shuffle(invalidLen, currqueen, vrfQueue, blockNumber, header.ParentHash)
+	nextQueue, err := shuffle(invalidLen, currqueen, vrfQueue)
 	if nil != err {
 		return err
 	}
@@ -2111,6 +2394,15 @@ func (sk *StakingPlugin) Election(blockHash common.Hash, header *types.Header, s
 		panic("The Next Round Validator is empty, blockNumber: " + fmt.Sprint(blockNumber))
 	}
 
+	// The rotated keys of the validators take effect from the first round of next Epoch
+	if xutil.IsBeginOfEpoch(start) {
+		if err := sk.rotateValidatorKeys(blockHash, xutil.CalculateEpoch(start), nextQueue); nil != err {
+			log.Error("Failed to rotateValidatorKeys on Election", "blockNumber", blockNumber,
+				"blockHash", blockHash.Hex(), "err", err)
+			return err
+		}
+	}
+
 	next := &staking.ValidatorArray{
 		Start: start,
 		End:   end,
@@ -2151,29 +2443,29 @@ func (sk *StakingPlugin) Election(blockHash common.Hash, header *types.Header, s
 	return nil
 }
 
-func shuffleQueue(remainCurrQueue, vrfQueue staking.ValidatorQueue, blockNumber uint64, parentHash common.Hash) (staking.ValidatorQueue, error) {
+func shuffleQueue(remainCurrQueue, vrfQueue staking.ValidatorQueue, policy ElectionPolicy, ctx *ElectionContext) (staking.ValidatorQueue, error) {
 
 	remainLen := len(remainCurrQueue)
 	totalQueue := append(remainCurrQueue, vrfQueue...)
 
-	for remainLen > int(xcom.MaxConsensusVals()-xcom.ShiftValidatorNum()) && len(totalQueue) > int(xcom.MaxConsensusVals()) {
+	for remainLen > int(xcom.MaxConsensusVals(ctx.BlockNumber)-xcom.ShiftValidatorNum(ctx.BlockNumber)) && len(totalQueue) > int(xcom.MaxConsensusVals(ctx.BlockNumber)) {
 		totalQueue = totalQueue[1:]
 		remainLen--
 	}
 
-	if len(totalQueue) > int(xcom.MaxConsensusVals()) {
-		totalQueue = totalQueue[:xcom.MaxConsensusVals()]
+	if len(totalQueue) > int(xcom.MaxConsensusVals(ctx.BlockNumber)) {
+		totalQueue = totalQueue[:xcom.MaxConsensusVals(ctx.BlockNumber)]
 	}
 
 	next := make(staking.ValidatorQueue, len(totalQueue))
 
 	copy(next, totalQueue)
 
-	// Divide all consensus nodes into two groups, the front and back positions of each group are not changed,
+	// The VRF policy divides all consensus nodes into two groups, the front and back positions of each group are not changed,
 	// but random ordering is performed in each group
 	// The first group: the first f nodes
 	// The second group: the last 2f + 1 nodes
-	next, err := randomOrderValidatorQueue(blockNumber, parentHash, next)
+	next, err := policy.Order(ctx, next)
 	if nil != err {
 		return nil, err
 	}
@@ -2213,7 +2505,7 @@ func randomOrderValidatorQueue(blockNumber uint64, parentHash common.Hash, queue
 		preNonces = preNonces[len(preNonces)-len(queue):]
 	}
 
-	if len(queue) <= int(xcom.ShiftValidatorNum()) {
+	if len(queue) <= int(xcom.ShiftValidatorNum(blockNumber)) {
 		return queue, nil
 	}
 
@@ -2227,8 +2519,8 @@ func randomOrderValidatorQueue(blockNumber uint64, parentHash common.Hash, queue
 		log.Debug("Call randomOrderValidatorQueue xor", "nodeId", v.NodeId.TerminalString(), "nodeAddress", v.NodeAddress.Hex(), "nonce", hexutil.Encode(preNonces[i]), "xorValue", value)
 	}
 
-	frontPart := orderList[:xcom.ShiftValidatorNum()]
-	backPart := orderList[xcom.ShiftValidatorNum():]
+	frontPart := orderList[:xcom.ShiftValidatorNum(blockNumber)]
+	backPart := orderList[xcom.ShiftValidatorNum(blockNumber):]
 
 	sort.Sort(frontPart)
 	sort.Sort(backPart)
@@ -2250,26 +2542,46 @@ func (sk *StakingPlugin) SlashCandidates(state xcom.StateDB, blockHash common.Ha
 
 	// Nodes that need to be deleted from the candidate list
 	// Keep governance votes that have been voted
-	invalidNodeIdMap := make(map[discover.NodeID]struct{}, 0)
+	invalidAddrMap := make(map[common.NodeAddress]struct{}, 0)
 	// Need to remove eligibility to govern voting
 	invalidRemoveGovNodeIdMap := make(map[discover.NodeID]struct{}, 0)
 
 	for _, slashItem := range queue {
+
+		canAddr, _ := xutil.NodeId2Addr(slashItem.NodeId)
+		addr, err := sk.resolveCanAddr(blockHash, canAddr, QueryStartNotIrr)
+		if nil != err {
+			return err
+		}
+		// The rotated nodeId is replaced by the nodeId staked with
+		if addr != canAddr {
+			canBase, err := sk.db.GetCanBaseStore(blockHash, addr)
+			if nil != err {
+				log.Error("Failed to SlashCandidates: Query CandidateBase info of rotated nodeId is failed", "blockNumber", blockNumber,
+					"blockHash", blockHash.Hex(), "nodeId", slashItem.NodeId.String(), "canAddr", addr.Hex(), "err", err)
+				return err
+			}
+			item := *slashItem
+			item.NodeId = canBase.NodeId
+			slashItem = &item
+			canAddr = addr
+		}
+
 		needRemove, err := sk.toSlash(state, blockNumber, blockHash, slashItem)
 		if nil != err {
 			return err
 		}
 		if needRemove {
-			invalidNodeIdMap[slashItem.NodeId] = struct{}{}
+			invalidAddrMap[canAddr] = struct{}{}
 			if slashItem.SlashType != staking.LowRatio {
 				invalidRemoveGovNodeIdMap[slashItem.NodeId] = struct{}{}
 			}
 		}
 	}
 
-	if len(invalidNodeIdMap) != 0 {
+	if len(invalidAddrMap) != 0 {
 		// remove the validator from epoch verifierList
-		if err := sk.removeFromVerifiers(blockNumber, blockHash, invalidNodeIdMap); nil != err {
+		if err := sk.removeFromVerifiers(blockNumber, blockHash, invalidAddrMap); nil != err {
 			return err
 		}
 		if len(invalidRemoveGovNodeIdMap) > 0 {
@@ -2420,8 +2732,7 @@ func (sk *StakingPlugin) toSlash(state xcom.StateDB, blockNumber uint64, blockHa
 	if needReturnHes {
 		// Return the staked deposit during the hesitation period
 		if can.ReleasedHes.Cmp(common.Big0) > 0 {
-			state.AddBalance(can.StakingAddress, can.ReleasedHes)
-			state.SubBalance(vm.StakingContractAddr, can.ReleasedHes)
+			transferBalance(state, vm.StakingContractAddr, can.StakingAddress, can.ReleasedHes, gov.ModuleStaking, types.BalanceChangeStakingRefund)
 			can.ReleasedHes = new(big.Int).SetInt64(0)
 		}
 		if can.RestrictingPlanHes.Cmp(common.Big0) > 0 {
@@ -2498,11 +2809,11 @@ func (sk *StakingPlugin) toSlash(state xcom.StateDB, blockNumber uint64, blockHa
 	return needRemove, nil
 }
 
-func (sk *StakingPlugin) removeFromVerifiers(blockNumber uint64, blockHash common.Hash, slashNodeIdMap map[discover.NodeID]struct{}) error {
+func (sk *StakingPlugin) removeFromVerifiers(blockNumber uint64, blockHash common.Hash, slashAddrMap map[common.NodeAddress]struct{}) error {
 	verifier, err := sk.getVerifierList(blockHash, blockNumber, QueryStartNotIrr)
 	if nil != err {
 		log.Error("Failed to SlashCandidates: Query Verifier List is failed", "blockNumber", blockNumber,
-			"blockHash", blockHash.Hex(), "nodeIdQueue Size", len(slashNodeIdMap), "err", err)
+			"blockHash", blockHash.Hex(), "nodeIdQueue Size", len(slashAddrMap), "err", err)
 		return err
 	}
 
@@ -2513,7 +2824,7 @@ func (sk *StakingPlugin) removeFromVerifiers(blockNumber uint64, blockHash commo
 
 		val := verifier.Arr[i]
 
-		if _, ok := slashNodeIdMap[val.NodeId]; ok {
+		if _, ok := slashAddrMap[val.NodeAddress]; ok {
 
 			log.Info("Call SlashCandidates, Delete the validator", "blockNumber", blockNumber,
 				"blockHash", blockHash.Hex(), "nodeId", val.NodeId.String())
@@ -2580,12 +2891,10 @@ func slashBalanceFn(slashAmount, canBalance *big.Int, isNotify bool,
 
 	if slashAmount.Cmp(canBalance) >= 0 {
 
-		state.SubBalance(vm.StakingContractAddr, canBalance)
-
 		if slashType.IsDuplicateSign() {
-			state.AddBalance(benefitAddr, canBalance)
+			transferBalance(state, vm.StakingContractAddr, benefitAddr, canBalance, gov.ModuleSlashing, types.BalanceChangeSlashing)
 		} else {
-			state.AddBalance(vm.RewardManagerPoolAddr, canBalance)
+			transferBalance(state, vm.StakingContractAddr, vm.RewardManagerPoolAddr, canBalance, gov.ModuleSlashing, types.BalanceChangeSlashing)
 		}
 
 		if isNotify {
@@ -2599,11 +2908,10 @@ func slashBalanceFn(slashAmount, canBalance *big.Int, isNotify bool,
 		balanceTmp = new(big.Int).SetInt64(0)
 
 	} else {
-		state.SubBalance(vm.StakingContractAddr, slashAmount)
 		if slashType.IsDuplicateSign() {
-			state.AddBalance(benefitAddr, slashAmount)
+			transferBalance(state, vm.StakingContractAddr, benefitAddr, slashAmount, gov.ModuleSlashing, types.BalanceChangeSlashing)
 		} else {
-			state.AddBalance(vm.RewardManagerPoolAddr, slashAmount)
+			transferBalance(state, vm.StakingContractAddr, vm.RewardManagerPoolAddr, slashAmount, gov.ModuleSlashing, types.BalanceChangeSlashing)
 		}
 
 		if isNotify {
@@ -2800,6 +3108,11 @@ func buildCbftValidators(start uint64, arr staking.ValidatorQueue) *cbfttypes.Va
 		Nodes:            valMap,
 		ValidBlockNumber: start,
 	}
+	// The validators produce the blocks by the governed schedule
+	if s := xcom.BlockScheduleAt(start); s.BlockNumber > 1 {
+		res.Period = s.Period()
+		res.Amount = uint32(s.PerRoundBlocks)
+	}
 	return res
 }
 
@@ -3164,8 +3477,8 @@ func (sk *StakingPlugin) getPreValIndex(blockHash common.Hash, blockNumber uint6
 	var targetIndex *staking.ValArrIndex
 
 	var preTargetNumber uint64
-	if blockNumber > xutil.ConsensusSize() {
-		preTargetNumber = blockNumber - xutil.ConsensusSize()
+	if round := xutil.CalculateRound(blockNumber); round > 1 {
+		preTargetNumber = xutil.CalcRoundEndBlock(round - 1)
 	}
 
 	var indexArr staking.ValArrIndexQueue
@@ -3698,11 +4011,18 @@ func (sk *StakingPlugin) storeRoundValidatorAddrs(blockNumber uint64, blockHash
 	}
 
 	validEpochCount := uint64(evidenceAge + 1)
-	validRoundCount := xutil.EpochSize() * validEpochCount
+	var validRoundCount uint64
 
 	// Only store the address of last consensus rounds on `validEpochCount` epochs
 	if nextEpoch > validEpochCount {
-		invalidRound := nextRound - validRoundCount
+		// The round at the same position of the epoch `validEpochCount` epochs ago,
+		// the epochs may have different sizes once the block schedule is governed.
+		invalidEpoch := nextEpoch - validEpochCount
+		invalidRound := xutil.CalcRoundsBeforeEpoch(invalidEpoch) + nextRound - xutil.CalcRoundsBeforeEpoch(nextEpoch)
+		if last := xutil.CalcRoundsBeforeEpoch(invalidEpoch + 1); invalidRound > last {
+			invalidRound = last
+		}
+		validRoundCount = nextRound - invalidRound
 
 		boundary, er := sk.db.GetRoundAddrBoundary(blockHash)
 		if snapshotdb.NonDbNotFoundErr(er) {
@@ -3768,6 +4088,323 @@ func (sk *StakingPlugin) HasStake(blockHash common.Hash, addr common.Address) (b
 	return sk.db.HasAccountStakeRc(blockHash, addr)
 }
 
+// applyKeyRotations makes the pending keys of the candidates take effect at the Epoch,
+// it must be called at the end of the previous Epoch before electing the verifiers of the Epoch.
+func (sk *StakingPlugin) applyKeyRotations(blockHash common.Hash, blockNumber, epoch uint64) error {
+
+	iter := sk.db.IteratorKeyRotationByBlockHash(blockHash, epoch)
+	if err := iter.Error(); nil != err {
+		log.Error("Failed to applyKeyRotations: take iter by key rotation is failed", "blockNumber",
+			blockNumber, "blockHash", blockHash.Hex(), "epoch", epoch, "err", err)
+		return err
+	}
+
+	prefixLen := len(staking.GetKeyRotationPrefix(epoch))
+	addrs := make([]common.NodeAddress, 0)
+	for iter.Valid(); iter.Next(); {
+		addrs = append(addrs, common.BytesToNodeAddress(iter.Key()[prefixLen:]))
+	}
+	iter.Release()
+
+	for _, canAddr := range addrs {
+
+		key, err := sk.db.GetKeyRotationStore(blockHash, epoch, canAddr)
+		if nil != err {
+			log.Error("Failed to applyKeyRotations: Query key rotation is failed", "blockNumber", blockNumber,
+				"blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+			return err
+		}
+
+		if err := sk.db.DelKeyRotationStore(blockHash, epoch, canAddr); nil != err {
+			log.Error("Failed to applyKeyRotations: Delete key rotation is failed", "blockNumber", blockNumber,
+				"blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+			return err
+		}
+
+		canBase, err := sk.db.GetCanBaseStore(blockHash, canAddr)
+		if snapshotdb.NonDbNotFoundErr(err) {
+			log.Error("Failed to applyKeyRotations: Query CandidateBase info is failed", "blockNumber", blockNumber,
+				"blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+			return err
+		}
+		if snapshotdb.IsDbNotFoundErr(err) || canBase.IsEmpty() {
+			if err := sk.releaseNodeIdAlias(blockHash, canAddr, key.NodeId, nil); nil != err {
+				log.Error("Failed to applyKeyRotations: Release nodeId alias is failed", "blockNumber", blockNumber,
+					"blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+				return err
+			}
+			continue
+		}
+
+		queue, err := sk.getCanKeyHistory(blockHash, canAddr, canBase.StakingBlockNum)
+		if nil != err {
+			log.Error("Failed to applyKeyRotations: Query candidate keys history is failed", "blockNumber", blockNumber,
+				"blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+			return err
+		}
+		// Keep the keys staked with for the evidences before the rotation
+		if len(queue) == 0 {
+			queue = append(queue, &staking.CandidateKey{
+				Epoch:     xutil.CalculateEpoch(canBase.StakingBlockNum),
+				NodeId:    canBase.NodeId,
+				BlsPubKey: canBase.BlsPubKey,
+			})
+		}
+		// The replaced nodeId is released for other candidates,
+		// unless the candidate rotates back to it in the next Epoch.
+		inUse := map[discover.NodeID]struct{}{key.NodeId: {}}
+		if next, err := sk.db.GetKeyRotationStore(blockHash, epoch+1, canAddr); nil == err {
+			inUse[next.NodeId] = struct{}{}
+		} else if snapshotdb.NonDbNotFoundErr(err) {
+			return err
+		}
+		if err := sk.releaseNodeIdAlias(blockHash, canAddr, queue[len(queue)-1].NodeId, inUse); nil != err {
+			log.Error("Failed to applyKeyRotations: Release nodeId alias is failed", "blockNumber", blockNumber,
+				"blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+			return err
+		}
+
+		queue = append(queue, key)
+		if err := sk.db.SetKeyHistoryStore(blockHash, canAddr, queue); nil != err {
+			log.Error("Failed to applyKeyRotations: Store candidate keys history is failed", "blockNumber", blockNumber,
+				"blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+			return err
+		}
+
+		canBase.BlsPubKey = key.BlsPubKey
+		if err := sk.db.SetCanBaseStore(blockHash, canAddr, canBase); nil != err {
+			log.Error("Failed to applyKeyRotations: Store CandidateBase info is failed", "blockNumber", blockNumber,
+				"blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+			return err
+		}
+
+		log.Debug("Call applyKeyRotations, the candidate keys take effect", "blockNumber", blockNumber,
+			"blockHash", blockHash.Hex(), "epoch", epoch, "nodeId", canBase.NodeId.String(), "key", key)
+	}
+	return nil
+}
+
+// applyCommissionSchedules makes the commission changes which take effect at the Epoch in force
+func (sk *StakingPlugin) applyCommissionSchedules(blockHash common.Hash, blockNumber, epoch uint64) error {
+
+	iter := sk.db.IteratorCommissionScheduleByBlockHash(blockHash, epoch)
+	if err := iter.Error(); nil != err {
+		log.Error("Failed to applyCommissionSchedules: take iter by commission schedule is failed", "blockNumber",
+			blockNumber, "blockHash", blockHash.Hex(), "epoch", epoch, "err", err)
+		return err
+	}
+
+	prefixLen := len(staking.GetCommissionSchedulePrefix(epoch))
+	addrs := make([]common.NodeAddress, 0)
+	for iter.Valid(); iter.Next(); {
+		addrs = append(addrs, common.BytesToNodeAddress(iter.Key()[prefixLen:]))
+	}
+	iter.Release()
+
+	for _, canAddr := range addrs {
+
+		schedule, err := sk.db.GetCommissionScheduleStore(blockHash, canAddr)
+		if nil != err {
+			log.Error("Failed to applyCommissionSchedules: Query commission schedule is failed", "blockNumber", blockNumber,
+				"blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+			return err
+		}
+
+		if err := sk.db.DelCommissionScheduleStore(blockHash, canAddr, schedule); nil != err {
+			log.Error("Failed to applyCommissionSchedules: Delete commission schedule is failed", "blockNumber", blockNumber,
+				"blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+			return err
+		}
+
+		can, err := sk.db.GetCandidateStore(blockHash, canAddr)
+		if snapshotdb.NonDbNotFoundErr(err) {
+			log.Error("Failed to applyCommissionSchedules: Query candidate info is failed", "blockNumber", blockNumber,
+				"blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+			return err
+		}
+		if snapshotdb.IsDbNotFoundErr(err) || can.IsEmpty() {
+			continue
+		}
+
+		if can.RewardPer == schedule.RewardPer && can.NextRewardPer == schedule.RewardPer {
+			continue
+		}
+		can.RewardPer = schedule.RewardPer
+		can.NextRewardPer = schedule.RewardPer
+		if err := sk.db.SetCanMutableStore(blockHash, canAddr, can.CandidateMutable); nil != err {
+			log.Error("Failed to applyCommissionSchedules: Store CandidateMutable info is failed", "blockNumber", blockNumber,
+				"blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+			return err
+		}
+
+		history, err := sk.db.GetCommissionHistoryStore(blockHash, canAddr)
+		if snapshotdb.NonDbNotFoundErr(err) {
+			log.Error("Failed to applyCommissionSchedules: Query commission history is failed", "blockNumber", blockNumber,
+				"blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+			return err
+		}
+		record := &staking.CommissionRecord{Epoch: uint32(epoch), RewardPer: schedule.RewardPer}
+		history = append(history, record)
+		if err := sk.db.SetCommissionHistoryStore(blockHash, canAddr, history); nil != err {
+			log.Error("Failed to applyCommissionSchedules: Store commission history is failed", "blockNumber", blockNumber,
+				"blockHash", blockHash.Hex(), "canAddr", canAddr.Hex(), "err", err)
+			return err
+		}
+
+		log.Debug("Call applyCommissionSchedules, the commission change takes effect", "blockNumber", blockNumber,
+			"blockHash", blockHash.Hex(), "epoch", epoch, "nodeId", can.NodeId.String(), "rewardPer", schedule.RewardPer)
+
+		sk.postEvent(staking.CommissionChangedEvent{NodeId: can.NodeId, Record: record})
+	}
+	return nil
+}
+
+// postEvent notifies the subscribers, it is skipped without the event mux
+func (sk *StakingPlugin) postEvent(ev interface{}) {
+	if nil == sk.eventMux {
+		return
+	}
+	if err := sk.eventMux.Post(ev); nil != err {
+		log.Error("Failed to post the event of stakingPlugin", "event", ev, "err", err)
+	}
+}
+
+// rotateValidatorKeys replaces the keys of the validators by the keys which take effect at the Epoch
+func (sk *StakingPlugin) rotateValidatorKeys(blockHash common.Hash, epoch uint64, queue staking.ValidatorQueue) error {
+	for i, v := range queue {
+		key, err := sk.db.GetKeyRotationStore(blockHash, epoch, v.NodeAddress)
+		if snapshotdb.IsDbNotFoundErr(err) {
+			continue
+		}
+		if nil != err {
+			return err
+		}
+		val := *v
+		val.NodeId = key.NodeId
+		val.BlsPubKey = key.BlsPubKey
+		queue[i] = &val
+	}
+	return nil
+}
+
+// getCanKeyHistory returns the keys history of the current staking of the candidate
+func (sk *StakingPlugin) getCanKeyHistory(blockHash common.Hash, canAddr common.NodeAddress, stakingBlockNum uint64) (staking.CandidateKeyQueue, error) {
+	queue, err := sk.db.GetKeyHistoryStore(blockHash, canAddr)
+	if snapshotdb.NonDbNotFoundErr(err) {
+		return nil, err
+	}
+	// The history is left by the previous staking of the same nodeId
+	if len(queue) > 0 && queue[0].Epoch < xutil.CalculateEpoch(stakingBlockNum) {
+		return nil, nil
+	}
+	return queue, nil
+}
+
+// getCanKey returns the consensus nodeId and bls key of the candidate in force at the Epoch
+func (sk *StakingPlugin) getCanKey(blockHash common.Hash, canAddr common.NodeAddress, can *staking.CandidateBase,
+	epoch uint64) (discover.NodeID, bls.PublicKeyHex, error) {
+
+	queue, err := sk.getCanKeyHistory(blockHash, canAddr, can.StakingBlockNum)
+	if nil != err {
+		return discover.ZeroNodeID, bls.PublicKeyHex{}, err
+	}
+	if key := queue.KeyAt(epoch); nil != key {
+		return key.NodeId, key.BlsPubKey, nil
+	}
+	return can.NodeId, can.BlsPubKey, nil
+}
+
+// resolveCanAddr returns the address of the candidate which the node address belongs to,
+// the node address of a rotated nodeId points to the candidate staked with the original nodeId.
+func (sk *StakingPlugin) resolveCanAddr(blockHash common.Hash, addr common.NodeAddress, isCommit bool) (common.NodeAddress, error) {
+	var canAddr common.NodeAddress
+	var err error
+	if !isCommit {
+		canAddr, err = sk.db.GetNodeIdAliasStore(blockHash, addr)
+	} else {
+		canAddr, err = sk.db.GetNodeIdAliasStoreByIrr(addr)
+	}
+	if snapshotdb.IsDbNotFoundErr(err) {
+		return addr, nil
+	}
+	if nil != err {
+		return common.ZeroNodeAddr, err
+	}
+	return canAddr, nil
+}
+
+// HasNodeIdAlias checks whether the node address is the rotated nodeId of the candidate other than the canAddr
+func (sk *StakingPlugin) HasNodeIdAlias(blockHash common.Hash, addr, canAddr common.NodeAddress) (bool, error) {
+	owner, err := sk.resolveCanAddr(blockHash, addr, QueryStartNotIrr)
+	if nil != err {
+		return false, err
+	}
+	return owner != addr && owner != canAddr, nil
+}
+
+// releaseCanKeys releases the nodeIds reserved by the key rotations of the candidate and
+// drops its pending rotations, it is called when the candidate is deleted.
+func (sk *StakingPlugin) releaseCanKeys(blockHash common.Hash, blockNumber uint64, canAddr common.NodeAddress,
+	can *staking.CandidateBase) error {
+
+	queue, err := sk.getCanKeyHistory(blockHash, canAddr, can.StakingBlockNum)
+	if nil != err {
+		return err
+	}
+	keys := append(staking.CandidateKeyQueue{}, queue...)
+
+	// The rotations submitted at most take effect two Epochs later
+	epoch := xutil.CalculateEpoch(blockNumber)
+	for e := epoch + 1; e <= epoch+2; e++ {
+		key, err := sk.db.GetKeyRotationStore(blockHash, e, canAddr)
+		if snapshotdb.IsDbNotFoundErr(err) {
+			continue
+		}
+		if nil != err {
+			return err
+		}
+		if err := sk.db.DelKeyRotationStore(blockHash, e, canAddr); nil != err {
+			return err
+		}
+		keys = append(keys, key)
+	}
+
+	for _, key := range keys {
+		if err := sk.releaseNodeIdAlias(blockHash, canAddr, key.NodeId, nil); nil != err {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseNodeIdAlias releases the nodeId reserved by the candidate,
+// unless it is one of the keys the candidate still uses.
+func (sk *StakingPlugin) releaseNodeIdAlias(blockHash common.Hash, canAddr common.NodeAddress, nodeId discover.NodeID,
+	inUse map[discover.NodeID]struct{}) error {
+
+	if _, ok := inUse[nodeId]; ok {
+		return nil
+	}
+	addr, err := xutil.NodeId2Addr(nodeId)
+	if nil != err {
+		return err
+	}
+	if addr == canAddr {
+		return nil
+	}
+	owner, err := sk.db.GetNodeIdAliasStore(blockHash, addr)
+	if snapshotdb.IsDbNotFoundErr(err) {
+		return nil
+	}
+	if nil != err {
+		return err
+	}
+	if owner != canAddr {
+		return nil
+	}
+	return sk.db.DelNodeIdAliasStore(blockHash, addr)
+}
+
 func calcCandidateTotalAmount(can *staking.Candidate) *big.Int {
 	release := new(big.Int).Add(can.Released, can.ReleasedHes)
 	restrictingPlan := new(big.Int).Add(can.RestrictingPlan, can.RestrictingPlanHes)
@@ -302,8 +302,8 @@ func buildPrepareData(genesis *types.Block, t *testing.T) (*types.Header, error)
 		return nil, err
 	}
 
-	PrintObject("Test round", validatorQueue[:xcom.MaxConsensusVals()])
-	roundArr, err := rlp.EncodeToBytes(validatorQueue[:xcom.MaxConsensusVals()])
+	PrintObject("Test round", validatorQueue[:xcom.MaxConsensusVals(0)])
+	roundArr, err := rlp.EncodeToBytes(validatorQueue[:xcom.MaxConsensusVals(0)])
 	if nil != err {
 		t.Errorf("Failed to rlp encodeing genesis validators. error:%s", err.Error())
 		return nil, err
@@ -666,8 +666,8 @@ func TestStakingPlugin_EndBlock(t *testing.T) {
 		return
 	}
 
-	PrintObject("Test round", validatorQueue[:xcom.MaxConsensusVals()])
-	roundArr, err := rlp.EncodeToBytes(validatorQueue[:xcom.MaxConsensusVals()])
+	PrintObject("Test round", validatorQueue[:xcom.MaxConsensusVals(0)])
+	roundArr, err := rlp.EncodeToBytes(validatorQueue[:xcom.MaxConsensusVals(0)])
 	if !assert.Nil(t, err, fmt.Sprintf("Failed to rlp encodeing genesis validators. error: %v", err)) {
 		return
 	}
@@ -983,8 +983,8 @@ func TestStakingPlugin_Confirmed(t *testing.T) {
 		return
 	}
 
-	PrintObject("Test round", validatorQueue[:xcom.MaxConsensusVals()])
-	roundArr, err := rlp.EncodeToBytes(validatorQueue[:xcom.MaxConsensusVals()])
+	PrintObject("Test round", validatorQueue[:xcom.MaxConsensusVals(0)])
+	roundArr, err := rlp.EncodeToBytes(validatorQueue[:xcom.MaxConsensusVals(0)])
 	if !assert.Nil(t, err, fmt.Sprintf("Failed to rlp encodeing genesis validators. error: %v", err)) {
 		return
 	}
@@ -1301,6 +1301,293 @@ func TestStakingPlugin_EditorCandidate(t *testing.T) {
 
 }
 
+func TestStakingPlugin_RotateCandidateKey(t *testing.T) {
+
+	state, genesis, err := newChainState()
+	if nil != err {
+		t.Error("Failed to build the state", err)
+		return
+	}
+	newPlugins()
+
+	build_gov_data(state)
+
+	sndb := snapshotdb.Instance()
+	defer func() {
+		sndb.Clear()
+	}()
+
+	if err := sndb.NewBlock(blockNumber, genesis.Hash(), blockHash); nil != err {
+		t.Error("newBlock err", err)
+		return
+	}
+
+	for _, index := range []int{1, 2} {
+		if err := create_staking(state, blockNumber, blockHash, index, 0, t); nil != err {
+			t.Error("Failed to Create Staking", err)
+			return
+		}
+	}
+
+	if err := sndb.Commit(blockHash); nil != err {
+		t.Errorf("Commit 1 err: %v", err)
+		return
+	}
+
+	if err := sndb.NewBlock(blockNumber2, blockHash, blockHash2); nil != err {
+		t.Error("newBlock2 err", err)
+		return
+	}
+
+	can1, err := getCandidate(blockHash2, 1)
+	assert.Nil(t, err)
+	can2, err := getCandidate(blockHash2, 2)
+	assert.Nil(t, err)
+	canAddr1, _ := xutil.NodeId2Addr(can1.NodeId)
+	canAddr2, _ := xutil.NodeId2Addr(can2.NodeId)
+	oldNodeId, oldBlsPubKey := can1.NodeId, can1.BlsPubKey
+
+	privateKey, _ := crypto.GenerateKey()
+	newNodeId := discover.PubkeyID(&privateKey.PublicKey)
+	newAddr, _ := xutil.NodeId2Addr(newNodeId)
+	var blsKey bls.SecretKey
+	blsKey.SetByCSPRNG()
+	var newBlsPubKey bls.PublicKeyHex
+	b, _ := blsKey.GetPublicKey().MarshalText()
+	assert.Nil(t, newBlsPubKey.UnmarshalText(b))
+
+	// the same keys
+	_, err = StakingInstance().RotateCandidateKey(blockHash2, blockNumber2, canAddr2, can2.CandidateBase, can2.NodeId, can2.BlsPubKey)
+	assert.Equal(t, staking.ErrCanKeysNotChanged, err)
+
+	// the staking nodeId of other candidate
+	_, err = StakingInstance().RotateCandidateKey(blockHash2, blockNumber2, canAddr1, can1.CandidateBase, can2.NodeId, newBlsPubKey)
+	assert.Equal(t, staking.ErrNodeIdAlreadyUsed, err)
+
+	epoch, err := StakingInstance().RotateCandidateKey(blockHash2, blockNumber2, canAddr1, can1.CandidateBase, newNodeId, newBlsPubKey)
+	if !assert.Nil(t, err, fmt.Sprintf("Failed to RotateCandidateKey: %v", err)) {
+		return
+	}
+	assert.Equal(t, xutil.CalculateEpoch(blockNumber2.Uint64())+1, epoch)
+
+	// the nodeId is reserved by the candidate
+	_, err = StakingInstance().RotateCandidateKey(blockHash2, blockNumber2, canAddr2, can2.CandidateBase, newNodeId, newBlsPubKey)
+	assert.Equal(t, staking.ErrNodeIdAlreadyUsed, err)
+	used, err := StakingInstance().HasNodeIdAlias(blockHash2, newAddr, newAddr)
+	assert.Nil(t, err)
+	assert.True(t, used)
+
+	addr, err := StakingInstance().resolveCanAddr(blockHash2, newAddr, QueryStartNotIrr)
+	assert.Nil(t, err)
+	assert.Equal(t, canAddr1, addr)
+	addr, err = StakingInstance().resolveCanAddr(blockHash2, canAddr2, QueryStartNotIrr)
+	assert.Nil(t, err)
+	assert.Equal(t, canAddr2, addr)
+
+	// the first round validators of the epoch use the new keys
+	queue := staking.ValidatorQueue{
+		{NodeAddress: canAddr1, NodeId: oldNodeId, BlsPubKey: oldBlsPubKey},
+		{NodeAddress: canAddr2, NodeId: can2.NodeId, BlsPubKey: can2.BlsPubKey},
+	}
+	assert.Nil(t, StakingInstance().rotateValidatorKeys(blockHash2, epoch, queue))
+	assert.Equal(t, newNodeId, queue[0].NodeId)
+	assert.Equal(t, newBlsPubKey, queue[0].BlsPubKey)
+	assert.Equal(t, can2.NodeId, queue[1].NodeId)
+
+	assert.Nil(t, StakingInstance().applyKeyRotations(blockHash2, blockNumber2.Uint64(), epoch))
+
+	canBase, err := StakingInstance().GetCanBase(blockHash2, canAddr1)
+	assert.Nil(t, err)
+	assert.Equal(t, oldNodeId, canBase.NodeId)
+	assert.Equal(t, newBlsPubKey, canBase.BlsPubKey)
+
+	// the old keys are kept for the history
+	nodeId, blsPubKey, err := StakingInstance().getCanKey(blockHash2, canAddr1, canBase, epoch-1)
+	assert.Nil(t, err)
+	assert.Equal(t, oldNodeId, nodeId)
+	assert.Equal(t, oldBlsPubKey, blsPubKey)
+	nodeId, blsPubKey, err = StakingInstance().getCanKey(blockHash2, canAddr1, canBase, epoch)
+	assert.Nil(t, err)
+	assert.Equal(t, newNodeId, nodeId)
+	assert.Equal(t, newBlsPubKey, blsPubKey)
+
+	_, err = StakingInstance().db.GetKeyRotationStore(blockHash2, epoch, canAddr1)
+	assert.True(t, snapshotdb.IsDbNotFoundErr(err))
+}
+
+func TestStakingPlugin_ReleaseNodeIdAlias(t *testing.T) {
+
+	state, genesis, err := newChainState()
+	if nil != err {
+		t.Error("Failed to build the state", err)
+		return
+	}
+	newPlugins()
+
+	build_gov_data(state)
+
+	sndb := snapshotdb.Instance()
+	defer func() {
+		sndb.Clear()
+	}()
+
+	if err := sndb.NewBlock(blockNumber, genesis.Hash(), blockHash); nil != err {
+		t.Error("newBlock err", err)
+		return
+	}
+	if err := create_staking(state, blockNumber, blockHash, 1, 0, t); nil != err {
+		t.Error("Failed to Create Staking", err)
+		return
+	}
+	if err := sndb.Commit(blockHash); nil != err {
+		t.Errorf("Commit 1 err: %v", err)
+		return
+	}
+	if err := sndb.NewBlock(blockNumber2, blockHash, blockHash2); nil != err {
+		t.Error("newBlock2 err", err)
+		return
+	}
+
+	can, err := getCandidate(blockHash2, 1)
+	assert.Nil(t, err)
+	canAddr, _ := xutil.NodeId2Addr(can.NodeId)
+
+	newKey := func() (discover.NodeID, common.NodeAddress) {
+		privateKey, _ := crypto.GenerateKey()
+		nodeId := discover.PubkeyID(&privateKey.PublicKey)
+		addr, _ := xutil.NodeId2Addr(nodeId)
+		return nodeId, addr
+	}
+	reserved := func(addr common.NodeAddress) bool {
+		used, err := StakingInstance().HasNodeIdAlias(blockHash2, addr, addr)
+		assert.Nil(t, err)
+		return used
+	}
+	nodeIdA, addrA := newKey()
+	nodeIdB, addrB := newKey()
+	nodeIdC, addrC := newKey()
+
+	// the pending rotation replaced in the same Epoch releases its nodeId
+	epoch, err := StakingInstance().RotateCandidateKey(blockHash2, blockNumber2, canAddr, can.CandidateBase, nodeIdA, can.BlsPubKey)
+	assert.Nil(t, err)
+	assert.True(t, reserved(addrA))
+	_, err = StakingInstance().RotateCandidateKey(blockHash2, blockNumber2, canAddr, can.CandidateBase, nodeIdB, can.BlsPubKey)
+	assert.Nil(t, err)
+	assert.False(t, reserved(addrA))
+	assert.True(t, reserved(addrB))
+
+	// the nodeId replaced by the next rotation is released when the rotation takes effect
+	assert.Nil(t, StakingInstance().applyKeyRotations(blockHash2, blockNumber2.Uint64(), epoch))
+	number := new(big.Int).SetUint64(epoch*xutil.CalcBlocksEachEpoch() + 1)
+	nextEpoch, err := StakingInstance().RotateCandidateKey(blockHash2, number, canAddr, can.CandidateBase, nodeIdC, can.BlsPubKey)
+	assert.Nil(t, err)
+	assert.True(t, reserved(addrB))
+	assert.True(t, reserved(addrC))
+	assert.Nil(t, StakingInstance().applyKeyRotations(blockHash2, number.Uint64(), nextEpoch))
+	assert.False(t, reserved(addrB))
+	assert.True(t, reserved(addrC))
+
+	// the deleted candidate releases all its nodeIds and pending rotations
+	nodeIdD, addrD := newKey()
+	_, err = StakingInstance().RotateCandidateKey(blockHash2, number, canAddr, can.CandidateBase, nodeIdD, can.BlsPubKey)
+	assert.Nil(t, err)
+	assert.Nil(t, StakingInstance().releaseCanKeys(blockHash2, number.Uint64(), canAddr, can.CandidateBase))
+	assert.False(t, reserved(addrC))
+	assert.False(t, reserved(addrD))
+	_, err = StakingInstance().db.GetKeyRotationStore(blockHash2, nextEpoch, canAddr)
+	assert.True(t, snapshotdb.IsDbNotFoundErr(err))
+}
+
+func TestStakingPlugin_ScheduleCommission(t *testing.T) {
+
+	state, genesis, err := newChainState()
+	if nil != err {
+		t.Error("Failed to build the state", err)
+		return
+	}
+	newPlugins()
+
+	build_gov_data(state)
+
+	sndb := snapshotdb.Instance()
+	defer func() {
+		sndb.Clear()
+	}()
+
+	if err := sndb.NewBlock(blockNumber, genesis.Hash(), blockHash); nil != err {
+		t.Error("newBlock err", err)
+		return
+	}
+
+	if err := create_staking(state, blockNumber, blockHash, 1, 0, t); nil != err {
+		t.Error("Failed to Create Staking", err)
+		return
+	}
+
+	if err := sndb.Commit(blockHash); nil != err {
+		t.Errorf("Commit 1 err: %v", err)
+		return
+	}
+
+	if err := sndb.NewBlock(blockNumber2, blockHash, blockHash2); nil != err {
+		t.Error("newBlock2 err", err)
+		return
+	}
+
+	eventMux := &event.TypeMux{}
+	StakingInstance().SetEventMux(eventMux)
+	defer StakingInstance().SetEventMux(nil)
+	sub := eventMux.Subscribe(staking.CommissionScheduledEvent{}, staking.CommissionChangedEvent{})
+	defer sub.Unsubscribe()
+
+	can, err := getCandidate(blockHash2, 1)
+	assert.Nil(t, err)
+	canAddr, _ := xutil.NodeId2Addr(can.NodeId)
+	oldRewardPer := can.RewardPer
+
+	epoch := uint32(xutil.CalculateEpoch(blockNumber2.Uint64()))
+	first := &staking.CommissionSchedule{RewardPer: oldRewardPer + 100, AnnounceEpoch: epoch, EffectiveEpoch: epoch + 3}
+	go func() {
+		assert.Nil(t, StakingInstance().ScheduleCommission(blockHash2, blockNumber2, canAddr, can.NodeId, first))
+	}()
+	ev := <-sub.Chan()
+	assert.Equal(t, first, ev.Data.(staking.CommissionScheduledEvent).Schedule)
+
+	// the later announcement replaces the pending one
+	second := &staking.CommissionSchedule{RewardPer: oldRewardPer + 50, AnnounceEpoch: epoch, EffectiveEpoch: epoch + 2}
+	go func() {
+		assert.Nil(t, StakingInstance().ScheduleCommission(blockHash2, blockNumber2, canAddr, can.NodeId, second))
+	}()
+	<-sub.Chan()
+
+	pending, err := StakingInstance().GetCommissionSchedule(blockHash2, canAddr)
+	assert.Nil(t, err)
+	assert.Equal(t, second, pending)
+
+	// nothing takes effect before the effective epoch
+	assert.Nil(t, StakingInstance().applyCommissionSchedules(blockHash2, blockNumber2.Uint64(), uint64(first.EffectiveEpoch)))
+	_, err = StakingInstance().GetCommissionHistory(blockHash2, canAddr)
+	assert.True(t, snapshotdb.IsDbNotFoundErr(err))
+
+	go func() {
+		assert.Nil(t, StakingInstance().applyCommissionSchedules(blockHash2, blockNumber2.Uint64(), uint64(second.EffectiveEpoch)))
+	}()
+	ev = <-sub.Chan()
+	assert.Equal(t, can.NodeId, ev.Data.(staking.CommissionChangedEvent).NodeId)
+
+	canMutable, err := StakingInstance().GetCanMutable(blockHash2, canAddr)
+	assert.Nil(t, err)
+	assert.Equal(t, second.RewardPer, canMutable.RewardPer)
+	assert.Equal(t, second.RewardPer, canMutable.NextRewardPer)
+
+	history, err := StakingInstance().GetCommissionHistory(blockHash2, canAddr)
+	assert.Nil(t, err)
+	assert.Equal(t, staking.CommissionHistory{{Epoch: second.EffectiveEpoch, RewardPer: second.RewardPer}}, history)
+
+	_, err = StakingInstance().GetCommissionSchedule(blockHash2, canAddr)
+	assert.True(t, snapshotdb.IsDbNotFoundErr(err))
+}
+
 func TestStakingPlugin_IncreaseStaking(t *testing.T) {
 
 	state, genesis, err := newChainState()
@@ -2546,7 +2833,7 @@ func TestStakingPlugin_Election(t *testing.T) {
 		End:   xutil.ConsensusSize(),
 	}
 
-	new_validatorArr.Arr = queue[:int(xcom.MaxConsensusVals())]
+	new_validatorArr.Arr = queue[:int(xcom.MaxConsensusVals(0))]
 
 	err = setRoundValList(blockHash, new_validatorArr)
 	if nil != err {
@@ -3766,7 +4053,7 @@ func TestStakingPlugin_ProbabilityElection(t *testing.T) {
 		time.Sleep(time.Microsecond * 10)
 	}
 
-	result, err := probabilityElection(vqList, int(xcom.ShiftValidatorNum()), currentNonce, preNonces, 1, params.GenesisVersion)
+	result, err := probabilityElection(vqList, int(xcom.ShiftValidatorNum(0)), currentNonce, preNonces, 1, params.GenesisVersion)
 	assert.Nil(t, err, fmt.Sprintf("Failed to probabilityElection, err: %v", err))
 	assert.True(t, nil != result, "the result is nil")
 
@@ -3826,7 +4113,7 @@ func TestStakingPlugin_ProbabilityElectionDifferentWeights(t *testing.T) {
 		vqList, preNonceList := buildCandidate(stakeThreshold)
 		stakeThreshold *= 10
 		t.Run(fmt.Sprintf("Election_%d", i+1), func(t *testing.T) {
-			result, err := probabilityElection(vqList, int(xcom.ShiftValidatorNum()), currentNonce, preNonceList, 1, params.GenesisVersion)
+			result, err := probabilityElection(vqList, int(xcom.ShiftValidatorNum(0)), currentNonce, preNonceList, 1, params.GenesisVersion)
 			assert.Nil(t, err, fmt.Sprintf("Failed to probabilityElection, err: %v", err))
 			assert.True(t, nil != result, "the result is nil")
 		})
@@ -3850,7 +4137,7 @@ func TestStakingPlugin_RandomOrderValidatorQueue(t *testing.T) {
 	if err := slash.db.NewBlock(new(big.Int).SetUint64(1), blockHash, common.ZeroHash); nil != err {
 		t.Fatal(err)
 	}
-	for i := 0; i < int(xcom.MaxConsensusVals()); i++ {
+	for i := 0; i < int(xcom.MaxConsensusVals(0)); i++ {
 		vrfData, err := vrf.Prove(privateKey, data)
 		if nil != err {
 			t.Fatal(err)
@@ -0,0 +1,52 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package scheduled
+
+import (
+	"github.com/hashkey-chain/hashkey-chain/common"
+)
+
+var (
+	ScheduledTxSeqKey         = []byte("ScheduledTxSeq")
+	ScheduledTxKeyPrefix      = []byte("ScheduledTxInfo")
+	ScheduledBlockKeyPrefix   = []byte("ScheduledTxBlock")
+	ScheduledEpochKeyPrefix   = []byte("ScheduledTxEpoch")
+	ScheduledAccountKeyPrefix = []byte("ScheduledTxAccount")
+	ScheduledBacklogKey       = []byte("ScheduledTxBacklog")
+)
+
+// GetScheduledTxKey used for search the scheduled transaction. key: prefix + id
+func GetScheduledTxKey(id uint64) []byte {
+	return append(ScheduledTxKeyPrefix, common.Uint64ToBytes(id)...)
+}
+
+// GetBlockKey used for search the ids of the transactions due at the block. key: prefix + blockNumber
+func GetBlockKey(blockNumber uint64) []byte {
+	return append(ScheduledBlockKeyPrefix, common.Uint64ToBytes(blockNumber)...)
+}
+
+// GetEpochKey used for search the ids of the transactions due at the first block
+// of the epoch. key: prefix + epoch
+func GetEpochKey(epoch uint64) []byte {
+	return append(ScheduledEpochKeyPrefix, common.Uint64ToBytes(epoch)...)
+}
+
+// GetAccountKey used for search the ids of the pending transactions submitted by
+// the account. key: prefix + account
+func GetAccountKey(account common.Address) []byte {
+	return append(ScheduledAccountKeyPrefix, account.Bytes()...)
+}
@@ -0,0 +1,43 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package scheduled
+
+import (
+	"fmt"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+)
+
+const (
+	MaxPendingPerAccount = 32 // The maximum number of the pending transactions an account can schedule
+)
+
+var (
+	ErrScheduledTxNotActive   = common.NewBizError(306001, "Scheduled transactions are not active before the version 1.5.0")
+	ErrInvalidScheduledTx     = common.NewBizError(306002, "The scheduled transaction cannot be decoded")
+	ErrInvalidTxSender        = common.NewBizError(306003, "The sender of the scheduled transaction cannot be recovered")
+	ErrSponsoredTxScheduled   = common.NewBizError(306004, "Sponsored transactions cannot be scheduled")
+	ErrInvalidTargetType      = common.NewBizError(306005, "The target type must be 0 for a block or 1 for an epoch")
+	ErrTargetNotInFuture      = common.NewBizError(306006, "The target block or epoch must be in the future")
+	ErrTxGasTooHigh           = common.NewBizError(306007, "The gas of the scheduled transaction exceeds the gas scheduled transactions can use in a block")
+	ErrTooManyPendingTxs      = common.NewBizError(306008, fmt.Sprintf("The account cannot have more than %d pending scheduled transactions", MaxPendingPerAccount))
+	ErrEscrowNotEnough        = common.NewBizError(306009, "The balance is not enough to escrow the gas of the scheduled transaction")
+	ErrScheduledTxNotFound    = common.NewBizError(306010, "The scheduled transaction is not found")
+	ErrScheduledTxNotPending  = common.NewBizError(306011, "The scheduled transaction is not pending")
+	ErrNotScheduledTxOwner    = common.NewBizError(306012, "Only the submitter or the sender can cancel the scheduled transaction")
+	ErrScheduledTxGasPriceLow = common.NewBizError(306013, "The gas price of the scheduled transaction cannot be zero")
+)
@@ -0,0 +1,66 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package scheduled
+
+import (
+	"math/big"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/common/hexutil"
+	"github.com/hashkey-chain/hashkey-chain/core/types"
+)
+
+// The kinds of the target a transaction is scheduled at.
+const (
+	TargetBlock uint8 = 0 // Executed at the beginning of the block
+	TargetEpoch uint8 = 1 // Executed at the beginning of the first block of the epoch
+)
+
+// Status is the state of a scheduled transaction.
+type Status uint8
+
+const (
+	StatusPending   Status = iota // Waiting for the target
+	StatusExecuted                // Executed and succeeded
+	StatusReverted                // Executed and failed, the gas used is charged
+	StatusDropped                 // Not executable at the target, like a stale nonce, the escrow is refunded
+	StatusCancelled               // Cancelled before the target, the escrow is refunded
+)
+
+// ScheduledTx is a signed transaction the chain executes at the beginning of
+// the target block, its gas is escrowed from the submitter when it's
+// scheduled and the unused part is refunded after the execution.
+type ScheduledTx struct {
+	ID         uint64         `json:"id"`
+	Submitter  common.Address `json:"submitter"`  // The account scheduled the transaction and paying its gas
+	Sender     common.Address `json:"sender"`     // The signer of the transaction
+	TxHash     common.Hash    `json:"txHash"`     // The hash of the signed transaction
+	Tx         hexutil.Bytes  `json:"tx"`         // The RLP encoded signed transaction
+	TargetType uint8          `json:"targetType"` // TargetBlock or TargetEpoch
+	Target     uint64         `json:"target"`     // The block number or the epoch
+	Escrow     *big.Int       `json:"escrow"`     // The gas limit times the gas price of the transaction
+	Status     Status         `json:"status"`
+	ExecBlock  uint64         `json:"execBlock"` // The block executed, dropped or cancelled the transaction
+	GasUsed    uint64         `json:"gasUsed"`
+	Failure    string         `json:"failure"`             // Why the transaction reverted or was dropped
+	Logs       []*types.Log   `json:"logs" rlp:"optional"` // The logs emitted by the execution, the transaction has no receipt of its own
+}
+
+// Pending reports whether the transaction is waiting for the target.
+func (s *ScheduledTx) Pending() bool {
+	return s.Status == StatusPending
+}
@@ -675,3 +675,134 @@ func (db *StakingDB) GetRoundAddrBoundary(blockHash common.Hash) (uint64, error)
 	}
 	return common.BytesToUint64(round), nil
 }
+
+// about the key rotation of candidate ...
+
+func (db *StakingDB) SetKeyRotationStore(blockHash common.Hash, addr common.NodeAddress, key *CandidateKey) error {
+	value, err := rlp.EncodeToBytes(key)
+	if nil != err {
+		return err
+	}
+	return db.put(blockHash, GetKeyRotationKey(key.Epoch, addr), value)
+}
+
+func (db *StakingDB) GetKeyRotationStore(blockHash common.Hash, epoch uint64, addr common.NodeAddress) (*CandidateKey, error) {
+	value, err := db.get(blockHash, GetKeyRotationKey(epoch, addr))
+	if nil != err {
+		return nil, err
+	}
+	var key CandidateKey
+	if err := rlp.DecodeBytes(value, &key); nil != err {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (db *StakingDB) DelKeyRotationStore(blockHash common.Hash, epoch uint64, addr common.NodeAddress) error {
+	return db.del(blockHash, GetKeyRotationKey(epoch, addr))
+}
+
+func (db *StakingDB) IteratorKeyRotationByBlockHash(blockHash common.Hash, epoch uint64) iterator.Iterator {
+	return db.ranking(blockHash, GetKeyRotationPrefix(epoch), 0)
+}
+
+func (db *StakingDB) SetKeyHistoryStore(blockHash common.Hash, addr common.NodeAddress, queue CandidateKeyQueue) error {
+	value, err := rlp.EncodeToBytes(queue)
+	if nil != err {
+		return err
+	}
+	return db.put(blockHash, GetKeyHistoryKey(addr), value)
+}
+
+func (db *StakingDB) GetKeyHistoryStore(blockHash common.Hash, addr common.NodeAddress) (CandidateKeyQueue, error) {
+	value, err := db.get(blockHash, GetKeyHistoryKey(addr))
+	if nil != err {
+		return nil, err
+	}
+	var queue CandidateKeyQueue
+	if err := rlp.DecodeBytes(value, &queue); nil != err {
+		return nil, err
+	}
+	return queue, nil
+}
+
+func (db *StakingDB) SetNodeIdAliasStore(blockHash common.Hash, addr, canAddr common.NodeAddress) error {
+	return db.put(blockHash, GetNodeIdAliasKey(addr), canAddr.Bytes())
+}
+
+func (db *StakingDB) DelNodeIdAliasStore(blockHash common.Hash, addr common.NodeAddress) error {
+	return db.del(blockHash, GetNodeIdAliasKey(addr))
+}
+
+func (db *StakingDB) GetNodeIdAliasStore(blockHash common.Hash, addr common.NodeAddress) (common.NodeAddress, error) {
+	value, err := db.get(blockHash, GetNodeIdAliasKey(addr))
+	if nil != err {
+		return common.ZeroNodeAddr, err
+	}
+	return common.BytesToNodeAddress(value), nil
+}
+
+func (db *StakingDB) GetNodeIdAliasStoreByIrr(addr common.NodeAddress) (common.NodeAddress, error) {
+	value, err := db.getFromCommitted(GetNodeIdAliasKey(addr))
+	if nil != err {
+		return common.ZeroNodeAddr, err
+	}
+	return common.BytesToNodeAddress(value), nil
+}
+
+// about the commission schedule of candidate ...
+
+func (db *StakingDB) SetCommissionScheduleStore(blockHash common.Hash, addr common.NodeAddress, schedule *CommissionSchedule) error {
+	value, err := rlp.EncodeToBytes(schedule)
+	if nil != err {
+		return err
+	}
+	if err := db.put(blockHash, GetCommissionScheduleKey(uint64(schedule.EffectiveEpoch), addr), value); nil != err {
+		return err
+	}
+	return db.put(blockHash, GetCommissionPendingKey(addr), value)
+}
+
+// GetCommissionScheduleStore returns the commission change which the candidate is waiting for
+func (db *StakingDB) GetCommissionScheduleStore(blockHash common.Hash, addr common.NodeAddress) (*CommissionSchedule, error) {
+	value, err := db.get(blockHash, GetCommissionPendingKey(addr))
+	if nil != err {
+		return nil, err
+	}
+	var schedule CommissionSchedule
+	if err := rlp.DecodeBytes(value, &schedule); nil != err {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (db *StakingDB) DelCommissionScheduleStore(blockHash common.Hash, addr common.NodeAddress, schedule *CommissionSchedule) error {
+	if err := db.del(blockHash, GetCommissionScheduleKey(uint64(schedule.EffectiveEpoch), addr)); nil != err {
+		return err
+	}
+	return db.del(blockHash, GetCommissionPendingKey(addr))
+}
+
+func (db *StakingDB) IteratorCommissionScheduleByBlockHash(blockHash common.Hash, epoch uint64) iterator.Iterator {
+	return db.ranking(blockHash, GetCommissionSchedulePrefix(epoch), 0)
+}
+
+func (db *StakingDB) SetCommissionHistoryStore(blockHash common.Hash, addr common.NodeAddress, history CommissionHistory) error {
+	value, err := rlp.EncodeToBytes(history)
+	if nil != err {
+		return err
+	}
+	return db.put(blockHash, GetCommissionHistoryKey(addr), value)
+}
+
+func (db *StakingDB) GetCommissionHistoryStore(blockHash common.Hash, addr common.NodeAddress) (CommissionHistory, error) {
+	value, err := db.get(blockHash, GetCommissionHistoryKey(addr))
+	if nil != err {
+		return nil, err
+	}
+	var history CommissionHistory
+	if err := rlp.DecodeBytes(value, &history); nil != err {
+		return nil, err
+	}
+	return history, nil
+}
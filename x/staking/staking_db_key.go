@@ -27,39 +27,51 @@ import (
 )
 
 const (
-	CanBasePrefixStr           = "CanBase"
-	CanMutablePrefixStr        = "CanMut"
-	CanPowerPrefixStr          = "Power"
-	UnStakeCountKeyStr         = "UnStakeCount"
-	UnStakeItemKeyStr          = "UnStakeItem"
-	DelegatePrefixStr          = "Del"
-	DelegationLockPrefixStr    = "DelegationLock"
-	EpochIndexKeyStr           = "EpochIndex"
-	EpochValArrPrefixStr       = "EpochValArr"
-	RoundIndexKeyStr           = "RoundIndex"
-	RoundValArrPrefixStr       = "RoundValArr"
-	AccountStakeRcPrefixStr    = "AccStakeRc"
-	PPOSHASHStr                = "PPOSHASH"
-	RoundValAddrArrPrefixStr   = "RoundValAddrArr"
-	RoundAddrBoundaryPrefixStr = "RoundAddrBoundary"
+	CanBasePrefixStr            = "CanBase"
+	CanMutablePrefixStr         = "CanMut"
+	CanPowerPrefixStr           = "Power"
+	UnStakeCountKeyStr          = "UnStakeCount"
+	UnStakeItemKeyStr           = "UnStakeItem"
+	DelegatePrefixStr           = "Del"
+	DelegationLockPrefixStr     = "DelegationLock"
+	EpochIndexKeyStr            = "EpochIndex"
+	EpochValArrPrefixStr        = "EpochValArr"
+	RoundIndexKeyStr            = "RoundIndex"
+	RoundValArrPrefixStr        = "RoundValArr"
+	AccountStakeRcPrefixStr     = "AccStakeRc"
+	PPOSHASHStr                 = "PPOSHASH"
+	RoundValAddrArrPrefixStr    = "RoundValAddrArr"
+	RoundAddrBoundaryPrefixStr  = "RoundAddrBoundary"
+	KeyRotationPrefixStr        = "KeyRotation"
+	KeyHistoryPrefixStr         = "KeyHistory"
+	NodeIdAliasPrefixStr        = "NodeIdAlias"
+	CommissionSchedulePrefixStr = "CommissionSchedule"
+	CommissionPendingPrefixStr  = "CommissionPending"
+	CommissionHistoryPrefixStr  = "CommissionHistory"
 )
 
 var (
-	CanBaseKeyPrefix        = []byte(CanBasePrefixStr)
-	CanMutableKeyPrefix     = []byte(CanMutablePrefixStr)
-	CanPowerKeyPrefix       = []byte(CanPowerPrefixStr)
-	UnStakeCountKey         = []byte(UnStakeCountKeyStr)
-	UnStakeItemKey          = []byte(UnStakeItemKeyStr)
-	DelegateKeyPrefix       = []byte(DelegatePrefixStr)
-	DelegationLockKeyPrefix = []byte(DelegationLockPrefixStr)
-	EpochIndexKey           = []byte(EpochIndexKeyStr)
-	EpochValArrPrefix       = []byte(EpochValArrPrefixStr)
-	RoundIndexKey           = []byte(RoundIndexKeyStr)
-	RoundValArrPrefix       = []byte(RoundValArrPrefixStr)
-	AccountStakeRcPrefix    = []byte(AccountStakeRcPrefixStr)
-	PPOSHASHKey             = []byte(PPOSHASHStr)
-	RoundValAddrArrPrefix   = []byte(RoundValAddrArrPrefixStr)
-	RoundAddrBoundaryPrefix = []byte(RoundAddrBoundaryPrefixStr)
+	CanBaseKeyPrefix         = []byte(CanBasePrefixStr)
+	CanMutableKeyPrefix      = []byte(CanMutablePrefixStr)
+	CanPowerKeyPrefix        = []byte(CanPowerPrefixStr)
+	UnStakeCountKey          = []byte(UnStakeCountKeyStr)
+	UnStakeItemKey           = []byte(UnStakeItemKeyStr)
+	DelegateKeyPrefix        = []byte(DelegatePrefixStr)
+	DelegationLockKeyPrefix  = []byte(DelegationLockPrefixStr)
+	EpochIndexKey            = []byte(EpochIndexKeyStr)
+	EpochValArrPrefix        = []byte(EpochValArrPrefixStr)
+	RoundIndexKey            = []byte(RoundIndexKeyStr)
+	RoundValArrPrefix        = []byte(RoundValArrPrefixStr)
+	AccountStakeRcPrefix     = []byte(AccountStakeRcPrefixStr)
+	PPOSHASHKey              = []byte(PPOSHASHStr)
+	RoundValAddrArrPrefix    = []byte(RoundValAddrArrPrefixStr)
+	RoundAddrBoundaryPrefix  = []byte(RoundAddrBoundaryPrefixStr)
+	KeyRotationPrefix        = []byte(KeyRotationPrefixStr)
+	KeyHistoryPrefix         = []byte(KeyHistoryPrefixStr)
+	NodeIdAliasPrefix        = []byte(NodeIdAliasPrefixStr)
+	CommissionSchedulePrefix = []byte(CommissionSchedulePrefixStr)
+	CommissionPendingPrefix  = []byte(CommissionPendingPrefixStr)
+	CommissionHistoryPrefix  = []byte(CommissionHistoryPrefixStr)
 
 	b104Len = len(math.MaxBig104.Bytes())
 )
@@ -158,7 +170,7 @@ func GetDelegateKey(delAddr common.Address, nodeId discover.NodeID, stakeBlockNu
 	return key
 }
 
-//notice this assume key must right
+// notice this assume key must right
 func DecodeDelegateKey(key []byte) (delAddr common.Address, nodeId discover.NodeID, stakeBlockNumber uint64) {
 	delegateKeyPrefixLength := len(DelegateKeyPrefix)
 	delAddrLength := len(delAddr) + delegateKeyPrefixLength
@@ -232,3 +244,71 @@ func GetRoundValAddrArrKey(round uint64) []byte {
 func GetRoundAddrBoundaryKey() []byte {
 	return RoundAddrBoundaryPrefix
 }
+
+// the prefix of the pending key rotations which take effect at the given epoch
+func GetKeyRotationPrefix(epoch uint64) []byte {
+	epochByte := common.Uint64ToBytes(epoch)
+
+	markPre := len(KeyRotationPrefix)
+	size := markPre + len(epochByte)
+
+	key := make([]byte, size)
+	copy(key[:markPre], KeyRotationPrefix)
+	copy(key[markPre:], epochByte)
+
+	return key
+}
+
+func GetKeyRotationKey(epoch uint64, addr common.NodeAddress) []byte {
+	prefix := GetKeyRotationPrefix(epoch)
+	addrByte := addr.Bytes()
+
+	key := make([]byte, len(prefix)+len(addrByte))
+	copy(key[:len(prefix)], prefix)
+	copy(key[len(prefix):], addrByte)
+
+	return key
+}
+
+func GetKeyHistoryKey(addr common.NodeAddress) []byte {
+	return append(KeyHistoryPrefix, addr.Bytes()...)
+}
+
+// the key of the candidate address which the node address of a rotated nodeId points to
+func GetNodeIdAliasKey(addr common.NodeAddress) []byte {
+	return append(NodeIdAliasPrefix, addr.Bytes()...)
+}
+
+// the prefix of the scheduled commission changes which take effect at the given epoch
+func GetCommissionSchedulePrefix(epoch uint64) []byte {
+	epochByte := common.Uint64ToBytes(epoch)
+
+	markPre := len(CommissionSchedulePrefix)
+	size := markPre + len(epochByte)
+
+	key := make([]byte, size)
+	copy(key[:markPre], CommissionSchedulePrefix)
+	copy(key[markPre:], epochByte)
+
+	return key
+}
+
+func GetCommissionScheduleKey(epoch uint64, addr common.NodeAddress) []byte {
+	prefix := GetCommissionSchedulePrefix(epoch)
+	addrByte := addr.Bytes()
+
+	key := make([]byte, len(prefix)+len(addrByte))
+	copy(key[:len(prefix)], prefix)
+	copy(key[len(prefix):], addrByte)
+
+	return key
+}
+
+// the key of the commission change which the candidate is waiting for
+func GetCommissionPendingKey(addr common.NodeAddress) []byte {
+	return append(CommissionPendingPrefix, addr.Bytes()...)
+}
+
+func GetCommissionHistoryKey(addr common.NodeAddress) []byte {
+	return append(CommissionHistoryPrefix, addr.Bytes()...)
+}
@@ -29,6 +29,7 @@ var (
 	ErrInvalidRewardPer             = common.NewBizError(301007, "Invalid param RewardPer")
 	ErrRewardPerInterval            = common.NewBizError(301008, "Modify the commission reward ratio too frequently")
 	ErrRewardPerChangeRange         = common.NewBizError(301009, "The modification range exceeds the limit")
+	ErrWrongNodeIdSign              = common.NewBizError(301010, "The signature of the new node ID is invalid")
 	ErrStakeVonTooLow               = common.NewBizError(301100, "Staking deposit is insufficient")
 	ErrCanAlreadyExist              = common.NewBizError(301101, "The candidate already existed")
 	ErrCanNoExist                   = common.NewBizError(301102, "The candidate does not exist")
@@ -49,6 +50,10 @@ var (
 	ErrWrongSlashType               = common.NewBizError(301117, "The slash type is illegal")
 	ErrSlashVonOverflow             = common.NewBizError(301118, "The amount of slash is overflowed")
 	ErrWrongSlashVonCalc            = common.NewBizError(301119, "The amount of slash for decreasing staking is incorrect")
+	ErrNodeIdAlreadyUsed            = common.NewBizError(301120, "The node ID is already used by another candidate")
+	ErrCanKeysNotChanged            = common.NewBizError(301121, "The new keys are the same as the keys of the candidate")
+	ErrNodeNotInAllowlist           = common.NewBizError(301122, "The node is not in the allowlist")
+	ErrStakingAddrNotInAllowlist    = common.NewBizError(301123, "The staking address is not the one allowed for the node")
 	ErrGetVerifierList              = common.NewBizError(301200, "Retreiving verifier list failed")
 	ErrGetValidatorList             = common.NewBizError(301201, "Retreiving validator list failed")
 	ErrGetCandidateList             = common.NewBizError(301202, "Retreiving candidate list failed")
@@ -58,4 +63,5 @@ var (
 	ErrNodeID2Addr                  = common.NewBizError(301206, "Failed to convert Node ID to address")
 	ErrDelegateLockBalanceNotEnough = common.NewBizError(301207, "the user delegation lock balance is not enough for delegate")
 	ErrQueryDelegationLockInfo      = common.NewBizError(301208, "Query delegation lock info failed")
+	ErrQueryCommissionInfo          = common.NewBizError(301209, "Query commission info failed")
 )
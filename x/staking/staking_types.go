@@ -979,6 +979,97 @@ type UnStakeItem struct {
 	Recovery bool
 }
 
+// The consensus keys of a candidate,
+// they take effect from the first block of the Epoch
+type CandidateKey struct {
+	Epoch     uint64
+	NodeId    discover.NodeID
+	BlsPubKey bls.PublicKeyHex
+}
+
+func (key *CandidateKey) String() string {
+	return fmt.Sprintf(`{"Epoch": %d,"NodeId": "%s","BlsPubKey": "%s"}`,
+		key.Epoch,
+		key.NodeId.String(),
+		fmt.Sprintf("%x", key.BlsPubKey.Bytes()))
+}
+
+// The keys history of a candidate, sorted by Epoch from small to big
+type CandidateKeyQueue []*CandidateKey
+
+// KeyAt returns the keys in force at the epoch,
+// nil means the candidate had not rotated its keys before the epoch
+func (queue CandidateKeyQueue) KeyAt(epoch uint64) *CandidateKey {
+	for i := len(queue) - 1; i >= 0; i-- {
+		if queue[i].Epoch <= epoch {
+			return queue[i]
+		}
+	}
+	return nil
+}
+
+func (queue CandidateKeyQueue) String() string {
+	arr := make([]string, len(queue))
+	for i, key := range queue {
+		arr[i] = key.String()
+	}
+	return "[" + strings.Join(arr, ",") + "]"
+}
+
+// The message signed by the new node key of a key rotation,
+// it binds the proof of possession to the staking address, the staking nodeId
+// of the candidate and the Epoch the rotation is submitted in
+type RotateKeyProof struct {
+	StakingAddress common.Address
+	NodeId         discover.NodeID
+	NewNodeId      discover.NodeID
+	Epoch          uint64
+}
+
+// The commission change announced by a candidate,
+// it takes effect from the first block of the EffectiveEpoch
+type CommissionSchedule struct {
+	RewardPer      uint16 `json:"rewardPer"`
+	AnnounceEpoch  uint32 `json:"announceEpoch"`
+	EffectiveEpoch uint32 `json:"effectiveEpoch"`
+}
+
+func (schedule *CommissionSchedule) String() string {
+	return fmt.Sprintf(`{"RewardPer": %d,"AnnounceEpoch": %d,"EffectiveEpoch": %d}`,
+		schedule.RewardPer,
+		schedule.AnnounceEpoch,
+		schedule.EffectiveEpoch)
+}
+
+// The commission reward ratio of a candidate in force from the Epoch
+type CommissionRecord struct {
+	Epoch     uint32 `json:"epoch"`
+	RewardPer uint16 `json:"rewardPer"`
+}
+
+// The commission history of a candidate, sorted by Epoch from small to big
+type CommissionHistory []*CommissionRecord
+
+func (history CommissionHistory) String() string {
+	arr := make([]string, len(history))
+	for i, record := range history {
+		arr[i] = fmt.Sprintf(`{"Epoch": %d,"RewardPer": %d}`, record.Epoch, record.RewardPer)
+	}
+	return "[" + strings.Join(arr, ",") + "]"
+}
+
+// CommissionScheduledEvent is posted when a candidate announces a commission change
+type CommissionScheduledEvent struct {
+	NodeId   discover.NodeID
+	Schedule *CommissionSchedule
+}
+
+// CommissionChangedEvent is posted when the announced commission change of a candidate takes effect
+type CommissionChangedEvent struct {
+	NodeId discover.NodeID
+	Record *CommissionRecord
+}
+
 type ValArrIndex struct {
 	Start uint64
 	End   uint64
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"github.com/hashkey-chain/hashkey-chain/params"
 	"github.com/hashkey-chain/hashkey-chain/rlp"
+	"math"
 	"math/big"
 	"sync"
 
@@ -60,6 +61,8 @@ const (
 	RewardPerMaxChangeRangeLowerLimit = 1
 	RewardPerChangeIntervalUpperLimit = 28
 	RewardPerChangeIntervalLowerLimit = 2
+	RewardPerNoticeEpochsUpperLimit   = 28
+	RewardPerNoticeEpochsLowerLimit   = 1
 	IncreaseIssuanceRatioUpperLimit   = 2000
 	IncreaseIssuanceRatioLowerLimit   = 0
 
@@ -166,19 +169,28 @@ type EconomicModel struct {
 
 // When the chain is started, if new parameters are added, add them to this structure
 type EconomicModelExtend struct {
-	Staking stakingConfigExtend `json:"staking"`
+	Staking     stakingConfigExtend     `json:"staking"`
+	ScheduledTx scheduledTxConfigExtend `json:"scheduledTx"`
 }
 
 type stakingConfigExtend struct {
 	// 可治理参数,在版本升级或者私链初始化版本高于1.3.0的时候被写入到快照db,后续通过快照db查询
 	UnDelegateFreezeDuration uint64 `json:"unDelegateFreezeDuration"` // The maximum number of delegates that can receive rewards at a time
+	// 可治理参数,在版本升级或者私链初始化版本高于1.4.0的时候被写入到快照db,后续通过快照db查询
+	RewardPerNoticeEpochs uint16 `json:"rewardPerNoticeEpochs"` // The number of epochs a commission increase must be announced ahead
+	// 可治理参数,在版本升级或者私链初始化版本高于1.5.0的时候被写入到快照db,后续通过快照db查询
+	ElectionPolicy string `json:"electionPolicy"` // The policy selecting the validators of each consensus round
+	// 可治理参数,在版本升级或者私链初始化版本高于1.5.0的时候被写入到快照db,后续通过快照db查询
+	AdmissionAllowlist bool `json:"admissionAllowlist"` // Whether only the nodes in the allowlist can stake
 }
 
-func EcParams130() ([]byte, error) {
+// EcParams130 returns the RLP encoded params added by the version 1.3.0, in
+// effect at the block.
+func EcParams130(blockNumber uint64) ([]byte, error) {
 	params := struct {
 		UnDelegateFreezeDuration uint64
 	}{
-		UnDelegateFreezeDuration: ece.Staking.UnDelegateFreezeDuration,
+		UnDelegateFreezeDuration: GetEceAt(blockNumber).Staking.UnDelegateFreezeDuration,
 	}
 	bytes, err := rlp.EncodeToBytes(params)
 	if err != nil {
@@ -193,7 +205,8 @@ var (
 	ece       *EconomicModelExtend
 )
 
-// Getting the global EconomicModel single instance
+// Getting the global EconomicModel single instance, it is the model of the
+// genesis. Use GetEcAt for the model in effect at a block.
 func GetEc(netId int8) *EconomicModel {
 	modelOnce.Do(func() {
 		ec = getDefaultEMConfig(netId)
@@ -201,22 +214,23 @@ func GetEc(netId int8) *EconomicModel {
 	return ec
 }
 
+// GetEce returns the extended economic model of the genesis. Use GetEceAt
+// for the model in effect at a block.
 func GetEce() *EconomicModelExtend {
 	return ece
 }
 
+// ResetEconomicDefaultConfig replaces the economic model of the genesis.
 func ResetEconomicDefaultConfig(newEc *EconomicModel) {
 	ec = newEc
 }
 
+// ResetEconomicExtendConfig replaces the extended economic model of the
+// genesis, the upgrades register their models by UpgradeEconomicExtend.
 func ResetEconomicExtendConfig(newEc *EconomicModelExtend) {
 	ece = newEc
 }
 
-func ResetEconomicExtendConfigUnDelegateFreezeDuration(UnDelegateFreezeDuration uint64) {
-	ece.Staking.UnDelegateFreezeDuration = UnDelegateFreezeDuration
-}
-
 const (
 	DefaultMainNet     = iota // PlatON default main net flag
 	DefaultTestNet            // PlatON default test net flag
@@ -295,6 +309,12 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 		ece = &EconomicModelExtend{
 			Staking: stakingConfigExtend{
 				UnDelegateFreezeDuration: 56,
+				RewardPerNoticeEpochs:    uint16(4),
+				ElectionPolicy:           ElectionPolicyVRF,
+			},
+			ScheduledTx: scheduledTxConfigExtend{
+				MaxTxsPerBlock: DefaultScheduledMaxTxsPerBlock,
+				MaxGasPerBlock: DefaultScheduledMaxGasPerBlock,
 			},
 		}
 	case DefaultTestNet:
@@ -355,6 +375,12 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 		ece = &EconomicModelExtend{
 			Staking: stakingConfigExtend{
 				UnDelegateFreezeDuration: 2,
+				RewardPerNoticeEpochs:    uint16(2),
+				ElectionPolicy:           ElectionPolicyVRF,
+			},
+			ScheduledTx: scheduledTxConfigExtend{
+				MaxTxsPerBlock: DefaultScheduledMaxTxsPerBlock,
+				MaxGasPerBlock: DefaultScheduledMaxGasPerBlock,
 			},
 		}
 	case DefaultUnitTestNet:
@@ -415,6 +441,12 @@ func getDefaultEMConfig(netId int8) *EconomicModel {
 		ece = &EconomicModelExtend{
 			Staking: stakingConfigExtend{
 				UnDelegateFreezeDuration: 2,
+				RewardPerNoticeEpochs:    uint16(2),
+				ElectionPolicy:           ElectionPolicyVRF,
+			},
+			ScheduledTx: scheduledTxConfigExtend{
+				MaxTxsPerBlock: DefaultScheduledMaxTxsPerBlock,
+				MaxGasPerBlock: DefaultScheduledMaxGasPerBlock,
 			},
 		}
 	default: // DefaultTestNet
@@ -521,6 +553,13 @@ func CheckRewardPerChangeInterval(rewardPerChangeInterval uint16) error {
 	return nil
 }
 
+func CheckRewardPerNoticeEpochs(rewardPerNoticeEpochs uint16) error {
+	if rewardPerNoticeEpochs < RewardPerNoticeEpochsLowerLimit || rewardPerNoticeEpochs > RewardPerNoticeEpochsUpperLimit {
+		return common.InvalidParameter.Wrap(fmt.Sprintf("The RewardPerNoticeEpochs must be [%d, %d]", RewardPerNoticeEpochsLowerLimit, RewardPerNoticeEpochsUpperLimit))
+	}
+	return nil
+}
+
 func CheckIncreaseIssuanceRatio(increaseIssuanceRatio uint16) error {
 	if increaseIssuanceRatio < IncreaseIssuanceRatioLowerLimit || increaseIssuanceRatio > IncreaseIssuanceRatioUpperLimit {
 		return common.InvalidParameter.Wrap(fmt.Sprintf("The IncreaseIssuanceRatio must be [%d, %d]", IncreaseIssuanceRatioLowerLimit, IncreaseIssuanceRatioUpperLimit))
@@ -657,14 +696,30 @@ func CheckEconomicModel(version uint32) error {
 			return err
 		}
 	}
+	if version >= params.FORKVERSION_1_4_0 {
+		if err := CheckRewardPerNoticeEpochs(ece.Staking.RewardPerNoticeEpochs); nil != err {
+			return err
+		}
+	}
+	if version >= params.FORKVERSION_1_5_0 {
+		if err := CheckElectionPolicy(ElectionPolicy(0)); nil != err {
+			return err
+		}
+		if err := CheckScheduledMaxTxsPerBlock(ScheduledMaxTxsPerBlock(0)); nil != err {
+			return err
+		}
+		if err := CheckScheduledMaxGasPerBlock(ScheduledMaxGasPerBlock(0)); nil != err {
+			return err
+		}
+	}
 	return nil
 }
 
 /******
  * Common configure
  ******/
-func MaxEpochMinutes() uint64 {
-	return ec.Common.MaxEpochMinutes
+func MaxEpochMinutes(blockNumber uint64) uint64 {
+	return GetEcAt(blockNumber).Common.MaxEpochMinutes
 }
 
 // set the value by genesis block
@@ -685,21 +740,21 @@ func Interval() uint64 {
 func BlocksWillCreate() uint64 {
 	return ec.Common.PerRoundBlocks
 }
-func MaxConsensusVals() uint64 {
-	return ec.Common.MaxConsensusVals
+func MaxConsensusVals(blockNumber uint64) uint64 {
+	return GetEcAt(blockNumber).Common.MaxConsensusVals
 }
 
-func AdditionalCycleTime() uint64 {
-	return ec.Common.AdditionalCycleTime
+func AdditionalCycleTime(blockNumber uint64) uint64 {
+	return GetEcAt(blockNumber).Common.AdditionalCycleTime
 }
 
 func ConsensusSize() uint64 {
-	return BlocksWillCreate() * MaxConsensusVals()
+	return BlocksWillCreate() * ec.Common.MaxConsensusVals
 }
 
 func EpochSize() uint64 {
 	consensusSize := ConsensusSize()
-	em := MaxEpochMinutes()
+	em := ec.Common.MaxEpochMinutes
 	i := Interval()
 
 	epochSize := em * 60 / (i * consensusSize)
@@ -721,8 +776,8 @@ func MaxValidators() uint64 {
 	return ec.Staking.MaxValidators
 }
 
-func ShiftValidatorNum() uint64 {
-	return (ec.Common.MaxConsensusVals - 1) / 3
+func ShiftValidatorNum(blockNumber uint64) uint64 {
+	return (GetEcAt(blockNumber).Common.MaxConsensusVals - 1) / 3
 }
 
 func HesitateRatio() uint64 {
@@ -746,8 +801,27 @@ func RewardPerChangeInterval() uint16 {
 	return ec.Staking.RewardPerChangeInterval
 }
 
-func UnDelegateFreezeDuration() uint64 {
-	return ece.Staking.UnDelegateFreezeDuration
+func UnDelegateFreezeDuration(blockNumber uint64) uint64 {
+	return GetEceAt(blockNumber).Staking.UnDelegateFreezeDuration
+}
+
+func RewardPerNoticeEpochs(blockNumber uint64) uint16 {
+	return GetEceAt(blockNumber).Staking.RewardPerNoticeEpochs
+}
+
+// AdmissionAllowlist reports whether only the nodes in the allowlist are
+// admitted to stake at the block, the initial nodes are in the allowlist.
+func AdmissionAllowlist(blockNumber uint64) bool {
+	return GetEceAt(blockNumber).Staking.AdmissionAllowlist
+}
+
+// ElectionPolicy returns the election policy in effect at the block, the
+// models stored before the policy was added select by VRF.
+func ElectionPolicy(blockNumber uint64) string {
+	if policy := GetEceAt(blockNumber).Staking.ElectionPolicy; policy != "" {
+		return policy
+	}
+	return ElectionPolicyVRF
 }
 
 /******
@@ -792,20 +866,20 @@ func ZeroProduceFreezeDuration() uint64 {
 /******
  * Reward config
  ******/
-func NewBlockRewardRate() uint64 {
-	return ec.Reward.NewBlockRate
+func NewBlockRewardRate(blockNumber uint64) uint64 {
+	return GetEcAt(blockNumber).Reward.NewBlockRate
 }
 
-func PlatONFoundationYear() uint32 {
-	return ec.Reward.PlatONFoundationYear
+func PlatONFoundationYear(blockNumber uint64) uint32 {
+	return GetEcAt(blockNumber).Reward.PlatONFoundationYear
 }
 
 func IncreaseIssuanceRatio() uint16 {
 	return ec.Reward.IncreaseIssuanceRatio
 }
 
-func TheNumberOfDelegationsReward() uint16 {
-	return ec.Reward.TheNumberOfDelegationsReward
+func TheNumberOfDelegationsReward(blockNumber uint64) uint16 {
+	return GetEcAt(blockNumber).Reward.TheNumberOfDelegationsReward
 }
 
 /******
@@ -815,16 +889,16 @@ func TheNumberOfDelegationsReward() uint16 {
 	return ec.Gov.VersionProposalVoteDurationSeconds / (Interval() * ec.Common.PerRoundBlocks * ec.Common.MaxConsensusVals)
 }*/
 
-func VersionProposalVote_DurationSeconds() uint64 {
-	return ec.Gov.VersionProposalVoteDurationSeconds
+func VersionProposalVote_DurationSeconds(blockNumber uint64) uint64 {
+	return GetEcAt(blockNumber).Gov.VersionProposalVoteDurationSeconds
 }
 
 /*func VersionProposalActive_ConsensusRounds() uint64 {
 	return ec.Gov.VersionProposalActive_ConsensusRounds
 }*/
 
-func VersionProposal_SupportRate() uint64 {
-	return ec.Gov.VersionProposalSupportRate
+func VersionProposal_SupportRate(blockNumber uint64) uint64 {
+	return GetEcAt(blockNumber).Gov.VersionProposalSupportRate
 }
 
 /*
@@ -832,57 +906,59 @@ func VersionProposal_SupportRate() uint64 {
 		return ec.Gov.TextProposalVoteDurationSeconds / (Interval() * ec.Common.PerRoundBlocks * ec.Common.MaxConsensusVals)
 	}
 */
-func TextProposalVote_DurationSeconds() uint64 {
-	return ec.Gov.TextProposalVoteDurationSeconds
+func TextProposalVote_DurationSeconds(blockNumber uint64) uint64 {
+	return GetEcAt(blockNumber).Gov.TextProposalVoteDurationSeconds
 }
-func TextProposal_VoteRate() uint64 {
-	return ec.Gov.TextProposalVoteRate
+func TextProposal_VoteRate(blockNumber uint64) uint64 {
+	return GetEcAt(blockNumber).Gov.TextProposalVoteRate
 }
 
-func TextProposal_SupportRate() uint64 {
-	return ec.Gov.TextProposalSupportRate
+func TextProposal_SupportRate(blockNumber uint64) uint64 {
+	return GetEcAt(blockNumber).Gov.TextProposalSupportRate
 }
 
-func CancelProposal_VoteRate() uint64 {
-	return ec.Gov.CancelProposalVoteRate
+func CancelProposal_VoteRate(blockNumber uint64) uint64 {
+	return GetEcAt(blockNumber).Gov.CancelProposalVoteRate
 }
 
-func CancelProposal_SupportRate() uint64 {
-	return ec.Gov.CancelProposalSupportRate
+func CancelProposal_SupportRate(blockNumber uint64) uint64 {
+	return GetEcAt(blockNumber).Gov.CancelProposalSupportRate
 }
 
-func ParamProposalVote_DurationSeconds() uint64 {
-	return ec.Gov.ParamProposalVoteDurationSeconds
+func ParamProposalVote_DurationSeconds(blockNumber uint64) uint64 {
+	return GetEcAt(blockNumber).Gov.ParamProposalVoteDurationSeconds
 }
 
-func ParamProposal_VoteRate() uint64 {
-	return ec.Gov.ParamProposalVoteRate
+func ParamProposal_VoteRate(blockNumber uint64) uint64 {
+	return GetEcAt(blockNumber).Gov.ParamProposalVoteRate
 }
 
-func ParamProposal_SupportRate() uint64 {
-	return ec.Gov.ParamProposalSupportRate
+func ParamProposal_SupportRate(blockNumber uint64) uint64 {
+	return GetEcAt(blockNumber).Gov.ParamProposalSupportRate
 }
 
 /******
  * Inner Account Config
  ******/
-func PlatONFundAccount() common.Address {
-	return ec.InnerAcc.PlatONFundAccount
+func PlatONFundAccount(blockNumber uint64) common.Address {
+	return GetEcAt(blockNumber).InnerAcc.PlatONFundAccount
 }
 
 func PlatONFundBalance() *big.Int {
 	return ec.InnerAcc.PlatONFundBalance
 }
 
-func CDFAccount() common.Address {
-	return ec.InnerAcc.CDFAccount
+func CDFAccount(blockNumber uint64) common.Address {
+	return GetEcAt(blockNumber).InnerAcc.CDFAccount
 }
 
 func CDFBalance() *big.Int {
 	return ec.InnerAcc.CDFBalance
 }
 
+// EconomicString returns the JSON of the economic model activated last.
 func EconomicString() string {
+	ec, ece := GetEcAt(math.MaxUint64), GetEceAt(math.MaxUint64)
 	if nil != ec {
 		type stakingConfigJson struct {
 			stakingConfig
@@ -890,7 +966,8 @@ func EconomicString() string {
 		}
 		type EconomicModelJson struct {
 			EconomicModel
-			Staking stakingConfigJson `json:"staking"`
+			Staking     stakingConfigJson       `json:"staking"`
+			ScheduledTx scheduledTxConfigExtend `json:"scheduledTx"`
 		}
 		emJson := &EconomicModelJson{
 			EconomicModel: *ec,
@@ -898,6 +975,7 @@ func EconomicString() string {
 				stakingConfig:       ec.Staking,
 				stakingConfigExtend: ece.Staking,
 			},
+			ScheduledTx: ece.ScheduledTx,
 		}
 		ecByte, _ := json.Marshal(emJson)
 		return string(ecByte)
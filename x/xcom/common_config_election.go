@@ -0,0 +1,61 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package xcom
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+)
+
+// The election policies built in the staking plugin, the policy in effect is
+// chosen by the genesis and governed by the param proposals.
+const (
+	ElectionPolicyVRF   = "vrf"   // Weighted by the VRF proofs and the square root of the shares
+	ElectionPolicyStake = "stake" // Ranked by the shares, deterministic
+)
+
+var electionPolicies = map[string]struct{}{
+	ElectionPolicyVRF:   {},
+	ElectionPolicyStake: {},
+}
+
+// AddElectionPolicy makes the name of a policy registered by the staking
+// plugin acceptable to the genesis and the param proposals, it must be called
+// before the chain is loaded.
+func AddElectionPolicy(name string) {
+	electionPolicies[name] = struct{}{}
+}
+
+// ElectionPolicies returns the sorted names of the known election policies.
+func ElectionPolicies() []string {
+	names := make([]string, 0, len(electionPolicies))
+	for name := range electionPolicies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func CheckElectionPolicy(name string) error {
+	if _, ok := electionPolicies[name]; !ok {
+		return common.InvalidParameter.Wrap(fmt.Sprintf("The ElectionPolicy must be one of [%s], ElectionPolicy: %s", strings.Join(ElectionPolicies(), ", "), name))
+	}
+	return nil
+}
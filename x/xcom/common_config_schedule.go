@@ -0,0 +1,224 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package xcom
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+)
+
+// BlockSchedule is the block production schedule in effect from the first
+// block of an epoch, the NodeBlockTimeWindow and PerRoundBlocks are governed
+// by the param proposals. The epochs and rounds before the schedule keep the
+// sizes they were produced with, so the historical blocks are validated as
+// they were.
+type BlockSchedule struct {
+	BlockNumber         uint64 // The first block of the schedule, it begins an epoch
+	Epoch               uint64 // The epoch begun by the first block
+	Round               uint64 // The consensus round begun by the first block
+	NodeBlockTimeWindow uint64 // Node block time window (uint: seconds)
+	PerRoundBlocks      uint64 // blocks each validator will create per consensus round
+}
+
+// BlockSchedules is the list of the governed schedules, sorted by the first
+// blocks.
+type BlockSchedules []*BlockSchedule
+
+// The governed schedules are stored with the PPOS data of the block tallying
+// the param proposal, the ones committed are loaded here. A schedule begins
+// at least an epoch after it's tallied, so the schedule of any block being
+// executed is committed already.
+var (
+	schedulesLock sync.RWMutex
+	schedules     BlockSchedules
+)
+
+// Interval returns the seconds between the blocks.
+func (s *BlockSchedule) Interval() uint64 {
+	return s.NodeBlockTimeWindow / s.PerRoundBlocks
+}
+
+// ConsensusSize returns the blocks of a consensus round.
+func (s *BlockSchedule) ConsensusSize() uint64 {
+	return s.PerRoundBlocks * MaxConsensusVals(s.BlockNumber)
+}
+
+// EpochSize returns the consensus rounds of an epoch.
+func (s *BlockSchedule) EpochSize() uint64 {
+	return MaxEpochMinutes(s.BlockNumber) * 60 / (s.Interval() * s.ConsensusSize())
+}
+
+// BlocksEachEpoch returns the blocks of an epoch.
+func (s *BlockSchedule) BlocksEachEpoch() uint64 {
+	return s.ConsensusSize() * s.EpochSize()
+}
+
+// ElectionDistance returns the blocks between the election of the next
+// round validators and the end of the round.
+func (s *BlockSchedule) ElectionDistance() uint64 {
+	// min need two view
+	return 2 * s.PerRoundBlocks
+}
+
+// Period returns the time window of a view in milliseconds, the period of
+// the cbft config.
+func (s *BlockSchedule) Period() uint64 {
+	return s.NodeBlockTimeWindow * 1000
+}
+
+// genesisSchedule returns the schedule of the genesis economic model.
+func genesisSchedule() *BlockSchedule {
+	return &BlockSchedule{
+		BlockNumber:         1,
+		Epoch:               1,
+		Round:               1,
+		NodeBlockTimeWindow: ec.Common.NodeBlockTimeWindow,
+		PerRoundBlocks:      ec.Common.PerRoundBlocks,
+	}
+}
+
+// Register returns the list with the schedule in effect from the block, the
+// block must begin an epoch of the former schedule. Registering the same
+// block again replaces the former schedule, as a replay of the tally does.
+func (ss BlockSchedules) Register(blockNumber, nodeBlockTimeWindow, perRoundBlocks uint64) (BlockSchedules, *BlockSchedule, error) {
+	s := &BlockSchedule{
+		BlockNumber:         blockNumber,
+		NodeBlockTimeWindow: nodeBlockTimeWindow,
+		PerRoundBlocks:      perRoundBlocks,
+	}
+	i := sort.Search(len(ss), func(i int) bool { return ss[i].BlockNumber >= blockNumber })
+	former := genesisSchedule()
+	if i > 0 {
+		former = ss[i-1]
+	}
+	if blockNumber <= former.BlockNumber || (blockNumber-former.BlockNumber)%former.BlocksEachEpoch() != 0 {
+		return nil, nil, fmt.Errorf("the block schedule must begin an epoch, blockNumber: %d", blockNumber)
+	}
+
+	list := make(BlockSchedules, 0, len(ss)+1)
+	list = append(append(list, ss[:i]...), s)
+	later := ss[i:]
+	if len(later) > 0 && later[0].BlockNumber == blockNumber {
+		later = later[1:]
+	}
+	for _, s := range later {
+		cpy := *s
+		list = append(list, &cpy)
+	}
+	// The epochs and rounds of the later schedules follow the sizes of the
+	// replaced one.
+	for _, s := range list[i:] {
+		s.Epoch = former.Epoch + (s.BlockNumber-former.BlockNumber)/former.BlocksEachEpoch()
+		s.Round = former.Round + (s.BlockNumber-former.BlockNumber)/former.ConsensusSize()
+		former = s
+	}
+	cpy := *s
+	return list, &cpy, nil
+}
+
+// Update returns the list with the schedule in effect from the block, it is
+// derived from the schedule in effect at the block by the update.
+func (ss BlockSchedules) Update(blockNumber uint64, update func(s *BlockSchedule)) (BlockSchedules, *BlockSchedule, error) {
+	s := ss.At(blockNumber)
+	update(s)
+	return ss.Register(blockNumber, s.NodeBlockTimeWindow, s.PerRoundBlocks)
+}
+
+// At returns the schedule in effect at the block.
+func (ss BlockSchedules) At(blockNumber uint64) *BlockSchedule {
+	i := sort.Search(len(ss), func(i int) bool { return ss[i].BlockNumber > blockNumber })
+	if i == 0 {
+		return genesisSchedule()
+	}
+	cpy := *ss[i-1]
+	return &cpy
+}
+
+// OfEpoch returns the schedule in effect in the epoch.
+func (ss BlockSchedules) OfEpoch(epoch uint64) *BlockSchedule {
+	i := sort.Search(len(ss), func(i int) bool { return ss[i].Epoch > epoch })
+	if i == 0 {
+		return genesisSchedule()
+	}
+	cpy := *ss[i-1]
+	return &cpy
+}
+
+// OfRound returns the schedule in effect in the consensus round.
+func (ss BlockSchedules) OfRound(round uint64) *BlockSchedule {
+	i := sort.Search(len(ss), func(i int) bool { return ss[i].Round > round })
+	if i == 0 {
+		return genesisSchedule()
+	}
+	cpy := *ss[i-1]
+	return &cpy
+}
+
+// SetBlockSchedules replaces the committed schedules.
+func SetBlockSchedules(ss BlockSchedules) {
+	schedulesLock.Lock()
+	defer schedulesLock.Unlock()
+
+	schedules = ss
+}
+
+// ResetBlockSchedules drops the committed schedules, the schedule of the
+// genesis is used for all blocks afterwards.
+func ResetBlockSchedules() {
+	SetBlockSchedules(nil)
+}
+
+// BlockScheduleAt returns the committed schedule in effect at the block.
+func BlockScheduleAt(blockNumber uint64) *BlockSchedule {
+	schedulesLock.RLock()
+	defer schedulesLock.RUnlock()
+	return schedules.At(blockNumber)
+}
+
+// BlockScheduleOfEpoch returns the committed schedule in effect in the epoch.
+func BlockScheduleOfEpoch(epoch uint64) *BlockSchedule {
+	schedulesLock.RLock()
+	defer schedulesLock.RUnlock()
+	return schedules.OfEpoch(epoch)
+}
+
+// BlockScheduleOfRound returns the committed schedule in effect in the
+// consensus round.
+func BlockScheduleOfRound(round uint64) *BlockSchedule {
+	schedulesLock.RLock()
+	defer schedulesLock.RUnlock()
+	return schedules.OfRound(round)
+}
+
+// CheckBlockSchedule checks the NodeBlockTimeWindow and PerRoundBlocks
+// governed at the block as CheckEconomicModel does for the genesis.
+func CheckBlockSchedule(blockNumber, nodeBlockTimeWindow, perRoundBlocks uint64) error {
+	if perRoundBlocks == 0 || nodeBlockTimeWindow < perRoundBlocks || nodeBlockTimeWindow%perRoundBlocks != 0 {
+		return common.InvalidParameter.Wrap(fmt.Sprintf("The NodeBlockTimeWindow must be integer multiples of the PerRoundBlocks, NodeBlockTimeWindow: %d, PerRoundBlocks: %d", nodeBlockTimeWindow, perRoundBlocks))
+	}
+	s := &BlockSchedule{BlockNumber: blockNumber, NodeBlockTimeWindow: nodeBlockTimeWindow, PerRoundBlocks: perRoundBlocks}
+	if s.EpochSize() < 4 {
+		return common.InvalidParameter.Wrap("The settlement period must be more than four times the consensus period")
+	}
+	if AdditionalCycleTime(blockNumber)*60/(s.EpochSize()*s.ConsensusSize()*s.Interval()) < 4 {
+		return common.InvalidParameter.Wrap("The issuance period must be integer multiples of the settlement period and multiples must be greater than or equal to 4")
+	}
+	return nil
+}
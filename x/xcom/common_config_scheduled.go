@@ -0,0 +1,79 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package xcom
+
+import (
+	"fmt"
+
+	"github.com/hashkey-chain/hashkey-chain/common"
+	"github.com/hashkey-chain/hashkey-chain/params"
+)
+
+// The limits of the scheduled transactions executed at the beginning of a
+// block, they are executed out of the block gas limit.
+const (
+	DefaultScheduledMaxTxsPerBlock uint32 = 20
+	DefaultScheduledMaxGasPerBlock uint64 = 5000000
+
+	ScheduledMaxTxsPerBlockLowerLimit uint32 = 1
+	ScheduledMaxTxsPerBlockUpperLimit uint32 = 1000
+	ScheduledMaxGasPerBlockLowerLimit        = params.TxGas
+	ScheduledMaxGasPerBlockUpperLimit        = params.GenesisGasLimit
+)
+
+type scheduledTxConfigExtend struct {
+	// 可治理参数,在版本升级或者私链初始化版本高于1.5.0的时候被写入到快照db,后续通过快照db查询
+	MaxTxsPerBlock uint32 `json:"maxTxsPerBlock"` // The maximum number of the scheduled transactions executed in a block
+	// 可治理参数,在版本升级或者私链初始化版本高于1.5.0的时候被写入到快照db,后续通过快照db查询
+	MaxGasPerBlock uint64 `json:"maxGasPerBlock"` // The maximum gas of the scheduled transactions executed in a block
+}
+
+// ScheduledMaxTxsPerBlock returns the maximum number of the scheduled
+// transactions executed in a block, in effect at the block, the models stored before
+// the scheduled transactions were added use the default.
+func ScheduledMaxTxsPerBlock(blockNumber uint64) uint32 {
+	ece := GetEceAt(blockNumber)
+	if ece.ScheduledTx.MaxTxsPerBlock == 0 {
+		return DefaultScheduledMaxTxsPerBlock
+	}
+	return ece.ScheduledTx.MaxTxsPerBlock
+}
+
+// ScheduledMaxGasPerBlock returns the maximum gas of the scheduled
+// transactions executed in a block, in effect at the block, the models stored before
+// the scheduled transactions were added use the default.
+func ScheduledMaxGasPerBlock(blockNumber uint64) uint64 {
+	ece := GetEceAt(blockNumber)
+	if ece.ScheduledTx.MaxGasPerBlock == 0 {
+		return DefaultScheduledMaxGasPerBlock
+	}
+	return ece.ScheduledTx.MaxGasPerBlock
+}
+
+func CheckScheduledMaxTxsPerBlock(num uint32) error {
+	if num < ScheduledMaxTxsPerBlockLowerLimit || num > ScheduledMaxTxsPerBlockUpperLimit {
+		return common.InvalidParameter.Wrap(fmt.Sprintf("The MaxTxsPerBlock of the scheduled transactions must be [%d, %d]", ScheduledMaxTxsPerBlockLowerLimit, ScheduledMaxTxsPerBlockUpperLimit))
+	}
+	return nil
+}
+
+func CheckScheduledMaxGasPerBlock(gas uint64) error {
+	if gas < ScheduledMaxGasPerBlockLowerLimit || gas > ScheduledMaxGasPerBlockUpperLimit {
+		return common.InvalidParameter.Wrap(fmt.Sprintf("The MaxGasPerBlock of the scheduled transactions must be [%d, %d]", ScheduledMaxGasPerBlockLowerLimit, ScheduledMaxGasPerBlockUpperLimit))
+	}
+	return nil
+}
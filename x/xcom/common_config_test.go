@@ -62,3 +62,55 @@ func TestMainNetHash(t *testing.T) {
 	}
 	assert.True(t, common.RlpHash(bytes).Hex() == MainNetECHash)
 }
+
+func TestEconomicVersions(t *testing.T) {
+	defer ResetEconomicVersions()
+	ResetEconomicVersions()
+	// The models of the genesis are set up by the test, the other tests replace them
+	genesisEc := getDefaultEMConfig(DefaultUnitTestNet)
+	ResetEconomicDefaultConfig(genesisEc)
+
+	genesisEce := GetEce()
+	assert.Nil(t, EconomicVersionAt(100))
+	assert.Equal(t, genesisEce, GetEceAt(100))
+
+	ev130 := UpgradeEconomicExtend(params.FORKVERSION_1_3_0, 100, func(ece *EconomicModelExtend) {
+		ece.Staking.UnDelegateFreezeDuration = genesisEce.Staking.UnDelegateFreezeDuration + 1
+	})
+	ev140 := UpgradeEconomicExtend(params.FORKVERSION_1_4_0, 200, func(ece *EconomicModelExtend) {
+		ece.Staking.RewardPerNoticeEpochs = genesisEce.Staking.RewardPerNoticeEpochs + 1
+	})
+
+	// The models of the former blocks are left unchanged
+	assert.Equal(t, genesisEce, GetEceAt(99))
+	assert.Equal(t, ev130.Ece, GetEceAt(100))
+	assert.Equal(t, ev130.Ece, GetEceAt(199))
+	assert.Equal(t, ev140.Ece, GetEceAt(200))
+	assert.Equal(t, genesisEce.Staking.UnDelegateFreezeDuration+1, GetEceAt(100).Staking.UnDelegateFreezeDuration)
+	assert.Equal(t, genesisEce.Staking.RewardPerNoticeEpochs, GetEceAt(100).Staking.RewardPerNoticeEpochs)
+	assert.Equal(t, genesisEce.Staking.UnDelegateFreezeDuration+1, GetEceAt(200).Staking.UnDelegateFreezeDuration)
+	assert.Equal(t, genesisEce.Staking.RewardPerNoticeEpochs+1, GetEceAt(200).Staking.RewardPerNoticeEpochs)
+	assert.Equal(t, genesisEc, GetEcAt(200))
+
+	// The getters resolve the model in effect at the block
+	assert.Equal(t, genesisEce.Staking.UnDelegateFreezeDuration, UnDelegateFreezeDuration(99))
+	assert.Equal(t, genesisEce.Staking.UnDelegateFreezeDuration+1, UnDelegateFreezeDuration(100))
+	assert.Equal(t, genesisEce.Staking.RewardPerNoticeEpochs, RewardPerNoticeEpochs(199))
+	assert.Equal(t, genesisEce.Staking.RewardPerNoticeEpochs+1, RewardPerNoticeEpochs(200))
+	assert.Equal(t, ElectionPolicy(0), ElectionPolicy(200))
+	assert.Equal(t, genesisEc.Common.MaxConsensusVals, MaxConsensusVals(200))
+
+	// The params of version 1.3.0 are resolved against the block
+	before, err := EcParams130(99)
+	assert.Nil(t, err)
+	after, err := EcParams130(100)
+	assert.Nil(t, err)
+	assert.NotEqual(t, before, after)
+
+	// Replaying the active block replaces the model
+	UpgradeEconomicExtend(params.FORKVERSION_1_3_0, 100, func(ece *EconomicModelExtend) {
+		ece.Staking.UnDelegateFreezeDuration = genesisEce.Staking.UnDelegateFreezeDuration + 2
+	})
+	assert.Len(t, EconomicVersions(), 2)
+	assert.Equal(t, genesisEce.Staking.UnDelegateFreezeDuration+2, GetEceAt(150).Staking.UnDelegateFreezeDuration)
+}
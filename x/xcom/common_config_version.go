@@ -0,0 +1,163 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package xcom
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// EconomicVersion is the economic model activated by a version proposal, it
+// takes effect from the active block of the version. The models are never
+// modified once registered, so the blocks before the activation are replayed
+// with the model they were executed with. A nil model keeps the one of the
+// genesis.
+type EconomicVersion struct {
+	Version     uint32
+	BlockNumber uint64
+	Ec          *EconomicModel
+	Ece         *EconomicModelExtend
+}
+
+var (
+	versionsLock sync.RWMutex
+	versions     []*EconomicVersion // Sorted by the active block
+)
+
+// Copy returns a deep copy of the economic model.
+func (e *EconomicModel) Copy() *EconomicModel {
+	if e == nil {
+		return nil
+	}
+	cpy := *e
+	cpy.Staking.StakeThreshold = copyBig(e.Staking.StakeThreshold)
+	cpy.Staking.OperatingThreshold = copyBig(e.Staking.OperatingThreshold)
+	cpy.Restricting.MinimumRelease = copyBig(e.Restricting.MinimumRelease)
+	cpy.InnerAcc.PlatONFundBalance = copyBig(e.InnerAcc.PlatONFundBalance)
+	cpy.InnerAcc.CDFBalance = copyBig(e.InnerAcc.CDFBalance)
+	return &cpy
+}
+
+// Copy returns a deep copy of the extended economic model.
+func (e *EconomicModelExtend) Copy() *EconomicModelExtend {
+	if e == nil {
+		return nil
+	}
+	cpy := *e
+	return &cpy
+}
+
+func copyBig(v *big.Int) *big.Int {
+	if v == nil {
+		return nil
+	}
+	return new(big.Int).Set(v)
+}
+
+// RegisterEconomicVersion records the economic model activated with the
+// version at the block. The models are copied, registering the same block
+// again replaces the former model, as a replay of the active block does.
+func RegisterEconomicVersion(version uint32, blockNumber uint64, ec *EconomicModel, ece *EconomicModelExtend) *EconomicVersion {
+	ev := &EconomicVersion{
+		Version:     version,
+		BlockNumber: blockNumber,
+		Ec:          ec.Copy(),
+		Ece:         ece.Copy(),
+	}
+
+	versionsLock.Lock()
+	defer versionsLock.Unlock()
+
+	i := sort.Search(len(versions), func(i int) bool { return versions[i].BlockNumber >= blockNumber })
+	if i < len(versions) && versions[i].BlockNumber == blockNumber {
+		versions[i] = ev
+		return ev
+	}
+	versions = append(versions, nil)
+	copy(versions[i+1:], versions[i:])
+	versions[i] = ev
+	return ev
+}
+
+// UpgradeEconomicExtend registers the extended economic model activated with
+// the version at the block, it is derived from the model of the former block
+// by the update.
+func UpgradeEconomicExtend(version uint32, blockNumber uint64, update func(ece *EconomicModelExtend)) *EconomicVersion {
+	var former uint64
+	if blockNumber > 0 {
+		former = blockNumber - 1
+	}
+	var ec *EconomicModel
+	if ev := EconomicVersionAt(former); ev != nil {
+		ec = ev.Ec
+	}
+	ece := GetEceAt(former).Copy()
+	if ece == nil {
+		ece = new(EconomicModelExtend)
+	}
+	update(ece)
+	return RegisterEconomicVersion(version, blockNumber, ec, ece)
+}
+
+// EconomicVersionAt returns the last economic version activated at or before
+// the block, nil if no version was activated since the genesis.
+func EconomicVersionAt(blockNumber uint64) *EconomicVersion {
+	versionsLock.RLock()
+	defer versionsLock.RUnlock()
+
+	i := sort.Search(len(versions), func(i int) bool { return versions[i].BlockNumber > blockNumber })
+	if i == 0 {
+		return nil
+	}
+	return versions[i-1]
+}
+
+// EconomicVersions returns the economic versions activated since the genesis.
+func EconomicVersions() []*EconomicVersion {
+	versionsLock.RLock()
+	defer versionsLock.RUnlock()
+
+	return append([]*EconomicVersion(nil), versions...)
+}
+
+// ResetEconomicVersions drops the activated economic versions, the models of
+// the genesis are used for all blocks afterwards.
+func ResetEconomicVersions() {
+	versionsLock.Lock()
+	defer versionsLock.Unlock()
+
+	versions = nil
+}
+
+// GetEcAt returns the economic model in effect at the block, the getters
+// of the model taking a block number read it.
+func GetEcAt(blockNumber uint64) *EconomicModel {
+	if ev := EconomicVersionAt(blockNumber); ev != nil && ev.Ec != nil {
+		return ev.Ec
+	}
+	return ec
+}
+
+// GetEceAt returns the extended economic model in effect at the block, the
+// getters of the model taking a block number read it.
+func GetEceAt(blockNumber uint64) *EconomicModelExtend {
+	if ev := EconomicVersionAt(blockNumber); ev != nil && ev.Ece != nil {
+		return ev.Ece
+	}
+	return ece
+}
@@ -69,6 +69,11 @@ type StateDB interface {
 	AddLog(*types.Log)
 	AddPreimage(common.Hash, []byte)
 
+	// AddBalanceChange records a balance change made by the plugins
+	AddBalanceChange(*types.BalanceChange)
+	// SetTxContext sets the current transaction, it is cleared for the block phase
+	SetTxContext(common.Hash, int)
+
 	ForEachStorage(common.Address, func([]byte, []byte) bool)
 
 	//ppos add
@@ -62,12 +62,14 @@ func ProgramVersion2Str(programVersion uint32) string {
 	return fmt.Sprintf("%d.%d.%d", major, minor, patch)
 }
 
-// ConsensusSize returns how many blocks per consensus round.
+// ConsensusSize returns the blocks of a consensus round of the genesis, use
+// ConsensusSizeAt for the round of a block.
 func ConsensusSize() uint64 {
 	return xcom.ConsensusSize()
 }
 
-// EpochSize returns how many consensus rounds per epoch.
+// EpochSize returns the consensus rounds of an epoch of the genesis, use
+// EpochSizeAt for the epoch of a block.
 func EpochSize() uint64 {
 	return xcom.EpochSize()
 }
@@ -76,14 +78,41 @@ func EpochSize() uint64 {
 func EpochsPerYear() uint64 {
 	epochBlocks := CalcBlocksEachEpoch()
 	i := xcom.Interval()
-	return xcom.AdditionalCycleTime() * 60 / (i * epochBlocks)
+	return xcom.AdditionalCycleTime(0) * 60 / (i * epochBlocks)
 }
 
-// CalcBlocksEachEpoch return how many blocks per epoch
+// CalcBlocksEachEpoch returns the blocks of an epoch of the genesis, use
+// CalcBlocksEachEpochAt for the epoch of a block.
 func CalcBlocksEachEpoch() uint64 {
 	return ConsensusSize() * EpochSize()
 }
 
+// ConsensusSizeAt returns the blocks of the consensus round of the block.
+func ConsensusSizeAt(blockNumber uint64) uint64 {
+	return xcom.BlockScheduleAt(blockNumber).ConsensusSize()
+}
+
+// EpochSizeAt returns the consensus rounds of the epoch of the block.
+func EpochSizeAt(blockNumber uint64) uint64 {
+	return xcom.BlockScheduleAt(blockNumber).EpochSize()
+}
+
+// CalcBlocksEachEpochAt returns the blocks of the epoch of the block.
+func CalcBlocksEachEpochAt(blockNumber uint64) uint64 {
+	return xcom.BlockScheduleAt(blockNumber).BlocksEachEpoch()
+}
+
+// IntervalAt returns the seconds between the blocks at the block.
+func IntervalAt(blockNumber uint64) uint64 {
+	return xcom.BlockScheduleAt(blockNumber).Interval()
+}
+
+// ElectionDistanceAt returns the distance between the election block and
+// the end of the consensus round of the block.
+func ElectionDistanceAt(blockNumber uint64) uint64 {
+	return xcom.BlockScheduleAt(blockNumber).ElectionDistance()
+}
+
 func EstimateConsensusRoundsForGov(seconds uint64) uint64 {
 	//v0.7.5, hard code 1 second for block interval for estimating.
 	blockInterval := uint64(1)
@@ -91,10 +120,11 @@ func EstimateConsensusRoundsForGov(seconds uint64) uint64 {
 }
 
 func EstimateEndVotingBlockForParaProposal(blockNumber uint64, seconds uint64) uint64 {
-	consensusSize := ConsensusSize()
-	epochMaxDuration := xcom.MaxEpochMinutes() //minutes
+	s := xcom.BlockScheduleAt(blockNumber)
+	consensusSize := s.ConsensusSize()
+	epochMaxDuration := xcom.MaxEpochMinutes(blockNumber) //minutes
 	//estimate how many consensus rounds in a epoch.
-	consensusRoundsEachEpoch := epochMaxDuration * 60 / (xcom.Interval() * consensusSize)
+	consensusRoundsEachEpoch := epochMaxDuration * 60 / (s.Interval() * consensusSize)
 	blocksEachEpoch := consensusRoundsEachEpoch * consensusSize
 
 	//v0.7.5, hard code 1 second for block interval for estimating.
@@ -102,7 +132,9 @@ func EstimateEndVotingBlockForParaProposal(blockNumber uint64, seconds uint64) u
 	durationEachEpoch := blocksEachEpoch * blockInterval
 
 	epochRounds := seconds / durationEachEpoch
-	return blockNumber + blocksEachEpoch - blockNumber%blocksEachEpoch + epochRounds*blocksEachEpoch
+	// The proposal is tallied at the end of an epoch, the epochs of a
+	// schedule governed meanwhile have their own sizes.
+	return CalcEpochEndBlock(CalculateEpoch(blockNumber+1) + epochRounds)
 }
 
 // calculate returns how many blocks per year.
@@ -110,45 +142,61 @@ func CalcBlocksEachYear() uint64 {
 	return EpochsPerYear() * CalcBlocksEachEpoch()
 }
 
-// calculate the Epoch number by blockNumber
-func CalculateEpoch(blockNumber uint64) uint64 {
-	size := CalcBlocksEachEpoch()
-
-	var epoch uint64
-	div := blockNumber / size
-	mod := blockNumber % size
-
-	switch {
-	// first epoch
-	case div == 0:
-		epoch = 1
-	case div > 0 && mod == 0:
-		epoch = div
-	case div > 0 && mod > 0:
-		epoch = div + 1
+// CalcBlocksEachYearAt returns the blocks of the additional cycle with the
+// epochs of the block.
+func CalcBlocksEachYearAt(blockNumber uint64) uint64 {
+	s := xcom.BlockScheduleAt(blockNumber)
+	epochBlocks := s.BlocksEachEpoch()
+	return xcom.AdditionalCycleTime(blockNumber) * 60 / (s.Interval() * epochBlocks) * epochBlocks
+}
+
+// calculate returns the epoch or round of the offset within a schedule, the
+// offset 0 is in the first one.
+func calculate(offset, size, first uint64) uint64 {
+	if offset == 0 {
+		return first
 	}
+	return first - 1 + (offset+size-1)/size
+}
 
-	return epoch
+// calculate the Epoch number by blockNumber
+func CalculateEpoch(blockNumber uint64) uint64 {
+	s := xcom.BlockScheduleAt(blockNumber)
+	return calculate(blockNumber-(s.BlockNumber-1), s.BlocksEachEpoch(), s.Epoch)
 }
 
 // calculate the Consensus number by blockNumber
 func CalculateRound(blockNumber uint64) uint64 {
-	size := ConsensusSize()
-
-	var round uint64
-	div := blockNumber / size
-	mod := blockNumber % size
-	switch {
-	// first consensus round
-	case div == 0:
-		round = 1
-	case div > 0 && mod == 0:
-		round = div
-	case div > 0 && mod > 0:
-		round = div + 1
+	s := xcom.BlockScheduleAt(blockNumber)
+	return calculate(blockNumber-(s.BlockNumber-1), s.ConsensusSize(), s.Round)
+}
+
+// CalcEpochEndBlock returns the last block of the epoch.
+func CalcEpochEndBlock(epoch uint64) uint64 {
+	if epoch == 0 {
+		return 0
 	}
+	s := xcom.BlockScheduleOfEpoch(epoch)
+	return s.BlockNumber - 1 + (epoch-s.Epoch+1)*s.BlocksEachEpoch()
+}
 
-	return round
+// CalcRoundEndBlock returns the last block of the consensus round.
+func CalcRoundEndBlock(round uint64) uint64 {
+	if round == 0 {
+		return 0
+	}
+	s := xcom.BlockScheduleOfRound(round)
+	return s.BlockNumber - 1 + (round-s.Round+1)*s.ConsensusSize()
+}
+
+// CalcRoundsBeforeEpoch returns the consensus rounds of the epochs before
+// the epoch.
+func CalcRoundsBeforeEpoch(epoch uint64) uint64 {
+	if epoch == 0 {
+		return 0
+	}
+	s := xcom.BlockScheduleOfEpoch(epoch)
+	return s.Round - 1 + (epoch-s.Epoch)*s.EpochSize()
 }
 
 func InNodeIDList(nodeID discover.NodeID, nodeIDList []discover.NodeID) bool {
@@ -171,39 +219,45 @@ func InHashList(hash common.Hash, hashList []common.Hash) bool {
 
 // end-voting-block = the end block of a consensus period - electionDistance, end-voting-block must be a Consensus Election block
 func CalEndVotingBlock(blockNumber uint64, endVotingRounds uint64) uint64 {
-	electionDistance := xcom.ElectionDistance()
-	consensusSize := ConsensusSize()
-	return blockNumber + consensusSize - blockNumber%consensusSize + endVotingRounds*consensusSize - electionDistance
+	roundEnd := CalcRoundEndBlock(CalculateRound(blockNumber+1) + endVotingRounds)
+	return roundEnd - ElectionDistanceAt(roundEnd)
 }
 
 // active-block = the begin of a consensus period, so, it is possible that active-block also is the begin of a epoch.
 func CalActiveBlock(endVotingBlock uint64) uint64 {
 	//return endVotingBlock + xcom.ElectionDistance() + (xcom.VersionProposalActive_ConsensusRounds()-1)*ConsensusSize() + 1
-	return endVotingBlock + xcom.ElectionDistance() + 1
+	return endVotingBlock + ElectionDistanceAt(endVotingBlock) + 1
+}
+
+// scheduleOffset returns the offset of the block within its schedule.
+func scheduleOffset(blockNumber uint64) (uint64, *xcom.BlockSchedule) {
+	s := xcom.BlockScheduleAt(blockNumber)
+	return blockNumber - (s.BlockNumber - 1), s
 }
 
 // IsBeginOfEpoch returns true if current block is the first block of a Epoch
 func IsBeginOfEpoch(blockNumber uint64) bool {
-	size := CalcBlocksEachEpoch()
-	mod := blockNumber % size
+	offset, s := scheduleOffset(blockNumber)
+	mod := offset % s.BlocksEachEpoch()
 	return mod == 1
 }
 
 // IsBeginOfConsensus returns true if current block is the first block of a Consensus Cycle
 func IsBeginOfConsensus(blockNumber uint64) bool {
-	size := ConsensusSize()
-	mod := blockNumber % size
+	offset, s := scheduleOffset(blockNumber)
+	mod := offset % s.ConsensusSize()
 	return mod == 1
 }
 
 func IsEndOfEpoch(blockNumber uint64) bool {
-	size := CalcBlocksEachEpoch()
-	mod := blockNumber % size
+	offset, s := scheduleOffset(blockNumber)
+	mod := offset % s.BlocksEachEpoch()
 	return mod == 0
 }
 
 func IsElection(blockNumber uint64) bool {
-	tmp := blockNumber + xcom.ElectionDistance()
-	mod := tmp % ConsensusSize()
+	offset, s := scheduleOffset(blockNumber)
+	tmp := offset + s.ElectionDistance()
+	mod := tmp % s.ConsensusSize()
 	return mod == 0
 }
@@ -0,0 +1,99 @@
+// Copyright 2021 The PlatON Network Authors
+// This file is part of the PlatON-Go library.
+//
+// The PlatON-Go library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The PlatON-Go library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the PlatON-Go library. If not, see <http://www.gnu.org/licenses/>.
+
+package xutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hashkey-chain/hashkey-chain/x/xcom"
+)
+
+func TestCalculateGenesisSchedule(t *testing.T) {
+	xcom.GetEc(xcom.DefaultUnitTestNet)
+	xcom.ResetBlockSchedules()
+
+	consensusSize, epochBlocks := ConsensusSize(), CalcBlocksEachEpoch()
+	ceil := func(n, size uint64) uint64 {
+		if n <= size {
+			return 1
+		}
+		return (n + size - 1) / size
+	}
+	for n := uint64(0); n < 3*epochBlocks; n++ {
+		assert.Equal(t, ceil(n, epochBlocks), CalculateEpoch(n), "blockNumber %d", n)
+		assert.Equal(t, ceil(n, consensusSize), CalculateRound(n), "blockNumber %d", n)
+		assert.Equal(t, n%epochBlocks == 1, IsBeginOfEpoch(n), "blockNumber %d", n)
+		assert.Equal(t, n%epochBlocks == 0, IsEndOfEpoch(n), "blockNumber %d", n)
+		assert.Equal(t, n%consensusSize == 1, IsBeginOfConsensus(n), "blockNumber %d", n)
+		assert.Equal(t, (n+xcom.ElectionDistance())%consensusSize == 0, IsElection(n), "blockNumber %d", n)
+		assert.Equal(t, n+consensusSize-n%consensusSize+2*consensusSize-xcom.ElectionDistance(), CalEndVotingBlock(n, 2), "blockNumber %d", n)
+	}
+	assert.Equal(t, 2*epochBlocks, CalcEpochEndBlock(2))
+	assert.Equal(t, 2*consensusSize, CalcRoundEndBlock(2))
+	assert.Equal(t, 2*EpochSize(), CalcRoundsBeforeEpoch(3))
+}
+
+func TestCalculateGovernedSchedule(t *testing.T) {
+	xcom.GetEc(xcom.DefaultUnitTestNet)
+	xcom.ResetBlockSchedules()
+	defer xcom.ResetBlockSchedules()
+
+	// 40 blocks each round and 9 rounds each epoch in the genesis, the epoch
+	// 3 has 4 rounds of 40 blocks once the interval is 2 seconds.
+	assert.NoError(t, xcom.CheckBlockSchedule(721, 20, 10))
+	_, _, err := xcom.BlockSchedules(nil).Register(722, 20, 10)
+	assert.Error(t, err)
+	schedules, s, err := xcom.BlockSchedules(nil).Register(721, 20, 10)
+	assert.NoError(t, err)
+	xcom.SetBlockSchedules(schedules)
+	assert.Equal(t, uint64(3), s.Epoch)
+	assert.Equal(t, uint64(19), s.Round)
+
+	assert.Equal(t, uint64(2), CalculateEpoch(720))
+	assert.Equal(t, uint64(3), CalculateEpoch(721))
+	assert.Equal(t, uint64(3), CalculateEpoch(880))
+	assert.Equal(t, uint64(4), CalculateEpoch(881))
+	assert.Equal(t, uint64(18), CalculateRound(720))
+	assert.Equal(t, uint64(19), CalculateRound(760))
+	assert.Equal(t, uint64(20), CalculateRound(761))
+
+	assert.True(t, IsEndOfEpoch(720))
+	assert.True(t, IsBeginOfEpoch(721))
+	assert.True(t, IsEndOfEpoch(880))
+	assert.False(t, IsEndOfEpoch(1080))
+	assert.True(t, IsBeginOfEpoch(881))
+	assert.True(t, IsElection(700))
+	assert.True(t, IsElection(740))
+
+	assert.Equal(t, uint64(360), CalcBlocksEachEpochAt(720))
+	assert.Equal(t, uint64(160), CalcBlocksEachEpochAt(721))
+	assert.Equal(t, uint64(2), IntervalAt(721))
+	assert.Equal(t, uint64(720), CalcEpochEndBlock(2))
+	assert.Equal(t, uint64(1040), CalcEpochEndBlock(4))
+	assert.Equal(t, uint64(720), CalcRoundEndBlock(18))
+	assert.Equal(t, uint64(760), CalcRoundEndBlock(19))
+	assert.Equal(t, uint64(18), CalcRoundsBeforeEpoch(3))
+	assert.Equal(t, uint64(22), CalcRoundsBeforeEpoch(4))
+
+	// The voting ends at the election block of a round across the schedules
+	assert.Equal(t, uint64(780), CalEndVotingBlock(700, 2))
+	assert.True(t, IsElection(780))
+	assert.Equal(t, uint64(720), EstimateEndVotingBlockForParaProposal(700, 0))
+	assert.Equal(t, uint64(880), EstimateEndVotingBlockForParaProposal(720, 0))
+}